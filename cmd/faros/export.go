@@ -0,0 +1,176 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pusher/faros/pkg/export"
+	flag "github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// exportCommand writes the resources in the namespace to files in a layout a
+// GitTrack can sync and prints a GitTrack for them. It returns the exit code
+// for the process.
+func exportCommand(args []string, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file to use")
+	context := flags.String("context", "", "Name of the kubeconfig context to use")
+	namespace := flags.StringP("namespace", "n", "", "Namespace to export, defaults to the namespace of the context")
+	allNamespaces := flags.BoolP("all-namespaces", "A", false, "Export the resources in every namespace")
+	selector := flags.StringP("selector", "l", "", "Only export resources matching this label selector eg app=example")
+	outputDir := flags.StringP("output-dir", "o", "", "Directory to write the exported resources to")
+	name := flags.String("name", "export", "Name of the GitTrack to print")
+	repository := flags.String("repo", "", "URL of the git repository the exported resources will be committed to")
+	reference := flags.String("ref", "master", "Git reference the GitTrack tracks")
+	subPath := flags.String("subpath", "", "Path within the repository the exported resources will be committed to")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *outputDir == "" || *repository == "" {
+		fmt.Fprintln(errOut, "--output-dir and --repo are required")
+		return 2
+	}
+
+	clientConfig := loadClientConfig(*kubeconfig, *context, *namespace)
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		fmt.Fprintf(errOut, "unable to load kubeconfig: %v\n", err)
+		return 1
+	}
+	ns, _, err := clientConfig.Namespace()
+	if err != nil {
+		fmt.Fprintf(errOut, "unable to determine namespace: %v\n", err)
+		return 1
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		fmt.Fprintf(errOut, "unable to create client: %v\n", err)
+		return 1
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		fmt.Fprintf(errOut, "unable to create client: %v\n", err)
+		return 1
+	}
+
+	gvrs, err := exportResources(discoveryClient)
+	if err != nil {
+		// Resources of the groups that could be discovered are still exported
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			fmt.Fprintln(errOut, err)
+			return 1
+		}
+		fmt.Fprintf(errOut, "warning: %v\n", err)
+	}
+
+	listNamespace := ns
+	if *allNamespaces {
+		listNamespace = metav1.NamespaceAll
+	}
+	exported, skipped := 0, 0
+	for _, gvr := range gvrs {
+		list, err := dynamicClient.Resource(gvr).Namespace(listNamespace).List(metav1.ListOptions{LabelSelector: *selector})
+		if err != nil {
+			fmt.Fprintf(errOut, "unable to list %s: %v\n", gvr.GroupResource(), err)
+			return 1
+		}
+		for i := range list.Items {
+			u := &list.Items[i]
+			if _, skip := export.Skip(u); skip {
+				skipped++
+				continue
+			}
+			export.Strip(u)
+			export.Adopt(u)
+			data, err := yaml.Marshal(u.Object)
+			if err != nil {
+				fmt.Fprintf(errOut, "unable to marshal %s %s/%s: %v\n", u.GetKind(), u.GetNamespace(), u.GetName(), err)
+				return 1
+			}
+			path := filepath.Join(*outputDir, export.FilePath(u))
+			if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				fmt.Fprintf(errOut, "unable to create directory: %v\n", err)
+				return 1
+			}
+			if err = ioutil.WriteFile(path, data, 0644); err != nil {
+				fmt.Fprintf(errOut, "unable to write %s: %v\n", path, err)
+				return 1
+			}
+			exported++
+		}
+	}
+
+	data, err := yaml.Marshal(export.GitTrack(*name, ns, *repository, *reference, *subPath))
+	if err != nil {
+		fmt.Fprintf(errOut, "unable to marshal GitTrack: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(out, "%s", data)
+	fmt.Fprintf(errOut, "Exported %d resources to %s, skipped %d created by controllers or Kubernetes\n", exported, *outputDir, skipped)
+	return 0
+}
+
+// exportResources returns the preferred version of each namespaced resource
+// that can be listed and is not skipped by export.SkipResource
+func exportResources(discoveryClient discovery.DiscoveryInterface) ([]schema.GroupVersionResource, error) {
+	lists, err := discoveryClient.ServerPreferredNamespacedResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, fmt.Errorf("unable to discover resources: %v", err)
+	}
+
+	gvrs := []schema.GroupVersionResource{}
+	for _, list := range lists {
+		gv, parseErr := schema.ParseGroupVersion(list.GroupVersion)
+		if parseErr != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			// Subresources, such as deployments/status, are part of their resource
+			if strings.Contains(resource.Name, "/") || !canList(resource) {
+				continue
+			}
+			gvr := gv.WithResource(resource.Name)
+			if export.SkipResource(gvr.GroupResource()) {
+				continue
+			}
+			gvrs = append(gvrs, gvr)
+		}
+	}
+	return gvrs, err
+}
+
+// canList returns whether the resource supports the list verb
+func canList(resource metav1.APIResource) bool {
+	for _, verb := range resource.Verbs {
+		if verb == "list" {
+			return true
+		}
+	}
+	return false
+}
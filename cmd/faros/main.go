@@ -0,0 +1,160 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/pusher/faros/pkg/render"
+	"github.com/pusher/faros/pkg/version"
+	gitstore "github.com/pusher/git-store"
+	flag "github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const usage = `faros is a tool for debugging the repositories tracked by Faros
+
+Usage:
+  faros render --repo <url> [--ref <reference>] [--subpath <path>]
+  faros status [<gittrack>] [--namespace <namespace>] [--all-namespaces]
+  faros export --output-dir <dir> --repo <url> [--namespace <namespace>] [--selector <selector>]
+  faros version
+
+Install as kubectl-faros to use it as a kubectl plugin, eg kubectl faros status
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "render":
+		os.Exit(renderCommand(os.Args[2:], os.Stdout, os.Stderr))
+	case "status":
+		os.Exit(statusCommand(os.Args[2:], os.Stdout, os.Stderr))
+	case "export":
+		os.Exit(exportCommand(os.Args[2:], os.Stdout, os.Stderr))
+	case "version":
+		fmt.Printf("faros %s\n", version.Get())
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// renderCommand fetches and parses the repository in the same way as the
+// GitTrack controller and prints the GitTrackObjects it would create, or
+// their children, as a multi-document YAML stream. It returns the exit code
+// for the process.
+func renderCommand(args []string, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	repository := flags.String("repo", "", "URL of the git repository to render")
+	reference := flags.String("ref", "master", "Git reference to check out")
+	subPath := flags.String("subpath", "", "Path within the repository to read manifests from")
+	privateKeyFile := flags.String("private-key-file", "", "Path to an SSH private key used to fetch the repository")
+	children := flags.Bool("children", false, "Print the child resources instead of the GitTrackObjects")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *repository == "" {
+		fmt.Fprintln(errOut, "--repo is required")
+		return 2
+	}
+
+	repoRef := &gitstore.RepoRef{URL: *repository}
+	if *privateKeyFile != "" {
+		key, err := ioutil.ReadFile(*privateKeyFile)
+		if err != nil {
+			fmt.Fprintf(errOut, "unable to read private key: %v\n", err)
+			return 1
+		}
+		repoRef.PrivateKey = key
+	}
+
+	files, err := fetchFiles(repoRef, *reference, *subPath)
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+
+	objects, objectFiles, fileErrors := render.Objects(files)
+	sort.Slice(objects, func(i, j int) bool {
+		return render.ObjectName(objects[i]) < render.ObjectName(objects[j])
+	})
+
+	for _, u := range objects {
+		var doc interface{} = u.Object
+		if !*children {
+			// Without a cluster to ask, objects with a namespace are assumed
+			// to be namespaced resources
+			gto, err := render.GitTrackObject(u, u.GetNamespace() != "")
+			if err != nil {
+				fmt.Fprintf(errOut, "%s: %v\n", objectFiles[u], err)
+				return 1
+			}
+			doc = gto
+		}
+		data, err := yaml.Marshal(doc)
+		if err != nil {
+			fmt.Fprintf(errOut, "%s: unable to marshal YAML: %v\n", objectFiles[u], err)
+			return 1
+		}
+		fmt.Fprintf(out, "---\n# Source: %s\n%s", objectFiles[u], data)
+	}
+
+	if len(fileErrors) == 0 {
+		return 0
+	}
+	paths := []string{}
+	for path := range fileErrors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(errOut, "%s: %s", path, fileErrors[path])
+	}
+	return 1
+}
+
+// fetchFiles checks out the repository at the reference and returns the
+// manifests within the subPath
+func fetchFiles(repoRef *gitstore.RepoRef, reference, subPath string) (map[string]*gitstore.File, error) {
+	repo, err := gitstore.NewRepoStore().Get(repoRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository '%s': %v", repoRef.URL, err)
+	}
+	if err = repo.Checkout(reference); err != nil {
+		return nil, fmt.Errorf("failed to checkout '%s': %v", reference, err)
+	}
+
+	files, err := repo.GetAllFiles(render.ManifestGlob(subPath), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all files for subpath '%s': %v", subPath, err)
+	} else if len(files) == 0 {
+		return nil, fmt.Errorf("no files for subpath '%s'", subPath)
+	}
+	return files, nil
+}
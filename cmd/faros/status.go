@@ -0,0 +1,379 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/client/clientset"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	flag "github.com/spf13/pflag"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// statusClients are the clients used to look up the state of GitTracks
+type statusClients struct {
+	faros clientset.Interface
+	kube  kubernetes.Interface
+}
+
+// statusCommand prints a summary of the state of a GitTrack, or of every
+// GitTrack in the namespace if no name is given. It returns the exit code for
+// the process.
+func statusCommand(args []string, out, errOut io.Writer) int {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	flags.SetOutput(errOut)
+	kubeconfig := flags.String("kubeconfig", "", "Path to the kubeconfig file to use")
+	context := flags.String("context", "", "Name of the kubeconfig context to use")
+	namespace := flags.StringP("namespace", "n", "", "Namespace of the GitTrack, defaults to the namespace of the context")
+	allNamespaces := flags.BoolP("all-namespaces", "A", false, "List the GitTracks in every namespace")
+	events := flags.Int("events", 10, "Number of recent events to show for a GitTrack")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		fmt.Fprintln(errOut, "at most one GitTrack may be given")
+		return 2
+	}
+
+	clientConfig := loadClientConfig(*kubeconfig, *context, *namespace)
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		fmt.Fprintf(errOut, "unable to load kubeconfig: %v\n", err)
+		return 1
+	}
+	ns, _, err := clientConfig.Namespace()
+	if err != nil {
+		fmt.Fprintf(errOut, "unable to determine namespace: %v\n", err)
+		return 1
+	}
+
+	clients := statusClients{}
+	if clients.faros, err = clientset.NewForConfig(config); err != nil {
+		fmt.Fprintf(errOut, "unable to create client: %v\n", err)
+		return 1
+	}
+	if clients.kube, err = kubernetes.NewForConfig(config); err != nil {
+		fmt.Fprintf(errOut, "unable to create client: %v\n", err)
+		return 1
+	}
+
+	if flags.NArg() == 0 {
+		if *allNamespaces {
+			ns = metav1.NamespaceAll
+		}
+		err = listGitTracks(clients, ns, out)
+	} else {
+		err = describeGitTrack(clients, ns, flags.Arg(0), *events, out)
+	}
+	if err != nil {
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	return 0
+}
+
+// loadClientConfig loads the kubeconfig in the same way as kubectl, with the
+// path, context and namespace given on the command line taking precedence
+func loadClientConfig(kubeconfig, context, namespace string) clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{
+		CurrentContext: context,
+		Context:        clientcmdapi.Context{Namespace: namespace},
+	})
+}
+
+// listGitTracks prints a line summarising each GitTrack in the namespace
+func listGitTracks(clients statusClients, namespace string, out io.Writer) error {
+	list, err := clients.faros.FarosV1alpha1().GitTracks(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to list GitTracks: %v", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool {
+		if list.Items[i].Namespace != list.Items[j].Namespace {
+			return list.Items[i].Namespace < list.Items[j].Namespace
+		}
+		return list.Items[i].Name < list.Items[j].Name
+	})
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tFETCHED\tSYNCED\tCHILDREN\tIN SYNC\tHEALTHY")
+	for _, gt := range list.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%s\n",
+			gt.Namespace,
+			gt.Name,
+			conditionStatus(&gt, farosv1alpha1.FilesFetchedType),
+			orNone(gt.Status.LastSyncedReference),
+			gt.Status.ObjectsApplied,
+			gt.Status.ObjectsInSync,
+			conditionStatus(&gt, farosv1alpha1.ChildrenHealthyType),
+		)
+	}
+	return w.Flush()
+}
+
+// describeGitTrack prints the state of the GitTrack, its children and its
+// recent events
+func describeGitTrack(clients statusClients, namespace, name string, events int, out io.Writer) error {
+	gt, err := clients.faros.FarosV1alpha1().GitTracks(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get GitTrack: %v", err)
+	}
+	unhealthy, err := unhealthyChildren(clients, gt)
+	if err != nil {
+		return err
+	}
+	recent, err := recentEvents(clients, gt, events)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", gt.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", gt.Namespace)
+	fmt.Fprintf(w, "Repository:\t%s\n", gt.Spec.Repository)
+	fmt.Fprintf(w, "Reference:\t%s\n", gt.Spec.Reference)
+	fmt.Fprintf(w, "Synced:\t%s\n", orNone(gt.Status.LastSyncedReference))
+	if gt.Status.Rollback != nil {
+		fmt.Fprintf(w, "Rolled back to:\t%s (%s)\n", gt.Status.Rollback.Revision, gt.Status.Rollback.Commit)
+	}
+	if gt.Spec.Suspend {
+		fmt.Fprintln(w, "Suspended:\ttrue")
+	}
+	fmt.Fprintf(w, "Fetch:\t%s\n", conditionSummary(gt, farosv1alpha1.FilesFetchedType))
+	fmt.Fprintf(w, "Parse:\t%s\n", conditionSummary(gt, farosv1alpha1.FilesParsedType))
+	fmt.Fprintf(w, "Apply:\t%s\n", conditionSummary(gt, farosv1alpha1.ChildrenUpToDateType))
+	fmt.Fprintf(w, "Health:\t%s\n", conditionSummary(gt, farosv1alpha1.ChildrenHealthyType))
+
+	fmt.Fprintln(w, "\nChildren:")
+	fmt.Fprintln(w, "  DISCOVERED\tAPPLIED\tIN SYNC\tIGNORED\tPRUNED\tRETAINED")
+	fmt.Fprintf(w, "  %d\t%d\t%d\t%d\t%d\t%d\n",
+		gt.Status.ObjectsDiscovered,
+		gt.Status.ObjectsApplied,
+		gt.Status.ObjectsInSync,
+		gt.Status.ObjectsIgnored,
+		gt.Status.ObjectsPruned,
+		gt.Status.ObjectsRetained,
+	)
+
+	fmt.Fprintln(w, "\nConditions:")
+	fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tMESSAGE")
+	for _, cond := range gt.Status.Conditions {
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", cond.Type, cond.Status, cond.Reason, firstLine(cond.Message))
+	}
+
+	if len(gt.Status.ChildrenOutOfSync) > 0 {
+		fmt.Fprintln(w, "\nOut of sync:")
+		fmt.Fprintln(w, "  NAME\tKIND\tFILE\tREASON")
+		for _, child := range gt.Status.ChildrenOutOfSync {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", child.Name, child.Kind, orNone(child.File), firstLine(child.Reason))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		fmt.Fprintln(w, "\nUnhealthy:")
+		fmt.Fprintln(w, "  NAME\tKIND\tREASON")
+		for _, child := range unhealthy {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", child.Name, child.Kind, firstLine(child.Reason))
+		}
+	}
+
+	if len(gt.Status.IgnoredFiles) > 0 {
+		fmt.Fprintln(w, "\nIgnored files:")
+		files := []string{}
+		for file := range gt.Status.IgnoredFiles {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			fmt.Fprintf(w, "  %s\t%s\n", file, firstLine(gt.Status.IgnoredFiles[file]))
+		}
+	}
+
+	if len(gt.Status.FileErrors) > 0 {
+		fmt.Fprintln(w, "\nFile errors:")
+		fmt.Fprintln(w, "  PATH\tCLASS\tMESSAGE")
+		for _, fileErr := range gt.Status.FileErrors {
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", fileErr.Path, fileErr.Class, firstLine(fileErr.Message))
+		}
+	}
+
+	fmt.Fprintln(w, "\nEvents:")
+	if len(recent) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintln(w, "  AGE\tTYPE\tREASON\tMESSAGE")
+		for _, event := range recent {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", eventAge(event), event.Type, event.Reason, firstLine(event.Message))
+		}
+	}
+	return w.Flush()
+}
+
+// unhealthyChildren returns the children of the GitTrack whose health check
+// is failing
+func unhealthyChildren(clients statusClients, gt *farosv1alpha1.GitTrack) ([]farosv1alpha1.GitTrackChildStatus, error) {
+	opts := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(labels.Set{gittrackutils.GitTrackLabel: gt.Name}).String()}
+	gtos, err := clients.faros.FarosV1alpha1().GitTrackObjects(gt.Namespace).List(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list GitTrackObjects: %v", err)
+	}
+	cgtos, err := clients.faros.FarosV1alpha1().ClusterGitTrackObjects().List(opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list ClusterGitTrackObjects: %v", err)
+	}
+
+	children := []farosv1alpha1.GitTrackObjectInterface{}
+	for i := range gtos.Items {
+		children = append(children, &gtos.Items[i])
+	}
+	for i := range cgtos.Items {
+		// The label only holds the name of the GitTrack
+		if metav1.IsControlledBy(&cgtos.Items[i], gt) {
+			children = append(children, &cgtos.Items[i])
+		}
+	}
+
+	unhealthy := []farosv1alpha1.GitTrackChildStatus{}
+	for _, child := range children {
+		for _, cond := range child.GetStatus().Conditions {
+			if cond.Type == farosv1alpha1.ChildHealthyType && cond.Status == apiv1.ConditionFalse {
+				unhealthy = append(unhealthy, farosv1alpha1.GitTrackChildStatus{
+					Name:   child.GetNamespacedName(),
+					Kind:   child.GetSpec().Kind,
+					Reason: fmt.Sprintf("%s: %s", cond.Reason, cond.Message),
+				})
+			}
+		}
+	}
+	sort.Slice(unhealthy, func(i, j int) bool {
+		return unhealthy[i].Name < unhealthy[j].Name
+	})
+	return unhealthy, nil
+}
+
+// recentEvents returns the newest events for the GitTrack, oldest first
+func recentEvents(clients statusClients, gt *farosv1alpha1.GitTrack, limit int) ([]apiv1.Event, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	selector := fields.SelectorFromSet(fields.Set{
+		"involvedObject.kind": "GitTrack",
+		"involvedObject.name": gt.Name,
+		"involvedObject.uid":  string(gt.UID),
+	})
+	list, err := clients.kube.CoreV1().Events(gt.Namespace).List(metav1.ListOptions{FieldSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list events: %v", err)
+	}
+
+	events := list.Items
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	if len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events, nil
+}
+
+// conditionStatus returns the status of the condition of the GitTrack
+func conditionStatus(gt *farosv1alpha1.GitTrack, condType farosv1alpha1.GitTrackConditionType) string {
+	cond := gittrackutils.GetGitTrackCondition(gt.Status, condType)
+	if cond == nil {
+		return string(apiv1.ConditionUnknown)
+	}
+	return string(cond.Status)
+}
+
+// conditionSummary describes the condition of the GitTrack on a single line
+func conditionSummary(gt *farosv1alpha1.GitTrack, condType farosv1alpha1.GitTrackConditionType) string {
+	cond := gittrackutils.GetGitTrackCondition(gt.Status, condType)
+	if cond == nil {
+		return string(apiv1.ConditionUnknown)
+	}
+	summary := fmt.Sprintf("%s (%s", cond.Status, cond.Reason)
+	if !cond.LastUpdateTime.IsZero() {
+		summary += fmt.Sprintf(", %s ago", humanDuration(time.Since(cond.LastUpdateTime.Time)))
+	}
+	summary += ")"
+	if cond.Message != "" {
+		summary += ": " + firstLine(cond.Message)
+	}
+	return summary
+}
+
+// eventTime returns the time the event was last seen
+func eventTime(event apiv1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
+
+// eventAge describes how long ago the event was last seen
+func eventAge(event apiv1.Event) string {
+	t := eventTime(event)
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return humanDuration(time.Since(t))
+}
+
+// humanDuration formats the duration in the same way as kubectl, eg 45s, 12m,
+// 5h or 3d
+func humanDuration(d time.Duration) string {
+	switch {
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// firstLine returns the first line of a possibly multi-line message
+func firstLine(message string) string {
+	if i := strings.Index(message, "\n"); i >= 0 {
+		return strings.TrimSuffix(message[:i], ",") + " ..."
+	}
+	return message
+}
+
+// orNone returns the value or a placeholder if it is empty
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
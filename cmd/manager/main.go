@@ -18,20 +18,28 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
-	"runtime"
+	"strings"
 	"time"
 
 	goflag "flag"
 
 	"github.com/pusher/faros/pkg/apis"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	"github.com/pusher/faros/pkg/controller"
 	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/health"
+	"github.com/pusher/faros/pkg/shutdown"
 	"github.com/pusher/faros/pkg/utils"
+	"github.com/pusher/faros/pkg/utils/logging"
+	"github.com/pusher/faros/pkg/version"
+	"github.com/pusher/faros/pkg/webhook"
 	flag "github.com/spf13/pflag"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/klog"
-	"k8s.io/klog/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	logr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
@@ -39,22 +47,32 @@ import (
 )
 
 var (
+	configFile               = flag.String("config", "", "Path to a YAML ControllerConfiguration file, flags given on the command line override its values")
+	configReloadInterval     = flag.Duration("config-reload-interval", 30*time.Second, "How often to check the config file for changes to the log levels, resource lists and sync period, 0 disables reloading")
 	leaderElection           = flag.Bool("leader-election", false, "Should the controller use leader election")
 	leaderElectionID         = flag.String("leader-election-id", "", "Name of the configmap used by the leader election system")
 	leaederElectionNamespace = flag.String("leader-election-namespace", "", "Namespace for the configmap used by the leader election system")
 	metricsBindAddress       = flag.String("metrics-bind-address", ":8080", "Specify which address to bind to for serving prometheus metrics")
-	syncPeriod               = flag.Duration("sync-period", 5*time.Minute, "Reconcile sync period")
+	healthProbeBindAddress   = flag.String("health-probe-bind-address", ":8081", "Specify which address to bind to for serving the /healthz and /readyz endpoints")
 	showVersion              = flag.Bool("version", false, "Show version and exit")
+	logLevel                 = flag.String("log-level", "info", "Log level, one of debug, info, warn, error or a verbosity such as 2")
+	logEncoding              = flag.String("log-encoding", logging.JSONEncoding, "Log encoding, one of json or console")
+	webhookPort              = flag.Int("webhook-port", 0, "Port to serve the defaulting webhooks on, 0 disables the webhooks")
+	webhookCertDir           = flag.String("webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "Directory containing the tls.crt and tls.key served by the webhooks")
+	shutdownTimeout          = flag.Duration("shutdown-timeout", 30*time.Second, "Maximum time to wait for in-flight reconciles to finish when shutting down")
+	runOnceMode              = flag.Bool("run-once", false, "Reconcile every GitTrack until it is synced or has failed, then exit non-zero if any sync failed")
+	runOnceTimeout           = flag.Duration("run-once-timeout", 10*time.Minute, "Maximum time to wait for the GitTracks to sync when running once")
+	controllerLogLevels      = flag.StringSlice("controller-log-level", []string{}, "Override the log level for a controller, specified in <controller>=<level> format eg gittrack=debug")
+	logLevelTokenFile        = flag.String("log-level-token-file", "", "Path to a file containing the bearer token required to change the log levels through the /log-level endpoint of the health probe server, the endpoint is disabled if not set")
 )
 
 func main() {
-	logr.SetLogger(klogr.New())
-	log := logr.Log.WithName("manager")
+	// klog flags remain to configure logging from client-go
 	logFlags := &goflag.FlagSet{}
 	klog.InitFlags(logFlags)
 	err := logFlags.Lookup("logtostderr").Value.Set("false")
 	if err != nil {
-		log.Error(err, "unable to set flag logtostderr")
+		fmt.Fprintf(os.Stderr, "unable to set flag logtostderr: %v\n", err)
 	}
 
 	// Setup flags
@@ -62,12 +80,92 @@ func main() {
 	flag.CommandLine.AddGoFlagSet(logFlags)
 	flag.Parse()
 
+	// Fill in the flags that were not given from the configuration file
+	var configLoader *farosflags.ConfigLoader
+	if *configFile != "" {
+		farosflags.RegisterReloadable("log-level", logLevel)
+		farosflags.RegisterReloadable("controller-log-level", controllerLogLevels)
+		configLoader = farosflags.NewConfigLoader(flag.CommandLine, *configFile)
+		if err := configLoader.Load(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Handle version flag
 	if *showVersion {
-		fmt.Printf("faros-gittrack-controller %s (built with %s)\n", VERSION, runtime.Version())
+		fmt.Printf("faros-gittrack-controller %s\n", version.Get())
 		return
 	}
 
+	if *shutdownTimeout < 0 {
+		fmt.Fprintf(os.Stderr, "invalid shutdown-timeout: must not be negative\n")
+		os.Exit(1)
+	}
+
+	if *configReloadInterval < 0 {
+		fmt.Fprintf(os.Stderr, "invalid config-reload-interval: must not be negative\n")
+		os.Exit(1)
+	}
+
+	syncPeriod := farosflags.SyncPeriod()
+	if syncPeriod < 0 {
+		fmt.Fprintf(os.Stderr, "invalid sync-period: must not be negative\n")
+		os.Exit(1)
+	}
+
+	if *runOnceMode && *leaderElection {
+		fmt.Fprintf(os.Stderr, "run-once cannot be used with leader-election\n")
+		os.Exit(1)
+	}
+
+	// Setup structured logging for the controllers
+	levels, err := logging.ParseControllerLevels(*controllerLogLevels)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid controller-log-level: %v\n", err)
+		os.Exit(1)
+	}
+	logger, logLevels, err := logging.NewReloadable(os.Stdout, logging.Options{
+		Level:            *logLevel,
+		Encoding:         *logEncoding,
+		ControllerLevels: levels,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to configure logging: %v\n", err)
+		os.Exit(1)
+	}
+	logr.SetLogger(logger)
+	log := logr.Log.WithName("manager")
+
+	// Read the token allowing the log levels to be changed at runtime
+	var logLevelToken string
+	if *logLevelTokenFile != "" {
+		data, err := ioutil.ReadFile(*logLevelTokenFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to read log-level-token-file: %v\n", err)
+			os.Exit(1)
+		}
+		logLevelToken = strings.TrimSpace(string(data))
+		if logLevelToken == "" {
+			fmt.Fprintf(os.Stderr, "invalid log-level-token-file: token must not be empty\n")
+			os.Exit(1)
+		}
+	}
+
+	// Apply log levels changed by reloading the configuration file
+	farosflags.OnReload(func() {
+		unlock := farosflags.RLockReloadable()
+		level, controllerLevels := *logLevel, *controllerLogLevels
+		unlock()
+		levels, err := logging.ParseControllerLevels(controllerLevels)
+		if err == nil {
+			err = logLevels.Set(level, levels)
+		}
+		if err != nil {
+			log.Error(err, "unable to apply reloaded log levels")
+		}
+	})
+
 	if logFlags.Lookup("logtostderr").Value.String() != "true" {
 		klog.CopyStandardLogTo("INFO")
 		klog.SetOutput(os.Stderr)
@@ -86,21 +184,34 @@ func main() {
 		panic(err)
 	}
 
-	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{
+	mgrOpts := manager.Options{
 		LeaderElection:          *leaderElection,
 		LeaderElectionID:        *leaderElectionID,
 		LeaderElectionNamespace: *leaederElectionNamespace,
 		MetricsBindAddress:      *metricsBindAddress,
-		SyncPeriod:              syncPeriod,
-		Namespace:               farosflags.Namespace,
+		SyncPeriod:              &syncPeriod,
 		MapperProvider:          utils.NewRestMapper,
-	})
+	}
+	// Restrict the cache to the managed namespaces, watching each namespace
+	// separately when several are given
+	switch len(farosflags.Namespaces) {
+	case 0:
+	case 1:
+		mgrOpts.Namespace = farosflags.Namespaces[0]
+	default:
+		mgrOpts.NewCache = cache.MultiNamespacedCacheBuilder(farosflags.Namespaces)
+	}
+
+	// Create a new Cmd to provide shared dependencies and start components
+	mgr, err := manager.New(cfg, mgrOpts)
 	if err != nil {
 		log.Error(err, "failed to initialise manager")
 		panic(err)
 	}
 
+	info := version.Get()
+	log.V(0).Info("Starting faros", "version", info.Version, "gitCommit", info.GitCommit, "buildDate", info.BuildDate, "goVersion", info.GoVersion)
+
 	log.V(0).Info("Registering Components.")
 
 	// Setup Scheme for all resources
@@ -115,12 +226,66 @@ func main() {
 		panic(err)
 	}
 
+	// Serve the defaulting webhooks unless disabled
+	if *webhookPort != 0 {
+		err = webhook.AddToManager(mgr, webhook.Options{
+			Port:    *webhookPort,
+			CertDir: *webhookCertDir,
+		})
+		if err != nil {
+			log.Error(err, "couldn't register webhooks")
+			panic(err)
+		}
+	}
+
+	// Report ready once the informers for the faros resources have synced
+	health.AddReadinessCheck("informers", health.InformersSynced(mgr.GetCache(),
+		&farosv1alpha1.GitTrack{},
+		&farosv1alpha1.GitTrackObject{},
+		&farosv1alpha1.ClusterGitTrackObject{},
+	))
+
+	// Serve the health probes unless disabled
+	if *healthProbeBindAddress != "0" {
+		go func() {
+			log.V(0).Info("Serving health probes", "address", *healthProbeBindAddress)
+			mux := http.NewServeMux()
+			mux.Handle("/version", version.Handler())
+			if logLevelToken != "" {
+				mux.Handle("/log-level", logging.Handler(logLevels, logLevelToken, logr.Log.WithName("log-level")))
+			}
+			mux.Handle("/", health.Handler())
+			if err := http.ListenAndServe(*healthProbeBindAddress, mux); err != nil {
+				log.Error(err, "health probe server error")
+				panic(err)
+			}
+		}()
+	}
+
+	stop := signals.SetupSignalHandler()
+
+	// Apply changes to the configuration file without restarting
+	if configLoader != nil && *configReloadInterval > 0 {
+		go watchConfig(configLoader, *configReloadInterval, mgr.GetEventRecorderFor("faros"), stop, log)
+	}
+
 	log.V(0).Info("Starting controllers...")
 
+	// Exit once every GitTrack has been synced when running once
+	if *runOnceMode {
+		os.Exit(runOnce(mgr, stop, *runOnceTimeout, *shutdownTimeout, log))
+	}
+
 	// Start the Cmd
-	err = mgr.Start(signals.SetupSignalHandler())
+	err = mgr.Start(stop)
 	if err != nil {
 		log.Error(err, "controller error")
 		panic(err)
 	}
+
+	// Let in-flight reconciles finish before exiting
+	log.V(0).Info("Draining in-flight reconciles", "timeout", shutdownTimeout.String())
+	if !shutdown.Drain(*shutdownTimeout) {
+		os.Exit(1)
+	}
 }
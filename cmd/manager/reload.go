@@ -0,0 +1,70 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// podReference returns a reference to the pod Faros is running in, as given
+// by the POD_NAME and POD_NAMESPACE environment variables, or nil if they
+// are not set
+func podReference() *corev1.ObjectReference {
+	name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if name == "" || namespace == "" {
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       name,
+		Namespace:  namespace,
+	}
+}
+
+// watchConfig reloads the configuration file every interval until the stop
+// channel is closed. The changes found are logged and, when the pod Faros is
+// running in is known, recorded in an event on the pod.
+func watchConfig(loader *farosflags.ConfigLoader, interval time.Duration, recorder record.EventRecorder, stop <-chan struct{}, log logr.Logger) {
+	pod := podReference()
+	loader.Watch(interval, stop, func(changes []farosflags.Change, err error) {
+		if err != nil {
+			log.Error(err, "unable to reload configuration")
+			if pod != nil {
+				recorder.Eventf(pod, corev1.EventTypeWarning, "ConfigReloadFailed", "Unable to reload configuration: %v", err)
+			}
+			return
+		}
+
+		descriptions := []string{}
+		for _, change := range changes {
+			descriptions = append(descriptions, change.String())
+		}
+		message := strings.Join(descriptions, ", ")
+		log.V(0).Info("Reloaded configuration", "changes", message)
+		if pod != nil {
+			recorder.Eventf(pod, corev1.EventTypeNormal, "ConfigReloaded", "Reloaded configuration: %s", message)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/runonce"
+	"github.com/pusher/faros/pkg/shutdown"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// runOnceInterval is how often the GitTracks are checked when running once
+const runOnceInterval = 2 * time.Second
+
+// runOnce starts the manager and waits for every GitTrack to finish syncing
+// before stopping it again. It returns the exit code for the process, which
+// is non-zero if any GitTrack failed to sync.
+func runOnce(mgr manager.Manager, stop <-chan struct{}, timeout, shutdownTimeout time.Duration, log logr.Logger) int {
+	mgrStop := make(chan struct{})
+	mgrDone := make(chan struct{})
+	go func() {
+		defer close(mgrDone)
+		if err := mgr.Start(mgrStop); err != nil {
+			log.Error(err, "controller error")
+		}
+	}()
+	defer func() {
+		close(mgrStop)
+		<-mgrDone
+		shutdown.Drain(shutdownTimeout)
+	}()
+
+	// Stop waiting if the manager exits early or a signal is received
+	waitStop := make(chan struct{})
+	go func() {
+		select {
+		case <-stop:
+		case <-mgrDone:
+		}
+		close(waitStop)
+	}()
+
+	if !mgr.GetCache().WaitForCacheSync(waitStop) {
+		log.Error(nil, "unable to sync informer caches")
+		return 1
+	}
+
+	results, err := runonce.Wait(mgr.GetClient(), farosflags.Namespaces, runOnceInterval, timeout, waitStop)
+	for _, res := range results {
+		log.V(0).Info("GitTrack sync finished", "gittrack", res.Name, "state", res.State, "message", res.Message)
+	}
+	if err != nil {
+		log.Error(err, "run once failed")
+		return 1
+	}
+	if failed := runonce.Failed(results); len(failed) > 0 {
+		log.Error(nil, "some GitTracks failed to sync", "failed", len(failed))
+		return 1
+	}
+	log.V(0).Info("All GitTracks synced")
+	return 0
+}
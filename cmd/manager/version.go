@@ -1,4 +0,0 @@
-package main
-
-// VERSION contains version information
-var VERSION = "undefined"
@@ -26,6 +26,8 @@ import (
 
 // ClusterGitTrackObject is the Schema for the clustergittrackobjects API
 // +k8s:openapi-gen=true
+// +kubebuilder:resource:path=clustergittrackobjects,shortName=cgto,categories=faros
+// +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="In Sync",type="string",JSONPath=".status.conditions[?(@.type=="ObjectInSync")].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type ClusterGitTrackObject struct {
@@ -0,0 +1,82 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 3.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AlertSeverity is the severity of a notification
+type AlertSeverity string
+
+const (
+	// AlertSeverityInfo is the severity of notifications that a GitTrack has
+	// recovered
+	AlertSeverityInfo AlertSeverity = "Info"
+	// AlertSeverityError is the severity of notifications that a GitTrack has
+	// failed to sync
+	AlertSeverityError AlertSeverity = "Error"
+)
+
+// FarosAlertSpec defines which notifications are routed to a FarosProvider
+type FarosAlertSpec struct {
+	// ProviderRef is the name of the FarosProvider in the same namespace that
+	// notifications are sent to
+	ProviderRef string `json:"providerRef"`
+
+	// GitTrackSelector selects the GitTracks in the same namespace whose
+	// notifications are sent. Defaults to all GitTracks.
+	GitTrackSelector *metav1.LabelSelector `json:"gitTrackSelector,omitempty"`
+
+	// Severity is the minimum severity of the notifications sent. Accepted values are "Info", "Error".
+	// Defaults to "Info", which sends both failures and recoveries.
+	// +kubebuilder:validation:Enum=Info,Error
+	Severity AlertSeverity `json:"severity,omitempty"`
+
+	// Suspend stops notifications being sent while true
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FarosAlert is the Schema for the farosalerts API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=farosalerts,categories=faros
+// +kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.providerRef"
+// +kubebuilder:printcolumn:name="Severity",type="string",JSONPath=".spec.severity"
+// +kubebuilder:printcolumn:name="Suspended",type="boolean",JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type FarosAlert struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FarosAlertSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FarosAlertList contains a list of FarosAlert
+type FarosAlertList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FarosAlert `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FarosAlert{}, &FarosAlertList{})
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FarosClusterSpec defines a cluster that GitTracks can apply children to.
+// GitTracks select clusters by the labels of the FarosCluster.
+type FarosClusterSpec struct {
+	// KubeConfigSecretRef holds a reference to a Secret in the same namespace
+	// containing a kubeconfig for the cluster
+	KubeConfigSecretRef GitTrackSecretReference `json:"kubeConfigSecretRef"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FarosCluster is the Schema for the farosclusters API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=farosclusters,categories=faros
+// +kubebuilder:printcolumn:name="Secret",type="string",JSONPath=".spec.kubeConfigSecretRef.secretName"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type FarosCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FarosClusterSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FarosClusterList contains a list of FarosCluster
+type FarosClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FarosCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FarosCluster{}, &FarosClusterList{})
+}
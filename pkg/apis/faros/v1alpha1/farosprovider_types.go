@@ -0,0 +1,81 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 3.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProviderType is the type of endpoint a FarosProvider sends notifications to
+type ProviderType string
+
+const (
+	// ProviderTypeSlack posts notifications to a Slack incoming webhook
+	ProviderTypeSlack ProviderType = "Slack"
+	// ProviderTypeTeams posts notifications to a Microsoft Teams incoming webhook
+	ProviderTypeTeams ProviderType = "Teams"
+	// ProviderTypePagerDuty triggers and resolves PagerDuty incidents
+	ProviderTypePagerDuty ProviderType = "PagerDuty"
+	// ProviderTypeOpsgenie creates and closes Opsgenie alerts
+	ProviderTypeOpsgenie ProviderType = "Opsgenie"
+	// ProviderTypeWebhook posts notifications as JSON to a URL
+	ProviderTypeWebhook ProviderType = "Webhook"
+)
+
+// FarosProviderSpec defines an endpoint that notifications can be sent to
+type FarosProviderSpec struct {
+	// Type is the type of the endpoint. Accepted values are "Slack", "Teams", "PagerDuty", "Opsgenie", "Webhook".
+	// +kubebuilder:validation:Enum=Slack,Teams,PagerDuty,Opsgenie,Webhook
+	Type ProviderType `json:"type"`
+
+	// Address is the URL notifications are sent to. Defaults to the PagerDuty
+	// Events API for the PagerDuty type and the Opsgenie API for the Opsgenie type.
+	Address string `json:"address,omitempty"`
+
+	// SecretName is the name of a Secret in the same namespace holding
+	// credentials. Its `address` key overrides Address and its `token` key
+	// holds the PagerDuty routing key or the Opsgenie API key.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FarosProvider is the Schema for the farosproviders API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=farosproviders,categories=faros
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+type FarosProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec FarosProviderSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FarosProviderList contains a list of FarosProvider
+type FarosProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FarosProvider `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FarosProvider{}, &FarosProviderList{})
+}
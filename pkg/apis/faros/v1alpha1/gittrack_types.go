@@ -31,6 +31,83 @@ const (
 	GitCredentialTypeHTTPBasicAuth = "HTTPBasicAuth"
 )
 
+// PrunePolicy defines how GitTrackObjects removed from the repository are handled
+type PrunePolicy string
+
+const (
+	// PrunePolicyDelete deletes GitTrackObjects whose manifests have been
+	// removed from the repository
+	PrunePolicyDelete PrunePolicy = "Delete"
+	// PrunePolicyRetain leaves GitTrackObjects whose manifests have been
+	// removed from the repository in place
+	PrunePolicyRetain PrunePolicy = "Retain"
+	// PrunePolicyRetainAnnotated leaves GitTrackObjects whose manifests have
+	// been removed from the repository in place if the manifest had the
+	// `faros.pusher.com/retain` annotation, and deletes all others
+	PrunePolicyRetainAnnotated PrunePolicy = "RetainAnnotated"
+)
+
+// GitTrackLayout defines how the directories of a repository map to the
+// objects it contains
+type GitTrackLayout string
+
+const (
+	// GitTrackLayoutFlat reads the namespace of each object from its manifest
+	GitTrackLayoutFlat GitTrackLayout = "Flat"
+	// GitTrackLayoutNamespaced sets the namespace of each namespaced object to
+	// the directory beneath the subPath its manifest is in
+	GitTrackLayoutNamespaced GitTrackLayout = "Namespaced"
+)
+
+// PolicyCheckMode defines what happens to children that violate the admission
+// policies of the cluster
+type PolicyCheckMode string
+
+const (
+	// PolicyCheckWarn reports children that violate a policy and applies them
+	// regardless
+	PolicyCheckWarn PolicyCheckMode = "Warn"
+	// PolicyCheckEnforce reports children that violate a policy and leaves
+	// them as they are
+	PolicyCheckEnforce PolicyCheckMode = "Enforce"
+)
+
+// SyncMode defines whether files that cannot be synced block the rest of a
+// sync
+type SyncMode string
+
+const (
+	// SyncModeStrict leaves every child as it is while any file in the
+	// repository cannot be synced
+	SyncModeStrict SyncMode = "Strict"
+	// SyncModePartial syncs the children that are valid and reports the files
+	// that cannot be synced
+	SyncModePartial SyncMode = "Partial"
+)
+
+// GitTrackPriority determines the queue a GitTrack is reconciled from
+type GitTrackPriority string
+
+const (
+	// GitTrackPriorityHigh reconciles the GitTrack from a separate queue,
+	// ahead of GitTracks with Normal priority
+	GitTrackPriorityHigh GitTrackPriority = "High"
+
+	// GitTrackPriorityNormal reconciles the GitTrack from the shared queue
+	GitTrackPriorityNormal GitTrackPriority = "Normal"
+)
+
+// CommitStatusProvider is the API to which commit statuses are reported
+type CommitStatusProvider string
+
+const (
+	// CommitStatusProviderGitHub reports commit statuses to the GitHub API
+	CommitStatusProviderGitHub CommitStatusProvider = "GitHub"
+
+	// CommitStatusProviderGitLab reports commit statuses to the GitLab API
+	CommitStatusProviderGitLab CommitStatusProvider = "GitLab"
+)
+
 // GitTrackSpec defines the desired state of GitTrack
 type GitTrackSpec struct {
 	// Reference contains the git reference this GitTrack tracks
@@ -43,8 +120,291 @@ type GitTrackSpec struct {
 	// SubPath is the subpath within the repository underneath which files are considered
 	SubPath string `json:"subPath,omitempty"`
 
+	// Layout determines how the directories beneath the subPath map to the
+	// objects they contain. Accepted values are "Flat" and "Namespaced". In
+	// the Namespaced layout, namespaced objects in `<subPath>/<namespace>/`
+	// are created in the namespace named by the directory, overriding the
+	// namespace of their manifests. Defaults to "Flat".
+	// +kubebuilder:validation:Enum=Flat,Namespaced
+	Layout GitTrackLayout `json:"layout,omitempty"`
+
+	// DeletionPropagation is the propagation policy used when deleting the
+	// children of the GitTrack, whether they are pruned or recreated, unless
+	// overridden by the `faros.pusher.com/deletion-propagation` annotation of
+	// a child. Accepted values are "Foreground" and "Background". Defaults to
+	// the propagation policy used by each type of deletion.
+	// +kubebuilder:validation:Enum=Foreground,Background
+	DeletionPropagation metav1.DeletionPropagation `json:"deletionPropagation,omitempty"`
+
 	// DeployKey holds a reference to an SSH key needed to access the repository
 	DeployKey GitTrackDeployKey `json:"deployKey,omitempty"`
+
+	// DeployKeys are used instead of DeployKey for the subPaths or references
+	// they are scoped to, so that GitTracks of different parts of a monorepo
+	// can use different credentials. The first key matching the path and
+	// reference read or written is used, falling back to DeployKey.
+	DeployKeys []GitTrackScopedDeployKey `json:"deployKeys,omitempty"`
+
+	// PrunePolicy determines what happens to GitTrackObjects whose manifests are
+	// removed from the repository. Accepted values are "Delete", "Retain", "RetainAnnotated". Defaults to "Delete".
+	// +kubebuilder:validation:Enum=Delete,Retain,RetainAnnotated
+	PrunePolicy PrunePolicy `json:"prunePolicy,omitempty"`
+
+	// RevisionHistoryLimit is the number of GitTrackRevisions to keep for this
+	// GitTrack. 0 disables recording revisions. Defaults to the controller's
+	// --revision-history-limit flag.
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// CommitStatus configures reporting the result of each sync back to the
+	// repository host as a commit status
+	CommitStatus *GitTrackCommitStatus `json:"commitStatus,omitempty"`
+
+	// AllowedNamespaces restricts the namespaces the repository's manifests may
+	// target. The GitTrack is rejected if any namespaced manifest targets a
+	// namespace outside this list. All namespaces are allowed if empty.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// AllowedKinds restricts the kinds of objects the repository's manifests
+	// may contain, regardless of the resources the controller allows. The
+	// GitTrack is rejected if any manifest is of a kind outside this list. All
+	// kinds are allowed if empty.
+	AllowedKinds []GitTrackAllowedKind `json:"allowedKinds,omitempty"`
+
+	// PolicyCheck submits each child to the API server as a server side dry
+	// run before it is applied so that the admission webhooks of the cluster,
+	// such as OPA Gatekeeper, can reject it. Violations are listed in
+	// status.policyViolations. "Warn" applies the children regardless,
+	// "Enforce" leaves the children that violate a policy as they are.
+	// Children are not checked if empty.
+	// +kubebuilder:validation:Enum=Warn,Enforce
+	PolicyCheck PolicyCheckMode `json:"policyCheck,omitempty"`
+
+	// SyncMode determines what happens when files in the repository cannot be
+	// synced, because they cannot be parsed or an object they define is
+	// invalid. "Strict" leaves every child as it is until all of the files
+	// can be synced, "Partial" syncs the valid objects and reports the files
+	// that cannot be synced. Defaults to "Partial".
+	// +kubebuilder:validation:Enum=Strict,Partial
+	SyncMode SyncMode `json:"syncMode,omitempty"`
+
+	// Priority determines the queue the GitTrack is reconciled from. "High"
+	// GitTracks, such as cluster add-ons, are reconciled by their own workers
+	// so that they are not held up behind "Normal" GitTracks. Defaults to
+	// "Normal".
+	// +kubebuilder:validation:Enum=High,Normal
+	Priority GitTrackPriority `json:"priority,omitempty"`
+
+	// Suspend pauses syncing the repository while true. Children already
+	// created are left in place.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// SyncWindows restricts when changes from the repository are applied.
+	// Changes are held back while any Deny window is active or, if there are
+	// Allow windows, while none of them are active.
+	SyncWindows []SyncWindow `json:"syncWindows,omitempty"`
+
+	// RequireApproval holds back changes from the repository until they have
+	// been approved. The changes are listed in status.pendingPlan.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// Canary rolls changes out to the selected children first and only
+	// applies them to the rest once the selected children are healthy
+	Canary *GitTrackCanary `json:"canary,omitempty"`
+
+	// RollbackTo is the name of a GitTrackRevision of this GitTrack to roll
+	// back to. While set, the commit recorded by the revision is synced
+	// instead of Reference.
+	RollbackTo string `json:"rollbackTo,omitempty"`
+
+	// AutoRollback rolls back to the last healthy revision when the children
+	// stay unhealthy after a new commit is synced
+	AutoRollback *GitTrackAutoRollback `json:"autoRollback,omitempty"`
+
+	// KubeConfigSecretRef holds a reference to a kubeconfig for the cluster
+	// the children are applied to. The GitTrackObjects stay in this cluster.
+	// Children are applied to this cluster if unset.
+	KubeConfigSecretRef *GitTrackSecretReference `json:"kubeConfigSecretRef,omitempty"`
+
+	// ClusterSelector selects FarosClusters in this namespace by their labels.
+	// The children are applied to every selected cluster, with a
+	// GitTrackObject per child and cluster. Takes precedence over
+	// KubeConfigSecretRef.
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+
+	// ImageUpdates configures committing newer tags of images to the
+	// repository, which are then deployed by syncing it. Requires the
+	// controller's --image-update-interval flag.
+	ImageUpdates *GitTrackImageUpdates `json:"imageUpdates,omitempty"`
+
+	// SyncReport configures committing a report of each sync to the
+	// repository, recording the outcome of deployments alongside the
+	// manifests
+	SyncReport *GitTrackSyncReport `json:"syncReport,omitempty"`
+
+	// Verification requires every manifest synced to be signed with cosign
+	// by one of the trusted keys or identities. Nothing is synced if any
+	// manifest is unsigned or its signature cannot be verified.
+	Verification *GitTrackVerification `json:"verification,omitempty"`
+}
+
+// GitTrackVerification configures verifying the cosign signatures of the
+// manifests synced. Each manifest is signed with `cosign sign-blob`, its
+// signature stored beside it with a .sig suffix and, for keyless signatures,
+// its certificate with a .pem suffix.
+type GitTrackVerification struct {
+	// PublicKeys holds a reference to the PEM encoded cosign public keys
+	// trusted to sign the manifests
+	PublicKeys *GitTrackSecretReference `json:"publicKeys,omitempty"`
+
+	// Roots holds a reference to the PEM encoded certificates of the
+	// certificate authorities, eg. the Fulcio root, trusted to issue the
+	// certificates of keyless signatures. Required by identities.
+	Roots *GitTrackSecretReference `json:"roots,omitempty"`
+
+	// Identities are the keyless signing identities trusted to sign the
+	// manifests
+	Identities []GitTrackSigningIdentity `json:"identities,omitempty"`
+}
+
+// GitTrackSigningIdentity is a keyless signing identity
+type GitTrackSigningIdentity struct {
+	// Issuer is the URL of the OIDC issuer that authenticated the identity,
+	// eg. https://token.actions.githubusercontent.com
+	Issuer string `json:"issuer"`
+
+	// Subject is the email address or URI of the identity
+	Subject string `json:"subject"`
+}
+
+// GitTrackSyncReport configures committing a report of each sync to the
+// repository
+type GitTrackSyncReport struct {
+	// Branch the reports are committed to. The branch is created from the
+	// default branch if it does not exist.
+	Branch string `json:"branch"`
+
+	// Path is the directory the reports are written to. Defaults to
+	// ".faros/reports".
+	Path string `json:"path,omitempty"`
+}
+
+// GitTrackImageUpdates configures committing newer tags of images to the
+// repository
+type GitTrackImageUpdates struct {
+	// Images are the images whose tags are updated
+	Images []ImagePolicy `json:"images"`
+
+	// Branch the updated tags are committed to. Defaults to Reference.
+	Branch string `json:"branch,omitempty"`
+
+	// PullSecretName is the name of a Secret of type
+	// `kubernetes.io/dockerconfigjson` in the same namespace holding
+	// credentials for the registries
+	PullSecretName string `json:"pullSecretName,omitempty"`
+}
+
+// ImagePolicy selects the tag an image is updated to. The highest semantic
+// version among the image's tags that matches the policy is selected.
+type ImagePolicy struct {
+	// Image is the image without a tag, as written in the manifests
+	Image string `json:"image"`
+
+	// Semver is the range of versions the tag must be in, eg ">=1.2.0 <2.0.0"
+	// or "^1.2". Any version is accepted if empty.
+	Semver string `json:"semver,omitempty"`
+
+	// Pattern is a regular expression the tag must match
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// GitTrackAutoRollback configures rolling back to the last healthy revision
+type GitTrackAutoRollback struct {
+	// Timeout is how long the children may be unhealthy after a new commit is
+	// synced before rolling back
+	Timeout metav1.Duration `json:"timeout"`
+}
+
+// GitTrackCanary selects the children that changes are rolled out to first.
+// A child is selected if it is in one of the namespaces or its labels match
+// the selector.
+type GitTrackCanary struct {
+	// Namespaces of the children to roll changes out to first
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Selector matches the labels of the children to roll changes out to first
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// SyncWindowKind determines whether a SyncWindow allows or denies syncing
+type SyncWindowKind string
+
+const (
+	// SyncWindowAllow allows changes to be applied while the window is active
+	SyncWindowAllow SyncWindowKind = "Allow"
+
+	// SyncWindowDeny prevents changes being applied while the window is active
+	SyncWindowDeny SyncWindowKind = "Deny"
+)
+
+// SyncWindow is a recurring period of time during which changes from the
+// repository are, or are not, applied
+type SyncWindow struct {
+	// Kind of the window. Accepted values are "Allow", "Deny".
+	// +kubebuilder:validation:Enum=Allow,Deny
+	Kind SyncWindowKind `json:"kind"`
+
+	// Schedule is a cron expression (minute hour day-of-month month day-of-week)
+	// for the start of each window
+	Schedule string `json:"schedule"`
+
+	// Duration is how long each window lasts from its start
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA time zone the schedule is evaluated in. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// GitTrackAllowedKind identifies a kind of object a GitTrack may manage
+type GitTrackAllowedKind struct {
+	// Group of the kind, empty for the core API group
+	Group string `json:"group,omitempty"`
+
+	// Version of the kind, any version is allowed if empty
+	Version string `json:"version,omitempty"`
+
+	// Kind of the object, eg Deployment
+	Kind string `json:"kind"`
+}
+
+// GitTrackCommitStatus configures reporting the result of a sync as a commit status
+type GitTrackCommitStatus struct {
+	// Provider is the API the status is reported to. Accepted values are "GitHub", "GitLab".
+	// +kubebuilder:validation:Enum=GitHub,GitLab
+	Provider CommitStatusProvider `json:"provider"`
+
+	// TokenSecret holds a reference to an API token with permission to set commit statuses.
+	// Defaults to the password of the deploy key if it has type "HTTPBasicAuth".
+	TokenSecret *GitTrackSecretReference `json:"tokenSecret,omitempty"`
+
+	// Context labels the status. Defaults to "faros/<namespace>/<name>".
+	Context string `json:"context,omitempty"`
+
+	// TargetURL is the details URL linked from the status
+	TargetURL string `json:"targetURL,omitempty"`
+
+	// APIURL is the base URL of the provider's API. Defaults to the public GitHub API,
+	// or the API of the repository's host for GitLab.
+	APIURL string `json:"apiURL,omitempty"`
+}
+
+// GitTrackSecretReference holds a reference to a key within a Secret
+type GitTrackSecretReference struct {
+	// SecretName is the name of the Secret object containing the key
+	SecretName string `json:"secretName"`
+
+	// Key is the key within the Secret object
+	Key string `json:"key"`
 }
 
 // GitTrackDeployKey holds a reference to a secret such as an SSH key or HTTP Basic Auth credentials needed to access the repository
@@ -60,6 +420,20 @@ type GitTrackDeployKey struct {
 	Type GitCredentialType `json:"type,omitempty"`
 }
 
+// GitTrackScopedDeployKey is a deploy key used for part of the repository
+type GitTrackScopedDeployKey struct {
+	// SubPath scopes the key to this directory of the repository and the
+	// directories beneath it. The key is used for any path if empty.
+	SubPath string `json:"subPath,omitempty"`
+
+	// Reference scopes the key to this reference of the repository. The key
+	// is used for any reference if empty.
+	Reference string `json:"reference,omitempty"`
+
+	// DeployKey holds a reference to the credentials
+	DeployKey GitTrackDeployKey `json:"deployKey"`
+}
+
 // GitTrackStatus defines the observed state of GitTrack
 type GitTrackStatus struct {
 	// ObjectsDiscovered is the number of k8s objects found in the repository path
@@ -74,13 +448,190 @@ type GitTrackStatus struct {
 	// ObjectsInSync is the number of GitTrackObjects that were successfully applied to the cluster
 	ObjectsInSync int64 `json:"objectsInSync"`
 
+	// ObjectsPruned is the number of GitTrackObjects deleted because their manifests were removed from the repository
+	ObjectsPruned int64 `json:"objectsPruned,omitempty"`
+
+	// ObjectsRetained is the number of GitTrackObjects retained by the prune policy after their manifests were removed from the repository
+	ObjectsRetained int64 `json:"objectsRetained,omitempty"`
+
+	// LastSyncedReference is the reference at which all children were last synced successfully
+	LastSyncedReference string `json:"lastSyncedReference,omitempty"`
+
 	// IgnoredFiles is the list of YAML files containing invalid k8s manifests.
 	IgnoredFiles map[string]string `json:"ignoredFiles,omitempty"`
 
+	// ObservedGeneration is the most recent generation observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastSyncToken is the value of the `faros.pusher.com/sync-now` annotation
+	// for which the repository was last fetched and synced
+	LastSyncToken string `json:"lastSyncToken,omitempty"`
+
+	// PendingDestructiveChanges is the set of destructive changes awaiting approval
+	PendingDestructiveChanges *PendingDestructiveChanges `json:"pendingDestructiveChanges,omitempty"`
+
+	// PendingPlan is the set of changes awaiting approval when spec.requireApproval is set
+	PendingPlan *PendingPlan `json:"pendingPlan,omitempty"`
+
+	// Rollback is the revision being synced while spec.rollbackTo is set
+	Rollback *GitTrackRollback `json:"rollback,omitempty"`
+
+	// LastHealthyRevision is the newest revision after which all children
+	// were healthy, which spec.autoRollback rolls back to
+	LastHealthyRevision *GitTrackRollback `json:"lastHealthyRevision,omitempty"`
+
+	// ChildrenOutOfSync lists the children that are out of sync or failed to be applied.
+	// At most 20 children are listed.
+	ChildrenOutOfSync []GitTrackChildStatus `json:"childrenOutOfSync,omitempty"`
+
+	// FileErrors lists the errors of the files in the repository that could
+	// not be parsed or turned into GitTrackObjects.
+	// At most 20 errors are listed.
+	FileErrors []GitTrackFileError `json:"fileErrors,omitempty"`
+
+	// PolicyViolations lists the children rejected by the admission policies
+	// of the cluster when spec.policyCheck is set.
+	// At most 20 children are listed.
+	PolicyViolations []GitTrackChildStatus `json:"policyViolations,omitempty"`
+
+	// ImageUpdates is the state of the image updates configured by spec.imageUpdates
+	ImageUpdates *GitTrackImageUpdateStatus `json:"imageUpdates,omitempty"`
+
+	// Clusters summarises the children applied to each cluster selected by
+	// spec.clusterSelector
+	Clusters []GitTrackClusterStatus `json:"clusters,omitempty"`
+
 	// Conditions are the conditions on this GitTrack
 	Conditions []GitTrackCondition `json:"conditions,omitempty"`
 }
 
+// GitTrackChildStatus describes a child of a GitTrack that is not in sync
+type GitTrackChildStatus struct {
+	// Name is the namespaced name of the GitTrackObject
+	Name string `json:"name"`
+
+	// Kind of the tracked object
+	Kind string `json:"kind"`
+
+	// Reason the child is not in sync
+	Reason string `json:"reason"`
+
+	// File is the path of the file in the repository the child was read from
+	File string `json:"file,omitempty"`
+}
+
+// FileErrorClass classifies the error of a file in the repository
+type FileErrorClass string
+
+const (
+	// FileErrorClassParse means the file could not be parsed as k8s manifests
+	FileErrorClassParse FileErrorClass = "ParseError"
+	// FileErrorClassInvalidObject means an object in the file could not be
+	// turned into a GitTrackObject, for example because its kind is unknown
+	FileErrorClassInvalidObject FileErrorClass = "InvalidObject"
+	// FileErrorClassApply means the GitTrackObject for an object in the file
+	// could not be created or updated
+	FileErrorClassApply FileErrorClass = "ApplyError"
+	// FileErrorClassDuplicateDefinition means an object in the file is also
+	// defined elsewhere in the repository
+	FileErrorClassDuplicateDefinition FileErrorClass = "DuplicateDefinition"
+)
+
+// GitTrackFileError describes a file in the repository that could not be
+// synced
+type GitTrackFileError struct {
+	// Path of the file in the repository
+	Path string `json:"path"`
+
+	// Class of the error
+	Class FileErrorClass `json:"class"`
+
+	// Message describing the error
+	Message string `json:"message"`
+}
+
+// GitTrackImageUpdateStatus describes the latest tags of the images updated
+// by a GitTrack
+type GitTrackImageUpdateStatus struct {
+	// LatestTags maps each image to the latest tag selected by its policy
+	LatestTags map[string]string `json:"latestTags,omitempty"`
+
+	// LastCommit is the last commit made to update the tags
+	LastCommit string `json:"lastCommit,omitempty"`
+
+	// LastUpdateTime is when the tags were last committed
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// GitTrackClusterStatus summarises the children of a GitTrack applied to a
+// FarosCluster
+type GitTrackClusterStatus struct {
+	// Name is the name of the FarosCluster
+	Name string `json:"name"`
+
+	// ObjectsApplied is the number of GitTrackObjects created for the cluster
+	ObjectsApplied int64 `json:"objectsApplied"`
+
+	// ObjectsInSync is the number of GitTrackObjects successfully applied to the cluster
+	ObjectsInSync int64 `json:"objectsInSync"`
+
+	// ObjectsUnhealthy is the number of children in the cluster that have not
+	// reached their desired state
+	ObjectsUnhealthy int64 `json:"objectsUnhealthy"`
+}
+
+// PendingDestructiveChanges describes destructive changes that require
+// approval before they are carried out
+type PendingDestructiveChanges struct {
+	// ID identifies this set of changes. Set the `faros.pusher.com/approve-destructive-changes`
+	// annotation on the GitTrack to this value to approve them.
+	ID string `json:"id"`
+
+	// Deletions is the list of GitTrackObjects that will be deleted
+	Deletions []string `json:"deletions"`
+
+	// Recreations is the list of GitTrackObjects whose children will be
+	// deleted and created again
+	Recreations []string `json:"recreations,omitempty"`
+}
+
+// GitTrackRollback describes a revision a GitTrack is rolled back to
+type GitTrackRollback struct {
+	// Revision is the name of the GitTrackRevision rolled back to
+	Revision string `json:"revision"`
+
+	// Commit is the commit recorded by the revision, which is synced instead
+	// of spec.reference
+	Commit string `json:"commit"`
+
+	// Reason is set when the GitTrack was rolled back automatically
+	Reason string `json:"reason,omitempty"`
+}
+
+// PendingPlan describes the changes a sync would make, which require approval
+// before they are carried out
+type PendingPlan struct {
+	// ID identifies this plan. Set the `faros.pusher.com/approve-plan`
+	// annotation on the GitTrack to this value to approve it.
+	ID string `json:"id"`
+
+	// Reference is the reference the plan was made for
+	Reference string `json:"reference"`
+
+	// Creations is the list of GitTrackObjects that will be created
+	Creations []string `json:"creations"`
+
+	// Updates is the list of GitTrackObjects that will be updated
+	Updates []string `json:"updates"`
+
+	// Deletions is the list of GitTrackObjects that will be deleted
+	Deletions []string `json:"deletions"`
+
+	// Recreations is the list of GitTrackObjects whose children will be
+	// deleted and created again
+	Recreations []string `json:"recreations,omitempty"`
+}
+
 // GitTrackConditionType is the type of a GitTrackCondition
 type GitTrackConditionType string
 
@@ -99,6 +650,22 @@ const (
 	// ChildrenGarbageCollectedType referes to whether all children that were meant to
 	// be GC'd have been GC'
 	ChildrenGarbageCollectedType GitTrackConditionType = "ChildrenGarbageCollected"
+
+	// ChildrenHealthyType refers to whether all children have reached their
+	// desired state
+	ChildrenHealthyType GitTrackConditionType = "ChildrenHealthy"
+
+	// SuspendedType refers to whether syncing has been suspended by
+	// spec.suspend
+	SuspendedType GitTrackConditionType = "Suspended"
+
+	// SyncWindowOpenType refers to whether spec.syncWindows allow changes to
+	// be applied
+	SyncWindowOpenType GitTrackConditionType = "SyncWindowOpen"
+
+	// PolicyCompliantType refers to whether the children comply with the
+	// admission policies of the cluster when spec.policyCheck is set
+	PolicyCompliantType GitTrackConditionType = "PolicyCompliant"
 )
 
 // GitTrackCondition is a status condition for a GitTrack
@@ -120,6 +687,9 @@ type GitTrackCondition struct {
 
 	// Message associated with this condition
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the GitTrack this condition was set for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +genclient
@@ -127,12 +697,19 @@ type GitTrackCondition struct {
 
 // GitTrack is the Schema for the gittracks API
 // +k8s:openapi-gen=true
+// +kubebuilder:resource:path=gittracks,shortName=gt,categories=faros
+// +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Repository",type="string",JSONPath=".spec.repository",priority=1
 // +kubebuilder:printcolumn:name="Reference",type="string",JSONPath=".spec.reference"
-// +kubebuilder:printcolumn:name="Children Created",type="integer",JSONPath=".status.objectsApplied"
-// +kubebuilder:printcolumn:name="Resources Discovered",type="integer",JSONPath=".status.objectsDiscovered"
-// +kubebuilder:printcolumn:name="Resources Ignored",type="integer",JSONPath=".status.objectsIgnored"
-// +kubebuilder:printcolumn:name="Children In Sync",type="integer",JSONPath=".status.objectsInSync"
+// +kubebuilder:printcolumn:name="Synced Reference",type="string",JSONPath=".status.lastSyncedReference"
+// +kubebuilder:printcolumn:name="Fetched",type="string",JSONPath=".status.conditions[?(@.type=="FilesFetched")].status"
+// +kubebuilder:printcolumn:name="Parsed",type="string",JSONPath=".status.conditions[?(@.type=="FilesParsed")].status"
+// +kubebuilder:printcolumn:name="Up To Date",type="string",JSONPath=".status.conditions[?(@.type=="ChildrenUpToDate")].status"
+// +kubebuilder:printcolumn:name="Healthy",type="string",JSONPath=".status.conditions[?(@.type=="ChildrenHealthy")].status"
+// +kubebuilder:printcolumn:name="Children Created",type="integer",JSONPath=".status.objectsApplied",priority=1
+// +kubebuilder:printcolumn:name="Resources Discovered",type="integer",JSONPath=".status.objectsDiscovered",priority=1
+// +kubebuilder:printcolumn:name="Resources Ignored",type="integer",JSONPath=".status.objectsIgnored",priority=1
+// +kubebuilder:printcolumn:name="Children In Sync",type="integer",JSONPath=".status.objectsInSync",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type GitTrack struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -23,6 +23,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ContentEncoding is the encoding of the data of a GitTrackObject
+type ContentEncoding string
+
+const (
+	// ContentEncodingGzip is used for data compressed with gzip
+	ContentEncodingGzip ContentEncoding = "gzip"
+)
+
 // GitTrackObjectSpec defines the desired state of GitTrackObject
 type GitTrackObjectSpec struct {
 	// Name of the tracked object
@@ -33,12 +41,148 @@ type GitTrackObjectSpec struct {
 
 	// Data representation of the tracked object
 	Data []byte `json:"data"`
+
+	// ContentEncoding is the encoding of Data, which is stored uncompressed if
+	// unset. Accepted values are "gzip".
+	// +kubebuilder:validation:Enum=gzip
+	ContentEncoding ContentEncoding `json:"contentEncoding,omitempty"`
+
+	// DataFrom references the ConfigMaps holding Data when it is too large to
+	// be held by the GitTrackObject, Data is empty when set
+	DataFrom *DataReference `json:"dataFrom,omitempty"`
+
+	// DeletionPropagation is the propagation policy used when deleting the
+	// tracked object, unless overridden by its
+	// `faros.pusher.com/deletion-propagation` annotation. Set from the
+	// deletionPropagation of the owning GitTrack.
+	// +kubebuilder:validation:Enum=Foreground,Background
+	DeletionPropagation metav1.DeletionPropagation `json:"deletionPropagation,omitempty"`
+
+	// Suspend pauses managing the tracked object while true
+	Suspend bool `json:"suspend,omitempty"`
+
+	// KubeConfigSecretRef holds a reference to a kubeconfig for the cluster
+	// the tracked object is applied to. The tracked object is applied to this
+	// cluster if unset.
+	KubeConfigSecretRef *KubeConfigSecretReference `json:"kubeConfigSecretRef,omitempty"`
+
+	// NamespaceSelector selects the namespaces a namespaced tracked object is
+	// applied to, with one child in each matching namespace. Only used by
+	// ClusterGitTrackObjects.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// DataReference references the ConfigMaps holding the data of a GitTrackObject
+type DataReference struct {
+	// Namespace of the ConfigMaps
+	Namespace string `json:"namespace"`
+
+	// ConfigMaps are the names of the ConfigMaps holding the data, which is
+	// the concatenation of the `data` key of their binaryData in order
+	ConfigMaps []string `json:"configMaps"`
+
+	// Hash of the data, used to check that the ConfigMaps are up to date
+	Hash string `json:"hash"`
+}
+
+// KubeConfigSecretReference holds a reference to a kubeconfig within a Secret
+type KubeConfigSecretReference struct {
+	// Namespace of the Secret object
+	Namespace string `json:"namespace"`
+
+	// SecretName is the name of the Secret object containing the kubeconfig
+	SecretName string `json:"secretName"`
+
+	// Key is the key within the Secret object that contains the kubeconfig
+	Key string `json:"key"`
 }
 
 // GitTrackObjectStatus defines the observed state of GitTrackObject
 type GitTrackObjectStatus struct {
 	// Conditions of this object
 	Conditions []GitTrackObjectCondition `json:"conditions,omitempty"`
+
+	// DryRunDiff lists the fields that would be changed if the child did not
+	// have the dry-run update strategy, one per line with their current and
+	// desired values. It is truncated to 4096 bytes.
+	DryRunDiff string `json:"dryRunDiff,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastUpdate describes the most recent update made to the child
+	LastUpdate *ChildUpdate `json:"lastUpdate,omitempty"`
+
+	// ChildHash is a hash of the desired state of the child when it was last
+	// successfully synced
+	ChildHash string `json:"childHash,omitempty"`
+
+	// ChildResourceVersion is the resourceVersion of the child when it was
+	// last successfully synced
+	ChildResourceVersion string `json:"childResourceVersion,omitempty"`
+
+	// ChildStates record the state of each child when it was last
+	// successfully synced, keyed by the kind and namespaced name of the child,
+	// when there is more than one child
+	ChildStates map[string]ChildState `json:"childStates,omitempty"`
+
+	// LastRecreateToken is the value of the faros.pusher.com/recreate
+	// annotation the child was last recreated for
+	LastRecreateToken string `json:"lastRecreateToken,omitempty"`
+
+	// AppliedHash is the SHA-256 hash of spec.data, prefixed with `sha256:`,
+	// when the child was last successfully applied
+	AppliedHash string `json:"appliedHash,omitempty"`
+
+	// LastAppliedTime is the time the child was last created or updated to
+	// match spec.data
+	LastAppliedTime *metav1.Time `json:"lastAppliedTime,omitempty"`
+
+	// LastAppliedData is the gzipped JSON of the children last applied, kept
+	// as the baseline of the next update when the configuration of a child is
+	// too large to be recorded in its last applied annotation
+	LastAppliedData []byte `json:"lastAppliedData,omitempty"`
+
+	// Namespaces the tracked object has been applied to when spec.namespaceSelector
+	// is set
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Children applied from spec.data, so that children whose documents are
+	// removed from spec.data are deleted
+	Children []ChildReference `json:"children,omitempty"`
+}
+
+// ChildReference identifies a child applied by a GitTrackObject
+type ChildReference struct {
+	// APIVersion of the child
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the child
+	Kind string `json:"kind"`
+
+	// Namespace of the child, empty for cluster scoped children
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the child
+	Name string `json:"name"`
+}
+
+// ChildState records the state of a child when it was last successfully synced
+type ChildState struct {
+	// Hash of the desired state of the child
+	Hash string `json:"hash"`
+
+	// ResourceVersion of the child
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// ChildUpdate describes an update made to the child of a GitTrackObject
+type ChildUpdate struct {
+	// Time the child was updated
+	Time metav1.Time `json:"time"`
+
+	// Paths of the fields changed by the update. At most 20 paths are listed.
+	Paths []string `json:"paths,omitempty"`
 }
 
 // GitTrackObjectConditionType is the type of a GitTrackObjectCondition
@@ -47,6 +191,13 @@ type GitTrackObjectConditionType string
 const (
 	// ObjectInSyncType whether the tracked object is in sync or not
 	ObjectInSyncType GitTrackObjectConditionType = "ObjectInSync"
+
+	// ChildHealthyType whether the tracked object has reached its desired state
+	ChildHealthyType GitTrackObjectConditionType = "ChildHealthy"
+
+	// ObjectSuspendedType whether managing the tracked object has been
+	// suspended by spec.suspend
+	ObjectSuspendedType GitTrackObjectConditionType = "Suspended"
 )
 
 // GitTrackObjectCondition is a status condition for a GitTrackObject
@@ -68,6 +219,9 @@ type GitTrackObjectCondition struct {
 
 	// Message associated with this condition
 	Message string `json:"message,omitempty"`
+
+	// ObservedGeneration is the generation of the GitTrackObject this condition was set for
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +genclient
@@ -75,6 +229,8 @@ type GitTrackObjectCondition struct {
 
 // GitTrackObject is the Schema for the gittrackobjects API
 // +k8s:openapi-gen=true
+// +kubebuilder:resource:path=gittrackobjects,shortName=gto,categories=faros
+// +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="In Sync",type="string",JSONPath=".status.conditions[?(@.type=="ObjectInSync")].status"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type GitTrackObject struct {
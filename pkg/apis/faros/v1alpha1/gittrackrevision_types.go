@@ -0,0 +1,110 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 3.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChildApplyResult describes what happened to a child during a sync
+type ChildApplyResult string
+
+const (
+	// ChildApplyResultCreated means the GitTrackObject was created
+	ChildApplyResultCreated ChildApplyResult = "Created"
+	// ChildApplyResultUpdated means the GitTrackObject was updated
+	ChildApplyResultUpdated ChildApplyResult = "Updated"
+	// ChildApplyResultUnchanged means the GitTrackObject was already up to date
+	ChildApplyResultUnchanged ChildApplyResult = "Unchanged"
+	// ChildApplyResultIgnored means no GitTrackObject was created for the object
+	ChildApplyResultIgnored ChildApplyResult = "Ignored"
+	// ChildApplyResultFailed means the GitTrackObject could not be applied
+	ChildApplyResultFailed ChildApplyResult = "Failed"
+	// ChildApplyResultPruned means the GitTrackObject was deleted because its
+	// manifest was removed from the repository
+	ChildApplyResultPruned ChildApplyResult = "Pruned"
+)
+
+// GitTrackRevisionSpec records the outcome of a successful sync of a GitTrack
+type GitTrackRevisionSpec struct {
+	// GitTrack is the name of the GitTrack that was synced
+	GitTrack string `json:"gitTrack"`
+
+	// Repository is the git repository URI that was synced
+	Repository string `json:"repository"`
+
+	// Reference is the git reference that was synced
+	Reference string `json:"reference"`
+
+	// Commit is the commit SHA that was synced, set when the reference is a
+	// full commit SHA
+	Commit string `json:"commit,omitempty"`
+
+	// SyncTime is the time the sync completed
+	SyncTime metav1.Time `json:"syncTime"`
+
+	// Files is the list of files read from the repository
+	Files []string `json:"files,omitempty"`
+
+	// Children lists the result of applying each child of the GitTrack
+	Children []GitTrackRevisionChild `json:"children,omitempty"`
+}
+
+// GitTrackRevisionChild describes the result of applying a single child
+type GitTrackRevisionChild struct {
+	// Name is the namespaced name of the GitTrackObject
+	Name string `json:"name"`
+
+	// Kind of the tracked object
+	Kind string `json:"kind,omitempty"`
+
+	// Result of applying the GitTrackObject
+	Result ChildApplyResult `json:"result"`
+
+	// Message gives further detail on the result
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitTrackRevision is the Schema for the gittrackrevisions API
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=gittrackrevisions,shortName=gtr,categories=faros
+// +kubebuilder:printcolumn:name="GitTrack",type="string",JSONPath=".spec.gitTrack"
+// +kubebuilder:printcolumn:name="Reference",type="string",JSONPath=".spec.reference"
+// +kubebuilder:printcolumn:name="Commit",type="string",JSONPath=".spec.commit",priority=1
+// +kubebuilder:printcolumn:name="Synced",type="date",JSONPath=".spec.syncTime"
+type GitTrackRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GitTrackRevisionSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitTrackRevisionList contains a list of GitTrackRevision
+type GitTrackRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitTrackRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitTrackRevision{}, &GitTrackRevisionList{})
+}
@@ -21,9 +21,64 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildReference) DeepCopyInto(out *ChildReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildReference.
+func (in *ChildReference) DeepCopy() *ChildReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildState) DeepCopyInto(out *ChildState) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildState.
+func (in *ChildState) DeepCopy() *ChildState {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChildUpdate) DeepCopyInto(out *ChildUpdate) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildUpdate.
+func (in *ChildUpdate) DeepCopy() *ChildUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(ChildUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterGitTrackObject) DeepCopyInto(out *ClusterGitTrackObject) {
 	*out = *in
@@ -86,27 +141,47 @@ func (in *ClusterGitTrackObjectList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrack) DeepCopyInto(out *GitTrack) {
+func (in *DataReference) DeepCopyInto(out *DataReference) {
+	*out = *in
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataReference.
+func (in *DataReference) DeepCopy() *DataReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DataReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FarosAlert) DeepCopyInto(out *FarosAlert) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrack.
-func (in *GitTrack) DeepCopy() *GitTrack {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosAlert.
+func (in *FarosAlert) DeepCopy() *FarosAlert {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrack)
+	out := new(FarosAlert)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GitTrack) DeepCopyObject() runtime.Object {
+func (in *FarosAlert) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -114,47 +189,94 @@ func (in *GitTrack) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackCondition) DeepCopyInto(out *GitTrackCondition) {
+func (in *FarosAlertList) DeepCopyInto(out *FarosAlertList) {
 	*out = *in
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FarosAlert, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackCondition.
-func (in *GitTrackCondition) DeepCopy() *GitTrackCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosAlertList.
+func (in *FarosAlertList) DeepCopy() *FarosAlertList {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackCondition)
+	out := new(FarosAlertList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FarosAlertList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackDeployKey) DeepCopyInto(out *GitTrackDeployKey) {
+func (in *FarosAlertSpec) DeepCopyInto(out *FarosAlertSpec) {
 	*out = *in
+	if in.GitTrackSelector != nil {
+		in, out := &in.GitTrackSelector, &out.GitTrackSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackDeployKey.
-func (in *GitTrackDeployKey) DeepCopy() *GitTrackDeployKey {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosAlertSpec.
+func (in *FarosAlertSpec) DeepCopy() *FarosAlertSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackDeployKey)
+	out := new(FarosAlertSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackList) DeepCopyInto(out *GitTrackList) {
+func (in *FarosCluster) DeepCopyInto(out *FarosCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosCluster.
+func (in *FarosCluster) DeepCopy() *FarosCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(FarosCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FarosCluster) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FarosClusterList) DeepCopyInto(out *FarosClusterList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]GitTrack, len(*in))
+		*out = make([]FarosCluster, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -162,18 +284,18 @@ func (in *GitTrackList) DeepCopyInto(out *GitTrackList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackList.
-func (in *GitTrackList) DeepCopy() *GitTrackList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosClusterList.
+func (in *FarosClusterList) DeepCopy() *FarosClusterList {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackList)
+	out := new(FarosClusterList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GitTrackList) DeepCopyObject() runtime.Object {
+func (in *FarosClusterList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -181,27 +303,43 @@ func (in *GitTrackList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackObject) DeepCopyInto(out *GitTrackObject) {
+func (in *FarosClusterSpec) DeepCopyInto(out *FarosClusterSpec) {
+	*out = *in
+	out.KubeConfigSecretRef = in.KubeConfigSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosClusterSpec.
+func (in *FarosClusterSpec) DeepCopy() *FarosClusterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FarosClusterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FarosProvider) DeepCopyInto(out *FarosProvider) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Spec = in.Spec
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObject.
-func (in *GitTrackObject) DeepCopy() *GitTrackObject {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosProvider.
+func (in *FarosProvider) DeepCopy() *FarosProvider {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackObject)
+	out := new(FarosProvider)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GitTrackObject) DeepCopyObject() runtime.Object {
+func (in *FarosProvider) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -209,50 +347,76 @@ func (in *GitTrackObject) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackObjectCondition) DeepCopyInto(out *GitTrackObjectCondition) {
+func (in *FarosProviderList) DeepCopyInto(out *FarosProviderList) {
 	*out = *in
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FarosProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectCondition.
-func (in *GitTrackObjectCondition) DeepCopy() *GitTrackObjectCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosProviderList.
+func (in *FarosProviderList) DeepCopy() *FarosProviderList {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackObjectCondition)
+	out := new(FarosProviderList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FarosProviderList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackObjectList) DeepCopyInto(out *GitTrackObjectList) {
+func (in *FarosProviderSpec) DeepCopyInto(out *FarosProviderSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	out.ListMeta = in.ListMeta
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]GitTrackObject, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FarosProviderSpec.
+func (in *FarosProviderSpec) DeepCopy() *FarosProviderSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(FarosProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrack) DeepCopyInto(out *GitTrack) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectList.
-func (in *GitTrackObjectList) DeepCopy() *GitTrackObjectList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrack.
+func (in *GitTrack) DeepCopy() *GitTrack {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackObjectList)
+	out := new(GitTrack)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *GitTrackObjectList) DeepCopyObject() runtime.Object {
+func (in *GitTrack) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -260,92 +424,891 @@ func (in *GitTrackObjectList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackObjectSpec) DeepCopyInto(out *GitTrackObjectSpec) {
+func (in *GitTrackAllowedKind) DeepCopyInto(out *GitTrackAllowedKind) {
 	*out = *in
-	if in.Data != nil {
-		in, out := &in.Data, &out.Data
-		*out = make([]byte, len(*in))
-		copy(*out, *in)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackAllowedKind.
+func (in *GitTrackAllowedKind) DeepCopy() *GitTrackAllowedKind {
+	if in == nil {
+		return nil
 	}
+	out := new(GitTrackAllowedKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackAutoRollback) DeepCopyInto(out *GitTrackAutoRollback) {
+	*out = *in
+	out.Timeout = in.Timeout
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectSpec.
-func (in *GitTrackObjectSpec) DeepCopy() *GitTrackObjectSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackAutoRollback.
+func (in *GitTrackAutoRollback) DeepCopy() *GitTrackAutoRollback {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackObjectSpec)
+	out := new(GitTrackAutoRollback)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackObjectStatus) DeepCopyInto(out *GitTrackObjectStatus) {
+func (in *GitTrackCanary) DeepCopyInto(out *GitTrackCanary) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]GitTrackObjectCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectStatus.
-func (in *GitTrackObjectStatus) DeepCopy() *GitTrackObjectStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackCanary.
+func (in *GitTrackCanary) DeepCopy() *GitTrackCanary {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackObjectStatus)
+	out := new(GitTrackCanary)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackSpec) DeepCopyInto(out *GitTrackSpec) {
+func (in *GitTrackChildStatus) DeepCopyInto(out *GitTrackChildStatus) {
 	*out = *in
-	out.DeployKey = in.DeployKey
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackSpec.
-func (in *GitTrackSpec) DeepCopy() *GitTrackSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackChildStatus.
+func (in *GitTrackChildStatus) DeepCopy() *GitTrackChildStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackSpec)
+	out := new(GitTrackChildStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GitTrackStatus) DeepCopyInto(out *GitTrackStatus) {
+func (in *GitTrackClusterStatus) DeepCopyInto(out *GitTrackClusterStatus) {
 	*out = *in
-	if in.IgnoredFiles != nil {
-		in, out := &in.IgnoredFiles, &out.IgnoredFiles
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackClusterStatus.
+func (in *GitTrackClusterStatus) DeepCopy() *GitTrackClusterStatus {
+	if in == nil {
+		return nil
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]GitTrackCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	out := new(GitTrackClusterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackCommitStatus) DeepCopyInto(out *GitTrackCommitStatus) {
+	*out = *in
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(GitTrackSecretReference)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackStatus.
-func (in *GitTrackStatus) DeepCopy() *GitTrackStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackCommitStatus.
+func (in *GitTrackCommitStatus) DeepCopy() *GitTrackCommitStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(GitTrackStatus)
+	out := new(GitTrackCommitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackCondition) DeepCopyInto(out *GitTrackCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackCondition.
+func (in *GitTrackCondition) DeepCopy() *GitTrackCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackDeployKey) DeepCopyInto(out *GitTrackDeployKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackDeployKey.
+func (in *GitTrackDeployKey) DeepCopy() *GitTrackDeployKey {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackDeployKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackFileError) DeepCopyInto(out *GitTrackFileError) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackFileError.
+func (in *GitTrackFileError) DeepCopy() *GitTrackFileError {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackFileError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackImageUpdateStatus) DeepCopyInto(out *GitTrackImageUpdateStatus) {
+	*out = *in
+	if in.LatestTags != nil {
+		in, out := &in.LatestTags, &out.LatestTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackImageUpdateStatus.
+func (in *GitTrackImageUpdateStatus) DeepCopy() *GitTrackImageUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackImageUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackImageUpdates) DeepCopyInto(out *GitTrackImageUpdates) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ImagePolicy, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackImageUpdates.
+func (in *GitTrackImageUpdates) DeepCopy() *GitTrackImageUpdates {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackImageUpdates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackList) DeepCopyInto(out *GitTrackList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitTrack, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackList.
+func (in *GitTrackList) DeepCopy() *GitTrackList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitTrackList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackObject) DeepCopyInto(out *GitTrackObject) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObject.
+func (in *GitTrackObject) DeepCopy() *GitTrackObject {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitTrackObject) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackObjectCondition) DeepCopyInto(out *GitTrackObjectCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectCondition.
+func (in *GitTrackObjectCondition) DeepCopy() *GitTrackObjectCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackObjectCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackObjectList) DeepCopyInto(out *GitTrackObjectList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitTrackObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectList.
+func (in *GitTrackObjectList) DeepCopy() *GitTrackObjectList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackObjectList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitTrackObjectList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackObjectSpec) DeepCopyInto(out *GitTrackObjectSpec) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataFrom != nil {
+		in, out := &in.DataFrom, &out.DataFrom
+		*out = new(DataReference)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeConfigSecretRef != nil {
+		in, out := &in.KubeConfigSecretRef, &out.KubeConfigSecretRef
+		*out = new(KubeConfigSecretReference)
+		**out = **in
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectSpec.
+func (in *GitTrackObjectSpec) DeepCopy() *GitTrackObjectSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackObjectSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackObjectStatus) DeepCopyInto(out *GitTrackObjectStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]GitTrackObjectCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdate != nil {
+		in, out := &in.LastUpdate, &out.LastUpdate
+		*out = new(ChildUpdate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ChildStates != nil {
+		in, out := &in.ChildStates, &out.ChildStates
+		*out = make(map[string]ChildState, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastAppliedTime != nil {
+		in, out := &in.LastAppliedTime, &out.LastAppliedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastAppliedData != nil {
+		in, out := &in.LastAppliedData, &out.LastAppliedData
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = make([]ChildReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackObjectStatus.
+func (in *GitTrackObjectStatus) DeepCopy() *GitTrackObjectStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackObjectStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackRevision) DeepCopyInto(out *GitTrackRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackRevision.
+func (in *GitTrackRevision) DeepCopy() *GitTrackRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitTrackRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackRevisionChild) DeepCopyInto(out *GitTrackRevisionChild) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackRevisionChild.
+func (in *GitTrackRevisionChild) DeepCopy() *GitTrackRevisionChild {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackRevisionChild)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackRevisionList) DeepCopyInto(out *GitTrackRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitTrackRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackRevisionList.
+func (in *GitTrackRevisionList) DeepCopy() *GitTrackRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitTrackRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackRevisionSpec) DeepCopyInto(out *GitTrackRevisionSpec) {
+	*out = *in
+	in.SyncTime.DeepCopyInto(&out.SyncTime)
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = make([]GitTrackRevisionChild, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackRevisionSpec.
+func (in *GitTrackRevisionSpec) DeepCopy() *GitTrackRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackRollback) DeepCopyInto(out *GitTrackRollback) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackRollback.
+func (in *GitTrackRollback) DeepCopy() *GitTrackRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackScopedDeployKey) DeepCopyInto(out *GitTrackScopedDeployKey) {
+	*out = *in
+	out.DeployKey = in.DeployKey
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackScopedDeployKey.
+func (in *GitTrackScopedDeployKey) DeepCopy() *GitTrackScopedDeployKey {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackScopedDeployKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackSecretReference) DeepCopyInto(out *GitTrackSecretReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackSecretReference.
+func (in *GitTrackSecretReference) DeepCopy() *GitTrackSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackSigningIdentity) DeepCopyInto(out *GitTrackSigningIdentity) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackSigningIdentity.
+func (in *GitTrackSigningIdentity) DeepCopy() *GitTrackSigningIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackSigningIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackSpec) DeepCopyInto(out *GitTrackSpec) {
+	*out = *in
+	out.DeployKey = in.DeployKey
+	if in.DeployKeys != nil {
+		in, out := &in.DeployKeys, &out.DeployKeys
+		*out = make([]GitTrackScopedDeployKey, len(*in))
+		copy(*out, *in)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CommitStatus != nil {
+		in, out := &in.CommitStatus, &out.CommitStatus
+		*out = new(GitTrackCommitStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedKinds != nil {
+		in, out := &in.AllowedKinds, &out.AllowedKinds
+		*out = make([]GitTrackAllowedKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.SyncWindows != nil {
+		in, out := &in.SyncWindows, &out.SyncWindows
+		*out = make([]SyncWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(GitTrackCanary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(GitTrackAutoRollback)
+		**out = **in
+	}
+	if in.KubeConfigSecretRef != nil {
+		in, out := &in.KubeConfigSecretRef, &out.KubeConfigSecretRef
+		*out = new(GitTrackSecretReference)
+		**out = **in
+	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageUpdates != nil {
+		in, out := &in.ImageUpdates, &out.ImageUpdates
+		*out = new(GitTrackImageUpdates)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SyncReport != nil {
+		in, out := &in.SyncReport, &out.SyncReport
+		*out = new(GitTrackSyncReport)
+		**out = **in
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(GitTrackVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackSpec.
+func (in *GitTrackSpec) DeepCopy() *GitTrackSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackStatus) DeepCopyInto(out *GitTrackStatus) {
+	*out = *in
+	if in.IgnoredFiles != nil {
+		in, out := &in.IgnoredFiles, &out.IgnoredFiles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PendingDestructiveChanges != nil {
+		in, out := &in.PendingDestructiveChanges, &out.PendingDestructiveChanges
+		*out = new(PendingDestructiveChanges)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingPlan != nil {
+		in, out := &in.PendingPlan, &out.PendingPlan
+		*out = new(PendingPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rollback != nil {
+		in, out := &in.Rollback, &out.Rollback
+		*out = new(GitTrackRollback)
+		**out = **in
+	}
+	if in.LastHealthyRevision != nil {
+		in, out := &in.LastHealthyRevision, &out.LastHealthyRevision
+		*out = new(GitTrackRollback)
+		**out = **in
+	}
+	if in.ChildrenOutOfSync != nil {
+		in, out := &in.ChildrenOutOfSync, &out.ChildrenOutOfSync
+		*out = make([]GitTrackChildStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.FileErrors != nil {
+		in, out := &in.FileErrors, &out.FileErrors
+		*out = make([]GitTrackFileError, len(*in))
+		copy(*out, *in)
+	}
+	if in.PolicyViolations != nil {
+		in, out := &in.PolicyViolations, &out.PolicyViolations
+		*out = make([]GitTrackChildStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageUpdates != nil {
+		in, out := &in.ImageUpdates, &out.ImageUpdates
+		*out = new(GitTrackImageUpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]GitTrackClusterStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]GitTrackCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackStatus.
+func (in *GitTrackStatus) DeepCopy() *GitTrackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackSyncReport) DeepCopyInto(out *GitTrackSyncReport) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackSyncReport.
+func (in *GitTrackSyncReport) DeepCopy() *GitTrackSyncReport {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackSyncReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTrackVerification) DeepCopyInto(out *GitTrackVerification) {
+	*out = *in
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = new(GitTrackSecretReference)
+		**out = **in
+	}
+	if in.Roots != nil {
+		in, out := &in.Roots, &out.Roots
+		*out = new(GitTrackSecretReference)
+		**out = **in
+	}
+	if in.Identities != nil {
+		in, out := &in.Identities, &out.Identities
+		*out = make([]GitTrackSigningIdentity, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrackVerification.
+func (in *GitTrackVerification) DeepCopy() *GitTrackVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrackVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicy) DeepCopyInto(out *ImagePolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicy.
+func (in *ImagePolicy) DeepCopy() *ImagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeConfigSecretReference) DeepCopyInto(out *KubeConfigSecretReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeConfigSecretReference.
+func (in *KubeConfigSecretReference) DeepCopy() *KubeConfigSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeConfigSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingDestructiveChanges) DeepCopyInto(out *PendingDestructiveChanges) {
+	*out = *in
+	if in.Deletions != nil {
+		in, out := &in.Deletions, &out.Deletions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Recreations != nil {
+		in, out := &in.Recreations, &out.Recreations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingDestructiveChanges.
+func (in *PendingDestructiveChanges) DeepCopy() *PendingDestructiveChanges {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingDestructiveChanges)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingPlan) DeepCopyInto(out *PendingPlan) {
+	*out = *in
+	if in.Creations != nil {
+		in, out := &in.Creations, &out.Creations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Updates != nil {
+		in, out := &in.Updates, &out.Updates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deletions != nil {
+		in, out := &in.Deletions, &out.Deletions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingPlan.
+func (in *PendingPlan) DeepCopy() *PendingPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncWindow) DeepCopyInto(out *SyncWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncWindow.
+func (in *SyncWindow) DeepCopy() *SyncWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncWindow)
 	in.DeepCopyInto(out)
 	return out
 }
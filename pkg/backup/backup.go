@@ -0,0 +1,119 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	// S3Endpoint is the endpoint of the AWS S3 API
+	S3Endpoint = "https://s3.amazonaws.com"
+
+	// GCSEndpoint is the endpoint of the S3 compatible Google Cloud Storage
+	// XML API
+	GCSEndpoint = "https://storage.googleapis.com"
+)
+
+// Store writes backups to object storage
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// Options configure the Store created by New
+type Options struct {
+	// URL is the s3:// or gs:// URL of the bucket and the prefix of the keys
+	// backups are written to
+	URL string
+
+	// Endpoint overrides the endpoint of the object storage API, for S3
+	// compatible stores. Defaults to the endpoint of the provider of the URL.
+	Endpoint string
+
+	// Region is the region of the bucket
+	Region string
+
+	// AccessKeyID and SecretAccessKey authenticate requests. Default to the
+	// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is sent with requests made with temporary credentials.
+	// Defaults to the AWS_SESSION_TOKEN environment variable.
+	SessionToken string
+}
+
+// New creates a Store writing to the bucket named by the URL. S3 buckets are
+// written to through the S3 API and GCS buckets through the S3 compatible XML
+// API, authenticated with HMAC keys.
+func New(opts Options) (Store, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup URL %q: %v", opts.URL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("backup URL %q has no bucket", opts.URL)
+	}
+
+	endpoint := opts.Endpoint
+	switch u.Scheme {
+	case "s3":
+		if endpoint == "" {
+			endpoint = S3Endpoint
+		}
+	case "gs":
+		if endpoint == "" {
+			endpoint = GCSEndpoint
+		}
+	default:
+		return nil, fmt.Errorf("backup URL %q must start with s3:// or gs://", opts.URL)
+	}
+	endpointURL, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || endpointURL.Scheme == "" || endpointURL.Host == "" {
+		return nil, fmt.Errorf("invalid backup endpoint %q", endpoint)
+	}
+
+	if opts.AccessKeyID == "" {
+		opts.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if opts.SecretAccessKey == "" {
+		opts.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if opts.SessionToken == "" {
+		opts.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if opts.AccessKeyID == "" || opts.SecretAccessKey == "" {
+		return nil, fmt.Errorf("backup credentials are required, set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+
+	return newS3(endpointURL, u.Host, strings.Trim(u.Path, "/"), opts), nil
+}
+
+// Key returns the key the backup of the manifests synced by a GitTrack is
+// written to: `<cluster>/<namespace>/<name>/<id>.yaml`, without the cluster if
+// it is empty
+func Key(cluster, namespace, name, id string) string {
+	return path.Join(cluster, namespace, name, id+".yaml")
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestBackup(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Backup Suite", reporters.Reporters())
+}
+
+var _ = Describe("Backup Suite", func() {
+	var opts Options
+
+	BeforeEach(func() {
+		opts = Options{
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		}
+	})
+
+	Context("New", func() {
+		It("writes s3:// URLs to S3", func() {
+			opts.URL = "s3://example-bucket/faros/"
+			store, err := New(opts)
+			Expect(err).NotTo(HaveOccurred())
+			s := store.(*s3)
+			Expect(s.endpoint.String()).To(Equal(S3Endpoint))
+			Expect(s.bucket).To(Equal("example-bucket"))
+			Expect(s.prefix).To(Equal("faros"))
+			Expect(s.opts.Region).To(Equal("us-east-1"))
+		})
+
+		It("writes gs:// URLs to GCS", func() {
+			opts.URL = "gs://example-bucket"
+			store, err := New(opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(store.(*s3).endpoint.String()).To(Equal(GCSEndpoint))
+		})
+
+		It("uses the endpoint when set", func() {
+			opts.URL = "s3://example-bucket"
+			opts.Endpoint = "http://minio.example.com:9000/"
+			store, err := New(opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(store.(*s3).endpoint.String()).To(Equal("http://minio.example.com:9000"))
+		})
+
+		It("rejects other schemes", func() {
+			opts.URL = "https://example-bucket"
+			_, err := New(opts)
+			Expect(err).To(MatchError(ContainSubstring("must start with s3:// or gs://")))
+		})
+
+		It("requires a bucket", func() {
+			opts.URL = "s3:///faros"
+			_, err := New(opts)
+			Expect(err).To(MatchError(ContainSubstring("has no bucket")))
+		})
+
+		It("requires credentials", func() {
+			opts.URL = "s3://example-bucket"
+			opts.AccessKeyID = ""
+			opts.SecretAccessKey = ""
+			_, err := New(opts)
+			Expect(err).To(MatchError(ContainSubstring("backup credentials are required")))
+		})
+	})
+
+	Context("Key", func() {
+		It("includes the cluster when set", func() {
+			Expect(Key("production", "default", "example", "abc123")).To(Equal("production/default/example/abc123.yaml"))
+		})
+
+		It("omits the cluster when empty", func() {
+			Expect(Key("", "default", "example", "abc123")).To(Equal("default/example/abc123.yaml"))
+		})
+	})
+
+	Context("signingKey", func() {
+		It("derives the key of the Signature Version 4 example", func() {
+			key := signingKey(opts.SecretAccessKey, "20120215", "us-east-1", "iam")
+			Expect(hex.EncodeToString(key)).To(Equal("f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"))
+		})
+	})
+
+	Context("Put", func() {
+		var server *httptest.Server
+		var requests []*http.Request
+		var bodies []string
+		var status int
+
+		BeforeEach(func() {
+			requests = []*http.Request{}
+			bodies = []string{}
+			status = http.StatusOK
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				body, _ := ioutil.ReadAll(req.Body)
+				requests = append(requests, req)
+				bodies = append(bodies, string(body))
+				w.WriteHeader(status)
+			}))
+			opts.URL = "s3://example-bucket/faros"
+			opts.Endpoint = server.URL
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		var put = func() error {
+			store, err := New(opts)
+			Expect(err).NotTo(HaveOccurred())
+			store.(*s3).now = func() time.Time {
+				return time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+			}
+			return store.Put(context.TODO(), "default/example/abc123.yaml", []byte("kind: ConfigMap\n"))
+		}
+
+		It("writes the data beneath the prefix of the bucket", func() {
+			Expect(put()).To(Succeed())
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Method).To(Equal(http.MethodPut))
+			Expect(requests[0].URL.Path).To(Equal("/example-bucket/faros/default/example/abc123.yaml"))
+			Expect(bodies[0]).To(Equal("kind: ConfigMap\n"))
+		})
+
+		It("signs the request", func() {
+			Expect(put()).To(Succeed())
+			Expect(requests[0].Header.Get("X-Amz-Date")).To(Equal("20190601T120000Z"))
+			Expect(requests[0].Header.Get("X-Amz-Content-Sha256")).To(Equal(hexSHA256([]byte("kind: ConfigMap\n"))))
+			Expect(requests[0].Header.Get("Authorization")).To(HavePrefix(
+				"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20190601/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=",
+			))
+		})
+
+		It("signs the session token when set", func() {
+			opts.SessionToken = "token"
+			Expect(put()).To(Succeed())
+			Expect(requests[0].Header.Get("X-Amz-Security-Token")).To(Equal("token"))
+			Expect(requests[0].Header.Get("Authorization")).To(ContainSubstring("SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token,"))
+		})
+
+		It("returns an error when the write is rejected", func() {
+			status = http.StatusForbidden
+			Expect(put()).To(MatchError(ContainSubstring("unexpected response")))
+		})
+	})
+})
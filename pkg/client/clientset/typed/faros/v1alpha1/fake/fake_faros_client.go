@@ -32,6 +32,18 @@ func (c *FakeFarosV1alpha1) ClusterGitTrackObjects() v1alpha1.ClusterGitTrackObj
 	return &FakeClusterGitTrackObjects{c}
 }
 
+func (c *FakeFarosV1alpha1) FarosAlerts(namespace string) v1alpha1.FarosAlertInterface {
+	return &FakeFarosAlerts{c, namespace}
+}
+
+func (c *FakeFarosV1alpha1) FarosClusters(namespace string) v1alpha1.FarosClusterInterface {
+	return &FakeFarosClusters{c, namespace}
+}
+
+func (c *FakeFarosV1alpha1) FarosProviders(namespace string) v1alpha1.FarosProviderInterface {
+	return &FakeFarosProviders{c, namespace}
+}
+
 func (c *FakeFarosV1alpha1) GitTracks(namespace string) v1alpha1.GitTrackInterface {
 	return &FakeGitTracks{c, namespace}
 }
@@ -40,6 +52,10 @@ func (c *FakeFarosV1alpha1) GitTrackObjects(namespace string) v1alpha1.GitTrackO
 	return &FakeGitTrackObjects{c, namespace}
 }
 
+func (c *FakeFarosV1alpha1) GitTrackRevisions(namespace string) v1alpha1.GitTrackRevisionInterface {
+	return &FakeGitTrackRevisions{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeFarosV1alpha1) RESTClient() rest.Interface {
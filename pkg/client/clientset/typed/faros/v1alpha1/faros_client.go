@@ -28,8 +28,12 @@ import (
 type FarosV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ClusterGitTrackObjectsGetter
+	FarosAlertsGetter
+	FarosClustersGetter
+	FarosProvidersGetter
 	GitTracksGetter
 	GitTrackObjectsGetter
+	GitTrackRevisionsGetter
 }
 
 // FarosV1alpha1Client is used to interact with features provided by the faros.pusher.com group.
@@ -41,6 +45,18 @@ func (c *FarosV1alpha1Client) ClusterGitTrackObjects() ClusterGitTrackObjectInte
 	return newClusterGitTrackObjects(c)
 }
 
+func (c *FarosV1alpha1Client) FarosAlerts(namespace string) FarosAlertInterface {
+	return newFarosAlerts(c, namespace)
+}
+
+func (c *FarosV1alpha1Client) FarosClusters(namespace string) FarosClusterInterface {
+	return newFarosClusters(c, namespace)
+}
+
+func (c *FarosV1alpha1Client) FarosProviders(namespace string) FarosProviderInterface {
+	return newFarosProviders(c, namespace)
+}
+
 func (c *FarosV1alpha1Client) GitTracks(namespace string) GitTrackInterface {
 	return newGitTracks(c, namespace)
 }
@@ -49,6 +65,10 @@ func (c *FarosV1alpha1Client) GitTrackObjects(namespace string) GitTrackObjectIn
 	return newGitTrackObjects(c, namespace)
 }
 
+func (c *FarosV1alpha1Client) GitTrackRevisions(namespace string) GitTrackRevisionInterface {
+	return newGitTrackRevisions(c, namespace)
+}
+
 // NewForConfig creates a new FarosV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*FarosV1alpha1Client, error) {
 	config := *c
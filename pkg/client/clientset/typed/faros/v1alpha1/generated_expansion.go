@@ -20,6 +20,14 @@ package v1alpha1
 
 type ClusterGitTrackObjectExpansion interface{}
 
+type FarosAlertExpansion interface{}
+
+type FarosClusterExpansion interface{}
+
+type FarosProviderExpansion interface{}
+
 type GitTrackExpansion interface{}
 
 type GitTrackObjectExpansion interface{}
+
+type GitTrackRevisionExpansion interface{}
@@ -26,10 +26,18 @@ import (
 type Interface interface {
 	// ClusterGitTrackObjects returns a ClusterGitTrackObjectInformer.
 	ClusterGitTrackObjects() ClusterGitTrackObjectInformer
+	// FarosAlerts returns a FarosAlertInformer.
+	FarosAlerts() FarosAlertInformer
+	// FarosClusters returns a FarosClusterInformer.
+	FarosClusters() FarosClusterInformer
+	// FarosProviders returns a FarosProviderInformer.
+	FarosProviders() FarosProviderInformer
 	// GitTracks returns a GitTrackInformer.
 	GitTracks() GitTrackInformer
 	// GitTrackObjects returns a GitTrackObjectInformer.
 	GitTrackObjects() GitTrackObjectInformer
+	// GitTrackRevisions returns a GitTrackRevisionInformer.
+	GitTrackRevisions() GitTrackRevisionInformer
 }
 
 type version struct {
@@ -48,6 +56,21 @@ func (v *version) ClusterGitTrackObjects() ClusterGitTrackObjectInformer {
 	return &clusterGitTrackObjectInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// FarosAlerts returns a FarosAlertInformer.
+func (v *version) FarosAlerts() FarosAlertInformer {
+	return &farosAlertInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// FarosClusters returns a FarosClusterInformer.
+func (v *version) FarosClusters() FarosClusterInformer {
+	return &farosClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// FarosProviders returns a FarosProviderInformer.
+func (v *version) FarosProviders() FarosProviderInformer {
+	return &farosProviderInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // GitTracks returns a GitTrackInformer.
 func (v *version) GitTracks() GitTrackInformer {
 	return &gitTrackInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -57,3 +80,8 @@ func (v *version) GitTracks() GitTrackInformer {
 func (v *version) GitTrackObjects() GitTrackObjectInformer {
 	return &gitTrackObjectInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// GitTrackRevisions returns a GitTrackRevisionInformer.
+func (v *version) GitTrackRevisions() GitTrackRevisionInformer {
+	return &gitTrackRevisionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
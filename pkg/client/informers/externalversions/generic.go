@@ -55,10 +55,18 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=faros.pusher.com, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("clustergittrackobjects"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Faros().V1alpha1().ClusterGitTrackObjects().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("farosalerts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Faros().V1alpha1().FarosAlerts().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("farosclusters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Faros().V1alpha1().FarosClusters().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("farosproviders"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Faros().V1alpha1().FarosProviders().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("gittracks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Faros().V1alpha1().GitTracks().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("gittrackobjects"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Faros().V1alpha1().GitTrackObjects().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("gittrackrevisions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Faros().V1alpha1().GitTrackRevisions().Informer()}, nil
 
 	}
 
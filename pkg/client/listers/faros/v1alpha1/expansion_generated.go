@@ -22,6 +22,30 @@ package v1alpha1
 // ClusterGitTrackObjectLister.
 type ClusterGitTrackObjectListerExpansion interface{}
 
+// FarosAlertListerExpansion allows custom methods to be added to
+// FarosAlertLister.
+type FarosAlertListerExpansion interface{}
+
+// FarosAlertNamespaceListerExpansion allows custom methods to be added to
+// FarosAlertNamespaceLister.
+type FarosAlertNamespaceListerExpansion interface{}
+
+// FarosClusterListerExpansion allows custom methods to be added to
+// FarosClusterLister.
+type FarosClusterListerExpansion interface{}
+
+// FarosClusterNamespaceListerExpansion allows custom methods to be added to
+// FarosClusterNamespaceLister.
+type FarosClusterNamespaceListerExpansion interface{}
+
+// FarosProviderListerExpansion allows custom methods to be added to
+// FarosProviderLister.
+type FarosProviderListerExpansion interface{}
+
+// FarosProviderNamespaceListerExpansion allows custom methods to be added to
+// FarosProviderNamespaceLister.
+type FarosProviderNamespaceListerExpansion interface{}
+
 // GitTrackListerExpansion allows custom methods to be added to
 // GitTrackLister.
 type GitTrackListerExpansion interface{}
@@ -37,3 +61,11 @@ type GitTrackObjectListerExpansion interface{}
 // GitTrackObjectNamespaceListerExpansion allows custom methods to be added to
 // GitTrackObjectNamespaceLister.
 type GitTrackObjectNamespaceListerExpansion interface{}
+
+// GitTrackRevisionListerExpansion allows custom methods to be added to
+// GitTrackRevisionLister.
+type GitTrackRevisionListerExpansion interface{}
+
+// GitTrackRevisionNamespaceListerExpansion allows custom methods to be added to
+// GitTrackRevisionNamespaceLister.
+type GitTrackRevisionNamespaceListerExpansion interface{}
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitstatus
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// State is the state of a commit status
+type State string
+
+const (
+	// StateSuccess is reported when all children were synced
+	StateSuccess State = "success"
+
+	// StateFailure is reported when the sync failed
+	StateFailure State = "failure"
+
+	// StatePending is reported when changes are waiting to be applied
+	StatePending State = "pending"
+)
+
+// Status is the result of syncing a reference of a repository
+type Status struct {
+	// Repository is the URL of the repository, as given in the GitTrack
+	Repository string
+
+	// Reference is the git reference that was synced
+	Reference string
+
+	State       State
+	Description string
+	Context     string
+	TargetURL   string
+}
+
+// Reporter reports commit statuses to a repository host
+type Reporter interface {
+	Report(context.Context, Status) error
+}
+
+var shaRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// isSHA returns whether the reference is a full commit SHA
+func isSHA(ref string) bool {
+	return shaRegexp.MatchString(ref)
+}
+
+// scpLikeRegexp matches repository URLs of the form user@host:path
+var scpLikeRegexp = regexp.MustCompile("^(?:[^@/]+@)?([^:/]+):(.+)$")
+
+// parseRepository returns the host and the path, without any leading slash or
+// trailing .git suffix, of a repository URL
+func parseRepository(repository string) (string, string, error) {
+	var host, path string
+	if u, err := url.Parse(repository); err == nil && u.Scheme != "" && u.Host != "" {
+		host, path = u.Hostname(), u.Path
+	} else if match := scpLikeRegexp.FindStringSubmatch(repository); match != nil {
+		host, path = match[1], match[2]
+	} else {
+		return "", "", fmt.Errorf("unable to parse repository URL %q", repository)
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if path == "" {
+		return "", "", fmt.Errorf("repository URL %q has no path", repository)
+	}
+	return host, path, nil
+}
+
+// truncate shortens s to at most max characters
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
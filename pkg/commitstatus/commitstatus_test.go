@@ -0,0 +1,60 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitstatus
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestCommitStatus(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "CommitStatus Suite", reporters.Reporters())
+}
+
+var _ = Describe("CommitStatus Suite", func() {
+	Context("parseRepository", func() {
+		for repository, path := range map[string]string{
+			"https://github.com/pusher/faros.git":       "pusher/faros",
+			"https://github.com/pusher/faros":           "pusher/faros",
+			"ssh://git@github.com:22/pusher/faros.git":  "pusher/faros",
+			"git@github.com:pusher/faros.git":           "pusher/faros",
+			"https://github.com/pusher/infra/faros.git": "pusher/infra/faros",
+		} {
+			repository, path := repository, path
+			It("parses "+repository, func() {
+				h, p, err := parseRepository(repository)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(h).To(Equal("github.com"))
+				Expect(p).To(Equal(path))
+			})
+		}
+
+		It("fails to parse a URL without a path", func() {
+			_, _, err := parseRepository("https://github.com/")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("truncates long descriptions", func() {
+		Expect(truncate("abcdef", 10)).To(Equal("abcdef"))
+		Expect(truncate("abcdefghijkl", 10)).To(Equal("abcdefg..."))
+	})
+})
@@ -0,0 +1,130 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitstatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// GitHubAPIURL is the URL of the public GitHub API
+	GitHubAPIURL = "https://api.github.com"
+
+	// maxGitHubDescription is the maximum length of a GitHub status description
+	maxGitHubDescription = 140
+)
+
+// GitHub reports commit statuses to the GitHub statuses API
+type GitHub struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewGitHub creates a Reporter for the GitHub API at apiURL, defaulting to
+// the public API, authenticated with token
+func NewGitHub(apiURL, token string) *GitHub {
+	if apiURL == "" {
+		apiURL = GitHubAPIURL
+	}
+	return &GitHub{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Report sets the status on the commit the reference points to. References
+// that are not commit SHAs are resolved through the API, so the status is set
+// on the commit the reference points to at the time of reporting.
+func (g *GitHub) Report(ctx context.Context, status Status) error {
+	_, repo, err := parseRepository(status.Repository)
+	if err != nil {
+		return err
+	}
+
+	sha := status.Reference
+	if !isSHA(sha) {
+		sha, err = g.resolve(ctx, repo, status.Reference)
+		if err != nil {
+			return fmt.Errorf("unable to resolve reference %s: %v", status.Reference, err)
+		}
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"state":       string(status.State),
+		"description": truncate(status.Description, maxGitHubDescription),
+		"context":     status.Context,
+		"target_url":  status.TargetURL,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to marshal status: %v", err)
+	}
+	_, err = g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/statuses/%s", repo, sha), "application/vnd.github.v3+json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to set status on %s: %v", sha, err)
+	}
+	return nil
+}
+
+// resolve returns the SHA of the commit the reference points to
+func (g *GitHub) resolve(ctx context.Context, repo, ref string) (string, error) {
+	sha, err := g.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/commits/%s", repo, ref), "application/vnd.github.v3.sha", nil)
+	if err != nil {
+		return "", err
+	}
+	sha = strings.TrimSpace(sha)
+	if !isSHA(sha) {
+		return "", fmt.Errorf("unexpected commit SHA %q", sha)
+	}
+	return sha, nil
+}
+
+// do performs a request against the API and returns the response body
+func (g *GitHub) do(ctx context.Context, method, path, accept string, body io.Reader) (string, error) {
+	req, err := http.NewRequest(method, g.apiURL+path, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Content-Type", "application/json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "token "+g.token)
+	}
+
+	resp, err := g.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return string(data), nil
+}
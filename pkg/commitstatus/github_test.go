@@ -0,0 +1,100 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitHub Suite", func() {
+	const sha = "a14443638218c782b84cae56a14f1090ee9e5c9c"
+
+	var server *httptest.Server
+	var statuses map[string]map[string]string
+	var reporter *GitHub
+	var status Status
+
+	BeforeEach(func() {
+		statuses = make(map[string]map[string]string)
+		mux := http.NewServeMux()
+		mux.HandleFunc("/repos/pusher/faros/commits/master", func(w http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			Expect(req.Header.Get("Accept")).To(Equal("application/vnd.github.v3.sha"))
+			fmt.Fprint(w, sha)
+		})
+		mux.HandleFunc("/repos/pusher/faros/statuses/", func(w http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			if req.Header.Get("Authorization") != "token secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			Expect(req.Method).To(Equal(http.MethodPost))
+			body := make(map[string]string)
+			Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+			statuses[req.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+		})
+		server = httptest.NewServer(mux)
+
+		reporter = NewGitHub(server.URL+"/", "secret")
+		status = Status{
+			Repository:  "git@github.com:pusher/faros.git",
+			Reference:   "master",
+			State:       StateSuccess,
+			Description: "Synced 3 objects",
+			Context:     "faros/default/example",
+			TargetURL:   "https://example.com",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("resolves the reference and sets the status on the commit", func() {
+		Expect(reporter.Report(context.TODO(), status)).To(Succeed())
+		Expect(statuses).To(HaveKeyWithValue("/repos/pusher/faros/statuses/"+sha, map[string]string{
+			"state":       "success",
+			"description": "Synced 3 objects",
+			"context":     "faros/default/example",
+			"target_url":  "https://example.com",
+		}))
+	})
+
+	It("does not resolve references that are commit SHAs", func() {
+		status.Reference = "b17c0e0f45beca3f1c1e62a7f49fecb738c60d42"
+		Expect(reporter.Report(context.TODO(), status)).To(Succeed())
+		Expect(statuses).To(HaveKey("/repos/pusher/faros/statuses/" + status.Reference))
+	})
+
+	It("returns an error when the reference cannot be resolved", func() {
+		status.Reference = "missing"
+		Expect(reporter.Report(context.TODO(), status)).To(MatchError(ContainSubstring("unable to resolve reference missing")))
+	})
+
+	It("returns an error when the status is rejected", func() {
+		reporter = NewGitHub(server.URL, "invalid")
+		Expect(reporter.Report(context.TODO(), status)).To(MatchError(ContainSubstring("401")))
+	})
+})
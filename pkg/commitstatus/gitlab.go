@@ -0,0 +1,143 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxGitLabDescription is the maximum length of a GitLab status description
+const maxGitLabDescription = 255
+
+// gitLabStates maps commit status states to GitLab's
+var gitLabStates = map[State]string{
+	StateSuccess: "success",
+	StateFailure: "failed",
+	StatePending: "pending",
+}
+
+// GitLab reports commit statuses to the GitLab commit status API
+type GitLab struct {
+	apiURL string
+	token  string
+	client *http.Client
+}
+
+// NewGitLab creates a Reporter for the GitLab API at apiURL, authenticated
+// with token. If apiURL is empty, the API of the host of each repository is
+// used.
+func NewGitLab(apiURL, token string) *GitLab {
+	return &GitLab{
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Report sets the status on the commit the reference points to. References
+// that are not commit SHAs are resolved through the API, so the status is set
+// on the commit the reference points to at the time of reporting.
+func (g *GitLab) Report(ctx context.Context, status Status) error {
+	host, project, err := parseRepository(status.Repository)
+	if err != nil {
+		return err
+	}
+	apiURL := g.apiURL
+	if apiURL == "" {
+		apiURL = fmt.Sprintf("https://%s/api/v4", host)
+	}
+	projectURL := fmt.Sprintf("%s/projects/%s", apiURL, url.PathEscape(project))
+
+	sha := status.Reference
+	if !isSHA(sha) {
+		sha, err = g.resolve(ctx, projectURL, status.Reference)
+		if err != nil {
+			return fmt.Errorf("unable to resolve reference %s: %v", status.Reference, err)
+		}
+	}
+
+	state, ok := gitLabStates[status.State]
+	if !ok {
+		return fmt.Errorf("unknown state %q", status.State)
+	}
+	params := url.Values{}
+	params.Set("state", state)
+	params.Set("name", status.Context)
+	params.Set("description", truncate(status.Description, maxGitLabDescription))
+	if status.TargetURL != "" {
+		params.Set("target_url", status.TargetURL)
+	}
+	_, err = g.do(ctx, http.MethodPost, fmt.Sprintf("%s/statuses/%s", projectURL, sha), strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("unable to set status on %s: %v", sha, err)
+	}
+	return nil
+}
+
+// resolve returns the SHA of the commit the reference points to
+func (g *GitLab) resolve(ctx context.Context, projectURL, ref string) (string, error) {
+	data, err := g.do(ctx, http.MethodGet, fmt.Sprintf("%s/repository/commits/%s", projectURL, url.PathEscape(ref)), nil)
+	if err != nil {
+		return "", err
+	}
+	commit := struct {
+		ID string `json:"id"`
+	}{}
+	if err := json.Unmarshal(data, &commit); err != nil {
+		return "", fmt.Errorf("unable to unmarshal commit: %v", err)
+	}
+	if !isSHA(commit.ID) {
+		return "", fmt.Errorf("unexpected commit SHA %q", commit.ID)
+	}
+	return commit.ID, nil
+}
+
+// do performs a request against the API and returns the response body
+func (g *GitLab) do(ctx context.Context, method, endpoint string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if g.token != "" {
+		req.Header.Set("Private-Token", g.token)
+	}
+
+	resp, err := g.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return data, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commitstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitLab Suite", func() {
+	const sha = "a14443638218c782b84cae56a14f1090ee9e5c9c"
+
+	var server *httptest.Server
+	var statuses map[string]url.Values
+	var reporter *GitLab
+	var status Status
+
+	BeforeEach(func() {
+		statuses = make(map[string]url.Values)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			if req.Header.Get("Private-Token") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			switch req.URL.EscapedPath() {
+			case "/api/v4/projects/pusher%2Ffaros/repository/commits/master":
+				fmt.Fprintf(w, `{"id":"%s"}`, sha)
+			case "/api/v4/projects/pusher%2Ffaros/statuses/" + sha:
+				Expect(req.Method).To(Equal(http.MethodPost))
+				Expect(req.ParseForm()).To(Succeed())
+				statuses[sha] = req.PostForm
+				w.WriteHeader(http.StatusCreated)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+
+		reporter = NewGitLab(server.URL+"/api/v4", "secret")
+		status = Status{
+			Repository:  "git@gitlab.com:pusher/faros.git",
+			Reference:   "master",
+			State:       StateFailure,
+			Description: "Failed to apply",
+			Context:     "faros/default/example",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("resolves the reference and sets the status on the commit", func() {
+		Expect(reporter.Report(context.TODO(), status)).To(Succeed())
+		Expect(statuses).To(HaveKey(sha))
+		Expect(statuses[sha].Get("state")).To(Equal("failed"))
+		Expect(statuses[sha].Get("name")).To(Equal("faros/default/example"))
+		Expect(statuses[sha].Get("description")).To(Equal("Failed to apply"))
+		Expect(statuses[sha]).NotTo(HaveKey("target_url"))
+	})
+
+	It("returns an error when the reference cannot be resolved", func() {
+		status.Reference = "missing"
+		Expect(reporter.Report(context.TODO(), status)).To(MatchError(ContainSubstring("unable to resolve reference missing")))
+	})
+
+	It("returns an error when the status is rejected", func() {
+		reporter = NewGitLab(server.URL+"/api/v4", "invalid")
+		Expect(reporter.Report(context.TODO(), status)).To(MatchError(ContainSubstring("401")))
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/notifications"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// alertMatches returns whether the FarosAlert routes the notification for the
+// GitTrack
+func alertMatches(alert *farosv1alpha1.FarosAlert, gt *farosv1alpha1.GitTrack, n notifications.Notification) (bool, error) {
+	if alert.Spec.Suspend {
+		return false, nil
+	}
+	if alert.Spec.Severity == farosv1alpha1.AlertSeverityError && n.Severity() != notifications.SeverityError {
+		return false, nil
+	}
+	if alert.Spec.GitTrackSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(alert.Spec.GitTrackSelector)
+	if err != nil {
+		return false, fmt.Errorf("invalid GitTrack selector in FarosAlert %s: %v", alert.GetName(), err)
+	}
+	return selector.Matches(labels.Set(gt.GetLabels())), nil
+}
+
+// alertNotifiers returns a Notifier for the provider of each FarosAlert in
+// the namespace of the GitTrack that routes the notification. Alerts that
+// cannot be resolved are reported in the error and skipped.
+func (r *ReconcileGitTrack) alertNotifiers(gt *farosv1alpha1.GitTrack, n notifications.Notification) ([]notifications.Notifier, error) {
+	alerts := &farosv1alpha1.FarosAlertList{}
+	err := r.List(context.TODO(), alerts, client.InNamespace(gt.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list FarosAlerts: %v", err)
+	}
+
+	var notifiers []notifications.Notifier
+	var errs []string
+	for i := range alerts.Items {
+		alert := &alerts.Items[i]
+		matches, err := alertMatches(alert, gt, n)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		notifier, err := r.providerNotifier(gt.Namespace, alert.Spec.ProviderRef)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("FarosAlert %s: %v", alert.GetName(), err))
+			continue
+		}
+		notifiers = append(notifiers, notifier)
+	}
+
+	if len(errs) > 0 {
+		return notifiers, fmt.Errorf("unable to route notification: %s", strings.Join(errs, ", "))
+	}
+	return notifiers, nil
+}
+
+// providerNotifier creates a Notifier for the named FarosProvider
+func (r *ReconcileGitTrack) providerNotifier(namespace, name string) (notifications.Notifier, error) {
+	provider := &farosv1alpha1.FarosProvider{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, provider)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get FarosProvider %s: %v", name, err)
+	}
+
+	address, token := provider.Spec.Address, ""
+	if provider.Spec.SecretName != "" {
+		secret := &apiv1.Secret{}
+		err = r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: provider.Spec.SecretName}, secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up secret %s: %v", provider.Spec.SecretName, err)
+		}
+		if data, ok := secret.Data["address"]; ok {
+			address = strings.TrimSpace(string(data))
+		}
+		token = strings.TrimSpace(string(secret.Data["token"]))
+	}
+
+	switch provider.Spec.Type {
+	case farosv1alpha1.ProviderTypePagerDuty, farosv1alpha1.ProviderTypeOpsgenie:
+		if token == "" {
+			return nil, fmt.Errorf("FarosProvider %s has no token", name)
+		}
+	default:
+		if address == "" {
+			return nil, fmt.Errorf("FarosProvider %s has no address", name)
+		}
+	}
+
+	switch provider.Spec.Type {
+	case farosv1alpha1.ProviderTypeSlack:
+		return &notifications.Slack{URL: address}, nil
+	case farosv1alpha1.ProviderTypeTeams:
+		return &notifications.Teams{URL: address}, nil
+	case farosv1alpha1.ProviderTypeWebhook:
+		return &notifications.Webhook{URL: address}, nil
+	case farosv1alpha1.ProviderTypePagerDuty:
+		return &notifications.PagerDuty{URL: address, RoutingKey: token}, nil
+	case farosv1alpha1.ProviderTypeOpsgenie:
+		return &notifications.Opsgenie{URL: address, APIKey: token}, nil
+	default:
+		return nil, fmt.Errorf("FarosProvider %s has unknown type %q", name, provider.Spec.Type)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// approvePlanAnnotation is set on a GitTrack to the ID of its pending plan
+// to approve it
+const approvePlanAnnotation = "faros.pusher.com/approve-plan"
+
+// holdForApproval plans the changes a sync would make when the GitTrack
+// requires approval and, until the plan is approved, reports it in the status
+// instead of making the changes. It returns whether the changes were held
+// back.
+func (r *ReconcileGitTrack) holdForApproval(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, existing map[string]farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) (bool, error) {
+	if !gt.Spec.RequireApproval {
+		return false, nil
+	}
+
+	// Plan into a copy so that nothing is counted twice if the sync goes ahead
+	held := opts.copy()
+	changes, err := r.planChanges(gt, objects, objectFiles, existing, held)
+	if err != nil {
+		return false, fmt.Errorf("failed to plan changes: %v", err)
+	}
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	plan := newPendingPlan(opts.reference, changes)
+	if gt.GetAnnotations()[approvePlanAnnotation] == plan.ID {
+		r.log.V(0).Info("Plan approved", "plan", plan.ID)
+		return false, nil
+	}
+
+	*opts = *held
+	opts.plan = plan
+	opts.upToDateError = fmt.Errorf("%d changes require approval, set the %s annotation to %q", len(changes), approvePlanAnnotation, plan.ID)
+	opts.upToDateReason = gittrackutils.ApprovalPending
+	opts.gcReason = gittrackutils.GCSkippedApproval
+	opts.childrenOutOfSync = changeStatuses(changes)
+	if gt.Status.PendingPlan == nil || gt.Status.PendingPlan.ID != plan.ID {
+		r.recorder.Eventf(gt, apiv1.EventTypeNormal, "ApprovalRequired", "%d changes to '%s' require approval of plan %s", len(changes), opts.reference, plan.ID)
+	}
+	return true, nil
+}
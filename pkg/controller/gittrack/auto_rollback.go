@@ -0,0 +1,112 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// trackHealthyRevision records the revision of the synced commit as the last
+// healthy revision once all of the children are healthy
+func (r *ReconcileGitTrack) trackHealthyRevision(gt *farosv1alpha1.GitTrack, opts *statusOpts) error {
+	if gt.Spec.AutoRollback == nil || opts.rollback != nil || opts.healthError != nil || opts.upToDateError != nil {
+		return nil
+	}
+	if opts.lastHealthy != nil && opts.lastHealthy.Commit == opts.reference {
+		return nil
+	}
+
+	// The revision is recorded after the sync, so may not exist yet
+	revision, err := r.latestRevision(gt, opts.reference)
+	if err != nil || revision == nil || revision.Spec.Commit == "" {
+		return err
+	}
+	opts.lastHealthy = &farosv1alpha1.GitTrackRollback{Revision: revision.Name, Commit: revision.Spec.Commit}
+	return nil
+}
+
+// checkAutoRollback rolls the GitTrack back to its last healthy revision once
+// the children have been unhealthy for longer than the timeout since the
+// current commit was synced. Until then it returns when to check again.
+func (r *ReconcileGitTrack) checkAutoRollback(gt *farosv1alpha1.GitTrack, opts *statusOpts) (time.Duration, error) {
+	if gt.Spec.AutoRollback == nil || gt.Spec.RollbackTo != "" || opts.healthError == nil {
+		return 0, nil
+	}
+	last := opts.lastHealthy
+	if last == nil || last.Commit == opts.reference {
+		return 0, nil
+	}
+	timeout := gt.Spec.AutoRollback.Timeout.Duration
+
+	// The timeout starts when the children became unhealthy, or when the
+	// current commit was synced if they were already unhealthy before
+	cond := gittrackutils.GetGitTrackCondition(gt.Status, farosv1alpha1.ChildrenHealthyType)
+	if cond == nil || cond.Status != apiv1.ConditionFalse {
+		return timeout, nil
+	}
+	since := cond.LastTransitionTime.Time
+	revision, err := r.latestRevision(gt, opts.reference)
+	if err != nil {
+		return 0, err
+	}
+	if revision != nil && revision.Spec.SyncTime.After(since) {
+		since = revision.Spec.SyncTime.Time
+	}
+	if elapsed := time.Since(since); elapsed < timeout {
+		return timeout - elapsed, nil
+	}
+
+	reason := fmt.Sprintf("children unhealthy for more than %s after syncing '%s'", timeout, opts.reference)
+	gt.Spec.RollbackTo = last.Revision
+	if err := r.Update(context.TODO(), gt); err != nil {
+		return 0, fmt.Errorf("failed to roll back to revision '%s': %v", last.Revision, err)
+	}
+	opts.autoRollback = &farosv1alpha1.GitTrackRollback{Revision: last.Revision, Commit: last.Commit, Reason: reason}
+	r.log.V(0).Info("Rolled back", "revision", last.Revision, "reason", reason)
+	r.recorder.Eventf(gt, apiv1.EventTypeWarning, "AutoRollback", "Rolled back to revision '%s' at '%s': %s", last.Revision, last.Commit, reason)
+	return 0, nil
+}
+
+// latestRevision returns the newest GitTrackRevision of the GitTrack for the
+// reference, or nil if there is none
+func (r *ReconcileGitTrack) latestRevision(gt *farosv1alpha1.GitTrack, reference string) (*farosv1alpha1.GitTrackRevision, error) {
+	list := &farosv1alpha1.GitTrackRevisionList{}
+	err := r.List(context.TODO(), list, client.InNamespace(gt.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GitTrackRevisions: %v", err)
+	}
+
+	var latest *farosv1alpha1.GitTrackRevision
+	for i := range list.Items {
+		revision := &list.Items[i]
+		if revision.Spec.Reference != reference || !metav1.IsControlledBy(revision, gt) {
+			continue
+		}
+		if latest == nil || latest.Spec.SyncTime.Before(&revision.Spec.SyncTime) {
+			latest = revision
+		}
+	}
+	return latest, nil
+}
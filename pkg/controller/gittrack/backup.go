@@ -0,0 +1,112 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/backup"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// manifest is a rendered object and the file it was read from
+type manifest struct {
+	file   string
+	object *unstructured.Unstructured
+}
+
+// renderManifests returns the objects as a multi-document YAML file, each
+// document preceded by the file it was read from. Documents are ordered by
+// file and object so that the same manifests always render the same.
+func renderManifests(manifests []manifest) ([]byte, error) {
+	sorted := append([]manifest{}, manifests...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].file != sorted[j].file {
+			return sorted[i].file < sorted[j].file
+		}
+		return objectName(sorted[i].object) < objectName(sorted[j].object)
+	})
+
+	var buf bytes.Buffer
+	for _, m := range sorted {
+		data, err := yaml.Marshal(m.object.Object)
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal %s: %v", objectName(m.object), err)
+		}
+		fmt.Fprintf(&buf, "---\n# Source: %s\n", m.file)
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// objectName returns the kind, namespace and name identifying the object
+func objectName(u *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", u.GetKind(), u.GetNamespace(), u.GetName())
+}
+
+// backupID returns the identifier of the backup of the manifests: the commit
+// synced, or the hash of the manifests when the reference is not a commit
+func backupID(reference string, data []byte) string {
+	if commitSHA.MatchString(reference) {
+		return reference
+	}
+	return fmt.Sprintf("sha256-%x", sha256.Sum256(data))
+}
+
+// backupManifests writes the manifests of a successful sync to the backup
+// store, if one is configured. Each set of manifests is only written once per
+// GitTrack so that unchanged syncs do not rewrite the backup.
+func (r *ReconcileGitTrack) backupManifests(gt *farosv1alpha1.GitTrack, sOpts *statusOpts, rOpts *revisionOpts) error {
+	if r.backups == nil || !sOpts.synced() {
+		return nil
+	}
+
+	data, err := renderManifests(rOpts.manifests)
+	if err != nil {
+		return fmt.Errorf("unable to back up manifests: %v", err)
+	}
+	reference := syncReference(gt, sOpts.rollback)
+	key := backup.Key(farosflags.ClusterName, gt.GetNamespace(), gt.GetName(), backupID(reference, data))
+	name := fmt.Sprintf("%s/%s", gt.GetNamespace(), gt.GetName())
+	r.mutex.RLock()
+	last := r.lastBackups[name]
+	r.mutex.RUnlock()
+	if last == key {
+		return nil
+	}
+
+	err = r.backups.Put(context.TODO(), key, data)
+	if err != nil {
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "BackupFailed", "Failed to back up manifests for '%s': %v", reference, err)
+		return fmt.Errorf("unable to back up manifests: %v", err)
+	}
+
+	r.mutex.Lock()
+	r.lastBackups[name] = key
+	r.mutex.Unlock()
+	r.recorder.Eventf(gt, apiv1.EventTypeNormal, "BackupWritten", "Backed up manifests for '%s' to '%s'", reference, key)
+	r.log.V(1).Info("Manifests backed up", "key", key)
+	return nil
+}
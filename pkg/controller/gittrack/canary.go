@@ -0,0 +1,210 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// canaryRollout is the part of a sync that is applied while changes are
+// rolled out to the canary children
+type canaryRollout struct {
+	// objects are applied while the rest of the changes are held back
+	objects []*unstructured.Unstructured
+	// held are the changes to the rest of the children
+	held []plannedChange
+	// started is set when this sync rolls changes out to the canary children
+	started bool
+	// failed describes the canary children that failed to become healthy
+	failed []string
+	// waiting describes the canary children that are not healthy yet
+	waiting []string
+}
+
+// planCanary works out which changes are rolled out to the canary children
+// of the GitTrack first. It returns nil when the changes can be applied to
+// all children, either because there is nothing to hold back or because the
+// canary children are healthy.
+func (r *ReconcileGitTrack) planCanary(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, existing map[string]farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) (*canaryRollout, error) {
+	// Nothing is applied in dry-run mode, so the canary would never finish
+	if gt.Spec.Canary == nil || farosflags.DryRun {
+		return nil, nil
+	}
+	selector, err := canarySelector(gt.Spec.Canary)
+	if err != nil {
+		return nil, err
+	}
+
+	// Plan into a copy as the children are counted by the sync itself
+	changes, err := r.planChanges(gt, objects, objectFiles, existing, opts.copy())
+	if err != nil {
+		return nil, fmt.Errorf("failed to plan changes: %v", err)
+	}
+	changed := make(map[string]plannedChange, len(changes))
+	for _, change := range changes {
+		changed[change.name] = change
+	}
+
+	rollout := &canaryRollout{}
+	canaries := []string{}
+	for _, u := range objects {
+		gto, err := r.newGitTrackObjectInterface(u)
+		if err != nil {
+			return nil, err
+		}
+		name := gto.GetNamespacedName()
+		change, ok := changed[name]
+		switch {
+		case isCanary(gt.Spec.Canary, selector, u):
+			canaries = append(canaries, name)
+			rollout.objects = append(rollout.objects, u)
+			rollout.started = rollout.started || ok
+		case ok:
+			rollout.held = append(rollout.held, change)
+		default:
+			rollout.objects = append(rollout.objects, u)
+		}
+	}
+	// Children are only deleted once the changes have been promoted
+	for _, change := range changes {
+		if change.action == actionDelete {
+			rollout.held = append(rollout.held, change)
+		}
+	}
+	if len(rollout.held) == 0 || len(canaries) == 0 {
+		return nil, nil
+	}
+	if rollout.started {
+		return rollout, nil
+	}
+
+	for _, name := range canaries {
+		found, ok := existing[name]
+		if !ok {
+			// Ignored children are never created so cannot gate the rollout
+			continue
+		}
+		healthy, failed, health := canaryHealth(found)
+		switch {
+		case failed:
+			rollout.failed = append(rollout.failed, fmt.Sprintf("%s: %s", name, health))
+		case !healthy:
+			rollout.waiting = append(rollout.waiting, fmt.Sprintf("%s: %s", name, health))
+		}
+	}
+	if len(rollout.failed) == 0 && len(rollout.waiting) == 0 {
+		return nil, nil
+	}
+	sort.Strings(rollout.failed)
+	sort.Strings(rollout.waiting)
+	return rollout, nil
+}
+
+// canarySelector converts the label selector of the canary to a selector,
+// which matches nothing if none was given
+func canarySelector(canary *farosv1alpha1.GitTrackCanary) (labels.Selector, error) {
+	if canary.Selector == nil {
+		return labels.Nothing(), nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(canary.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid canary selector: %v", err)
+	}
+	return selector, nil
+}
+
+// isCanary returns whether the manifest is for a canary child
+func isCanary(canary *farosv1alpha1.GitTrackCanary, selector labels.Selector, u *unstructured.Unstructured) bool {
+	if u.GetNamespace() != "" {
+		for _, ns := range canary.Namespaces {
+			if u.GetNamespace() == ns {
+				return true
+			}
+		}
+	}
+	return selector.Matches(labels.Set(u.GetLabels()))
+}
+
+// canaryHealth returns whether the canary child's resource has reached its
+// desired state since its last update or has failed to and, if not healthy,
+// a description of its health
+func canaryHealth(child farosv1alpha1.GitTrackObjectInterface) (healthy bool, failed bool, health string) {
+	if child.GetStatus().ObservedGeneration != child.GetGeneration() {
+		return false, false, "update not observed yet"
+	}
+	for _, condition := range child.GetStatus().Conditions {
+		if condition.Type != farosv1alpha1.ChildHealthyType {
+			continue
+		}
+		// Health reported before the update says nothing about the update
+		if condition.ObservedGeneration != child.GetGeneration() {
+			return false, false, "health of update unknown"
+		}
+		if condition.Status == apiv1.ConditionTrue {
+			return true, false, ""
+		}
+		failed = condition.Reason == string(gittrackobjectutils.ChildFailed)
+		return false, failed, fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+	}
+	return false, false, "health unknown"
+}
+
+// holdForCanary reports the changes that are held back until the canary
+// children are healthy, aborting the rollout if any of them failed
+func (r *ReconcileGitTrack) holdForCanary(gt *farosv1alpha1.GitTrack, rollout *canaryRollout, opts *statusOpts) {
+	opts.gcReason = gittrackutils.GCSkippedCanary
+	opts.childrenOutOfSync = boundChildStatuses(append(opts.childrenOutOfSync, changeStatuses(rollout.held)...))
+	// Errors applying the canary children take precedence
+	if opts.upToDateError != nil {
+		return
+	}
+
+	previous := gittrackutils.GetGitTrackCondition(gt.Status, farosv1alpha1.ChildrenUpToDateType)
+	switch {
+	case len(rollout.failed) > 0:
+		opts.upToDateError = fmt.Errorf("canary failed, %d changes aborted:\n%s", len(rollout.held), strings.Join(rollout.failed, ",\n"))
+		opts.upToDateReason = gittrackutils.CanaryFailed
+		if previous == nil || previous.Reason != string(gittrackutils.CanaryFailed) {
+			r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CanaryFailed", "Aborted rollout of '%s' as canary children failed", opts.reference)
+		}
+	case rollout.started:
+		opts.upToDateError = fmt.Errorf("rolled out to canary children, %d changes held back until they are healthy", len(rollout.held))
+		opts.upToDateReason = gittrackutils.CanaryInProgress
+		r.recorder.Eventf(gt, apiv1.EventTypeNormal, "CanaryStarted", "Rolling out '%s' to canary children", opts.reference)
+	default:
+		opts.upToDateError = fmt.Errorf("%d changes held back until canary children are healthy:\n%s", len(rollout.held), strings.Join(rollout.waiting, ",\n"))
+		opts.upToDateReason = gittrackutils.CanaryInProgress
+	}
+}
+
+// canaryPromoted returns whether the previous sync held back changes while
+// rolling them out to the canary children
+func canaryPromoted(gt *farosv1alpha1.GitTrack) bool {
+	cond := gittrackutils.GetGitTrackCondition(gt.Status, farosv1alpha1.ChildrenUpToDateType)
+	return gt.Spec.Canary != nil && cond != nil && cond.Reason == string(gittrackutils.CanaryInProgress)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Canary Suite", func() {
+	Context("canaryHealth", func() {
+		var gto *farosv1alpha1.GitTrackObject
+
+		var setHealth = func(status v1.ConditionStatus, reason string, generation int64) {
+			gto.Status.Conditions = []farosv1alpha1.GitTrackObjectCondition{
+				{
+					Type:               farosv1alpha1.ChildHealthyType,
+					Status:             status,
+					Reason:             reason,
+					ObservedGeneration: generation,
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			gto = &farosv1alpha1.GitTrackObject{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default", Generation: 2},
+				Status:     farosv1alpha1.GitTrackObjectStatus{ObservedGeneration: 2},
+			}
+		})
+
+		It("is healthy when the update is healthy", func() {
+			setHealth(v1.ConditionTrue, "ChildReady", 2)
+			healthy, failed, _ := canaryHealth(gto)
+			Expect(healthy).To(BeTrue())
+			Expect(failed).To(BeFalse())
+		})
+
+		It("has failed when the update failed", func() {
+			setHealth(v1.ConditionFalse, "ChildFailed", 2)
+			healthy, failed, _ := canaryHealth(gto)
+			Expect(healthy).To(BeFalse())
+			Expect(failed).To(BeTrue())
+		})
+
+		It("is waiting while the update is progressing", func() {
+			setHealth(v1.ConditionFalse, "ChildProgressing", 2)
+			healthy, failed, _ := canaryHealth(gto)
+			Expect(healthy).To(BeFalse())
+			Expect(failed).To(BeFalse())
+		})
+
+		It("is waiting while the health is from before the update", func() {
+			setHealth(v1.ConditionTrue, "ChildReady", 1)
+			healthy, failed, health := canaryHealth(gto)
+			Expect(healthy).To(BeFalse())
+			Expect(failed).To(BeFalse())
+			Expect(health).To(Equal("health of update unknown"))
+		})
+
+		It("is waiting while the update has not been observed", func() {
+			gto.Status.ObservedGeneration = 1
+			setHealth(v1.ConditionTrue, "ChildReady", 2)
+			healthy, _, health := canaryHealth(gto)
+			Expect(healthy).To(BeFalse())
+			Expect(health).To(Equal("update not observed yet"))
+		})
+	})
+})
@@ -0,0 +1,159 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/render"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// selectClusters returns the FarosClusters in the namespace of the GitTrack
+// selected by spec.clusterSelector, by name
+func (r *ReconcileGitTrack) selectClusters(gt *farosv1alpha1.GitTrack) (map[string]*farosv1alpha1.FarosCluster, error) {
+	selector, err := metav1.LabelSelectorAsSelector(gt.Spec.ClusterSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster selector: %v", err)
+	}
+
+	clusters := &farosv1alpha1.FarosClusterList{}
+	err = r.List(context.TODO(), clusters, client.InNamespace(gt.Namespace))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list FarosClusters: %v", err)
+	}
+
+	selected := make(map[string]*farosv1alpha1.FarosCluster)
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if selector.Matches(labels.Set(cluster.GetLabels())) {
+			selected[cluster.GetName()] = cluster
+		}
+	}
+	return selected, nil
+}
+
+// fanOutObjects copies each object for every cluster, recording the file each
+// copy was read from
+func fanOutObjects(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, clusters map[string]*farosv1alpha1.FarosCluster) ([]*unstructured.Unstructured, map[*unstructured.Unstructured]string) {
+	names := []string{}
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	copies := []*unstructured.Unstructured{}
+	copyFiles := make(map[*unstructured.Unstructured]string)
+	for _, u := range objects {
+		for _, name := range names {
+			c := render.ForCluster(u, name)
+			copies = append(copies, c)
+			copyFiles[c] = objectFiles[u]
+		}
+	}
+	return copies, copyFiles
+}
+
+// kubeConfigSecretRef returns the kubeconfig for the cluster the object is
+// applied to, or nil if it is applied to this cluster
+func (r *ReconcileGitTrack) kubeConfigSecretRef(u *unstructured.Unstructured, owner *farosv1alpha1.GitTrack) *farosv1alpha1.GitTrackSecretReference {
+	if name, ok := u.GetAnnotations()[render.ClusterAnnotation]; ok {
+		if cluster, ok := r.clusters[name]; ok {
+			return &cluster.Spec.KubeConfigSecretRef
+		}
+	}
+	return owner.Spec.KubeConfigSecretRef
+}
+
+// clusterStatuses aggregates the results of the children of each cluster
+type clusterStatuses map[string]*farosv1alpha1.GitTrackClusterStatus
+
+// newClusterStatuses returns an empty status for each of the clusters
+func newClusterStatuses(clusters map[string]*farosv1alpha1.FarosCluster) clusterStatuses {
+	statuses := make(clusterStatuses, len(clusters))
+	for name := range clusters {
+		statuses[name] = &farosv1alpha1.GitTrackClusterStatus{Name: name}
+	}
+	return statuses
+}
+
+// add counts the result towards the status of its cluster
+func (s clusterStatuses) add(res result) {
+	status, ok := s[res.Cluster]
+	if !ok || res.Ignored {
+		return
+	}
+	status.ObjectsApplied++
+	if res.InSync {
+		status.ObjectsInSync++
+	}
+	if !res.Healthy {
+		status.ObjectsUnhealthy++
+	}
+}
+
+// list returns the statuses sorted by cluster name
+func (s clusterStatuses) list() []farosv1alpha1.GitTrackClusterStatus {
+	if len(s) == 0 {
+		return nil
+	}
+	statuses := []farosv1alpha1.GitTrackClusterStatus{}
+	for _, status := range s {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// clusterRequests returns a function that enqueues the GitTracks selecting
+// a FarosCluster when it changes
+func clusterRequests(c client.Client, log logr.Logger) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		gts := &farosv1alpha1.GitTrackList{}
+		err := c.List(context.TODO(), gts, client.InNamespace(obj.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "unable to list GitTracks after FarosCluster changed")
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, gt := range gts.Items {
+			if gt.Spec.ClusterSelector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(gt.Spec.ClusterSelector)
+			if err != nil || !selector.Matches(labels.Set(obj.Meta.GetLabels())) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: gt.Namespace, Name: gt.Name},
+			})
+		}
+		return requests
+	}
+}
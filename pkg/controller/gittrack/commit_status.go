@@ -0,0 +1,144 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/commitstatus"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newCommitStatus returns the commit status describing the result of the sync
+// of the GitTrack
+func newCommitStatus(gt *farosv1alpha1.GitTrack, opts *statusOpts) commitstatus.Status {
+	status := commitstatus.Status{
+		Repository: gt.Spec.Repository,
+		Reference:  syncReference(gt, opts.rollback),
+		Context:    gt.Spec.CommitStatus.Context,
+		TargetURL:  gt.Spec.CommitStatus.TargetURL,
+	}
+	if status.Context == "" {
+		status.Context = fmt.Sprintf("faros/%s/%s", gt.GetNamespace(), gt.GetName())
+	}
+
+	if opts.changesPending() {
+		status.State = commitstatus.StatePending
+		status.Description = fmt.Sprintf("Waiting to sync: %v", opts.upToDateError)
+	} else if stage, err := failedStage(opts); err != nil {
+		status.State = commitstatus.StateFailure
+		status.Description = fmt.Sprintf("Failed to %s: %v", stage, err)
+	} else if !opts.synced() {
+		status.State = commitstatus.StateFailure
+		status.Description = "Failed to sync"
+	} else {
+		status.State = commitstatus.StateSuccess
+		status.Description = fmt.Sprintf("Synced %d objects, %d in sync", opts.discovered, opts.inSync)
+	}
+	return status
+}
+
+// reportCommitStatus reports the result of the sync to the repository host if
+// the GitTrack asks for it. Statuses are only reported when the sync changed
+// something or the result differs from the last status reported.
+func (r *ReconcileGitTrack) reportCommitStatus(gt *farosv1alpha1.GitTrack, sOpts *statusOpts, rOpts *revisionOpts) error {
+	// The commit is unknown if the repository could not be fetched
+	if gt.Spec.CommitStatus == nil || !sOpts.fetched() {
+		return nil
+	}
+
+	status := newCommitStatus(gt, sOpts)
+	key := fmt.Sprintf("%s/%s", gt.GetNamespace(), gt.GetName())
+	reported := strings.Join([]string{status.Reference, string(status.State), status.Description}, "/")
+	r.mutex.RLock()
+	last := r.commitStatuses[key]
+	r.mutex.RUnlock()
+	if last == reported && !rOpts.changed(gt) {
+		return nil
+	}
+
+	reporter, err := r.commitStatusReporter(gt)
+	if err != nil {
+		return fmt.Errorf("unable to report commit status: %v", err)
+	}
+	err = reporter.Report(context.TODO(), status)
+	if err != nil {
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CommitStatusFailed", "Failed to report commit status for '%s': %v", status.Reference, err)
+		return fmt.Errorf("unable to report commit status: %v", err)
+	}
+
+	r.mutex.Lock()
+	r.commitStatuses[key] = reported
+	r.mutex.Unlock()
+	r.log.V(1).Info("Commit status reported", "state", status.State)
+	return nil
+}
+
+// commitStatusReporter creates a Reporter for the provider configured in the
+// GitTrack
+func (r *ReconcileGitTrack) commitStatusReporter(gt *farosv1alpha1.GitTrack) (commitstatus.Reporter, error) {
+	token, err := r.commitStatusToken(gt)
+	if err != nil {
+		return nil, err
+	}
+
+	switch gt.Spec.CommitStatus.Provider {
+	case farosv1alpha1.CommitStatusProviderGitHub:
+		return commitstatus.NewGitHub(gt.Spec.CommitStatus.APIURL, token), nil
+	case farosv1alpha1.CommitStatusProviderGitLab:
+		return commitstatus.NewGitLab(gt.Spec.CommitStatus.APIURL, token), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", gt.Spec.CommitStatus.Provider)
+	}
+}
+
+// commitStatusToken fetches the API token used to report commit statuses,
+// falling back to the password of an HTTP basic auth deploy key
+func (r *ReconcileGitTrack) commitStatusToken(gt *farosv1alpha1.GitTrack) (string, error) {
+	ref := gt.Spec.CommitStatus.TokenSecret
+	if ref == nil {
+		deployKey := gittrackutils.DeployKey(gt, gt.Spec.SubPath, gt.Spec.Reference)
+		if deployKey.Type != farosv1alpha1.GitCredentialTypeHTTPBasicAuth {
+			return "", fmt.Errorf("no token secret set and the deploy key is not of type %s", farosv1alpha1.GitCredentialTypeHTTPBasicAuth)
+		}
+		creds, err := r.fetchGitCredentials(gt.GetNamespace(), deployKey)
+		if err != nil {
+			return "", err
+		}
+		split := strings.SplitN(string(creds.secret), ":", 2)
+		if len(split) != 2 {
+			return "", fmt.Errorf("deploy key must be of the form <username>:<password>")
+		}
+		return split[1], nil
+	}
+
+	secret := &apiv1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: gt.GetNamespace(), Name: ref.SecretName}, secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up secret %s: %v", ref.SecretName, err)
+	}
+	token, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s does not have key %s", ref.SecretName, ref.Key)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
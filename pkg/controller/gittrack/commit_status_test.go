@@ -0,0 +1,72 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/commitstatus"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Commit Status Suite", func() {
+	Context("newCommitStatus", func() {
+		var gt *farosv1alpha1.GitTrack
+		var opts *statusOpts
+
+		BeforeEach(func() {
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec: farosv1alpha1.GitTrackSpec{
+					Repository:   "https://github.com/pusher/faros",
+					Reference:    "master",
+					CommitStatus: &farosv1alpha1.GitTrackCommitStatus{Provider: farosv1alpha1.CommitStatusProviderGitHub},
+				},
+			}
+			opts = newStatusOpts()
+			opts.gitReason = gittrackutils.GitFetchSuccess
+			opts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
+			opts.gcReason = gittrackutils.GCSuccess
+			opts.discovered = 3
+			opts.inSync = 3
+		})
+
+		It("reports success when all children were synced", func() {
+			status := newCommitStatus(gt, opts)
+			Expect(status.State).To(Equal(commitstatus.StateSuccess))
+			Expect(status.Description).To(Equal("Synced 3 objects, 3 in sync"))
+		})
+
+		It("defaults the context to the GitTrack's name", func() {
+			Expect(newCommitStatus(gt, opts).Context).To(Equal("faros/default/example"))
+			gt.Spec.CommitStatus.Context = "deploy/production"
+			Expect(newCommitStatus(gt, opts).Context).To(Equal("deploy/production"))
+		})
+
+		It("reports failure with the error of the failed stage", func() {
+			opts.upToDateError = errors.New("unable to update child")
+			opts.upToDateReason = gittrackutils.ErrorUpdatingChildren
+			status := newCommitStatus(gt, opts)
+			Expect(status.State).To(Equal(commitstatus.StateFailure))
+			Expect(status.Description).To(Equal("Failed to apply: unable to update child"))
+		})
+	})
+})
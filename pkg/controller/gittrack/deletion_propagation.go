@@ -0,0 +1,76 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	utils "github.com/pusher/faros/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// childDeletionPropagation returns the propagation policy to delete the
+// (Cluster)GitTrackObject with when pruning it: the value of the
+// `faros.pusher.com/deletion-propagation` annotation of its child, or else the
+// policy of the GitTrack. The policy of the GitTrack is also used when the
+// data cannot be read, so that pruning is not blocked by missing data.
+//
+// Children to be orphaned are never deleted in the foreground, as the garbage
+// collector would delete them before the orphan finalizer of the
+// (Cluster)GitTrackObject removes their owner references.
+func (r *ReconcileGitTrack) childDeletionPropagation(owner *farosv1alpha1.GitTrack, gto farosv1alpha1.GitTrackObjectInterface) (metav1.DeletionPropagation, error) {
+	policy, err := r.childAnnotatedPropagation(gto)
+	if err != nil {
+		return "", err
+	}
+	if policy == "" {
+		policy = owner.Spec.DeletionPropagation
+	}
+	if policy == metav1.DeletePropagationForeground && utils.HasFinalizer(gto, gittrackobjectutils.OrphanFinalizer) {
+		policy = metav1.DeletePropagationBackground
+	}
+	return policy, nil
+}
+
+// childAnnotatedPropagation returns the first propagation policy set by the
+// `faros.pusher.com/deletion-propagation` annotation of the children in the
+// data of the (Cluster)GitTrackObject, or an empty policy if none is set or
+// the data cannot be read
+func (r *ReconcileGitTrack) childAnnotatedPropagation(gto farosv1alpha1.GitTrackObjectInterface) (metav1.DeletionPropagation, error) {
+	data, err := gittrackobjectutils.ReadData(r.apiReader, gto.GetSpec())
+	if err != nil {
+		r.log.Error(err, "unable to read data of child, using deletion propagation of GitTrack", "child", gto.GetNamespacedName())
+		return "", nil
+	}
+	children, err := utils.YAMLToUnstructuredSlice(data)
+	if err != nil {
+		r.log.Error(err, "unable to unmarshal data of child, using deletion propagation of GitTrack", "child", gto.GetNamespacedName())
+		return "", nil
+	}
+	for _, child := range children {
+		policy, err := gittrackobjectutils.GetDeletionPropagationPolicy(child)
+		if err != nil {
+			return "", fmt.Errorf("unable to get deletion propagation policy of child '%s': %v", gto.GetNamespacedName(), err)
+		}
+		if policy != nil {
+			return *policy, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	testutils "github.com/pusher/faros/test/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Deletion Propagation Suite", func() {
+	var r *ReconcileGitTrack
+	var owner *farosv1alpha1.GitTrack
+	var gto *farosv1alpha1.GitTrackObject
+
+	BeforeEach(func() {
+		r = &ReconcileGitTrack{log: rlogr.Log.WithName("deletion-propagation-test")}
+		owner = &farosv1alpha1.GitTrack{}
+		gto = testutils.ExampleGitTrackObject.DeepCopy()
+	})
+
+	setChildPropagation := func(policy string) {
+		child := testutils.ExampleDeployment.DeepCopy()
+		child.SetAnnotations(map[string]string{"faros.pusher.com/deletion-propagation": policy})
+		Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, child)).To(Succeed())
+	}
+
+	Context("childDeletionPropagation", func() {
+		It("uses no policy when neither the child nor the GitTrack set one", func() {
+			Expect(r.childDeletionPropagation(owner, gto)).To(BeEmpty())
+		})
+
+		It("uses the policy of the GitTrack", func() {
+			owner.Spec.DeletionPropagation = metav1.DeletePropagationBackground
+			Expect(r.childDeletionPropagation(owner, gto)).To(Equal(metav1.DeletePropagationBackground))
+		})
+
+		It("prefers the annotation of the child to the policy of the GitTrack", func() {
+			owner.Spec.DeletionPropagation = metav1.DeletePropagationBackground
+			setChildPropagation("Orphan")
+			Expect(r.childDeletionPropagation(owner, gto)).To(Equal(metav1.DeletePropagationOrphan))
+		})
+
+		It("uses the policy of the GitTrack when the data cannot be read", func() {
+			owner.Spec.DeletionPropagation = metav1.DeletePropagationBackground
+			gto.Spec.DataFrom = &farosv1alpha1.DataReference{Namespace: gto.GetNamespace(), Hash: "outdated"}
+			Expect(r.childDeletionPropagation(owner, gto)).To(Equal(metav1.DeletePropagationBackground))
+		})
+
+		It("returns an error for an invalid annotation", func() {
+			setChildPropagation("Sideways")
+			_, err := r.childDeletionPropagation(owner, gto)
+			Expect(err).To(HaveOccurred())
+		})
+
+		Context("when the GitTrackObject orphans its children", func() {
+			BeforeEach(func() {
+				gto.SetFinalizers([]string{gittrackobjectutils.OrphanFinalizer})
+			})
+
+			It("deletes in the background instead of the foreground set by the GitTrack", func() {
+				owner.Spec.DeletionPropagation = metav1.DeletePropagationForeground
+				Expect(r.childDeletionPropagation(owner, gto)).To(Equal(metav1.DeletePropagationBackground))
+			})
+
+			It("deletes in the background instead of the foreground set by the child", func() {
+				setChildPropagation("Foreground")
+				Expect(r.childDeletionPropagation(owner, gto)).To(Equal(metav1.DeletePropagationBackground))
+			})
+
+			It("keeps an orphan policy", func() {
+				setChildPropagation("Orphan")
+				Expect(r.childDeletionPropagation(owner, gto)).To(Equal(metav1.DeletePropagationOrphan))
+			})
+		})
+	})
+})
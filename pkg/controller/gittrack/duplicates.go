@@ -0,0 +1,117 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// definitionKey returns the group, kind, namespace and name identifying the
+// object a manifest defines. Versions are left out as every version of a
+// kind refers to the same object.
+func definitionKey(u *unstructured.Unstructured) string {
+	gk := u.GroupVersionKind().GroupKind()
+	return fmt.Sprintf("%s/%s/%s", gk.String(), u.GetNamespace(), u.GetName())
+}
+
+// definitionName returns the kind, namespace and name of the object a
+// manifest defines for use in messages
+func definitionName(u *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", u.GetKind(), strings.TrimLeft(fmt.Sprintf("%s/%s", u.GetNamespace(), u.GetName()), "/"))
+}
+
+// findDuplicates separates the objects defined more than once in the
+// repository from the rest. Which definition is applied would otherwise
+// depend on the order the files are read in, so none of them are. The reason
+// each file defining a duplicate is rejected is returned by file.
+func findDuplicates(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string) ([]*unstructured.Unstructured, []*unstructured.Unstructured, map[string]string) {
+	definitions := make(map[string][]*unstructured.Unstructured)
+	for _, u := range objects {
+		key := definitionKey(u)
+		definitions[key] = append(definitions[key], u)
+	}
+
+	unique := []*unstructured.Unstructured{}
+	duplicates := []*unstructured.Unstructured{}
+	reasons := make(map[string][]string)
+	for _, u := range objects {
+		defs := definitions[definitionKey(u)]
+		if len(defs) == 1 {
+			unique = append(unique, u)
+			continue
+		}
+		duplicates = append(duplicates, u)
+		if defs[0] != u {
+			continue
+		}
+
+		// Record the reason once against each of the files defining the object
+		files := []string{}
+		seen := make(map[string]bool)
+		for _, d := range defs {
+			if file := objectFiles[d]; !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+		sort.Strings(files)
+		reason := fmt.Sprintf("%s is defined %d times, in '%s'", definitionName(u), len(defs), strings.Join(files, "', '"))
+		for _, file := range files {
+			reasons[file] = append(reasons[file], reason)
+		}
+	}
+
+	fileErrors := make(map[string]string, len(reasons))
+	for file, rs := range reasons {
+		sort.Strings(rs)
+		fileErrors[file] = strings.Join(rs, "; ")
+	}
+	return unique, duplicates, fileErrors
+}
+
+// duplicateFileErrors converts the reasons the files defining duplicate
+// objects were rejected into file errors
+func duplicateFileErrors(errs map[string]string) []farosv1alpha1.GitTrackFileError {
+	fileErrors := []farosv1alpha1.GitTrackFileError{}
+	for path, msg := range errs {
+		fileErrors = append(fileErrors, farosv1alpha1.GitTrackFileError{
+			Path:    path,
+			Class:   farosv1alpha1.FileErrorClassDuplicateDefinition,
+			Message: msg,
+		})
+	}
+	return fileErrors
+}
+
+// keepDuplicates removes the (Cluster)GitTrackObjects of the duplicate
+// objects from existing so that they are neither updated nor pruned until the
+// duplicates are resolved
+func (r *ReconcileGitTrack) keepDuplicates(duplicates []*unstructured.Unstructured, existing map[string]farosv1alpha1.GitTrackObjectInterface) {
+	for _, u := range duplicates {
+		name := render.ObjectName(u)
+		if gto, err := r.newGitTrackObjectInterface(u); err == nil {
+			name = gto.GetNamespacedName()
+		}
+		delete(existing, name)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Duplicates Suite", func() {
+	Context("findDuplicates", func() {
+		var objectFiles map[*unstructured.Unstructured]string
+
+		newObject := func(apiVersion, kind, namespace, name, file string) *unstructured.Unstructured {
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion(apiVersion)
+			u.SetKind(kind)
+			u.SetNamespace(namespace)
+			u.SetName(name)
+			objectFiles[u] = file
+			return u
+		}
+
+		BeforeEach(func() {
+			objectFiles = make(map[*unstructured.Unstructured]string)
+		})
+
+		It("keeps objects that are only defined once", func() {
+			a := newObject("v1", "ConfigMap", "default", "a", "a.yaml")
+			b := newObject("v1", "ConfigMap", "other", "a", "b.yaml")
+			c := newObject("v1", "Secret", "default", "a", "b.yaml")
+			unique, duplicates, fileErrors := findDuplicates([]*unstructured.Unstructured{a, b, c}, objectFiles)
+			Expect(unique).To(Equal([]*unstructured.Unstructured{a, b, c}))
+			Expect(duplicates).To(BeEmpty())
+			Expect(fileErrors).To(BeEmpty())
+		})
+
+		It("rejects every definition of an object defined more than once", func() {
+			a := newObject("apps/v1", "Deployment", "default", "nginx", "b.yaml")
+			b := newObject("v1", "ConfigMap", "default", "nginx", "b.yaml")
+			c := newObject("apps/v1beta2", "Deployment", "default", "nginx", "a.yaml")
+			unique, duplicates, fileErrors := findDuplicates([]*unstructured.Unstructured{a, b, c}, objectFiles)
+			Expect(unique).To(Equal([]*unstructured.Unstructured{b}))
+			Expect(duplicates).To(Equal([]*unstructured.Unstructured{a, c}))
+			reason := "Deployment default/nginx is defined 2 times, in 'a.yaml', 'b.yaml'"
+			Expect(fileErrors).To(Equal(map[string]string{"a.yaml": reason, "b.yaml": reason}))
+		})
+
+		It("rejects objects defined more than once in the same file", func() {
+			a := newObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "admin", "a.yaml")
+			b := newObject("rbac.authorization.k8s.io/v1", "ClusterRole", "", "admin", "a.yaml")
+			unique, duplicates, fileErrors := findDuplicates([]*unstructured.Unstructured{a, b}, objectFiles)
+			Expect(unique).To(BeEmpty())
+			Expect(duplicates).To(HaveLen(2))
+			Expect(fileErrors).To(Equal(map[string]string{"a.yaml": "ClusterRole admin is defined 2 times, in 'a.yaml'"}))
+		})
+
+		It("lists every duplicate object defined in a file", func() {
+			newObject("v1", "ConfigMap", "default", "a", "a.yaml")
+			newObject("v1", "ConfigMap", "default", "a", "b.yaml")
+			newObject("v1", "Secret", "default", "a", "a.yaml")
+			newObject("v1", "Secret", "default", "a", "c.yaml")
+			objects := []*unstructured.Unstructured{}
+			for u := range objectFiles {
+				objects = append(objects, u)
+			}
+			_, _, fileErrors := findDuplicates(objects, objectFiles)
+			Expect(fileErrors).To(HaveKeyWithValue("a.yaml", "ConfigMap default/a is defined 2 times, in 'a.yaml', 'b.yaml'; Secret default/a is defined 2 times, in 'a.yaml', 'c.yaml'"))
+		})
+
+		It("converts the reasons into DuplicateDefinition file errors", func() {
+			Expect(duplicateFileErrors(map[string]string{"a.yaml": "defined twice"})).To(Equal([]farosv1alpha1.GitTrackFileError{
+				{Path: "a.yaml", Class: farosv1alpha1.FileErrorClassDuplicateDefinition, Message: "defined twice"},
+			}))
+		})
+	})
+})
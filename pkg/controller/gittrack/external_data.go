@@ -0,0 +1,184 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// dataChunkSize is the maximum size of the data held by each ConfigMap, which
+// leaves room for its base64 encoding below the etcd request size limit
+const dataChunkSize = 512 * 1024
+
+// dataConfigMapName returns the name of the ConfigMap holding the chunk of the
+// data of the (Cluster)GitTrackObject at the index. ConfigMaps are created in
+// the namespace of the GitTrack, so the name identifies both.
+func dataConfigMapName(owner *farosv1alpha1.GitTrack, gto farosv1alpha1.GitTrackObjectInterface, index int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s", owner.Name, gto.GetNamespacedName())))
+	return fmt.Sprintf("faros-data-%x-%d", sum[:8], index)
+}
+
+// splitData splits the data into chunks of at most size bytes
+func splitData(data []byte, size int) [][]byte {
+	chunks := [][]byte{}
+	for len(data) > size {
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	return append(chunks, data)
+}
+
+// storeData moves the data of the (Cluster)GitTrackObject into ConfigMaps
+// owned by the GitTrack when it is larger than the external data threshold,
+// even once compressed. The retain annotation of the manifest is copied to the
+// (Cluster)GitTrackObject so that the prune policy can be applied without
+// reading the ConfigMaps.
+func (r *ReconcileGitTrack) storeData(owner *farosv1alpha1.GitTrack, gto farosv1alpha1.GitTrackObjectInterface, u *unstructured.Unstructured) error {
+	spec := gto.GetSpec()
+	if farosflags.ExternalDataThreshold <= 0 || len(spec.Data) <= farosflags.ExternalDataThreshold {
+		return nil
+	}
+
+	ref := &farosv1alpha1.DataReference{
+		Namespace: owner.Namespace,
+		Hash:      gittrackobjectutils.DataHash(spec.Data),
+	}
+	for i, chunk := range splitData(spec.Data, dataChunkSize) {
+		name := dataConfigMapName(owner, gto, i)
+		err := r.storeDataChunk(owner, name, chunk)
+		if err != nil {
+			return fmt.Errorf("unable to store data in ConfigMap %s/%s: %v", owner.Namespace, name, err)
+		}
+		ref.ConfigMaps = append(ref.ConfigMaps, name)
+	}
+	spec.Data = []byte{}
+	spec.DataFrom = ref
+	gto.SetSpec(spec)
+
+	if u.GetAnnotations()[retainAnnotation] == "true" {
+		annotations := gto.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[retainAnnotation] = "true"
+		gto.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+// storeDataChunk creates or updates the ConfigMap holding the chunk of data
+func (r *ReconcileGitTrack) storeDataChunk(owner *farosv1alpha1.GitTrack, name string, chunk []byte) error {
+	cm := &apiv1.ConfigMap{}
+	err := r.apiReader.Get(context.TODO(), types.NamespacedName{Namespace: owner.Namespace, Name: name}, cm)
+	if err != nil && errors.IsNotFound(err) {
+		cm = &apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: owner.Namespace,
+				Labels:    map[string]string{gittrackutils.GitTrackLabel: owner.Name},
+			},
+			BinaryData: map[string][]byte{gittrackobjectutils.DataConfigMapKey: chunk},
+		}
+		err = controllerutil.SetControllerReference(owner, cm, r.scheme)
+		if err != nil {
+			return err
+		}
+		return r.Create(context.TODO(), cm)
+	} else if err != nil {
+		return err
+	}
+
+	if ref := metav1.GetControllerOf(cm); ref == nil || ref.UID != owner.UID {
+		return fmt.Errorf("ConfigMap is not owned by GitTrack %s", owner.Name)
+	}
+	if bytes.Equal(cm.BinaryData[gittrackobjectutils.DataConfigMapKey], chunk) {
+		return nil
+	}
+	cm.BinaryData = map[string][]byte{gittrackobjectutils.DataConfigMapKey: chunk}
+	return r.Update(context.TODO(), cm)
+}
+
+// removeStaleData deletes the ConfigMaps referenced by the previous version of
+// the (Cluster)GitTrackObject that are no longer referenced
+func (r *ReconcileGitTrack) removeStaleData(previous, current farosv1alpha1.GitTrackObjectInterface) error {
+	referenced := make(map[string]bool)
+	if dataFrom := current.GetSpec().DataFrom; dataFrom != nil {
+		for _, name := range dataFrom.ConfigMaps {
+			referenced[name] = true
+		}
+	}
+	dataFrom := previous.GetSpec().DataFrom
+	if dataFrom == nil {
+		return nil
+	}
+	for _, name := range dataFrom.ConfigMaps {
+		if referenced[name] {
+			continue
+		}
+		err := r.deleteDataConfigMap(dataFrom.Namespace, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteData deletes the ConfigMaps holding the data of the
+// (Cluster)GitTrackObject, if any
+func (r *ReconcileGitTrack) deleteData(gto farosv1alpha1.GitTrackObjectInterface) error {
+	dataFrom := gto.GetSpec().DataFrom
+	if dataFrom == nil {
+		return nil
+	}
+	for _, name := range dataFrom.ConfigMaps {
+		err := r.deleteDataConfigMap(dataFrom.Namespace, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteDataConfigMap deletes the ConfigMap, ignoring ConfigMaps that have
+// already been deleted
+func (r *ReconcileGitTrack) deleteDataConfigMap(namespace, name string) error {
+	cm := &apiv1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	err := r.Delete(context.TODO(), cm)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete ConfigMap %s/%s: %v", namespace, name, err)
+	}
+	return nil
+}
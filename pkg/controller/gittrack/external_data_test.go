@@ -0,0 +1,34 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("External Data Suite", func() {
+	Context("splitData", func() {
+		It("splits the data into chunks of at most the size", func() {
+			Expect(splitData([]byte("abcdefg"), 3)).To(Equal([][]byte{[]byte("abc"), []byte("def"), []byte("g")}))
+		})
+
+		It("returns the data in a single chunk when it fits", func() {
+			Expect(splitData([]byte("abc"), 3)).To(Equal([][]byte{[]byte("abc")}))
+		})
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	utils "github.com/pusher/faros/pkg/utils"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cleanupFinalizer is added to GitTracks so that their cluster scoped
+// children are deleted before the GitTrack is removed. Garbage collection
+// cannot be relied upon for these children as owner references across scopes
+// are not supported.
+const cleanupFinalizer = "faros.pusher.com/cleanup"
+
+// ensureFinalizer adds the cleanup finalizer to the GitTrack and returns
+// whether it was added
+func ensureFinalizer(gt *farosv1alpha1.GitTrack) bool {
+	if utils.HasFinalizer(gt, cleanupFinalizer) {
+		return false
+	}
+	gt.SetFinalizers(append(gt.GetFinalizers(), cleanupFinalizer))
+	return true
+}
+
+// handleDeletion deletes the cluster scoped children of the GitTrack, unless
+// its dependents are being orphaned, and then removes the cleanup finalizer
+func (r *ReconcileGitTrack) handleDeletion(gt *farosv1alpha1.GitTrack) error {
+	// Stop reporting the time since the last sync, it would otherwise grow
+	// until the controller restarts
+	deleteMetrics(gt)
+
+	if !utils.HasFinalizer(gt, cleanupFinalizer) {
+		return nil
+	}
+
+	if !utils.HasFinalizer(gt, metav1.FinalizerOrphanDependents) {
+		err := r.deleteClusterChildren(gt)
+		if err != nil {
+			r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CleanupFailed", "Failed to clean-up cluster scoped resources")
+			return fmt.Errorf("failed to clean-up cluster scoped children: %v", err)
+		}
+	}
+
+	gt.SetFinalizers(utils.RemoveFinalizer(gt.GetFinalizers(), cleanupFinalizer))
+	err := r.Update(context.TODO(), gt)
+	if err != nil {
+		return fmt.Errorf("unable to remove finalizer: %v", err)
+	}
+	return nil
+}
+
+// deleteClusterChildren deletes all ClusterGitTrackObjects controlled by the
+// GitTrack
+func (r *ReconcileGitTrack) deleteClusterChildren(gt *farosv1alpha1.GitTrack) error {
+	cgtos := &farosv1alpha1.ClusterGitTrackObjectList{}
+	err := r.List(context.TODO(), cgtos)
+	if err != nil {
+		return err
+	}
+	for i := range cgtos.Items {
+		cgto := &cgtos.Items[i]
+		if !metav1.IsControlledBy(cgto, gt) || cgto.GetDeletionTimestamp() != nil {
+			continue
+		}
+		err = r.Delete(context.TODO(), cgto)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete child for '%s': %v", cgto.GetName(), err)
+		}
+		r.log.V(0).Info("Child deleted", "child name", cgto.GetName())
+	}
+	return nil
+}
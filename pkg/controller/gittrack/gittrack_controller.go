@@ -19,14 +19,22 @@ package gittrack
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/backup"
 	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
 	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/gitwrite"
+	"github.com/pusher/faros/pkg/health"
+	"github.com/pusher/faros/pkg/notifications"
+	"github.com/pusher/faros/pkg/render"
+	"github.com/pusher/faros/pkg/shutdown"
 	utils "github.com/pusher/faros/pkg/utils"
 	farosclient "github.com/pusher/faros/pkg/utils/client"
 	gitstore "github.com/pusher/git-store"
@@ -35,6 +43,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -54,7 +63,15 @@ import (
 // and Start it when the Manager is Started.
 // USER ACTION REQUIRED: update cmd/manager/main.go to call this faros.Add(mgr) to install this Controller
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	r := newReconciler(mgr)
+	if err := add(mgr, r); err != nil {
+		return err
+	}
+
+	// GitTracks with High priority are reconciled by a second controller so
+	// that they have their own queue and workers
+	high := r.(*ReconcileGitTrack).withPriority(farosv1alpha1.GitTrackPriorityHigh)
+	return addWithPriority(mgr, high, farosv1alpha1.GitTrackPriorityHigh, farosflags.HighPriorityWorkers)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -70,35 +87,118 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		panic(fmt.Errorf("unable to parse ignored resources: %v", err))
 	}
 
+	ignoredResourcesConfigMap, err := farosflags.ParseIgnoredResourcesConfigMap()
+	if err != nil {
+		panic(fmt.Errorf("unable to parse ignored resources ConfigMap: %v", err))
+	}
+
+	allowedGVRs, err := farosflags.ParseAllowedResources()
+	if err != nil {
+		panic(fmt.Errorf("unable to parse allowed resources: %v", err))
+	}
+
+	selector, err := farosflags.ParseGitTrackSelector()
+	if err != nil {
+		panic(fmt.Errorf("unable to parse gittrack selector: %v", err))
+	}
+
 	applier, err := farosclient.NewApplier(mgr.GetConfig(), farosclient.Options{})
 	if err != nil {
 		panic(fmt.Errorf("unable to create applier: %v", err))
 	}
 
-	return &ReconcileGitTrack{
-		Client:          mgr.GetClient(),
-		scheme:          mgr.GetScheme(),
-		store:           gitstore.NewRepoStore(),
-		restMapper:      restMapper,
-		recorder:        mgr.GetEventRecorderFor("gittrack-controller"),
-		ignoredGVRs:     gvrs,
-		lastUpdateTimes: make(map[string]time.Time),
-		mutex:           &sync.RWMutex{},
-		applier:         applier,
-		log:             rlogr.Log.WithName("gittrack-controller"),
+	// Children are listed directly from the API server so that they can be
+	// fetched in pages
+	apiReader, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: restMapper})
+	if err != nil {
+		panic(fmt.Errorf("unable to create API client: %v", err))
 	}
+
+	// Notify the configured endpoints when a GitTrack fails to sync or recovers
+	notifier := notifications.New(notifications.Options{
+		SlackURLs:   farosflags.SlackWebhookURLs,
+		WebhookURLs: farosflags.NotificationWebhookURLs,
+	})
+
+	// Back up the manifests of each sync when a bucket is configured
+	var backups backup.Store
+	if farosflags.BackupURL != "" {
+		backups, err = backup.New(backup.Options{
+			URL:      farosflags.BackupURL,
+			Endpoint: farosflags.BackupEndpoint,
+			Region:   farosflags.BackupRegion,
+		})
+		if err != nil {
+			panic(fmt.Errorf("unable to create backup store: %v", err))
+		}
+	}
+
+	// Limit the rate of git operations against each host
+	var gitHostLimiter *hostLimiter
+	if farosflags.GitHostRate > 0 {
+		gitHostLimiter = newHostLimiter(farosflags.GitHostRate, farosflags.GitHostBurst)
+	}
+
+	r := &ReconcileGitTrack{
+		Client:                    mgr.GetClient(),
+		scheme:                    mgr.GetScheme(),
+		store:                     gitstore.NewRepoStore(),
+		restMapper:                restMapper,
+		recorder:                  mgr.GetEventRecorderFor("gittrack-controller"),
+		ignoredGVRs:               gvrs,
+		ignoredResourcesConfigMap: ignoredResourcesConfigMap,
+		allowedGVRs:               allowedGVRs,
+		selector:                  selector,
+		lastUpdateTimes:           make(map[string]time.Time),
+		commitStatuses:            make(map[string]string),
+		syncReports:               make(map[string]string),
+		lastBackups:               make(map[string]string),
+		mutex:                     &sync.RWMutex{},
+		applier:                   applier,
+		commitFiles:               gitwrite.Commit,
+		notifier:                  notifier,
+		backups:                   backups,
+		gitHostLimiter:            gitHostLimiter,
+		upsertWorkers:             farosflags.UpsertWorkers,
+		apiReader:                 apiReader,
+		listPageSize:              defaultListPageSize,
+		priority:                  farosv1alpha1.GitTrackPriorityNormal,
+		informerSyncPeriod:        farosflags.SyncPeriod(),
+		log:                       rlogr.Log.WithName("gittrack-controller"),
+	}
+	farosflags.OnReload(r.reloadResources)
+	return r
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	return addWithPriority(mgr, r, farosv1alpha1.GitTrackPriorityNormal, 1)
+}
+
+// addWithPriority adds a new Controller to mgr reconciling the GitTracks of
+// the priority with r, using the given number of workers
+func addWithPriority(mgr manager.Manager, r reconcile.Reconciler, priority farosv1alpha1.GitTrackPriority, workers int) error {
 	// Create a new controller
-	c, err := controller.New("gittrack-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName(priority), mgr, controller.Options{
+		Reconciler:              r,
+		MaxConcurrentReconciles: workers,
+	})
+	if err != nil {
+		return err
+	}
+
+	selector, err := farosflags.ParseGitTrackSelector()
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to GitTrack
-	err = c.Watch(&source.Kind{Type: &farosv1alpha1.GitTrack{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to GitTracks of the priority matching the selector
+	err = c.Watch(
+		&source.Kind{Type: &farosv1alpha1.GitTrack{}},
+		&handler.EnqueueRequestForObject{},
+		utils.NewLabelSelectorPredicate(selector),
+		priorityPredicate{priority: priority},
+	)
 	if err != nil {
 		return err
 	}
@@ -119,6 +219,44 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Reconcile the GitTracks selecting a FarosCluster when it changes
+	err = c.Watch(&source.Kind{Type: &farosv1alpha1.FarosCluster{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: clusterRequests(mgr.GetClient(), rlogr.Log.WithName("gittrack-controller")),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Reconcile the GitTracks referencing a Secret when it changes
+	err = c.Watch(&source.Kind{Type: &apiv1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: secretRequests(mgr.GetClient(), selector, rlogr.Log.WithName("gittrack-controller")),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Reconcile all GitTracks when the ignored resources ConfigMap changes
+	ignoredResourcesConfigMap, err := farosflags.ParseIgnoredResourcesConfigMap()
+	if err != nil {
+		return err
+	}
+	if ignoredResourcesConfigMap != nil {
+		informer, err := ignoredResourcesInformer(mgr, *ignoredResourcesConfigMap)
+		if err != nil {
+			return err
+		}
+		err = c.Watch(&source.Informer{Informer: informer}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: ignoredResourcesRequests(
+				mgr.GetClient(),
+				*ignoredResourcesConfigMap,
+				rlogr.Log.WithName("gittrack-controller"),
+			),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -127,35 +265,87 @@ var _ reconcile.Reconciler = &ReconcileGitTrack{}
 // ReconcileGitTrack reconciles a GitTrack object
 type ReconcileGitTrack struct {
 	client.Client
-	scheme          *runtime.Scheme
-	store           *gitstore.RepoStore
-	restMapper      meta.RESTMapper
-	recorder        record.EventRecorder
-	ignoredGVRs     map[schema.GroupVersionResource]interface{}
-	lastUpdateTimes map[string]time.Time
-	mutex           *sync.RWMutex
-	applier         farosclient.Client
-	log             logr.Logger
+	scheme                    *runtime.Scheme
+	store                     *gitstore.RepoStore
+	restMapper                meta.RESTMapper
+	recorder                  record.EventRecorder
+	ignoredGVRs               map[schema.GroupVersionResource]interface{}
+	ignoredResourcesConfigMap *types.NamespacedName
+	allowedGVRs               map[schema.GroupVersionResource]interface{}
+	selector                  labels.Selector
+	lastUpdateTimes           map[string]time.Time
+	commitStatuses            map[string]string
+	syncReports               map[string]string
+	lastBackups               map[string]string
+	mutex                     *sync.RWMutex
+	applier                   farosclient.Client
+	commitFiles               func(gitwrite.Options, gitwrite.EditFunc) (string, error)
+	notifier                  notifications.Notifier
+	backups                   backup.Store
+	gitHostLimiter            *hostLimiter
+	upsertWorkers             int
+	apiReader                 client.Reader
+	listPageSize              int64
+	log                       logr.Logger
+
+	// priority is the priority of the GitTracks reconciled, GitTracks of
+	// other priorities are reconciled from another queue
+	priority farosv1alpha1.GitTrackPriority
+
+	// informerSyncPeriod is the sync period the informer caches resync with
+	informerSyncPeriod time.Duration
+
+	// clusters are the FarosClusters selected by the GitTrack being reconciled
+	clusters map[string]*farosv1alpha1.FarosCluster
+
+	// hookToken identifies the desired state of the GitTrack being
+	// reconciled, its hooks are run once for each token
+	hookToken string
+}
+
+// withPriority returns a copy of the reconciler reconciling the GitTracks of
+// the priority. The copy shares the state of the reconciler and reloads its
+// own resource lists.
+func (r *ReconcileGitTrack) withPriority(priority farosv1alpha1.GitTrackPriority) *ReconcileGitTrack {
+	r.mutex.RLock()
+	reconciler := *r
+	r.mutex.RUnlock()
+	reconciler.priority = priority
+	farosflags.OnReload(reconciler.reloadResources)
+	return &reconciler
 }
 
 func (r *ReconcileGitTrack) withValues(keysAndValues ...interface{}) *ReconcileGitTrack {
+	r.mutex.RLock()
 	reconciler := *r
+	r.mutex.RUnlock()
 	reconciler.log = r.log.WithValues(keysAndValues...)
 	return &reconciler
 }
 
-// checkoutRepo checks out the repository at reference and returns a pointer to said repository
-func (r *ReconcileGitTrack) checkoutRepo(url string, ref string, gitCreds *gitCredentials) (*gitstore.Repo, error) {
+// checkoutRepo checks out the repository at reference and returns a pointer to said repository.
+// The repository is fetched before checking out when fetch is true.
+func (r *ReconcileGitTrack) checkoutRepo(url string, ref string, gitCreds *gitCredentials, fetch bool) (*gitstore.Repo, error) {
 	r.log.V(1).Info("Getting repository", "url", url)
 	repoRef, err := createRepoRefFromCreds(url, gitCreds)
 	if err != nil {
 		return &gitstore.Repo{}, err
 	}
+	// Getting the repository clones it the first time
+	r.gitHostLimiter.accept(url)
 	repo, err := r.store.Get(repoRef)
 	if err != nil {
 		return &gitstore.Repo{}, fmt.Errorf("failed to get repository '%s': %v'", url, err)
 	}
 
+	if fetch {
+		r.log.V(1).Info("Fetching repository", "url", url)
+		r.gitHostLimiter.accept(url)
+		if err = repo.Fetch(); err != nil {
+			return &gitstore.Repo{}, fmt.Errorf("failed to fetch repository '%s': %v", url, err)
+		}
+	}
+
 	r.log.V(1).Info("Checking out reference", "reference", ref)
 	err = repo.Checkout(ref)
 	if err != nil {
@@ -206,30 +396,25 @@ func (r *ReconcileGitTrack) fetchGitCredentials(namespace string, deployKey faro
 	return &gitCredentials{secret: secretData, credentialType: deployKey.Type}, nil
 }
 
-// getFiles checks out the Spec.Repository at Spec.Reference and returns a map of filename to
+// getFiles checks out the Spec.Repository at the reference and returns a map of filename to
 // gitstore.File pointers
-func (r *ReconcileGitTrack) getFiles(gt *farosv1alpha1.GitTrack) (map[string]*gitstore.File, error) {
-	r.recorder.Eventf(gt, apiv1.EventTypeNormal, "CheckoutStarted", "Checking out '%s' at '%s'", gt.Spec.Repository, gt.Spec.Reference)
-	gitCreds, err := r.fetchGitCredentials(gt.Namespace, gt.Spec.DeployKey)
+func (r *ReconcileGitTrack) getFiles(gt *farosv1alpha1.GitTrack, ref string) (map[string]*gitstore.File, error) {
+	r.recorder.Eventf(gt, apiv1.EventTypeNormal, "CheckoutStarted", "Checking out '%s' at '%s'", gt.Spec.Repository, ref)
+	deployKey := gittrackutils.DeployKey(gt, gt.Spec.SubPath, gt.Spec.Reference)
+	gitCreds, err := r.fetchGitCredentials(gt.Namespace, deployKey)
 	if err != nil {
-		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CheckoutFailed", "Failed to checkout '%s' at '%s'", gt.Spec.Repository, gt.Spec.Reference)
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CheckoutFailed", "Failed to checkout '%s' at '%s'", gt.Spec.Repository, ref)
 		return nil, fmt.Errorf("unable to retrieve git credentials from secret: %v", err)
 	}
 
-	repo, err := r.checkoutRepo(gt.Spec.Repository, gt.Spec.Reference, gitCreds)
+	repo, err := r.checkoutRepo(gt.Spec.Repository, ref, gitCreds, pendingSyncToken(gt) != "")
 	if err != nil {
-		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CheckoutFailed", "Failed to checkout '%s' at '%s'", gt.Spec.Repository, gt.Spec.Reference)
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CheckoutFailed", "Failed to checkout '%s' at '%s'", gt.Spec.Repository, ref)
 		return nil, err
 	}
 
-	subPath := gt.Spec.SubPath
-	if !strings.HasSuffix(subPath, "/") {
-		subPath += "/"
-	}
-
-	r.log.V(1).Info("Loading files from subpath", "subpath", subPath)
-	globbedSubPath := strings.TrimPrefix(subPath, "/") + "{**/*,*}.{yaml,yml,json}"
-	files, err := repo.GetAllFiles(globbedSubPath, true)
+	r.log.V(1).Info("Loading files from subpath", "subpath", gt.Spec.SubPath)
+	files, err := repo.GetAllFiles(render.ManifestGlob(gt.Spec.SubPath), true)
 	if err != nil {
 		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "CheckoutFailed", "Failed to get files for SubPath '%s'", gt.Spec.SubPath)
 		return nil, fmt.Errorf("failed to get all files for subpath '%s': %v", gt.Spec.SubPath, err)
@@ -255,35 +440,41 @@ func (r *ReconcileGitTrack) fetchInstance(req reconcile.Request) (*farosv1alpha1
 		// Error reading the object - requeue the request.
 		return nil, err
 	}
+	// GitTracks not matching the selector are managed by another controller
+	if r.selector != nil && !r.selector.Matches(labels.Set(instance.GetLabels())) {
+		return nil, nil
+	}
+	// GitTracks of another priority are reconciled from another queue
+	if gitTrackPriority(instance) != r.priority {
+		return nil, nil
+	}
 	return instance, nil
 }
 
-// listObjectsByName lists and filters GitTrackObjects by the `faros.pusher.com/owned-by` label,
-// and returns a map of names to GitTrackObject mappings
+// listObjectsByName lists GitTrackObjects by the `faros.pusher.com/gittrack` label, filters
+// them by owner and returns a map of names to GitTrackObject mappings
 func (r *ReconcileGitTrack) listObjectsByName(owner *farosv1alpha1.GitTrack) (map[string]farosv1alpha1.GitTrackObjectInterface, error) {
 	result := make(map[string]farosv1alpha1.GitTrackObjectInterface)
 
-	gtos := &farosv1alpha1.GitTrackObjectList{}
-	err := r.List(context.TODO(), gtos)
-	if err != nil {
-		return nil, err
-	}
-	for _, gto := range gtos.Items {
-		if metav1.IsControlledBy(&gto, owner) {
-			result[gto.GetNamespacedName()] = gto.DeepCopy()
-		}
-	}
-
-	cgtos := &farosv1alpha1.ClusterGitTrackObjectList{}
-	err = r.List(context.TODO(), cgtos)
+	err := r.listChildren(owner,
+		func(gtos *farosv1alpha1.GitTrackObjectList) {
+			for i := range gtos.Items {
+				if metav1.IsControlledBy(&gtos.Items[i], owner) {
+					result[gtos.Items[i].GetNamespacedName()] = gtos.Items[i].DeepCopy()
+				}
+			}
+		},
+		func(cgtos *farosv1alpha1.ClusterGitTrackObjectList) {
+			for i := range cgtos.Items {
+				if metav1.IsControlledBy(&cgtos.Items[i], owner) {
+					result[cgtos.Items[i].GetNamespacedName()] = cgtos.Items[i].DeepCopy()
+				}
+			}
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
-	for _, cgto := range cgtos.Items {
-		if metav1.IsControlledBy(&cgto, owner) {
-			result[cgto.GetNamespacedName()] = cgto.DeepCopy()
-		}
-	}
 
 	return result, nil
 }
@@ -292,70 +483,100 @@ func (r *ReconcileGitTrack) listObjectsByName(owner *farosv1alpha1.GitTrack) (ma
 type result struct {
 	NamespacedName string
 	Error          error
+	Kind           string
 	Ignored        bool
 	Reason         string
 	InSync         bool
+	SyncReason     string
+	Healthy        bool
+	Health         string
+	Applied        farosv1alpha1.ChildApplyResult
 	TimeToDeploy   time.Duration
+	File           string
+	Cluster        string
+	ErrorClass     farosv1alpha1.FileErrorClass
 }
 
 // errorResult is a convenience function for creating an error result
 func errorResult(namespacedName string, err error) result {
-	return result{NamespacedName: namespacedName, Error: err, Ignored: true}
+	return result{NamespacedName: namespacedName, Error: err, ErrorClass: farosv1alpha1.FileErrorClassApply, Ignored: true, Applied: farosv1alpha1.ChildApplyResultFailed}
 }
 
 // ignoreResult is a convenience function for creating an ignore result
 func ignoreResult(namespacedName string, reason string) result {
-	return result{NamespacedName: namespacedName, Ignored: true, Reason: reason}
+	return result{NamespacedName: namespacedName, Ignored: true, Reason: reason, Applied: farosv1alpha1.ChildApplyResultIgnored}
 }
 
 // successResult is a convenience function for creating a success result
 func successResult(namespacedName string, timeToDeploy time.Duration, inSync bool) result {
-	return result{NamespacedName: namespacedName, TimeToDeploy: timeToDeploy, InSync: inSync}
+	return result{NamespacedName: namespacedName, TimeToDeploy: timeToDeploy, InSync: inSync, Applied: farosv1alpha1.ChildApplyResultUnchanged}
 }
 
-func (r *ReconcileGitTrack) newGitTrackObjectInterface(name string, u *unstructured.Unstructured) (farosv1alpha1.GitTrackObjectInterface, error) {
-	var instance farosv1alpha1.GitTrackObjectInterface
+// newGitTrackObjectInterface builds the (Cluster)GitTrackObject for the object
+// depending on whether its resource is namespaced. Large data is compressed so
+// that the (Cluster)GitTrackObject stays below the etcd request size limit.
+func (r *ReconcileGitTrack) newGitTrackObjectInterface(u *unstructured.Unstructured) (farosv1alpha1.GitTrackObjectInterface, error) {
 	_, namespaced, err := utils.GetAPIResource(r.restMapper, u.GetObjectKind().GroupVersionKind())
 	if err != nil {
 		return nil, fmt.Errorf("error getting API resource: %v", err)
 	}
-	if namespaced {
-		instance = &farosv1alpha1.GitTrackObject{
-			TypeMeta: farosv1alpha1.GitTrackObjectTypeMeta,
-		}
-	} else {
-		instance = &farosv1alpha1.ClusterGitTrackObject{
-			TypeMeta: farosv1alpha1.ClusterGitTrackObjectTypeMeta,
-		}
+	gto, err := render.GitTrackObject(u, namespaced)
+	if err != nil {
+		return nil, err
 	}
-	instance.SetName(name)
-	instance.SetNamespace(u.GetNamespace())
-
-	data, err := u.MarshalJSON()
+	spec := gto.GetSpec()
+	err = gittrackobjectutils.CompressData(&spec, farosflags.CompressDataThreshold)
 	if err != nil {
-		return nil, fmt.Errorf("error marshalling JSON: %v", err)
+		return nil, err
 	}
+	gto.SetSpec(spec)
+	return gto, nil
+}
 
-	instance.SetSpec(farosv1alpha1.GitTrackObjectSpec{
-		Name: u.GetName(),
-		Kind: u.GetKind(),
-		Data: data,
-	})
-	return instance, nil
+// setKubeConfigSecretRef points the (Cluster)GitTrackObject at the kubeconfig
+// of the cluster the object is applied to, if any
+func (r *ReconcileGitTrack) setKubeConfigSecretRef(gto farosv1alpha1.GitTrackObjectInterface, u *unstructured.Unstructured, owner *farosv1alpha1.GitTrack) {
+	ref := r.kubeConfigSecretRef(u, owner)
+	if ref == nil {
+		return
+	}
+	spec := gto.GetSpec()
+	spec.KubeConfigSecretRef = &farosv1alpha1.KubeConfigSecretReference{
+		Namespace:  owner.Namespace,
+		SecretName: ref.SecretName,
+		Key:        ref.Key,
+	}
+	gto.SetSpec(spec)
+}
+
+// setDeletionPropagation sets the propagation policy the
+// (Cluster)GitTrackObject deletes its child with to that of the GitTrack
+func setDeletionPropagation(gto farosv1alpha1.GitTrackObjectInterface, owner *farosv1alpha1.GitTrack) {
+	spec := gto.GetSpec()
+	spec.DeletionPropagation = owner.Spec.DeletionPropagation
+	gto.SetSpec(spec)
 }
 
-// objectName constructs a name from an Unstructured object
-func objectName(u *unstructured.Unstructured) string {
-	return strings.ToLower(fmt.Sprintf("%s-%s", u.GetKind(), strings.Replace(u.GetName(), ":", "-", -1)))
+// isNamespaced returns whether the resource of the object is namespaced,
+// objects whose resource is unknown are assumed not to be
+func (r *ReconcileGitTrack) isNamespaced(u *unstructured.Unstructured) bool {
+	_, namespaced, err := utils.GetAPIResource(r.restMapper, u.GetObjectKind().GroupVersionKind())
+	if err != nil {
+		r.log.V(1).Info("Unable to determine whether object is namespaced", "kind", u.GetKind(), "name", u.GetName(), "error", err.Error())
+		return false
+	}
+	return namespaced
 }
 
 // handleObject either creates or updates a GitTrackObject
 func (r *ReconcileGitTrack) handleObject(u *unstructured.Unstructured, owner *farosv1alpha1.GitTrack) result {
-	name := objectName(u)
-	gto, err := r.newGitTrackObjectInterface(name, u)
+	name := render.ObjectName(u)
+	gto, err := r.newGitTrackObjectInterface(u)
 	if err != nil {
 		namespacedName := strings.TrimLeft(fmt.Sprintf("%s/%s", u.GetNamespace(), name), "/")
-		return errorResult(namespacedName, err)
+		res := errorResult(namespacedName, err)
+		res.ErrorClass = farosv1alpha1.FileErrorClassInvalidObject
+		return res
 	}
 
 	ignored, reason, err := r.ignoreObject(u)
@@ -365,6 +586,8 @@ func (r *ReconcileGitTrack) handleObject(u *unstructured.Unstructured, owner *fa
 	if ignored {
 		return ignoreResult(gto.GetNamespacedName(), reason)
 	}
+	r.setKubeConfigSecretRef(gto, u, owner)
+	setDeletionPropagation(gto, owner)
 
 	r.mutex.RLock()
 	timeToDeploy := time.Now().Sub(r.lastUpdateTimes[owner.Spec.Repository])
@@ -373,6 +596,20 @@ func (r *ReconcileGitTrack) handleObject(u *unstructured.Unstructured, owner *fa
 	if err = controllerutil.SetControllerReference(owner, gto, r.scheme); err != nil {
 		return errorResult(gto.GetNamespacedName(), err)
 	}
+	// Label the child so that the children of the GitTrack can be listed
+	gto.SetLabels(map[string]string{gittrackutils.GitTrackLabel: owner.Name})
+	if cluster, ok := u.GetAnnotations()[render.ClusterAnnotation]; ok {
+		gto.GetLabels()[gittrackutils.ClusterLabel] = cluster
+	}
+	// Hooks are run by recreating their child whenever the token changes
+	if r.hookToken != "" && isHook(u) {
+		gto.SetAnnotations(map[string]string{gittrackobjectutils.RecreateAnnotation: r.hookToken})
+	}
+	// Data too large for the (Cluster)GitTrackObject is stored in ConfigMaps
+	err = r.storeData(owner, gto, u)
+	if err != nil {
+		return errorResult(gto.GetNamespacedName(), fmt.Errorf("failed to store data for '%s': %v", name, err))
+	}
 	found := gto.DeepCopyInterface()
 	err = r.Get(context.TODO(), types.NamespacedName{Name: gto.GetName(), Namespace: gto.GetNamespace()}, found)
 	if err != nil && errors.IsNotFound(err) {
@@ -387,18 +624,34 @@ func (r *ReconcileGitTrack) handleObject(u *unstructured.Unstructured, owner *fa
 		return ignoreResult(gto.GetNamespacedName(), "child is owned by another controller")
 	}
 
-	inSync := childInSync(found)
+	// The child is in the repository again so it is no longer orphaned
+	err = r.clearOrphanedLabel(found)
+	if err != nil {
+		return errorResult(gto.GetNamespacedName(), fmt.Errorf("failed to remove orphaned label from child '%s': %v", name, err))
+	}
+
+	inSync, syncReason := childInSync(found), childSyncReason(found)
 	childUpdated, err := r.updateChild(found, gto)
 	if err != nil {
 		r.recorder.Eventf(owner, apiv1.EventTypeWarning, "UpdateFailed", "Failed to update child '%s'", name)
 		return errorResult(gto.GetNamespacedName(), fmt.Errorf("failed to update child resource: %v", err))
 	}
+	err = r.removeStaleData(found, gto)
+	if err != nil {
+		return errorResult(gto.GetNamespacedName(), fmt.Errorf("failed to remove stale data for '%s': %v", name, err))
+	}
 	if childUpdated {
-		inSync = false
+		inSync, syncReason = false, "child updated"
 		r.log.V(0).Info("Child updated", "child name", name)
 		r.recorder.Eventf(owner, apiv1.EventTypeNormal, "UpdateSuccessful", "Updated child '%s'", name)
 	}
-	return successResult(gto.GetNamespacedName(), timeToDeploy, inSync)
+	res := successResult(gto.GetNamespacedName(), timeToDeploy, inSync)
+	res.SyncReason = syncReason
+	if childUpdated {
+		res.Applied = farosv1alpha1.ChildApplyResultUpdated
+	}
+	res.Healthy, res.Health = childHealth(found)
+	return res
 }
 
 func childInSync(child farosv1alpha1.GitTrackObjectInterface) bool {
@@ -410,6 +663,31 @@ func childInSync(child farosv1alpha1.GitTrackObjectInterface) bool {
 	return false
 }
 
+// childSyncReason returns a description of why the child is not in sync
+func childSyncReason(child farosv1alpha1.GitTrackObjectInterface) string {
+	for _, condition := range child.GetStatus().Conditions {
+		if condition.Type == farosv1alpha1.ObjectInSyncType {
+			return strings.TrimSuffix(fmt.Sprintf("%s: %s", condition.Reason, condition.Message), ": ")
+		}
+	}
+	return "sync status unknown"
+}
+
+// childHealth returns whether the child's resource has reached its desired
+// state and, if not, a description of its health
+func childHealth(child farosv1alpha1.GitTrackObjectInterface) (bool, string) {
+	for _, condition := range child.GetStatus().Conditions {
+		if condition.Type != farosv1alpha1.ChildHealthyType {
+			continue
+		}
+		if condition.Status == apiv1.ConditionTrue {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+	}
+	return false, "health unknown"
+}
+
 func (r *ReconcileGitTrack) createChild(name string, timeToDeploy time.Duration, owner *farosv1alpha1.GitTrack, foundGTO, childGTO farosv1alpha1.GitTrackObjectInterface) result {
 	r.recorder.Eventf(owner, apiv1.EventTypeNormal, "CreateStarted", "Creating child '%s'", name)
 	if err := r.applier.Apply(context.TODO(), &farosclient.ApplyOptions{}, childGTO); err != nil {
@@ -418,7 +696,11 @@ func (r *ReconcileGitTrack) createChild(name string, timeToDeploy time.Duration,
 	}
 	r.recorder.Eventf(owner, apiv1.EventTypeNormal, "CreateSuccessful", "Created child '%s'", name)
 	r.log.V(0).Info("Child created", "child name", name)
-	return successResult(childGTO.GetNamespacedName(), timeToDeploy, false)
+	res := successResult(childGTO.GetNamespacedName(), timeToDeploy, false)
+	res.SyncReason = "child created"
+	res.Applied = farosv1alpha1.ChildApplyResultCreated
+	res.Healthy, res.Health = childHealth(childGTO)
+	return res
 }
 
 // UpdateChild compares the two GitTrackObjects and updates the foundGTO if the
@@ -439,34 +721,33 @@ func (r *ReconcileGitTrack) updateChild(foundGTO, childGTO farosv1alpha1.GitTrac
 }
 
 // deleteResources deletes any resources that are present in the given map
-func (r *ReconcileGitTrack) deleteResources(leftovers map[string]farosv1alpha1.GitTrackObjectInterface) error {
+func (r *ReconcileGitTrack) deleteResources(owner *farosv1alpha1.GitTrack, leftovers map[string]farosv1alpha1.GitTrackObjectInterface) error {
 	if len(leftovers) > 0 {
 		r.log.V(0).Info("Found leftover resources to clean up", "leftover resources", string(len(leftovers)))
 	}
 	for name, obj := range leftovers {
-		if err := r.Delete(context.TODO(), obj); err != nil {
+		if err := r.deleteChild(owner, obj); err != nil {
 			return fmt.Errorf("failed to delete child for '%s': '%s'", name, err)
 		}
+		if err := r.deleteData(obj); err != nil {
+			return fmt.Errorf("failed to delete data of child '%s': %v", name, err)
+		}
 		r.log.V(0).Info("Child deleted", "child name", name)
 	}
 	return nil
 }
 
-// objectsFrom iterates through all the files given and attempts to create Unstructured objects
-func objectsFrom(files map[string]*gitstore.File) ([]*unstructured.Unstructured, map[string]string) {
-	objects := []*unstructured.Unstructured{}
-	fileErrors := make(map[string]string)
-	for path, file := range files {
-		// TODO (@JoelSpeed): What happens if there are multiple resources in one file,
-		// but one of them is invalid? Can we still get the rest?
-		us, err := utils.YAMLToUnstructuredSlice([]byte(file.Contents()))
-		if err != nil {
-			fileErrors[path] = fmt.Sprintf("unable to parse '%s': %v\n", path, err)
-			continue
-		}
-		objects = append(objects, us...)
+// deleteChild deletes the (Cluster)GitTrackObject with the propagation
+// policy its child is then garbage collected with, if one is set
+func (r *ReconcileGitTrack) deleteChild(owner *farosv1alpha1.GitTrack, child farosv1alpha1.GitTrackObjectInterface) error {
+	policy, err := r.childDeletionPropagation(owner, child)
+	if err != nil {
+		return err
 	}
-	return objects, fileErrors
+	if policy != "" {
+		return r.Delete(context.TODO(), child, client.PropagationPolicy(policy))
+	}
+	return r.Delete(context.TODO(), child)
 }
 
 // checkOwner checks the owner reference of an object from the API to see if it
@@ -492,15 +773,22 @@ func (r *ReconcileGitTrack) ignoreObject(u *unstructured.Unstructured) (bool, st
 		return false, "", err
 	}
 
-	// Ignore namespaced objects not in the namespace managed by the controller
-	if namespaced && farosflags.Namespace != "" && farosflags.Namespace != u.GetNamespace() {
-		r.log.V(1).Info("Object not in namespace", "object namespace", u.GetNamespace(), "managed namespace", farosflags.Namespace)
+	// Ignore namespaced objects not in the namespaces managed by the controller,
+	// objects applied to selected namespaces are limited to managed namespaces
+	// when they are applied
+	if namespaced && !render.HasNamespaceSelector(u) && !farosflags.ManagesNamespace(u.GetNamespace()) {
+		r.log.V(1).Info("Object not in namespace", "object namespace", u.GetNamespace(), "managed namespaces", farosflags.Namespaces)
 		return true, fmt.Sprintf("namespace `%s` is not managed by this Faros", u.GetNamespace()), nil
 	}
 	// Ignore GVKs in the ignoredGVKs set
-	if _, ok := r.ignoredGVRs[gvr]; ok {
+	if source, ok := farosflags.MatchResource(r.ignoredGVRs, gvr); ok {
 		r.log.V(1).Info("Object group version ignored globally", "group version resource", gvr.String())
-		return true, fmt.Sprintf("resource `%s.%s/%s` ignored globally by flag", gvr.Resource, gvr.Group, gvr.Version), nil
+		return true, fmt.Sprintf("resource `%s.%s/%s` ignored globally by %s", gvr.Resource, gvr.Group, gvr.Version, ignoredBy(source)), nil
+	}
+	// Ignore GVKs not in the allowedGVRs set when it is given
+	if _, ok := farosflags.MatchResource(r.allowedGVRs, gvr); len(r.allowedGVRs) > 0 && !ok {
+		r.log.V(1).Info("Object group version not allowed", "group version resource", gvr.String())
+		return true, fmt.Sprintf("resource `%s.%s/%s` is not an allowed resource", gvr.Resource, gvr.Group, gvr.Version), nil
 	}
 	return false, "", nil
 }
@@ -511,7 +799,29 @@ func (r *ReconcileGitTrack) ignoreObject(u *unstructured.Unstructured) (bool, st
 // +kubebuilder:rbac:groups=faros.pusher.com,resources=gittracks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=faros.pusher.com,resources=gittrackobjects,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=faros.pusher.com,resources=clustergittrackobjects,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=gittrackrevisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=farosalerts,verbs=get;list;watch
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=farosproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=farosclusters,verbs=get;list;watch
 func (r *ReconcileGitTrack) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	defer health.RecoverPanic("gittrack-controller")
+	// Leave the request for the next instance once shutdown has begun
+	done, ok := shutdown.Begin()
+	if !ok {
+		r.log.V(1).Info("Shutting down, skipping reconcile", "request", request.String())
+		return reconcile.Result{}, nil
+	}
+	defer done()
+	start := time.Now()
+	result, err := r.handleReconcile(request)
+	if mErr := updateReconcileDurationMetric(time.Since(start), err); mErr != nil {
+		r.log.Error(mErr, "error updating Reconcile Duration metric")
+	}
+	return r.requeueAfterSyncPeriod(result), err
+}
+
+// handleReconcile performs the reconcile of the GitTrack for the request
+func (r *ReconcileGitTrack) handleReconcile(request reconcile.Request) (reconcile.Result, error) {
 	instance, err := r.fetchInstance(request)
 	if err != nil || instance == nil {
 		return reconcile.Result{}, err
@@ -520,49 +830,119 @@ func (r *ReconcileGitTrack) Reconcile(request reconcile.Request) (reconcile.Resu
 	reconciler := r.withValues(
 		"namespace", instance.GetNamespace(),
 		"name", instance.GetName(),
+		"reference", instance.Spec.Reference,
 	)
 	reconciler.log.V(1).Info("Reconcile started")
 
+	// Clean up cluster scoped children if the GitTrack is being deleted
+	if instance.GetDeletionTimestamp() != nil {
+		err = reconciler.handleDeletion(instance)
+		reconciler.log.V(1).Info("Reconcile finished")
+		return reconcile.Result{}, err
+	}
+
+	// Skip syncing while the GitTrack is suspended
+	if instance.Spec.Suspend {
+		err = reconciler.updateSuspendedStatus(instance)
+		reconciler.log.V(1).Info("Reconcile suspended")
+		return reconcile.Result{}, err
+	}
+
 	sOpts := newStatusOpts()
+	sOpts.lastHealthy = instance.Status.LastHealthyRevision
 	mOpts := newMetricOpts(sOpts)
+	rOpts := &revisionOpts{}
 
 	// Update the GitTrack status when we leave this function
 	defer func() {
 		err := reconciler.updateStatus(instance, sOpts)
 		mErr := reconciler.updateMetrics(instance, mOpts)
+		rErr := reconciler.recordRevision(instance, sOpts, rOpts)
+		nErr := reconciler.notify(instance, sOpts)
+		csErr := reconciler.reportCommitStatus(instance, sOpts, rOpts)
+		srErr := reconciler.commitSyncReport(instance, sOpts, rOpts)
+		bErr := reconciler.backupManifests(instance, sOpts, rOpts)
 
 		reconciler.log.V(1).Info("Reconcile finished")
 		// Print out any errors that may have occurred
 		for _, e := range []error{
 			err,
 			mErr,
+			rErr,
+			nErr,
+			csErr,
+			srErr,
+			bErr,
 			sOpts.gitError,
 			sOpts.parseError,
 			sOpts.gcError,
 			sOpts.upToDateError,
 		} {
 			if e != nil {
-				r.log.Error(e, "error in reconcile")
+				reconciler.log.Error(e, "error in reconcile")
 			}
 		}
 	}()
 
-	// Set the repository for metrics
+	// Sync the commit of a previous revision while rolled back
+	sOpts.rollback, err = reconciler.resolveRollback(instance)
+	if err != nil {
+		sOpts.gitError = err
+		sOpts.gitReason = gittrackutils.InvalidRollback
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "RollbackFailed", "Failed to roll back to revision '%s': %v", instance.Spec.RollbackTo, err)
+		return reconcile.Result{}, err
+	}
+	if sOpts.rollback != nil && instance.Status.Rollback == nil {
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeNormal, "RollbackStarted", "Rolling back to revision '%s' at '%s'", sOpts.rollback.Revision, sOpts.rollback.Commit)
+	}
+	rOpts.rollback = sOpts.rollback
+
+	// Set the repository for metrics and the reference for status
 	mOpts.repository = instance.Spec.Repository
+	sOpts.reference = syncReference(instance, sOpts.rollback)
+
+	syncToken := pendingSyncToken(instance)
+	if syncToken != "" {
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeNormal, "SyncRequested", "Sync requested by %s annotation %q", gittrackutils.SyncNowAnnotation, syncToken)
+	}
 
 	// Get a map of the files that are in the Spec
-	files, err := reconciler.getFiles(instance)
+	files, err := reconciler.getFiles(instance, sOpts.reference)
 	if err != nil {
 		sOpts.gitError = err
 		sOpts.gitReason = gittrackutils.ErrorFetchingFiles
 		return reconcile.Result{}, err
 	}
+	// Refuse to sync anything that is not signed by a trusted key
+	if err = reconciler.verifyFiles(instance, sOpts.reference, files); err != nil {
+		sOpts.gitError = err
+		sOpts.gitReason = gittrackutils.VerificationFailed
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "VerificationFailed", "Failed to verify '%s' at '%s': %v", instance.Spec.Repository, sOpts.reference, err)
+		return reconcile.Result{}, err
+	}
 	// Git successful, set condition
 	sOpts.gitReason = gittrackutils.GitFetchSuccess
-	reconciler.recorder.Eventf(instance, apiv1.EventTypeNormal, "CheckoutSuccessful", "Successfully checked out '%s' at '%s'", instance.Spec.Repository, instance.Spec.Reference)
+	sOpts.syncToken = syncToken
+	rOpts.setFiles(files)
+	reconciler.recorder.Eventf(instance, apiv1.EventTypeNormal, "CheckoutSuccessful", "Successfully checked out '%s' at '%s'", instance.Spec.Repository, sOpts.reference)
 
 	// Attempt to parse k8s objects from files
-	objects, fileErrors := objectsFrom(files)
+	objects, objectFiles, fileErrors := render.Objects(files)
+	if instance.Spec.Layout == farosv1alpha1.GitTrackLayoutNamespaced {
+		var layoutErrors map[string]string
+		objects, layoutErrors = render.NamespacedLayout(objects, objectFiles, instance.Spec.SubPath, reconciler.isNamespaced)
+		for file, reason := range layoutErrors {
+			fileErrors[file] = reason
+		}
+	}
+	// Reject objects defined more than once rather than applying whichever
+	// definition happens to be read last
+	objects, duplicates, duplicateErrors := findDuplicates(objects, objectFiles)
+	sOpts.fileErrors = boundFileErrors(append(parseFileErrors(fileErrors), duplicateFileErrors(duplicateErrors)...))
+	for file, reason := range duplicateErrors {
+		fileErrors[file] = reason
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "DuplicateDefinition", "Rejected '%s': %s", file, reason)
+	}
 	sOpts.ignoredFiles = fileErrors
 	sOpts.ignored += int64(len(fileErrors))
 	if len(fileErrors) > 0 {
@@ -570,6 +950,8 @@ func (r *ReconcileGitTrack) Reconcile(request reconcile.Request) (reconcile.Resu
 		for file, reason := range fileErrors {
 			errs = append(errs, fmt.Sprintf("%s: %s", file, reason))
 		}
+		// Sort the errors so that the condition message is stable
+		sort.Strings(errs)
 		sOpts.parseError = fmt.Errorf(strings.Join(errs, ",\n"))
 		sOpts.parseReason = gittrackutils.ErrorParsingFiles
 	} else {
@@ -579,23 +961,125 @@ func (r *ReconcileGitTrack) Reconcile(request reconcile.Request) (reconcile.Resu
 	// Update status with the number of objects discovered
 	sOpts.discovered = int64(len(objects))
 
+	// Reject the GitTrack if it targets namespaces it is not allowed to
+	if err = reconciler.checkAllowedNamespaces(instance, objects); err != nil {
+		sOpts.parseError = err
+		sOpts.parseReason = gittrackutils.NamespaceNotAllowed
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "NamespaceNotAllowed", "Rejected GitTrack: %v", err)
+		return reconcile.Result{}, nil
+	}
+
+	// Reject the GitTrack if it contains kinds it is not allowed to
+	if err = checkAllowedKinds(instance, objects); err != nil {
+		sOpts.parseError = err
+		sOpts.parseReason = gittrackutils.KindNotAllowed
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "KindNotAllowed", "Rejected GitTrack: %v", err)
+		return reconcile.Result{}, nil
+	}
+
+	// Leave the children as they are while any file cannot be synced if the
+	// GitTrack is in the Strict sync mode
+	if reconciler.holdForInvalidFiles(instance, objects, objectFiles, sOpts) {
+		return reconcile.Result{}, nil
+	}
+
+	// Keep the rendered manifests to back up once they have been synced
+	if reconciler.backups != nil {
+		rOpts.setManifests(objects, objectFiles)
+	}
+
+	// Copy the objects for each of the selected clusters
+	if instance.Spec.ClusterSelector != nil {
+		reconciler.clusters, err = reconciler.selectClusters(instance)
+		if err != nil {
+			sOpts.parseError = err
+			sOpts.parseReason = gittrackutils.ErrorSelectingClusters
+			return reconcile.Result{}, err
+		}
+		if len(reconciler.clusters) == 0 {
+			reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "NoClustersSelected", "No FarosClusters match the cluster selector")
+		}
+		objects, objectFiles = fanOutObjects(objects, objectFiles, reconciler.clusters)
+	}
+
 	// Get a list of the GitTrackObjects that currently exist, by name
 	objectsByName, err := reconciler.listObjectsByName(instance)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	// Process the objects and feed back the results
-	resultsChan := make(chan result, len(objects))
-	for _, obj := range objects {
-		go func(obj *unstructured.Unstructured) {
-			resultsChan <- reconciler.handleObject(obj, instance)
-		}(obj)
+	mOpts.setChildren(objectsByName)
+	// Leave the children of duplicate objects as they are until the
+	// duplicates are resolved
+	if instance.Spec.ClusterSelector != nil {
+		duplicates, _ = fanOutObjects(duplicates, objectFiles, reconciler.clusters)
+	}
+	reconciler.keepDuplicates(duplicates, objectsByName)
+	// Resolve the resources to ignore as they may be changed at runtime
+	reconciler.ignoredGVRs, err = reconciler.ignoredResources(instance)
+	if err != nil {
+		reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "IgnoredResourcesFailed", "Failed to read ignored resources: %v", err)
+		return reconcile.Result{}, err
+	}
+	// Hold back changes while the sync windows are closed
+	held, err := reconciler.holdForSyncWindows(instance, objects, objectFiles, objectsByName, sOpts)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if held {
+		// Windows are scheduled to the minute, so check again in a minute
+		return reconcile.Result{RequeueAfter: time.Minute}, nil
+	}
+
+	// Hold back changes until they have been approved
+	held, err = reconciler.holdForApproval(instance, objects, objectFiles, objectsByName, sOpts)
+	if err != nil || held {
+		return reconcile.Result{}, err
+	}
+
+	// Hold back large deletions and recreations until they have been approved
+	held, err = reconciler.holdForDestructiveChanges(instance, objects, objectFiles, objectsByName, sOpts)
+	if err != nil || held {
+		return reconcile.Result{}, err
+	}
+
+	// Check the children against the admission policies of the cluster
+	objects = reconciler.checkPolicies(instance, objects, objectFiles, objectsByName, sOpts)
+
+	// Run the pre-apply hooks before the rest of the children and the
+	// post-apply hooks once the rest are in sync
+	hooks, err := reconciler.planHooks(objects, objectFiles, objectsByName)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Roll changes out to the canary children before the rest
+	var rollout *canaryRollout
+	if hooks.holding() {
+		objects = hooks.objects
+	} else {
+		rollout, err = reconciler.planCanary(instance, objects, objectFiles, objectsByName, sOpts)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if rollout != nil {
+			objects = rollout.objects
+		} else if canaryPromoted(instance) {
+			reconciler.recorder.Eventf(instance, apiv1.EventTypeNormal, "CanaryPromoted", "Promoting '%s' as canary children are healthy", sOpts.reference)
+		}
 	}
 
-	handlerErrors := []string{}
+	// Process the objects and feed back the results
+	resultsChan := reconciler.handleObjects(objects, objectFiles, instance)
+
+	handlerErrors := make(map[string][]string)
+	unhealthy := []string{}
+	outOfSync := []farosv1alpha1.GitTrackChildStatus{}
+	fileErrs := sOpts.fileErrors
+	clusters := newClusterStatuses(reconciler.clusters)
 	// Iterate through results and update status accordingly
 	for range objects {
 		res := <-resultsChan
+		clusters.add(res)
 		if res.Ignored {
 			sOpts.ignoredFiles[res.NamespacedName] = res.Reason
 			sOpts.ignored++
@@ -606,29 +1090,99 @@ func (r *ReconcileGitTrack) Reconcile(request reconcile.Request) (reconcile.Resu
 		if res.InSync {
 			sOpts.inSync++
 		}
+		if !res.Ignored && !res.Healthy {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s: %s", res.NamespacedName, res.Health))
+		}
 		delete(objectsByName, res.NamespacedName)
+		rOpts.addChild(res)
 		if res.Error != nil {
-			handlerErrors = append(handlerErrors, res.Error.Error())
+			handlerErrors[res.File] = append(handlerErrors[res.File], fmt.Sprintf("%s: %v", res.NamespacedName, res.Error))
+			outOfSync = append(outOfSync, farosv1alpha1.GitTrackChildStatus{Name: res.NamespacedName, Kind: res.Kind, Reason: res.Error.Error(), File: res.File})
+			fileErrs = append(fileErrs, farosv1alpha1.GitTrackFileError{Path: res.File, Class: res.ErrorClass, Message: fmt.Sprintf("%s: %v", res.NamespacedName, res.Error)})
+		} else if !res.Ignored && !res.InSync {
+			outOfSync = append(outOfSync, farosv1alpha1.GitTrackChildStatus{Name: res.NamespacedName, Kind: res.Kind, Reason: res.SyncReason, File: res.File})
 		}
 	}
 
 	// If there were errors updating the child objects, set the ChildrenUpToDate
 	// condition appropriately
 	if len(handlerErrors) > 0 {
-		sOpts.upToDateError = fmt.Errorf(strings.Join(handlerErrors, ",\n"))
+		sOpts.upToDateError = fileErrorsMessage(handlerErrors)
 		sOpts.upToDateReason = gittrackutils.ErrorUpdatingChildren
 	} else {
 		sOpts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
 	}
 
+	sOpts.childrenOutOfSync = boundChildStatuses(outOfSync)
+	sOpts.fileErrors = boundFileErrors(fileErrs)
+	sOpts.clusters = clusters.list()
+
+	// Aggregate the health of the children into the ChildrenHealthy condition
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		sOpts.healthError = fmt.Errorf(strings.Join(unhealthy, ",\n"))
+		sOpts.healthReason = gittrackutils.ChildrenUnhealthy
+	} else {
+		sOpts.healthReason = gittrackutils.ChildrenHealthy
+	}
+
+	// Roll back to the last healthy revision if the children stay unhealthy
+	if err = reconciler.trackHealthyRevision(instance, sOpts); err != nil {
+		return reconcile.Result{}, err
+	}
+	recheck, err := reconciler.checkAutoRollback(instance, sOpts)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	requeue := reconcile.Result{RequeueAfter: recheck}
+
+	// Hold back the rest of the changes until the hooks have completed
+	if hooks != nil {
+		reconciler.reportHooks(instance, hooks, sOpts)
+		if hooks.holding() {
+			return requeue, nil
+		}
+	}
+
+	// Hold back the rest of the changes until the canary children are healthy
+	if rollout != nil {
+		reconciler.holdForCanary(instance, rollout, sOpts)
+		return requeue, nil
+	}
+
+	// Split leftover resources according to the prune policy
+	toDelete, toRetain := partitionLeftovers(instance.Spec.PrunePolicy, objectsByName)
+
+	// Report, but never carry out, the clean-up in dry-run mode
+	if farosflags.DryRun {
+		if len(toDelete) > 0 {
+			reconciler.recorder.Eventf(instance, apiv1.EventTypeNormal, "DryRunCleanup", "Would delete %d leftover children", len(toDelete))
+		}
+		sOpts.gcReason = gittrackutils.GCSkippedDryRun
+		return requeue, nil
+	}
+
+	// Label any resources that should be left in place
+	if err = reconciler.retainResources(instance, toRetain); err != nil {
+		sOpts.gcError = err
+		sOpts.gcReason = gittrackutils.ErrorRetainingChildren
+		return reconcile.Result{}, fmt.Errorf("failed to retain tracked objects: %v", err)
+	}
+	sOpts.retained = int64(len(toRetain))
+
 	// Cleanup potentially leftover resources
-	if err = reconciler.deleteResources(objectsByName); err != nil {
+	if err = reconciler.deleteResources(instance, toDelete); err != nil {
 		sOpts.gcError = err
 		sOpts.gcReason = gittrackutils.ErrorDeletingChildren
 		reconciler.recorder.Eventf(instance, apiv1.EventTypeWarning, "CleanupFailed", "Failed to clean-up leftover resources")
 		return reconcile.Result{}, fmt.Errorf("failed to clean-up tracked objects: %v", err)
 	}
+	sOpts.pruned = int64(len(toDelete))
+	rOpts.addPruned(toDelete)
 	sOpts.gcReason = gittrackutils.GCSuccess
+	if len(toRetain) > 0 {
+		sOpts.gcReason = gittrackutils.ChildrenRetained
+	}
 
-	return reconcile.Result{}, nil
+	return requeue, nil
 }
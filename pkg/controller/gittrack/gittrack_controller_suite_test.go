@@ -17,7 +17,6 @@ limitations under the License.
 package gittrack
 
 import (
-	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -28,17 +27,11 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/pusher/faros/pkg/apis"
 	farosflags "github.com/pusher/faros/pkg/flags"
 	"github.com/pusher/faros/test/reporters"
-	"k8s.io/client-go/kubernetes/scheme"
+	"github.com/pusher/faros/test/testenv"
 	"k8s.io/client-go/rest"
-	"k8s.io/klog"
-	"k8s.io/klog/klogr"
-	"sigs.k8s.io/controller-runtime/pkg/envtest"
-	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	logr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 var cfg *rest.Config
@@ -70,28 +63,20 @@ func TestMain(t *testing.T) {
 	RunSpecsWithDefaultAndCustomReporters(t, "GitTrack Suite", reporters.Reporters())
 }
 
-var t *envtest.Environment
+var t *testenv.Environment
 
 var _ = BeforeSuite(func() {
-	logr.SetLogger(klogr.New())
-	logFlags := &flag.FlagSet{}
-	klog.InitFlags(logFlags)
-	// Set log level high for tests
-	logFlags.Lookup("v").Value.Set("4")
-
-	t = &envtest.Environment{
-		CRDDirectoryPaths: []string{filepath.Join("..", "..", "..", "config", "crds")},
-	}
-	apis.AddToScheme(scheme.Scheme)
+	testenv.SetupLogging()
 
 	repositoryPath = setupRepository()
 	repositoryURL = fmt.Sprintf("file://%s", repositoryPath)
-	farosflags.Namespace = "default"
+	farosflags.Namespaces = []string{"default"}
 
 	var err error
-	if cfg, err = t.Start(); err != nil {
+	if t, err = testenv.Start(); err != nil {
 		log.Fatal(err)
 	}
+	cfg = t.Config
 })
 
 var _ = AfterSuite(func() {
@@ -113,13 +98,3 @@ func SetupTestReconcile(inner reconcile.Reconciler) (reconcile.Reconciler, chan
 	})
 	return fn, requests
 }
-
-// StartTestManager adds recFn
-func StartTestManager(mgr manager.Manager) chan struct{} {
-	stop := make(chan struct{})
-	go func() {
-		defer GinkgoRecover()
-		Expect(mgr.Start(stop)).NotTo(HaveOccurred())
-	}()
-	return stop
-}
@@ -30,20 +30,33 @@ import (
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	"github.com/pusher/faros/pkg/controller/gittrack/metrics"
 	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
 	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/gitwrite"
+	"github.com/pusher/faros/pkg/notifications"
+	"github.com/pusher/faros/pkg/render"
 	farosclient "github.com/pusher/faros/pkg/utils/client"
 	testevents "github.com/pusher/faros/test/events"
+	"github.com/pusher/faros/test/testenv"
 	testutils "github.com/pusher/faros/test/utils"
 	gitstore "github.com/pusher/git-store"
 	"golang.org/x/net/context"
+	billy "gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 var c client.Client
@@ -96,10 +109,7 @@ var _ = Describe("GitTrack Suite", func() {
 		// channel when it is finished.
 		var err error
 		cfg.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
-		mgr, err = manager.New(cfg, manager.Options{
-			Namespace:          farosflags.Namespace,
-			MetricsBindAddress: "0", // Disable serving metrics while testing
-		})
+		mgr, err = t.NewManager(farosflags.Namespaces[0])
 		Expect(err).NotTo(HaveOccurred())
 		c = mgr.GetClient()
 
@@ -107,7 +117,7 @@ var _ = Describe("GitTrack Suite", func() {
 		r = newReconciler(mgr)
 		recFn, requests = SetupTestReconcile(r)
 		Expect(add(mgr, recFn)).NotTo(HaveOccurred())
-		stop = StartTestManager(mgr)
+		stop = testenv.StartManager(mgr)
 		instance = &farosv1alpha1.GitTrack{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "example",
@@ -121,10 +131,15 @@ var _ = Describe("GitTrack Suite", func() {
 
 	AfterEach(func() {
 		close(stop)
+		// Remove finalizers as the controller is no longer running to handle them
+		testutils.RemoveFinalizers(cfg, &farosv1alpha1.GitTrackList{})
 		testutils.DeleteAll(cfg, timeout,
 			&farosv1alpha1.GitTrackList{},
 			&farosv1alpha1.GitTrackObjectList{},
 			&farosv1alpha1.ClusterGitTrackObjectList{},
+			&farosv1alpha1.GitTrackRevisionList{},
+			&farosv1alpha1.FarosAlertList{},
+			&farosv1alpha1.FarosProviderList{},
 			&v1.EventList{},
 		)
 	})
@@ -140,10 +155,10 @@ var _ = Describe("GitTrack Suite", func() {
 			It("updates its status", func() {
 				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
 				two, zero := int64(2), int64(0)
-				Expect(instance.Status.ObjectsDiscovered).To(Equal(two))
-				Expect(instance.Status.ObjectsApplied).To(Equal(two))
-				Expect(instance.Status.ObjectsIgnored).To(Equal(zero))
-				Expect(instance.Status.ObjectsInSync).To(Equal(zero))
+				Expect(instance).To(testutils.WithGitTrackStatusObjectsDiscovered(Equal(two)))
+				Expect(instance).To(testutils.WithGitTrackStatusObjectsApplied(Equal(two)))
+				Expect(instance).To(testutils.WithGitTrackStatusObjectsIgnored(Equal(zero)))
+				Expect(instance).To(testutils.WithGitTrackStatusObjectsInSync(Equal(zero)))
 
 				deployGto := &farosv1alpha1.GitTrackObject{}
 				Eventually(func() error {
@@ -159,7 +174,7 @@ var _ = Describe("GitTrack Suite", func() {
 						LastUpdateTime:     now,
 					},
 				}
-				Expect(c.Update(context.TODO(), deployGto)).ToNot(HaveOccurred())
+				Expect(c.Status().Update(context.TODO(), deployGto)).ToNot(HaveOccurred())
 				// Wait for reconcile for update
 				Eventually(requests, timeout).Should(Receive(Equal(expectedRequest)))
 				// Wait for reconcile for status
@@ -171,15 +186,43 @@ var _ = Describe("GitTrack Suite", func() {
 			It("sets the status conditions", func() {
 				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
 				conditions := instance.Status.Conditions
-				Expect(len(conditions)).To(Equal(4))
+				Expect(len(conditions)).To(Equal(5))
 				parseErrorCondition := conditions[0]
 				gitErrorCondition := conditions[1]
 				gcErrorCondition := conditions[2]
 				upToDateCondiiton := conditions[3]
+				healthyCondition := conditions[4]
 				Expect(parseErrorCondition.Type).To(Equal(farosv1alpha1.FilesParsedType))
 				Expect(gitErrorCondition.Type).To(Equal(farosv1alpha1.FilesFetchedType))
 				Expect(gcErrorCondition.Type).To(Equal(farosv1alpha1.ChildrenGarbageCollectedType))
 				Expect(upToDateCondiiton.Type).To(Equal(farosv1alpha1.ChildrenUpToDateType))
+				Expect(healthyCondition.Type).To(Equal(farosv1alpha1.ChildrenHealthyType))
+			})
+
+			It("sets the last synced reference", func() {
+				Eventually(func() (string, error) {
+					err := c.Get(context.TODO(), key, instance)
+					return instance.Status.LastSyncedReference, err
+				}, timeout).Should(Equal(instance.Spec.Reference))
+			})
+
+			It("records a GitTrackRevision", func() {
+				revisions := &farosv1alpha1.GitTrackRevisionList{}
+				Eventually(func() ([]farosv1alpha1.GitTrackRevision, error) {
+					err := c.List(context.TODO(), revisions, client.InNamespace(instance.Namespace))
+					return revisions.Items, err
+				}, timeout).Should(HaveLen(1))
+
+				revision := revisions.Items[0]
+				Expect(revision.Labels).To(HaveKeyWithValue(gittrackutils.GitTrackLabel, instance.Name))
+				Expect(revision.Spec.GitTrack).To(Equal(instance.Name))
+				Expect(revision.Spec.Reference).To(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+				Expect(revision.Spec.Commit).To(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+				Expect(revision.Spec.Files).NotTo(BeEmpty())
+				Expect(revision.Spec.Children).To(ConsistOf(
+					farosv1alpha1.GitTrackRevisionChild{Name: "default/deployment-nginx", Kind: "Deployment", Result: farosv1alpha1.ChildApplyResultCreated},
+					farosv1alpha1.GitTrackRevisionChild{Name: "default/service-nginx", Kind: "Service", Result: farosv1alpha1.ChildApplyResultCreated},
+				))
 			})
 
 			Context("sets the status metrics", func() {
@@ -408,6 +451,26 @@ var _ = Describe("GitTrack Suite", func() {
 			})
 		})
 
+		Context("with the Namespaced layout", func() {
+			BeforeEach(func() {
+				instance.Spec.SubPath = "foo"
+				instance.Spec.Layout = farosv1alpha1.GitTrackLayoutNamespaced
+				createInstance(instance, "4c31dbdd7103dc209c8bb21b75d78b3efafadc31")
+				// Wait for client cache to expire
+				waitForInstanceCreated(key)
+			})
+
+			It("sets the namespace of objects in a directory from the directory", func() {
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+				Expect(instance.Status.IgnoredFiles).To(HaveKeyWithValue("bar/service-nginx", "namespace `bar` is not managed by this Faros"))
+			})
+
+			It("leaves the namespace of objects directly beneath the subPath", func() {
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+				Expect(instance.Status.IgnoredFiles).To(HaveKeyWithValue("foo/deployment-nginx", "namespace `foo` is not managed by this Faros"))
+			})
+		})
+
 		Context("with a child owned by another controller", func() {
 			truth := true
 			var existingChild *farosv1alpha1.GitTrackObject
@@ -540,6 +603,19 @@ var _ = Describe("GitTrack Suite", func() {
 					return c.Get(context.TODO(), types.NamespacedName{Name: "ingress-example", Namespace: "default"}, after)
 				}, timeout).Should(Succeed())
 			})
+
+			It("records a GitTrackRevision for each sync", func() {
+				instance.Spec.Reference = "09d24c51c191b4caacd35cda23bd44c86f16edc6"
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+				// Wait for reconcile for update
+				Eventually(requests, timeout).Should(Receive(Equal(expectedRequest)))
+
+				revisions := &farosv1alpha1.GitTrackRevisionList{}
+				Eventually(func() ([]farosv1alpha1.GitTrackRevision, error) {
+					err := c.List(context.TODO(), revisions, client.InNamespace(instance.Namespace))
+					return revisions.Items, err
+				}, timeout).Should(HaveLen(2))
+			})
 		})
 
 		Context("and resources are removed from the repository", func() {
@@ -584,6 +660,109 @@ var _ = Describe("GitTrack Suite", func() {
 			})
 		})
 
+		Context("and resources are removed from the repository in dry-run mode", func() {
+			BeforeEach(func() {
+				farosflags.DryRun = true
+				createInstance(instance, "4532b487a5aaf651839f5401371556aa16732a6e")
+				// Wait for client cache to expire
+				waitForInstanceCreated(key)
+
+				// Check the configmap that would be deleted was created
+				Eventually(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "configmap-deleted-config", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				}, timeout).Should(Succeed())
+
+				// Update the repository
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+				instance.Spec.Reference = "28928ccaeb314b96293e18cc8889997f0f46b79b"
+				err := c.Update(context.TODO(), instance)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Wait for cache to sync
+				waitForInstanceCreated(key)
+			})
+
+			AfterEach(func() {
+				farosflags.DryRun = false
+			})
+
+			It("reports that garbage collection was skipped", func() {
+				Eventually(func() *farosv1alpha1.GitTrack {
+					c.Get(context.TODO(), key, instance)
+					return instance
+				}, timeout).Should(testutils.WithGitTrackStatusConditions(ContainElement(SatisfyAll(
+					testutils.WithGitTrackConditionType(Equal(farosv1alpha1.ChildrenGarbageCollectedType)),
+					testutils.WithGitTrackConditionReason(Equal(string(gittrackutils.GCSkippedDryRun))),
+				))))
+			})
+
+			It("doesn't delete the removed resources", func() {
+				Consistently(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "configmap-deleted-config", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				}, time.Second).Should(Succeed())
+			})
+		})
+
+		Context("and resources are removed from the repository with the Retain prune policy", func() {
+			BeforeEach(func() {
+				instance.Spec.PrunePolicy = farosv1alpha1.PrunePolicyRetain
+				createInstance(instance, "4532b487a5aaf651839f5401371556aa16732a6e")
+				// Wait for client cache to expire
+				waitForInstanceCreated(key)
+
+				// Check the instance created
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+
+				// Check the configmap to be retained was created
+				Eventually(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "configmap-deleted-config", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				}, timeout).Should(Succeed())
+
+				// Update the repository
+				instance.Spec.Reference = "28928ccaeb314b96293e18cc8889997f0f46b79b"
+				err := c.Update(context.TODO(), instance)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Wait for cache to sync
+				waitForInstanceCreated(key)
+			})
+
+			It("does not delete the removed resources", func() {
+				Consistently(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "configmap-deleted-config", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				}, time.Second).Should(Succeed())
+			})
+
+			It("labels the removed resources as orphaned", func() {
+				gto := &farosv1alpha1.GitTrackObject{}
+				Eventually(func() map[string]string {
+					Expect(c.Get(context.TODO(), types.NamespacedName{Name: "configmap-deleted-config", Namespace: "default"}, gto)).To(Succeed())
+					return gto.GetLabels()
+				}, timeout).Should(HaveKeyWithValue("faros.pusher.com/orphaned", "true"))
+			})
+
+			It("counts the retained resources in the status", func() {
+				Eventually(func() int64 {
+					Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+					return instance.Status.ObjectsRetained
+				}, timeout).Should(Equal(int64(1)))
+			})
+
+			It("sends a ChildRetained event", func() {
+				events := &v1.EventList{}
+				Eventually(func() error {
+					err := c.List(context.TODO(), events)
+					if err != nil {
+						return err
+					}
+					if testevents.None(events.Items, reasonFilter("ChildRetained")) {
+						return fmt.Errorf("events hasn't been sent yet")
+					}
+					return nil
+				}, timeout).Should(Succeed())
+			})
+		})
+
 		Context("and resources in the repository are updated", func() {
 			BeforeEach(func() {
 				createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
@@ -921,6 +1100,33 @@ var _ = Describe("GitTrack Suite", func() {
 	})
 
 	Context("When a GitTrack resource is deleted", func() {
+		BeforeEach(func() {
+			createInstance(instance, "b17c0e0f45beca3f1c1e62a7f49fecb738c60d42")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+
+			Eventually(func() error {
+				return c.Get(context.TODO(), types.NamespacedName{Name: "namespace-test", Namespace: ""}, &farosv1alpha1.ClusterGitTrackObject{})
+			}, timeout).Should(Succeed())
+			Eventually(func() []string {
+				Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+				return instance.GetFinalizers()
+			}, timeout).Should(ContainElement(cleanupFinalizer))
+
+			Expect(c.Delete(context.TODO(), instance)).To(Succeed())
+		})
+
+		It("deletes cluster scoped children", func() {
+			Eventually(func() error {
+				return c.Get(context.TODO(), types.NamespacedName{Name: "namespace-test", Namespace: ""}, &farosv1alpha1.ClusterGitTrackObject{})
+			}, timeout).ShouldNot(Succeed())
+		})
+
+		It("removes the cleanup finalizer", func() {
+			Eventually(func() error {
+				return c.Get(context.TODO(), key, &farosv1alpha1.GitTrack{})
+			}, timeout).ShouldNot(Succeed())
+		})
 	})
 
 	Context("When a GitTrack has a DeployKey, the Reconciler should", func() {
@@ -1026,6 +1232,66 @@ var _ = Describe("GitTrack Suite", func() {
 			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
 			Expect(instance.Status.IgnoredFiles).To(HaveLen(int(instance.Status.ObjectsIgnored)))
 		})
+
+		It("lists the invalid file in the fileErrors status", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.FileErrors).To(ContainElement(farosv1alpha1.GitTrackFileError{
+				Path:    "invalid_file.yaml",
+				Class:   farosv1alpha1.FileErrorClassParse,
+				Message: "unable to parse 'invalid_file.yaml': unable to unmarshal JSON: Object 'Kind' is missing in '{\"I\":\"a;m an \\\"invalid Kubernetes manifest.)\"}'",
+			}))
+		})
+	})
+
+	Context("with invalid files in the Strict sync mode", func() {
+		BeforeEach(func() {
+			instance.Spec.SyncMode = farosv1alpha1.SyncModeStrict
+			createInstance(instance, "936b7ee3df1dbd61b1fc691b742fa5d5d3c0dced")
+			waitForInstanceCreated(key)
+		})
+
+		It("blocks the sync", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.FilesParsedType)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(v1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(string(gittrackutils.StrictSyncBlocked)))
+			Expect(cond.Message).To(ContainSubstring("invalid_file.yaml: unable to parse 'invalid_file.yaml'"))
+			Expect(instance.Status.ObjectsApplied).To(Equal(int64(0)))
+		})
+
+		It("does not create any GitTrackObjects", func() {
+			gto := &farosv1alpha1.GitTrackObject{}
+			err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("sends a SyncBlocked event", func() {
+			events := &v1.EventList{}
+			Eventually(func() error { return c.List(context.TODO(), events) }, timeout).Should(Succeed())
+			blockedEvents := testevents.Select(events.Items, reasonFilter("SyncBlocked"))
+			Expect(blockedEvents).ToNot(BeEmpty())
+			for _, e := range blockedEvents {
+				Expect(e.InvolvedObject.Kind).To(Equal("GitTrack"))
+				Expect(e.Type).To(Equal(string(v1.EventTypeWarning)))
+			}
+		})
+	})
+
+	Context("with invalid files in the Partial sync mode", func() {
+		BeforeEach(func() {
+			instance.Spec.SyncMode = farosv1alpha1.SyncModePartial
+			createInstance(instance, "936b7ee3df1dbd61b1fc691b742fa5d5d3c0dced")
+			waitForInstanceCreated(key)
+		})
+
+		It("applies the valid objects", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.FilesParsedType)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal(string(gittrackutils.ErrorParsingFiles)))
+			Expect(instance.Status.ObjectsApplied).NotTo(BeZero())
+		})
 	})
 
 	Context("When a list of ignored GVRs is supplied", func() {
@@ -1071,112 +1337,1424 @@ var _ = Describe("GitTrack Suite", func() {
 		})
 	})
 
-	Context("listObjectsByName", func() {
-		var reconciler *ReconcileGitTrack
-		var children map[string]farosv1alpha1.GitTrackObjectInterface
-
+	Context("When a GitTrack is suspended", func() {
 		BeforeEach(func() {
-			var ok bool
-			reconciler, ok = r.(*ReconcileGitTrack)
-			Expect(ok).To(BeTrue())
-
-			createInstance(instance, "b17c0e0f45beca3f1c1e62a7f49fecb738c60d42")
+			instance.Spec.Suspend = true
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
 			// Wait for client cache to expire
 			waitForInstanceCreated(key)
+		})
 
-			var err error
-			children, err = reconciler.listObjectsByName(instance)
-			Expect(err).NotTo(HaveOccurred())
+		It("sets the Suspended condition", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.SuspendedType)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(v1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(string(gittrackutils.SuspendedBySpec)))
 		})
 
-		It("should return 6 child objects", func() {
-			Expect(children).Should(HaveLen(6))
+		It("does not sync the repository", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.ObjectsDiscovered).To(Equal(int64(0)))
+			gto := &farosv1alpha1.GitTrackObject{}
+			err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+			Expect(err).To(HaveOccurred())
 		})
 
-		It("should return 5 namespaced objects", func() {
-			var count int
-			for _, obj := range children {
-				if _, ok := obj.(*farosv1alpha1.GitTrackObject); ok {
-					count++
-				}
-			}
-			Expect(count).To(Equal(5))
+		Context("and then resumed", func() {
+			BeforeEach(func() {
+				Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+				instance.Spec.Suspend = false
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+			})
+
+			It("syncs the repository and removes the Suspended condition", func() {
+				Eventually(func() (int64, error) {
+					err := c.Get(context.TODO(), key, instance)
+					return instance.Status.ObjectsApplied, err
+				}, timeout).Should(Equal(int64(2)))
+				Expect(gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.SuspendedType)).To(BeNil())
+			})
 		})
+	})
 
-		It("should return 1 non-namespaced resource", func() {
-			var count int
-			for _, obj := range children {
-				if _, ok := obj.(*farosv1alpha1.ClusterGitTrackObject); ok {
-					count++
-				}
+	Context("When a GitTrack is outside of its sync windows", func() {
+		BeforeEach(func() {
+			instance.Spec.SyncWindows = []farosv1alpha1.SyncWindow{
+				{
+					Kind:     farosv1alpha1.SyncWindowDeny,
+					Schedule: "* * * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+				},
 			}
-			Expect(count).To(Equal(1))
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
 		})
 
-		It("should key all items by their NamespacedName", func() {
-			for key, obj := range children {
-				Expect(key).Should(Equal(obj.GetNamespacedName()))
-			}
+		It("sets the SyncWindowOpen condition", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance).To(testutils.WithGitTrackStatusConditions(ContainElement(SatisfyAll(
+				testutils.WithGitTrackConditionType(Equal(farosv1alpha1.SyncWindowOpenType)),
+				testutils.WithGitTrackConditionStatus(Equal(v1.ConditionFalse)),
+				testutils.WithGitTrackConditionReason(Equal(string(gittrackutils.OutsideSyncWindow))),
+			))))
 		})
-	})
-})
 
-var getsFilesFromRepo = func(path string, count int) {
-	Context(fmt.Sprintf("With subPath %s", path), func() {
-		var files map[string]*gitstore.File
-		var gt *farosv1alpha1.GitTrack
+		It("reports the changes as pending", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance).To(testutils.WithGitTrackStatusConditions(ContainElement(SatisfyAll(
+				testutils.WithGitTrackConditionType(Equal(farosv1alpha1.ChildrenUpToDateType)),
+				testutils.WithGitTrackConditionReason(Equal(string(gittrackutils.ChangesPending))),
+			))))
+			Expect(instance.Status.ChildrenOutOfSync).To(HaveLen(2))
+			Expect(instance.Status.ChildrenOutOfSync[0].Reason).To(Equal("creation pending"))
+		})
+
+		It("does not create the children", func() {
+			gto := &farosv1alpha1.GitTrackObject{}
+			err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+			Expect(err).To(HaveOccurred())
+		})
+
+		Context("and the sync windows are removed", func() {
+			BeforeEach(func() {
+				Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+				instance.Spec.SyncWindows = nil
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+			})
+
+			It("syncs the repository and removes the SyncWindowOpen condition", func() {
+				Eventually(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				}, timeout).Should(Succeed())
+				Eventually(func() *farosv1alpha1.GitTrackCondition {
+					Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+					return gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.SyncWindowOpenType)
+				}, timeout).Should(BeNil())
+			})
+		})
+	})
 
+	Context("When a GitTrack requires approval", func() {
 		BeforeEach(func() {
-			var err error
-			var reconciler *ReconcileGitTrack
-			var ok bool
-			reconciler, ok = r.(*ReconcileGitTrack)
-			Expect(ok).To(BeTrue())
-			gt = &farosv1alpha1.GitTrack{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      "test",
-					Namespace: "default",
-				},
-				Spec: farosv1alpha1.GitTrackSpec{
-					SubPath:    path,
-					Repository: repositoryURL,
-					DeployKey:  farosv1alpha1.GitTrackDeployKey{},
-					Reference:  "51798af1c1374d1d375a0eb7a3e53dd67ac5d135",
-				},
-			}
+			instance.Spec.RequireApproval = true
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
 
-			Expect(c.Create(context.TODO(), gt)).NotTo(HaveOccurred())
-			req := reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      "test",
-					Namespace: "default",
-				},
-			}
-			Eventually(requests, timeout).Should(Receive(Equal(req)))
+		It("reports the plan in the status", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.PendingPlan).NotTo(BeNil())
+			Expect(instance.Status.PendingPlan.ID).NotTo(BeEmpty())
+			Expect(instance.Status.PendingPlan.Reference).To(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+			Expect(instance.Status.PendingPlan.Creations).To(ConsistOf("default/deployment-nginx", "default/service-nginx"))
+			Expect(instance).To(testutils.WithGitTrackStatusConditions(ContainElement(SatisfyAll(
+				testutils.WithGitTrackConditionType(Equal(farosv1alpha1.ChildrenUpToDateType)),
+				testutils.WithGitTrackConditionReason(Equal(string(gittrackutils.ApprovalPending))),
+			))))
+		})
 
-			files, err = reconciler.getFiles(gt)
-			Expect(err).ToNot(HaveOccurred())
+		It("does not create the children", func() {
+			gto := &farosv1alpha1.GitTrackObject{}
+			err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+			Expect(err).To(HaveOccurred())
 		})
 
-		AfterEach(func() {
-			Expect(c.Delete(context.TODO(), gt)).NotTo(HaveOccurred())
+		Context("and the plan is approved", func() {
+			BeforeEach(func() {
+				Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+				Expect(instance.Status.PendingPlan).NotTo(BeNil())
+				instance.SetAnnotations(map[string]string{approvePlanAnnotation: instance.Status.PendingPlan.ID})
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+			})
+
+			It("creates the children and clears the plan", func() {
+				Eventually(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				}, timeout).Should(Succeed())
+				Eventually(func() *farosv1alpha1.PendingPlan {
+					Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+					return instance.Status.PendingPlan
+				}, timeout).Should(BeNil())
+			})
 		})
+	})
 
-		It("Filters files by SubPath", func() {
-			for filePath := range files {
-				Expect(filePath).To(HavePrefix(strings.TrimPrefix(path, "/")))
+	Context("When a GitTrack rolls changes out to canary children", func() {
+		var ingressKey = types.NamespacedName{Name: "ingress-example", Namespace: "default"}
+
+		var setCanaryHealth = func(status v1.ConditionStatus, reason string) {
+			gto := &farosv1alpha1.GitTrackObject{}
+			Eventually(func() error { return c.Get(context.TODO(), ingressKey, gto) }, timeout).Should(Succeed())
+			now := metav1.NewTime(time.Now())
+			gto.Status.ObservedGeneration = gto.GetGeneration()
+			gto.Status.Conditions = []farosv1alpha1.GitTrackObjectCondition{
+				{
+					Type:               farosv1alpha1.ChildHealthyType,
+					Status:             status,
+					Reason:             reason,
+					LastTransitionTime: now,
+					LastUpdateTime:     now,
+					ObservedGeneration: gto.GetGeneration(),
+				},
 			}
-		})
+			Expect(c.Status().Update(context.TODO(), gto)).To(Succeed())
+		}
 
-		It("Filters files by file extension", func() {
-			for filePath := range files {
-				Expect(filePath).To(MatchRegexp(filePathRegexp))
+		BeforeEach(func() {
+			// Only the Ingress is unlabelled
+			instance.Spec.Canary = &farosv1alpha1.GitTrackCanary{
+				Selector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: metav1.LabelSelectorOpDoesNotExist},
+					},
+				},
 			}
+			createInstance(instance, "09d24c51c191b4caacd35cda23bd44c86f16edc6")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
 		})
 
-		It("Fetches all files recursively from the SubPath", func() {
-			Expect(files).To(HaveLen(count))
+		It("creates the canary children first", func() {
+			Eventually(func() error { return c.Get(context.TODO(), ingressKey, &farosv1alpha1.GitTrackObject{}) }, timeout).Should(Succeed())
+			err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reports the rest of the changes as pending", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance).To(testutils.WithGitTrackStatusConditions(ContainElement(SatisfyAll(
+				testutils.WithGitTrackConditionType(Equal(farosv1alpha1.ChildrenUpToDateType)),
+				testutils.WithGitTrackConditionReason(Equal(string(gittrackutils.CanaryInProgress))),
+			))))
+			Expect(instance.Status.ChildrenOutOfSync).To(ContainElement(farosv1alpha1.GitTrackChildStatus{
+				Name:   "default/deployment-nginx",
+				Kind:   "Deployment",
+				Reason: "creation pending",
+				File:   "deployment.yaml",
+			}))
+		})
+
+		Context("and the canary children become healthy", func() {
+			BeforeEach(func() {
+				setCanaryHealth(v1.ConditionTrue, "ChildReady")
+			})
+
+			It("promotes the changes to the rest of the children", func() {
+				Eventually(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				}, timeout).Should(Succeed())
+				Eventually(func() error {
+					err := c.Get(context.TODO(), key, instance)
+					if err != nil {
+						return err
+					}
+					cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.ChildrenUpToDateType)
+					if cond == nil || cond.Reason != string(gittrackutils.ChildrenUpdateSuccess) {
+						return fmt.Errorf("changes not promoted yet")
+					}
+					return nil
+				}, timeout).Should(Succeed())
+			})
+		})
+
+		Context("and the canary children fail", func() {
+			BeforeEach(func() {
+				setCanaryHealth(v1.ConditionFalse, "ChildFailed")
+			})
+
+			It("aborts the rollout", func() {
+				Eventually(func() error {
+					err := c.Get(context.TODO(), key, instance)
+					if err != nil {
+						return err
+					}
+					cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.ChildrenUpToDateType)
+					if cond == nil || cond.Reason != string(gittrackutils.CanaryFailed) {
+						return fmt.Errorf("rollout not aborted yet")
+					}
+					return nil
+				}, timeout).Should(Succeed())
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, &farosv1alpha1.GitTrackObject{})
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("sends a CanaryFailed event", func() {
+				events := &v1.EventList{}
+				Eventually(func() ([]v1.Event, error) {
+					err := c.List(context.TODO(), events)
+					return testevents.Select(events.Items, reasonFilter("CanaryFailed")), err
+				}, timeout).ShouldNot(BeEmpty())
+			})
+		})
+	})
+
+	Context("When a GitTrack is rolled back", func() {
+		var deployKey = types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}
+		var revisionName string
+
+		BeforeEach(func() {
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+
+			revisions := &farosv1alpha1.GitTrackRevisionList{}
+			Eventually(func() ([]farosv1alpha1.GitTrackRevision, error) {
+				err := c.List(context.TODO(), revisions, client.InNamespace(instance.Namespace))
+				return revisions.Items, err
+			}, timeout).Should(HaveLen(1))
+			revisionName = revisions.Items[0].Name
+
+			// Scale the deployment to 5 replicas
+			Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+			instance.Spec.Reference = "448b39a21d285fcb5aa4b718b27a3e13ffc649b3"
+			Expect(c.Update(context.TODO(), instance)).To(Succeed())
+			Eventually(func() (string, error) {
+				err := c.Get(context.TODO(), key, instance)
+				return instance.Status.LastSyncedReference, err
+			}, timeout).Should(Equal("448b39a21d285fcb5aa4b718b27a3e13ffc649b3"))
+		})
+
+		Context("to a revision of the GitTrack", func() {
+			BeforeEach(func() {
+				Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+				instance.Spec.RollbackTo = revisionName
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+			})
+
+			It("syncs the commit of the revision", func() {
+				Eventually(func() (string, error) {
+					err := c.Get(context.TODO(), key, instance)
+					return instance.Status.LastSyncedReference, err
+				}, timeout).Should(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+				Expect(instance.Status.Rollback).To(Equal(&farosv1alpha1.GitTrackRollback{
+					Revision: revisionName,
+					Commit:   "a14443638218c782b84cae56a14f1090ee9e5c9c",
+				}))
+
+				gto := &farosv1alpha1.GitTrackObject{}
+				Expect(c.Get(context.TODO(), deployKey, gto)).To(Succeed())
+				Expect(string(gto.Spec.Data)).To(ContainSubstring(`"replicas":3`))
+			})
+
+			Context("and the rollback is removed", func() {
+				BeforeEach(func() {
+					Eventually(func() *farosv1alpha1.GitTrackRollback {
+						Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+						return instance.Status.Rollback
+					}, timeout).ShouldNot(BeNil())
+					instance.Spec.RollbackTo = ""
+					Expect(c.Update(context.TODO(), instance)).To(Succeed())
+				})
+
+				It("syncs the reference again", func() {
+					Eventually(func() (string, error) {
+						err := c.Get(context.TODO(), key, instance)
+						return instance.Status.LastSyncedReference, err
+					}, timeout).Should(Equal("448b39a21d285fcb5aa4b718b27a3e13ffc649b3"))
+					Expect(instance.Status.Rollback).To(BeNil())
+				})
+			})
+		})
+
+		Context("to a revision that does not exist", func() {
+			BeforeEach(func() {
+				Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+				instance.Spec.RollbackTo = "does-not-exist"
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+			})
+
+			It("sets the FilesFetched condition", func() {
+				Eventually(func() error {
+					err := c.Get(context.TODO(), key, instance)
+					if err != nil {
+						return err
+					}
+					cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.FilesFetchedType)
+					if cond == nil || cond.Reason != string(gittrackutils.InvalidRollback) {
+						return fmt.Errorf("rollback not rejected yet")
+					}
+					return nil
+				}, timeout).Should(Succeed())
+				Expect(instance.Status.LastSyncedReference).To(Equal("448b39a21d285fcb5aa4b718b27a3e13ffc649b3"))
+			})
+		})
+	})
+
+	Context("When a GitTrack rolls back automatically", func() {
+		var setHealth = func(name string, status v1.ConditionStatus, reason string) {
+			gto := &farosv1alpha1.GitTrackObject{}
+			Eventually(func() error {
+				return c.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: "default"}, gto)
+			}, timeout).Should(Succeed())
+			now := metav1.NewTime(time.Now())
+			gto.Status.Conditions = []farosv1alpha1.GitTrackObjectCondition{
+				{
+					Type:               farosv1alpha1.ChildHealthyType,
+					Status:             status,
+					Reason:             reason,
+					LastTransitionTime: now,
+					LastUpdateTime:     now,
+				},
+			}
+			Expect(c.Status().Update(context.TODO(), gto)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			instance.Spec.AutoRollback = &farosv1alpha1.GitTrackAutoRollback{
+				Timeout: metav1.Duration{Duration: time.Second},
+			}
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+
+			setHealth("deployment-nginx", v1.ConditionTrue, "ChildReady")
+			setHealth("service-nginx", v1.ConditionTrue, "ChildReady")
+			Eventually(func() (*farosv1alpha1.GitTrackRollback, error) {
+				err := c.Get(context.TODO(), key, instance)
+				return instance.Status.LastHealthyRevision, err
+			}, timeout).ShouldNot(BeNil())
+			Expect(instance.Status.LastHealthyRevision.Commit).To(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+		})
+
+		Context("and the children fail after a new commit", func() {
+			var lastHealthy string
+
+			BeforeEach(func() {
+				lastHealthy = instance.Status.LastHealthyRevision.Revision
+				instance.Spec.Reference = "448b39a21d285fcb5aa4b718b27a3e13ffc649b3"
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+				Eventually(func() (string, error) {
+					err := c.Get(context.TODO(), key, instance)
+					return instance.Status.LastSyncedReference, err
+				}, timeout).Should(Equal("448b39a21d285fcb5aa4b718b27a3e13ffc649b3"))
+				setHealth("deployment-nginx", v1.ConditionFalse, "ChildFailed")
+			})
+
+			It("rolls back to the last healthy revision", func() {
+				Eventually(func() (string, error) {
+					err := c.Get(context.TODO(), key, instance)
+					return instance.Spec.RollbackTo, err
+				}, timeout).Should(Equal(lastHealthy))
+				Eventually(func() (string, error) {
+					err := c.Get(context.TODO(), key, instance)
+					return instance.Status.LastSyncedReference, err
+				}, timeout).Should(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+				Expect(instance.Status.Rollback).NotTo(BeNil())
+				Expect(instance.Status.Rollback.Reason).To(ContainSubstring("children unhealthy"))
+			})
+
+			It("sends an AutoRollback event", func() {
+				events := &v1.EventList{}
+				Eventually(func() ([]v1.Event, error) {
+					err := c.List(context.TODO(), events)
+					return testevents.Select(events.Items, reasonFilter("AutoRollback")), err
+				}, timeout).ShouldNot(BeEmpty())
+			})
+		})
+	})
+
+	Context("When a GitTrack applies its children to another cluster", func() {
+		BeforeEach(func() {
+			instance.Spec.KubeConfigSecretRef = &farosv1alpha1.GitTrackSecretReference{SecretName: "remote-kubeconfig", Key: "config"}
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("points the GitTrackObjects at the kubeconfig", func() {
+			gto := &farosv1alpha1.GitTrackObject{}
+			Eventually(func() error {
+				return c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+			}, timeout).Should(Succeed())
+			Expect(gto.Spec.KubeConfigSecretRef).To(Equal(&farosv1alpha1.KubeConfigSecretReference{
+				Namespace:  "default",
+				SecretName: "remote-kubeconfig",
+				Key:        "config",
+			}))
+		})
+	})
+
+	Context("When a GitTrack selects the clusters to apply its children to", func() {
+		BeforeEach(func() {
+			for name, env := range map[string]string{"staging-a": "staging", "staging-b": "staging", "production": "production"} {
+				cluster := &farosv1alpha1.FarosCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"env": env}},
+					Spec: farosv1alpha1.FarosClusterSpec{
+						KubeConfigSecretRef: farosv1alpha1.GitTrackSecretReference{SecretName: name + "-kubeconfig", Key: "config"},
+					},
+				}
+				Expect(c.Create(context.TODO(), cluster)).To(Succeed())
+			}
+			instance.Spec.ClusterSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"env": "staging"}}
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		AfterEach(func() {
+			testutils.DeleteAll(cfg, timeout, &farosv1alpha1.FarosClusterList{})
+		})
+
+		It("creates GitTrackObjects for each selected cluster", func() {
+			for _, cluster := range []string{"staging-a", "staging-b"} {
+				gto := &farosv1alpha1.GitTrackObject{}
+				Eventually(func() error {
+					return c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx." + cluster, Namespace: "default"}, gto)
+				}, timeout).Should(Succeed())
+				Expect(gto.Spec.KubeConfigSecretRef).To(Equal(&farosv1alpha1.KubeConfigSecretReference{
+					Namespace:  "default",
+					SecretName: cluster + "-kubeconfig",
+					Key:        "config",
+				}))
+				Expect(gto.GetLabels()).To(HaveKeyWithValue(gittrackutils.ClusterLabel, cluster))
+			}
+		})
+
+		It("does not create GitTrackObjects for other clusters", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			gto := &farosv1alpha1.GitTrackObject{}
+			err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx.production", Namespace: "default"}, gto)
+			Expect(err).To(HaveOccurred())
+			err = c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("reports the status of each cluster", func() {
+			Eventually(func() []farosv1alpha1.GitTrackClusterStatus {
+				c.Get(context.TODO(), key, instance)
+				return instance.Status.Clusters
+			}, timeout).Should(HaveLen(2))
+			Expect(instance.Status.ObjectsDiscovered).To(Equal(int64(2)))
+			Expect(instance.Status.ObjectsApplied).To(Equal(int64(4)))
+			Expect(instance.Status.Clusters[0].Name).To(Equal("staging-a"))
+			Expect(instance.Status.Clusters[0].ObjectsApplied).To(Equal(int64(2)))
+			Expect(instance.Status.Clusters[1].Name).To(Equal("staging-b"))
+			Expect(instance.Status.Clusters[1].ObjectsApplied).To(Equal(int64(2)))
+		})
+	})
+
+	Context("When a GitTrack sets the deletion propagation", func() {
+		BeforeEach(func() {
+			instance.Spec.DeletionPropagation = metav1.DeletePropagationBackground
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("sets the deletion propagation of the GitTrackObjects", func() {
+			deployGto := &farosv1alpha1.GitTrackObject{}
+			Eventually(func() error {
+				return c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, deployGto)
+			}, timeout).Should(Succeed())
+			Expect(deployGto.Spec.DeletionPropagation).To(Equal(metav1.DeletePropagationBackground))
+		})
+	})
+
+	Context("When a GitTrack restricts its allowed namespaces", func() {
+		Context("and the manifests target an allowed namespace", func() {
+			BeforeEach(func() {
+				instance.Spec.AllowedNamespaces = []string{"default"}
+				createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+				// Wait for client cache to expire
+				waitForInstanceCreated(key)
+			})
+
+			It("applies the manifests", func() {
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+				Expect(instance.Status.ObjectsApplied).To(Equal(int64(2)))
+			})
+		})
+
+		Context("and the manifests target another namespace", func() {
+			BeforeEach(func() {
+				instance.Spec.AllowedNamespaces = []string{"team-a", "team-b"}
+				createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+				// Wait for client cache to expire
+				waitForInstanceCreated(key)
+			})
+
+			It("rejects the GitTrack", func() {
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+				cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.FilesParsedType)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(v1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(string(gittrackutils.NamespaceNotAllowed)))
+				Expect(cond.Message).To(ContainSubstring("outside of team-a, team-b: Deployment default/nginx"))
+				Expect(instance.Status.ObjectsApplied).To(Equal(int64(0)))
+			})
+
+			It("does not create any GitTrackObjects", func() {
+				gto := &farosv1alpha1.GitTrackObject{}
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("sends a NamespaceNotAllowed event", func() {
+				events := &v1.EventList{}
+				Eventually(func() error { return c.List(context.TODO(), events) }, timeout).Should(Succeed())
+				rejectedEvents := testevents.Select(events.Items, reasonFilter("NamespaceNotAllowed"))
+				Expect(rejectedEvents).ToNot(BeEmpty())
+				for _, e := range rejectedEvents {
+					Expect(e.InvolvedObject.Kind).To(Equal("GitTrack"))
+					Expect(e.Type).To(Equal(string(v1.EventTypeWarning)))
+				}
+			})
+		})
+	})
+
+	Context("When a GitTrack restricts its allowed kinds", func() {
+		Context("and the manifests are of allowed kinds", func() {
+			BeforeEach(func() {
+				instance.Spec.AllowedKinds = []farosv1alpha1.GitTrackAllowedKind{
+					{Group: "apps", Kind: "Deployment"},
+					{Kind: "Service"},
+				}
+				createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+				// Wait for client cache to expire
+				waitForInstanceCreated(key)
+			})
+
+			It("applies the manifests", func() {
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+				Expect(instance.Status.ObjectsApplied).To(Equal(int64(2)))
+			})
+		})
+
+		Context("and the manifests are of other kinds", func() {
+			BeforeEach(func() {
+				instance.Spec.AllowedKinds = []farosv1alpha1.GitTrackAllowedKind{
+					{Group: "apps", Kind: "Deployment"},
+					{Kind: "ConfigMap"},
+				}
+				createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+				// Wait for client cache to expire
+				waitForInstanceCreated(key)
+			})
+
+			It("rejects the GitTrack", func() {
+				Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+				cond := gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.FilesParsedType)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(v1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(string(gittrackutils.KindNotAllowed)))
+				Expect(cond.Message).To(Equal("1 objects are not of the kinds Deployment.apps, ConfigMap: Service default/nginx"))
+				Expect(instance.Status.ObjectsApplied).To(Equal(int64(0)))
+			})
+
+			It("does not create any GitTrackObjects", func() {
+				gto := &farosv1alpha1.GitTrackObject{}
+				err := c.Get(context.TODO(), types.NamespacedName{Name: "deployment-nginx", Namespace: "default"}, gto)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Context("When a GitTrack checks its children against admission policies", func() {
+		BeforeEach(func() {
+			instance.Spec.PolicyCheck = farosv1alpha1.PolicyCheckEnforce
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("sets the PolicyCompliant condition", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance).To(testutils.WithGitTrackStatusConditions(ContainElement(SatisfyAll(
+				testutils.WithGitTrackConditionType(Equal(farosv1alpha1.PolicyCompliantType)),
+				testutils.WithGitTrackConditionStatus(Equal(v1.ConditionTrue)),
+				testutils.WithGitTrackConditionReason(Equal(string(gittrackutils.PolicyCheckPassed))),
+			))))
+			Expect(instance.Status.PolicyViolations).To(BeEmpty())
+		})
+
+		It("applies the children that comply", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.ObjectsApplied).To(Equal(int64(2)))
+		})
+
+		Context("and the policy check is removed", func() {
+			BeforeEach(func() {
+				Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+				instance.Spec.PolicyCheck = ""
+				Expect(c.Update(context.TODO(), instance)).To(Succeed())
+			})
+
+			It("removes the PolicyCompliant condition", func() {
+				Eventually(func() *farosv1alpha1.GitTrackCondition {
+					Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+					return gittrackutils.GetGitTrackCondition(instance.Status, farosv1alpha1.PolicyCompliantType)
+				}, timeout).Should(BeNil())
+			})
+		})
+	})
+
+	Context("When a list of allowed GVRs is supplied", func() {
+		BeforeEach(func() {
+			reconciler, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler.allowedGVRs = map[schema.GroupVersionResource]interface{}{
+				{Group: "apps", Version: "v1", Resource: "deployments"}: nil,
+			}
+
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("only applies the allowed resources", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.ObjectsApplied).To(Equal(int64(1)))
+			Expect(instance.Status.ObjectsIgnored).To(Equal(int64(1)))
+			Expect(instance.Status.IgnoredFiles).NotTo(HaveKey("default/deployment-nginx"))
+			for _, reason := range instance.Status.IgnoredFiles {
+				Expect(reason).To(HaveSuffix("is not an allowed resource"))
+			}
+		})
+	})
+
+	Context("When children are upserted by a single worker", func() {
+		BeforeEach(func() {
+			reconciler, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler.upsertWorkers = 1
+
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("applies all of the children", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.ObjectsApplied).To(Equal(int64(2)))
+		})
+
+		It("records the file each out of sync child was read from", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.ChildrenOutOfSync).NotTo(BeEmpty())
+			for _, child := range instance.Status.ChildrenOutOfSync {
+				Expect(child.File).NotTo(BeEmpty())
+			}
+		})
+	})
+
+	Context("When ignored resources are listed in a ConfigMap", func() {
+		var cm *v1.ConfigMap
+
+		BeforeEach(func() {
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "faros-ignored-resources",
+					Namespace: "default",
+				},
+				Data: map[string]string{
+					"resources": "# Deployments are managed elsewhere\ndeployments.apps/v1\n",
+				},
+			}
+			Expect(c.Create(context.TODO(), cm)).To(Succeed())
+
+			reconciler, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler.ignoredResourcesConfigMap = &types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}
+
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		AfterEach(func() {
+			Expect(c.Delete(context.TODO(), cm)).To(Succeed())
+		})
+
+		It("adds a message to the ignoredFiles status", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.IgnoredFiles).To(HaveKeyWithValue("default/deployment-nginx", "resource `deployments.apps/v1` ignored globally by ConfigMap `default/faros-ignored-resources`"))
+			Expect(instance.Status.ObjectsIgnored).To(Equal(int64(1)))
+		})
+	})
+
+	Context("When the ignored resources ConfigMap does not exist", func() {
+		BeforeEach(func() {
+			reconciler, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler.ignoredResourcesConfigMap = &types.NamespacedName{Name: "does-not-exist", Namespace: "default"}
+
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("sends an IgnoredResourcesNotFound event", func() {
+			events := &v1.EventList{}
+			Eventually(func() error { return c.List(context.TODO(), events) }, timeout).Should(Succeed())
+			notFoundEvents := testevents.Select(events.Items, reasonFilter("IgnoredResourcesNotFound"))
+			Expect(notFoundEvents).ToNot(BeEmpty())
+			for _, e := range notFoundEvents {
+				Expect(e.InvolvedObject.Kind).To(Equal("GitTrack"))
+				Expect(e.Type).To(Equal(string(v1.EventTypeWarning)))
+			}
+		})
+
+		It("still syncs the children", func() {
+			Eventually(func() error { return c.Get(context.TODO(), key, instance) }, timeout).Should(Succeed())
+			Expect(instance.Status.ObjectsIgnored).To(Equal(int64(0)))
+		})
+	})
+
+	Context("secretRequests", func() {
+		var secret *v1.Secret
+		var mapSecret = func() []reconcile.Request {
+			return secretRequests(c, labels.Everything(), rlogr.Log)(handler.MapObject{Meta: secret, Object: secret})
+		}
+
+		BeforeEach(func() {
+			secret = &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "deploy-key", Namespace: "default"}}
+			instance.Spec.Reference = "master"
+			instance.Spec.DeployKey = farosv1alpha1.GitTrackDeployKey{SecretName: "deploy-key", Key: "id_rsa"}
+			Expect(c.Create(context.TODO(), instance)).To(Succeed())
+		})
+
+		It("enqueues the GitTracks referencing the Secret", func() {
+			Eventually(mapSecret, timeout).Should(ConsistOf(expectedRequest))
+		})
+
+		It("does not enqueue GitTracks referencing other Secrets", func() {
+			secret.SetName("other")
+			Consistently(mapSecret).Should(BeEmpty())
+		})
+
+		It("does not enqueue GitTracks in other namespaces", func() {
+			secret.SetNamespace("other")
+			Consistently(mapSecret).Should(BeEmpty())
+		})
+	})
+
+	Context("notify", func() {
+		var reconciler ReconcileGitTrack
+		var notifier *fakeNotifier
+		var gt *farosv1alpha1.GitTrack
+		var opts *statusOpts
+
+		BeforeEach(func() {
+			notifier = &fakeNotifier{}
+			reconciler = *r.(*ReconcileGitTrack)
+			reconciler.notifier = notifier
+
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec:       farosv1alpha1.GitTrackSpec{Repository: "https://github.com/pusher/faros", Reference: "master"},
+			}
+			opts = newStatusOpts()
+			opts.gitReason = gittrackutils.GitFetchSuccess
+			opts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
+			opts.gcReason = gittrackutils.GCSuccess
+		})
+
+		It("notifies when a stage starts failing", func() {
+			opts.gitError = errors.New("unable to fetch")
+			opts.gitReason = gittrackutils.ErrorFetchingFiles
+			Expect(reconciler.notify(gt, opts)).To(Succeed())
+			Expect(notifier.sent).To(HaveLen(1))
+			Expect(notifier.sent[0].Stage).To(Equal(notifications.FetchStage))
+			Expect(notifier.sent[0].Recovered).To(BeFalse())
+			Expect(notifier.sent[0].Message).To(Equal("unable to fetch"))
+		})
+
+		It("does not notify again while the same stage is failing", func() {
+			setCondition(&gt.Status, farosv1alpha1.ChildrenUpToDateType, errors.New("unable to apply"), gittrackutils.ErrorUpdatingChildren)
+			opts.upToDateError = errors.New("unable to apply")
+			opts.upToDateReason = gittrackutils.ErrorUpdatingChildren
+			Expect(reconciler.notify(gt, opts)).To(Succeed())
+			Expect(notifier.sent).To(BeEmpty())
+		})
+
+		It("notifies when a failed GitTrack recovers", func() {
+			setCondition(&gt.Status, farosv1alpha1.FilesParsedType, errors.New("unable to parse"), gittrackutils.ErrorParsingFiles)
+			Expect(reconciler.notify(gt, opts)).To(Succeed())
+			Expect(notifier.sent).To(HaveLen(1))
+			Expect(notifier.sent[0].Stage).To(Equal(notifications.ParseStage))
+			Expect(notifier.sent[0].Recovered).To(BeTrue())
+		})
+
+		It("does not notify while a GitTrack stays healthy", func() {
+			Expect(reconciler.notify(gt, opts)).To(Succeed())
+			Expect(notifier.sent).To(BeEmpty())
+		})
+
+		Context("with a FarosAlert", func() {
+			var alert *farosv1alpha1.FarosAlert
+			var n notifications.Notification
+
+			BeforeEach(func() {
+				gt.SetLabels(map[string]string{"team": "platform"})
+				alert = &farosv1alpha1.FarosAlert{
+					ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+					Spec: farosv1alpha1.FarosAlertSpec{
+						ProviderRef: "example",
+						GitTrackSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"team": "platform"},
+						},
+					},
+				}
+				n = notifications.Notification{Namespace: "default", Name: "example", Stage: notifications.ApplyStage}
+			})
+
+			It("matches GitTracks selected by the alert", func() {
+				Expect(alertMatches(alert, gt, n)).To(BeTrue())
+				gt.SetLabels(map[string]string{"team": "other"})
+				Expect(alertMatches(alert, gt, n)).To(BeFalse())
+			})
+
+			It("matches all GitTracks without a selector", func() {
+				alert.Spec.GitTrackSelector = nil
+				gt.SetLabels(nil)
+				Expect(alertMatches(alert, gt, n)).To(BeTrue())
+			})
+
+			It("filters notifications below the alert's severity", func() {
+				alert.Spec.Severity = farosv1alpha1.AlertSeverityError
+				Expect(alertMatches(alert, gt, n)).To(BeTrue())
+				n.Recovered = true
+				Expect(alertMatches(alert, gt, n)).To(BeFalse())
+			})
+
+			It("does not match while suspended", func() {
+				alert.Spec.Suspend = true
+				Expect(alertMatches(alert, gt, n)).To(BeFalse())
+			})
+
+			It("routes the notification to the alert's provider", func() {
+				provider := &farosv1alpha1.FarosProvider{
+					ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+					Spec: farosv1alpha1.FarosProviderSpec{
+						Type:    farosv1alpha1.ProviderTypeWebhook,
+						Address: "https://example.com/faros",
+					},
+				}
+				Expect(c.Create(context.TODO(), provider)).To(Succeed())
+				Expect(c.Create(context.TODO(), alert)).To(Succeed())
+
+				Eventually(func() ([]notifications.Notifier, error) {
+					return reconciler.alertNotifiers(gt, n)
+				}, timeout).Should(ConsistOf(&notifications.Webhook{URL: "https://example.com/faros"}))
+			})
+		})
+	})
+
+	Context("commitSyncReport", func() {
+		var reconciler ReconcileGitTrack
+		var gt *farosv1alpha1.GitTrack
+		var sOpts *statusOpts
+		var repo billy.Filesystem
+		var commits []gitwrite.Options
+
+		BeforeEach(func() {
+			shared, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler = *shared
+			reconciler.syncReports = make(map[string]string)
+			repo = memfs.New()
+			commits = []gitwrite.Options{}
+			reconciler.commitFiles = func(opts gitwrite.Options, edit gitwrite.EditFunc) (string, error) {
+				if _, err := edit(repo); err != nil {
+					return "", err
+				}
+				commits = append(commits, opts)
+				return "abc123", nil
+			}
+
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec: farosv1alpha1.GitTrackSpec{
+					Repository: "https://github.com/pusher/faros",
+					Reference:  "master",
+					SyncReport: &farosv1alpha1.GitTrackSyncReport{Branch: "faros-reports"},
+				},
+				Status: farosv1alpha1.GitTrackStatus{LastSyncedReference: "master"},
+			}
+			sOpts = newStatusOpts()
+			sOpts.gitReason = gittrackutils.GitFetchSuccess
+			sOpts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
+			sOpts.gcReason = gittrackutils.GCSuccess
+		})
+
+		It("commits the report to the branch", func() {
+			Expect(reconciler.commitSyncReport(gt, sOpts, &revisionOpts{})).To(Succeed())
+			Expect(commits).To(HaveLen(1))
+			Expect(commits[0].Branch).To(Equal("faros-reports"))
+
+			data, err := gitwrite.ReadFile(repo, ".faros/reports/default/example.json")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"result": "Succeeded"`))
+		})
+
+		It("does not commit the same result twice", func() {
+			Expect(reconciler.commitSyncReport(gt, sOpts, &revisionOpts{})).To(Succeed())
+			Expect(reconciler.commitSyncReport(gt, sOpts, &revisionOpts{})).To(Succeed())
+			Expect(commits).To(HaveLen(1))
+		})
+
+		It("commits again when the sync changes a child", func() {
+			Expect(reconciler.commitSyncReport(gt, sOpts, &revisionOpts{})).To(Succeed())
+			rOpts := &revisionOpts{children: []farosv1alpha1.GitTrackRevisionChild{
+				{Name: "deployment-nginx", Result: farosv1alpha1.ChildApplyResultUpdated},
+			}}
+			Expect(reconciler.commitSyncReport(gt, sOpts, rOpts)).To(Succeed())
+			Expect(commits).To(HaveLen(2))
+		})
+
+		It("does not commit if the repository could not be fetched", func() {
+			sOpts.gitError = errors.New("unable to fetch")
+			sOpts.gitReason = gittrackutils.ErrorFetchingFiles
+			Expect(reconciler.commitSyncReport(gt, sOpts, &revisionOpts{})).To(Succeed())
+			Expect(commits).To(BeEmpty())
+		})
+	})
+
+	Context("verifyFiles", func() {
+		var reconciler ReconcileGitTrack
+		var gt *farosv1alpha1.GitTrack
+		var files map[string]*gitstore.File
+		var secret *v1.Secret
+
+		BeforeEach(func() {
+			shared, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler = *shared
+
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec: farosv1alpha1.GitTrackSpec{
+					Repository: repositoryURL,
+					Reference:  "51798af1c1374d1d375a0eb7a3e53dd67ac5d135",
+					SubPath:    "foo",
+					Verification: &farosv1alpha1.GitTrackVerification{
+						PublicKeys: &farosv1alpha1.GitTrackSecretReference{SecretName: "trusted-keys", Key: "cosign.pub"},
+					},
+				},
+			}
+			var err error
+			files, err = reconciler.getFiles(gt, gt.Spec.Reference)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, publicKey := newSigningKey()
+			secret = &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "trusted-keys", Namespace: "default"},
+				Data:       map[string][]byte{"cosign.pub": publicKey},
+			}
+			Expect(c.Create(context.TODO(), secret)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			testutils.DeleteAll(cfg, timeout, &v1.SecretList{})
+		})
+
+		It("does nothing if verification is not configured", func() {
+			gt.Spec.Verification = nil
+			Expect(reconciler.verifyFiles(gt, gt.Spec.Reference, files)).To(Succeed())
+		})
+
+		It("refuses manifests without signatures", func() {
+			err := reconciler.verifyFiles(gt, gt.Spec.Reference, files)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("foo/deployment.yaml: not signed"))
+		})
+
+		It("returns an error if the keys secret does not exist", func() {
+			gt.Spec.Verification.PublicKeys.SecretName = "does-not-exist"
+			err := reconciler.verifyFiles(gt, gt.Spec.Reference, files)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does-not-exist"))
+		})
+
+		It("returns an error if no keys or identities are trusted", func() {
+			gt.Spec.Verification = &farosv1alpha1.GitTrackVerification{}
+			Expect(reconciler.verifyFiles(gt, gt.Spec.Reference, files)).NotTo(Succeed())
+		})
+	})
+
+	Context("backupManifests", func() {
+		var reconciler ReconcileGitTrack
+		var store *fakeBackupStore
+		var gt *farosv1alpha1.GitTrack
+		var sOpts *statusOpts
+		var rOpts *revisionOpts
+
+		BeforeEach(func() {
+			reconciler = *r.(*ReconcileGitTrack)
+			store = &fakeBackupStore{written: make(map[string]string)}
+			reconciler.backups = store
+			reconciler.lastBackups = make(map[string]string)
+
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec: farosv1alpha1.GitTrackSpec{
+					Repository: "https://github.com/pusher/faros",
+					Reference:  "a14443638218c782b84cae56a14f1090ee9e5c9c",
+				},
+			}
+			sOpts = newStatusOpts()
+			sOpts.gitReason = gittrackutils.GitFetchSuccess
+			sOpts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
+			sOpts.gcReason = gittrackutils.GCSuccess
+
+			configMap := &unstructured.Unstructured{}
+			configMap.SetAPIVersion("v1")
+			configMap.SetKind("ConfigMap")
+			configMap.SetNamespace("default")
+			configMap.SetName("nginx")
+			rOpts = &revisionOpts{}
+			rOpts.setManifests(
+				[]*unstructured.Unstructured{configMap},
+				map[*unstructured.Unstructured]string{configMap: "configmap.yaml"},
+			)
+		})
+
+		It("writes the manifests keyed by the commit synced", func() {
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).To(Succeed())
+			Expect(store.written).To(HaveKey("default/example/a14443638218c782b84cae56a14f1090ee9e5c9c.yaml"))
+			data := store.written["default/example/a14443638218c782b84cae56a14f1090ee9e5c9c.yaml"]
+			Expect(data).To(HavePrefix("---\n# Source: configmap.yaml\n"))
+			Expect(data).To(ContainSubstring("name: nginx"))
+		})
+
+		It("keys the manifests by their hash when the reference is not a commit", func() {
+			gt.Spec.Reference = "master"
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).To(Succeed())
+			Expect(store.written).To(HaveLen(1))
+			for key := range store.written {
+				Expect(key).To(MatchRegexp("^default/example/sha256-[0-9a-f]{64}\\.yaml$"))
+			}
+		})
+
+		It("writes the manifests beneath the cluster's directory", func() {
+			farosflags.ClusterName = "production"
+			defer func() { farosflags.ClusterName = "" }()
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).To(Succeed())
+			Expect(store.written).To(HaveKey("production/default/example/a14443638218c782b84cae56a14f1090ee9e5c9c.yaml"))
+		})
+
+		It("does not write the same manifests twice", func() {
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).To(Succeed())
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).To(Succeed())
+			Expect(store.puts).To(Equal(1))
+		})
+
+		It("does not write the manifests of a failed sync", func() {
+			sOpts.upToDateError = errors.New("unable to update child")
+			sOpts.upToDateReason = gittrackutils.ErrorUpdatingChildren
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).To(Succeed())
+			Expect(store.written).To(BeEmpty())
+		})
+
+		It("writes the manifests again after a failed write", func() {
+			store.err = errors.New("access denied")
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).NotTo(Succeed())
+			store.err = nil
+			Expect(reconciler.backupManifests(gt, sOpts, rOpts)).To(Succeed())
+			Expect(store.written).To(HaveLen(1))
+		})
+	})
+
+	Context("storeData", func() {
+		var reconciler *ReconcileGitTrack
+		var gto farosv1alpha1.GitTrackObjectInterface
+		var data []byte
+
+		BeforeEach(func() {
+			reconciler = r.(*ReconcileGitTrack)
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			waitForInstanceCreated(key)
+			Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion("v1")
+			u.SetKind("ConfigMap")
+			u.SetNamespace("default")
+			u.SetName("dashboards")
+			u.SetAnnotations(map[string]string{retainAnnotation: "true"})
+			var err error
+			gto, err = render.GitTrackObject(u, true)
+			Expect(err).NotTo(HaveOccurred())
+			data = gto.GetSpec().Data
+
+			farosflags.ExternalDataThreshold = 10
+			Expect(reconciler.storeData(instance, gto, u)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			farosflags.ExternalDataThreshold = 512 * 1024
+			Expect(reconciler.deleteData(gto)).To(Succeed())
+		})
+
+		It("moves the data into a ConfigMap in the namespace of the GitTrack", func() {
+			spec := gto.GetSpec()
+			Expect(spec.Data).To(BeEmpty())
+			Expect(spec.DataFrom).NotTo(BeNil())
+			Expect(spec.DataFrom.Namespace).To(Equal(instance.Namespace))
+			Expect(spec.DataFrom.ConfigMaps).To(Equal([]string{dataConfigMapName(instance, gto, 0)}))
+		})
+
+		It("stores the data so that it can be read back", func() {
+			Expect(gittrackobjectutils.ReadData(reconciler.apiReader, gto.GetSpec())).To(Equal(data))
+		})
+
+		It("copies the retain annotation to the GitTrackObject", func() {
+			Expect(gto.GetAnnotations()).To(HaveKeyWithValue(retainAnnotation, "true"))
+		})
+
+		It("deletes ConfigMaps that are no longer referenced", func() {
+			current := gto.DeepCopyInterface()
+			spec := current.GetSpec()
+			spec.DataFrom = nil
+			current.SetSpec(spec)
+			Expect(reconciler.removeStaleData(gto, current)).To(Succeed())
+
+			cm := &v1.ConfigMap{}
+			err := reconciler.apiReader.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: gto.GetSpec().DataFrom.ConfigMaps[0]}, cm)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Context("fetchInstance with a GitTrack selector", func() {
+		var reconciler ReconcileGitTrack
+
+		BeforeEach(func() {
+			shared, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler = *shared
+			reconciler.selector = labels.SelectorFromSet(labels.Set{"shard": "a"})
+
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("ignores GitTracks not matching the selector", func() {
+			gt, err := reconciler.fetchInstance(expectedRequest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gt).To(BeNil())
+		})
+
+		It("returns GitTracks matching the selector", func() {
+			Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+			instance.SetLabels(map[string]string{"shard": "a"})
+			Expect(c.Update(context.TODO(), instance)).To(Succeed())
+
+			Eventually(func() (*farosv1alpha1.GitTrack, error) {
+				return reconciler.fetchInstance(expectedRequest)
+			}, timeout).ShouldNot(BeNil())
+		})
+	})
+
+	Context("fetchInstance with a priority", func() {
+		var reconciler *ReconcileGitTrack
+
+		BeforeEach(func() {
+			shared, ok := r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			reconciler = shared.withPriority(farosv1alpha1.GitTrackPriorityHigh)
+
+			createInstance(instance, "a14443638218c782b84cae56a14f1090ee9e5c9c")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+		})
+
+		It("ignores GitTracks of another priority", func() {
+			gt, err := reconciler.fetchInstance(expectedRequest)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gt).To(BeNil())
+		})
+
+		It("returns GitTracks of its priority", func() {
+			Expect(c.Get(context.TODO(), key, instance)).To(Succeed())
+			instance.Spec.Priority = farosv1alpha1.GitTrackPriorityHigh
+			Expect(c.Update(context.TODO(), instance)).To(Succeed())
+
+			Eventually(func() (*farosv1alpha1.GitTrack, error) {
+				return reconciler.fetchInstance(expectedRequest)
+			}, timeout).ShouldNot(BeNil())
+		})
+	})
+
+	Context("priorityPredicate", func() {
+		var gt *farosv1alpha1.GitTrack
+
+		BeforeEach(func() {
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			}
+		})
+
+		It("treats GitTracks without a priority as Normal", func() {
+			Expect(priorityPredicate{priority: farosv1alpha1.GitTrackPriorityNormal}.Create(event.CreateEvent{Object: gt})).To(BeTrue())
+			Expect(priorityPredicate{priority: farosv1alpha1.GitTrackPriorityHigh}.Create(event.CreateEvent{Object: gt})).To(BeFalse())
+		})
+
+		It("matches the priority of the updated GitTrack", func() {
+			high := gt.DeepCopy()
+			high.Spec.Priority = farosv1alpha1.GitTrackPriorityHigh
+			e := event.UpdateEvent{ObjectOld: gt, ObjectNew: high}
+			Expect(priorityPredicate{priority: farosv1alpha1.GitTrackPriorityHigh}.Update(e)).To(BeTrue())
+			Expect(priorityPredicate{priority: farosv1alpha1.GitTrackPriorityNormal}.Update(e)).To(BeFalse())
+		})
+
+		It("names the controller of each priority", func() {
+			Expect(controllerName(farosv1alpha1.GitTrackPriorityNormal)).To(Equal("gittrack-controller"))
+			Expect(controllerName(farosv1alpha1.GitTrackPriorityHigh)).To(Equal("gittrack-high-priority-controller"))
+		})
+	})
+
+	Context("listObjectsByName", func() {
+		var reconciler *ReconcileGitTrack
+		var children map[string]farosv1alpha1.GitTrackObjectInterface
+
+		BeforeEach(func() {
+			var ok bool
+			reconciler, ok = r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+
+			createInstance(instance, "b17c0e0f45beca3f1c1e62a7f49fecb738c60d42")
+			// Wait for client cache to expire
+			waitForInstanceCreated(key)
+
+			var err error
+			children, err = reconciler.listObjectsByName(instance)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return 6 child objects", func() {
+			Expect(children).Should(HaveLen(6))
+		})
+
+		It("should return 5 namespaced objects", func() {
+			var count int
+			for _, obj := range children {
+				if _, ok := obj.(*farosv1alpha1.GitTrackObject); ok {
+					count++
+				}
+			}
+			Expect(count).To(Equal(5))
+		})
+
+		It("should return 1 non-namespaced resource", func() {
+			var count int
+			for _, obj := range children {
+				if _, ok := obj.(*farosv1alpha1.ClusterGitTrackObject); ok {
+					count++
+				}
+			}
+			Expect(count).To(Equal(1))
+		})
+
+		It("should key all items by their NamespacedName", func() {
+			for key, obj := range children {
+				Expect(key).Should(Equal(obj.GetNamespacedName()))
+			}
+		})
+
+		It("should label all items with the GitTrack", func() {
+			for _, obj := range children {
+				Expect(obj.GetLabels()).To(HaveKeyWithValue(gittrackutils.GitTrackLabel, instance.Name))
+			}
+		})
+
+		It("should return the same objects when listing in pages", func() {
+			reconciler.listPageSize = 2
+			paged, err := reconciler.listObjectsByName(instance)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paged).To(HaveLen(len(children)))
+			for key := range children {
+				Expect(paged).To(HaveKey(key))
+			}
+		})
+	})
+})
+
+var getsFilesFromRepo = func(path string, count int) {
+	Context(fmt.Sprintf("With subPath %s", path), func() {
+		var files map[string]*gitstore.File
+		var gt *farosv1alpha1.GitTrack
+
+		BeforeEach(func() {
+			var err error
+			var reconciler *ReconcileGitTrack
+			var ok bool
+			reconciler, ok = r.(*ReconcileGitTrack)
+			Expect(ok).To(BeTrue())
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: "default",
+				},
+				Spec: farosv1alpha1.GitTrackSpec{
+					SubPath:    path,
+					Repository: repositoryURL,
+					DeployKey:  farosv1alpha1.GitTrackDeployKey{},
+					Reference:  "51798af1c1374d1d375a0eb7a3e53dd67ac5d135",
+				},
+			}
+
+			Expect(c.Create(context.TODO(), gt)).NotTo(HaveOccurred())
+			req := reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "test",
+					Namespace: "default",
+				},
+			}
+			Eventually(requests, timeout).Should(Receive(Equal(req)))
+
+			files, err = reconciler.getFiles(gt, gt.Spec.Reference)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(c.Delete(context.TODO(), gt)).NotTo(HaveOccurred())
+		})
+
+		It("Filters files by SubPath", func() {
+			for filePath := range files {
+				Expect(filePath).To(HavePrefix(strings.TrimPrefix(path, "/")))
+			}
+		})
+
+		It("Filters files by file extension", func() {
+			for filePath := range files {
+				Expect(filePath).To(MatchRegexp(filePathRegexp))
+			}
+		})
+
+		It("Fetches all files recursively from the SubPath", func() {
+			Expect(files).To(HaveLen(count))
 		})
 
 	})
 }
+
+// fakeBackupStore records the backups written to it
+type fakeBackupStore struct {
+	written map[string]string
+	puts    int
+	err     error
+}
+
+func (f *fakeBackupStore) Put(ctx context.Context, key string, data []byte) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.puts++
+	f.written[key] = string(data)
+	return nil
+}
+
+// fakeNotifier records the notifications it is sent
+type fakeNotifier struct {
+	sent []notifications.Notification
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n notifications.Notification) error {
+	f.sent = append(f.sent, n)
+	return nil
+}
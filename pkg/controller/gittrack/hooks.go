@@ -0,0 +1,302 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// hookAnnotation marks a manifest as a hook that is run before or after the
+// rest of the children are applied
+const hookAnnotation = "faros.pusher.com/hook"
+
+// hookPhase is when a hook is run in a sync
+type hookPhase string
+
+const (
+	hookPreApply  hookPhase = "pre-apply"
+	hookPostApply hookPhase = "post-apply"
+)
+
+// hookRun is the part of a sync that is applied while hooks are run
+type hookRun struct {
+	// phase is the phase of the hooks being run
+	phase hookPhase
+	// objects are applied while the rest of the children are held back
+	objects []*unstructured.Unstructured
+	// held are the children held back until the hooks have completed
+	held []farosv1alpha1.GitTrackChildStatus
+	// failed describes the hooks that failed
+	failed []string
+	// waiting describes the hooks, or the children, that are not complete yet
+	waiting []string
+}
+
+// holding returns whether any children are held back by the hooks
+func (run *hookRun) holding() bool {
+	return run != nil && len(run.held) > 0
+}
+
+// planHooks works out which children are applied while the hooks of the
+// GitTrack are run. Pre-apply hooks are run first and the rest of the
+// children are held back until they complete. Post-apply hooks are held back
+// until the rest of the children are in sync. It returns nil when the
+// GitTrack has no hooks or all of them have completed.
+func (r *ReconcileGitTrack) planHooks(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, existing map[string]farosv1alpha1.GitTrackObjectInterface) (*hookRun, error) {
+	// Nothing is applied in dry-run mode, so the hooks would never complete
+	if farosflags.DryRun {
+		return nil, nil
+	}
+
+	var pre, post, rest []*unstructured.Unstructured
+	for _, u := range objects {
+		switch phase := hookPhase(u.GetAnnotations()[hookAnnotation]); phase {
+		case hookPreApply:
+			pre = append(pre, u)
+		case hookPostApply:
+			post = append(post, u)
+		case "":
+			rest = append(rest, u)
+		default:
+			return nil, fmt.Errorf("invalid %s annotation %q on '%s', must be %s or %s", hookAnnotation, phase, objectFiles[u], hookPreApply, hookPostApply)
+		}
+	}
+	if len(pre) == 0 && len(post) == 0 {
+		return nil, nil
+	}
+
+	token, err := hookToken(objects)
+	if err != nil {
+		return nil, err
+	}
+	r.hookToken = token
+
+	run := &hookRun{phase: hookPreApply}
+	run.failed, run.waiting, err = r.hookStates(pre, existing, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(run.failed) > 0 || len(run.waiting) > 0 {
+		run.objects = pre
+		run.held, err = r.heldChildren(append(rest, post...), objectFiles, fmt.Sprintf("held back until %s hooks complete", hookPreApply))
+		if err != nil {
+			return nil, err
+		}
+		return run, nil
+	}
+
+	run.phase = hookPostApply
+	run.waiting, err = r.unsyncedChildren(rest, existing)
+	if err != nil {
+		return nil, err
+	}
+	if len(run.waiting) > 0 {
+		run.objects = append(pre, rest...)
+		run.held, err = r.heldChildren(post, objectFiles, "held back until the rest of the children are in sync")
+		if err != nil {
+			return nil, err
+		}
+		return run, nil
+	}
+
+	run.objects = objects
+	run.failed, run.waiting, err = r.hookStates(post, existing, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(run.failed) == 0 && len(run.waiting) == 0 {
+		return nil, nil
+	}
+	return run, nil
+}
+
+// hookToken identifies the desired state of all of the children. Hooks are
+// run once for each token, so they are run again whenever it changes.
+func hookToken(objects []*unstructured.Unstructured) (string, error) {
+	manifests := make([]string, 0, len(objects))
+	for _, u := range objects {
+		data, err := u.MarshalJSON()
+		if err != nil {
+			return "", fmt.Errorf("error marshalling JSON: %v", err)
+		}
+		manifests = append(manifests, string(data))
+	}
+	sort.Strings(manifests)
+	sum := sha256.Sum256([]byte(strings.Join(manifests, "\n")))
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// isHook returns whether the manifest is for a hook
+func isHook(u *unstructured.Unstructured) bool {
+	_, ok := u.GetAnnotations()[hookAnnotation]
+	return ok
+}
+
+// hookStates describes the hooks that have failed or have not completed for
+// the token yet
+func (r *ReconcileGitTrack) hookStates(hooks []*unstructured.Unstructured, existing map[string]farosv1alpha1.GitTrackObjectInterface, token string) (failed []string, waiting []string, err error) {
+	for _, u := range hooks {
+		gto, err := r.newGitTrackObjectInterface(u)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Ignored hooks are never created so cannot hold back the sync
+		ignored, _, err := r.ignoreObject(u)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ignored {
+			continue
+		}
+
+		name := gto.GetNamespacedName()
+		found, ok := existing[name]
+		if !ok {
+			waiting = append(waiting, fmt.Sprintf("%s: not started", name))
+			continue
+		}
+		complete, hookFailed, state := hookState(found, token)
+		switch {
+		case hookFailed:
+			failed = append(failed, fmt.Sprintf("%s: %s", name, state))
+		case !complete:
+			waiting = append(waiting, fmt.Sprintf("%s: %s", name, state))
+		}
+	}
+	sort.Strings(failed)
+	sort.Strings(waiting)
+	return failed, waiting, nil
+}
+
+// hookState returns whether the hook has completed for the token or has
+// failed and, if not complete, a description of its state. Hooks are run by
+// recreating their child, so the health of the child only describes the run
+// once the child has been recreated for the token.
+func hookState(child farosv1alpha1.GitTrackObjectInterface, token string) (complete bool, failed bool, state string) {
+	if child.GetAnnotations()[gittrackobjectutils.RecreateAnnotation] != token || child.GetStatus().LastRecreateToken != token {
+		return false, false, "not started"
+	}
+	for _, condition := range child.GetStatus().Conditions {
+		if condition.Type != farosv1alpha1.ChildHealthyType {
+			continue
+		}
+		if condition.Status == apiv1.ConditionTrue {
+			return true, false, ""
+		}
+		failed = condition.Reason == string(gittrackobjectutils.ChildFailed)
+		return false, failed, fmt.Sprintf("%s: %s", condition.Reason, condition.Message)
+	}
+	return false, false, "health unknown"
+}
+
+// unsyncedChildren describes the children that have not been synced with
+// their desired state yet
+func (r *ReconcileGitTrack) unsyncedChildren(objects []*unstructured.Unstructured, existing map[string]farosv1alpha1.GitTrackObjectInterface) ([]string, error) {
+	waiting := []string{}
+	for _, u := range objects {
+		gto, err := r.newGitTrackObjectInterface(u)
+		if err != nil {
+			return nil, err
+		}
+		ignored, _, err := r.ignoreObject(u)
+		if err != nil {
+			return nil, err
+		}
+		if ignored {
+			continue
+		}
+
+		name := gto.GetNamespacedName()
+		found, ok := existing[name]
+		switch {
+		case !ok || !specEqual(found.GetSpec(), gto.GetSpec()):
+			waiting = append(waiting, fmt.Sprintf("%s: not applied yet", name))
+		case found.GetStatus().ObservedGeneration != found.GetGeneration():
+			waiting = append(waiting, fmt.Sprintf("%s: update not observed yet", name))
+		case !childInSync(found):
+			waiting = append(waiting, fmt.Sprintf("%s: %s", name, childSyncReason(found)))
+		}
+	}
+	sort.Strings(waiting)
+	return waiting, nil
+}
+
+// heldChildren describes the children held back by the hooks as children
+// that are out of sync
+func (r *ReconcileGitTrack) heldChildren(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, reason string) ([]farosv1alpha1.GitTrackChildStatus, error) {
+	held := []farosv1alpha1.GitTrackChildStatus{}
+	for _, u := range objects {
+		gto, err := r.newGitTrackObjectInterface(u)
+		if err != nil {
+			return nil, err
+		}
+		held = append(held, farosv1alpha1.GitTrackChildStatus{
+			Name:   gto.GetNamespacedName(),
+			Kind:   u.GetKind(),
+			Reason: reason,
+			File:   objectFiles[u],
+		})
+	}
+	return held, nil
+}
+
+// reportHooks reports the hooks that are running or have failed, and the
+// children held back until they complete
+func (r *ReconcileGitTrack) reportHooks(gt *farosv1alpha1.GitTrack, run *hookRun, opts *statusOpts) {
+	if run.holding() {
+		opts.gcReason = gittrackutils.GCSkippedHooks
+		opts.childrenOutOfSync = boundChildStatuses(append(opts.childrenOutOfSync, run.held...))
+	}
+	// Errors applying the children take precedence
+	if opts.upToDateError != nil {
+		return
+	}
+
+	held := ""
+	if run.holding() {
+		held = fmt.Sprintf(", %d changes held back", len(run.held))
+	}
+	previous := gittrackutils.GetGitTrackCondition(gt.Status, farosv1alpha1.ChildrenUpToDateType)
+	switch {
+	case len(run.failed) > 0:
+		opts.upToDateError = fmt.Errorf("%s hooks failed%s:\n%s", run.phase, held, strings.Join(run.failed, ",\n"))
+		opts.upToDateReason = gittrackutils.HookFailed
+		if previous == nil || previous.Reason != string(gittrackutils.HookFailed) {
+			r.recorder.Eventf(gt, apiv1.EventTypeWarning, "HookFailed", "The %s hooks failed for '%s'", run.phase, opts.reference)
+		}
+	case run.holding() && run.phase == hookPostApply:
+		opts.upToDateError = fmt.Errorf("%d %s hooks held back until the rest of the children are in sync:\n%s", len(run.held), run.phase, strings.Join(run.waiting, ",\n"))
+		opts.upToDateReason = gittrackutils.HookInProgress
+	default:
+		opts.upToDateError = fmt.Errorf("waiting for %s hooks to complete%s:\n%s", run.phase, held, strings.Join(run.waiting, ",\n"))
+		opts.upToDateReason = gittrackutils.HookInProgress
+		if previous == nil || previous.Reason != string(gittrackutils.HookInProgress) {
+			r.recorder.Eventf(gt, apiv1.EventTypeNormal, "HookStarted", "Running the %s hooks for '%s'", run.phase, opts.reference)
+		}
+	}
+}
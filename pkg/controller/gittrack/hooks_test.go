@@ -0,0 +1,111 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Hooks Suite", func() {
+	Context("hookState", func() {
+		var gto *farosv1alpha1.GitTrackObject
+
+		var setHealth = func(status v1.ConditionStatus, reason string) {
+			gto.Status.Conditions = []farosv1alpha1.GitTrackObjectCondition{
+				{
+					Type:   farosv1alpha1.ChildHealthyType,
+					Status: status,
+					Reason: reason,
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			gto = &farosv1alpha1.GitTrackObject{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "job-migrate",
+					Namespace:   "default",
+					Annotations: map[string]string{gittrackobjectutils.RecreateAnnotation: "abc"},
+				},
+				Status: farosv1alpha1.GitTrackObjectStatus{LastRecreateToken: "abc"},
+			}
+		})
+
+		It("is complete when the child is healthy", func() {
+			setHealth(v1.ConditionTrue, "ChildReady")
+			complete, failed, _ := hookState(gto, "abc")
+			Expect(complete).To(BeTrue())
+			Expect(failed).To(BeFalse())
+		})
+
+		It("has failed when the child failed", func() {
+			setHealth(v1.ConditionFalse, "ChildFailed")
+			complete, failed, _ := hookState(gto, "abc")
+			Expect(complete).To(BeFalse())
+			Expect(failed).To(BeTrue())
+		})
+
+		It("is waiting while the child is progressing", func() {
+			setHealth(v1.ConditionFalse, "ChildProgressing")
+			complete, failed, _ := hookState(gto, "abc")
+			Expect(complete).To(BeFalse())
+			Expect(failed).To(BeFalse())
+		})
+
+		It("has not started until the child is recreated for the token", func() {
+			setHealth(v1.ConditionTrue, "ChildReady")
+			complete, failed, state := hookState(gto, "def")
+			Expect(complete).To(BeFalse())
+			Expect(failed).To(BeFalse())
+			Expect(state).To(Equal("not started"))
+		})
+	})
+
+	Context("hookToken", func() {
+		var objects []*unstructured.Unstructured
+
+		BeforeEach(func() {
+			objects = []*unstructured.Unstructured{
+				{Object: map[string]interface{}{"kind": "Job", "metadata": map[string]interface{}{"name": "migrate"}}},
+				{Object: map[string]interface{}{"kind": "Deployment", "metadata": map[string]interface{}{"name": "nginx"}}},
+			}
+		})
+
+		It("does not depend on the order of the objects", func() {
+			token, err := hookToken(objects)
+			Expect(err).NotTo(HaveOccurred())
+			reversed, err := hookToken([]*unstructured.Unstructured{objects[1], objects[0]})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reversed).To(Equal(token))
+		})
+
+		It("changes when an object changes", func() {
+			token, err := hookToken(objects)
+			Expect(err).NotTo(HaveOccurred())
+			objects[1].SetLabels(map[string]string{"app": "nginx"})
+			changed, err := hookToken(objects)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).NotTo(Equal(token))
+		})
+	})
+})
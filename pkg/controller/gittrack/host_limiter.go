@@ -0,0 +1,100 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pusher/faros/pkg/controller/gittrack/metrics"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// hostLimiter limits the rate of git operations against each git host so
+// that tracking many repositories on one host does not trip its abuse
+// detection
+type hostLimiter struct {
+	qps   float32
+	burst int
+
+	mutex    sync.Mutex
+	limiters map[string]flowcontrol.RateLimiter
+}
+
+// newHostLimiter returns a hostLimiter allowing qps operations per second,
+// with bursts of up to burst operations, against each host. A qps of 0
+// disables the limit.
+func newHostLimiter(qps float32, burst int) *hostLimiter {
+	return &hostLimiter{
+		qps:      qps,
+		burst:    burst,
+		limiters: make(map[string]flowcontrol.RateLimiter),
+	}
+}
+
+// accept blocks until an operation against the host of the repository is
+// allowed. Time spent waiting is recorded so that the saturation of the limit
+// can be monitored.
+func (l *hostLimiter) accept(repository string) {
+	if l == nil || l.qps <= 0 {
+		return
+	}
+	host := repositoryHost(repository)
+
+	l.mutex.Lock()
+	limiter, ok := l.limiters[host]
+	if !ok {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(l.qps, l.burst)
+		l.limiters[host] = limiter
+	}
+	l.mutex.Unlock()
+
+	if limiter.TryAccept() {
+		metrics.GitHostRequests.WithLabelValues(host, "false").Inc()
+		return
+	}
+	metrics.GitHostRequests.WithLabelValues(host, "true").Inc()
+	metrics.GitHostRequestsWaiting.WithLabelValues(host).Inc()
+	defer metrics.GitHostRequestsWaiting.WithLabelValues(host).Dec()
+	start := time.Now()
+	limiter.Accept()
+	metrics.GitHostWaitDuration.WithLabelValues(host).Observe(time.Since(start).Seconds())
+}
+
+// repositoryHost returns the host of the repository URL, which is either a
+// URL or an scp-like SSH address such as `git@github.com:pusher/faros.git`.
+// Local repositories have no host.
+func repositoryHost(repository string) string {
+	if strings.Contains(repository, "://") {
+		u, err := url.Parse(repository)
+		if err != nil {
+			return ""
+		}
+		return u.Hostname()
+	}
+	colon := strings.Index(repository, ":")
+	if colon < 0 || strings.Contains(repository[:colon], "/") {
+		return ""
+	}
+	host := repository[:colon]
+	if at := strings.LastIndex(host, "@"); at >= 0 {
+		host = host[at+1:]
+	}
+	return host
+}
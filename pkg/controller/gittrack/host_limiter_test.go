@@ -0,0 +1,75 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/pusher/faros/pkg/controller/gittrack/metrics"
+)
+
+var _ = Describe("Host Limiter Suite", func() {
+	Context("repositoryHost", func() {
+		It("returns the host of URLs", func() {
+			Expect(repositoryHost("https://github.com/pusher/faros.git")).To(Equal("github.com"))
+			Expect(repositoryHost("ssh://git@gitlab.example.com:2222/pusher/faros.git")).To(Equal("gitlab.example.com"))
+		})
+
+		It("returns the host of scp-like addresses", func() {
+			Expect(repositoryHost("git@github.com:pusher/faros.git")).To(Equal("github.com"))
+		})
+
+		It("returns no host for local repositories", func() {
+			Expect(repositoryHost("file:///tmp/faros")).To(BeEmpty())
+			Expect(repositoryHost("/tmp/faros")).To(BeEmpty())
+		})
+	})
+
+	Context("hostLimiter", func() {
+		var throttled = func(host string) float64 {
+			var metric dto.Metric
+			Expect(metrics.GitHostRequests.WithLabelValues(host, "true").Write(&metric)).To(Succeed())
+			return metric.GetCounter().GetValue()
+		}
+
+		It("throttles operations beyond the burst against a host", func() {
+			limiter := newHostLimiter(100, 1)
+			before := throttled("throttled.example.com")
+			limiter.accept("https://throttled.example.com/a.git")
+			limiter.accept("https://throttled.example.com/b.git")
+			Expect(throttled("throttled.example.com") - before).To(Equal(1.0))
+		})
+
+		It("limits each host separately", func() {
+			limiter := newHostLimiter(100, 1)
+			before := throttled("a.example.com") + throttled("b.example.com")
+			limiter.accept("https://a.example.com/faros.git")
+			limiter.accept("git@b.example.com:pusher/faros.git")
+			Expect(throttled("a.example.com") + throttled("b.example.com") - before).To(BeZero())
+			Expect(limiter.limiters).To(HaveLen(2))
+		})
+
+		It("does not limit when disabled", func() {
+			var limiter *hostLimiter
+			limiter.accept("https://github.com/pusher/faros.git")
+			limiter = newHostLimiter(0, 1)
+			limiter.accept("https://github.com/pusher/faros.git")
+			Expect(limiter.limiters).To(BeEmpty())
+		})
+	})
+})
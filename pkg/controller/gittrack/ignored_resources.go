@@ -0,0 +1,172 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// ignoredResourcesKey is the key of the ignored resources ConfigMap listing
+// the resources to ignore
+const ignoredResourcesKey = "resources"
+
+// ignoredResources returns the set of resources to ignore, combining those
+// given by flag with those listed in the ignored resources ConfigMap. The
+// ConfigMap is read from the API server as ConfigMaps are not cached. A
+// warning is recorded on the GitTrack if the ConfigMap does not exist.
+func (r *ReconcileGitTrack) ignoredResources(gt *farosv1alpha1.GitTrack) (map[schema.GroupVersionResource]interface{}, error) {
+	if r.ignoredResourcesConfigMap == nil {
+		return r.ignoredGVRs, nil
+	}
+
+	cm := &apiv1.ConfigMap{}
+	err := r.apiReader.Get(context.TODO(), *r.ignoredResourcesConfigMap, cm)
+	if errors.IsNotFound(err) {
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "IgnoredResourcesNotFound", "Ignored resources ConfigMap %s not found, only ignoring resources given by flag", r.ignoredResourcesConfigMap)
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to get ignored resources ConfigMap %s: %v", r.ignoredResourcesConfigMap, err)
+	}
+	gvrs, err := farosflags.ParseResources(parseResourceList(cm.Data[ignoredResourcesKey]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignored resources ConfigMap %s: %v", r.ignoredResourcesConfigMap, err)
+	}
+
+	source := fmt.Sprintf("ConfigMap `%s`", r.ignoredResourcesConfigMap)
+	for gvr := range gvrs {
+		gvrs[gvr] = source
+	}
+	for gvr, source := range r.ignoredGVRs {
+		gvrs[gvr] = source
+	}
+	return gvrs, nil
+}
+
+// reloadResources parses the ignored and allowed resources again once the
+// configuration file has been reloaded, keeping the previous sets if either
+// is invalid
+func (r *ReconcileGitTrack) reloadResources() {
+	ignored, err := farosflags.ParseIgnoredResources()
+	if err != nil {
+		r.log.Error(err, "unable to parse reloaded ignored resources")
+		return
+	}
+	allowed, err := farosflags.ParseAllowedResources()
+	if err != nil {
+		r.log.Error(err, "unable to parse reloaded allowed resources")
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.ignoredGVRs = ignored
+	r.allowedGVRs = allowed
+}
+
+// parseResourceList splits a list of resources separated by commas or new
+// lines, skipping blank lines and comments
+func parseResourceList(data string) []string {
+	resources := []string{}
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		for _, resource := range strings.Split(line, ",") {
+			resource = strings.TrimSpace(resource)
+			if resource != "" {
+				resources = append(resources, resource)
+			}
+		}
+	}
+	return resources
+}
+
+// ignoredBy describes where an ignored resource was configured, resources
+// given by flag have no source
+func ignoredBy(source interface{}) string {
+	if s, ok := source.(string); ok {
+		return s
+	}
+	return "flag"
+}
+
+// ignoredResourcesInformer returns an informer watching only the ignored
+// resources ConfigMap, run by the manager, so that other ConfigMaps are not
+// cached
+func ignoredResourcesInformer(mgr manager.Manager, name types.NamespacedName) (toolscache.SharedIndexInformer, error) {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create clientset: %v", err)
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(name.Namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", name.Name).String()
+		}),
+	)
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	err = mgr.Add(manager.RunnableFunc(func(stop <-chan struct{}) error {
+		informer.Run(stop)
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return informer, nil
+}
+
+// ignoredResourcesRequests returns a mapping function that queues every
+// GitTrack for reconciliation when the ignored resources ConfigMap changes
+func ignoredResourcesRequests(c client.Client, name types.NamespacedName, log logr.Logger) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		if obj.Meta.GetNamespace() != name.Namespace || obj.Meta.GetName() != name.Name {
+			return nil
+		}
+
+		gts := &farosv1alpha1.GitTrackList{}
+		err := c.List(context.TODO(), gts)
+		if err != nil {
+			log.Error(err, "unable to list GitTracks after ignored resources changed")
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for _, gt := range gts.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: gt.Namespace, Name: gt.Name},
+			})
+		}
+		return requests
+	}
+}
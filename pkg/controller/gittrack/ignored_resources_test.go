@@ -0,0 +1,35 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ignored Resources Suite", func() {
+	Context("parseResourceList", func() {
+		It("splits resources on commas and new lines", func() {
+			resources := parseResourceList("jobs.batch/v1, cronjobs.batch/v1beta1\n\n  deployments.apps/v1 # managed elsewhere\n# roles.rbac.authorization.k8s.io/v1\n")
+			Expect(resources).To(Equal([]string{"jobs.batch/v1", "cronjobs.batch/v1beta1", "deployments.apps/v1"}))
+		})
+
+		It("returns no resources for empty data", func() {
+			Expect(parseResourceList("")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// kindAllowed returns whether the GroupVersionKind matches any of the allowed
+// kinds
+func kindAllowed(allowed []farosv1alpha1.GitTrackAllowedKind, gvk schema.GroupVersionKind) bool {
+	for _, k := range allowed {
+		if k.Group == gvk.Group && k.Kind == gvk.Kind && (k.Version == "" || k.Version == gvk.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedKindString formats the allowed kind as `<kind>.<group>/<version>`,
+// leaving out the parts that are empty
+func allowedKindString(k farosv1alpha1.GitTrackAllowedKind) string {
+	s := k.Kind
+	if k.Group != "" {
+		s = fmt.Sprintf("%s.%s", s, k.Group)
+	}
+	if k.Version != "" {
+		s = fmt.Sprintf("%s/%s", s, k.Version)
+	}
+	return s
+}
+
+// checkAllowedKinds returns an error listing the objects whose kind is not
+// one of the GitTrack's allowed kinds
+func checkAllowedKinds(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured) error {
+	if len(gt.Spec.AllowedKinds) == 0 {
+		return nil
+	}
+
+	disallowed := []string{}
+	for _, u := range objects {
+		if kindAllowed(gt.Spec.AllowedKinds, u.GroupVersionKind()) {
+			continue
+		}
+		name := u.GetName()
+		if u.GetNamespace() != "" {
+			name = fmt.Sprintf("%s/%s", u.GetNamespace(), name)
+		}
+		disallowed = append(disallowed, fmt.Sprintf("%s %s", u.GetKind(), name))
+	}
+	if len(disallowed) == 0 {
+		return nil
+	}
+
+	kinds := []string{}
+	for _, k := range gt.Spec.AllowedKinds {
+		kinds = append(kinds, allowedKindString(k))
+	}
+	sort.Strings(disallowed)
+	count := len(disallowed)
+	if count > maxDisallowedObjects {
+		disallowed = append(disallowed[:maxDisallowedObjects], fmt.Sprintf("%d more", count-maxDisallowedObjects))
+	}
+	return fmt.Errorf("%d objects are not of the kinds %s: %s", count, strings.Join(kinds, ", "), strings.Join(disallowed, ", "))
+}
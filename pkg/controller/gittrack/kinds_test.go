@@ -0,0 +1,44 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Kinds Suite", func() {
+	Context("kindAllowed", func() {
+		var deployment = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+		It("matches the group and kind of any version when the version is empty", func() {
+			Expect(kindAllowed([]farosv1alpha1.GitTrackAllowedKind{{Group: "apps", Kind: "Deployment"}}, deployment)).To(BeTrue())
+		})
+
+		It("matches the version when it is given", func() {
+			Expect(kindAllowed([]farosv1alpha1.GitTrackAllowedKind{{Group: "apps", Version: "v1", Kind: "Deployment"}}, deployment)).To(BeTrue())
+			Expect(kindAllowed([]farosv1alpha1.GitTrackAllowedKind{{Group: "apps", Version: "v1beta2", Kind: "Deployment"}}, deployment)).To(BeFalse())
+		})
+
+		It("does not match kinds of other groups", func() {
+			Expect(kindAllowed([]farosv1alpha1.GitTrackAllowedKind{{Kind: "Deployment"}}, deployment)).To(BeFalse())
+			Expect(kindAllowed([]farosv1alpha1.GitTrackAllowedKind{{Group: "apps", Kind: "StatefulSet"}}, deployment)).To(BeFalse())
+		})
+	})
+})
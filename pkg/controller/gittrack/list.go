@@ -0,0 +1,92 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultListPageSize is the maximum number of objects fetched by each List
+// request when enumerating the children of a GitTrack
+const defaultListPageSize int64 = 500
+
+// listPages lists objects directly from the API server in pages of at most
+// listPageSize objects, calling fn with each page in turn
+func (r *ReconcileGitTrack) listPages(newList func() runtime.Object, fn func(runtime.Object), opts ...client.ListOptionFunc) error {
+	continueToken := ""
+	for {
+		list := newList()
+		pageOpts := append(opts, func(o *client.ListOptions) {
+			o.Raw = &metav1.ListOptions{Limit: r.listPageSize, Continue: continueToken}
+		})
+		err := r.apiReader.List(context.TODO(), list, pageOpts...)
+		if err != nil {
+			return err
+		}
+		fn(list)
+
+		listMeta, err := meta.ListAccessor(list)
+		if err != nil {
+			return fmt.Errorf("unable to read list metadata: %v", err)
+		}
+		continueToken = listMeta.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
+
+// listChildren lists the (Cluster)GitTrackObjects labelled as children of the
+// GitTrack, namespaced children are only listed in the namespaces managed by
+// the controller
+func (r *ReconcileGitTrack) listChildren(owner *farosv1alpha1.GitTrack, gtoFn func(*farosv1alpha1.GitTrackObjectList), cgtoFn func(*farosv1alpha1.ClusterGitTrackObjectList)) error {
+	selector := client.MatchingLabels(map[string]string{gittrackutils.GitTrackLabel: owner.Name})
+
+	namespaces := farosflags.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+	for _, namespace := range namespaces {
+		err := r.listPages(
+			func() runtime.Object { return &farosv1alpha1.GitTrackObjectList{} },
+			func(list runtime.Object) { gtoFn(list.(*farosv1alpha1.GitTrackObjectList)) },
+			selector, client.InNamespace(namespace),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to list GitTrackObjects: %v", err)
+		}
+	}
+
+	err := r.listPages(
+		func() runtime.Object { return &farosv1alpha1.ClusterGitTrackObjectList{} },
+		func(list runtime.Object) { cgtoFn(list.(*farosv1alpha1.ClusterGitTrackObjectList)) },
+		selector,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to list ClusterGitTrackObjects: %v", err)
+	}
+	return nil
+}
@@ -28,6 +28,12 @@ type metricsOpts struct {
 	status       *statusOpts
 	timeToDeploy []time.Duration
 	repository   string
+
+	// children counts the children of each kind, it is nil until the
+	// children have been listed
+	children map[string]int
+	// childDataSizes are the sizes of the data of the children of each kind
+	childDataSizes map[string][]int
 }
 
 func newMetricOpts(status *statusOpts) *metricsOpts {
@@ -37,6 +43,18 @@ func newMetricOpts(status *statusOpts) *metricsOpts {
 	}
 }
 
+// setChildren records the number of children of each kind and the size of
+// their data
+func (m *metricsOpts) setChildren(children map[string]farosv1alpha1.GitTrackObjectInterface) {
+	m.children = make(map[string]int)
+	m.childDataSizes = make(map[string][]int)
+	for _, child := range children {
+		kind := child.GetSpec().Kind
+		m.children[kind]++
+		m.childDataSizes[kind] = append(m.childDataSizes[kind], len(child.GetSpec().Data))
+	}
+}
+
 func (r *ReconcileGitTrack) updateMetrics(gt *farosv1alpha1.GitTrack, opts *metricsOpts) error {
 	if gt == nil {
 		return nil
@@ -47,6 +65,8 @@ func (r *ReconcileGitTrack) updateMetrics(gt *farosv1alpha1.GitTrack, opts *metr
 			"applied":    opts.status.applied,
 			"ignored":    opts.status.ignored,
 			"inSync":     opts.status.inSync,
+			"pruned":     opts.status.pruned,
+			"retained":   opts.status.retained,
 		},
 	)
 	if err != nil {
@@ -57,9 +77,33 @@ func (r *ReconcileGitTrack) updateMetrics(gt *farosv1alpha1.GitTrack, opts *metr
 	if err != nil {
 		return fmt.Errorf("error updating Time To Deploy metric: %v", err)
 	}
+
+	if opts.children != nil {
+		metrics.Children.Set(gt.GetName(), gt.GetNamespace(), opts.children)
+		err = updateChildDataSizeMetric(opts.childDataSizes)
+		if err != nil {
+			return fmt.Errorf("error updating Child Data Size metric: %v", err)
+		}
+	}
+
+	now := time.Now()
+	if opts.status.fetched() {
+		metrics.SecondsSinceLastFetch.Set(gt.GetName(), gt.GetNamespace(), now)
+	}
+	if opts.status.synced() {
+		metrics.SecondsSinceLastSync.Set(gt.GetName(), gt.GetNamespace(), now)
+	}
 	return nil
 }
 
+// deleteMetrics removes the metrics for a GitTrack that are not expected to
+// remain once it is deleted
+func deleteMetrics(gt *farosv1alpha1.GitTrack) {
+	metrics.SecondsSinceLastFetch.Delete(gt.GetName(), gt.GetNamespace())
+	metrics.SecondsSinceLastSync.Delete(gt.GetName(), gt.GetNamespace())
+	metrics.Children.Delete(gt.GetName(), gt.GetNamespace())
+}
+
 func updateChildStatusMetric(gtName, gtNamespace string, values map[string]int64) error {
 	for status, value := range values {
 		labels := map[string]string{
@@ -76,6 +120,26 @@ func updateChildStatusMetric(gtName, gtNamespace string, values map[string]int64
 	return nil
 }
 
+func updateReconcileDurationMetric(duration time.Duration, err error) error {
+	labels := map[string]string{
+		"result": resultLabel(err),
+	}
+	metric, err := metrics.ReconcileDuration.GetMetricWith(labels)
+	if err != nil {
+		return fmt.Errorf("unable to get metric with labels %+v: %v", labels, err)
+	}
+	metric.Observe(duration.Seconds())
+	return nil
+}
+
+// resultLabel returns the result label for an operation that returned err
+func resultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
 func updateTimeToDeployMetric(gtName, gtNamespace, repository string, durations []time.Duration) error {
 	labels := map[string]string{
 		"name":       gtName,
@@ -94,3 +158,19 @@ func updateTimeToDeployMetric(gtName, gtNamespace, repository string, durations
 
 	return nil
 }
+
+func updateChildDataSizeMetric(sizes map[string][]int) error {
+	for kind, kindSizes := range sizes {
+		labels := map[string]string{
+			"kind": kind,
+		}
+		metric, err := metrics.ChildDataSize.GetMetricWith(labels)
+		if err != nil {
+			return fmt.Errorf("unable to get metric with labels %+v: %v", labels, err)
+		}
+		for _, size := range kindSizes {
+			metric.Observe(float64(size))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ChildrenCollector is a prometheus collector that reports, for each
+// GitTrack, the number of children of each kind
+//
+// The counts of a GitTrack are replaced as a whole so that kinds the GitTrack
+// no longer has children of stop being reported
+type ChildrenCollector struct {
+	gitTrackCollector
+}
+
+var _ prometheus.Collector = &ChildrenCollector{}
+
+// NewChildrenCollector constructs a ChildrenCollector with the given name and
+// help
+func NewChildrenCollector(name, help string) *ChildrenCollector {
+	return &ChildrenCollector{newGitTrackCollector(name, help, "kind")}
+}
+
+// Set replaces the number of children of each kind for the given GitTrack
+func (c *ChildrenCollector) Set(name, namespace string, counts map[string]int) {
+	values := []gaugeValue{}
+	for kind, count := range counts {
+		values = append(values, gaugeValue{value: float64(count), labels: []string{kind}})
+	}
+	c.set(name, namespace, func() []gaugeValue {
+		return values
+	})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("Children Collector Suite", func() {
+	Context("ChildrenCollector", func() {
+		var collector *ChildrenCollector
+
+		var collect = func() map[string]float64 {
+			ch := make(chan prometheus.Metric, 10)
+			collector.Collect(ch)
+			close(ch)
+			result := make(map[string]float64)
+			for m := range ch {
+				metric := &dto.Metric{}
+				Expect(m.Write(metric)).To(Succeed())
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "kind" {
+						result[label.GetValue()] = metric.GetGauge().GetValue()
+					}
+				}
+			}
+			return result
+		}
+
+		BeforeEach(func() {
+			collector = NewChildrenCollector("faros_test_children", "Test")
+			collector.Set("example", "default", map[string]int{"Deployment": 2, "Service": 1})
+		})
+
+		It("reports the number of children of each kind", func() {
+			Expect(collect()).To(Equal(map[string]float64{"Deployment": 2, "Service": 1}))
+		})
+
+		It("stops reporting kinds without children", func() {
+			collector.Set("example", "default", map[string]int{"Deployment": 3})
+			Expect(collect()).To(Equal(map[string]float64{"Deployment": 3}))
+		})
+
+		It("stops reporting deleted GitTracks", func() {
+			collector.Delete("example", "default")
+			Expect(collect()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,98 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gitTrackCollector is a prometheus collector that reports gauges for each
+// GitTrack, labelled by the name and namespace of the GitTrack and any further
+// labels of the gauge
+//
+// The values of a GitTrack are computed when the metric is collected and are
+// replaced as a whole, so that gauges the GitTrack no longer has stop being
+// reported
+type gitTrackCollector struct {
+	desc   *prometheus.Desc
+	mutex  sync.Mutex
+	values map[gitTrackKey]func() []gaugeValue
+}
+
+// gitTrackKey identifies the GitTrack a value is reported for
+type gitTrackKey struct {
+	name      string
+	namespace string
+}
+
+// gaugeValue is the value of a gauge along with the values of its labels
+// other than the name and namespace of the GitTrack
+type gaugeValue struct {
+	value  float64
+	labels []string
+}
+
+var _ prometheus.Collector = &gitTrackCollector{}
+
+// newGitTrackCollector constructs a gitTrackCollector with the given name,
+// help and labels other than the name and namespace of the GitTrack
+func newGitTrackCollector(name, help string, labels ...string) gitTrackCollector {
+	return gitTrackCollector{
+		desc:   prometheus.NewDesc(name, help, append([]string{"name", "namespace"}, labels...), nil),
+		values: make(map[gitTrackKey]func() []gaugeValue),
+	}
+}
+
+// set replaces the function computing the values for the given GitTrack
+func (c *gitTrackCollector) set(name, namespace string, values func() []gaugeValue) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.values[gitTrackKey{name: name, namespace: namespace}] = values
+}
+
+// Delete removes the values for the given GitTrack
+func (c *gitTrackCollector) Delete(name, namespace string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.values, gitTrackKey{name: name, namespace: namespace})
+}
+
+// Reset removes the values for all GitTracks
+func (c *gitTrackCollector) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.values = make(map[gitTrackKey]func() []gaugeValue)
+}
+
+// Describe implements prometheus.Collector
+func (c *gitTrackCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *gitTrackCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, values := range c.values {
+		for _, v := range values() {
+			labels := append([]string{key.name, key.namespace}, v.labels...)
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, v.value, labels...)
+		}
+	}
+}
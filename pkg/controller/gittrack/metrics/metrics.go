@@ -54,9 +54,77 @@ var (
 			1 * time.Hour.Seconds(), // +Inf after an hour
 		},
 	}, []string{"name", "namespace", "repository"})
+
+	// ReconcileDuration is a prometheus histogram that holds the time taken
+	// to reconcile a GitTrack
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "faros_gittrack_reconcile_duration_seconds",
+		Help:    "Counts the time taken to reconcile a GitTrack",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+	}, []string{"result"})
+
+	// Children is a prometheus gauge that details the number of children of
+	// each kind the GitTrack has
+	Children = NewChildrenCollector(
+		"faros_gittrack_children",
+		"Shows the number of child objects of a GitTrack by kind",
+	)
+
+	// ChildDataSize is a prometheus histogram that holds the size of the data
+	// stored in the GitTrackObjects of GitTracks, which must stay below the
+	// object size limit of etcd
+	ChildDataSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "faros_gittrack_child_data_size_bytes",
+		Help:    "Measures the size of the data of (Cluster)GitTrackObjects",
+		Buckets: prometheus.ExponentialBuckets(1024, 2, 12),
+	}, []string{"kind"})
+
+	// SecondsSinceLastSync is a prometheus gauge that details the time since
+	// the GitTrack last synced all of its children successfully
+	SecondsSinceLastSync = NewSinceCollector(
+		"faros_gittrack_seconds_since_last_sync",
+		"Shows the number of seconds since a GitTrack last synced successfully",
+	)
+
+	// SecondsSinceLastFetch is a prometheus gauge that details the time since
+	// the GitTrack last fetched its repository successfully
+	SecondsSinceLastFetch = NewSinceCollector(
+		"faros_gittrack_seconds_since_last_fetch",
+		"Shows the number of seconds since a GitTrack last fetched successfully",
+	)
+
+	// GitHostRequests is a prometheus counter that counts the git operations
+	// against each host and whether they were throttled by the host rate limit
+	GitHostRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "faros_gittrack_git_host_requests_total",
+		Help: "Counts the git operations against each host by whether they were throttled",
+	}, []string{"host", "throttled"})
+
+	// GitHostRequestsWaiting is a prometheus gauge that details the number of
+	// git operations waiting for the rate limit of each host
+	GitHostRequestsWaiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "faros_gittrack_git_host_requests_waiting",
+		Help: "Shows the number of git operations waiting for the rate limit of each host",
+	}, []string{"host"})
+
+	// GitHostWaitDuration is a prometheus histogram that holds the time git
+	// operations waited for the rate limit of each host
+	GitHostWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "faros_gittrack_git_host_wait_duration_seconds",
+		Help:    "Counts the time git operations waited for the rate limit of each host",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+	}, []string{"host"})
 )
 
 func init() {
 	ctrlmetrics.Registry.MustRegister(ChildStatus)
 	ctrlmetrics.Registry.MustRegister(TimeToDeploy)
+	ctrlmetrics.Registry.MustRegister(ReconcileDuration)
+	ctrlmetrics.Registry.MustRegister(Children)
+	ctrlmetrics.Registry.MustRegister(ChildDataSize)
+	ctrlmetrics.Registry.MustRegister(SecondsSinceLastSync)
+	ctrlmetrics.Registry.MustRegister(SecondsSinceLastFetch)
+	ctrlmetrics.Registry.MustRegister(GitHostRequests)
+	ctrlmetrics.Registry.MustRegister(GitHostRequestsWaiting)
+	ctrlmetrics.Registry.MustRegister(GitHostWaitDuration)
 }
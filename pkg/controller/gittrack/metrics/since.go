@@ -0,0 +1,46 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SinceCollector is a prometheus collector that reports, for each GitTrack,
+// the number of seconds since a recorded point in time
+//
+// The value is computed when the metric is collected so that it continues to
+// grow while no new time is recorded
+type SinceCollector struct {
+	gitTrackCollector
+}
+
+var _ prometheus.Collector = &SinceCollector{}
+
+// NewSinceCollector constructs a SinceCollector with the given name and help
+func NewSinceCollector(name, help string) *SinceCollector {
+	return &SinceCollector{newGitTrackCollector(name, help)}
+}
+
+// Set records the time for the given GitTrack
+func (s *SinceCollector) Set(name, namespace string, t time.Time) {
+	s.set(name, namespace, func() []gaugeValue {
+		return []gaugeValue{{value: time.Since(t).Seconds()}}
+	})
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var _ = Describe("Since Collector Suite", func() {
+	Context("SinceCollector", func() {
+		var collector *SinceCollector
+
+		var collect = func() []*dto.Metric {
+			ch := make(chan prometheus.Metric, 10)
+			collector.Collect(ch)
+			close(ch)
+			result := []*dto.Metric{}
+			for m := range ch {
+				metric := &dto.Metric{}
+				Expect(m.Write(metric)).To(Succeed())
+				result = append(result, metric)
+			}
+			return result
+		}
+
+		BeforeEach(func() {
+			collector = NewSinceCollector("faros_test_seconds_since", "Test")
+			collector.Set("example", "default", time.Now().Add(-time.Minute))
+		})
+
+		It("reports the seconds since the recorded time", func() {
+			collected := collect()
+			Expect(collected).To(HaveLen(1))
+			Expect(collected[0].GetGauge().GetValue()).To(BeNumerically(">=", 60))
+		})
+
+		It("stops reporting deleted GitTracks", func() {
+			collector.Delete("example", "default")
+			Expect(collect()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,39 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+var _ = Describe("Metrics Suite", func() {
+	Context("setChildren", func() {
+		It("counts the children and their data sizes by kind", func() {
+			mOpts := newMetricOpts(newStatusOpts())
+			mOpts.setChildren(map[string]farosv1alpha1.GitTrackObjectInterface{
+				"default/a": &farosv1alpha1.GitTrackObject{Spec: farosv1alpha1.GitTrackObjectSpec{Kind: "Deployment", Data: []byte("aaaa")}},
+				"default/b": &farosv1alpha1.GitTrackObject{Spec: farosv1alpha1.GitTrackObjectSpec{Kind: "Deployment", Data: []byte("bb")}},
+				"c":         &farosv1alpha1.ClusterGitTrackObject{Spec: farosv1alpha1.GitTrackObjectSpec{Kind: "Namespace", Data: []byte("c")}},
+			})
+			Expect(mOpts.children).To(Equal(map[string]int{"Deployment": 2, "Namespace": 1}))
+			Expect(mOpts.childDataSizes["Deployment"]).To(ConsistOf(4, 2))
+			Expect(mOpts.childDataSizes["Namespace"]).To(ConsistOf(1))
+		})
+	})
+})
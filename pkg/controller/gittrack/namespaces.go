@@ -0,0 +1,76 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/render"
+	utils "github.com/pusher/faros/pkg/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxDisallowedObjects is the maximum number of objects listed when a
+// GitTrack is rejected for targeting namespaces or containing kinds it is not
+// allowed to
+const maxDisallowedObjects = 5
+
+// checkAllowedNamespaces returns an error listing the objects that target a
+// namespace outside the GitTrack's allowed namespaces
+func (r *ReconcileGitTrack) checkAllowedNamespaces(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured) error {
+	if len(gt.Spec.AllowedNamespaces) == 0 {
+		return nil
+	}
+	allowed := make(map[string]struct{})
+	for _, ns := range gt.Spec.AllowedNamespaces {
+		allowed[ns] = struct{}{}
+	}
+
+	disallowed := []string{}
+	for _, u := range objects {
+		// Objects applied to selected namespaces may target any namespace
+		if render.HasNamespaceSelector(u) && r.isNamespaced(u) {
+			disallowed = append(disallowed, fmt.Sprintf("%s %s (namespace selector)", u.GetKind(), u.GetName()))
+			continue
+		}
+		if u.GetNamespace() == "" {
+			continue
+		}
+		if _, ok := allowed[u.GetNamespace()]; ok {
+			continue
+		}
+		_, namespaced, err := utils.GetAPIResource(r.restMapper, u.GetObjectKind().GroupVersionKind())
+		if err != nil || !namespaced {
+			// Unknown kinds are reported when the object is handled
+			continue
+		}
+		disallowed = append(disallowed, fmt.Sprintf("%s %s/%s", u.GetKind(), u.GetNamespace(), u.GetName()))
+	}
+	if len(disallowed) == 0 {
+		return nil
+	}
+
+	sort.Strings(disallowed)
+	count := len(disallowed)
+	if count > maxDisallowedObjects {
+		disallowed = append(disallowed[:maxDisallowedObjects], fmt.Sprintf("%d more", count-maxDisallowedObjects))
+	}
+	return fmt.Errorf("%d objects target namespaces outside of %s: %s", count, strings.Join(gt.Spec.AllowedNamespaces, ", "), strings.Join(disallowed, ", "))
+}
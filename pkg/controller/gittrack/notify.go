@@ -0,0 +1,113 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	"github.com/pusher/faros/pkg/notifications"
+	v1 "k8s.io/api/core/v1"
+)
+
+// stageConditions maps each sync stage to the condition reporting it, in the
+// order the stages are run
+var stageConditions = []struct {
+	stage     notifications.Stage
+	condition farosv1alpha1.GitTrackConditionType
+}{
+	{notifications.FetchStage, farosv1alpha1.FilesFetchedType},
+	{notifications.ParseStage, farosv1alpha1.FilesParsedType},
+	{notifications.ApplyStage, farosv1alpha1.ChildrenUpToDateType},
+}
+
+// failedStage returns the first stage of the sync that failed and its error
+func failedStage(opts *statusOpts) (notifications.Stage, error) {
+	switch {
+	case opts.gitError != nil:
+		return notifications.FetchStage, opts.gitError
+	case opts.parseError != nil:
+		return notifications.ParseStage, opts.parseError
+	case opts.upToDateError != nil && !opts.changesPending():
+		return notifications.ApplyStage, opts.upToDateError
+	}
+	return "", nil
+}
+
+// previousFailedStage returns the first stage reported as failed by the
+// conditions of the GitTrack
+func previousFailedStage(gt *farosv1alpha1.GitTrack) notifications.Stage {
+	for _, sc := range stageConditions {
+		cond := gittrackutils.GetGitTrackCondition(gt.Status, sc.condition)
+		// Changes that were held back have not failed
+		if cond != nil && cond.Status == v1.ConditionFalse && !heldBack(gittrackutils.ConditionReason(cond.Reason)) {
+			return sc.stage
+		}
+	}
+	return ""
+}
+
+// newNotification returns the notification to send when the GitTrack enters
+// a failed stage or syncs successfully after failing, and whether one should
+// be sent. The GitTrack passed must hold the status from before this
+// reconcile.
+func newNotification(gt *farosv1alpha1.GitTrack, opts *statusOpts) (notifications.Notification, bool) {
+	previous := previousFailedStage(gt)
+	stage, err := failedStage(opts)
+	n := notifications.Notification{
+		Namespace:  gt.GetNamespace(),
+		Name:       gt.GetName(),
+		Repository: gt.Spec.Repository,
+		Reference:  syncReference(gt, opts.rollback),
+		Time:       time.Now(),
+	}
+	switch {
+	case err != nil && stage != previous:
+		n.Stage = stage
+		n.Message = err.Error()
+	case err == nil && previous != "" && opts.synced():
+		n.Stage = previous
+		n.Recovered = true
+	default:
+		return n, false
+	}
+	return n, true
+}
+
+// notify sends a notification to the endpoints configured by flags and to the
+// providers of the FarosAlerts matching the GitTrack when the GitTrack enters
+// a failed stage or recovers
+func (r *ReconcileGitTrack) notify(gt *farosv1alpha1.GitTrack, opts *statusOpts) error {
+	n, ok := newNotification(gt, opts)
+	if !ok {
+		return nil
+	}
+
+	notifiers, err := r.alertNotifiers(gt, n)
+	if r.notifier != nil {
+		notifiers = append(notifiers, r.notifier)
+	}
+	if len(notifiers) > 0 {
+		r.log.V(0).Info("Sending notification", "stage", n.Stage, "recovered", n.Recovered)
+		if nErr := notifications.Multi(notifiers...).Notify(context.TODO(), n); nErr != nil {
+			return nErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// changeAction is what a sync would do to a child
+type changeAction string
+
+const (
+	actionCreate   changeAction = "creation"
+	actionUpdate   changeAction = "update"
+	actionRecreate changeAction = "recreation"
+	actionDelete   changeAction = "deletion"
+)
+
+// plannedChange is a change that a sync would make to a child
+type plannedChange struct {
+	name   string
+	kind   string
+	file   string
+	action changeAction
+}
+
+// changeStatuses describes the planned changes as children that are out of
+// sync
+func changeStatuses(changes []plannedChange) []farosv1alpha1.GitTrackChildStatus {
+	statuses := []farosv1alpha1.GitTrackChildStatus{}
+	for _, change := range changes {
+		statuses = append(statuses, farosv1alpha1.GitTrackChildStatus{
+			Name:   change.name,
+			Kind:   change.kind,
+			Reason: fmt.Sprintf("%s pending", change.action),
+			File:   change.file,
+		})
+	}
+	return boundChildStatuses(statuses)
+}
+
+// planChanges works out which children a sync would create, update or
+// delete without changing anything. The counts and health of the children
+// are filled in to opts so that they can be reported if the changes are held
+// back.
+func (r *ReconcileGitTrack) planChanges(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, existing map[string]farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) ([]plannedChange, error) {
+	leftovers := make(map[string]farosv1alpha1.GitTrackObjectInterface, len(existing))
+	for name, obj := range existing {
+		leftovers[name] = obj
+	}
+
+	changes := []plannedChange{}
+	unhealthy := []string{}
+	for _, u := range objects {
+		gto, err := r.newGitTrackObjectInterface(u)
+		if err != nil {
+			return nil, err
+		}
+		name := gto.GetNamespacedName()
+		// Ignored children are not pruned either
+		found, ok := leftovers[name]
+		delete(leftovers, name)
+		ignored, reason, err := r.ignoreObject(u)
+		if err != nil {
+			return nil, err
+		}
+		if ignored {
+			opts.ignoredFiles[name] = reason
+			opts.ignored++
+			continue
+		}
+		opts.applied++
+
+		switch {
+		case !ok:
+			changes = append(changes, plannedChange{name: name, kind: u.GetKind(), file: objectFiles[u], action: actionCreate})
+		case recreationPlanned(found, gto, u):
+			changes = append(changes, plannedChange{name: name, kind: u.GetKind(), file: objectFiles[u], action: actionRecreate})
+		case !specEqual(found.GetSpec(), gto.GetSpec()):
+			changes = append(changes, plannedChange{name: name, kind: u.GetKind(), file: objectFiles[u], action: actionUpdate})
+		case childInSync(found):
+			opts.inSync++
+		}
+		if ok {
+			if healthy, health := childHealth(found); !healthy {
+				unhealthy = append(unhealthy, fmt.Sprintf("%s: %s", name, health))
+			}
+		}
+	}
+
+	toDelete, _ := partitionLeftovers(gt.Spec.PrunePolicy, leftovers)
+	for name, obj := range toDelete {
+		changes = append(changes, plannedChange{name: name, kind: obj.GetSpec().Kind, action: actionDelete})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].name < changes[j].name
+	})
+
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		opts.healthError = fmt.Errorf(strings.Join(unhealthy, ",\n"))
+		opts.healthReason = gittrackutils.ChildrenUnhealthy
+	} else {
+		opts.healthReason = gittrackutils.ChildrenHealthy
+	}
+	return changes, nil
+}
+
+// specEqual returns whether applying the desired spec would leave the found
+// spec unchanged
+func specEqual(found, desired farosv1alpha1.GitTrackObjectSpec) bool {
+	return found.Name == desired.Name && found.Kind == desired.Kind && bytes.Equal(found.Data, desired.Data)
+}
+
+// recreationPlanned returns whether applying the desired GitTrackObject
+// would delete and create its child again, either because a new recreation
+// was requested or because the child has the recreate update strategy and
+// has changed
+func recreationPlanned(found, desired farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) bool {
+	if gittrackobjectutils.PendingRecreate(found.GetStatus().LastRecreateToken, found, child) != "" {
+		return true
+	}
+	if specEqual(found.GetSpec(), desired.GetSpec()) {
+		return false
+	}
+	strategy, err := gittrackobjectutils.GetUpdateStrategy(child)
+	return err == nil && strategy == gittrackobjectutils.RecreateUpdateStrategy
+}
+
+// newPendingPlan builds the plan awaiting approval from the planned changes
+func newPendingPlan(reference string, changes []plannedChange) *farosv1alpha1.PendingPlan {
+	plan := &farosv1alpha1.PendingPlan{
+		Reference: reference,
+		Creations: []string{},
+		Updates:   []string{},
+		Deletions: []string{},
+	}
+	entries := []string{reference}
+	for _, change := range changes {
+		switch change.action {
+		case actionCreate:
+			plan.Creations = append(plan.Creations, change.name)
+		case actionUpdate, actionRecreate:
+			plan.Updates = append(plan.Updates, change.name)
+		case actionDelete:
+			plan.Deletions = append(plan.Deletions, change.name)
+		}
+		entries = append(entries, fmt.Sprintf("%s:%s", change.action, change.name))
+	}
+
+	// Identify the plan so that an approval only applies to the changes that
+	// were reviewed
+	hash := sha256.Sum256([]byte(strings.Join(entries, ",")))
+	plan.ID = hex.EncodeToString(hash[:])[:16]
+	return plan
+}
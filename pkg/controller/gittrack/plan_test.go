@@ -0,0 +1,100 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	testutils "github.com/pusher/faros/test/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Plan Suite", func() {
+	Context("recreationPlanned", func() {
+		var found, desired *farosv1alpha1.GitTrackObject
+		var child *unstructured.Unstructured
+
+		BeforeEach(func() {
+			found = testutils.ExampleGitTrackObject.DeepCopy()
+			desired = found.DeepCopy()
+			child = &unstructured.Unstructured{}
+			child.SetAnnotations(map[string]string{"faros.pusher.com/update-strategy": "recreate"})
+		})
+
+		It("plans no recreation when the child is unchanged", func() {
+			Expect(recreationPlanned(found, desired, child)).To(BeFalse())
+		})
+
+		It("plans a recreation when a child with the recreate strategy changes", func() {
+			desired.Spec.Data = []byte("changed")
+			Expect(recreationPlanned(found, desired, child)).To(BeTrue())
+		})
+
+		It("plans no recreation when a child with the default strategy changes", func() {
+			desired.Spec.Data = []byte("changed")
+			child.SetAnnotations(map[string]string{"faros.pusher.com/update-strategy": "update"})
+			Expect(recreationPlanned(found, desired, child)).To(BeFalse())
+		})
+
+		It("plans a recreation when a new one is requested", func() {
+			child.SetAnnotations(map[string]string{gittrackobjectutils.RecreateAnnotation: "2019-01-01"})
+			Expect(recreationPlanned(found, desired, child)).To(BeTrue())
+		})
+
+		It("plans no recreation once the request has been carried out", func() {
+			child.SetAnnotations(map[string]string{gittrackobjectutils.RecreateAnnotation: "2019-01-01"})
+			found.Status.LastRecreateToken = "2019-01-01"
+			Expect(recreationPlanned(found, desired, child)).To(BeFalse())
+		})
+	})
+
+	Context("newPendingPlan", func() {
+		var changes []plannedChange
+
+		BeforeEach(func() {
+			changes = []plannedChange{
+				{name: "default/a", kind: "ConfigMap", action: actionCreate},
+				{name: "default/b", kind: "Deployment", action: actionUpdate},
+				{name: "default/c", kind: "Service", action: actionDelete},
+			}
+		})
+
+		It("lists the changes by action", func() {
+			plan := newPendingPlan("master", changes)
+			Expect(plan.Reference).To(Equal("master"))
+			Expect(plan.Creations).To(Equal([]string{"default/a"}))
+			Expect(plan.Updates).To(Equal([]string{"default/b"}))
+			Expect(plan.Deletions).To(Equal([]string{"default/c"}))
+		})
+
+		It("gives the same plan the same ID", func() {
+			Expect(newPendingPlan("master", changes).ID).To(Equal(newPendingPlan("master", changes).ID))
+		})
+
+		It("gives a new ID when the changes differ", func() {
+			plan := newPendingPlan("master", changes)
+			changes[0].action = actionUpdate
+			Expect(newPendingPlan("master", changes).ID).NotTo(Equal(plan.ID))
+		})
+
+		It("gives a new ID when the reference differs", func() {
+			Expect(newPendingPlan("master", changes).ID).NotTo(Equal(newPendingPlan("develop", changes).ID))
+		})
+	})
+})
@@ -0,0 +1,133 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/render"
+	farosclient "github.com/pusher/faros/pkg/utils/client"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// webhookDenied prefixes the errors returned by the API server when an
+// admission webhook rejects a request
+const webhookDenied = "admission webhook"
+
+// policyViolation returns the message of the admission webhook that rejected
+// a request, or false if the error was not caused by an admission webhook
+func policyViolation(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	msg := err.Error()
+	i := strings.Index(msg, webhookDenied)
+	if i < 0 || !strings.Contains(msg[i:], "denied the request") {
+		return "", false
+	}
+	return msg[i:], true
+}
+
+// checkPolicies submits the children as server side dry runs so that the
+// admission webhooks of the cluster check them against their policies. The
+// children that violate a policy are listed in the status and, when the check
+// is enforced, removed from the objects returned and from existing so that
+// their GitTrackObjects are neither updated nor pruned.
+func (r *ReconcileGitTrack) checkPolicies(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, existing map[string]farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) []*unstructured.Unstructured {
+	if gt.Spec.PolicyCheck == "" {
+		return objects
+	}
+	if !farosflags.ServerDryRun {
+		opts.policyError = fmt.Errorf("server side dry run is disabled by the --server-dry-run flag")
+		opts.policyReason = gittrackutils.PolicyCheckSkipped
+		return objects
+	}
+	if gt.Spec.KubeConfigSecretRef != nil {
+		opts.policyError = fmt.Errorf("children applied to a remote cluster cannot be checked")
+		opts.policyReason = gittrackutils.PolicyCheckSkipped
+		return objects
+	}
+
+	violations := []farosv1alpha1.GitTrackChildStatus{}
+	allowed := []*unstructured.Unstructured{}
+	for _, u := range objects {
+		msg, violated := r.checkPolicy(u)
+		if !violated {
+			allowed = append(allowed, u)
+			continue
+		}
+
+		name := render.ObjectName(u)
+		if gto, err := r.newGitTrackObjectInterface(u); err == nil {
+			name = gto.GetNamespacedName()
+		}
+		violations = append(violations, farosv1alpha1.GitTrackChildStatus{Name: name, Kind: u.GetKind(), Reason: msg, File: objectFiles[u]})
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "PolicyViolation", "Child '%s' violates a policy: %s", name, msg)
+
+		if gt.Spec.PolicyCheck != farosv1alpha1.PolicyCheckEnforce {
+			allowed = append(allowed, u)
+			continue
+		}
+		// Leave the child as it is until it complies
+		if opts.ignoredFiles == nil {
+			opts.ignoredFiles = make(map[string]string)
+		}
+		opts.ignoredFiles[name] = fmt.Sprintf("violates policy: %s", msg)
+		opts.ignored++
+		delete(existing, name)
+	}
+
+	if len(violations) > 0 {
+		opts.policyError = fmt.Errorf("%d children violate policies", len(violations))
+		opts.policyReason = gittrackutils.PolicyViolated
+	} else {
+		opts.policyReason = gittrackutils.PolicyCheckPassed
+	}
+	opts.policyViolations = boundChildStatuses(violations)
+	return allowed
+}
+
+// checkPolicy submits the object as a server side dry run and returns the
+// message of the admission webhook that rejected it, if any. Other errors are
+// logged and left for the GitTrackObject to report when the object is applied.
+func (r *ReconcileGitTrack) checkPolicy(u *unstructured.Unstructured) (string, bool) {
+	// Children of other clusters are checked by the admission webhooks of
+	// their cluster when they are applied
+	if _, ok := u.GetAnnotations()[render.ClusterAnnotation]; ok {
+		return "", false
+	}
+	ignored, _, err := r.ignoreObject(u)
+	if err != nil || ignored {
+		return "", false
+	}
+
+	dryRun := true
+	err = r.applier.Apply(context.TODO(), &farosclient.ApplyOptions{ServerDryRun: &dryRun}, u.DeepCopy())
+	if msg, violated := policyViolation(err); violated {
+		return msg, true
+	}
+	if err != nil {
+		r.log.V(1).Info("Unable to check object against policies", "kind", u.GetKind(), "name", u.GetName(), "error", err.Error())
+	}
+	return "", false
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Policy Suite", func() {
+	Context("policyViolation", func() {
+		It("returns the message of the admission webhook that denied the request", func() {
+			err := fmt.Errorf(`error creating object: admission webhook "validation.gatekeeper.sh" denied the request: [denied by required-labels] you must provide labels: {"team"}`)
+			msg, violated := policyViolation(err)
+			Expect(violated).To(BeTrue())
+			Expect(msg).To(Equal(`admission webhook "validation.gatekeeper.sh" denied the request: [denied by required-labels] you must provide labels: {"team"}`))
+		})
+
+		It("ignores other errors", func() {
+			_, violated := policyViolation(fmt.Errorf("error creating object: namespaces \"missing\" not found"))
+			Expect(violated).To(BeFalse())
+			_, violated = policyViolation(nil)
+			Expect(violated).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,71 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// gitTrackPriority returns the priority of the GitTrack, Normal unless set
+func gitTrackPriority(gt *farosv1alpha1.GitTrack) farosv1alpha1.GitTrackPriority {
+	if gt.Spec.Priority == "" {
+		return farosv1alpha1.GitTrackPriorityNormal
+	}
+	return gt.Spec.Priority
+}
+
+// controllerName returns the name of the controller reconciling GitTracks of
+// the priority. Each controller has its own queue and workers.
+func controllerName(priority farosv1alpha1.GitTrackPriority) string {
+	if priority == farosv1alpha1.GitTrackPriorityHigh {
+		return "gittrack-high-priority-controller"
+	}
+	return "gittrack-controller"
+}
+
+// priorityPredicate filters events to those whose object is a GitTrack of
+// the priority
+type priorityPredicate struct {
+	priority farosv1alpha1.GitTrackPriority
+}
+
+// Create returns true if the event object is a GitTrack of the priority
+func (p priorityPredicate) Create(e event.CreateEvent) bool {
+	return p.matches(e.Object)
+}
+
+// Update returns true if the new event object is a GitTrack of the priority
+func (p priorityPredicate) Update(e event.UpdateEvent) bool {
+	return p.matches(e.ObjectNew)
+}
+
+// Delete returns true if the event object is a GitTrack of the priority
+func (p priorityPredicate) Delete(e event.DeleteEvent) bool {
+	return p.matches(e.Object)
+}
+
+// Generic returns true if the event object is a GitTrack of the priority
+func (p priorityPredicate) Generic(e event.GenericEvent) bool {
+	return p.matches(e.Object)
+}
+
+func (p priorityPredicate) matches(obj runtime.Object) bool {
+	gt, ok := obj.(*farosv1alpha1.GitTrack)
+	return ok && gitTrackPriority(gt) == p.priority
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/utils"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// retainAnnotation marks manifests that should be retained by the
+// RetainAnnotated prune policy
+const retainAnnotation = "faros.pusher.com/retain"
+
+// partitionLeftovers splits the leftover resources into those that should be
+// deleted and those that should be retained according to the prune policy
+func partitionLeftovers(policy farosv1alpha1.PrunePolicy, leftovers map[string]farosv1alpha1.GitTrackObjectInterface) (map[string]farosv1alpha1.GitTrackObjectInterface, map[string]farosv1alpha1.GitTrackObjectInterface) {
+	toDelete := make(map[string]farosv1alpha1.GitTrackObjectInterface)
+	toRetain := make(map[string]farosv1alpha1.GitTrackObjectInterface)
+	for name, obj := range leftovers {
+		switch {
+		case policy == farosv1alpha1.PrunePolicyRetain:
+			toRetain[name] = obj
+		case policy == farosv1alpha1.PrunePolicyRetainAnnotated && hasRetainAnnotation(obj):
+			toRetain[name] = obj
+		default:
+			toDelete[name] = obj
+		}
+	}
+	return toDelete, toRetain
+}
+
+// hasRetainAnnotation returns whether the GitTrackObject or any of the
+// manifests it contains has the retain annotation set to true
+func hasRetainAnnotation(obj farosv1alpha1.GitTrackObjectInterface) bool {
+	if obj.GetAnnotations()[retainAnnotation] == "true" {
+		return true
+	}
+	data, err := gittrackobjectutils.ChildData(obj.GetSpec())
+	if err != nil {
+		return false
+	}
+	children, err := utils.YAMLToUnstructuredSlice(data)
+	if err != nil {
+		return false
+	}
+	for _, child := range children {
+		if child.GetAnnotations()[retainAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// retainResources labels any resources that are present in the given map as
+// orphaned, recording when they were orphaned, without deleting them
+func (r *ReconcileGitTrack) retainResources(owner *farosv1alpha1.GitTrack, leftovers map[string]farosv1alpha1.GitTrackObjectInterface) error {
+	for name, obj := range leftovers {
+		labels := obj.GetLabels()
+		if _, ok := labels[gittrackutils.OrphanedLabel]; ok {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[gittrackutils.OrphanedLabel] = "true"
+		obj.SetLabels(labels)
+
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[gittrackutils.OrphanedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+		obj.SetAnnotations(annotations)
+		if err := r.Update(context.TODO(), obj); err != nil {
+			return fmt.Errorf("failed to label child '%s' as orphaned: %v", name, err)
+		}
+		r.log.V(0).Info("Child retained by prune policy", "child name", name)
+		r.recorder.Eventf(owner, apiv1.EventTypeNormal, "ChildRetained", "Retained child '%s' removed from the repository", name)
+	}
+	return nil
+}
+
+// clearOrphanedLabel removes the orphaned label and annotation from the
+// GitTrackObject if they are present
+func (r *ReconcileGitTrack) clearOrphanedLabel(obj farosv1alpha1.GitTrackObjectInterface) error {
+	labels := obj.GetLabels()
+	if _, ok := labels[gittrackutils.OrphanedLabel]; !ok {
+		return nil
+	}
+	delete(labels, gittrackutils.OrphanedLabel)
+	obj.SetLabels(labels)
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, gittrackutils.OrphanedAtAnnotation)
+	obj.SetAnnotations(annotations)
+	return r.Update(context.TODO(), obj)
+}
+
+// approveDestructiveChangesAnnotation is set on a GitTrack to the ID of its
+// pending destructive changes to approve them
+const approveDestructiveChangesAnnotation = "faros.pusher.com/approve-destructive-changes"
+
+// holdForDestructiveChanges plans the changes a sync would make and, if it
+// would delete or recreate more children than the destructive change
+// threshold, holds back all of the changes until they have been approved. It
+// returns whether the changes were held back.
+func (r *ReconcileGitTrack) holdForDestructiveChanges(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, existing map[string]farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) (bool, error) {
+	// Nothing is deleted or recreated in dry-run mode
+	if farosflags.DestructiveChangeThreshold <= 0 || farosflags.DryRun {
+		return false, nil
+	}
+
+	// Plan into a copy so that nothing is counted twice if the sync goes ahead
+	held := opts.copy()
+	changes, err := r.planChanges(gt, objects, objectFiles, existing, held)
+	if err != nil {
+		return false, fmt.Errorf("failed to plan changes: %v", err)
+	}
+	pending := pendingDestructiveChanges(gt, changes)
+	if pending == nil {
+		return false, nil
+	}
+
+	count := len(pending.Deletions) + len(pending.Recreations)
+	*opts = *held
+	opts.pending = pending
+	opts.upToDateError = fmt.Errorf("deleting or recreating %d children requires approval, set the %s annotation to %q", count, approveDestructiveChangesAnnotation, pending.ID)
+	opts.upToDateReason = gittrackutils.DestructiveChangesPending
+	opts.gcError = opts.upToDateError
+	opts.gcReason = gittrackutils.DestructiveChangesPending
+	opts.childrenOutOfSync = changeStatuses(changes)
+	if gt.Status.PendingDestructiveChanges == nil || gt.Status.PendingDestructiveChanges.ID != pending.ID {
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "ApprovalRequired", "Deleting or recreating %d children requires approval", count)
+	}
+	return true, nil
+}
+
+// pendingDestructiveChanges returns the deletions and recreations among the
+// planned changes that await approval, or nil if they are under the threshold
+// or have already been approved
+func pendingDestructiveChanges(owner *farosv1alpha1.GitTrack, changes []plannedChange) *farosv1alpha1.PendingDestructiveChanges {
+	threshold := farosflags.DestructiveChangeThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	deletions := []string{}
+	recreations := []string{}
+	for _, change := range changes {
+		switch change.action {
+		case actionDelete:
+			deletions = append(deletions, change.name)
+		case actionRecreate:
+			recreations = append(recreations, change.name)
+		}
+	}
+	if len(deletions)+len(recreations) <= threshold {
+		return nil
+	}
+	sort.Strings(deletions)
+	sort.Strings(recreations)
+
+	// Identify the set of changes so that an approval only applies to the
+	// changes that were reviewed
+	entries := append([]string{}, deletions...)
+	for _, name := range recreations {
+		entries = append(entries, fmt.Sprintf("%s:%s", actionRecreate, name))
+	}
+	hash := sha256.Sum256([]byte(strings.Join(entries, ",")))
+	id := hex.EncodeToString(hash[:])[:16]
+	if owner.GetAnnotations()[approveDestructiveChangesAnnotation] == id {
+		return nil
+	}
+
+	pending := &farosv1alpha1.PendingDestructiveChanges{
+		ID:        id,
+		Deletions: deletions,
+	}
+	if len(recreations) > 0 {
+		pending.Recreations = recreations
+	}
+	return pending
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	farosflags "github.com/pusher/faros/pkg/flags"
+)
+
+var _ = Describe("Prune Suite", func() {
+	Context("partitionLeftovers", func() {
+		var leftovers map[string]farosv1alpha1.GitTrackObjectInterface
+
+		BeforeEach(func() {
+			annotated := &farosv1alpha1.GitTrackObject{}
+			annotated.SetAnnotations(map[string]string{retainAnnotation: "true"})
+			leftovers = map[string]farosv1alpha1.GitTrackObjectInterface{
+				"default/annotated":   annotated,
+				"default/unannotated": &farosv1alpha1.GitTrackObject{},
+			}
+		})
+
+		It("deletes all leftovers with the Delete policy", func() {
+			toDelete, toRetain := partitionLeftovers(farosv1alpha1.PrunePolicyDelete, leftovers)
+			Expect(toDelete).To(HaveLen(2))
+			Expect(toRetain).To(BeEmpty())
+		})
+
+		It("retains all leftovers with the Retain policy", func() {
+			toDelete, toRetain := partitionLeftovers(farosv1alpha1.PrunePolicyRetain, leftovers)
+			Expect(toDelete).To(BeEmpty())
+			Expect(toRetain).To(HaveLen(2))
+		})
+
+		It("retains only annotated leftovers with the RetainAnnotated policy", func() {
+			toDelete, toRetain := partitionLeftovers(farosv1alpha1.PrunePolicyRetainAnnotated, leftovers)
+			Expect(toDelete).To(HaveKey("default/unannotated"))
+			Expect(toRetain).To(HaveKey("default/annotated"))
+		})
+	})
+
+	Context("pendingDestructiveChanges", func() {
+		var changes []plannedChange
+		var gt *farosv1alpha1.GitTrack
+
+		BeforeEach(func() {
+			farosflags.DestructiveChangeThreshold = 1
+			gt = &farosv1alpha1.GitTrack{}
+			changes = []plannedChange{
+				{name: "default/b", action: actionDelete},
+				{name: "default/a", action: actionDelete},
+				{name: "default/c", action: actionUpdate},
+				{name: "default/d", action: actionCreate},
+			}
+		})
+
+		AfterEach(func() {
+			farosflags.DestructiveChangeThreshold = 0
+		})
+
+		It("returns nothing when approval is disabled", func() {
+			farosflags.DestructiveChangeThreshold = 0
+			Expect(pendingDestructiveChanges(gt, changes)).To(BeNil())
+		})
+
+		It("returns nothing when under the threshold", func() {
+			farosflags.DestructiveChangeThreshold = 2
+			Expect(pendingDestructiveChanges(gt, changes)).To(BeNil())
+		})
+
+		It("returns the sorted deletions when over the threshold", func() {
+			pending := pendingDestructiveChanges(gt, changes)
+			Expect(pending).NotTo(BeNil())
+			Expect(pending.ID).NotTo(BeEmpty())
+			Expect(pending.Deletions).To(Equal([]string{"default/a", "default/b"}))
+			Expect(pending.Recreations).To(BeEmpty())
+		})
+
+		It("returns nothing once the changes are approved", func() {
+			pending := pendingDestructiveChanges(gt, changes)
+			gt.SetAnnotations(map[string]string{approveDestructiveChangesAnnotation: pending.ID})
+			Expect(pendingDestructiveChanges(gt, changes)).To(BeNil())
+		})
+
+		It("requires a new approval when the deletions change", func() {
+			pending := pendingDestructiveChanges(gt, changes)
+			gt.SetAnnotations(map[string]string{approveDestructiveChangesAnnotation: pending.ID})
+			changes = append(changes, plannedChange{name: "default/e", action: actionDelete})
+			Expect(pendingDestructiveChanges(gt, changes)).NotTo(BeNil())
+		})
+
+		Context("with recreations", func() {
+			BeforeEach(func() {
+				farosflags.DestructiveChangeThreshold = 2
+				changes = []plannedChange{
+					{name: "default/b", action: actionRecreate},
+					{name: "default/a", action: actionRecreate},
+					{name: "default/c", action: actionRecreate},
+					{name: "default/d", action: actionUpdate},
+				}
+			})
+
+			It("returns the sorted recreations when they alone exceed the threshold", func() {
+				pending := pendingDestructiveChanges(gt, changes)
+				Expect(pending).NotTo(BeNil())
+				Expect(pending.Deletions).To(BeEmpty())
+				Expect(pending.Recreations).To(Equal([]string{"default/a", "default/b", "default/c"}))
+			})
+
+			It("counts recreations and deletions together", func() {
+				changes = []plannedChange{
+					{name: "default/a", action: actionRecreate},
+					{name: "default/b", action: actionDelete},
+					{name: "default/c", action: actionDelete},
+				}
+				pending := pendingDestructiveChanges(gt, changes)
+				Expect(pending).NotTo(BeNil())
+				Expect(pending.Deletions).To(Equal([]string{"default/b", "default/c"}))
+				Expect(pending.Recreations).To(Equal([]string{"default/a"}))
+			})
+
+			It("requires a new approval when a deletion becomes a recreation", func() {
+				pending := pendingDestructiveChanges(gt, changes)
+				changes[0].action = actionDelete
+				Expect(pendingDestructiveChanges(gt, changes).ID).NotTo(Equal(pending.ID))
+			})
+		})
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	gitstore "github.com/pusher/git-store"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// commitSHA matches a full git commit SHA
+var commitSHA = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// revisionOpts collects the outcome of a sync so that it can be recorded as a
+// GitTrackRevision
+type revisionOpts struct {
+	files    []string
+	children []farosv1alpha1.GitTrackRevisionChild
+	rollback *farosv1alpha1.GitTrackRollback
+
+	// manifests are the rendered objects, kept to be backed up
+	manifests []manifest
+}
+
+// setFiles records the names of the files read from the repository
+func (opts *revisionOpts) setFiles(files map[string]*gitstore.File) {
+	opts.files = []string{}
+	for name := range files {
+		opts.files = append(opts.files, name)
+	}
+	sort.Strings(opts.files)
+}
+
+// setManifests records the rendered objects and the files they were read
+// from. The objects are copied as they are modified while being applied.
+func (opts *revisionOpts) setManifests(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string) {
+	opts.manifests = []manifest{}
+	for _, obj := range objects {
+		opts.manifests = append(opts.manifests, manifest{file: objectFiles[obj], object: obj.DeepCopy()})
+	}
+}
+
+// addChild records the result of handling a child
+func (opts *revisionOpts) addChild(res result) {
+	child := farosv1alpha1.GitTrackRevisionChild{
+		Name:   res.NamespacedName,
+		Kind:   res.Kind,
+		Result: res.Applied,
+	}
+	switch {
+	case res.Error != nil:
+		child.Message = res.Error.Error()
+	case res.Ignored:
+		child.Message = res.Reason
+	}
+	opts.children = append(opts.children, child)
+}
+
+// addPruned records the children deleted by the prune policy
+func (opts *revisionOpts) addPruned(pruned map[string]farosv1alpha1.GitTrackObjectInterface) {
+	for name, obj := range pruned {
+		opts.children = append(opts.children, farosv1alpha1.GitTrackRevisionChild{
+			Name:   name,
+			Kind:   obj.GetSpec().Kind,
+			Result: farosv1alpha1.ChildApplyResultPruned,
+		})
+	}
+}
+
+// changed returns whether the sync modified any children or synced a
+// different reference to the last sync, syncs that change nothing are not
+// recorded
+func (opts *revisionOpts) changed(gt *farosv1alpha1.GitTrack) bool {
+	if gt.Status.LastSyncedReference != syncReference(gt, opts.rollback) {
+		return true
+	}
+	for _, child := range opts.children {
+		switch child.Result {
+		case farosv1alpha1.ChildApplyResultCreated, farosv1alpha1.ChildApplyResultUpdated, farosv1alpha1.ChildApplyResultPruned:
+			return true
+		}
+	}
+	return false
+}
+
+// revisionHistoryLimit returns the number of GitTrackRevisions to keep for the
+// GitTrack
+func revisionHistoryLimit(gt *farosv1alpha1.GitTrack) int {
+	if gt.Spec.RevisionHistoryLimit != nil {
+		return int(*gt.Spec.RevisionHistoryLimit)
+	}
+	return farosflags.RevisionHistoryLimit
+}
+
+// newRevision constructs a GitTrackRevision recording the sync of the GitTrack
+func newRevision(gt *farosv1alpha1.GitTrack, opts *revisionOpts, syncTime time.Time) *farosv1alpha1.GitTrackRevision {
+	children := append([]farosv1alpha1.GitTrackRevisionChild{}, opts.children...)
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name < children[j].Name
+	})
+
+	revision := &farosv1alpha1.GitTrackRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", gt.Name),
+			Namespace:    gt.Namespace,
+			Labels: map[string]string{
+				gittrackutils.GitTrackLabel: gt.Name,
+			},
+		},
+		Spec: farosv1alpha1.GitTrackRevisionSpec{
+			GitTrack:   gt.Name,
+			Repository: gt.Spec.Repository,
+			Reference:  syncReference(gt, opts.rollback),
+			SyncTime:   metav1.NewTime(syncTime),
+			Files:      opts.files,
+			Children:   children,
+		},
+	}
+	// The commit synced is only known when the reference is a commit
+	if commitSHA.MatchString(revision.Spec.Reference) {
+		revision.Spec.Commit = revision.Spec.Reference
+	}
+	return revision
+}
+
+// recordRevision creates a GitTrackRevision for a successful sync which
+// changed the cluster and prunes revisions beyond the history limit
+func (r *ReconcileGitTrack) recordRevision(gt *farosv1alpha1.GitTrack, sOpts *statusOpts, rOpts *revisionOpts) error {
+	if !sOpts.synced() || !rOpts.changed(gt) {
+		return nil
+	}
+	limit := revisionHistoryLimit(gt)
+	if limit <= 0 {
+		return r.pruneRevisions(gt, 0, "")
+	}
+
+	revision := newRevision(gt, rOpts, time.Now())
+	if err := controllerutil.SetControllerReference(gt, revision, r.scheme); err != nil {
+		return fmt.Errorf("failed to set owner of GitTrackRevision: %v", err)
+	}
+	if err := r.Create(context.TODO(), revision); err != nil {
+		return fmt.Errorf("failed to create GitTrackRevision: %v", err)
+	}
+	r.log.V(1).Info("Revision recorded", "revision", revision.Name)
+	r.recorder.Eventf(gt, apiv1.EventTypeNormal, "RevisionRecorded", "Recorded revision '%s' for '%s'", revision.Name, revision.Spec.Reference)
+
+	return r.pruneRevisions(gt, limit-1, revision.Name)
+}
+
+// pruneRevisions deletes the oldest GitTrackRevisions of the GitTrack so that
+// at most keep remain, not counting the revision named exclude
+func (r *ReconcileGitTrack) pruneRevisions(gt *farosv1alpha1.GitTrack, keep int, exclude string) error {
+	list := &farosv1alpha1.GitTrackRevisionList{}
+	err := r.List(context.TODO(), list, client.InNamespace(gt.Namespace))
+	if err != nil {
+		return fmt.Errorf("failed to list GitTrackRevisions: %v", err)
+	}
+
+	revisions := []farosv1alpha1.GitTrackRevision{}
+	for _, revision := range list.Items {
+		if revision.Name != exclude && metav1.IsControlledBy(&revision, gt) {
+			revisions = append(revisions, revision)
+		}
+	}
+	if len(revisions) <= keep {
+		return nil
+	}
+
+	// Newest first, so that everything after keep is deleted
+	sort.Slice(revisions, func(i, j int) bool {
+		if !revisions[i].Spec.SyncTime.Equal(&revisions[j].Spec.SyncTime) {
+			return revisions[j].Spec.SyncTime.Before(&revisions[i].Spec.SyncTime)
+		}
+		return revisions[i].Name > revisions[j].Name
+	})
+	for i := keep; i < len(revisions); i++ {
+		if err := r.Delete(context.TODO(), &revisions[i]); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete GitTrackRevision '%s': %v", revisions[i].Name, err)
+		}
+		r.log.V(1).Info("Revision deleted", "revision", revisions[i].Name)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Revision Suite", func() {
+	Context("revisionOpts", func() {
+		var opts *revisionOpts
+		var gt *farosv1alpha1.GitTrack
+
+		BeforeEach(func() {
+			opts = &revisionOpts{}
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec:       farosv1alpha1.GitTrackSpec{Reference: "master"},
+				Status:     farosv1alpha1.GitTrackStatus{LastSyncedReference: "master"},
+			}
+			opts.addChild(successResult("default/a", 0, true))
+		})
+
+		It("is unchanged when no children were modified", func() {
+			Expect(opts.changed(gt)).To(BeFalse())
+		})
+
+		It("is changed when the reference differs from the last synced reference", func() {
+			gt.Spec.Reference = "v1.0.0"
+			Expect(opts.changed(gt)).To(BeTrue())
+		})
+
+		It("is changed when a child was updated", func() {
+			res := successResult("default/b", 0, false)
+			res.Applied = farosv1alpha1.ChildApplyResultUpdated
+			opts.addChild(res)
+			Expect(opts.changed(gt)).To(BeTrue())
+		})
+
+		It("is changed when a child was pruned", func() {
+			opts.addPruned(map[string]farosv1alpha1.GitTrackObjectInterface{
+				"default/c": &farosv1alpha1.GitTrackObject{},
+			})
+			Expect(opts.changed(gt)).To(BeTrue())
+		})
+
+		It("records the reason a child was ignored", func() {
+			opts.addChild(ignoreResult("default/d", "namespace not managed"))
+			Expect(opts.children[1].Result).To(Equal(farosv1alpha1.ChildApplyResultIgnored))
+			Expect(opts.children[1].Message).To(Equal("namespace not managed"))
+		})
+
+		It("only records a commit when the reference is a commit SHA", func() {
+			Expect(newRevision(gt, opts, time.Now()).Spec.Commit).To(BeEmpty())
+			gt.Spec.Reference = "a14443638218c782b84cae56a14f1090ee9e5c9c"
+			Expect(newRevision(gt, opts, time.Now()).Spec.Commit).To(Equal(gt.Spec.Reference))
+		})
+
+		It("records the commit rolled back to", func() {
+			opts.rollback = &farosv1alpha1.GitTrackRollback{Revision: "example-abcde", Commit: "a14443638218c782b84cae56a14f1090ee9e5c9c"}
+			Expect(opts.changed(gt)).To(BeTrue())
+			revision := newRevision(gt, opts, time.Now())
+			Expect(revision.Spec.Reference).To(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+			Expect(revision.Spec.Commit).To(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+		})
+
+		It("uses the GitTrack's history limit over the default", func() {
+			Expect(revisionHistoryLimit(gt)).To(Equal(farosflags.RevisionHistoryLimit))
+			limit := int32(3)
+			gt.Spec.RevisionHistoryLimit = &limit
+			Expect(revisionHistoryLimit(gt)).To(Equal(3))
+		})
+	})
+})
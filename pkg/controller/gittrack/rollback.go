@@ -0,0 +1,68 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// syncReference returns the reference synced for the GitTrack, which is the
+// commit of the revision it is rolled back to, if any
+func syncReference(gt *farosv1alpha1.GitTrack, rollback *farosv1alpha1.GitTrackRollback) string {
+	if rollback != nil {
+		return rollback.Commit
+	}
+	return gt.Spec.Reference
+}
+
+// resolveRollback looks up the commit of the revision named by
+// spec.rollbackTo. The commit is kept in the status once resolved so that the
+// rollback continues after the revision has been pruned from the history.
+func (r *ReconcileGitTrack) resolveRollback(gt *farosv1alpha1.GitTrack) (*farosv1alpha1.GitTrackRollback, error) {
+	name := gt.Spec.RollbackTo
+	if name == "" {
+		return nil, nil
+	}
+	if gt.Status.Rollback != nil && gt.Status.Rollback.Revision == name {
+		return gt.Status.Rollback.DeepCopy(), nil
+	}
+
+	revision := &farosv1alpha1.GitTrackRevision{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: gt.Namespace, Name: name}, revision)
+	if errors.IsNotFound(err) {
+		return nil, fmt.Errorf("revision '%s' not found", name)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get revision '%s': %v", name, err)
+	}
+	if !metav1.IsControlledBy(revision, gt) {
+		return nil, fmt.Errorf("revision '%s' does not belong to this GitTrack", name)
+	}
+	if revision.Spec.Repository != gt.Spec.Repository {
+		return nil, fmt.Errorf("revision '%s' is of repository '%s'", name, revision.Spec.Repository)
+	}
+	// Only commits identify what was synced, branches and tags may have moved
+	if revision.Spec.Commit == "" {
+		return nil, fmt.Errorf("revision '%s' did not record a commit", name)
+	}
+	return &farosv1alpha1.GitTrackRollback{Revision: name, Commit: revision.Spec.Commit}, nil
+}
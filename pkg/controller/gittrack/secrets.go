@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// referencedSecrets returns the names of the Secrets in its namespace that
+// the GitTrack reads while syncing
+func referencedSecrets(gt *farosv1alpha1.GitTrack) []string {
+	secrets := []string{}
+	if gt.Spec.DeployKey.SecretName != "" {
+		secrets = append(secrets, gt.Spec.DeployKey.SecretName)
+	}
+	for _, key := range gt.Spec.DeployKeys {
+		if key.DeployKey.SecretName != "" {
+			secrets = append(secrets, key.DeployKey.SecretName)
+		}
+	}
+	if gt.Spec.CommitStatus != nil && gt.Spec.CommitStatus.TokenSecret != nil {
+		secrets = append(secrets, gt.Spec.CommitStatus.TokenSecret.SecretName)
+	}
+	return secrets
+}
+
+// secretRequests returns a function that enqueues the GitTracks matching the
+// selector that reference a Secret when it changes, so that rotated
+// credentials are used straight away
+func secretRequests(c client.Client, selector labels.Selector, log logr.Logger) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		gts := &farosv1alpha1.GitTrackList{}
+		err := c.List(context.TODO(), gts, client.InNamespace(obj.Meta.GetNamespace()))
+		if err != nil {
+			log.Error(err, "unable to list GitTracks after Secret changed")
+			return nil
+		}
+		requests := []reconcile.Request{}
+		for i := range gts.Items {
+			gt := &gts.Items[i]
+			if !selector.Matches(labels.Set(gt.GetLabels())) {
+				continue
+			}
+			for _, name := range referencedSecrets(gt) {
+				if name != obj.Meta.GetName() {
+					continue
+				}
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: gt.Namespace, Name: gt.Name},
+				})
+				break
+			}
+		}
+		return requests
+	}
+}
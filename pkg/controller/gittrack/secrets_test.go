@@ -0,0 +1,52 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+var _ = Describe("Secrets Suite", func() {
+	Context("referencedSecrets", func() {
+		var gt *farosv1alpha1.GitTrack
+
+		BeforeEach(func() {
+			gt = &farosv1alpha1.GitTrack{}
+		})
+
+		It("returns nothing without credentials", func() {
+			Expect(referencedSecrets(gt)).To(BeEmpty())
+		})
+
+		It("returns the deploy key and commit status token Secrets", func() {
+			gt.Spec.DeployKey = farosv1alpha1.GitTrackDeployKey{SecretName: "deploy-key", Key: "id_rsa"}
+			gt.Spec.CommitStatus = &farosv1alpha1.GitTrackCommitStatus{
+				TokenSecret: &farosv1alpha1.GitTrackSecretReference{SecretName: "github-token", Key: "token"},
+			}
+			Expect(referencedSecrets(gt)).To(Equal([]string{"deploy-key", "github-token"}))
+		})
+
+		It("returns the Secrets of scoped deploy keys", func() {
+			gt.Spec.DeployKeys = []farosv1alpha1.GitTrackScopedDeployKey{
+				{SubPath: "payments", DeployKey: farosv1alpha1.GitTrackDeployKey{SecretName: "payments-deploy-key", Key: "id_rsa"}},
+			}
+			Expect(referencedSecrets(gt)).To(Equal([]string{"payments-deploy-key"}))
+		})
+	})
+})
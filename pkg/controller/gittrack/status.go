@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
@@ -31,6 +33,8 @@ type statusOpts struct {
 	discovered     int64
 	ignored        int64
 	inSync         int64
+	pruned         int64
+	retained       int64
 	parseError     error
 	parseReason    gittrackutils.ConditionReason
 	gitError       error
@@ -39,7 +43,80 @@ type statusOpts struct {
 	gcReason       gittrackutils.ConditionReason
 	upToDateError  error
 	upToDateReason gittrackutils.ConditionReason
+	healthError    error
+	healthReason   gittrackutils.ConditionReason
+	windowError    error
+	windowReason   gittrackutils.ConditionReason
+	policyError    error
+	policyReason   gittrackutils.ConditionReason
 	ignoredFiles   map[string]string
+	pending        *farosv1alpha1.PendingDestructiveChanges
+	plan           *farosv1alpha1.PendingPlan
+	rollback       *farosv1alpha1.GitTrackRollback
+	lastHealthy    *farosv1alpha1.GitTrackRollback
+	autoRollback   *farosv1alpha1.GitTrackRollback
+	syncToken      string
+
+	childrenOutOfSync []farosv1alpha1.GitTrackChildStatus
+	fileErrors        []farosv1alpha1.GitTrackFileError
+	policyViolations  []farosv1alpha1.GitTrackChildStatus
+	clusters          []farosv1alpha1.GitTrackClusterStatus
+	reference         string
+}
+
+// maxChildStatuses is the maximum number of children listed in the status of
+// a GitTrack
+const maxChildStatuses = 20
+
+// boundChildStatuses sorts the child statuses by name and truncates them to
+// at most maxChildStatuses
+func boundChildStatuses(statuses []farosv1alpha1.GitTrackChildStatus) []farosv1alpha1.GitTrackChildStatus {
+	if len(statuses) == 0 {
+		return nil
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	if len(statuses) > maxChildStatuses {
+		statuses = statuses[:maxChildStatuses]
+	}
+	return statuses
+}
+
+// maxFileErrors is the maximum number of file errors listed in the status of
+// a GitTrack
+const maxFileErrors = 20
+
+// boundFileErrors sorts the file errors by path and truncates them to at most
+// maxFileErrors
+func boundFileErrors(fileErrors []farosv1alpha1.GitTrackFileError) []farosv1alpha1.GitTrackFileError {
+	if len(fileErrors) == 0 {
+		return nil
+	}
+	sort.Slice(fileErrors, func(i, j int) bool {
+		if fileErrors[i].Path != fileErrors[j].Path {
+			return fileErrors[i].Path < fileErrors[j].Path
+		}
+		return fileErrors[i].Message < fileErrors[j].Message
+	})
+	if len(fileErrors) > maxFileErrors {
+		fileErrors = fileErrors[:maxFileErrors]
+	}
+	return fileErrors
+}
+
+// parseFileErrors converts the errors of the files that could not be parsed
+// into file errors
+func parseFileErrors(errs map[string]string) []farosv1alpha1.GitTrackFileError {
+	fileErrors := []farosv1alpha1.GitTrackFileError{}
+	for path, msg := range errs {
+		fileErrors = append(fileErrors, farosv1alpha1.GitTrackFileError{
+			Path:    path,
+			Class:   farosv1alpha1.FileErrorClassParse,
+			Message: strings.TrimSpace(msg),
+		})
+	}
+	return fileErrors
 }
 
 func newStatusOpts() *statusOpts {
@@ -48,7 +125,47 @@ func newStatusOpts() *statusOpts {
 		gitReason:      gittrackutils.StatusUnknown,
 		gcReason:       gittrackutils.StatusUnknown,
 		upToDateReason: gittrackutils.StatusUnknown,
+		healthReason:   gittrackutils.StatusUnknown,
+		windowReason:   gittrackutils.StatusUnknown,
+		policyReason:   gittrackutils.StatusUnknown,
+	}
+}
+
+// fetched returns whether the repository was fetched successfully
+func (opts *statusOpts) fetched() bool {
+	return opts.gitError == nil && opts.gitReason == gittrackutils.GitFetchSuccess
+}
+
+// changesPending returns whether changes to the children were held back by
+// the sync windows, until they are approved, until the canary children are
+// healthy or until the hooks have completed
+func (opts *statusOpts) changesPending() bool {
+	return heldBack(opts.upToDateReason)
+}
+
+// heldBack returns whether the ChildrenUpToDate reason means changes were
+// held back rather than failing
+func heldBack(reason gittrackutils.ConditionReason) bool {
+	return reason == gittrackutils.ChangesPending || reason == gittrackutils.ApprovalPending || reason == gittrackutils.CanaryInProgress ||
+		reason == gittrackutils.HookInProgress
+}
+
+// copy returns a copy of the options that can be modified independently
+func (opts *statusOpts) copy() *statusOpts {
+	c := *opts
+	c.ignoredFiles = make(map[string]string, len(opts.ignoredFiles))
+	for name, reason := range opts.ignoredFiles {
+		c.ignoredFiles[name] = reason
 	}
+	return &c
+}
+
+// synced returns whether the repository was fetched and all children were
+// updated and garbage collected successfully
+func (opts *statusOpts) synced() bool {
+	return opts.fetched() &&
+		opts.upToDateError == nil && opts.upToDateReason == gittrackutils.ChildrenUpdateSuccess &&
+		opts.gcError == nil && opts.gcReason != gittrackutils.StatusUnknown
 }
 
 func updateGitTrackStatus(gt *farosv1alpha1.GitTrack, opts *statusOpts) (updated bool) {
@@ -58,15 +175,48 @@ func updateGitTrackStatus(gt *farosv1alpha1.GitTrack, opts *statusOpts) (updated
 
 	status := gt.Status
 
+	status.ObservedGeneration = gt.GetGeneration()
 	status.ObjectsApplied = opts.applied
 	status.ObjectsDiscovered = opts.discovered
 	status.ObjectsIgnored = opts.ignored
 	status.ObjectsInSync = opts.inSync
+	status.ObjectsPruned = opts.pruned
+	status.ObjectsRetained = opts.retained
 	status.IgnoredFiles = opts.ignoredFiles
+	status.PendingDestructiveChanges = opts.pending
+	status.PendingPlan = opts.plan
+	status.Rollback = opts.rollback
+	if opts.autoRollback != nil {
+		// Keep the reason for the rollback until it is synced
+		status.Rollback = opts.autoRollback
+	}
+	status.LastHealthyRevision = opts.lastHealthy
+	status.ChildrenOutOfSync = opts.childrenOutOfSync
+	status.FileErrors = opts.fileErrors
+	status.PolicyViolations = opts.policyViolations
+	status.Clusters = opts.clusters
+	if opts.synced() {
+		status.LastSyncedReference = opts.reference
+	}
+	if opts.syncToken != "" {
+		status.LastSyncToken = opts.syncToken
+	}
 	setCondition(&status, farosv1alpha1.FilesParsedType, opts.parseError, opts.parseReason)
 	setCondition(&status, farosv1alpha1.FilesFetchedType, opts.gitError, opts.gitReason)
 	setCondition(&status, farosv1alpha1.ChildrenGarbageCollectedType, opts.gcError, opts.gcReason)
 	setCondition(&status, farosv1alpha1.ChildrenUpToDateType, opts.upToDateError, opts.upToDateReason)
+	setCondition(&status, farosv1alpha1.ChildrenHealthyType, opts.healthError, opts.healthReason)
+	setSuspended(&status, gt.Spec.Suspend)
+	if len(gt.Spec.SyncWindows) > 0 {
+		setCondition(&status, farosv1alpha1.SyncWindowOpenType, opts.windowError, opts.windowReason)
+	} else {
+		gittrackutils.RemoveGitTrackCondition(&status, farosv1alpha1.SyncWindowOpenType)
+	}
+	if gt.Spec.PolicyCheck != "" {
+		setCondition(&status, farosv1alpha1.PolicyCompliantType, opts.policyError, opts.policyReason)
+	} else {
+		gittrackutils.RemoveGitTrackCondition(&status, farosv1alpha1.PolicyCompliantType)
+	}
 
 	if !reflect.DeepEqual(gt.Status, status) {
 		gt.Status = status
@@ -84,6 +234,21 @@ func setCondition(status *farosv1alpha1.GitTrackStatus, condType farosv1alpha1.G
 			reason,
 			condErr.Error(),
 		)
+		cond.ObservedGeneration = status.ObservedGeneration
+		gittrackutils.SetGitTrackCondition(status, *cond)
+		return
+	}
+
+	// The stage was not reached as an earlier stage failed or held back the
+	// sync, so its outcome is unknown rather than successful
+	if reason == gittrackutils.StatusUnknown {
+		cond := gittrackutils.NewGitTrackCondition(
+			condType,
+			v1.ConditionUnknown,
+			reason,
+			"not evaluated as an earlier stage did not complete",
+		)
+		cond.ObservedGeneration = status.ObservedGeneration
 		gittrackutils.SetGitTrackCondition(status, *cond)
 		return
 	}
@@ -95,9 +260,45 @@ func setCondition(status *farosv1alpha1.GitTrackStatus, condType farosv1alpha1.G
 		reason,
 		"",
 	)
+	cond.ObservedGeneration = status.ObservedGeneration
+	gittrackutils.SetGitTrackCondition(status, *cond)
+}
+
+// setSuspended sets the Suspended condition if the GitTrack is suspended and
+// removes it otherwise
+func setSuspended(status *farosv1alpha1.GitTrackStatus, suspended bool) {
+	if !suspended {
+		gittrackutils.RemoveGitTrackCondition(status, farosv1alpha1.SuspendedType)
+		return
+	}
+	cond := gittrackutils.NewGitTrackCondition(
+		farosv1alpha1.SuspendedType,
+		v1.ConditionTrue,
+		gittrackutils.SuspendedBySpec,
+		"syncing is suspended by spec.suspend",
+	)
+	cond.ObservedGeneration = status.ObservedGeneration
 	gittrackutils.SetGitTrackCondition(status, *cond)
 }
 
+// updateSuspendedStatus sets the Suspended condition of the GitTrack, leaving
+// the rest of its status as it was when it was last synced
+func (r *ReconcileGitTrack) updateSuspendedStatus(original *farosv1alpha1.GitTrack) error {
+	gt := original.DeepCopy()
+	gt.Status.ObservedGeneration = gt.GetGeneration()
+	setSuspended(&gt.Status, true)
+	if reflect.DeepEqual(original.Status, gt.Status) {
+		return nil
+	}
+
+	err := r.Status().Update(context.TODO(), gt)
+	if err != nil {
+		return fmt.Errorf("unable to update GitTrack status: %v", err)
+	}
+	r.log.V(1).Info("Status updated")
+	return nil
+}
+
 // updateStatus calculates a new status for the GitTrack and then updates
 // the resource on the API if the status differs from before.
 func (r *ReconcileGitTrack) updateStatus(original *farosv1alpha1.GitTrack, opts *statusOpts) error {
@@ -105,12 +306,23 @@ func (r *ReconcileGitTrack) updateStatus(original *farosv1alpha1.GitTrack, opts
 	gt := original.DeepCopy()
 	gtUpdated := updateGitTrackStatus(gt, opts)
 
-	// If the status was modified, update the GitTrack on the API
-	if gtUpdated {
+	// Add the cleanup finalizer, the status is ignored by this update and so
+	// is restored before updating the status subresource
+	if ensureFinalizer(gt) {
+		status := gt.Status
 		err := r.Update(context.TODO(), gt)
 		if err != nil {
 			return fmt.Errorf("unable to update GitTrack: %v", err)
 		}
+		gt.Status = status
+	}
+
+	// If the status was modified, update the GitTrack on the API
+	if gtUpdated {
+		err := r.Status().Update(context.TODO(), gt)
+		if err != nil {
+			return fmt.Errorf("unable to update GitTrack status: %v", err)
+		}
 		r.log.V(1).Info("Status updated")
 	}
 	return nil
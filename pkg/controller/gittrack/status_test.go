@@ -0,0 +1,155 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Status Suite", func() {
+	Context("statusOpts", func() {
+		var opts *statusOpts
+
+		BeforeEach(func() {
+			opts = newStatusOpts()
+			opts.gitReason = gittrackutils.GitFetchSuccess
+			opts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
+			opts.gcReason = gittrackutils.GCSuccess
+		})
+
+		It("is fetched and synced when all steps succeed", func() {
+			Expect(opts.fetched()).To(BeTrue())
+			Expect(opts.synced()).To(BeTrue())
+		})
+
+		It("is fetched but not synced when garbage collection fails", func() {
+			opts.gcError = fmt.Errorf("error")
+			opts.gcReason = gittrackutils.ErrorDeletingChildren
+			Expect(opts.fetched()).To(BeTrue())
+			Expect(opts.synced()).To(BeFalse())
+		})
+
+		It("is neither fetched nor synced when fetching fails", func() {
+			opts.gitError = fmt.Errorf("error")
+			opts.gitReason = gittrackutils.ErrorFetchingFiles
+			Expect(opts.fetched()).To(BeFalse())
+			Expect(opts.synced()).To(BeFalse())
+		})
+	})
+
+	Context("updateGitTrackStatus", func() {
+		var gt *farosv1alpha1.GitTrack
+		var opts *statusOpts
+
+		var conditionStatus = func(condType farosv1alpha1.GitTrackConditionType) v1.ConditionStatus {
+			cond := gittrackutils.GetGitTrackCondition(gt.Status, condType)
+			Expect(cond).NotTo(BeNil())
+			return cond.Status
+		}
+
+		BeforeEach(func() {
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec:       farosv1alpha1.GitTrackSpec{Repository: "https://github.com/pusher/faros", Reference: "master"},
+			}
+			opts = newStatusOpts()
+		})
+
+		It("sets a condition for each stage when all stages succeed", func() {
+			opts.gitReason = gittrackutils.GitFetchSuccess
+			opts.parseReason = gittrackutils.FileParseSuccess
+			opts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
+			opts.healthReason = gittrackutils.ChildrenHealthy
+			opts.gcReason = gittrackutils.GCSuccess
+			Expect(updateGitTrackStatus(gt, opts)).To(BeTrue())
+			Expect(conditionStatus(farosv1alpha1.FilesFetchedType)).To(Equal(v1.ConditionTrue))
+			Expect(conditionStatus(farosv1alpha1.FilesParsedType)).To(Equal(v1.ConditionTrue))
+			Expect(conditionStatus(farosv1alpha1.ChildrenUpToDateType)).To(Equal(v1.ConditionTrue))
+			Expect(conditionStatus(farosv1alpha1.ChildrenHealthyType)).To(Equal(v1.ConditionTrue))
+			Expect(conditionStatus(farosv1alpha1.ChildrenGarbageCollectedType)).To(Equal(v1.ConditionTrue))
+		})
+
+		It("sets the conditions of stages after a failed stage to Unknown", func() {
+			opts.gitError = errors.New("unable to fetch")
+			opts.gitReason = gittrackutils.ErrorFetchingFiles
+			Expect(updateGitTrackStatus(gt, opts)).To(BeTrue())
+			Expect(conditionStatus(farosv1alpha1.FilesFetchedType)).To(Equal(v1.ConditionFalse))
+			Expect(conditionStatus(farosv1alpha1.FilesParsedType)).To(Equal(v1.ConditionUnknown))
+			Expect(conditionStatus(farosv1alpha1.ChildrenUpToDateType)).To(Equal(v1.ConditionUnknown))
+			Expect(conditionStatus(farosv1alpha1.ChildrenHealthyType)).To(Equal(v1.ConditionUnknown))
+			Expect(conditionStatus(farosv1alpha1.ChildrenGarbageCollectedType)).To(Equal(v1.ConditionUnknown))
+		})
+	})
+
+	Context("boundChildStatuses", func() {
+		It("returns nothing when all children are in sync", func() {
+			Expect(boundChildStatuses([]farosv1alpha1.GitTrackChildStatus{})).To(BeNil())
+		})
+
+		It("sorts the children by name", func() {
+			statuses := boundChildStatuses([]farosv1alpha1.GitTrackChildStatus{
+				{Name: "default/b", Kind: "Service", Reason: "child updated"},
+				{Name: "default/a", Kind: "Deployment", Reason: "child created"},
+			})
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Name).To(Equal("default/a"))
+			Expect(statuses[1].Name).To(Equal("default/b"))
+		})
+
+		It("lists at most maxChildStatuses children", func() {
+			statuses := []farosv1alpha1.GitTrackChildStatus{}
+			for i := 0; i < maxChildStatuses+5; i++ {
+				statuses = append(statuses, farosv1alpha1.GitTrackChildStatus{Name: fmt.Sprintf("default/%03d", i)})
+			}
+			Expect(boundChildStatuses(statuses)).To(HaveLen(maxChildStatuses))
+		})
+	})
+
+	Context("boundFileErrors", func() {
+		It("returns nothing when there are no errors", func() {
+			Expect(boundFileErrors(parseFileErrors(map[string]string{}))).To(BeNil())
+		})
+
+		It("sorts the errors by path and message", func() {
+			fileErrors := boundFileErrors([]farosv1alpha1.GitTrackFileError{
+				{Path: "b.yaml", Class: farosv1alpha1.FileErrorClassParse, Message: "unable to parse"},
+				{Path: "a.yaml", Class: farosv1alpha1.FileErrorClassApply, Message: "default/a2: failed"},
+				{Path: "a.yaml", Class: farosv1alpha1.FileErrorClassInvalidObject, Message: "default/a1: failed"},
+			})
+			Expect(fileErrors).To(HaveLen(3))
+			Expect(fileErrors[0].Message).To(Equal("default/a1: failed"))
+			Expect(fileErrors[1].Message).To(Equal("default/a2: failed"))
+			Expect(fileErrors[2].Path).To(Equal("b.yaml"))
+		})
+
+		It("lists at most maxFileErrors errors", func() {
+			fileErrors := []farosv1alpha1.GitTrackFileError{}
+			for i := 0; i < maxFileErrors+5; i++ {
+				fileErrors = append(fileErrors, farosv1alpha1.GitTrackFileError{Path: fmt.Sprintf("%03d.yaml", i)})
+			}
+			Expect(boundFileErrors(fileErrors)).To(HaveLen(maxFileErrors))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	"github.com/pusher/faros/pkg/render"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// gitTrackSyncMode returns the sync mode of the GitTrack, Partial unless set
+func gitTrackSyncMode(gt *farosv1alpha1.GitTrack) farosv1alpha1.SyncMode {
+	if gt.Spec.SyncMode == "" {
+		return farosv1alpha1.SyncModePartial
+	}
+	return gt.Spec.SyncMode
+}
+
+// invalidObjects returns the errors of the objects that cannot be turned into
+// (Cluster)GitTrackObjects, for example because their kind is unknown
+func (r *ReconcileGitTrack) invalidObjects(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string) []farosv1alpha1.GitTrackFileError {
+	fileErrors := []farosv1alpha1.GitTrackFileError{}
+	for _, u := range objects {
+		if _, err := r.newGitTrackObjectInterface(u); err != nil {
+			name := strings.TrimLeft(fmt.Sprintf("%s/%s", u.GetNamespace(), render.ObjectName(u)), "/")
+			fileErrors = append(fileErrors, farosv1alpha1.GitTrackFileError{
+				Path:    objectFiles[u],
+				Class:   farosv1alpha1.FileErrorClassInvalidObject,
+				Message: fmt.Sprintf("%s: %v", name, err),
+			})
+		}
+	}
+	return fileErrors
+}
+
+// holdForInvalidFiles leaves every child as it is when the GitTrack is in the
+// Strict sync mode and any of its files cannot be synced, either because they
+// could not be parsed or because an object they define is invalid. The files
+// are listed in the status and the FilesParsed condition.
+func (r *ReconcileGitTrack) holdForInvalidFiles(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, opts *statusOpts) bool {
+	if gitTrackSyncMode(gt) != farosv1alpha1.SyncModeStrict {
+		return false
+	}
+	invalid := r.invalidObjects(objects, objectFiles)
+	if len(opts.ignoredFiles) == 0 && len(invalid) == 0 {
+		return false
+	}
+
+	errs := []string{}
+	files := make(map[string]bool)
+	for file, reason := range opts.ignoredFiles {
+		errs = append(errs, fmt.Sprintf("%s: %s", file, strings.TrimSpace(reason)))
+		files[file] = true
+	}
+	for _, fileErr := range invalid {
+		errs = append(errs, fmt.Sprintf("%s: %s", fileErr.Path, fileErr.Message))
+		files[fileErr.Path] = true
+	}
+	sort.Strings(errs)
+	opts.fileErrors = boundFileErrors(append(opts.fileErrors, invalid...))
+	opts.parseError = fmt.Errorf("sync blocked by %d files that cannot be synced:\n%s", len(files), strings.Join(errs, ",\n"))
+	opts.parseReason = gittrackutils.StrictSyncBlocked
+	r.recorder.Eventf(gt, apiv1.EventTypeWarning, "SyncBlocked", "Not syncing as %d files cannot be synced", len(files))
+	return true
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+)
+
+// pendingSyncToken returns the value of the sync-now annotation if it has
+// not yet been handled, or an empty string otherwise
+func pendingSyncToken(gt *farosv1alpha1.GitTrack) string {
+	token := gt.GetAnnotations()[gittrackutils.SyncNowAnnotation]
+	if token == gt.Status.LastSyncToken {
+		return ""
+	}
+	return token
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Sync Now Suite", func() {
+	Context("pendingSyncToken", func() {
+		var gt *farosv1alpha1.GitTrack
+
+		BeforeEach(func() {
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			}
+		})
+
+		It("returns nothing when a sync has not been requested", func() {
+			Expect(pendingSyncToken(gt)).To(BeEmpty())
+		})
+
+		It("returns the token when a sync has been requested", func() {
+			gt.SetAnnotations(map[string]string{gittrackutils.SyncNowAnnotation: "deploy-1"})
+			Expect(pendingSyncToken(gt)).To(Equal("deploy-1"))
+		})
+
+		It("returns nothing once the token has been handled", func() {
+			gt.SetAnnotations(map[string]string{gittrackutils.SyncNowAnnotation: "deploy-1"})
+			gt.Status.LastSyncToken = "deploy-1"
+			Expect(pendingSyncToken(gt)).To(BeEmpty())
+		})
+
+		It("returns a new token after an earlier one was handled", func() {
+			gt.SetAnnotations(map[string]string{gittrackutils.SyncNowAnnotation: "deploy-2"})
+			gt.Status.LastSyncToken = "deploy-1"
+			Expect(pendingSyncToken(gt)).To(Equal("deploy-2"))
+		})
+	})
+})
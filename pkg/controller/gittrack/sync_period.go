@@ -0,0 +1,37 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// requeueAfterSyncPeriod requeues the GitTrack after the sync period once it
+// differs from the period the informer caches resync with, so that a shorter
+// sync period reloaded from the configuration file takes effect without a
+// restart
+func (r *ReconcileGitTrack) requeueAfterSyncPeriod(result reconcile.Result) reconcile.Result {
+	period := farosflags.SyncPeriod()
+	if period == 0 || period == r.informerSyncPeriod {
+		return result
+	}
+	if result.RequeueAfter == 0 || period < result.RequeueAfter {
+		result.RequeueAfter = period
+	}
+	return result
+}
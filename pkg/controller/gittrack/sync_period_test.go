@@ -0,0 +1,63 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Sync Period Suite", func() {
+	Context("requeueAfterSyncPeriod", func() {
+		var r *ReconcileGitTrack
+		var original string
+
+		BeforeEach(func() {
+			r = &ReconcileGitTrack{informerSyncPeriod: 5 * time.Minute}
+			original = farosflags.FlagSet.Lookup("sync-period").Value.String()
+		})
+
+		AfterEach(func() {
+			Expect(farosflags.FlagSet.Set("sync-period", original)).To(Succeed())
+		})
+
+		It("leaves the result alone while the informers resync with the sync period", func() {
+			Expect(farosflags.FlagSet.Set("sync-period", "5m")).To(Succeed())
+			Expect(r.requeueAfterSyncPeriod(reconcile.Result{})).To(Equal(reconcile.Result{}))
+		})
+
+		It("requeues after a reloaded sync period", func() {
+			Expect(farosflags.FlagSet.Set("sync-period", "1m")).To(Succeed())
+			Expect(r.requeueAfterSyncPeriod(reconcile.Result{})).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+		})
+
+		It("keeps an earlier requeue", func() {
+			Expect(farosflags.FlagSet.Set("sync-period", "1m")).To(Succeed())
+			result := reconcile.Result{RequeueAfter: 30 * time.Second}
+			Expect(r.requeueAfterSyncPeriod(result)).To(Equal(result))
+		})
+
+		It("does not requeue when resyncs are disabled", func() {
+			Expect(farosflags.FlagSet.Set("sync-period", "0s")).To(Succeed())
+			Expect(r.requeueAfterSyncPeriod(reconcile.Result{})).To(Equal(reconcile.Result{}))
+		})
+	})
+})
@@ -0,0 +1,193 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/gitwrite"
+	billy "gopkg.in/src-d/go-billy.v4"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultSyncReportPath is the directory sync reports are written to unless
+// the GitTrack sets another
+const defaultSyncReportPath = ".faros/reports"
+
+// syncResult is the overall outcome of a sync
+type syncResult string
+
+const (
+	syncResultSucceeded syncResult = "Succeeded"
+	syncResultFailed    syncResult = "Failed"
+	syncResultPending   syncResult = "Pending"
+)
+
+// syncReport is the machine-readable report of a sync committed to the
+// repository
+type syncReport struct {
+	GitTrack          string                                `json:"gitTrack"`
+	Cluster           string                                `json:"cluster,omitempty"`
+	Repository        string                                `json:"repository"`
+	Reference         string                                `json:"reference"`
+	Commit            string                                `json:"commit,omitempty"`
+	SyncTime          metav1.Time                           `json:"syncTime"`
+	Result            syncResult                            `json:"result"`
+	Message           string                                `json:"message,omitempty"`
+	ObjectsDiscovered int64                                 `json:"objectsDiscovered"`
+	ObjectsApplied    int64                                 `json:"objectsApplied"`
+	ObjectsInSync     int64                                 `json:"objectsInSync"`
+	ObjectsIgnored    int64                                 `json:"objectsIgnored"`
+	ObjectsPruned     int64                                 `json:"objectsPruned"`
+	Objects           []farosv1alpha1.GitTrackRevisionChild `json:"objects,omitempty"`
+	Failures          []farosv1alpha1.GitTrackRevisionChild `json:"failures,omitempty"`
+	IgnoredFiles      map[string]string                     `json:"ignoredFiles,omitempty"`
+	Clusters          []farosv1alpha1.GitTrackClusterStatus `json:"clusters,omitempty"`
+}
+
+// newSyncReport returns the report of the sync of the GitTrack
+func newSyncReport(gt *farosv1alpha1.GitTrack, sOpts *statusOpts, rOpts *revisionOpts, syncTime time.Time) *syncReport {
+	report := &syncReport{
+		GitTrack:          fmt.Sprintf("%s/%s", gt.GetNamespace(), gt.GetName()),
+		Cluster:           farosflags.ClusterName,
+		Repository:        gt.Spec.Repository,
+		Reference:         syncReference(gt, sOpts.rollback),
+		SyncTime:          metav1.NewTime(syncTime),
+		ObjectsDiscovered: sOpts.discovered,
+		ObjectsApplied:    sOpts.applied,
+		ObjectsInSync:     sOpts.inSync,
+		ObjectsIgnored:    sOpts.ignored,
+		ObjectsPruned:     sOpts.pruned,
+		IgnoredFiles:      sOpts.ignoredFiles,
+		Clusters:          sOpts.clusters,
+	}
+	// The commit synced is only known when the reference is a commit
+	if commitSHA.MatchString(report.Reference) {
+		report.Commit = report.Reference
+	}
+
+	if sOpts.changesPending() {
+		report.Result = syncResultPending
+		report.Message = fmt.Sprintf("Waiting to sync: %v", sOpts.upToDateError)
+	} else if stage, err := failedStage(sOpts); err != nil {
+		report.Result = syncResultFailed
+		report.Message = fmt.Sprintf("Failed to %s: %v", stage, err)
+	} else if !sOpts.synced() {
+		report.Result = syncResultFailed
+		report.Message = "Failed to sync"
+	} else {
+		report.Result = syncResultSucceeded
+	}
+
+	report.Objects = append([]farosv1alpha1.GitTrackRevisionChild{}, rOpts.children...)
+	sort.Slice(report.Objects, func(i, j int) bool {
+		return report.Objects[i].Name < report.Objects[j].Name
+	})
+	for _, child := range report.Objects {
+		if child.Result == farosv1alpha1.ChildApplyResultFailed {
+			report.Failures = append(report.Failures, child)
+		}
+	}
+	return report
+}
+
+// syncReportFile returns the path of the file the reports of the GitTrack are
+// written to. Reports are kept per cluster so that GitTracks in different
+// clusters can report to the same branch.
+func syncReportFile(gt *farosv1alpha1.GitTrack) string {
+	dir := strings.Trim(gt.Spec.SyncReport.Path, "/")
+	if dir == "" {
+		dir = defaultSyncReportPath
+	}
+	if farosflags.ClusterName != "" {
+		dir = path.Join(dir, farosflags.ClusterName)
+	}
+	return path.Join(dir, gt.GetNamespace(), gt.GetName()+".json")
+}
+
+// writeSyncReport returns an EditFunc writing the report to the file
+func writeSyncReport(filename string, report *syncReport) gitwrite.EditFunc {
+	return func(fs billy.Filesystem) ([]string, error) {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal sync report: %v", err)
+		}
+		if err = gitwrite.WriteFile(fs, filename, append(data, '\n')); err != nil {
+			return nil, fmt.Errorf("failed to write '%s': %v", filename, err)
+		}
+		return []string{filename}, nil
+	}
+}
+
+// commitSyncReport commits a report of the sync to the repository if the
+// GitTrack asks for it. Reports are only committed when the sync changed
+// something or the result differs from the last report committed, so that
+// the history of the file records each deployment.
+func (r *ReconcileGitTrack) commitSyncReport(gt *farosv1alpha1.GitTrack, sOpts *statusOpts, rOpts *revisionOpts) error {
+	// The reference synced is unknown if the repository could not be fetched
+	if gt.Spec.SyncReport == nil || !sOpts.fetched() {
+		return nil
+	}
+
+	report := newSyncReport(gt, sOpts, rOpts, time.Now())
+	key := fmt.Sprintf("%s/%s", gt.GetNamespace(), gt.GetName())
+	reported := strings.Join([]string{report.Reference, string(report.Result), report.Message}, "/")
+	r.mutex.RLock()
+	last := r.syncReports[key]
+	r.mutex.RUnlock()
+	if last == reported && !rOpts.changed(gt) {
+		return nil
+	}
+
+	filename := syncReportFile(gt)
+	deployKey := gittrackutils.DeployKey(gt, path.Dir(filename), gt.Spec.SyncReport.Branch)
+	gitCreds, err := r.fetchGitCredentials(gt.GetNamespace(), deployKey)
+	if err != nil {
+		return fmt.Errorf("unable to commit sync report: %v", err)
+	}
+	opts := gitwrite.Options{
+		URL:     gt.Spec.Repository,
+		Branch:  gt.Spec.SyncReport.Branch,
+		Message: fmt.Sprintf("Report sync of %s at '%s': %s", key, report.Reference, report.Result),
+		Author:  gitwrite.Author{Name: farosflags.GitAuthorName, Email: farosflags.GitAuthorEmail},
+	}
+	if gitCreds != nil {
+		opts.Credentials = gitCreds.secret
+		opts.CredentialType = gitCreds.credentialType
+	}
+	r.gitHostLimiter.accept(gt.Spec.Repository)
+	hash, err := r.commitFiles(opts, writeSyncReport(filename, report))
+	if err != nil {
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "SyncReportFailed", "Failed to commit sync report for '%s': %v", report.Reference, err)
+		return fmt.Errorf("unable to commit sync report: %v", err)
+	}
+
+	r.mutex.Lock()
+	r.syncReports[key] = reported
+	r.mutex.Unlock()
+	r.log.V(1).Info("Sync report committed", "result", report.Result, "commit", hash)
+	return nil
+}
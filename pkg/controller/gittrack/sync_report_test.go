@@ -0,0 +1,92 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Sync Report Suite", func() {
+	Context("newSyncReport", func() {
+		var gt *farosv1alpha1.GitTrack
+		var sOpts *statusOpts
+		var rOpts *revisionOpts
+
+		BeforeEach(func() {
+			gt = &farosv1alpha1.GitTrack{
+				ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+				Spec: farosv1alpha1.GitTrackSpec{
+					Repository: "https://github.com/pusher/faros",
+					Reference:  "a14443638218c782b84cae56a14f1090ee9e5c9c",
+					SyncReport: &farosv1alpha1.GitTrackSyncReport{Branch: "faros-reports"},
+				},
+			}
+			sOpts = newStatusOpts()
+			sOpts.gitReason = gittrackutils.GitFetchSuccess
+			sOpts.upToDateReason = gittrackutils.ChildrenUpdateSuccess
+			sOpts.gcReason = gittrackutils.GCSuccess
+			sOpts.discovered = 2
+			sOpts.inSync = 2
+			rOpts = &revisionOpts{children: []farosv1alpha1.GitTrackRevisionChild{
+				{Name: "deployment-nginx", Kind: "Deployment", Result: farosv1alpha1.ChildApplyResultUpdated},
+				{Name: "configmap-nginx", Kind: "ConfigMap", Result: farosv1alpha1.ChildApplyResultUnchanged},
+			}}
+		})
+
+		It("reports success when all children were synced", func() {
+			report := newSyncReport(gt, sOpts, rOpts, time.Now())
+			Expect(report.GitTrack).To(Equal("default/example"))
+			Expect(report.Result).To(Equal(syncResultSucceeded))
+			Expect(report.Commit).To(Equal("a14443638218c782b84cae56a14f1090ee9e5c9c"))
+			Expect(report.Failures).To(BeEmpty())
+		})
+
+		It("lists the objects by name", func() {
+			report := newSyncReport(gt, sOpts, rOpts, time.Now())
+			Expect(report.Objects).To(HaveLen(2))
+			Expect(report.Objects[0].Name).To(Equal("configmap-nginx"))
+			Expect(report.Objects[1].Name).To(Equal("deployment-nginx"))
+		})
+
+		It("reports failure with the objects that failed", func() {
+			sOpts.upToDateError = errors.New("unable to update child")
+			sOpts.upToDateReason = gittrackutils.ErrorUpdatingChildren
+			rOpts.children[0].Result = farosv1alpha1.ChildApplyResultFailed
+			rOpts.children[0].Message = "unable to update child"
+			report := newSyncReport(gt, sOpts, rOpts, time.Now())
+			Expect(report.Result).To(Equal(syncResultFailed))
+			Expect(report.Message).To(Equal("Failed to apply: unable to update child"))
+			Expect(report.Failures).To(ConsistOf(rOpts.children[0]))
+		})
+
+		It("writes the report beneath the cluster's directory", func() {
+			Expect(syncReportFile(gt)).To(Equal(".faros/reports/default/example.json"))
+			farosflags.ClusterName = "production"
+			defer func() { farosflags.ClusterName = "" }()
+			gt.Spec.SyncReport.Path = "/reports/"
+			Expect(syncReportFile(gt)).To(Equal("reports/production/default/example.json"))
+		})
+	})
+})
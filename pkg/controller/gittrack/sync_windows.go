@@ -0,0 +1,71 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	"github.com/pusher/faros/pkg/syncwindows"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// holdForSyncWindows checks the sync windows of the GitTrack and, while they
+// are closed, reports the changes that a sync would make in the status
+// instead of making them. It returns whether the changes were held back.
+func (r *ReconcileGitTrack) holdForSyncWindows(gt *farosv1alpha1.GitTrack, objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, existing map[string]farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) (bool, error) {
+	if len(gt.Spec.SyncWindows) == 0 {
+		return false, nil
+	}
+
+	open, message, err := syncwindows.Open(gt.Spec.SyncWindows, time.Now())
+	switch {
+	case err != nil:
+		// Nothing is applied until the windows are fixed
+		opts.windowError = err
+		opts.windowReason = gittrackutils.InvalidSyncWindow
+		message = "sync windows are invalid"
+	case !open:
+		opts.windowError = fmt.Errorf("%s", message)
+		opts.windowReason = gittrackutils.OutsideSyncWindow
+	default:
+		opts.windowReason = gittrackutils.InsideSyncWindow
+		return false, nil
+	}
+
+	// Plan into a copy so that nothing is counted twice if the sync goes ahead
+	held := opts.copy()
+	changes, err := r.planChanges(gt, objects, objectFiles, existing, held)
+	if err != nil {
+		return false, fmt.Errorf("failed to plan changes: %v", err)
+	}
+	// Nothing would change, so syncing is harmless and keeps the status fresh
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	*opts = *held
+	opts.upToDateError = fmt.Errorf("%d changes pending: %s", len(changes), message)
+	opts.upToDateReason = gittrackutils.ChangesPending
+	opts.gcReason = gittrackutils.GCSkippedSyncWindow
+	opts.childrenOutOfSync = changeStatuses(changes)
+	r.recorder.Eventf(gt, apiv1.EventTypeNormal, "ChangesPending", "Holding back %d changes: %s", len(changes), message)
+	return true, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/pkg/render"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// handleObjects creates or updates the GitTrackObjects for the objects using a
+// bounded pool of workers, the results are sent to the returned channel which
+// has room for a result for every object
+func (r *ReconcileGitTrack) handleObjects(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, owner *farosv1alpha1.GitTrack) <-chan result {
+	workers := r.upsertWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(objects) {
+		workers = len(objects)
+	}
+
+	objectsChan := make(chan *unstructured.Unstructured, len(objects))
+	for _, obj := range objects {
+		objectsChan <- obj
+	}
+	close(objectsChan)
+
+	resultsChan := make(chan result, len(objects))
+	for i := 0; i < workers; i++ {
+		go func() {
+			for obj := range objectsChan {
+				res := r.handleObject(obj, owner)
+				res.Kind = obj.GetKind()
+				res.File = objectFiles[obj]
+				res.Cluster = obj.GetAnnotations()[render.ClusterAnnotation]
+				resultsChan <- res
+			}
+		}()
+	}
+	return resultsChan
+}
+
+// fileErrorsMessage aggregates the errors for the objects of each file into a
+// single error, sorted by file so that the condition message is stable
+func fileErrorsMessage(fileErrors map[string][]string) error {
+	files := []string{}
+	for file := range fileErrors {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	msgs := []string{}
+	for _, file := range files {
+		errs := fileErrors[file]
+		sort.Strings(errs)
+		msgs = append(msgs, fmt.Sprintf("%s: %s", file, strings.Join(errs, "; ")))
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, ",\n"))
+}
@@ -42,6 +42,18 @@ const (
 	// parsing files from the repository
 	FileParseSuccess ConditionReason = "FileParseSuccess"
 
+	// StrictSyncBlocked represents the condition reason when files that cannot
+	// be synced block a GitTrack in the Strict sync mode
+	StrictSyncBlocked ConditionReason = "StrictSyncBlocked"
+
+	// NamespaceNotAllowed represents the condition reason when manifests in the
+	// repository target namespaces the GitTrack is not allowed to manage
+	NamespaceNotAllowed ConditionReason = "NamespaceNotAllowed"
+
+	// KindNotAllowed represents the condition reason when the repository
+	// contains objects of kinds outside of the GitTrack's allowed kinds
+	KindNotAllowed ConditionReason = "KindNotAllowed"
+
 	// ErrorUpdatingChildren represents the condition reason when an error occurs
 	// updating the child objects
 	ErrorUpdatingChildren ConditionReason = "ErrorUpdatingChildren"
@@ -57,6 +69,113 @@ const (
 	// GCSuccess represents the condition reason when no error occurs
 	// removing orphaned children
 	GCSuccess ConditionReason = "GCSuccess"
+
+	// GCSkippedDryRun represents the condition reason when leftover children
+	// are not deleted or retained because the controller is in dry-run mode
+	GCSkippedDryRun ConditionReason = "GCSkippedDryRun"
+
+	// ChildrenRetained represents the condition reason when orphaned children
+	// are left in place due to the GitTrack's prune policy
+	ChildrenRetained ConditionReason = "ChildrenRetained"
+
+	// ErrorRetainingChildren represents the condition reason when an error
+	// occurs labelling retained children
+	ErrorRetainingChildren ConditionReason = "ErrorRetainingChildren"
+
+	// ChildrenHealthy represents the condition reason when all children have
+	// reached their desired state
+	ChildrenHealthy ConditionReason = "ChildrenHealthy"
+
+	// ChildrenUnhealthy represents the condition reason when some children
+	// have not reached their desired state
+	ChildrenUnhealthy ConditionReason = "ChildrenUnhealthy"
+
+	// SuspendedBySpec represents the condition reason when syncing has been
+	// suspended by spec.suspend
+	SuspendedBySpec ConditionReason = "SuspendedBySpec"
+
+	// DestructiveChangesPending represents the condition reason when orphaned
+	// children are not removed as the deletion requires approval
+	DestructiveChangesPending ConditionReason = "DestructiveChangesPending"
+
+	// InsideSyncWindow represents the condition reason when the sync windows
+	// allow changes to be applied
+	InsideSyncWindow ConditionReason = "InsideSyncWindow"
+
+	// OutsideSyncWindow represents the condition reason when the sync windows
+	// do not allow changes to be applied
+	OutsideSyncWindow ConditionReason = "OutsideSyncWindow"
+
+	// InvalidSyncWindow represents the condition reason when a sync window
+	// cannot be parsed
+	InvalidSyncWindow ConditionReason = "InvalidSyncWindow"
+
+	// ChangesPending represents the condition reason when changes to children
+	// are held back until the sync windows allow them to be applied
+	ChangesPending ConditionReason = "ChangesPending"
+
+	// GCSkippedSyncWindow represents the condition reason when leftover
+	// children are not deleted or retained because the sync windows do not
+	// allow changes to be applied
+	GCSkippedSyncWindow ConditionReason = "GCSkippedSyncWindow"
+
+	// ApprovalPending represents the condition reason when changes to
+	// children are held back until their plan is approved
+	ApprovalPending ConditionReason = "ApprovalPending"
+
+	// GCSkippedApproval represents the condition reason when leftover children
+	// are not deleted or retained because the plan has not been approved
+	GCSkippedApproval ConditionReason = "GCSkippedApproval"
+
+	// CanaryInProgress represents the condition reason when changes to
+	// children are held back until the canary children are healthy
+	CanaryInProgress ConditionReason = "CanaryInProgress"
+
+	// CanaryFailed represents the condition reason when changes to children
+	// are aborted because the canary children failed
+	CanaryFailed ConditionReason = "CanaryFailed"
+
+	// GCSkippedCanary represents the condition reason when leftover children
+	// are not deleted or retained until the canary children are healthy
+	GCSkippedCanary ConditionReason = "GCSkippedCanary"
+
+	// HookInProgress represents the condition reason when changes to
+	// children are held back until the hooks of the GitTrack have completed
+	HookInProgress ConditionReason = "HookInProgress"
+
+	// HookFailed represents the condition reason when a hook of the GitTrack
+	// failed
+	HookFailed ConditionReason = "HookFailed"
+
+	// GCSkippedHooks represents the condition reason when leftover children
+	// are not deleted or retained until the hooks of the GitTrack have
+	// completed
+	GCSkippedHooks ConditionReason = "GCSkippedHooks"
+
+	// InvalidRollback represents the condition reason when the revision named
+	// by spec.rollbackTo cannot be rolled back to
+	InvalidRollback ConditionReason = "InvalidRollback"
+
+	// ErrorSelectingClusters represents the condition reason when the
+	// FarosClusters selected by spec.clusterSelector cannot be listed
+	ErrorSelectingClusters ConditionReason = "ErrorSelectingClusters"
+
+	// PolicyCheckPassed represents the condition reason when all children
+	// were accepted by the admission policies of the cluster
+	PolicyCheckPassed ConditionReason = "PolicyCheckPassed"
+
+	// PolicyViolated represents the condition reason when children were
+	// rejected by the admission policies of the cluster
+	PolicyViolated ConditionReason = "PolicyViolated"
+
+	// PolicyCheckSkipped represents the condition reason when the children
+	// could not be checked against the admission policies of the cluster
+	PolicyCheckSkipped ConditionReason = "PolicyCheckSkipped"
+
+	// VerificationFailed represents the condition reason when a manifest
+	// synced is not signed by one of the keys or identities trusted by
+	// spec.verification
+	VerificationFailed ConditionReason = "VerificationFailed"
 )
 
 // ConditionReason represents a valid condition reason
@@ -85,19 +204,29 @@ func GetGitTrackCondition(status farosv1alpha1.GitTrackStatus, condType farosv1a
 	return nil
 }
 
-// SetGitTrackCondition updates the GitTrack to include the provided condition. If the condition that
-// we are about to add already exists and has the same status and reason then we are not going to update.
+// SetGitTrackCondition updates the GitTrack to include the provided condition, following the same
+// semantics as meta.SetStatusCondition. An existing condition is updated in place, keeping its
+// lastTransitionTime unless the status changes. If the condition is unchanged then we are not going to update.
 func SetGitTrackCondition(status *farosv1alpha1.GitTrackStatus, condition farosv1alpha1.GitTrackCondition) {
-	currentCond := GetGitTrackCondition(*status, condition.Type)
-	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason {
+	for i := range status.Conditions {
+		currentCond := &status.Conditions[i]
+		if currentCond.Type != condition.Type {
+			continue
+		}
+		if currentCond.Status == condition.Status &&
+			currentCond.Reason == condition.Reason &&
+			currentCond.Message == condition.Message &&
+			currentCond.ObservedGeneration == condition.ObservedGeneration {
+			return
+		}
+		// Do not update lastTransitionTime if the status of the condition doesn't change.
+		if currentCond.Status == condition.Status {
+			condition.LastTransitionTime = currentCond.LastTransitionTime
+		}
+		*currentCond = condition
 		return
 	}
-	// Do not update lastTransitionTime if the status of the condition doesn't change.
-	if currentCond != nil && currentCond.Status == condition.Status {
-		condition.LastTransitionTime = currentCond.LastTransitionTime
-	}
-	newConditions := filterOutCondition(status.Conditions, condition.Type)
-	status.Conditions = append(newConditions, condition)
+	status.Conditions = append(status.Conditions, condition)
 }
 
 // RemoveGitTrackCondition removes the GitTrack condition with the provided type.
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"path"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+// DeployKey returns the deploy key used to read or write the subPath of the
+// GitTrack's repository at the reference: the first of spec.deployKeys scoped
+// to them, or spec.deployKey if none are
+func DeployKey(gt *farosv1alpha1.GitTrack, subPath, reference string) farosv1alpha1.GitTrackDeployKey {
+	for _, key := range gt.Spec.DeployKeys {
+		if key.Reference != "" && key.Reference != reference {
+			continue
+		}
+		if !withinPath(subPath, key.SubPath) {
+			continue
+		}
+		return key.DeployKey
+	}
+	return gt.Spec.DeployKey
+}
+
+// withinPath returns whether p is the directory dir or beneath it, an empty
+// dir contains every path
+func withinPath(p, dir string) bool {
+	dir = cleanPath(dir)
+	if dir == "" {
+		return true
+	}
+	p = cleanPath(p)
+	return p == dir || strings.HasPrefix(p, dir+"/")
+}
+
+// cleanPath returns the path relative to the root of the repository
+func cleanPath(p string) string {
+	return strings.Trim(path.Clean("/"+p), "/")
+}
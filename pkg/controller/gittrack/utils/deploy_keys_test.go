@@ -0,0 +1,62 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+var _ = Describe("Deploy Keys Suite", func() {
+	Context("DeployKey", func() {
+		var gt *farosv1alpha1.GitTrack
+		var defaultKey, paymentsKey, reportsKey farosv1alpha1.GitTrackDeployKey
+
+		BeforeEach(func() {
+			defaultKey = farosv1alpha1.GitTrackDeployKey{SecretName: "deploy-key", Key: "id_rsa"}
+			paymentsKey = farosv1alpha1.GitTrackDeployKey{SecretName: "payments-deploy-key", Key: "id_rsa"}
+			reportsKey = farosv1alpha1.GitTrackDeployKey{SecretName: "reports-deploy-key", Key: "id_rsa"}
+			gt = &farosv1alpha1.GitTrack{
+				Spec: farosv1alpha1.GitTrackSpec{
+					DeployKey: defaultKey,
+					DeployKeys: []farosv1alpha1.GitTrackScopedDeployKey{
+						{SubPath: "/teams/payments/", DeployKey: paymentsKey},
+						{Reference: "faros-reports", DeployKey: reportsKey},
+					},
+				},
+			}
+		})
+
+		It("uses the key scoped to the subPath and the paths beneath it", func() {
+			Expect(DeployKey(gt, "teams/payments", "master")).To(Equal(paymentsKey))
+			Expect(DeployKey(gt, "teams/payments/production", "master")).To(Equal(paymentsKey))
+		})
+
+		It("does not match other paths sharing a prefix", func() {
+			Expect(DeployKey(gt, "teams/payments-legacy", "master")).To(Equal(defaultKey))
+		})
+
+		It("uses the key scoped to the reference", func() {
+			Expect(DeployKey(gt, ".faros/reports", "faros-reports")).To(Equal(reportsKey))
+		})
+
+		It("falls back to the deploy key", func() {
+			Expect(DeployKey(gt, "teams/search", "master")).To(Equal(defaultKey))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+const (
+	// OrphanedLabel is added to GitTrackObjects that have been retained by the
+	// prune policy after their manifest was removed from the repository
+	OrphanedLabel = "faros.pusher.com/orphaned"
+
+	// OrphanedAtAnnotation records the time, in RFC3339 format, at which a
+	// GitTrackObject was labelled as orphaned
+	OrphanedAtAnnotation = "faros.pusher.com/orphaned-at"
+
+	// GitTrackLabel is added to GitTrackRevisions and (Cluster)GitTrackObjects
+	// and holds the name of the GitTrack they were recorded for or belong to
+	GitTrackLabel = "faros.pusher.com/gittrack"
+
+	// ClusterLabel is added to (Cluster)GitTrackObjects applied to a
+	// FarosCluster and holds the name of the FarosCluster
+	ClusterLabel = "faros.pusher.com/cluster"
+
+	// SyncNowAnnotation is set on a GitTrack to fetch its repository and sync
+	// it immediately rather than waiting for the next sync period. Each new
+	// value triggers a single sync.
+	SyncNowAnnotation = "faros.pusher.com/sync-now"
+)
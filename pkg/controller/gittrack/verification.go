@@ -0,0 +1,139 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	"github.com/pusher/faros/pkg/cosign"
+	"github.com/pusher/faros/pkg/render"
+	gitstore "github.com/pusher/git-store"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// verifyFiles checks that each manifest checked out at the reference has a
+// cosign signature, stored beside it, made by one of the keys or identities
+// trusted by spec.verification. Nothing is checked if verification is not
+// configured.
+func (r *ReconcileGitTrack) verifyFiles(gt *farosv1alpha1.GitTrack, ref string, files map[string]*gitstore.File) error {
+	if gt.Spec.Verification == nil {
+		return nil
+	}
+
+	verifier, err := r.verifier(gt)
+	if err != nil {
+		return err
+	}
+	deployKey := gittrackutils.DeployKey(gt, gt.Spec.SubPath, gt.Spec.Reference)
+	gitCreds, err := r.fetchGitCredentials(gt.Namespace, deployKey)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve git credentials from secret: %v", err)
+	}
+	repo, err := r.checkoutRepo(gt.Spec.Repository, ref, gitCreds, false)
+	if err != nil {
+		return err
+	}
+	signatures, err := repo.GetAllFiles(render.ManifestGlob(gt.Spec.SubPath)+".{sig,pem}", true)
+	if err != nil {
+		return fmt.Errorf("failed to get signatures for subpath '%s': %v", gt.Spec.SubPath, err)
+	}
+
+	if err = verifyManifests(verifier, fileContents(files), fileContents(signatures)); err != nil {
+		return err
+	}
+	r.log.V(1).Info("Verified manifests", "reference", ref, "file count", len(files))
+	return nil
+}
+
+// verifier creates a verifier trusting the keys and identities of
+// spec.verification
+func (r *ReconcileGitTrack) verifier(gt *farosv1alpha1.GitTrack) (*cosign.Verifier, error) {
+	verification := gt.Spec.Verification
+	opts := cosign.Options{}
+	var err error
+	if verification.PublicKeys != nil {
+		if opts.PublicKeys, err = r.readSecretReference(gt.GetNamespace(), *verification.PublicKeys); err != nil {
+			return nil, err
+		}
+	}
+	if verification.Roots != nil {
+		if opts.Roots, err = r.readSecretReference(gt.GetNamespace(), *verification.Roots); err != nil {
+			return nil, err
+		}
+	}
+	for _, identity := range verification.Identities {
+		opts.Identities = append(opts.Identities, cosign.Identity{Issuer: identity.Issuer, Subject: identity.Subject})
+	}
+
+	verifier, err := cosign.NewVerifier(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification: %v", err)
+	}
+	return verifier, nil
+}
+
+// readSecretReference reads the key of the secret referenced
+func (r *ReconcileGitTrack) readSecretReference(namespace string, ref farosv1alpha1.GitTrackSecretReference) ([]byte, error) {
+	secret := &apiv1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ref.SecretName}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up secret %s: %v", ref.SecretName, err)
+	}
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s does not have key %s", ref.SecretName, ref.Key)
+	}
+	return data, nil
+}
+
+// verifyManifests checks the signature of each manifest, found at the path
+// of the manifest with the cosign signature suffix, along with the
+// certificate of keyless signatures, so that only signed content is synced
+func verifyManifests(verifier *cosign.Verifier, manifests, signatures map[string][]byte) error {
+	var failures []string
+	for path, manifest := range manifests {
+		signature, ok := signatures[path+cosign.SignatureSuffix]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: not signed", path))
+			continue
+		}
+		if err := verifier.Verify(manifest, signature, signatures[path+cosign.CertificateSuffix]); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", path, err))
+		}
+	}
+	if len(failures) > 0 {
+		// Sort the failures so that the condition message is stable
+		sort.Strings(failures)
+		return fmt.Errorf("manifests failed verification: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// fileContents returns the contents of each file by path
+func fileContents(files map[string]*gitstore.File) map[string][]byte {
+	contents := make(map[string][]byte, len(files))
+	for path, file := range files {
+		contents[path] = []byte(file.Contents())
+	}
+	return contents
+}
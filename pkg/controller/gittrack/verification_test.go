@@ -0,0 +1,111 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrack
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/pkg/cosign"
+)
+
+// newSigningKey creates a cosign key pair, returning the PEM encoded public
+// key
+func newSigningKey() (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	Expect(err).NotTo(HaveOccurred())
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// signManifest signs the manifest as `cosign sign-blob` does
+func signManifest(key *ecdsa.PrivateKey, manifest []byte) []byte {
+	digest := sha256.Sum256(manifest)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	Expect(err).NotTo(HaveOccurred())
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	Expect(err).NotTo(HaveOccurred())
+	return []byte(base64.StdEncoding.EncodeToString(sig))
+}
+
+var _ = Describe("Verification Suite", func() {
+	Context("verifyManifests", func() {
+		var key *ecdsa.PrivateKey
+		var verifier *cosign.Verifier
+		var manifests, signatures map[string][]byte
+
+		BeforeEach(func() {
+			var publicKey []byte
+			key, publicKey = newSigningKey()
+			var err error
+			verifier, err = cosign.NewVerifier(cosign.Options{PublicKeys: publicKey})
+			Expect(err).NotTo(HaveOccurred())
+
+			manifests = map[string][]byte{
+				"foo/deployment.yaml": []byte("kind: Deployment"),
+				"foo/service.yaml":    []byte("kind: Service"),
+			}
+			signatures = map[string][]byte{}
+			for path, manifest := range manifests {
+				signatures[path+".sig"] = signManifest(key, manifest)
+			}
+		})
+
+		It("verifies manifests signed by a trusted key", func() {
+			Expect(verifyManifests(verifier, manifests, signatures)).To(Succeed())
+		})
+
+		It("refuses manifests without signatures", func() {
+			delete(signatures, "foo/service.yaml.sig")
+			err := verifyManifests(verifier, manifests, signatures)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("manifests failed verification: foo/service.yaml: not signed"))
+		})
+
+		It("refuses manifests changed since they were signed", func() {
+			manifests["foo/deployment.yaml"] = []byte("kind: DaemonSet")
+			err := verifyManifests(verifier, manifests, signatures)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("foo/deployment.yaml: signature does not match any trusted key"))
+		})
+
+		It("refuses manifests signed by other keys", func() {
+			other, _ := newSigningKey()
+			signatures["foo/service.yaml.sig"] = signManifest(other, manifests["foo/service.yaml"])
+			err := verifyManifests(verifier, manifests, signatures)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("foo/service.yaml"))
+		})
+
+		It("lists every failure in order", func() {
+			signatures = map[string][]byte{}
+			err := verifyManifests(verifier, manifests, signatures)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("manifests failed verification: foo/deployment.yaml: not signed; foo/service.yaml: not signed"))
+		})
+	})
+})
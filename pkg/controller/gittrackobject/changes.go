@@ -0,0 +1,115 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	farosclient "github.com/pusher/faros/pkg/utils/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// maxUpdatePaths is the maximum number of changed field paths recorded in
+	// the status of a GitTrackObject
+	maxUpdatePaths = 20
+
+	// maxEventPaths is the maximum number of changed field paths included in
+	// an update event
+	maxEventPaths = 5
+
+	// maxDryRunDiffSize is the maximum size in bytes of the diff recorded in
+	// the status of a GitTrackObject in dry-run
+	maxDryRunDiffSize = 4096
+)
+
+// changedPaths returns the paths of the fields that will be changed by
+// updating found to the desired state of child, or nil if they cannot be
+// determined
+func (r *ReconcileGitTrackObject) changedPaths(found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) []string {
+	diffOpts := *opts
+	result, err := r.applier.Diff(context.TODO(), &diffOpts, child, found)
+	if err != nil {
+		r.log.Error(err, "unable to compute changed fields")
+		return nil
+	}
+	return result.Paths
+}
+
+// newChildUpdate records an update to the child that changed the given paths
+func newChildUpdate(paths []string) *farosv1alpha1.ChildUpdate {
+	if len(paths) > maxUpdatePaths {
+		paths = paths[:maxUpdatePaths]
+	}
+	return &farosv1alpha1.ChildUpdate{
+		Time:  metav1.Now(),
+		Paths: paths,
+	}
+}
+
+// updateMessage returns the message for an UpdateSuccessful event, listing at
+// most maxEventPaths of the fields that were changed
+func updateMessage(child *unstructured.Unstructured, paths []string) string {
+	message := fmt.Sprintf("Successfully updated child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+	if len(paths) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s, changed %s", message, summarizePaths(paths, maxEventPaths))
+}
+
+// summarizePaths joins at most max paths, noting how many were left out
+func summarizePaths(paths []string, max int) string {
+	if len(paths) <= max {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:max], ", "), len(paths)-max)
+}
+
+// renderDryRunDiff describes the changes one field per line with its current
+// and desired values, leaving out the fields that would exceed
+// maxDryRunDiffSize
+func renderDryRunDiff(changes []farosclient.FieldChange) string {
+	lines := []string{}
+	size := 0
+	for i, change := range changes {
+		line := fmt.Sprintf("%s: %s -> %s", change.Path, renderValue(change.Current), renderValue(change.Desired))
+		if size+len(line)+1 > maxDryRunDiffSize {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(changes)-i))
+			break
+		}
+		lines = append(lines, line)
+		size += len(line) + 1
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderValue renders the value of a field as JSON, or <unset> if it is nil
+func renderValue(value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	farosclient "github.com/pusher/faros/pkg/utils/client"
+	testutils "github.com/pusher/faros/test/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Changes Suite", func() {
+	Context("summarizePaths", func() {
+		It("lists all paths when within the limit", func() {
+			Expect(summarizePaths([]string{"spec.replicas", "spec.paused"}, 2)).To(Equal("spec.replicas, spec.paused"))
+		})
+
+		It("notes how many paths were left out", func() {
+			paths := []string{"metadata.labels.app", "spec.paused", "spec.replicas"}
+			Expect(summarizePaths(paths, 1)).To(Equal("metadata.labels.app and 2 more"))
+		})
+	})
+
+	Context("updateMessage", func() {
+		var child *unstructured.Unstructured
+
+		BeforeEach(func() {
+			child = &unstructured.Unstructured{}
+			child.SetKind("Deployment")
+			child.SetNamespace("default")
+			child.SetName("example")
+		})
+
+		It("includes the changed paths", func() {
+			Expect(updateMessage(child, []string{"spec.replicas"})).To(Equal("Successfully updated child Deployment default/example, changed spec.replicas"))
+		})
+
+		It("omits the changed paths when unknown", func() {
+			Expect(updateMessage(child, nil)).To(Equal("Successfully updated child Deployment default/example"))
+		})
+	})
+
+	Context("newChildUpdate", func() {
+		It("records at most maxUpdatePaths paths", func() {
+			paths := []string{}
+			for i := 0; i < maxUpdatePaths+5; i++ {
+				paths = append(paths, fmt.Sprintf("metadata.labels.label-%d", i))
+			}
+			update := newChildUpdate(paths)
+			Expect(update.Paths).To(Equal(paths[:maxUpdatePaths]))
+			Expect(update.Time.IsZero()).To(BeFalse())
+		})
+	})
+
+	Context("renderDryRunDiff", func() {
+		It("lists the current and desired value of each field", func() {
+			changes := []farosclient.FieldChange{
+				{Path: "metadata.labels.app", Current: "nginx", Desired: nil},
+				{Path: "spec.replicas", Current: int64(1), Desired: int64(3)},
+				{Path: "spec.paused", Current: nil, Desired: true},
+			}
+			Expect(renderDryRunDiff(changes)).To(Equal("metadata.labels.app: \"nginx\" -> <unset>\nspec.replicas: 1 -> 3\nspec.paused: <unset> -> true"))
+		})
+
+		It("leaves out the fields exceeding maxDryRunDiffSize", func() {
+			changes := []farosclient.FieldChange{}
+			for i := 0; i < maxDryRunDiffSize; i++ {
+				changes = append(changes, farosclient.FieldChange{Path: fmt.Sprintf("metadata.labels.label-%d", i), Desired: "value"})
+			}
+			diff := renderDryRunDiff(changes)
+			Expect(len(diff)).To(BeNumerically("<=", maxDryRunDiffSize+len("... and 0000 more")))
+			Expect(diff).To(HavePrefix("metadata.labels.label-0: <unset> -> \"value\"\n"))
+			Expect(diff).To(MatchRegexp(`\.\.\. and \d+ more$`))
+		})
+	})
+
+	Context("updateGitTrackObjectStatus", func() {
+		var gto *farosv1alpha1.GitTrackObject
+
+		BeforeEach(func() {
+			gto = testutils.ExampleGitTrackObject.DeepCopy()
+		})
+
+		It("records the last update", func() {
+			update := newChildUpdate([]string{"spec.replicas"})
+			Expect(updateGitTrackObjectStatus(gto, &statusOpts{lastUpdate: update})).To(BeTrue())
+			Expect(gto.Status.LastUpdate).To(Equal(update))
+		})
+
+		It("keeps the previous update when the child was not updated", func() {
+			update := newChildUpdate([]string{"spec.replicas"})
+			gto.Status.LastUpdate = update
+			updateGitTrackObjectStatus(gto, &statusOpts{})
+			Expect(gto.Status.LastUpdate).To(Equal(update))
+		})
+	})
+})
@@ -0,0 +1,106 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// conflictTracker records the drift corrections made to each child so that
+// faros stops fighting another controller that keeps changing the child back
+type conflictTracker struct {
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mutex       sync.Mutex
+	corrections map[string][]time.Time
+	backoff     map[string]time.Time
+}
+
+// newConflictTracker returns a conflictTracker that backs off from a child for
+// the window once it has been corrected threshold times within the window, a
+// threshold of 0 disables conflict detection
+func newConflictTracker(threshold int, window time.Duration) *conflictTracker {
+	return &conflictTracker{
+		threshold:   threshold,
+		window:      window,
+		now:         time.Now,
+		corrections: make(map[string][]time.Time),
+		backoff:     make(map[string]time.Time),
+	}
+}
+
+// conflictKey identifies the child within the conflictTracker
+func conflictKey(child *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", child.GroupVersionKind().GroupKind().String(), child.GetNamespace(), child.GetName())
+}
+
+// record records a drift correction of the child and returns whether it is
+// in conflict with another controller, in which case updates to the child are
+// held back for the window
+func (c *conflictTracker) record(child *unstructured.Unstructured) bool {
+	if c == nil || c.threshold <= 0 || c.window <= 0 {
+		return false
+	}
+	key := conflictKey(child)
+	now := c.now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	corrections := []time.Time{}
+	for _, t := range c.corrections[key] {
+		if now.Sub(t) < c.window {
+			corrections = append(corrections, t)
+		}
+	}
+	corrections = append(corrections, now)
+	if len(corrections) < c.threshold {
+		c.corrections[key] = corrections
+		return false
+	}
+
+	delete(c.corrections, key)
+	c.backoff[key] = now.Add(c.window)
+	return true
+}
+
+// backingOff returns how long updates to the child are still held back for,
+// or 0 if the child may be updated
+func (c *conflictTracker) backingOff(child *unstructured.Unstructured) time.Duration {
+	if c == nil {
+		return 0
+	}
+	key := conflictKey(child)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	until, ok := c.backoff[key]
+	if !ok {
+		return 0
+	}
+	remaining := until.Sub(c.now())
+	if remaining <= 0 {
+		delete(c.backoff, key)
+		return 0
+	}
+	return remaining
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Conflict Suite", func() {
+	var tracker *conflictTracker
+	var child, other *unstructured.Unstructured
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Now()
+		tracker = newConflictTracker(3, time.Minute)
+		tracker.now = func() time.Time { return now }
+
+		child = &unstructured.Unstructured{}
+		child.SetAPIVersion("apps/v1")
+		child.SetKind("Deployment")
+		child.SetNamespace("default")
+		child.SetName("example")
+		other = child.DeepCopy()
+		other.SetName("other")
+	})
+
+	Context("record", func() {
+		It("reports a conflict once the threshold is reached within the window", func() {
+			Expect(tracker.record(child)).To(BeFalse())
+			Expect(tracker.record(child)).To(BeFalse())
+			Expect(tracker.record(child)).To(BeTrue())
+		})
+
+		It("forgets corrections older than the window", func() {
+			Expect(tracker.record(child)).To(BeFalse())
+			Expect(tracker.record(child)).To(BeFalse())
+			now = now.Add(2 * time.Minute)
+			Expect(tracker.record(child)).To(BeFalse())
+		})
+
+		It("counts corrections of each child separately", func() {
+			Expect(tracker.record(child)).To(BeFalse())
+			Expect(tracker.record(child)).To(BeFalse())
+			Expect(tracker.record(other)).To(BeFalse())
+		})
+
+		It("never reports a conflict when disabled", func() {
+			tracker.threshold = 0
+			for i := 0; i < 5; i++ {
+				Expect(tracker.record(child)).To(BeFalse())
+			}
+		})
+	})
+
+	Context("backingOff", func() {
+		It("does not back off from children without a conflict", func() {
+			Expect(tracker.backingOff(child)).To(BeZero())
+		})
+
+		It("backs off from a child in conflict for the window", func() {
+			for i := 0; i < 3; i++ {
+				tracker.record(child)
+			}
+			Expect(tracker.backingOff(child)).To(Equal(time.Minute))
+			Expect(tracker.backingOff(other)).To(BeZero())
+
+			now = now.Add(30 * time.Second)
+			Expect(tracker.backingOff(child)).To(Equal(30 * time.Second))
+
+			now = now.Add(30 * time.Second)
+			Expect(tracker.backingOff(child)).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// missingKindRequests returns a mapping function that enqueues every
+// (Cluster)GitTrackObject which failed to watch a child of the kind defined by
+// a CustomResourceDefinition, so that children whose CRD is installed after
+// the GitTrackObject was created are picked up without a restart
+func missingKindRequests(c client.Client, log logr.Logger) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		crd, ok := obj.Object.(*apiextensionsv1beta1.CustomResourceDefinition)
+		if !ok {
+			return nil
+		}
+		gk := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+
+		gtos := &farosv1alpha1.GitTrackObjectList{}
+		err := c.List(context.TODO(), gtos)
+		if err != nil {
+			log.Error(err, "unable to list GitTrackObjects after CRD changed", "crd", crd.Name)
+			return nil
+		}
+		cgtos := &farosv1alpha1.ClusterGitTrackObjectList{}
+		err = c.List(context.TODO(), cgtos)
+		if err != nil {
+			log.Error(err, "unable to list ClusterGitTrackObjects after CRD changed", "crd", crd.Name)
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for i := range gtos.Items {
+			if isMissingKind(&gtos.Items[i], gk) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Namespace: gtos.Items[i].Namespace, Name: gtos.Items[i].Name},
+				})
+			}
+		}
+		for i := range cgtos.Items {
+			if isMissingKind(&cgtos.Items[i], gk) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: cgtos.Items[i].Name},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// isMissingKind returns true if the (Cluster)GitTrackObject manages a child of
+// the given group and kind and couldn't start watching it
+func isMissingKind(gto farosv1alpha1.GitTrackObjectInterface, gk schema.GroupKind) bool {
+	if !managesKind(gto, gk) {
+		return false
+	}
+	for _, condition := range gto.GetStatus().Conditions {
+		if condition.Type == farosv1alpha1.ObjectInSyncType {
+			return condition.Reason == string(gittrackobjectutils.ErrorWatchingChild)
+		}
+	}
+	return false
+}
+
+// managesKind returns true if the (Cluster)GitTrackObject manages a child of
+// the given group and kind. The group of the children is only known once they
+// have been recorded in the status, until then only the kind is compared.
+func managesKind(gto farosv1alpha1.GitTrackObjectInterface, gk schema.GroupKind) bool {
+	children := gto.GetStatus().Children
+	if len(children) == 0 {
+		return gto.GetSpec().Kind == gk.Kind
+	}
+	for _, ref := range children {
+		if schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind() == gk {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("CRDs Suite", func() {
+	Context("isMissingKind", func() {
+		var gto *farosv1alpha1.GitTrackObject
+		var foo = schema.GroupKind{Group: "foo.example.com", Kind: "Foo"}
+
+		BeforeEach(func() {
+			gto = &farosv1alpha1.GitTrackObject{
+				Spec: farosv1alpha1.GitTrackObjectSpec{Kind: "Foo", Name: "example"},
+				Status: farosv1alpha1.GitTrackObjectStatus{
+					Conditions: []farosv1alpha1.GitTrackObjectCondition{
+						{
+							Type:   farosv1alpha1.ObjectInSyncType,
+							Reason: string(gittrackobjectutils.ErrorWatchingChild),
+						},
+					},
+				},
+			}
+		})
+
+		It("matches the kind while the children are not recorded", func() {
+			Expect(isMissingKind(gto, foo)).To(BeTrue())
+			Expect(isMissingKind(gto, schema.GroupKind{Group: "foo.example.com", Kind: "Bar"})).To(BeFalse())
+		})
+
+		It("matches the group and kind of the recorded children", func() {
+			gto.Status.Children = []farosv1alpha1.ChildReference{
+				{APIVersion: "foo.example.com/v1", Kind: "Foo", Name: "example"},
+			}
+			Expect(isMissingKind(gto, foo)).To(BeTrue())
+			Expect(isMissingKind(gto, schema.GroupKind{Group: "bar.example.com", Kind: "Foo"})).To(BeFalse())
+		})
+
+		It("does not match when the child is being watched", func() {
+			gto.Status.Conditions[0].Reason = string(gittrackobjectutils.ChildAppliedSuccess)
+			Expect(isMissingKind(gto, foo)).To(BeFalse())
+		})
+	})
+})
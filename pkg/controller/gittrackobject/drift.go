@@ -0,0 +1,64 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	farosclient "github.com/pusher/faros/pkg/utils/client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// detectDrift determines whether the update about to be made to the child
+// resets changes made outside of faros, and returns the field paths that
+// were changed if so
+func (r *ReconcileGitTrackObject) detectDrift(found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) ([]string, error) {
+	// If the desired state has changed since it was last applied, the update
+	// is expected and not drift
+	matches, err := r.applier.MatchesLastApplied(found, child)
+	if err != nil || !matches {
+		return nil, err
+	}
+
+	diffOpts := *opts
+	result, err := r.applier.Diff(context.TODO(), &diffOpts, child, found)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute diff: %v", err)
+	}
+	return result.Paths, nil
+}
+
+// handleDrift records an event and updates the drift metric for the fields
+// of the child that were modified outside of faros, warning when the child
+// keeps being changed back by another controller
+func (r *ReconcileGitTrackObject) handleDrift(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	r.log.V(0).Info("Child drifted from desired state", "paths", paths)
+	r.sendEvent(gto, corev1.EventTypeWarning, "DriftCorrected", "Corrected out-of-band changes to child %s %s/%s: %s", child.GetKind(), child.GetNamespace(), child.GetName(), strings.Join(paths, ", "))
+	r.updateDriftMetric(child.GetKind(), child.GetNamespace())
+
+	if r.conflicts.record(child) {
+		r.log.V(0).Info("Child in conflict with another controller, backing off", "window", r.conflicts.window)
+		r.sendEvent(gto, corev1.EventTypeWarning, "ControllerConflict", "Child %s %s/%s was changed back %d times within %s, another controller may be managing it, backing off for %s: %s", child.GetKind(), child.GetNamespace(), child.GetName(), r.conflicts.threshold, r.conflicts.window, r.conflicts.window, strings.Join(paths, ", "))
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"context"
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"github.com/pusher/faros/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// orphanFinalizer is added to (Cluster)GitTrackObjects whose child should be
+// left in place when the (Cluster)GitTrackObject is deleted
+const orphanFinalizer = gittrackobjectutils.OrphanFinalizer
+
+// cleanupFinalizer is added to ClusterGitTrackObjects whose children should
+// be deleted with them, so that the children are removed before the
+// ClusterGitTrackObject rather than left to the garbage collector once it is
+// gone
+const cleanupFinalizer = "faros.pusher.com/cleanup"
+
+// updateFinalizers adds or removes the orphan finalizer on the
+// (Cluster)GitTrackObject depending on the orphan-on-delete annotation.
+// ClusterGitTrackObjects whose children are not orphaned get the cleanup
+// finalizer instead.
+func (r *ReconcileGitTrackObject) updateFinalizers(gto farosv1alpha1.GitTrackObjectInterface, children []*unstructured.Unstructured) error {
+	orphan, err := shouldOrphanChildren(gto, children)
+	if err != nil {
+		return err
+	}
+	_, cluster := gto.(*farosv1alpha1.ClusterGitTrackObject)
+
+	updated := setFinalizer(gto, orphanFinalizer, orphan)
+	updated = setFinalizer(gto, cleanupFinalizer, cluster && !orphan) || updated
+	if !updated {
+		return nil
+	}
+	return r.Update(context.TODO(), gto)
+}
+
+// setFinalizer adds or removes the finalizer on the object and returns
+// whether its finalizers were changed
+func setFinalizer(obj metav1.Object, finalizer string, present bool) bool {
+	if present == utils.HasFinalizer(obj, finalizer) {
+		return false
+	}
+	if present {
+		obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	} else {
+		obj.SetFinalizers(utils.RemoveFinalizer(obj.GetFinalizers(), finalizer))
+	}
+	return true
+}
+
+// handleDeletion removes the (Cluster)GitTrackObject's owner reference from
+// its child before removing the orphan finalizer so that the child is not
+// garbage collected. Children in other clusters are deleted explicitly, as are
+// the children of ClusterGitTrackObjects with the cleanup finalizer.
+func (r *ReconcileGitTrackObject) handleDeletion(gto farosv1alpha1.GitTrackObjectInterface) error {
+	if utils.HasFinalizer(gto, remoteFinalizer) {
+		return r.handleRemoteDeletion(gto)
+	}
+	if utils.HasFinalizer(gto, cleanupFinalizer) {
+		return r.handleCleanup(gto)
+	}
+	if !utils.HasFinalizer(gto, orphanFinalizer) {
+		return nil
+	}
+
+	children, _, err := r.getChildrenFromGitTrackObject(gto)
+	if err == nil {
+		for _, child := range children {
+			for _, c := range selectedChildren(gto, child) {
+				err = r.orphanChild(gto, c)
+				if err != nil {
+					return fmt.Errorf("unable to orphan child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err)
+				}
+			}
+		}
+	}
+
+	gto.SetFinalizers(utils.RemoveFinalizer(gto.GetFinalizers(), orphanFinalizer))
+	err = r.Update(context.TODO(), gto)
+	if err != nil {
+		return fmt.Errorf("unable to remove finalizer: %v", err)
+	}
+	return nil
+}
+
+// handleCleanup deletes the children of the ClusterGitTrackObject, unless its
+// dependents are being orphaned, and then removes the cleanup finalizer
+func (r *ReconcileGitTrackObject) handleCleanup(gto farosv1alpha1.GitTrackObjectInterface) error {
+	orphan := utils.HasFinalizer(gto, orphanFinalizer) || utils.HasFinalizer(gto, metav1.FinalizerOrphanDependents)
+	if !orphan {
+		for _, child := range r.cleanupChildren(gto) {
+			err := r.removeChild(gto, child, "its ClusterGitTrackObject is being deleted")
+			if err != nil {
+				r.sendEvent(gto, corev1.EventTypeWarning, "CleanupFailed", "Failed to clean-up child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+				return fmt.Errorf("unable to clean-up child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err)
+			}
+		}
+	}
+
+	gto.SetFinalizers(utils.RemoveFinalizer(gto.GetFinalizers(), cleanupFinalizer))
+	err := r.Update(context.TODO(), gto)
+	if err != nil {
+		return fmt.Errorf("unable to remove finalizer: %v", err)
+	}
+	return nil
+}
+
+// cleanupChildren returns the children to delete with the
+// ClusterGitTrackObject, falling back to the children recorded in its status
+// when they cannot be read from its data
+func (r *ReconcileGitTrackObject) cleanupChildren(gto farosv1alpha1.GitTrackObjectInterface) []*unstructured.Unstructured {
+	cleanup := []*unstructured.Unstructured{}
+	children, _, err := r.getChildrenFromGitTrackObject(gto)
+	if err != nil {
+		for _, ref := range gto.GetStatus().Children {
+			cleanup = append(cleanup, referencedChild(ref))
+		}
+		return cleanup
+	}
+	for _, child := range children {
+		cleanup = append(cleanup, selectedChildren(gto, child)...)
+	}
+	return cleanup
+}
+
+// orphanChild removes any owner references to the (Cluster)GitTrackObject
+// from the child
+func (r *ReconcileGitTrackObject) orphanChild(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) error {
+	found := &unstructured.Unstructured{}
+	found.SetKind(child.GetKind())
+	found.SetAPIVersion(child.GetAPIVersion())
+	err := r.Get(context.TODO(), types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	ownerRefs := []metav1.OwnerReference{}
+	for _, ref := range found.GetOwnerReferences() {
+		if ref.UID != gto.GetUID() {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	if len(ownerRefs) == len(found.GetOwnerReferences()) {
+		return nil
+	}
+
+	found.SetOwnerReferences(ownerRefs)
+	err = r.Update(context.TODO(), found)
+	if err != nil {
+		return err
+	}
+	r.log.V(0).Info("Child orphaned")
+	r.sendEvent(gto, corev1.EventTypeNormal, "Orphaned", "Removed owner reference from child %s %s/%s, leaving it in place", child.GetKind(), child.GetNamespace(), child.GetName())
+	return nil
+}
+
+// shouldOrphanChildren returns whether the children of the
+// (Cluster)GitTrackObject should be left in place when it is deleted. The
+// children are deleted together, so they are all orphaned if any of them asks
+// to be.
+func shouldOrphanChildren(gto farosv1alpha1.GitTrackObjectInterface, children []*unstructured.Unstructured) (bool, error) {
+	for _, child := range children {
+		orphan, err := gittrackobjectutils.ShouldOrphanOnDelete(gto, child)
+		if err != nil || orphan {
+			return orphan, err
+		}
+	}
+	return false, nil
+}
@@ -22,24 +22,37 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
 
 	"github.com/go-logr/logr"
+	"github.com/pusher/faros/pkg/health"
+	"github.com/pusher/faros/pkg/shutdown"
 	"github.com/pusher/faros/pkg/utils"
 	farosclient "github.com/pusher/faros/pkg/utils/client"
+	"github.com/pusher/faros/pkg/utils/events"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -63,17 +76,58 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		close(stop)
 	}()
 
-	applier, err := farosclient.NewApplier(mgr.GetConfig(), farosclient.Options{})
+	// Apply any client rate limits to the config used for managing children
+	config := rest.CopyConfig(mgr.GetConfig())
+	if farosflags.ClientQPS > 0 {
+		config.QPS = farosflags.ClientQPS
+	}
+	if farosflags.ClientBurst > 0 {
+		config.Burst = farosflags.ClientBurst
+	}
+
+	applier, err := farosclient.NewApplier(config, farosclient.Options{})
 	if err != nil {
 		panic(fmt.Errorf("unable to create applier: %v", err))
 	}
 
-	dryRunVerifier, err := utils.NewDryRunVerifier(mgr.GetConfig())
+	dryRunVerifier, err := utils.NewDryRunVerifier(config)
 	if err != nil {
 		panic(fmt.Errorf("unable to create dry run verifier: %v", err))
 	}
 
-	return &ReconcileGitTrackObject{
+	restMapper, err := utils.NewRestMapper(mgr.GetConfig())
+	if err != nil {
+		panic(fmt.Errorf("unable to create rest mapper: %v", err))
+	}
+
+	// Data stored outside of GitTrackObjects is read directly from the API
+	// server rather than caching every ConfigMap in the cluster
+	apiReader, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: restMapper})
+	if err != nil {
+		panic(fmt.Errorf("unable to create API client: %v", err))
+	}
+
+	allowedGVRs, err := farosflags.ParseAllowedResources()
+	if err != nil {
+		panic(fmt.Errorf("unable to parse allowed resources: %v", err))
+	}
+
+	var applyLimiter flowcontrol.RateLimiter
+	if farosflags.ApplyRate > 0 {
+		applyLimiter = flowcontrol.NewTokenBucketRateLimiter(farosflags.ApplyRate, farosflags.ApplyBurst)
+	}
+
+	// Collapse repeated events during reconcile storms
+	recorder := events.NewAggregator(
+		mgr.GetEventRecorderFor("gittrackobject-controller"),
+		farosflags.EventAggregationWindow,
+		farosflags.AggregatedEventReasons,
+	)
+	if aggregator, ok := recorder.(*events.Aggregator); ok {
+		shutdown.AddFlusher("gittrackobject-events", aggregator.Flush)
+	}
+
+	r := &ReconcileGitTrackObject{
 		Client:         mgr.GetClient(),
 		scheme:         mgr.GetScheme(),
 		eventStream:    make(chan event.GenericEvent),
@@ -81,11 +135,20 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		informers:      make(map[string]cache.Informer),
 		config:         mgr.GetConfig(),
 		stop:           stop,
-		recorder:       mgr.GetEventRecorderFor("gittrackobject-controller"),
+		recorder:       recorder,
 		applier:        applier,
 		dryRunVerifier: dryRunVerifier,
+		applyLimiter:   applyLimiter,
+		restMapper:     restMapper,
+		apiReader:      apiReader,
+		allowedGVRs:    allowedGVRs,
+		remoteClusters: make(map[farosv1alpha1.KubeConfigSecretReference]*remoteCluster),
+		conflicts:      newConflictTracker(farosflags.ConflictThreshold, farosflags.ConflictWindow),
+		mutex:          &sync.RWMutex{},
 		log:            rlogr.Log.WithName("gittrackobject-controller"),
 	}
+	farosflags.OnReload(r.reloadAllowedResources)
+	return r
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -96,8 +159,18 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// Watch for changes to GitTrackObject
-	err = c.Watch(&source.Kind{Type: &farosv1alpha1.GitTrackObject{}}, &handler.EnqueueRequestForObject{})
+	selector, err := farosflags.ParseGitTrackSelector()
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to GitTrackObject, when sharding by GitTrack selector
+	// only those owned by a selected GitTrack are reconciled
+	gtoPredicates := []predicate.Predicate{}
+	if !selector.Empty() {
+		gtoPredicates = append(gtoPredicates, utils.NewOwnerInNamespacePredicate(mgr.GetClient(), selector))
+	}
+	err = c.Watch(&source.Kind{Type: &farosv1alpha1.GitTrackObject{}}, &handler.EnqueueRequestForObject{}, gtoPredicates...)
 	if err != nil {
 		return err
 	}
@@ -106,7 +179,32 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	err = c.Watch(
 		&source.Kind{Type: &farosv1alpha1.ClusterGitTrackObject{}},
 		&handler.EnqueueRequestForObject{},
-		utils.NewOwnerInNamespacePredicate(mgr.GetClient()),
+		utils.NewOwnerInNamespacePredicate(mgr.GetClient(), selector),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for CRDs so that children whose kind wasn't available when they were
+	// first seen are retried as soon as their CRD is installed
+	err = c.Watch(
+		&source.Kind{Type: &apiextensionsv1beta1.CustomResourceDefinition{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: missingKindRequests(mgr.GetClient(), rlogr.Log.WithName("gittrackobject-controller/missing-kind-requests")),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	// Watch for Namespaces so that the children of ClusterGitTrackObjects
+	// selecting namespaces follow namespaces as they appear, disappear or are
+	// relabelled
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Namespace{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: namespaceSelectorRequests(mgr.GetClient(), rlogr.Log.WithName("gittrackobject-controller/namespace-selector-requests")),
+		},
 	)
 	if err != nil {
 		return err
@@ -131,7 +229,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 				},
 				Log: rlogr.Log.WithName("gittrackobject-controller/enqueue-request-for-owner"),
 			},
-			utils.NewOwnersOwnerInNamespacePredicate(mgr.GetClient()),
+			utils.NewOwnersOwnerInNamespacePredicate(mgr.GetClient(), selector),
 		)
 		if err != nil {
 			msg := fmt.Sprintf("unable to watch channel: %v", err)
@@ -166,6 +264,13 @@ type ReconcileGitTrackObject struct {
 
 	applier        farosclient.Client
 	dryRunVerifier *utils.DryRunVerifier
+	applyLimiter   flowcontrol.RateLimiter
+	restMapper     meta.RESTMapper
+	apiReader      client.Reader
+	allowedGVRs    map[schema.GroupVersionResource]interface{}
+	remoteClusters map[farosv1alpha1.KubeConfigSecretReference]*remoteCluster
+	conflicts      *conflictTracker
+	mutex          *sync.RWMutex
 }
 
 // EventStream returns a stream of generic event to trigger reconciles
@@ -179,17 +284,62 @@ func (r *ReconcileGitTrackObject) StopChan() chan struct{} {
 }
 
 func (r *ReconcileGitTrackObject) withValues(keysAndValues ...interface{}) *ReconcileGitTrackObject {
+	r.mutex.RLock()
 	reconciler := *r
+	r.mutex.RUnlock()
 	reconciler.log = r.log.WithValues(keysAndValues...)
 	return &reconciler
 }
 
+// reloadAllowedResources parses the allowed resources again once the
+// configuration file has been reloaded, keeping the previous set if it is
+// invalid
+func (r *ReconcileGitTrackObject) reloadAllowedResources() {
+	allowed, err := farosflags.ParseAllowedResources()
+	if err != nil {
+		r.log.Error(err, "unable to parse reloaded allowed resources")
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.allowedGVRs = allowed
+}
+
+// ownerName returns the name of the GitTrack controlling the instance, or an
+// empty string if it has no controller
+func ownerName(instance farosv1alpha1.GitTrackObjectInterface) string {
+	if owner := metav1.GetControllerOf(instance); owner != nil {
+		return owner.Name
+	}
+	return ""
+}
+
 // Reconcile reads that state of the cluster for a GitTrackObject object and makes changes based on the state read
 // and what is in the GitTrackObject.Spec
 // Automatically generate RBAC rules to allow the Controller to read and write Deployments
 // +kubebuilder:rbac:groups=*,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=faros.pusher.com,resources=gittrackobjects,verbs=get;list;watch;create;update;patch;delete
 func (r *ReconcileGitTrackObject) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	defer health.RecoverPanic("gittrackobject-controller")
+	// Leave the request for the next instance once shutdown has begun
+	done, ok := shutdown.Begin()
+	if !ok {
+		r.log.V(1).Info("Shutting down, skipping reconcile", "request", request.String())
+		return reconcile.Result{}, nil
+	}
+	defer done()
+	start := time.Now()
+	result, err := r.handleReconcile(request)
+	if mErr := updateReconcileDurationMetric(time.Since(start), err); mErr != nil {
+		r.log.Error(mErr, "error updating reconcile duration metric")
+	}
+	return result, err
+}
+
+// handleReconcile performs the reconcile of the (Cluster)GitTrackObject for
+// the request
+func (r *ReconcileGitTrackObject) handleReconcile(request reconcile.Request) (reconcile.Result, error) {
 	// Fetch the GTO requested
 	instance, err := r.getInstance(request)
 	if err != nil {
@@ -205,20 +355,64 @@ func (r *ReconcileGitTrackObject) Reconcile(request reconcile.Request) (reconcil
 	// Get a reconciler with appropriate logging values
 	reconciler := r.withValues(
 		"namespace", instance.GetNamespace(),
+		"name", instance.GetName(),
+		"gittrack", ownerName(instance),
 		"child name", instance.GetSpec().Name,
 		"child kind", instance.GetSpec().Kind,
 	)
 
 	reconciler.log.V(1).Info("Reconcile started")
 
+	// Orphan the child if required when the instance is being deleted
+	if instance.GetDeletionTimestamp() != nil {
+		err = reconciler.handleDeletion(instance)
+		reconciler.log.V(1).Info("Reconcile finished")
+		return reconcile.Result{}, err
+	}
+
+	// Leave the child alone while the instance is suspended
+	if instance.GetSpec().Suspend {
+		err = reconciler.updateSuspendedStatus(instance)
+		reconciler.log.V(1).Info("Reconcile suspended")
+		return reconcile.Result{}, err
+	}
+
 	// Create new opts structs for updating status and metrics
 	result := reconciler.handleGitTrackObject(instance)
-	reconciler.updateStatus(instance, &statusOpts{inSyncError: result.inSyncError, inSyncReason: result.inSyncReason})
-	inSync := result.inSyncError == nil
+	sOpts := &statusOpts{
+		inSyncError:  result.inSyncError,
+		inSyncReason: result.inSyncReason,
+		dryRunDiff:   result.dryRunDiff,
+		health:       result.health,
+		healthDetail: result.healthDetail,
+		lastUpdate:   result.lastUpdate,
+
+		childHash:            result.childHash,
+		childResourceVersion: result.childResourceVersion,
+		childStates:          result.childStates,
+		recreateToken:        result.recreateToken,
+		waitForReady:         result.waitForReady,
+		namespaces:           result.namespaces,
+		children:             result.children,
+	}
+	if result.inSyncError == nil && result.dryRunDiff == "" {
+		sOpts.appliedHash = gittrackobjectutils.DataHash(instance.GetSpec().Data)
+		if dataFrom := instance.GetSpec().DataFrom; dataFrom != nil {
+			sOpts.appliedHash = dataFrom.Hash
+		}
+		sOpts.lastAppliedData = result.lastAppliedData
+	}
+	reconciler.updateStatus(instance, sOpts)
+	inSync := result.inSyncError == nil && result.dryRunDiff == "" && !sOpts.waitingForReady()
 	reconciler.updateMetrics(instance, &metricsOpts{inSync: inSync})
 
 	reconciler.log.V(1).Info("Reconcile finished")
-	return reconcile.Result{}, result.inSyncError
+	// Retrying a child in conflict would only fight the other controller, so
+	// wait until the back off has passed
+	if result.inSyncReason == gittrackobjectutils.ChildConflict {
+		return reconcile.Result{RequeueAfter: result.requeueAfter}, nil
+	}
+	return reconcile.Result{RequeueAfter: result.requeueAfter}, result.inSyncError
 }
 
 // getInstance fetches the requested (Cluster)GitTrackObject from the API server
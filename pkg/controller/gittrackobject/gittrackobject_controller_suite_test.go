@@ -17,28 +17,20 @@ limitations under the License.
 package gittrackobject
 
 import (
-	"flag"
 	"log"
-	"path/filepath"
 	"sync"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/pusher/faros/pkg/apis"
 	farosflags "github.com/pusher/faros/pkg/flags"
 	"github.com/pusher/faros/test/reporters"
+	"github.com/pusher/faros/test/testenv"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
-	"k8s.io/klog"
-	"k8s.io/klog/klogr"
-	"sigs.k8s.io/controller-runtime/pkg/envtest"
-	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	logr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 var cfg *rest.Config
@@ -48,26 +40,18 @@ func TestGitTrackObjectController(t *testing.T) {
 	RunSpecsWithDefaultAndCustomReporters(t, "GitTrackObject Suite", reporters.Reporters())
 }
 
-var t *envtest.Environment
+var t *testenv.Environment
 
 var _ = BeforeSuite(func() {
-	logr.SetLogger(klogr.New())
-	logFlags := &flag.FlagSet{}
-	klog.InitFlags(logFlags)
-	// Set log level high for tests
-	logFlags.Lookup("v").Value.Set("4")
-
-	t = &envtest.Environment{
-		CRDDirectoryPaths: []string{filepath.Join("..", "..", "..", "config", "crds")},
-	}
-	apis.AddToScheme(scheme.Scheme)
+	testenv.SetupLogging()
 
-	farosflags.Namespace = "default"
+	farosflags.Namespaces = []string{"default"}
 
 	var err error
-	if cfg, err = t.Start(); err != nil {
+	if t, err = testenv.Start(); err != nil {
 		log.Fatal(err)
 	}
+	cfg = t.Config
 })
 
 var _ = AfterSuite(func() {
@@ -106,16 +90,6 @@ func SetupTestReconcile(inner reconcile.Reconciler) (reconcile.Reconciler, chan
 	}, requests, wait
 }
 
-// StartTestManager adds recFn
-func StartTestManager(mgr manager.Manager) chan struct{} {
-	stop := make(chan struct{})
-	go func() {
-		defer GinkgoRecover()
-		Expect(mgr.Start(stop)).NotTo(HaveOccurred())
-	}()
-	return stop
-}
-
 // testEventRecorder is used to inspect the input to the Reconciler's event
 // recorder during tests
 type testEventRecorder struct {
@@ -29,12 +29,15 @@ import (
 	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
 	farosflags "github.com/pusher/faros/pkg/flags"
 	farosclient "github.com/pusher/faros/pkg/utils/client"
+	"github.com/pusher/faros/test/testenv"
 	testutils "github.com/pusher/faros/test/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -65,10 +68,7 @@ var _ = Describe("GitTrackObject Suite", func() {
 		// channel when it is finished.
 		var err error
 		cfg.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
-		mgr, err = manager.New(cfg, manager.Options{
-			Namespace:          farosflags.Namespace,
-			MetricsBindAddress: "0", // Disable serving metrics while testing
-		})
+		mgr, err = t.NewManager(farosflags.Namespaces[0])
 		Expect(err).NotTo(HaveOccurred())
 
 		applier, err := farosclient.NewApplier(cfg, farosclient.Options{})
@@ -85,7 +85,7 @@ var _ = Describe("GitTrackObject Suite", func() {
 		Expect(add(mgr, recFn)).NotTo(HaveOccurred())
 
 		stopInformers = r.StopChan()
-		stop = StartTestManager(mgr)
+		stop = testenv.StartManager(mgr)
 
 		// Create a GitTrack to own the ClusterGitTrackObjects
 		// The Reconciler wont reconcile CGTOs that aren't owned by the a GT in their
@@ -122,6 +122,8 @@ var _ = Describe("GitTrackObject Suite", func() {
 		// Wait for last reconcile to stop
 		reconcileStopped.Wait()
 
+		// Remove finalizers as the controller is no longer running to handle them
+		testutils.RemoveFinalizers(cfg, &farosv1alpha1.GitTrackObjectList{}, &farosv1alpha1.ClusterGitTrackObjectList{})
 		// Clean up all resources as GC is disabled in the control plane
 		testutils.DeleteAll(cfg, timeout,
 			&farosv1alpha1.GitTrackList{},
@@ -179,6 +181,72 @@ var _ = Describe("GitTrackObject Suite", func() {
 						Should(testutils.WithAnnotations(HaveKey(farosclient.LastAppliedAnnotation)))
 				})
 
+				It("should record the synced state of the child", func() {
+					Eventually(func() error {
+						m.Get(gto, timeout).Should(Succeed())
+						m.Get(child, timeout).Should(Succeed())
+						if gto.Status.ChildHash == "" {
+							return fmt.Errorf("child hash not recorded")
+						}
+						if gto.Status.ChildResourceVersion != child.GetResourceVersion() {
+							return fmt.Errorf("child resource version %q, expected %q", gto.Status.ChildResourceVersion, child.GetResourceVersion())
+						}
+						return nil
+					}, timeout).Should(Succeed())
+				})
+
+				Context("when the child is modified after it was synced", func() {
+					BeforeEach(func() {
+						child.Spec.Template.Spec.Containers[0].Image = "nginx:latest"
+						m.Update(child).Should(Succeed())
+						Eventually(requests, timeout).Should(Receive(Equal(expectedRequest)))
+					})
+
+					It("should not skip updating the child", func() {
+						m.Eventually(child, timeout).Should(testutils.WithContainers(ContainElement(testutils.WithImage(Equal("nginx")))))
+					})
+				})
+
+				Context("when the recreate annotation is set", func() {
+					var originalUID types.UID
+
+					BeforeEach(func() {
+						originalUID = child.GetUID()
+						gto.SetAnnotations(map[string]string{gittrackobjectutils.RecreateAnnotation: "1546300800"})
+						m.Update(gto, timeout).Should(Succeed())
+
+						// Keep a copy of the child, otherwise we'll run into data race issues
+						childCopy := child.DeepCopy()
+
+						go func() {
+							defer GinkgoRecover()
+							// We are expecting a delete but we have no GC so have to do it manually
+							m.Eventually(childCopy, timeout).Should(testutils.WithFinalizers(ContainElement("foregroundDeletion")))
+							childCopy.SetFinalizers([]string{})
+							m.Update(childCopy).Should(Succeed())
+						}()
+
+						Eventually(requests, timeout).Should(Receive(Equal(expectedRequest)))
+					})
+
+					It("should replace the child", func() {
+						m.Eventually(child, timeout).Should(testutils.WithUID(Not(Equal(originalUID))))
+					})
+
+					It("should record the recreation in the status", func() {
+						Eventually(func() string {
+							m.Get(gto, timeout).Should(Succeed())
+							return gto.Status.LastRecreateToken
+						}, timeout).Should(Equal("1546300800"))
+					})
+
+					It("should only replace the child once", func() {
+						m.Eventually(child, timeout).Should(testutils.WithUID(Not(Equal(originalUID))))
+						m.Get(child, timeout).Should(Succeed())
+						m.Consistently(child, consistentlyTimeout).Should(testutils.WithUID(Equal(child.GetUID())))
+					})
+				})
+
 				Context("when the child has the update strategy", func() {
 					var originalVersion string
 					var originalUID types.UID
@@ -384,7 +452,7 @@ var _ = Describe("GitTrackObject Suite", func() {
 					PIt("to the namespace the controller is restricted to", func() {
 						for range events.Items {
 							event := <-testEvents
-							Expect(event.Namespace).To(Equal(farosflags.Namespace))
+							Expect(event.Namespace).To(Equal(farosflags.Namespaces[0]))
 						}
 					})
 				})
@@ -406,6 +474,17 @@ var _ = Describe("GitTrackObject Suite", func() {
 									Not(ContainElement(testutils.WithImage(Equal("nginx:latest")))),
 								)))
 						})
+
+						It("should send a DriftCorrected event", func() {
+							events := &corev1.EventList{}
+							m.Eventually(events, timeout).Should(testutils.WithItems(ContainElement(
+								SatisfyAll(
+									testutils.WithReason(Equal("DriftCorrected")),
+									testutils.WithInvolvedObjectName(Equal(gto.GetName())),
+									testutils.WithEventType(Equal(string(corev1.EventTypeWarning))),
+								),
+							)))
+						})
 					})
 
 					Context("in a non-conflicting manner", func() {
@@ -432,6 +511,59 @@ var _ = Describe("GitTrackObject Suite", func() {
 				})
 			})
 
+			Context("when suspended", func() {
+				BeforeEach(func() {
+					gto.Spec.Suspend = true
+
+					// Create and fetch the instance to make sure caches are synced
+					m.Create(gto).Should(Succeed())
+					// Wait twice for the extra reconcile for status updates
+					Eventually(requests, timeout).Should(Receive(Equal(expectedRequest)))
+					Eventually(requests, timeout).Should(Receive(Equal(expectedRequest)))
+					m.Get(gto, timeout).Should(Succeed())
+				})
+
+				It("should not create the child resource", func() {
+					key := types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}
+					Consistently(func() error {
+						return c.Get(context.TODO(), key, &appsv1.Deployment{})
+					}, consistentlyTimeout).ShouldNot(Succeed())
+				})
+
+				It("should set the Suspended condition", func() {
+					m.Eventually(gto, timeout).Should(
+						testutils.WithGitTrackObjectStatusConditions(
+							ContainElement(
+								SatisfyAll(
+									testutils.WithGitTrackObjectConditionType(Equal(farosv1alpha1.ObjectSuspendedType)),
+									testutils.WithGitTrackObjectConditionStatus(Equal(corev1.ConditionTrue)),
+									testutils.WithGitTrackObjectConditionReason(Equal(string(gittrackobjectutils.SuspendedBySpec))),
+								),
+							),
+						),
+					)
+				})
+
+				Context("and then resumed", func() {
+					BeforeEach(func() {
+						gto.Spec.Suspend = false
+						m.Update(gto, timeout).Should(Succeed())
+					})
+
+					It("should create the child resource", func() {
+						m.Get(child, timeout).Should(Succeed())
+					})
+
+					It("should remove the Suspended condition", func() {
+						m.Eventually(gto, timeout).Should(
+							testutils.WithGitTrackObjectStatusConditions(
+								Not(ContainElement(testutils.WithGitTrackObjectConditionType(Equal(farosv1alpha1.ObjectSuspendedType)))),
+							),
+						)
+					})
+				})
+			})
+
 			Context("with invalid data", func() {
 				BeforeEach(func() {
 					// Break the JSON data
@@ -482,6 +614,83 @@ var _ = Describe("GitTrackObject Suite", func() {
 				})
 			})
 
+			Context("targeting another cluster", func() {
+				BeforeEach(func() {
+					// The test cluster stands in for the remote cluster
+					kubeConfig, err := clientcmd.Write(clientcmdapi.Config{
+						Clusters:       map[string]*clientcmdapi.Cluster{"remote": {Server: cfg.Host}},
+						Contexts:       map[string]*clientcmdapi.Context{"remote": {Cluster: "remote"}},
+						CurrentContext: "remote",
+					})
+					Expect(err).NotTo(HaveOccurred())
+					secret := &corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{Name: "remote-kubeconfig", Namespace: "default"},
+						Data:       map[string][]byte{"config": kubeConfig},
+					}
+					m.Create(secret).Should(Succeed())
+
+					gto.Spec.KubeConfigSecretRef = &farosv1alpha1.KubeConfigSecretReference{
+						Namespace:  "default",
+						SecretName: "remote-kubeconfig",
+						Key:        "config",
+					}
+					m.Create(gto).Should(Succeed())
+					Eventually(requests, timeout).Should(Receive(Equal(expectedRequest)))
+				})
+
+				AfterEach(func() {
+					testutils.DeleteAll(cfg, timeout, &corev1.SecretList{})
+				})
+
+				It("should create the child resource", func() {
+					m.Get(child, timeout).Should(Succeed())
+				})
+
+				It("should record the owner in an annotation instead of an owner reference", func() {
+					m.Eventually(child, timeout).Should(testutils.WithAnnotations(HaveKeyWithValue("faros.pusher.com/owned-by", gto.GetNamespacedName())))
+					m.Get(child, timeout).Should(Succeed())
+					Expect(child.GetOwnerReferences()).To(BeEmpty())
+				})
+
+				It("should add the remote finalizer", func() {
+					m.Eventually(gto, timeout).Should(testutils.WithFinalizers(ContainElement("faros.pusher.com/remote-child")))
+				})
+
+				Context("when the GitTrackObject is deleted", func() {
+					BeforeEach(func() {
+						m.Get(child, timeout).Should(Succeed())
+						m.Eventually(gto, timeout).Should(testutils.WithFinalizers(ContainElement("faros.pusher.com/remote-child")))
+						m.Delete(gto).Should(Succeed())
+					})
+
+					It("should delete the child resource", func() {
+						m.Get(child, timeout).ShouldNot(Succeed())
+					})
+				})
+
+				Context("when the kubeconfig is missing", func() {
+					BeforeEach(func() {
+						m.Get(gto, timeout).Should(Succeed())
+						gto.Spec.KubeConfigSecretRef.SecretName = "missing"
+						m.Update(gto, timeout).Should(Succeed())
+					})
+
+					It("should update the status", func() {
+						m.Eventually(gto, timeout).Should(
+							testutils.WithGitTrackObjectStatusConditions(
+								ContainElement(
+									SatisfyAll(
+										testutils.WithGitTrackObjectConditionType(Equal(farosv1alpha1.ObjectInSyncType)),
+										testutils.WithGitTrackObjectConditionStatus(Equal(corev1.ConditionFalse)),
+										testutils.WithGitTrackObjectConditionReason(Equal(string(gittrackobjectutils.ErrorLoadingKubeConfig))),
+									),
+								),
+							),
+						)
+					})
+				})
+			})
+
 			Context("in a different namespace", func() {
 				var ns *corev1.Namespace
 
@@ -757,7 +966,7 @@ var _ = Describe("GitTrackObject Suite", func() {
 					PIt("to the namespace the controller is restricted to", func() {
 						for range events.Items {
 							event := <-testEvents
-							Expect(event.Namespace).To(Equal(farosflags.Namespace))
+							Expect(event.Namespace).To(Equal(farosflags.Namespaces[0]))
 						}
 					})
 				})
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
@@ -28,28 +30,72 @@ import (
 	farosclient "github.com/pusher/faros/pkg/utils/client"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// recreateTimeout is how long to wait for a child to be deleted before it is
+// recreated
+const recreateTimeout = 30 * time.Second
+
 // handlerResult is the single return object from handleGitTrackObject
 // It contains all information required to update the status and metrics of
 // the (Cluster)GitTrackObject passed to it
 type handlerResult struct {
 	inSyncError  error
 	inSyncReason gittrackobjectutils.ConditionReason
+	dryRunDiff   string
+	health       gittrackobjectutils.Health
+	healthDetail string
+	lastUpdate   *farosv1alpha1.ChildUpdate
+
+	// childHash and childResourceVersion record the state of the child once
+	// it has been successfully synced
+	childHash            string
+	childResourceVersion string
+
+	// childStates record the state of each child, keyed by childKey, once it
+	// has been successfully synced when there is more than one child
+	childStates map[string]farosv1alpha1.ChildState
+
+	// waitForReady is set when the child is only in sync once it is ready
+	waitForReady bool
+
+	// recreateToken is the value of the `faros.pusher.com/recreate` annotation
+	// the child was recreated for
+	recreateToken string
+
+	// requeueAfter is set when the child should be checked again without
+	// waiting for it to change
+	requeueAfter time.Duration
+
+	// namespaces the child has been applied to when the ClusterGitTrackObject
+	// selects namespaces
+	namespaces []string
+
+	// lastAppliedData is the compressed children applied, set when the
+	// configuration of a child is too large for its last applied annotation
+	lastAppliedData []byte
+	// children applied from the data, along with removed children that could
+	// not be deleted
+	children []farosv1alpha1.ChildReference
 }
 
-// handleGitTrackObject handles the management of the child of the GitTrackObjectInterface
-// and returns a handlerResult which contains information for updating the
-// (Cluster)GitTrackObject's status and metrics
+// handleGitTrackObject handles the management of the children of the
+// GitTrackObjectInterface and returns a handlerResult which contains
+// information for updating the (Cluster)GitTrackObject's status and metrics
 //
-// It reads the child object from the instance and udpates the API if the object
-// is out of sync
+// It reads the child objects from the instance and udpates the API if they are
+// out of sync. Data holding multiple documents is managed as a unit: every
+// document is applied on each sync and the (Cluster)GitTrackObject is only in
+// sync once all of them are.
 func (r *ReconcileGitTrackObject) handleGitTrackObject(gto farosv1alpha1.GitTrackObjectInterface) handlerResult {
-	// Generate the child from the spec
-	child, reason, err := r.getChildFromGitTrackObject(gto)
+	// Generate the children from the spec
+	children, reason, err := r.getChildrenFromGitTrackObject(gto)
 	if err != nil {
 		return handlerResult{
 			inSyncReason: reason,
@@ -57,6 +103,86 @@ func (r *ReconcileGitTrackObject) handleGitTrackObject(gto farosv1alpha1.GitTrac
 		}
 	}
 
+	waitForReady := false
+	for _, child := range children {
+		wait, err := gittrackobjectutils.ShouldWaitForReady(gto, child)
+		if err != nil {
+			return handlerResult{
+				inSyncReason: gittrackobjectutils.ErrorUpdatingChild,
+				inSyncError:  fmt.Errorf("error updating child %s %s: unable to get wait-for-ready annotation: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+			}
+		}
+		waitForReady = waitForReady || wait
+	}
+
+	// Make sure the children are orphaned on deletion if requested, children
+	// in other clusters are deleted explicitly instead
+	if gto.GetSpec().KubeConfigSecretRef == nil {
+		err = r.updateFinalizers(gto, children)
+		if err != nil {
+			return handlerResult{
+				inSyncReason: gittrackobjectutils.ErrorUpdatingFinalizer,
+				inSyncError:  fmt.Errorf("unable to update finalizers for child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+			}
+		}
+	}
+
+	// Keep the children as read from the data, before syncing modifies them,
+	// in case they are needed as the baseline of the next update
+	lastAppliedData := r.lastAppliedData(children)
+
+	keys := []string{}
+	results := make(map[string]handlerResult)
+	for _, child := range children {
+		key := childKey(child)
+		keys = append(keys, key)
+		if hasNamespaceSelector(gto) {
+			results[key] = r.syncSelectedNamespaces(gto, child)
+		} else {
+			results[key] = withChildState(r.syncChild(gto, child), child)
+		}
+	}
+	result := results[keys[0]]
+	if len(children) > 1 {
+		result = mergeResults("child", keys, results)
+	}
+	// A single child records its state in childHash and childResourceVersion
+	if result.childHash != "" {
+		result.childStates = nil
+	}
+
+	// Children whose documents have been removed are deleted, children in
+	// other clusters are deleted along with the (Cluster)GitTrackObject
+	if gto.GetSpec().KubeConfigSecretRef == nil {
+		result.children = r.pruneChildren(gto, children, &result)
+	}
+
+	result.waitForReady = waitForReady
+	result.lastAppliedData = lastAppliedData
+	// The health of an updated child is reported from its state before the
+	// update, so it cannot be ready until the update has been observed
+	if waitForReady && result.lastUpdate != nil && result.health == gittrackobjectutils.HealthReady {
+		result.health, result.healthDetail = gittrackobjectutils.HealthProgressing, "waiting for the update to be observed"
+	}
+	return result
+}
+
+// syncChild creates or updates the child of the (Cluster)GitTrackObject
+func (r *ReconcileGitTrackObject) syncChild(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) handlerResult {
+	// Refuse to manage children of kinds the controller is not allowed to
+	err := r.checkAllowed(child)
+	if err != nil {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ChildNotAllowed,
+			inSyncError:  fmt.Errorf("unable to manage child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+
+	// Children of GitTracks targeting another cluster are applied there
+	if gto.GetSpec().KubeConfigSecretRef != nil {
+		return r.handleRemoteGitTrackObject(gto, child)
+	}
+
 	// Make sure to watch the child resource (does nothing if the resource is
 	// already being watched)
 	err = r.watch(*child)
@@ -76,6 +202,23 @@ func (r *ReconcileGitTrackObject) handleGitTrackObject(gto farosv1alpha1.GitTrac
 		}
 	}
 
+	// Skip the update entirely if neither the desired state of the child nor
+	// the child itself have changed since it was last synced
+	hash, err := gittrackobjectutils.ChildHash(gto, child)
+	if err != nil {
+		r.log.Error(err, "unable to hash child")
+	}
+	if cached := r.getUnchangedChild(gto, child, hash); cached != nil {
+		r.log.V(1).Info("Child unchanged since last sync, skipping update")
+		health, detail := gittrackobjectutils.GetHealth(cached)
+		return handlerResult{
+			health:               health,
+			healthDetail:         detail,
+			childHash:            hash,
+			childResourceVersion: cached.GetResourceVersion(),
+		}
+	}
+
 	// Construct holder for API copy of child
 	found := &unstructured.Unstructured{}
 	found.SetKind(child.GetKind())
@@ -83,7 +226,10 @@ func (r *ReconcileGitTrackObject) handleGitTrackObject(gto farosv1alpha1.GitTrac
 
 	err = r.Get(context.TODO(), types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}, found)
 	if err != nil && errors.IsNotFound(err) {
-		reason, err = r.handleCreate(gto, child)
+		if farosflags.DryRun {
+			return r.handleDryRunCreate(gto, child)
+		}
+		reason, err := r.handleCreate(gto, child)
 		if err != nil {
 			return handlerResult{
 				inSyncReason: reason,
@@ -91,8 +237,16 @@ func (r *ReconcileGitTrackObject) handleGitTrackObject(gto farosv1alpha1.GitTrac
 			}
 		}
 
-		// Successfully created child
-		return handlerResult{}
+		// Successfully created child, which also satisfies any pending request
+		// to recreate it
+		health, detail := gittrackobjectutils.GetHealth(child)
+		return handlerResult{
+			health:               health,
+			healthDetail:         detail,
+			childHash:            hash,
+			childResourceVersion: child.GetResourceVersion(),
+			recreateToken:        gittrackobjectutils.PendingRecreate(gto.GetStatus().LastRecreateToken, gto),
+		}
 	} else if err != nil {
 		return handlerResult{
 			inSyncReason: gittrackobjectutils.ErrorGettingChild,
@@ -100,32 +254,150 @@ func (r *ReconcileGitTrackObject) handleGitTrackObject(gto farosv1alpha1.GitTrac
 		}
 	}
 
-	reason, err = r.handleUpdate(gto, found, child)
-	if err != nil {
-		return handlerResult{
-			inSyncReason: reason,
-			inSyncError:  fmt.Errorf("error updating child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+	// Only take ownership of existing unowned children if asked to
+	previousOwner := metav1.GetControllerOf(found)
+	if previousOwner == nil {
+		reason, err := r.handleAdopt(gto, child)
+		if err != nil {
+			return handlerResult{
+				inSyncReason: reason,
+				inSyncError:  fmt.Errorf("error adopting child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+			}
+		}
+	}
+
+	// Delete and recreate the child once if asked to, whatever its update
+	// strategy
+	token := gittrackobjectutils.PendingRecreate(gto.GetStatus().LastRecreateToken, gto, found)
+	if token != "" && !farosflags.DryRun {
+		return r.handleForceRecreate(gto, found, child, hash, token)
+	}
+
+	// The health of the child is reported from its state before the update,
+	// any change in health will trigger a further reconcile
+	result := r.handleUpdate(gto, found, child)
+	result.health, result.healthDetail = gittrackobjectutils.GetHealth(found)
+	if result.inSyncError == nil && result.dryRunDiff == "" {
+		result.childHash = hash
+		result.childResourceVersion = child.GetResourceVersion()
+		if result.childResourceVersion == "" {
+			result.childResourceVersion = found.GetResourceVersion()
+		}
+		r.recordOwnershipChange(gto, child, previousOwner)
+	}
+	return result
+}
+
+// withChildState records the state of the synced child in the result, keyed by
+// the child, so that it is kept when the results of several children are
+// merged
+func withChildState(result handlerResult, child *unstructured.Unstructured) handlerResult {
+	if result.childHash != "" {
+		result.childStates = map[string]farosv1alpha1.ChildState{
+			childKey(child): {Hash: result.childHash, ResourceVersion: result.childResourceVersion},
 		}
 	}
+	return result
+}
+
+// getUnchangedChild returns the child from the informer cache if it has the
+// resourceVersion recorded when it was last synced and the hash of its desired
+// state is unchanged, otherwise it returns nil. The state of each child is
+// looked up by its key when there is more than one child. It also returns nil if the
+// (Cluster)GitTrackObject asks for the child to be recreated.
+func (r *ReconcileGitTrackObject) getUnchangedChild(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured, hash string) *unstructured.Unstructured {
+	status := gto.GetStatus()
+	state := farosv1alpha1.ChildState{Hash: status.ChildHash, ResourceVersion: status.ChildResourceVersion}
+	if len(status.ChildStates) > 0 {
+		state = status.ChildStates[childKey(child)]
+	}
+	if hash == "" || state.Hash != hash || state.ResourceVersion == "" {
+		return nil
+	}
+	if gittrackobjectutils.PendingRecreate(status.LastRecreateToken, gto) != "" {
+		return nil
+	}
+
+	cached := &unstructured.Unstructured{}
+	cached.SetKind(child.GetKind())
+	cached.SetAPIVersion(child.GetAPIVersion())
+	err := r.cache.Get(context.TODO(), types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}, cached)
+	if err != nil || cached.GetResourceVersion() != state.ResourceVersion {
+		return nil
+	}
+	return cached
+}
+
+// handleAdopt checks whether an existing child without an owner may be
+// adopted by the (Cluster)GitTrackObject
+func (r *ReconcileGitTrackObject) handleAdopt(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) (gittrackobjectutils.ConditionReason, error) {
+	adopt, err := gittrackobjectutils.ShouldAdopt(gto, child)
+	if err != nil {
+		return gittrackobjectutils.ChildAlreadyExists, fmt.Errorf("unable to get adopt annotation: %v", err)
+	}
+	if !adopt {
+		r.sendEvent(gto, corev1.EventTypeWarning, "AlreadyExists", "Child %s %s/%s already exists and is not marked for adoption", child.GetKind(), child.GetNamespace(), child.GetName())
+		return gittrackobjectutils.ChildAlreadyExists, fmt.Errorf("child already exists without an owner, set the faros.pusher.com/adopt annotation to adopt it")
+	}
 
-	return handlerResult{}
+	r.log.V(0).Info("Adopting child")
+	return "", nil
 }
 
-// getChildFromGitTrackObject reads the Data from a GitTrackObjectSpec and
-// converts it into and unstructured.unstructured runtime object
-func (r *ReconcileGitTrackObject) getChildFromGitTrackObject(gto farosv1alpha1.GitTrackObjectInterface) (*unstructured.Unstructured, gittrackobjectutils.ConditionReason, error) {
-	child, err := utils.YAMLToUnstructured(gto.GetSpec().Data)
+// getChildrenFromGitTrackObject reads the Data from a GitTrackObjectSpec and
+// converts each of its documents into an unstructured.Unstructured runtime
+// object
+func (r *ReconcileGitTrackObject) getChildrenFromGitTrackObject(gto farosv1alpha1.GitTrackObjectInterface) ([]*unstructured.Unstructured, gittrackobjectutils.ConditionReason, error) {
+	data, err := gittrackobjectutils.ReadData(r.apiReader, gto.GetSpec())
+	if err != nil {
+		r.sendEvent(gto, corev1.EventTypeWarning, "DecodeFailed", "Couldn't read data: %v", err)
+		return nil, gittrackobjectutils.ErrorUnmarshallingData, fmt.Errorf("unable to read data: %v", err)
+	}
+	children, err := utils.YAMLToUnstructuredSlice(data)
 	if err != nil {
 		r.sendEvent(gto, corev1.EventTypeWarning, "UnmarshalFailed", "Couldn't unmarshal object from JSON/YAML")
 		return nil, gittrackobjectutils.ErrorUnmarshallingData, fmt.Errorf("unable to unmarshal data: %v", err)
 	}
+	if len(children) == 0 {
+		return nil, gittrackobjectutils.ErrorUnmarshallingData, fmt.Errorf("unable to unmarshal data: no objects found")
+	}
 
-	// If the child has no name then we can't use it
-	if child.GetName() == "" {
-		return nil, gittrackobjectutils.ErrorGettingChild, fmt.Errorf("unable to get child: name cannot be empty")
+	seen := make(map[string]bool)
+	for _, child := range children {
+		// If the child has no name then we can't use it
+		if child.GetName() == "" {
+			return nil, gittrackobjectutils.ErrorGettingChild, fmt.Errorf("unable to get child: name cannot be empty")
+		}
+		// Each child can only be managed once
+		key := childKey(child)
+		if seen[key] {
+			return nil, gittrackobjectutils.ErrorGettingChild, fmt.Errorf("unable to get child: %s is defined more than once", key)
+		}
+		seen[key] = true
 	}
 
-	return &child, "", nil
+	return children, "", nil
+}
+
+// childKey identifies a child among the children of a (Cluster)GitTrackObject
+func childKey(child *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", child.GetKind(), strings.TrimLeft(fmt.Sprintf("%s/%s", child.GetNamespace(), child.GetName()), "/"))
+}
+
+// checkAllowed returns an error if the child's resource is not in the set of
+// allowed resources, all resources are allowed if the set is empty
+func (r *ReconcileGitTrackObject) checkAllowed(child *unstructured.Unstructured) error {
+	if len(r.allowedGVRs) == 0 {
+		return nil
+	}
+	gvr, _, err := utils.GetAPIResource(r.restMapper, child.GroupVersionKind())
+	if err != nil {
+		return fmt.Errorf("unable to get resource: %v", err)
+	}
+	if _, ok := farosflags.MatchResource(r.allowedGVRs, gvr); !ok {
+		return fmt.Errorf("resource `%s.%s/%s` is not an allowed resource", gvr.Resource, gvr.Group, gvr.Version)
+	}
+	return nil
 }
 
 // handleCreate takes an unstructured object sends it to the API to create it
@@ -133,7 +405,13 @@ func (r *ReconcileGitTrackObject) handleCreate(gto farosv1alpha1.GitTrackObjectI
 	// Log and send event that we are attempting to create the child resource
 	r.sendEvent(gto, corev1.EventTypeNormal, "CreateStarted", "Creating child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
 
-	err := r.applier.Apply(context.TODO(), &farosclient.ApplyOptions{}, child)
+	opts, err := r.applyOptions(gto, child)
+	if err != nil {
+		r.sendEvent(gto, corev1.EventTypeWarning, "CreateFailed", "Failed to create child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+		return gittrackobjectutils.ErrorCreatingChild, fmt.Errorf("unable to create child: %v", err)
+	}
+	err = r.apply(opts, child)
+	r.updateApplyOperationsMetric(child.GetKind(), createOperation, err)
 	if err != nil {
 		r.sendEvent(gto, corev1.EventTypeWarning, "CreateFailed", "Failed to create child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
 		return gittrackobjectutils.ErrorCreatingChild, fmt.Errorf("unable to create child: %v", err)
@@ -146,101 +424,335 @@ func (r *ReconcileGitTrackObject) handleCreate(gto farosv1alpha1.GitTrackObjectI
 	return "", nil
 }
 
-func (r *ReconcileGitTrackObject) handleUpdate(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured) (gittrackobjectutils.ConditionReason, error) {
+// handleUpdate updates the existing child according to its update strategy
+func (r *ReconcileGitTrackObject) handleUpdate(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured) handlerResult {
 	updateStrategy, err := gittrackobjectutils.GetUpdateStrategy(child)
 	if err != nil {
-		return gittrackobjectutils.ErrorUpdatingChild, fmt.Errorf("unable to get update strategy: %v", err)
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorUpdatingChild,
+			inSyncError:  fmt.Errorf("error updating child %s %s: unable to get update strategy: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+
+	opts, err := r.applyOptions(gto, child)
+	if err != nil {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorUpdatingChild,
+			inSyncError:  fmt.Errorf("error updating child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+
+	// Never modify the child in dry-run mode, whatever its update strategy
+	if farosflags.DryRun {
+		return r.handleDryRunUpdateStrategy(gto, found, child, opts)
+	}
+
+	// Leave the child alone while backing off from a conflict with another
+	// controller
+	if backoff := r.conflicts.backingOff(child); backoff > 0 {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ChildConflict,
+			inSyncError:  fmt.Errorf("not updating child %s %s: changed back by another controller, backing off for %s", gto.GetSpec().Kind, gto.GetSpec().Name, backoff.Round(time.Second)),
+			requeueAfter: backoff,
+		}
 	}
 
+	var update *farosv1alpha1.ChildUpdate
+	var reason gittrackobjectutils.ConditionReason
 	switch updateStrategy {
+	case gittrackobjectutils.DryRunUpdateStrategy:
+		return r.handleDryRunUpdateStrategy(gto, found, child, opts)
 	case gittrackobjectutils.RecreateUpdateStrategy:
-		return r.handleRecreateUpdateStrategy(gto, found, child)
+		update, reason, err = r.handleRecreateUpdateStrategy(gto, found, child, opts)
 	case gittrackobjectutils.NeverUpdateStrategy:
-		return r.handleNeverUpdateStrategy(gto, found)
+		update, reason, err = r.handleNeverUpdateStrategy(gto, found, opts)
 	default:
-		return r.handleDefaultUpdateStrategy(gto, found, child)
+		update, reason, err = r.handleDefaultUpdateStrategy(gto, found, child, opts)
+	}
+	if err != nil {
+		return handlerResult{
+			inSyncReason: reason,
+			inSyncError:  fmt.Errorf("error updating child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+	return handlerResult{lastUpdate: update}
+}
+
+// applyOptions returns the options the child is applied with, whether it is
+// created, updated or recreated. The child is updated rather than created if
+// it already exists in the API server, so the options of updates are always
+// given.
+func (r *ReconcileGitTrackObject) applyOptions(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) (*farosclient.ApplyOptions, error) {
+	ignorePaths, err := gittrackobjectutils.GetIgnorePaths(gto, child)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get ignore paths: %v", err)
+	}
+
+	listMergeStrategy, err := gittrackobjectutils.GetListMergeStrategy(gto, child)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get list merge strategy: %v", err)
 	}
+	mergeLists := listMergeStrategy == gittrackobjectutils.MergeListMergeStrategy
+
+	// Leave the replica count alone if it is being managed by an autoscaler
+	targeted, err := r.isTargetedByHPA(child)
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine whether child is autoscaled: %v", err)
+	}
+	if targeted {
+		r.log.V(1).Info("Child is targeted by a HorizontalPodAutoscaler, ignoring replicas")
+		ignorePaths = append(ignorePaths, replicasPath)
+	}
+	return &farosclient.ApplyOptions{
+		IgnorePaths: ignorePaths,
+		MergeLists:  &mergeLists,
+		LastApplied: r.lastAppliedChild(gto, child),
+	}, nil
 }
 
 // handleDefaultUpdateStrategy compares the existing and desired state of the
-// child resource and updates the object in-place if required
-func (r *ReconcileGitTrackObject) handleDefaultUpdateStrategy(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured) (gittrackobjectutils.ConditionReason, error) {
-	childUpdated, err := r.updateChild(found, child)
+// child resource and updates the object in-place if required, returning a
+// description of the update if one was made
+func (r *ReconcileGitTrackObject) handleDefaultUpdateStrategy(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) (*farosv1alpha1.ChildUpdate, gittrackobjectutils.ConditionReason, error) {
+	// Keep a copy of the desired state as the child is modified by the update
+	desired := child.DeepCopy()
+	childUpdated, err := r.updateChild(found, child, opts)
+	r.updateApplyOperationsMetric(child.GetKind(), operationFor(updateOperation, childUpdated, err), err)
 	if err != nil {
 		r.sendEvent(gto, corev1.EventTypeWarning, "UpdateFailed", "Unable to update child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
-		return gittrackobjectutils.ErrorUpdatingChild, fmt.Errorf("unable to update child: %v", err)
+		return nil, gittrackobjectutils.ErrorUpdatingChild, fmt.Errorf("unable to update child: %v", err)
 	}
 	if !childUpdated {
-		return "", nil
+		return nil, "", nil
 	}
 
 	// Update was successful
-	r.sendEvent(gto, corev1.EventTypeNormal, "UpdateSuccessful", "Successfully updated child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
-	r.log.V(0).Info("Child updated")
-	return "", nil
+	paths := r.changedPaths(found, desired, opts)
+	r.sendEvent(gto, corev1.EventTypeNormal, "UpdateSuccessful", "%s", updateMessage(desired, paths))
+	r.log.V(0).Info("Child updated", "paths", paths)
+	r.checkDrift(gto, found, desired, opts)
+	return newChildUpdate(paths), "", nil
+}
+
+// checkDrift reports any out-of-band changes to the child that were reset by
+// an update
+func (r *ReconcileGitTrackObject) checkDrift(gto farosv1alpha1.GitTrackObjectInterface, found, desired *unstructured.Unstructured, opts *farosclient.ApplyOptions) {
+	paths, err := r.detectDrift(found, desired, opts)
+	if err != nil {
+		r.log.Error(err, "unable to detect drift")
+		return
+	}
+	r.handleDrift(gto, desired, paths)
 }
 
 // handleNeverUpdateStrategy compares the existing object to the existing object
 // with the correct owner references applied and updates if necessary
-func (r *ReconcileGitTrackObject) handleNeverUpdateStrategy(gto farosv1alpha1.GitTrackObjectInterface, found *unstructured.Unstructured) (gittrackobjectutils.ConditionReason, error) {
+func (r *ReconcileGitTrackObject) handleNeverUpdateStrategy(gto farosv1alpha1.GitTrackObjectInterface, found *unstructured.Unstructured, opts *farosclient.ApplyOptions) (*farosv1alpha1.ChildUpdate, gittrackobjectutils.ConditionReason, error) {
 	r.log.V(1).Info("Child has `never` update strategy")
 	child := found.DeepCopy()
 	err := controllerutil.SetControllerReference(gto, child, r.scheme)
 	if err != nil {
-		return gittrackobjectutils.ErrorAddingOwnerReference, fmt.Errorf("unable to add owner reference: %v", err)
+		return nil, gittrackobjectutils.ErrorAddingOwnerReference, fmt.Errorf("unable to add owner reference: %v", err)
+	}
+	return r.handleDefaultUpdateStrategy(gto, found, child, opts)
+}
+
+// handleDryRunCreate reports the child that would be created without creating
+// it, for use when the controller is in dry-run mode
+func (r *ReconcileGitTrackObject) handleDryRunCreate(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) handlerResult {
+	// Every field of the child would be set by creating it. The identifying
+	// fields are set as patches may not change them.
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(child.GroupVersionKind())
+	current.SetNamespace(child.GetNamespace())
+	current.SetName(child.GetName())
+	result, err := r.applier.Diff(context.TODO(), &farosclient.ApplyOptions{}, child, current)
+	if err != nil {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorCreatingChild,
+			inSyncError:  fmt.Errorf("error computing diff for child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+
+	// Only send an event when the diff changes to avoid flooding the event stream
+	diff := renderDryRunDiff(result.Changes)
+	if gto.GetStatus().DryRunDiff != diff {
+		r.sendEvent(gto, corev1.EventTypeNormal, "DryRunCreate", "Child %s %s/%s would be created", child.GetKind(), child.GetNamespace(), child.GetName())
+		r.log.V(0).Info("Child would be created")
+	}
+	return handlerResult{dryRunDiff: diff}
+}
+
+// handleDryRunUpdateStrategy computes the patch required to bring the child
+// to its desired state and reports the fields it changes without ever
+// modifying the child
+func (r *ReconcileGitTrackObject) handleDryRunUpdateStrategy(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) handlerResult {
+	r.log.V(1).Info("Child has `dry-run` update strategy")
+	result, err := r.applier.Diff(context.TODO(), opts, child, found)
+	if err != nil {
+		r.sendEvent(gto, corev1.EventTypeWarning, "DryRunFailed", "Unable to compute diff for child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorUpdatingChild,
+			inSyncError:  fmt.Errorf("error computing diff for child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
 	}
-	return r.handleDefaultUpdateStrategy(gto, found, child)
+	if string(result.Patch) == "{}" || len(result.Changes) == 0 {
+		return handlerResult{}
+	}
+
+	// Only send an event when the diff changes to avoid flooding the event stream
+	diff := renderDryRunDiff(result.Changes)
+	if gto.GetStatus().DryRunDiff != diff {
+		r.sendEvent(gto, corev1.EventTypeNormal, "DryRunDiff", "Child %s %s/%s differs from desired state, would change %s", child.GetKind(), child.GetNamespace(), child.GetName(), summarizePaths(result.Paths, maxEventPaths))
+		r.log.V(0).Info("Child differs from desired state", "diff", diff)
+	}
+	return handlerResult{dryRunDiff: diff}
 }
 
 // handleRecreateUpdateStrategy compares the existing and desired state of the
 // resources and then deletes and recreates the child object if an update is
-// required
-func (r *ReconcileGitTrackObject) handleRecreateUpdateStrategy(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured) (gittrackobjectutils.ConditionReason, error) {
+// required, returning a description of the update if one was made
+func (r *ReconcileGitTrackObject) handleRecreateUpdateStrategy(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) (*farosv1alpha1.ChildUpdate, gittrackobjectutils.ConditionReason, error) {
 	r.log.V(1).Info("Child has `recreate` update strategy")
-	childUpdated, err := r.recreateChild(found, child)
+	propagationPolicy, err := gittrackobjectutils.DeletionPropagationPolicy(gto, child)
+	if err != nil {
+		return nil, gittrackobjectutils.ErrorUpdatingChild, fmt.Errorf("unable to get deletion propagation policy: %v", err)
+	}
+	recreateOpts := *opts
+	recreateOpts.PropagationPolicy = propagationPolicy
+
+	desired := child.DeepCopy()
+	childUpdated, err := r.recreateChild(found, child, &recreateOpts)
+	r.updateApplyOperationsMetric(child.GetKind(), operationFor(recreateOperation, childUpdated, err), err)
 	if err != nil {
 		r.sendEvent(gto, corev1.EventTypeWarning, "UpdateFailed", "Unable to update child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
-		return gittrackobjectutils.ErrorUpdatingChild, fmt.Errorf("unable to update child: %v", err)
+		return nil, gittrackobjectutils.ErrorUpdatingChild, fmt.Errorf("unable to update child: %v", err)
 	}
 	if !childUpdated {
-		return "", nil
+		return nil, "", nil
 	}
 
 	// Update was successful
-	r.sendEvent(gto, corev1.EventTypeNormal, "UpdateSuccessful", "Successfully updated child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
-	r.log.V(0).Info("Child updated")
-	return "", nil
+	paths := r.changedPaths(found, desired, opts)
+	r.sendEvent(gto, corev1.EventTypeNormal, "UpdateSuccessful", "%s", updateMessage(desired, paths))
+	r.log.V(0).Info("Child updated", "paths", paths)
+	r.checkDrift(gto, found, desired, opts)
+	return newChildUpdate(paths), "", nil
 }
 
 // recreateChild first deletes and then creates a child resource for a (Cluster)GitTrackObject
-func (r *ReconcileGitTrackObject) recreateChild(found, child *unstructured.Unstructured) (bool, error) {
+func (r *ReconcileGitTrackObject) recreateChild(found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) (bool, error) {
 	// Recreating the child does not make sense with dry run (dry run delete does
 	// not mean we can dry run create) and so do not attempt dry run here.
-	return r.applyChild(found, child, true)
+	force := true
+	recreateOpts := *opts
+	recreateOpts.ForceDeletion = &force
+	return r.applyChild(found, child, &recreateOpts)
+}
+
+// handleForceRecreate deletes the child and creates it again from its desired
+// state, as requested by the `faros.pusher.com/recreate` annotation
+func (r *ReconcileGitTrackObject) handleForceRecreate(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured, hash, token string) handlerResult {
+	r.sendEvent(gto, corev1.EventTypeNormal, "RecreateStarted", "Recreating child %s %s/%s as requested by %s annotation %q", child.GetKind(), child.GetNamespace(), child.GetName(), gittrackobjectutils.RecreateAnnotation, token)
+
+	opts, err := r.applyOptions(gto, child)
+	if err == nil {
+		err = r.forceRecreateChild(gto, found, child, opts)
+	}
+	r.updateApplyOperationsMetric(child.GetKind(), recreateOperation, err)
+	if err != nil {
+		r.sendEvent(gto, corev1.EventTypeWarning, "RecreateFailed", "Failed to recreate child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorUpdatingChild,
+			inSyncError:  fmt.Errorf("error recreating child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+
+	r.log.V(0).Info("Child recreated", "token", token)
+	r.sendEvent(gto, corev1.EventTypeNormal, "RecreateSuccessful", "Successfully recreated child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+	health, detail := gittrackobjectutils.GetHealth(child)
+	return handlerResult{
+		health:               health,
+		healthDetail:         detail,
+		childHash:            hash,
+		childResourceVersion: child.GetResourceVersion(),
+		recreateToken:        token,
+	}
+}
+
+// deleteChild deletes the child with the propagation policy set by its
+// annotation or by the (Cluster)GitTrackObject, if either is set
+func deleteChild(c client.Client, gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured) error {
+	propagationPolicy, err := gittrackobjectutils.DeletionPropagationPolicy(gto, child)
+	if err != nil {
+		return fmt.Errorf("unable to get deletion propagation policy: %v", err)
+	}
+	if propagationPolicy == nil {
+		return c.Delete(context.TODO(), found)
+	}
+	return c.Delete(context.TODO(), found, client.PropagationPolicy(*propagationPolicy))
+}
+
+// forceRecreateChild deletes the child, waits for it to be removed and then
+// creates it again. Unlike recreateChild, the child is recreated even if it
+// could be updated in place.
+func (r *ReconcileGitTrackObject) forceRecreateChild(gto farosv1alpha1.GitTrackObjectInterface, found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) error {
+	propagationPolicy, err := gittrackobjectutils.DeletionPropagationPolicy(gto, child)
+	if err != nil {
+		return fmt.Errorf("unable to get deletion propagation policy: %v", err)
+	}
+	if propagationPolicy == nil {
+		foreground := metav1.DeletePropagationForeground
+		propagationPolicy = &foreground
+	}
+	err = r.Delete(context.TODO(), found, client.PropagationPolicy(*propagationPolicy))
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete child: %v", err)
+	}
+
+	key := types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}
+	err = wait.PollImmediate(time.Second, recreateTimeout, func() (bool, error) {
+		err := r.Get(context.TODO(), key, found.DeepCopy())
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("child was not deleted: %v", err)
+	}
+
+	err = r.apply(opts, child)
+	if err != nil {
+		return fmt.Errorf("unable to create child: %v", err)
+	}
+	return nil
 }
 
 // updateChild updates the given child resource of a (Cluster)GitTrackObject
-func (r *ReconcileGitTrackObject) updateChild(found, child *unstructured.Unstructured) (bool, error) {
+func (r *ReconcileGitTrackObject) updateChild(found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) (bool, error) {
 	// HasSupport returns an error if dry run not supported
 	if farosflags.ServerDryRun {
 		if err := r.dryRunVerifier.HasSupport(child.GroupVersionKind()); err == nil {
 			r.log.V(2).Info("Updating child with dry-run support")
-			return r.applyChildWithDryRun(found, child, false)
+			return r.applyChildWithDryRun(found, child, opts)
 		}
 	}
 	// Dry run not supported so apply without DryRun
 	r.log.V(2).Info("Updating child without dry-run support")
-	return r.applyChild(found, child, false)
+	return r.applyChild(found, child, opts)
 }
 
 // applyChildWithDryRun first applies the child with DryRun and then updates the resource if there is change to persist
-func (r *ReconcileGitTrackObject) applyChildWithDryRun(found, child *unstructured.Unstructured, force bool) (bool, error) {
+func (r *ReconcileGitTrackObject) applyChildWithDryRun(found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) (bool, error) {
 	// Take a copy of the original child so that if the dry run shows a diff,
 	// we Apply the original state of the child object
 	originalChild := child.DeepCopy()
 
 	dryRunTrue := true
-	err := r.applier.Apply(context.TODO(), &farosclient.ApplyOptions{ForceDeletion: &force, ServerDryRun: &dryRunTrue}, child)
+	dryRunOpts := *opts
+	dryRunOpts.ServerDryRun = &dryRunTrue
+	err := r.apply(&dryRunOpts, child)
 	if err != nil {
 		return false, fmt.Errorf("unable to update child resource: %v", err)
 	}
@@ -251,7 +763,7 @@ func (r *ReconcileGitTrackObject) applyChildWithDryRun(found, child *unstructure
 	}
 
 	// The DryRun showed a change is required so now update without DryRun
-	err = r.applier.Apply(context.TODO(), &farosclient.ApplyOptions{ForceDeletion: &force}, originalChild)
+	err = r.apply(opts, originalChild)
 	if err != nil {
 		return false, fmt.Errorf("unable to update child resource: %v", err)
 	}
@@ -259,9 +771,9 @@ func (r *ReconcileGitTrackObject) applyChildWithDryRun(found, child *unstructure
 }
 
 // applyChild uses the applier to update the child
-func (r *ReconcileGitTrackObject) applyChild(found, child *unstructured.Unstructured, force bool) (bool, error) {
+func (r *ReconcileGitTrackObject) applyChild(found, child *unstructured.Unstructured, opts *farosclient.ApplyOptions) (bool, error) {
 	originalResourceVersion := found.GetResourceVersion()
-	err := r.applier.Apply(context.TODO(), &farosclient.ApplyOptions{ForceDeletion: &force}, child)
+	err := r.apply(opts, child)
 	if err != nil {
 		return false, fmt.Errorf("unable to update child resource: %v", err)
 	}
@@ -273,12 +785,21 @@ func (r *ReconcileGitTrackObject) applyChild(found, child *unstructured.Unstruct
 	return true, nil
 }
 
+// apply waits for the apply rate limiter, if configured, and then applies
+// the child
+func (r *ReconcileGitTrackObject) apply(opts *farosclient.ApplyOptions, child *unstructured.Unstructured) error {
+	if r.applyLimiter != nil {
+		r.applyLimiter.Accept()
+	}
+	return r.applier.Apply(context.TODO(), opts, child)
+}
+
 // sendEvent wraps event recording to make sure the namespace is set correctly
 // on all events
 func (r *ReconcileGitTrackObject) sendEvent(gto farosv1alpha1.GitTrackObjectInterface, eventType, reason, messageFmt string, args ...interface{}) {
 	instance := gto.DeepCopyInterface()
-	if instance.GetNamespace() == "" {
-		instance.SetNamespace(farosflags.Namespace)
+	if instance.GetNamespace() == "" && len(farosflags.Namespaces) > 0 {
+		instance.SetNamespace(farosflags.Namespaces[0])
 	}
 
 	r.recorder.Eventf(instance, eventType, reason, messageFmt, args...)
@@ -17,6 +17,7 @@ limitations under the License.
 package gittrackobject
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -26,11 +27,16 @@ import (
 	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
 	farosflags "github.com/pusher/faros/pkg/flags"
 	farosclient "github.com/pusher/faros/pkg/utils/client"
+	"github.com/pusher/faros/test/testenv"
 	testutils "github.com/pusher/faros/test/utils"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -53,10 +59,7 @@ var _ = Describe("Handler Suite", func() {
 		// channel when it is finished.
 		var err error
 		cfg.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
-		mgr, err = manager.New(cfg, manager.Options{
-			Namespace:          farosflags.Namespace,
-			MetricsBindAddress: "0", // Disable serving metrics while testing
-		})
+		mgr, err = t.NewManager(farosflags.Namespaces[0])
 		Expect(err).NotTo(HaveOccurred())
 
 		applier, err := farosclient.NewApplier(cfg, farosclient.Options{})
@@ -71,19 +74,22 @@ var _ = Describe("Handler Suite", func() {
 		r = recFn.(*ReconcileGitTrackObject)
 
 		stopInformers = r.StopChan()
-		stop = StartTestManager(mgr)
+		stop = testenv.StartManager(mgr)
 	})
 
 	AfterEach(func() {
 		// Stop Controller and informers before cleaning up
 		close(stop)
 		close(stopInformers)
+		// Remove finalizers as the controller is no longer running to handle them
+		testutils.RemoveFinalizers(cfg, &farosv1alpha1.GitTrackObjectList{}, &farosv1alpha1.ClusterGitTrackObjectList{})
 		// Clean up all resources as GC is disabled in the control plane
 		testutils.DeleteAll(cfg, timeout,
 			&farosv1alpha1.GitTrackList{},
 			&farosv1alpha1.GitTrackObjectList{},
 			&farosv1alpha1.ClusterGitTrackObjectList{},
 			&appsv1.DeploymentList{},
+			&autoscalingv1.HorizontalPodAutoscalerList{},
 			&rbacv1.ClusterRoleBindingList{},
 			&corev1.EventList{},
 		)
@@ -134,24 +140,377 @@ var _ = Describe("Handler Suite", func() {
 				})
 			})
 
+			Context("when the controller is in dry-run mode", func() {
+				BeforeEach(func() {
+					farosflags.DryRun = true
+				})
+
+				AfterEach(func() {
+					farosflags.DryRun = false
+				})
+
+				Context("and the child does not exist", func() {
+					BeforeEach(func() {
+						result = r.handleGitTrackObject(gto)
+						Expect(result.inSyncError).To(BeNil())
+					})
+
+					It("should not create the child resource", func() {
+						m.Consistently(&appsv1.DeploymentList{}, consistentlyTimeout).Should(testutils.WithItems(BeEmpty()))
+					})
+
+					It("should report the child that would be created", func() {
+						Expect(result.dryRunDiff).To(ContainSubstring(child.Name))
+					})
+				})
+
+				Context("and the child differs from its desired state", func() {
+					var originalVersion string
+
+					BeforeEach(func() {
+						child.Spec.Template.SetAnnotations(map[string]string{"updated": "annotations"})
+						child.SetOwnerReferences([]metav1.OwnerReference{testutils.GetGitTrackObjectOwnerRef(gto)})
+						m.Apply(child, &farosclient.ApplyOptions{}).Should(Succeed())
+						m.Get(child, timeout).Should(Succeed())
+						originalVersion = child.GetResourceVersion()
+
+						result = r.handleGitTrackObject(gto)
+						Expect(result.inSyncError).To(BeNil())
+					})
+
+					It("should not update the child", func() {
+						m.Consistently(child, consistentlyTimeout).Should(testutils.WithResourceVersion(Equal(originalVersion)))
+					})
+
+					It("should report the diff", func() {
+						Expect(result.dryRunDiff).NotTo(BeEmpty())
+					})
+				})
+			})
+
 			Context("when the child already exists", func() {
 				BeforeEach(func() {
 					// Create and fetch the instance to make sure caches are synced
 					m.Create(child).Should(Succeed())
 					m.Get(child, timeout).Should(Succeed())
+				})
 
+				Context("and the GitTrackObject has the adopt annotation", func() {
+					BeforeEach(func() {
+						gto.SetAnnotations(map[string]string{"faros.pusher.com/adopt": "true"})
+						result = r.handleGitTrackObject(gto)
+						Expect(result.inSyncError).To(BeNil())
+					})
+
+					It("should add an owner reference to the child", func() {
+						m.Eventually(child, timeout).
+							Should(testutils.WithOwnerReferences(ContainElement(testutils.GetGitTrackObjectOwnerRef(gto))))
+					})
+
+					It("should add a last applied annotation to the child", func() {
+						m.Eventually(child, timeout).
+							Should(testutils.WithAnnotations(HaveKey(farosclient.LastAppliedAnnotation)))
+					})
+				})
+
+				Context("and the GitTrackObject does not have the adopt annotation", func() {
+					BeforeEach(func() {
+						result = r.handleGitTrackObject(gto)
+					})
+
+					It("should return a ChildAlreadyExists error", func() {
+						Expect(result.inSyncError).NotTo(BeNil())
+						Expect(result.inSyncReason).To(Equal(gittrackobjectutils.ChildAlreadyExists))
+					})
+
+					It("should not add an owner reference to the child", func() {
+						m.Consistently(child, consistentlyTimeout).
+							Should(testutils.WithOwnerReferences(BeEmpty()))
+					})
+				})
+			})
+
+			Context("when only some resources are allowed", func() {
+				It("should manage a child of an allowed resource", func() {
+					r.allowedGVRs = map[schema.GroupVersionResource]interface{}{
+						{Group: "apps", Version: "v1", Resource: "deployments"}: nil,
+					}
 					result = r.handleGitTrackObject(gto)
 					Expect(result.inSyncError).To(BeNil())
+					m.Get(child, timeout).Should(Succeed())
 				})
 
-				It("should add an owner reference to the child", func() {
+				It("should return a ChildNotAllowed error for other resources", func() {
+					r.allowedGVRs = map[schema.GroupVersionResource]interface{}{
+						{Group: "", Version: "v1", Resource: "services"}: nil,
+					}
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncReason).To(Equal(gittrackobjectutils.ChildNotAllowed))
+					Expect(result.inSyncError).To(MatchError("unable to manage child Deployment example: resource `deployments.apps/v1` is not an allowed resource"))
+				})
+			})
+
+			Context("when the child is targeted by a HorizontalPodAutoscaler", func() {
+				BeforeEach(func() {
+					replicas := int32(1)
+					child.Spec.Replicas = &replicas
+					child.SetOwnerReferences([]metav1.OwnerReference{testutils.GetGitTrackObjectOwnerRef(gto)})
+					m.Apply(child, &farosclient.ApplyOptions{}).Should(Succeed())
+					m.Get(child, timeout).Should(Succeed())
+
+					hpa := &autoscalingv1.HorizontalPodAutoscaler{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "example",
+							Namespace: child.GetNamespace(),
+						},
+						Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+							ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+								APIVersion: "apps/v1",
+								Kind:       "Deployment",
+								Name:       child.GetName(),
+							},
+							MaxReplicas: 10,
+						},
+					}
+					m.Create(hpa).Should(Succeed())
+					m.Get(hpa, timeout).Should(Succeed())
+
+					// Scale the child as the autoscaler would
+					scaled := int32(5)
+					child.Spec.Replicas = &scaled
+					m.Update(child, timeout).Should(Succeed())
+
+					specData := testutils.ExampleDeployment.DeepCopy()
+					desired := int32(2)
+					specData.Spec.Replicas = &desired
+					Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, specData)).To(Succeed())
+					m.Update(gto, timeout).Should(Succeed())
+
+					Eventually(func() error {
+						return r.handleGitTrackObject(gto).inSyncError
+					}, timeout).Should(Succeed())
+				})
+
+				It("should not reset the replicas", func() {
+					m.Consistently(child, consistentlyTimeout).Should(WithTransform(func(d *appsv1.Deployment) int32 {
+						return *d.Spec.Replicas
+					}, Equal(int32(5))))
+				})
+			})
+
+			Context("when the child has the orphan-on-delete annotation", func() {
+				BeforeEach(func() {
+					specData := testutils.ExampleDeployment.DeepCopy()
+					specData.SetAnnotations(map[string]string{"faros.pusher.com/orphan-on-delete": "true"})
+					Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, specData)).To(Succeed())
+					m.Update(gto, timeout).Should(Succeed())
+
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncError).To(BeNil())
+				})
+
+				It("should add the orphan finalizer to the GitTrackObject", func() {
+					m.Eventually(gto, timeout).Should(testutils.WithFinalizers(ContainElement(orphanFinalizer)))
+				})
+
+				Context("and the GitTrackObject is deleted", func() {
+					BeforeEach(func() {
+						m.Get(child, timeout).Should(Succeed())
+						m.Delete(gto).Should(Succeed())
+						m.Get(gto, timeout).Should(Succeed())
+						Expect(r.handleDeletion(gto)).To(Succeed())
+					})
+
+					It("should remove the owner reference from the child", func() {
+						m.Eventually(child, timeout).Should(testutils.WithOwnerReferences(BeEmpty()))
+					})
+				})
+			})
+
+			Context("when the data holds multiple documents", func() {
+				var second *appsv1.Deployment
+
+				BeforeEach(func() {
+					second = testutils.ExampleDeployment.DeepCopy()
+					second.SetName("example-second")
+					second.SetAnnotations(map[string]string{"faros.pusher.com/orphan-on-delete": "true"})
+					Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, second)).To(Succeed())
+					secondData := gto.Spec.Data
+					Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, child)).To(Succeed())
+					gto.Spec.Data = []byte(fmt.Sprintf("%s\n---\n%s", gto.Spec.Data, secondData))
+					m.Update(gto, timeout).Should(Succeed())
+
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncError).To(BeNil())
+				})
+
+				It("should create every child", func() {
+					m.Get(child, timeout).Should(Succeed())
+					m.Get(second, timeout).Should(Succeed())
+				})
+
+				It("should add an owner reference to every child", func() {
 					m.Eventually(child, timeout).
 						Should(testutils.WithOwnerReferences(ContainElement(testutils.GetGitTrackObjectOwnerRef(gto))))
+					m.Eventually(second, timeout).
+						Should(testutils.WithOwnerReferences(ContainElement(testutils.GetGitTrackObjectOwnerRef(gto))))
 				})
 
-				It("should add a last applied annotation to the child", func() {
-					m.Eventually(child, timeout).
-						Should(testutils.WithAnnotations(HaveKey(farosclient.LastAppliedAnnotation)))
+				It("should add the orphan finalizer when any child asks to be orphaned", func() {
+					m.Eventually(gto, timeout).Should(testutils.WithFinalizers(ContainElement(orphanFinalizer)))
+				})
+
+				It("should record the synced state of every child", func() {
+					Expect(result.childHash).To(BeEmpty())
+					Expect(result.childStates).To(HaveLen(2))
+					m.Get(child, timeout).Should(Succeed())
+					m.Get(second, timeout).Should(Succeed())
+					Expect(result.childStates[fmt.Sprintf("Deployment %s/%s", child.GetNamespace(), child.GetName())].ResourceVersion).To(Equal(child.GetResourceVersion()))
+					Expect(result.childStates[fmt.Sprintf("Deployment %s/%s", second.GetNamespace(), second.GetName())].ResourceVersion).To(Equal(second.GetResourceVersion()))
+				})
+
+				It("should skip every unchanged child on the next sync", func() {
+					gto.Status.ChildStates = result.childStates
+					children, _, err := r.getChildrenFromGitTrackObject(gto)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(children).To(HaveLen(2))
+					for _, c := range children {
+						state := result.childStates[childKey(c)]
+						Eventually(func() interface{} {
+							return r.getUnchangedChild(gto, c, state.Hash)
+						}, timeout).ShouldNot(BeNil())
+					}
+				})
+
+				Context("and the GitTrackObject is deleted", func() {
+					BeforeEach(func() {
+						m.Get(child, timeout).Should(Succeed())
+						m.Get(second, timeout).Should(Succeed())
+						m.Delete(gto).Should(Succeed())
+						m.Get(gto, timeout).Should(Succeed())
+						Expect(r.handleDeletion(gto)).To(Succeed())
+					})
+
+					It("should remove the owner reference from every child", func() {
+						m.Eventually(child, timeout).Should(testutils.WithOwnerReferences(BeEmpty()))
+						m.Eventually(second, timeout).Should(testutils.WithOwnerReferences(BeEmpty()))
+					})
+				})
+			})
+
+			Context("when the data is compressed", func() {
+				BeforeEach(func() {
+					spec := gto.Spec
+					Expect(gittrackobjectutils.CompressData(&spec, 1)).To(Succeed())
+					gto.Spec = spec
+					m.Update(gto, timeout).Should(Succeed())
+
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncError).To(BeNil())
+				})
+
+				It("should create the child resource", func() {
+					m.Get(child, timeout).Should(Succeed())
+				})
+			})
+
+			Context("when the data is stored in ConfigMaps", func() {
+				var cm *corev1.ConfigMap
+
+				BeforeEach(func() {
+					cm = &corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{Name: "faros-data-example-0", Namespace: gto.Namespace},
+						BinaryData: map[string][]byte{gittrackobjectutils.DataConfigMapKey: gto.Spec.Data},
+					}
+					m.Create(cm).Should(Succeed())
+
+					gto.Spec.DataFrom = &farosv1alpha1.DataReference{
+						Namespace:  cm.Namespace,
+						ConfigMaps: []string{cm.Name},
+						Hash:       gittrackobjectutils.DataHash(gto.Spec.Data),
+					}
+					gto.Spec.Data = []byte{}
+					m.Update(gto, timeout).Should(Succeed())
+				})
+
+				AfterEach(func() {
+					m.Delete(cm).Should(Succeed())
+				})
+
+				It("should create the child resource", func() {
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncError).To(BeNil())
+					m.Get(child, timeout).Should(Succeed())
+				})
+
+				It("should return an error when the ConfigMaps do not match the hash", func() {
+					gto.Spec.DataFrom.Hash = gittrackobjectutils.DataHash([]byte("other"))
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncReason).To(Equal(gittrackobjectutils.ErrorUnmarshallingData))
+					Expect(result.inSyncError).To(MatchError(ContainSubstring("data in ConfigMaps has hash")))
+				})
+			})
+
+			Context("when a document is removed from the data", func() {
+				var second *appsv1.Deployment
+
+				BeforeEach(func() {
+					second = testutils.ExampleDeployment.DeepCopy()
+					second.SetName("example-second")
+					Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, second)).To(Succeed())
+					secondData := gto.Spec.Data
+					Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, child)).To(Succeed())
+					childData := gto.Spec.Data
+					gto.Spec.Data = []byte(fmt.Sprintf("%s\n---\n%s", childData, secondData))
+					m.Update(gto, timeout).Should(Succeed())
+
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncError).To(BeNil())
+					m.Get(second, timeout).Should(Succeed())
+
+					// Record the children applied as the status update would
+					gto.Status.Children = result.children
+					gto.Spec.Data = childData
+					m.Update(gto, timeout).Should(Succeed())
+					gto.Status.Children = result.children
+
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncError).To(BeNil())
+				})
+
+				It("should delete the child of the removed document", func() {
+					m.Get(second, timeout).ShouldNot(Succeed())
+				})
+
+				It("should keep the remaining child", func() {
+					m.Get(child, timeout).Should(Succeed())
+				})
+
+				It("should only record the remaining child", func() {
+					Expect(result.children).To(ConsistOf(farosv1alpha1.ChildReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Namespace:  child.GetNamespace(),
+						Name:       child.GetName(),
+					}))
+				})
+			})
+
+			Context("when a document is defined more than once", func() {
+				BeforeEach(func() {
+					gto.Spec.Data = []byte(fmt.Sprintf("%s\n---\n%s", gto.Spec.Data, gto.Spec.Data))
+					m.Update(gto, timeout).Should(Succeed())
+
+					result = r.handleGitTrackObject(gto)
+				})
+
+				It("should return an error", func() {
+					Expect(result.inSyncReason).To(Equal(gittrackobjectutils.ErrorGettingChild))
+					Expect(result.inSyncError).To(MatchError(ContainSubstring("Deployment %s/%s is defined more than once", child.GetNamespace(), child.GetName())))
+				})
+
+				It("should not create the child", func() {
+					m.Get(child, timeout).ShouldNot(Succeed())
 				})
 			})
 
@@ -211,6 +570,49 @@ var _ = Describe("Handler Suite", func() {
 					})
 				})
 
+				Context("never with an ignored field that drifted", func() {
+					var applier *recordingApplier
+
+					BeforeEach(func() {
+						// Scale the child out of band
+						m.Get(child, timeout).Should(Succeed())
+						replicas := int32(3)
+						child.Spec.Replicas = &replicas
+						m.Update(child).Should(Succeed())
+
+						specData := testutils.ExampleDeployment.DeepCopy()
+						annotations := map[string]string{
+							"faros.pusher.com/update-strategy": string(gittrackobjectutils.NeverUpdateStrategy),
+							"faros.pusher.com/ignore-paths":    "/spec/replicas",
+						}
+						specData.SetAnnotations(annotations)
+						Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, specData)).To(Succeed())
+
+						applier = &recordingApplier{Client: r.applier}
+						r.applier = applier
+						m.Update(gto, timeout).Should(Succeed())
+						result = r.handleGitTrackObject(gto)
+						Expect(result.inSyncError).To(BeNil())
+					})
+
+					It("should apply the child with the ignored paths", func() {
+						Expect(applier.opts).NotTo(BeEmpty())
+						for _, opts := range applier.opts {
+							Expect(opts.IgnorePaths).To(ContainElement("/spec/replicas"))
+						}
+					})
+
+					It("should keep the drifted field", func() {
+						m.Consistently(child, consistentlyTimeout).Should(WithTransform(func(d *appsv1.Deployment) int32 {
+							return *d.Spec.Replicas
+						}, Equal(int32(3))))
+					})
+
+					It("should not replace the child", func() {
+						m.Consistently(child, consistentlyTimeout).Should(testutils.WithUID(Equal(originalUID)))
+					})
+				})
+
 				Context("recreate", func() {
 					Context("without conflicts", func() {
 						BeforeEach(func() {
@@ -233,6 +635,27 @@ var _ = Describe("Handler Suite", func() {
 						})
 					})
 				})
+
+				Context("dry-run", func() {
+					BeforeEach(func() {
+						specData := testutils.ExampleDeployment.DeepCopy()
+						annotations := map[string]string{"faros.pusher.com/update-strategy": string(gittrackobjectutils.DryRunUpdateStrategy)}
+						specData.SetAnnotations(annotations)
+						Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, specData)).To(Succeed())
+
+						m.Update(gto, timeout).Should(Succeed())
+						result = r.handleGitTrackObject(gto)
+						Expect(result.inSyncError).To(BeNil())
+					})
+
+					It("should not update the child", func() {
+						m.Consistently(child, consistentlyTimeout).Should(testutils.WithResourceVersion(Equal(originalVersion)))
+					})
+
+					It("should report the diff", func() {
+						Expect(result.dryRunDiff).To(ContainSubstring("updated"))
+					})
+				})
 			})
 		})
 
@@ -278,6 +701,46 @@ var _ = Describe("Handler Suite", func() {
 					m.Eventually(child, timeout).
 						Should(testutils.WithAnnotations(HaveKey(farosclient.LastAppliedAnnotation)))
 				})
+
+				It("should add the cleanup finalizer to the ClusterGitTrackObject", func() {
+					m.Eventually(gto, timeout).Should(testutils.WithFinalizers(ContainElement(cleanupFinalizer)))
+				})
+
+				Context("and the ClusterGitTrackObject is deleted", func() {
+					BeforeEach(func() {
+						m.Get(child, timeout).Should(Succeed())
+						m.Delete(gto).Should(Succeed())
+						m.Get(gto, timeout).Should(Succeed())
+						Expect(r.handleDeletion(gto)).To(Succeed())
+					})
+
+					It("should delete the child", func() {
+						m.Get(child, timeout).ShouldNot(Succeed())
+					})
+
+					It("should remove the cleanup finalizer", func() {
+						m.Eventually(gto, timeout).Should(testutils.WithFinalizers(Not(ContainElement(cleanupFinalizer))))
+					})
+				})
+			})
+
+			Context("when the child has the orphan-on-delete annotation", func() {
+				BeforeEach(func() {
+					specData := testutils.ExampleClusterRoleBinding.DeepCopy()
+					specData.SetAnnotations(map[string]string{"faros.pusher.com/orphan-on-delete": "true"})
+					Expect(testutils.SetGitTrackObjectInterfaceSpec(gto, specData)).To(Succeed())
+					m.Update(gto, timeout).Should(Succeed())
+
+					result = r.handleGitTrackObject(gto)
+					Expect(result.inSyncError).To(BeNil())
+				})
+
+				It("should add the orphan finalizer instead of the cleanup finalizer", func() {
+					m.Eventually(gto, timeout).Should(testutils.WithFinalizers(SatisfyAll(
+						ContainElement(orphanFinalizer),
+						Not(ContainElement(cleanupFinalizer)),
+					)))
+				})
 			})
 
 			Context("when the child already exists", func() {
@@ -285,19 +748,40 @@ var _ = Describe("Handler Suite", func() {
 					// Create and fetch the instance to make sure caches are synced
 					m.Create(child).Should(Succeed())
 					m.Get(child, timeout).Should(Succeed())
-
-					result = r.handleGitTrackObject(gto)
-					Expect(result.inSyncError).To(BeNil())
 				})
 
-				It("should add an owner reference to the child", func() {
-					m.Eventually(child, timeout).
-						Should(testutils.WithOwnerReferences(ContainElement(testutils.GetClusterGitTrackObjectOwnerRef(gto))))
+				Context("and the GitTrackObject has the adopt annotation", func() {
+					BeforeEach(func() {
+						gto.SetAnnotations(map[string]string{"faros.pusher.com/adopt": "true"})
+						result = r.handleGitTrackObject(gto)
+						Expect(result.inSyncError).To(BeNil())
+					})
+
+					It("should add an owner reference to the child", func() {
+						m.Eventually(child, timeout).
+							Should(testutils.WithOwnerReferences(ContainElement(testutils.GetClusterGitTrackObjectOwnerRef(gto))))
+					})
+
+					It("should add a last applied annotation to the child", func() {
+						m.Eventually(child, timeout).
+							Should(testutils.WithAnnotations(HaveKey(farosclient.LastAppliedAnnotation)))
+					})
 				})
 
-				It("should add a last applied annotation to the child", func() {
-					m.Eventually(child, timeout).
-						Should(testutils.WithAnnotations(HaveKey(farosclient.LastAppliedAnnotation)))
+				Context("and the GitTrackObject does not have the adopt annotation", func() {
+					BeforeEach(func() {
+						result = r.handleGitTrackObject(gto)
+					})
+
+					It("should return a ChildAlreadyExists error", func() {
+						Expect(result.inSyncError).NotTo(BeNil())
+						Expect(result.inSyncReason).To(Equal(gittrackobjectutils.ChildAlreadyExists))
+					})
+
+					It("should not add an owner reference to the child", func() {
+						m.Consistently(child, consistentlyTimeout).
+							Should(testutils.WithOwnerReferences(BeEmpty()))
+					})
 				})
 			})
 
@@ -403,4 +887,36 @@ var _ = Describe("Handler Suite", func() {
 			})
 		})
 	})
+
+	Context("handleDryRunCreate", func() {
+		var gto *farosv1alpha1.GitTrackObject
+
+		BeforeEach(func() {
+			gto = testutils.ExampleGitTrackObject.DeepCopy()
+		})
+
+		It("reports the fields of a custom resource that would be created", func() {
+			child := &unstructured.Unstructured{}
+			child.SetAPIVersion("example.com/v1")
+			child.SetKind("Example")
+			child.SetNamespace(gto.GetNamespace())
+			child.SetName("example")
+			Expect(unstructured.SetNestedField(child.Object, int64(3), "spec", "replicas")).To(Succeed())
+
+			result := r.handleDryRunCreate(gto, child)
+			Expect(result.inSyncError).To(BeNil())
+			Expect(result.dryRunDiff).To(ContainSubstring("spec.replicas: <unset> -> 3"))
+		})
+	})
 })
+
+// recordingApplier records the options each object is applied with
+type recordingApplier struct {
+	farosclient.Client
+	opts []*farosclient.ApplyOptions
+}
+
+func (a *recordingApplier) Apply(ctx context.Context, opts *farosclient.ApplyOptions, obj runtime.Object) error {
+	a.opts = append(a.opts, opts)
+	return a.Client.Apply(ctx, opts, obj)
+}
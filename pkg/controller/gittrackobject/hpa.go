@@ -0,0 +1,87 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// replicasPath is the JSON pointer to the field managed by
+// HorizontalPodAutoscalers on their targets
+const replicasPath = "/spec/replicas"
+
+// scalableKinds is the set of kinds that faros expects to be targeted by
+// HorizontalPodAutoscalers
+var scalableKinds = map[schema.GroupKind]struct{}{
+	{Group: "apps", Kind: "Deployment"}:        {},
+	{Group: "apps", Kind: "StatefulSet"}:       {},
+	{Group: "apps", Kind: "ReplicaSet"}:        {},
+	{Group: "", Kind: "ReplicationController"}: {},
+}
+
+// isTargetedByHPA returns whether any HorizontalPodAutoscaler in the child's
+// namespace has the child as its scale target
+func (r *ReconcileGitTrackObject) isTargetedByHPA(child *unstructured.Unstructured) (bool, error) {
+	if _, ok := scalableKinds[scaleGroupKind(child.GroupVersionKind().GroupKind())]; !ok {
+		return false, nil
+	}
+
+	hpas := &autoscalingv1.HorizontalPodAutoscalerList{}
+	err := r.List(context.TODO(), hpas, client.InNamespace(child.GetNamespace()))
+	if err != nil {
+		return false, fmt.Errorf("unable to list HorizontalPodAutoscalers: %v", err)
+	}
+	for _, hpa := range hpas.Items {
+		if targetsChild(hpa.Spec.ScaleTargetRef, child) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// targetsChild returns whether the scale target reference is the child. The
+// group is only compared when the reference sets an apiVersion.
+func targetsChild(ref autoscalingv1.CrossVersionObjectReference, child *unstructured.Unstructured) bool {
+	if ref.Kind != child.GetKind() || ref.Name != child.GetName() {
+		return false
+	}
+	if ref.APIVersion == "" {
+		return true
+	}
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	target := scaleGroupKind(gv.WithKind(ref.Kind).GroupKind())
+	return target == scaleGroupKind(child.GroupVersionKind().GroupKind())
+}
+
+// scaleGroupKind returns the group and kind of workloads that are also served
+// by the deprecated extensions group as they are in the apps group, as both
+// groups serve the same objects
+func scaleGroupKind(gk schema.GroupKind) schema.GroupKind {
+	if gk.Group == "extensions" {
+		gk.Group = "apps"
+	}
+	return gk
+}
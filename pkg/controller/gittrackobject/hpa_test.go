@@ -0,0 +1,68 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("HPA Suite", func() {
+	Context("targetsChild", func() {
+		var child *unstructured.Unstructured
+
+		BeforeEach(func() {
+			child = &unstructured.Unstructured{}
+			child.SetAPIVersion("apps/v1")
+			child.SetKind("Deployment")
+			child.SetNamespace("default")
+			child.SetName("example")
+		})
+
+		It("matches the group, kind and name of the child", func() {
+			ref := autoscalingv1.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "example"}
+			Expect(targetsChild(ref, child)).To(BeTrue())
+		})
+
+		It("matches any version of the group", func() {
+			ref := autoscalingv1.CrossVersionObjectReference{APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "example"}
+			Expect(targetsChild(ref, child)).To(BeTrue())
+		})
+
+		It("treats the extensions group as the apps group", func() {
+			ref := autoscalingv1.CrossVersionObjectReference{APIVersion: "extensions/v1beta1", Kind: "Deployment", Name: "example"}
+			Expect(targetsChild(ref, child)).To(BeTrue())
+		})
+
+		It("matches the kind and name when the apiVersion is not set", func() {
+			ref := autoscalingv1.CrossVersionObjectReference{Kind: "Deployment", Name: "example"}
+			Expect(targetsChild(ref, child)).To(BeTrue())
+		})
+
+		It("does not match a kind of the same name in another group", func() {
+			ref := autoscalingv1.CrossVersionObjectReference{APIVersion: "example.com/v1", Kind: "Deployment", Name: "example"}
+			Expect(targetsChild(ref, child)).To(BeFalse())
+		})
+
+		It("does not match another name", func() {
+			ref := autoscalingv1.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "other"}
+			Expect(targetsChild(ref, child)).To(BeFalse())
+		})
+	})
+})
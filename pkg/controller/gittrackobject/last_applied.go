@@ -0,0 +1,108 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"encoding/json"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// maxLastAppliedDataSize is the largest compressed size of the children
+// recorded in the status, so that the (Cluster)GitTrackObject stays well
+// within the size limit of objects
+const maxLastAppliedDataSize = 256 * 1024
+
+// lastAppliedData returns the compressed children to record in the status as
+// the baseline of their next update, if the configuration of any of them is
+// too large for the last applied annotation. Otherwise, or if they are too
+// large to record, it returns nil.
+func (r *ReconcileGitTrackObject) lastAppliedData(children []*unstructured.Unstructured) []byte {
+	exceeds := false
+	objects := []map[string]interface{}{}
+	for _, child := range children {
+		e, err := r.applier.ExceedsAnnotationLimit(child)
+		if err != nil {
+			r.log.Error(err, "unable to determine size of last applied configuration")
+			return nil
+		}
+		exceeds = exceeds || e
+		objects = append(objects, child.Object)
+	}
+	if !exceeds {
+		return nil
+	}
+
+	data, err := json.Marshal(objects)
+	if err != nil {
+		r.log.Error(err, "unable to marshal last applied children")
+		return nil
+	}
+	spec := farosv1alpha1.GitTrackObjectSpec{ContentEncoding: farosv1alpha1.ContentEncodingGzip}
+	err = gittrackobjectutils.SetChildData(&spec, data)
+	if err != nil {
+		r.log.Error(err, "unable to compress last applied children")
+		return nil
+	}
+	if len(spec.Data) > maxLastAppliedDataSize {
+		r.log.V(1).Info("Last applied children too large to record", "size", len(spec.Data))
+		return nil
+	}
+	return spec.Data
+}
+
+// lastAppliedChild returns the child as it was last applied, from the
+// children recorded in the status, or nil if it was not recorded. The
+// namespace and owner references set on the child when syncing are set on it
+// too, so that its configuration matches the one last applied.
+func (r *ReconcileGitTrackObject) lastAppliedChild(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) runtime.Object {
+	lastApplied := gto.GetStatus().LastAppliedData
+	if len(lastApplied) == 0 {
+		return nil
+	}
+	data, err := gittrackobjectutils.ChildData(farosv1alpha1.GitTrackObjectSpec{
+		Data:            lastApplied,
+		ContentEncoding: farosv1alpha1.ContentEncodingGzip,
+	})
+	if err != nil {
+		r.log.Error(err, "unable to read last applied children")
+		return nil
+	}
+	objects := []map[string]interface{}{}
+	err = json.Unmarshal(data, &objects)
+	if err != nil {
+		r.log.Error(err, "unable to unmarshal last applied children")
+		return nil
+	}
+
+	for _, object := range objects {
+		baseline := &unstructured.Unstructured{Object: object}
+		if baseline.GetKind() != child.GetKind() || baseline.GetName() != child.GetName() {
+			continue
+		}
+		if baseline.GetNamespace() != "" && !hasNamespaceSelector(gto) && baseline.GetNamespace() != child.GetNamespace() {
+			continue
+		}
+		baseline.SetNamespace(child.GetNamespace())
+		baseline.SetOwnerReferences(child.GetOwnerReferences())
+		return baseline
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	farosclient "github.com/pusher/faros/pkg/utils/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Last Applied Suite", func() {
+	var r *ReconcileGitTrackObject
+	var gto *farosv1alpha1.GitTrackObject
+	var child *unstructured.Unstructured
+
+	BeforeEach(func() {
+		applier, err := farosclient.NewApplier(cfg, farosclient.Options{})
+		Expect(err).NotTo(HaveOccurred())
+		r = &ReconcileGitTrackObject{log: rlogr.Log.WithName("last-applied-test"), applier: applier}
+		gto = &farosv1alpha1.GitTrackObject{}
+
+		child = &unstructured.Unstructured{}
+		child.SetAPIVersion("v1")
+		child.SetKind("ConfigMap")
+		child.SetNamespace("default")
+		child.SetName("large")
+	})
+
+	Context("lastAppliedData", func() {
+		It("records nothing when the configuration fits in the annotation", func() {
+			Expect(r.lastAppliedData([]*unstructured.Unstructured{child})).To(BeNil())
+		})
+
+		It("records the children when a configuration is too large for the annotation", func() {
+			child.Object["data"] = map[string]interface{}{"large": strings.Repeat("x", 300*1024)}
+			Expect(r.lastAppliedData([]*unstructured.Unstructured{child})).NotTo(BeEmpty())
+		})
+	})
+
+	Context("lastAppliedChild", func() {
+		BeforeEach(func() {
+			child.Object["data"] = map[string]interface{}{"large": strings.Repeat("x", 300*1024)}
+			gto.Status.LastAppliedData = r.lastAppliedData([]*unstructured.Unstructured{child})
+		})
+
+		It("returns nothing when no children were recorded", func() {
+			gto.Status.LastAppliedData = nil
+			Expect(r.lastAppliedChild(gto, child)).To(BeNil())
+		})
+
+		It("returns nothing for another child", func() {
+			other := child.DeepCopy()
+			other.SetName("other")
+			Expect(r.lastAppliedChild(gto, other)).To(BeNil())
+		})
+
+		It("returns the child with the owner references set when syncing", func() {
+			desired := child.DeepCopy()
+			desired.Object["data"] = map[string]interface{}{"small": "x"}
+			desired.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "faros.pusher.com/v1alpha1", Kind: "GitTrackObject", Name: "configmap-large", UID: "1234"}})
+
+			expected := child.DeepCopy()
+			expected.SetOwnerReferences(desired.GetOwnerReferences())
+			Expect(r.lastAppliedChild(gto, desired)).To(Equal(expected))
+		})
+	})
+})
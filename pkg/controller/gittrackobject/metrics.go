@@ -18,11 +18,20 @@ package gittrackobject
 
 import (
 	"fmt"
+	"time"
 
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	"github.com/pusher/faros/pkg/controller/gittrackobject/metrics"
 )
 
+// Operations recorded by the apply operations metric
+const (
+	createOperation   = "create"
+	updateOperation   = "update"
+	recreateOperation = "recreate"
+	noopOperation     = "noop"
+)
+
 type metricsOpts struct {
 	inSync bool
 }
@@ -44,3 +53,59 @@ func (r *ReconcileGitTrackObject) updateMetrics(gto farosv1alpha1.GitTrackObject
 	}
 	return nil
 }
+
+func updateReconcileDurationMetric(duration time.Duration, err error) error {
+	labels := map[string]string{
+		"result": resultLabel(err),
+	}
+	metric, err := metrics.ReconcileDuration.GetMetricWith(labels)
+	if err != nil {
+		return fmt.Errorf("unable to update reconcile duration metric: %v", err)
+	}
+	metric.Observe(duration.Seconds())
+	return nil
+}
+
+func (r *ReconcileGitTrackObject) updateApplyOperationsMetric(kind, operation string, opErr error) {
+	labels := map[string]string{
+		"kind":      kind,
+		"operation": operation,
+		"result":    resultLabel(opErr),
+	}
+	counter, err := metrics.ApplyOperations.GetMetricWith(labels)
+	if err != nil {
+		r.log.Error(err, "unable to update apply operations metric")
+		return
+	}
+	counter.Inc()
+}
+
+func (r *ReconcileGitTrackObject) updateDriftMetric(kind, namespace string) {
+	labels := map[string]string{
+		"kind":      kind,
+		"namespace": namespace,
+	}
+	counter, err := metrics.DriftCorrected.GetMetricWith(labels)
+	if err != nil {
+		r.log.Error(err, "unable to update drift corrected metric")
+		return
+	}
+	counter.Inc()
+}
+
+// resultLabel returns the result label for an operation that returned err
+func resultLabel(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// operationFor returns the operation to record for an attempted operation,
+// which is a no-op if it succeeded but left the child unchanged
+func operationFor(operation string, updated bool, err error) string {
+	if updated || err != nil {
+		return operation
+	}
+	return noopOperation
+}
@@ -30,8 +30,33 @@ var (
 		Name: "faros_gittrackobject_in_sync",
 		Help: "Shows whether a (Cluster)GitTrackObject is In Sync (boolean)",
 	}, []string{"kind", "name", "namespace"})
+
+	// ReconcileDuration is a prometheus histogram that holds the time taken
+	// to reconcile a (Cluster)GitTrackObject
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "faros_gittrackobject_reconcile_duration_seconds",
+		Help:    "Counts the time taken to reconcile a (Cluster)GitTrackObject",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+	}, []string{"result"})
+
+	// ApplyOperations is a prometheus counter for the operations performed on
+	// the children of (Cluster)GitTrackObjects
+	ApplyOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "faros_gittrackobject_apply_operations_total",
+		Help: "Counts the create, update, recreate and no-op operations performed on child objects",
+	}, []string{"kind", "operation", "result"})
+
+	// DriftCorrected is a prometheus counter for the number of times children
+	// of (Cluster)GitTrackObjects were reset after being modified out-of-band
+	DriftCorrected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "faros_gittrackobject_drift_corrected_total",
+		Help: "Counts the number of times child objects modified out-of-band were reset",
+	}, []string{"kind", "namespace"})
 )
 
 func init() {
 	ctrlmetrics.Registry.MustRegister(InSync)
+	ctrlmetrics.Registry.MustRegister(ReconcileDuration)
+	ctrlmetrics.Registry.MustRegister(ApplyOperations)
+	ctrlmetrics.Registry.MustRegister(DriftCorrected)
 }
@@ -17,6 +17,9 @@ limitations under the License.
 package gittrackobject
 
 import (
+	"fmt"
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
@@ -24,6 +27,7 @@ import (
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	"github.com/pusher/faros/pkg/controller/gittrackobject/metrics"
 	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/test/testenv"
 	testutils "github.com/pusher/faros/test/utils"
 	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -38,10 +42,7 @@ var _ = Describe("Metrics Suite", func() {
 		// channel when it is finished.
 		var err error
 		cfg.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
-		mgr, err = manager.New(cfg, manager.Options{
-			Namespace:          farosflags.Namespace,
-			MetricsBindAddress: "0", // Disable serving metrics while testing
-		})
+		mgr, err = t.NewManager(farosflags.Namespaces[0])
 		Expect(err).NotTo(HaveOccurred())
 
 		recFn := newReconciler(mgr)
@@ -49,6 +50,8 @@ var _ = Describe("Metrics Suite", func() {
 
 		// Reset all metrics before each test
 		metrics.InSync.Reset()
+		metrics.ReconcileDuration.Reset()
+		metrics.ApplyOperations.Reset()
 	})
 
 	Context("updateMetrics", func() {
@@ -138,8 +141,89 @@ var _ = Describe("Metrics Suite", func() {
 			})
 		})
 	})
+
+	Context("updateReconcileDurationMetric", func() {
+		It("observes successful reconciles", func() {
+			Expect(updateReconcileDurationMetric(time.Second, nil)).To(Succeed())
+			histogram, err := GetHistogram(metrics.ReconcileDuration, "success")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(histogram.GetSampleCount()).To(Equal(uint64(1)))
+			Expect(histogram.GetSampleSum()).To(Equal(1.0))
+		})
+
+		It("observes failed reconciles", func() {
+			Expect(updateReconcileDurationMetric(time.Second, fmt.Errorf("error"))).To(Succeed())
+			histogram, err := GetHistogram(metrics.ReconcileDuration, "error")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(histogram.GetSampleCount()).To(Equal(uint64(1)))
+		})
+	})
+
+	Context("updateApplyOperationsMetric", func() {
+		It("counts operations by kind, operation and result", func() {
+			r.updateApplyOperationsMetric("Deployment", updateOperation, nil)
+			r.updateApplyOperationsMetric("Deployment", updateOperation, nil)
+			r.updateApplyOperationsMetric("Deployment", createOperation, fmt.Errorf("error"))
+
+			counter, err := GetCounter(metrics.ApplyOperations, "Deployment", updateOperation, "success")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counter.GetValue()).To(Equal(2.0))
+
+			counter, err = GetCounter(metrics.ApplyOperations, "Deployment", createOperation, "error")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counter.GetValue()).To(Equal(1.0))
+		})
+	})
+
+	Context("operationFor", func() {
+		It("returns the operation when the child was updated", func() {
+			Expect(operationFor(recreateOperation, true, nil)).To(Equal(recreateOperation))
+		})
+
+		It("returns a no-op when the child was unchanged", func() {
+			Expect(operationFor(recreateOperation, false, nil)).To(Equal(noopOperation))
+		})
+
+		It("returns the operation when the operation failed", func() {
+			Expect(operationFor(recreateOperation, false, fmt.Errorf("error"))).To(Equal(recreateOperation))
+		})
+	})
 })
 
+func GetCounter(cv *prometheus.CounterVec, kind, operation, result string) (*dto.Counter, error) {
+	counter, err := cv.GetMetricWith(map[string]string{
+		"kind":      kind,
+		"operation": operation,
+		"result":    result,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var metric dto.Metric
+	err = counter.Write(&metric)
+	if err != nil {
+		return nil, err
+	}
+
+	return metric.GetCounter(), nil
+}
+
+func GetHistogram(hv *prometheus.HistogramVec, result string) (*dto.Histogram, error) {
+	histogram, err := hv.GetMetricWith(map[string]string{"result": result})
+	if err != nil {
+		return nil, err
+	}
+
+	var metric dto.Metric
+	err = histogram.(prometheus.Metric).Write(&metric)
+	if err != nil {
+		return nil, err
+	}
+
+	return metric.GetHistogram(), nil
+}
+
 func GetGauge(gv *prometheus.GaugeVec, obj farosv1alpha1.GitTrackObjectInterface) (*dto.Gauge, error) {
 	gauge, err := gv.GetMetricWith(map[string]string{
 		"kind":      obj.GetSpec().Kind,
@@ -0,0 +1,308 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// hasNamespaceSelector returns whether the child of the (Cluster)GitTrackObject
+// is applied to each namespace matching its namespace selector. Only
+// ClusterGitTrackObjects select namespaces.
+func hasNamespaceSelector(gto farosv1alpha1.GitTrackObjectInterface) bool {
+	return gto.GetSpec().NamespaceSelector != nil && gto.GetNamespace() == ""
+}
+
+// selectedChildren returns a copy of the child for each namespace it has been
+// applied to, or the child itself if it does not select namespaces
+func selectedChildren(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) []*unstructured.Unstructured {
+	if !hasNamespaceSelector(gto) {
+		return []*unstructured.Unstructured{child}
+	}
+	return childrenIn(child, gto.GetStatus().Namespaces)
+}
+
+// childrenIn returns a copy of the child in each of the namespaces
+func childrenIn(child *unstructured.Unstructured, namespaces []string) []*unstructured.Unstructured {
+	children := []*unstructured.Unstructured{}
+	for _, namespace := range namespaces {
+		c := child.DeepCopy()
+		c.SetNamespace(namespace)
+		children = append(children, c)
+	}
+	return children
+}
+
+// syncSelectedNamespaces applies a copy of the child to each namespace
+// matching the namespace selector of the ClusterGitTrackObject and removes the
+// copies from namespaces that no longer match
+func (r *ReconcileGitTrackObject) syncSelectedNamespaces(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) handlerResult {
+	if gto.GetSpec().KubeConfigSecretRef != nil {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorSelectingNamespaces,
+			inSyncError:  fmt.Errorf("unable to select namespaces for child %s %s: namespace selectors are not supported for other clusters", gto.GetSpec().Kind, gto.GetSpec().Name),
+		}
+	}
+	namespaces, err := r.selectNamespaces(gto.GetSpec().NamespaceSelector)
+	if err != nil {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorSelectingNamespaces,
+			inSyncError:  fmt.Errorf("unable to select namespaces for child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+
+	results := make(map[string]handlerResult)
+	for _, c := range childrenIn(child, namespaces) {
+		results[c.GetNamespace()] = withChildState(r.withValues("child namespace", c.GetNamespace()).syncChild(gto, c), c)
+	}
+	result := mergeResults("namespace", namespaces, results)
+
+	// Children that could not be removed are kept in the status so that
+	// removing them is retried
+	result.namespaces = namespaces
+	for _, c := range childrenIn(child, deselectedNamespaces(gto.GetStatus().Namespaces, namespaces)) {
+		err = r.removeChild(gto, c, "its namespace is no longer selected")
+		if err != nil {
+			result.namespaces = append(result.namespaces, c.GetNamespace())
+			if result.inSyncError == nil {
+				result.inSyncReason = gittrackobjectutils.ErrorDeletingChild
+				result.inSyncError = fmt.Errorf("unable to remove child %s %s from namespace %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, c.GetNamespace(), err)
+			}
+		}
+	}
+	sort.Strings(result.namespaces)
+	return result
+}
+
+// selectNamespaces returns the names of the namespaces matching the selector
+func (r *ReconcileGitTrackObject) selectNamespaces(selector *metav1.LabelSelector) ([]string, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace selector: %v", err)
+	}
+	namespaces := &corev1.NamespaceList{}
+	err = r.List(context.TODO(), namespaces)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list namespaces: %v", err)
+	}
+	return matchNamespaces(s, namespaces.Items), nil
+}
+
+// matchNamespaces returns the sorted names of the namespaces matching the
+// selector that are managed by the controller and are not being deleted
+func matchNamespaces(selector labels.Selector, namespaces []corev1.Namespace) []string {
+	names := []string{}
+	for _, namespace := range namespaces {
+		if namespace.DeletionTimestamp != nil || !farosflags.ManagesNamespace(namespace.Name) {
+			continue
+		}
+		if selector.Matches(labels.Set(namespace.Labels)) {
+			names = append(names, namespace.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// deselectedNamespaces returns the namespaces in previous that are not in
+// current
+func deselectedNamespaces(previous, current []string) []string {
+	selected := make(map[string]bool)
+	for _, namespace := range current {
+		selected[namespace] = true
+	}
+	deselected := []string{}
+	for _, namespace := range previous {
+		if !selected[namespace] {
+			deselected = append(deselected, namespace)
+		}
+	}
+	return deselected
+}
+
+// removeChild deletes a child that is no longer wanted, or orphans it if
+// asked to, recording the reason in the event sent. Children no longer
+// controlled by the (Cluster)GitTrackObject are left alone.
+func (r *ReconcileGitTrackObject) removeChild(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured, reason string) error {
+	found := &unstructured.Unstructured{}
+	found.SetKind(child.GetKind())
+	found.SetAPIVersion(child.GetAPIVersion())
+	err := r.Get(context.TODO(), types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if owner := metav1.GetControllerOf(found); owner == nil || owner.UID != gto.GetUID() {
+		return nil
+	}
+
+	// The child may only be known by its reference, so fall back to the
+	// annotations it was applied with
+	orphan, err := gittrackobjectutils.ShouldOrphanOnDelete(gto, child, found)
+	if err != nil {
+		return err
+	}
+	if orphan {
+		return r.orphanChild(gto, child)
+	}
+
+	err = deleteChild(r.Client, gto, found, found)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	r.log.V(0).Info("Child deleted", "child kind", child.GetKind(), "child namespace", child.GetNamespace(), "child name", child.GetName(), "reason", reason)
+	r.sendEvent(gto, corev1.EventTypeNormal, "DeleteSuccessful", "Deleted child %s %s/%s as %s", child.GetKind(), child.GetNamespace(), child.GetName(), reason)
+	return nil
+}
+
+// mergeResults combines the results of syncing each child, labelled by its
+// key, into a single result. The children are only in sync once they are all
+// in sync and their health is the worst health across the children. The state
+// of each child that was synced is kept so that unchanged children are skipped
+// on the next sync.
+func mergeResults(label string, keys []string, results map[string]handlerResult) handlerResult {
+	merged := handlerResult{}
+	errs := []string{}
+	diffs := []string{}
+	namespaces := make(map[string]bool)
+	for _, key := range keys {
+		res := results[key]
+		if res.inSyncError != nil {
+			if merged.inSyncReason == "" {
+				merged.inSyncReason = res.inSyncReason
+			}
+			errs = append(errs, fmt.Sprintf("%s %s: %v", label, key, res.inSyncError))
+		}
+		if res.dryRunDiff != "" {
+			diffs = append(diffs, fmt.Sprintf("# %s: %s\n%s", label, key, res.dryRunDiff))
+		}
+		if healthSeverity(res.health) > healthSeverity(merged.health) {
+			merged.health, merged.healthDetail = res.health, ""
+			if res.healthDetail != "" {
+				merged.healthDetail = fmt.Sprintf("%s %s: %s", label, key, res.healthDetail)
+			}
+		}
+		if res.lastUpdate != nil && (merged.lastUpdate == nil || merged.lastUpdate.Time.Before(&res.lastUpdate.Time)) {
+			merged.lastUpdate = res.lastUpdate
+		}
+		if res.recreateToken != "" {
+			merged.recreateToken = res.recreateToken
+		}
+		for k, state := range res.childStates {
+			if merged.childStates == nil {
+				merged.childStates = make(map[string]farosv1alpha1.ChildState)
+			}
+			merged.childStates[k] = state
+		}
+		if res.requeueAfter > 0 && (merged.requeueAfter == 0 || res.requeueAfter < merged.requeueAfter) {
+			merged.requeueAfter = res.requeueAfter
+		}
+		if res.namespaces != nil && merged.namespaces == nil {
+			merged.namespaces = []string{}
+		}
+		for _, namespace := range res.namespaces {
+			if !namespaces[namespace] {
+				namespaces[namespace] = true
+				merged.namespaces = append(merged.namespaces, namespace)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		merged.inSyncError = fmt.Errorf("%s", strings.Join(errs, "; "))
+		// The children that failed must be recreated too
+		merged.recreateToken = ""
+	}
+	merged.dryRunDiff = strings.Join(diffs, "\n")
+	sort.Strings(merged.namespaces)
+	return merged
+}
+
+// healthSeverity orders the health of children from unknown to failed
+func healthSeverity(health gittrackobjectutils.Health) int {
+	switch health {
+	case gittrackobjectutils.HealthReady:
+		return 1
+	case gittrackobjectutils.HealthProgressing:
+		return 2
+	case gittrackobjectutils.HealthFailed:
+		return 3
+	}
+	return 0
+}
+
+// namespaceSelectorRequests returns a mapping function that enqueues every
+// ClusterGitTrackObject selecting the namespace, so that children are applied
+// to namespaces as they appear or start matching and removed from namespaces
+// that stop matching
+func namespaceSelectorRequests(c client.Client, log logr.Logger) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		namespace, ok := obj.Object.(*corev1.Namespace)
+		if !ok {
+			return nil
+		}
+
+		cgtos := &farosv1alpha1.ClusterGitTrackObjectList{}
+		err := c.List(context.TODO(), cgtos)
+		if err != nil {
+			log.Error(err, "unable to list ClusterGitTrackObjects after namespace changed", "namespace", namespace.Name)
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for i := range cgtos.Items {
+			if selectsNamespace(&cgtos.Items[i], namespace) {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: cgtos.Items[i].Name},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// selectsNamespace returns whether the namespace matches the namespace
+// selector of the ClusterGitTrackObject or holds one of its children
+func selectsNamespace(cgto *farosv1alpha1.ClusterGitTrackObject, namespace *corev1.Namespace) bool {
+	if cgto.Spec.NamespaceSelector == nil {
+		return false
+	}
+	for _, name := range cgto.Status.Namespaces {
+		if name == namespace.Name {
+			return true
+		}
+	}
+	selector, err := metav1.LabelSelectorAsSelector(cgto.Spec.NamespaceSelector)
+	return err == nil && selector.Matches(labels.Set(namespace.Labels))
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ = Describe("Namespace Selector Suite", func() {
+	var namespace = func(name string, labels map[string]string) corev1.Namespace {
+		return corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	Context("matchNamespaces", func() {
+		It("returns the sorted names of the matching namespaces", func() {
+			selector := labels.SelectorFromSet(labels.Set{"team": "platform"})
+			namespaces := []corev1.Namespace{
+				namespace("b", map[string]string{"team": "platform"}),
+				namespace("c", map[string]string{"team": "other"}),
+				namespace("a", map[string]string{"team": "platform"}),
+			}
+			Expect(matchNamespaces(selector, namespaces)).To(Equal([]string{"a", "b"}))
+		})
+
+		It("skips namespaces being deleted", func() {
+			deleted := namespace("a", nil)
+			now := metav1.Now()
+			deleted.DeletionTimestamp = &now
+			Expect(matchNamespaces(labels.Everything(), []corev1.Namespace{deleted})).To(BeEmpty())
+		})
+	})
+
+	Context("deselectedNamespaces", func() {
+		It("returns the namespaces no longer selected", func() {
+			Expect(deselectedNamespaces([]string{"a", "b", "c"}, []string{"b", "d"})).To(Equal([]string{"a", "c"}))
+		})
+	})
+
+	Context("selectedChildren", func() {
+		var cgto *farosv1alpha1.ClusterGitTrackObject
+		var child *unstructured.Unstructured
+
+		BeforeEach(func() {
+			cgto = &farosv1alpha1.ClusterGitTrackObject{ObjectMeta: metav1.ObjectMeta{Name: "networkpolicy-default-deny"}}
+			child = &unstructured.Unstructured{}
+			child.SetKind("NetworkPolicy")
+			child.SetName("default-deny")
+		})
+
+		It("returns the child when no namespaces are selected", func() {
+			Expect(selectedChildren(cgto, child)).To(Equal([]*unstructured.Unstructured{child}))
+		})
+
+		It("returns a copy of the child in each namespace it was applied to", func() {
+			cgto.Spec.NamespaceSelector = &metav1.LabelSelector{}
+			cgto.Status.Namespaces = []string{"a", "b"}
+			children := selectedChildren(cgto, child)
+			Expect(children).To(HaveLen(2))
+			Expect(children[0].GetNamespace()).To(Equal("a"))
+			Expect(children[1].GetNamespace()).To(Equal("b"))
+			Expect(child.GetNamespace()).To(BeEmpty())
+		})
+	})
+
+	Context("mergeResults", func() {
+		It("is in sync when every namespace is in sync", func() {
+			merged := mergeResults("namespace", []string{"a", "b"}, map[string]handlerResult{
+				"a": {health: gittrackobjectutils.HealthReady},
+				"b": {health: gittrackobjectutils.HealthProgressing, healthDetail: "waiting for rollout"},
+			})
+			Expect(merged.inSyncError).To(BeNil())
+			Expect(merged.health).To(Equal(gittrackobjectutils.HealthProgressing))
+			Expect(merged.healthDetail).To(Equal("namespace b: waiting for rollout"))
+		})
+
+		It("reports the errors of each namespace", func() {
+			merged := mergeResults("namespace", []string{"a", "b"}, map[string]handlerResult{
+				"a": {inSyncReason: gittrackobjectutils.ErrorCreatingChild, inSyncError: fmt.Errorf("forbidden")},
+				"b": {inSyncReason: gittrackobjectutils.ErrorUpdatingChild, inSyncError: fmt.Errorf("conflict"), recreateToken: "1"},
+			})
+			Expect(merged.inSyncReason).To(Equal(gittrackobjectutils.ErrorCreatingChild))
+			Expect(merged.inSyncError).To(MatchError("namespace a: forbidden; namespace b: conflict"))
+			Expect(merged.recreateToken).To(BeEmpty())
+		})
+
+		It("requeues after the shortest back off", func() {
+			merged := mergeResults("namespace", []string{"a", "b"}, map[string]handlerResult{
+				"a": {requeueAfter: time.Minute},
+				"b": {requeueAfter: time.Second},
+			})
+			Expect(merged.requeueAfter).To(Equal(time.Second))
+		})
+
+		It("combines the namespaces of each child", func() {
+			merged := mergeResults("child", []string{"ConfigMap a", "Secret b"}, map[string]handlerResult{
+				"ConfigMap a": {namespaces: []string{"team-b", "team-a"}},
+				"Secret b":    {namespaces: []string{"team-a", "team-c"}},
+			})
+			Expect(merged.namespaces).To(Equal([]string{"team-a", "team-b", "team-c"}))
+		})
+
+		It("keeps the state of every child that was synced", func() {
+			merged := mergeResults("namespace", []string{"a", "b", "c"}, map[string]handlerResult{
+				"a": {childStates: map[string]farosv1alpha1.ChildState{"ConfigMap a/example": {Hash: "1", ResourceVersion: "10"}}},
+				"b": {childStates: map[string]farosv1alpha1.ChildState{"ConfigMap b/example": {Hash: "2", ResourceVersion: "20"}}},
+				"c": {inSyncReason: gittrackobjectutils.ErrorUpdatingChild, inSyncError: fmt.Errorf("conflict")},
+			})
+			Expect(merged.childHash).To(BeEmpty())
+			Expect(merged.childStates).To(Equal(map[string]farosv1alpha1.ChildState{
+				"ConfigMap a/example": {Hash: "1", ResourceVersion: "10"},
+				"ConfigMap b/example": {Hash: "2", ResourceVersion: "20"},
+			}))
+		})
+
+		It("leaves the namespaces unset when no child selects namespaces", func() {
+			merged := mergeResults("child", []string{"ConfigMap a"}, map[string]handlerResult{
+				"ConfigMap a": {health: gittrackobjectutils.HealthReady},
+			})
+			Expect(merged.namespaces).To(BeNil())
+		})
+	})
+
+	Context("selectsNamespace", func() {
+		var cgto *farosv1alpha1.ClusterGitTrackObject
+
+		BeforeEach(func() {
+			cgto = &farosv1alpha1.ClusterGitTrackObject{
+				Spec: farosv1alpha1.GitTrackObjectSpec{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+				},
+			}
+		})
+
+		It("selects matching namespaces", func() {
+			ns := namespace("a", map[string]string{"team": "platform"})
+			Expect(selectsNamespace(cgto, &ns)).To(BeTrue())
+		})
+
+		It("selects namespaces holding a child that no longer match", func() {
+			cgto.Status.Namespaces = []string{"a"}
+			ns := namespace("a", nil)
+			Expect(selectsNamespace(cgto, &ns)).To(BeTrue())
+		})
+
+		It("does not select other namespaces", func() {
+			ns := namespace("a", nil)
+			Expect(selectsNamespace(cgto, &ns)).To(BeFalse())
+		})
+
+		It("does not select namespaces without a namespace selector", func() {
+			cgto.Spec.NamespaceSelector = nil
+			ns := namespace("a", map[string]string{"team": "platform"})
+			Expect(selectsNamespace(cgto, &ns)).To(BeFalse())
+		})
+	})
+})
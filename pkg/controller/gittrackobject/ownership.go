@@ -0,0 +1,82 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ownershipEvent returns the reason and message of the event recording that
+// the child was taken over from its previous owner, or adopted if it had none
+func ownershipEvent(child *unstructured.Unstructured, previousOwner string) (string, string) {
+	if previousOwner == "" {
+		return "Adopted", fmt.Sprintf("Adopted existing child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+	}
+	return "OwnershipTransferred", fmt.Sprintf("Took ownership of child %s %s/%s from %s", child.GetKind(), child.GetNamespace(), child.GetName(), previousOwner)
+}
+
+// ownerFromReference returns the (Cluster)GitTrackObject the owner reference
+// refers to, with enough metadata to record events against it, or nil if the
+// owner is not a (Cluster)GitTrackObject
+func ownerFromReference(ref *metav1.OwnerReference, namespace string) farosv1alpha1.GitTrackObjectInterface {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil || gv.Group != farosv1alpha1.SchemeGroupVersion.Group {
+		return nil
+	}
+	meta := metav1.ObjectMeta{Name: ref.Name, UID: ref.UID}
+	switch ref.Kind {
+	case "GitTrackObject":
+		meta.Namespace = namespace
+		return &farosv1alpha1.GitTrackObject{ObjectMeta: meta}
+	case "ClusterGitTrackObject":
+		return &farosv1alpha1.ClusterGitTrackObject{ObjectMeta: meta}
+	}
+	return nil
+}
+
+// recordOwnershipChange sends an event when the (Cluster)GitTrackObject has
+// taken ownership of the child from the controller it had before the update,
+// so that audit trails capture the child changing hands. When the child is
+// taken from another (Cluster)GitTrackObject the event is also sent to the
+// previous owner.
+func (r *ReconcileGitTrackObject) recordOwnershipChange(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured, previous *metav1.OwnerReference) {
+	if previous == nil {
+		r.sendOwnershipEvent(gto, child, "")
+		return
+	}
+	if previous.UID == gto.GetUID() {
+		return
+	}
+	r.sendOwnershipEvent(gto, child, fmt.Sprintf("%s %s", previous.Kind, previous.Name))
+	if owner := ownerFromReference(previous, child.GetNamespace()); owner != nil {
+		r.sendEvent(owner, corev1.EventTypeNormal, "OwnershipTransferred", "Ownership of child %s %s/%s transferred to %s", child.GetKind(), child.GetNamespace(), child.GetName(), gto.GetName())
+	}
+}
+
+// sendOwnershipEvent sends the event recording that the child was taken over
+// from its previous owner, or adopted if it had none
+func (r *ReconcileGitTrackObject) sendOwnershipEvent(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured, previousOwner string) {
+	reason, message := ownershipEvent(child, previousOwner)
+	r.log.V(0).Info("Child ownership changed", "reason", reason, "previous owner", previousOwner)
+	r.sendEvent(gto, corev1.EventTypeNormal, reason, "%s", message)
+}
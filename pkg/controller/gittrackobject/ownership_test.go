@@ -0,0 +1,70 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Ownership Suite", func() {
+	var child *unstructured.Unstructured
+
+	BeforeEach(func() {
+		child = &unstructured.Unstructured{}
+		child.SetAPIVersion("apps/v1")
+		child.SetKind("Deployment")
+		child.SetNamespace("default")
+		child.SetName("nginx")
+	})
+
+	Context("ownershipEvent", func() {
+		It("reports an adoption when the child had no owner", func() {
+			reason, message := ownershipEvent(child, "")
+			Expect(reason).To(Equal("Adopted"))
+			Expect(message).To(Equal("Adopted existing child Deployment default/nginx"))
+		})
+
+		It("reports a transfer when the child had another owner", func() {
+			reason, message := ownershipEvent(child, "GitTrackObject deployment-nginx")
+			Expect(reason).To(Equal("OwnershipTransferred"))
+			Expect(message).To(Equal("Took ownership of child Deployment default/nginx from GitTrackObject deployment-nginx"))
+		})
+	})
+
+	Context("ownerFromReference", func() {
+		It("returns a GitTrackObject in the namespace of the child", func() {
+			owner := ownerFromReference(&metav1.OwnerReference{APIVersion: "faros.pusher.com/v1alpha1", Kind: "GitTrackObject", Name: "deployment-nginx", UID: "1234"}, "default")
+			Expect(owner).To(BeAssignableToTypeOf(&farosv1alpha1.GitTrackObject{}))
+			Expect(owner.GetNamespacedName()).To(Equal("default/deployment-nginx"))
+			Expect(owner.GetUID()).To(BeEquivalentTo("1234"))
+		})
+
+		It("returns a ClusterGitTrackObject without a namespace", func() {
+			owner := ownerFromReference(&metav1.OwnerReference{APIVersion: "faros.pusher.com/v1alpha1", Kind: "ClusterGitTrackObject", Name: "deployment-nginx"}, "default")
+			Expect(owner).To(BeAssignableToTypeOf(&farosv1alpha1.ClusterGitTrackObject{}))
+			Expect(owner.GetNamespace()).To(BeEmpty())
+		})
+
+		It("returns nothing for other owners", func() {
+			Expect(ownerFromReference(&metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx"}, "default")).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,110 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// childReference returns the reference recorded in the status for the child
+func childReference(child *unstructured.Unstructured) farosv1alpha1.ChildReference {
+	return farosv1alpha1.ChildReference{
+		APIVersion: child.GetAPIVersion(),
+		Kind:       child.GetKind(),
+		Namespace:  child.GetNamespace(),
+		Name:       child.GetName(),
+	}
+}
+
+// referencedChild returns a child identified by the reference
+func referencedChild(ref farosv1alpha1.ChildReference) *unstructured.Unstructured {
+	child := &unstructured.Unstructured{}
+	child.SetAPIVersion(ref.APIVersion)
+	child.SetKind(ref.Kind)
+	child.SetNamespace(ref.Namespace)
+	child.SetName(ref.Name)
+	return child
+}
+
+// removedChildren returns the children recorded in the status whose
+// documents are no longer in the data
+func removedChildren(recorded []farosv1alpha1.ChildReference, children []*unstructured.Unstructured) []*unstructured.Unstructured {
+	current := make(map[string]bool)
+	for _, child := range children {
+		current[childKey(child)] = true
+	}
+	removed := []*unstructured.Unstructured{}
+	for _, ref := range recorded {
+		child := referencedChild(ref)
+		if !current[childKey(child)] {
+			removed = append(removed, child)
+		}
+	}
+	return removed
+}
+
+// pruneChildren deletes the children whose documents have been removed from
+// the data since they were applied, or orphans them if asked to, so that the
+// children of the (Cluster)GitTrackObject are deleted together as well as
+// applied together. It returns the children to record in the status: the
+// current children and any removed children that could not be deleted, so
+// that deleting them is retried.
+func (r *ReconcileGitTrackObject) pruneChildren(gto farosv1alpha1.GitTrackObjectInterface, children []*unstructured.Unstructured, result *handlerResult) []farosv1alpha1.ChildReference {
+	refs := []farosv1alpha1.ChildReference{}
+	for _, child := range children {
+		refs = append(refs, childReference(child))
+	}
+
+	for _, child := range removedChildren(gto.GetStatus().Children, children) {
+		// Never modify children in dry-run mode
+		if farosflags.DryRun {
+			refs = append(refs, childReference(child))
+			continue
+		}
+		err := r.removeChildCopies(gto, child, result.namespaces)
+		if err != nil {
+			refs = append(refs, childReference(child))
+			if result.inSyncError == nil {
+				result.inSyncReason = gittrackobjectutils.ErrorDeletingChild
+				result.inSyncError = fmt.Errorf("unable to remove child %s: %v", childKey(child), err)
+			}
+		}
+	}
+	return refs
+}
+
+// removeChildCopies removes the child, or each copy of it when the
+// ClusterGitTrackObject selects namespaces, as its document has been removed
+// from the data
+func (r *ReconcileGitTrackObject) removeChildCopies(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured, namespaces []string) error {
+	copies := []*unstructured.Unstructured{child}
+	if hasNamespaceSelector(gto) {
+		copies = childrenIn(child, append(deselectedNamespaces(gto.GetStatus().Namespaces, namespaces), namespaces...))
+	}
+	for _, c := range copies {
+		err := r.removeChild(gto, c, "its document was removed from the data")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
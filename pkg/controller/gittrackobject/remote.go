@@ -0,0 +1,273 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittrackobject
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/utils"
+	farosclient "github.com/pusher/faros/pkg/utils/client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// remoteFinalizer is added to (Cluster)GitTrackObjects whose child is applied
+// to another cluster, where it cannot be garbage collected through an owner
+// reference, so that the child is deleted with the (Cluster)GitTrackObject
+const remoteFinalizer = "faros.pusher.com/remote-child"
+
+// remoteOwnerAnnotation records the (Cluster)GitTrackObject managing a child
+// in another cluster in place of an owner reference
+const remoteOwnerAnnotation = "faros.pusher.com/owned-by"
+
+// remoteResyncPeriod is how often children in other clusters are checked, as
+// they are not watched
+const remoteResyncPeriod = 5 * time.Minute
+
+// remoteCluster holds the clients for a cluster children are applied to
+type remoteCluster struct {
+	client          client.Client
+	applier         farosclient.Client
+	resourceVersion string
+}
+
+// getRemoteCluster returns the clients for the cluster in the referenced
+// kubeconfig, building them again whenever the Secret holding it changes
+func (r *ReconcileGitTrackObject) getRemoteCluster(ref farosv1alpha1.KubeConfigSecretReference) (*remoteCluster, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.SecretName}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get secret %s/%s: %v", ref.Namespace, ref.SecretName, err)
+	}
+
+	r.mutex.RLock()
+	cluster, ok := r.remoteClusters[ref]
+	r.mutex.RUnlock()
+	if ok && cluster.resourceVersion == secret.ResourceVersion {
+		return cluster, nil
+	}
+
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s does not have key %s", ref.Namespace, ref.SecretName, ref.Key)
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load kubeconfig: %v", err)
+	}
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %v", err)
+	}
+	applier, err := farosclient.NewApplier(config, farosclient.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create applier: %v", err)
+	}
+
+	cluster = &remoteCluster{client: c, applier: applier, resourceVersion: secret.ResourceVersion}
+	r.mutex.Lock()
+	r.remoteClusters[ref] = cluster
+	r.mutex.Unlock()
+	r.log.V(1).Info("Loaded kubeconfig", "secret", fmt.Sprintf("%s/%s", ref.Namespace, ref.SecretName))
+	return cluster, nil
+}
+
+// forCluster returns a copy of the reconciler that reads and applies children
+// in the remote cluster
+func (r *ReconcileGitTrackObject) forCluster(cluster *remoteCluster) *ReconcileGitTrackObject {
+	r.mutex.RLock()
+	reconciler := *r
+	r.mutex.RUnlock()
+	reconciler.Client = cluster.client
+	reconciler.applier = cluster.applier
+	return &reconciler
+}
+
+// handleRemoteGitTrackObject applies the child of the (Cluster)GitTrackObject
+// to the cluster in its kubeconfig
+func (r *ReconcileGitTrackObject) handleRemoteGitTrackObject(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) handlerResult {
+	result := r.syncRemoteChild(gto, child)
+	// Children in other clusters are not watched, so check them again later
+	result.requeueAfter = remoteResyncPeriod
+	return result
+}
+
+// syncRemoteChild creates or updates the child in the remote cluster
+func (r *ReconcileGitTrackObject) syncRemoteChild(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) handlerResult {
+	// Make sure the child is deleted with the (Cluster)GitTrackObject
+	if !utils.HasFinalizer(gto, remoteFinalizer) {
+		gto.SetFinalizers(append(gto.GetFinalizers(), remoteFinalizer))
+		if err := r.Update(context.TODO(), gto); err != nil {
+			return handlerResult{
+				inSyncReason: gittrackobjectutils.ErrorUpdatingFinalizer,
+				inSyncError:  fmt.Errorf("unable to update finalizers for child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+			}
+		}
+	}
+
+	cluster, err := r.getRemoteCluster(*gto.GetSpec().KubeConfigSecretRef)
+	if err != nil {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorLoadingKubeConfig,
+			inSyncError:  fmt.Errorf("unable to connect to cluster for child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+	remote := r.forCluster(cluster)
+
+	// Owner references cannot point to another cluster, so the owner is
+	// recorded in an annotation instead
+	setRemoteOwner(gto, child)
+
+	found := &unstructured.Unstructured{}
+	found.SetKind(child.GetKind())
+	found.SetAPIVersion(child.GetAPIVersion())
+	err = remote.Get(context.TODO(), types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}, found)
+	if err != nil && errors.IsNotFound(err) {
+		if farosflags.DryRun {
+			return remote.handleDryRunCreate(gto, child)
+		}
+		reason, err := remote.handleCreate(gto, child)
+		if err != nil {
+			return handlerResult{
+				inSyncReason: reason,
+				inSyncError:  fmt.Errorf("error creating child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+			}
+		}
+		health, detail := gittrackobjectutils.GetHealth(child)
+		return handlerResult{
+			health:        health,
+			healthDetail:  detail,
+			recreateToken: gittrackobjectutils.PendingRecreate(gto.GetStatus().LastRecreateToken, gto),
+		}
+	} else if err != nil {
+		return handlerResult{
+			inSyncReason: gittrackobjectutils.ErrorGettingChild,
+			inSyncError:  fmt.Errorf("unable to get child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+		}
+	}
+
+	// Only take over existing children managed by something else if asked to
+	previousOwner := found.GetAnnotations()[remoteOwnerAnnotation]
+	takeOver := previousOwner != gto.GetNamespacedName()
+	if takeOver {
+		reason, err := remote.handleAdopt(gto, child)
+		if err != nil {
+			return handlerResult{
+				inSyncReason: reason,
+				inSyncError:  fmt.Errorf("error adopting child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err),
+			}
+		}
+	}
+
+	token := gittrackobjectutils.PendingRecreate(gto.GetStatus().LastRecreateToken, gto, found)
+	if token != "" && !farosflags.DryRun {
+		return remote.handleForceRecreate(gto, found, child, "", token)
+	}
+
+	// The `never` update strategy only sets the owner reference, which
+	// children in other clusters do not have
+	updateStrategy, err := gittrackobjectutils.GetUpdateStrategy(child)
+	if err == nil && updateStrategy == gittrackobjectutils.NeverUpdateStrategy {
+		health, detail := gittrackobjectutils.GetHealth(found)
+		return handlerResult{health: health, healthDetail: detail}
+	}
+
+	result := remote.handleUpdate(gto, found, child)
+	result.health, result.healthDetail = gittrackobjectutils.GetHealth(found)
+	if takeOver && result.inSyncError == nil && result.dryRunDiff == "" {
+		remote.sendOwnershipEvent(gto, child, previousOwner)
+	}
+	return result
+}
+
+// setRemoteOwner annotates the child with the (Cluster)GitTrackObject
+// managing it
+func setRemoteOwner(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) {
+	annotations := child.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[remoteOwnerAnnotation] = gto.GetNamespacedName()
+	child.SetAnnotations(annotations)
+}
+
+// handleRemoteDeletion deletes the child from the remote cluster, unless it
+// should be orphaned, before removing the remote finalizer
+func (r *ReconcileGitTrackObject) handleRemoteDeletion(gto farosv1alpha1.GitTrackObjectInterface) error {
+	children, _, err := r.getChildrenFromGitTrackObject(gto)
+	if err == nil && gto.GetSpec().KubeConfigSecretRef != nil {
+		orphan, err := shouldOrphanChildren(gto, children)
+		if err != nil {
+			return fmt.Errorf("unable to delete child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err)
+		}
+		for _, child := range children {
+			if orphan {
+				r.sendEvent(gto, corev1.EventTypeNormal, "Orphaned", "Orphaned child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+				continue
+			}
+			err = r.deleteRemoteChild(gto, child)
+			if err != nil {
+				return fmt.Errorf("unable to delete child %s %s: %v", gto.GetSpec().Kind, gto.GetSpec().Name, err)
+			}
+		}
+	}
+
+	gto.SetFinalizers(utils.RemoveFinalizer(gto.GetFinalizers(), remoteFinalizer))
+	err = r.Update(context.TODO(), gto)
+	if err != nil {
+		return fmt.Errorf("unable to remove finalizer: %v", err)
+	}
+	return nil
+}
+
+// deleteRemoteChild deletes the child from the remote cluster if it is still
+// managed by the (Cluster)GitTrackObject
+func (r *ReconcileGitTrackObject) deleteRemoteChild(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) error {
+	cluster, err := r.getRemoteCluster(*gto.GetSpec().KubeConfigSecretRef)
+	if err != nil {
+		return err
+	}
+	found := &unstructured.Unstructured{}
+	found.SetKind(child.GetKind())
+	found.SetAPIVersion(child.GetAPIVersion())
+	err = cluster.client.Get(context.TODO(), types.NamespacedName{Name: child.GetName(), Namespace: child.GetNamespace()}, found)
+	if err != nil && errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if found.GetAnnotations()[remoteOwnerAnnotation] != gto.GetNamespacedName() {
+		return nil
+	}
+
+	err = deleteChild(cluster.client, gto, found, child)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	r.log.V(0).Info("Child deleted from remote cluster")
+	r.sendEvent(gto, corev1.EventTypeNormal, "DeleteSuccessful", "Deleted child %s %s/%s", child.GetKind(), child.GetNamespace(), child.GetName())
+	return nil
+}
@@ -24,23 +24,85 @@ import (
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type statusOpts struct {
 	inSyncError  error
 	inSyncReason gittrackobjectutils.ConditionReason
+	dryRunDiff   string
+	health       gittrackobjectutils.Health
+	healthDetail string
+	lastUpdate   *farosv1alpha1.ChildUpdate
+
+	childHash            string
+	childResourceVersion string
+	childStates          map[string]farosv1alpha1.ChildState
+	recreateToken        string
+
+	// namespaces the child has been applied to, only set when the
+	// ClusterGitTrackObject selects namespaces
+	namespaces []string
+
+	// children applied from the data, left unchanged in the status if nil
+	children []farosv1alpha1.ChildReference
+
+	// appliedHash is the hash of the data of the (Cluster)GitTrackObject,
+	// set once its child has been successfully applied
+	appliedHash string
+
+	// lastAppliedData is the compressed children applied, recorded with the
+	// appliedHash when the configuration of a child is too large for its last
+	// applied annotation
+	lastAppliedData []byte
+
+	// waitForReady is set when the child is only in sync once it is ready
+	waitForReady bool
 }
 
 func (s *statusOpts) isEmpty() bool {
-	empty := &statusOpts{}
-	return *s == *empty
+	return s.inSyncError == nil && s.inSyncReason == "" && s.dryRunDiff == ""
+}
+
+// waitingForReady returns whether the child has been applied but is not yet
+// in sync because it is not ready
+func (s *statusOpts) waitingForReady() bool {
+	return s.waitForReady && s.inSyncError == nil && s.dryRunDiff == "" && s.health != gittrackobjectutils.HealthReady
 }
 
 // updateGitTrackObjectStatus updates the GitTrackObject's status field if
 // any condition has changed.
 func updateGitTrackObjectStatus(gto farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) bool {
 	status := gto.GetStatus()
-	setCondition(&status, farosv1alpha1.ObjectInSyncType, opts.inSyncError, opts.inSyncReason)
+	status.ObservedGeneration = gto.GetGeneration()
+	status.DryRunDiff = opts.dryRunDiff
+	if opts.dryRunDiff != "" {
+		setCondition(&status, farosv1alpha1.ObjectInSyncType, fmt.Errorf("child differs from desired state"), gittrackobjectutils.ChildDryRunDiff)
+	} else if opts.waitingForReady() {
+		setCondition(&status, farosv1alpha1.ObjectInSyncType, fmt.Errorf("waiting for child to become ready: %s", opts.healthDetail), gittrackobjectutils.ChildNotReady)
+	} else {
+		setCondition(&status, farosv1alpha1.ObjectInSyncType, opts.inSyncError, opts.inSyncReason)
+	}
+	setHealthCondition(&status, opts.health, opts.healthDetail)
+	setSuspended(&status, gto.GetSpec().Suspend)
+	if opts.lastUpdate != nil {
+		status.LastUpdate = opts.lastUpdate
+	}
+	status.ChildHash = opts.childHash
+	status.ChildResourceVersion = opts.childResourceVersion
+	status.ChildStates = opts.childStates
+	if opts.recreateToken != "" {
+		status.LastRecreateToken = opts.recreateToken
+	}
+	setApplied(&status, opts)
+	if opts.children != nil {
+		status.Children = opts.children
+	}
+	if !hasNamespaceSelector(gto) {
+		status.Namespaces = nil
+	} else if opts.namespaces != nil {
+		status.Namespaces = opts.namespaces
+	}
 
 	if !reflect.DeepEqual(gto.GetStatus(), status) {
 		gto.SetStatus(status)
@@ -58,6 +120,7 @@ func setCondition(status *farosv1alpha1.GitTrackObjectStatus, condType farosv1al
 			reason,
 			condErr.Error(),
 		)
+		cond.ObservedGeneration = status.ObservedGeneration
 		gittrackobjectutils.SetGitTrackObjectCondition(status, *cond)
 		return
 	}
@@ -69,9 +132,76 @@ func setCondition(status *farosv1alpha1.GitTrackObjectStatus, condType farosv1al
 		reason,
 		"",
 	)
+	cond.ObservedGeneration = status.ObservedGeneration
 	gittrackobjectutils.SetGitTrackObjectCondition(status, *cond)
 }
 
+// setApplied records the hash of the data applied to the child, along with
+// the children applied if they are needed as a baseline. The time of
+// the apply is only moved on when the child was written or the data changed,
+// so that resyncs of an unchanged child do not update the status.
+func setApplied(status *farosv1alpha1.GitTrackObjectStatus, opts *statusOpts) {
+	if opts.appliedHash == "" {
+		return
+	}
+	if status.LastAppliedTime == nil || status.AppliedHash != opts.appliedHash || opts.lastUpdate != nil || opts.recreateToken != "" {
+		now := metav1.Now()
+		status.LastAppliedTime = &now
+	}
+	status.AppliedHash = opts.appliedHash
+	status.LastAppliedData = opts.lastAppliedData
+}
+
+// setHealthCondition sets the ChildHealthy condition if the health of the
+// child is known
+func setHealthCondition(status *farosv1alpha1.GitTrackObjectStatus, health gittrackobjectutils.Health, detail string) {
+	switch health {
+	case gittrackobjectutils.HealthReady:
+		setCondition(status, farosv1alpha1.ChildHealthyType, nil, gittrackobjectutils.ChildReady)
+	case gittrackobjectutils.HealthProgressing:
+		setCondition(status, farosv1alpha1.ChildHealthyType, fmt.Errorf("%s", detail), gittrackobjectutils.ChildProgressing)
+	case gittrackobjectutils.HealthFailed:
+		setCondition(status, farosv1alpha1.ChildHealthyType, fmt.Errorf("%s", detail), gittrackobjectutils.ChildFailed)
+	}
+}
+
+// setSuspended sets the Suspended condition if the GitTrackObject is suspended
+// and removes it otherwise
+func setSuspended(status *farosv1alpha1.GitTrackObjectStatus, suspended bool) {
+	if !suspended {
+		gittrackobjectutils.RemoveGitTrackObjectCondition(status, farosv1alpha1.ObjectSuspendedType)
+		return
+	}
+	cond := gittrackobjectutils.NewGitTrackObjectCondition(
+		farosv1alpha1.ObjectSuspendedType,
+		v1.ConditionTrue,
+		gittrackobjectutils.SuspendedBySpec,
+		"managing the child is suspended by spec.suspend",
+	)
+	cond.ObservedGeneration = status.ObservedGeneration
+	gittrackobjectutils.SetGitTrackObjectCondition(status, *cond)
+}
+
+// updateSuspendedStatus sets the Suspended condition of the GitTrackObject,
+// leaving the rest of its status as it was when its child was last managed
+func (r *ReconcileGitTrackObject) updateSuspendedStatus(original farosv1alpha1.GitTrackObjectInterface) error {
+	gto := original.DeepCopyInterface()
+	status := gto.GetStatus()
+	status.ObservedGeneration = gto.GetGeneration()
+	setSuspended(&status, true)
+	if reflect.DeepEqual(original.GetStatus(), status) {
+		return nil
+	}
+
+	gto.SetStatus(status)
+	err := r.Status().Update(context.TODO(), gto)
+	if err != nil {
+		return fmt.Errorf("unable to update status: %v", err)
+	}
+	r.log.V(1).Info("Parent status updated")
+	return nil
+}
+
 // updateStatus calculates a new status for the GitTrackObject and then updates
 // the resource on the API if the status differs from before.
 func (r *ReconcileGitTrackObject) updateStatus(original farosv1alpha1.GitTrackObjectInterface, opts *statusOpts) error {
@@ -82,7 +212,7 @@ func (r *ReconcileGitTrackObject) updateStatus(original farosv1alpha1.GitTrackOb
 	gto := original.DeepCopyInterface()
 	gtoUpdated := updateGitTrackObjectStatus(gto, opts)
 	if gtoUpdated {
-		err := r.Update(context.TODO(), gto)
+		err := r.Status().Update(context.TODO(), gto)
 		if err != nil {
 			return fmt.Errorf("unable to update status: %v", err)
 		}
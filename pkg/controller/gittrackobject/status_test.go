@@ -25,10 +25,12 @@ import (
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
 	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/test/testenv"
 	testutils "github.com/pusher/faros/test/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/flowcontrol"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -48,10 +50,7 @@ var _ = Describe("Status Suite", func() {
 		// channel when it is finished.
 		var err error
 		cfg.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
-		mgr, err = manager.New(cfg, manager.Options{
-			Namespace:          farosflags.Namespace,
-			MetricsBindAddress: "0", // Disable serving metrics while testing
-		})
+		mgr, err = t.NewManager(farosflags.Namespaces[0])
 		Expect(err).NotTo(HaveOccurred())
 
 		m = testutils.Matcher{Client: mgr.GetClient()}
@@ -60,7 +59,7 @@ var _ = Describe("Status Suite", func() {
 		r = recFn.(*ReconcileGitTrackObject)
 
 		stopInformers = r.StopChan()
-		stop = StartTestManager(mgr)
+		stop = testenv.StartManager(mgr)
 	})
 
 	AfterEach(func() {
@@ -144,6 +143,56 @@ var _ = Describe("Status Suite", func() {
 					)
 				})
 			})
+
+			Context("when waiting for the child to become ready", func() {
+				BeforeEach(func() {
+					opts.waitForReady = true
+				})
+
+				Context("and the child is progressing", func() {
+					BeforeEach(func() {
+						opts.health = gittrackobjectutils.HealthProgressing
+						opts.healthDetail = "0 of 1 replicas available"
+						r.updateStatus(gto, opts)
+					})
+
+					It("should not set the inSync condition", func() {
+						m.Eventually(gto).Should(
+							testutils.WithGitTrackObjectStatusConditions(
+								ContainElement(
+									SatisfyAll(
+										testutils.WithGitTrackObjectConditionType(Equal(farosv1alpha1.ObjectInSyncType)),
+										testutils.WithGitTrackObjectConditionStatus(Equal(corev1.ConditionFalse)),
+										testutils.WithGitTrackObjectConditionReason(Equal(string(gittrackobjectutils.ChildNotReady))),
+										testutils.WithGitTrackObjectConditionMessage(Equal("waiting for child to become ready: 0 of 1 replicas available")),
+									),
+								),
+							),
+						)
+					})
+				})
+
+				Context("and the child is ready", func() {
+					BeforeEach(func() {
+						opts.health = gittrackobjectutils.HealthReady
+						r.updateStatus(gto, opts)
+					})
+
+					It("should set the inSync condition", func() {
+						m.Eventually(gto).Should(
+							testutils.WithGitTrackObjectStatusConditions(
+								ContainElement(
+									SatisfyAll(
+										testutils.WithGitTrackObjectConditionType(Equal(farosv1alpha1.ObjectInSyncType)),
+										testutils.WithGitTrackObjectConditionStatus(Equal(corev1.ConditionTrue)),
+										testutils.WithGitTrackObjectConditionReason(Equal(string(gittrackobjectutils.ChildAppliedSuccess))),
+									),
+								),
+							),
+						)
+					})
+				})
+			})
 		})
 
 		Context("with a ClusterGitTrackObject", func() {
@@ -207,4 +256,66 @@ var _ = Describe("Status Suite", func() {
 			})
 		})
 	})
+
+	Context("updateGitTrackObjectStatus", func() {
+		var gto *farosv1alpha1.GitTrackObject
+		var opts *statusOpts
+
+		BeforeEach(func() {
+			gto = testutils.ExampleGitTrackObject.DeepCopy()
+			opts = &statusOpts{appliedHash: gittrackobjectutils.DataHash(gto.Spec.Data)}
+		})
+
+		It("records the hash and time of the apply", func() {
+			Expect(updateGitTrackObjectStatus(gto, opts)).To(BeTrue())
+			Expect(gto.Status.AppliedHash).To(Equal(opts.appliedHash))
+			Expect(gto.Status.LastAppliedTime).NotTo(BeNil())
+		})
+
+		It("keeps the time of the apply while the child is unchanged", func() {
+			applied := metav1.NewTime(time.Now().Add(-time.Hour))
+			gto.Status.AppliedHash = opts.appliedHash
+			gto.Status.LastAppliedTime = &applied
+			updateGitTrackObjectStatus(gto, opts)
+			Expect(gto.Status.LastAppliedTime).To(Equal(&applied))
+		})
+
+		It("moves the time of the apply on when the child is updated", func() {
+			applied := metav1.NewTime(time.Now().Add(-time.Hour))
+			gto.Status.AppliedHash = opts.appliedHash
+			gto.Status.LastAppliedTime = &applied
+			opts.lastUpdate = &farosv1alpha1.ChildUpdate{Time: metav1.Now()}
+			updateGitTrackObjectStatus(gto, opts)
+			Expect(gto.Status.LastAppliedTime.After(applied.Time)).To(BeTrue())
+		})
+
+		It("keeps the last apply when the child fails to sync", func() {
+			applied := metav1.NewTime(time.Now().Add(-time.Hour))
+			gto.Status.AppliedHash = "sha256:abc"
+			gto.Status.LastAppliedTime = &applied
+			opts = &statusOpts{inSyncError: fmt.Errorf("error"), inSyncReason: gittrackobjectutils.ErrorUpdatingChild}
+			updateGitTrackObjectStatus(gto, opts)
+			Expect(gto.Status.AppliedHash).To(Equal("sha256:abc"))
+			Expect(gto.Status.LastAppliedTime).To(Equal(&applied))
+		})
+
+		It("records the children applied", func() {
+			opts.lastAppliedData = []byte("children")
+			updateGitTrackObjectStatus(gto, opts)
+			Expect(gto.Status.LastAppliedData).To(Equal([]byte("children")))
+		})
+
+		It("clears the children applied once they are no longer needed", func() {
+			gto.Status.LastAppliedData = []byte("children")
+			updateGitTrackObjectStatus(gto, opts)
+			Expect(gto.Status.LastAppliedData).To(BeNil())
+		})
+
+		It("keeps the children applied when the child fails to sync", func() {
+			gto.Status.LastAppliedData = []byte("children")
+			opts = &statusOpts{inSyncError: fmt.Errorf("error"), inSyncReason: gittrackobjectutils.ErrorUpdatingChild}
+			updateGitTrackObjectStatus(gto, opts)
+			Expect(gto.Status.LastAppliedData).To(Equal([]byte("children")))
+		})
+	})
 })
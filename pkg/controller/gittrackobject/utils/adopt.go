@@ -0,0 +1,49 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AdoptAnnotation allows Faros to take ownership of a child that already
+// exists in the cluster without an owner
+const AdoptAnnotation = "faros.pusher.com/adopt"
+
+// ShouldAdopt returns the value of the first `faros.pusher.com/adopt`
+// annotation found on the given objects, or false if none of them have one
+func ShouldAdopt(objs ...metav1.Object) (bool, error) {
+	return getBoolAnnotation(AdoptAnnotation, objs...)
+}
+
+// getBoolAnnotation returns the boolean value of the first instance of the
+// given annotation found on the objects, or false if none of them have it
+func getBoolAnnotation(annotation string, objs ...metav1.Object) (bool, error) {
+	for _, obj := range objs {
+		if data, ok := obj.GetAnnotations()[annotation]; ok {
+			value, err := strconv.ParseBool(data)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s annotation %q: %v", annotation, data, err)
+			}
+			return value, nil
+		}
+	}
+	return false, nil
+}
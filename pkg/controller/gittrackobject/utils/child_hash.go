@@ -0,0 +1,49 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ChildHash returns a hash of the desired state of the child together with
+// the annotations of the (Cluster)GitTrackObject which affect how the child is
+// applied
+func ChildHash(gto metav1.Object, child *unstructured.Unstructured) (string, error) {
+	data, err := json.Marshal(struct {
+		Annotations map[string]string      `json:"annotations"`
+		Child       map[string]interface{} `json:"child"`
+	}{
+		Annotations: gto.GetAnnotations(),
+		Child:       child.Object,
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal child: %v", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// DataHash returns the SHA-256 hash of the data of a (Cluster)GitTrackObject,
+// in the form recorded in its status once the data has been applied
+func DataHash(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("ChildHash Suite", func() {
+	var gto *metav1.ObjectMeta
+	var child *unstructured.Unstructured
+	var hash string
+
+	BeforeEach(func() {
+		gto = &metav1.ObjectMeta{}
+		child = &unstructured.Unstructured{}
+		child.SetAPIVersion("v1")
+		child.SetKind("ConfigMap")
+		child.SetName("example")
+
+		var err error
+		hash, err = ChildHash(gto, child)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns the same hash for the same child", func() {
+		Expect(ChildHash(gto, child.DeepCopy())).To(Equal(hash))
+	})
+
+	It("returns a different hash when the child changes", func() {
+		child.SetLabels(map[string]string{"foo": "bar"})
+		Expect(ChildHash(gto, child)).NotTo(Equal(hash))
+	})
+
+	It("returns a different hash when the annotations of the GitTrackObject change", func() {
+		gto.SetAnnotations(map[string]string{ignorePathsAnnotation: "/spec/replicas"})
+		Expect(ChildHash(gto, child)).NotTo(Equal(hash))
+	})
+
+	It("returns the hash of the data", func() {
+		Expect(DataHash([]byte("foo"))).To(Equal("sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"))
+	})
+})
@@ -27,6 +27,44 @@ const (
 	// applying the child object
 	ChildAppliedSuccess ConditionReason = "ChildAppliedSuccess"
 
+	// ChildDryRunDiff represents the condition reason when the child has the
+	// dry-run update strategy and differs from its desired state
+	ChildDryRunDiff ConditionReason = "ChildDryRunDiff"
+
+	// ChildAlreadyExists represents the condition reason when the child
+	// already exists without an owner and has not been marked for adoption
+	ChildAlreadyExists ConditionReason = "ChildAlreadyExists"
+
+	// ChildNotAllowed represents the condition reason when the child's
+	// resource is not one the controller is allowed to manage
+	ChildNotAllowed ConditionReason = "ChildNotAllowed"
+
+	// ChildReady represents the condition reason when the child has reached
+	// its desired state
+	ChildReady ConditionReason = "ChildReady"
+
+	// ChildProgressing represents the condition reason when the child is
+	// working towards its desired state
+	ChildProgressing ConditionReason = "ChildProgressing"
+
+	// ChildNotReady represents the condition reason when the child has been
+	// applied but the (Cluster)GitTrackObject waits for it to become ready
+	// before it is in sync
+	ChildNotReady ConditionReason = "ChildNotReady"
+
+	// ChildConflict represents the condition reason when the child keeps
+	// being changed back by another controller and updates to it are held
+	// back
+	ChildConflict ConditionReason = "ChildConflict"
+
+	// ChildFailed represents the condition reason when the child will not
+	// reach its desired state without intervention
+	ChildFailed ConditionReason = "ChildFailed"
+
+	// SuspendedBySpec represents the condition reason when managing the child
+	// has been suspended by spec.suspend
+	SuspendedBySpec ConditionReason = "SuspendedBySpec"
+
 	// ErrorAddingOwnerReference represents the condition reason when the child's
 	// Owner reference cannot be set
 	ErrorAddingOwnerReference ConditionReason = "ErrorAddingOwnerReference"
@@ -47,9 +85,26 @@ const (
 	// hits an error trying to update the child
 	ErrorUpdatingChild ConditionReason = "ErrorUpdatingChild"
 
+	// ErrorUpdatingFinalizer represents the condition reason when the
+	// controller cannot update the finalizers of the (Cluster)GitTrackObject
+	ErrorUpdatingFinalizer ConditionReason = "ErrorUpdatingFinalizer"
+
 	// ErrorWatchingChild represents the condition reason when the controller
 	// cannot create an informer for the child's kind
 	ErrorWatchingChild ConditionReason = "ErrorWatchingChild"
+
+	// ErrorLoadingKubeConfig represents the condition reason when the
+	// controller cannot connect to the cluster the child is applied to
+	ErrorLoadingKubeConfig ConditionReason = "ErrorLoadingKubeConfig"
+
+	// ErrorSelectingNamespaces represents the condition reason when the
+	// controller cannot select the namespaces the child is applied to
+	ErrorSelectingNamespaces ConditionReason = "ErrorSelectingNamespaces"
+
+	// ErrorDeletingChild represents the condition reason when the controller
+	// hits an error trying to delete the child from a namespace that is no
+	// longer selected
+	ErrorDeletingChild ConditionReason = "ErrorDeletingChild"
 )
 
 // ConditionReason represents a valid condition reason
@@ -78,19 +133,29 @@ func GetGitTrackObjectCondition(status farosv1alpha1.GitTrackObjectStatus, condT
 	return nil
 }
 
-// SetGitTrackObjectCondition updates the GitTrackObject to include the provided condition. If the condition that
-// we are about to add already exists and has the same status and reason then we are not going to update.
+// SetGitTrackObjectCondition updates the GitTrackObject to include the provided condition, following the same
+// semantics as meta.SetStatusCondition. An existing condition is updated in place, keeping its
+// lastTransitionTime unless the status changes. If the condition is unchanged then we are not going to update.
 func SetGitTrackObjectCondition(status *farosv1alpha1.GitTrackObjectStatus, condition farosv1alpha1.GitTrackObjectCondition) {
-	currentCond := GetGitTrackObjectCondition(*status, condition.Type)
-	if currentCond != nil && currentCond.Status == condition.Status && currentCond.Reason == condition.Reason {
+	for i := range status.Conditions {
+		currentCond := &status.Conditions[i]
+		if currentCond.Type != condition.Type {
+			continue
+		}
+		if currentCond.Status == condition.Status &&
+			currentCond.Reason == condition.Reason &&
+			currentCond.Message == condition.Message &&
+			currentCond.ObservedGeneration == condition.ObservedGeneration {
+			return
+		}
+		// Do not update lastTransitionTime if the status of the condition doesn't change.
+		if currentCond.Status == condition.Status {
+			condition.LastTransitionTime = currentCond.LastTransitionTime
+		}
+		*currentCond = condition
 		return
 	}
-	// Do not update lastTransitionTime if the status of the condition doesn't change.
-	if currentCond != nil && currentCond.Status == condition.Status {
-		condition.LastTransitionTime = currentCond.LastTransitionTime
-	}
-	newConditions := filterOutCondition(status.Conditions, condition.Type)
-	status.Conditions = append(newConditions, condition)
+	status.Conditions = append(status.Conditions, condition)
 }
 
 // RemoveGitTrackObjectCondition removes the GitTrackObject condition with the provided type.
@@ -0,0 +1,90 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Conditions Suite", func() {
+	var status *farosv1alpha1.GitTrackObjectStatus
+	var transitionTime metav1.Time
+
+	BeforeEach(func() {
+		transitionTime = metav1.NewTime(time.Now().Add(-time.Hour))
+		status = &farosv1alpha1.GitTrackObjectStatus{
+			Conditions: []farosv1alpha1.GitTrackObjectCondition{
+				{
+					Type:               farosv1alpha1.ObjectInSyncType,
+					Status:             v1.ConditionTrue,
+					LastTransitionTime: transitionTime,
+					LastUpdateTime:     transitionTime,
+					Reason:             string(ChildAppliedSuccess),
+					ObservedGeneration: 1,
+				},
+				{
+					Type:   farosv1alpha1.ChildHealthyType,
+					Status: v1.ConditionTrue,
+					Reason: string(ChildReady),
+				},
+			},
+		}
+	})
+
+	Context("SetGitTrackObjectCondition", func() {
+		It("appends new conditions", func() {
+			status.Conditions = nil
+			SetGitTrackObjectCondition(status, *NewGitTrackObjectCondition(farosv1alpha1.ObjectInSyncType, v1.ConditionTrue, ChildAppliedSuccess, ""))
+			Expect(status.Conditions).To(HaveLen(1))
+		})
+
+		It("does not modify unchanged conditions", func() {
+			cond := NewGitTrackObjectCondition(farosv1alpha1.ObjectInSyncType, v1.ConditionTrue, ChildAppliedSuccess, "")
+			cond.ObservedGeneration = 1
+			SetGitTrackObjectCondition(status, *cond)
+			Expect(status.Conditions[0].LastUpdateTime).To(Equal(transitionTime))
+		})
+
+		It("updates the observed generation in place without a transition", func() {
+			cond := NewGitTrackObjectCondition(farosv1alpha1.ObjectInSyncType, v1.ConditionTrue, ChildAppliedSuccess, "")
+			cond.ObservedGeneration = 2
+			SetGitTrackObjectCondition(status, *cond)
+			Expect(status.Conditions).To(HaveLen(2))
+			Expect(status.Conditions[0].Type).To(Equal(farosv1alpha1.ObjectInSyncType))
+			Expect(status.Conditions[0].ObservedGeneration).To(Equal(int64(2)))
+			Expect(status.Conditions[0].LastTransitionTime).To(Equal(transitionTime))
+		})
+
+		It("updates the message without a transition", func() {
+			SetGitTrackObjectCondition(status, *NewGitTrackObjectCondition(farosv1alpha1.ObjectInSyncType, v1.ConditionTrue, ChildAppliedSuccess, "new message"))
+			Expect(status.Conditions[0].Message).To(Equal("new message"))
+			Expect(status.Conditions[0].LastTransitionTime).To(Equal(transitionTime))
+		})
+
+		It("updates the transition time when the status changes", func() {
+			SetGitTrackObjectCondition(status, *NewGitTrackObjectCondition(farosv1alpha1.ObjectInSyncType, v1.ConditionFalse, ErrorUpdatingChild, "error"))
+			Expect(status.Conditions[0].Status).To(Equal(v1.ConditionFalse))
+			Expect(status.Conditions[0].LastTransitionTime).NotTo(Equal(transitionTime))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+// ChildData returns the data of the (Cluster)GitTrackObject spec, decoded
+// according to its content encoding
+func ChildData(spec farosv1alpha1.GitTrackObjectSpec) ([]byte, error) {
+	switch spec.ContentEncoding {
+	case "":
+		return spec.Data, nil
+	case farosv1alpha1.ContentEncodingGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(spec.Data))
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress data: %v", err)
+		}
+		defer reader.Close()
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decompress data: %v", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", spec.ContentEncoding)
+	}
+}
+
+// SetChildData sets the data of the (Cluster)GitTrackObject spec, encoded
+// according to its content encoding
+func SetChildData(spec *farosv1alpha1.GitTrackObjectSpec, data []byte) error {
+	switch spec.ContentEncoding {
+	case "":
+		spec.Data = data
+		return nil
+	case farosv1alpha1.ContentEncodingGzip:
+		var buf bytes.Buffer
+		writer, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return fmt.Errorf("unable to compress data: %v", err)
+		}
+		_, err = writer.Write(data)
+		if err != nil {
+			return fmt.Errorf("unable to compress data: %v", err)
+		}
+		err = writer.Close()
+		if err != nil {
+			return fmt.Errorf("unable to compress data: %v", err)
+		}
+		spec.Data = buf.Bytes()
+		return nil
+	default:
+		return fmt.Errorf("unsupported content encoding %q", spec.ContentEncoding)
+	}
+}
+
+// CompressData gzips the data of the (Cluster)GitTrackObject spec if it is
+// larger than the threshold in bytes, a threshold of 0 disables compression.
+// The same data always compresses to the same bytes, so compressed
+// (Cluster)GitTrackObjects are only updated when their data changes.
+func CompressData(spec *farosv1alpha1.GitTrackObjectSpec, threshold int) error {
+	if threshold <= 0 || spec.ContentEncoding != "" || len(spec.Data) <= threshold {
+		return nil
+	}
+	spec.ContentEncoding = farosv1alpha1.ContentEncodingGzip
+	return SetChildData(spec, spec.Data)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+var _ = Describe("ContentEncoding Suite", func() {
+	var spec *farosv1alpha1.GitTrackObjectSpec
+	var data []byte
+
+	BeforeEach(func() {
+		data = bytes.Repeat([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"example"}}`), 10)
+		spec = &farosv1alpha1.GitTrackObjectSpec{Data: data}
+	})
+
+	Context("CompressData", func() {
+		It("leaves data below the threshold uncompressed", func() {
+			Expect(CompressData(spec, len(data))).To(Succeed())
+			Expect(spec.ContentEncoding).To(BeEmpty())
+			Expect(spec.Data).To(Equal(data))
+		})
+
+		It("leaves data uncompressed when the threshold is 0", func() {
+			Expect(CompressData(spec, 0)).To(Succeed())
+			Expect(spec.ContentEncoding).To(BeEmpty())
+			Expect(spec.Data).To(Equal(data))
+		})
+
+		It("gzips data above the threshold", func() {
+			Expect(CompressData(spec, 100)).To(Succeed())
+			Expect(spec.ContentEncoding).To(Equal(farosv1alpha1.ContentEncodingGzip))
+			Expect(len(spec.Data)).To(BeNumerically("<", len(data)))
+			Expect(ChildData(*spec)).To(Equal(data))
+		})
+
+		It("compresses the same data to the same bytes", func() {
+			other := spec.DeepCopy()
+			Expect(CompressData(spec, 100)).To(Succeed())
+			Expect(CompressData(other, 100)).To(Succeed())
+			Expect(other.Data).To(Equal(spec.Data))
+		})
+	})
+
+	Context("ChildData", func() {
+		It("returns uncompressed data unchanged", func() {
+			Expect(ChildData(*spec)).To(Equal(data))
+		})
+
+		It("returns an error for invalid gzip data", func() {
+			spec.ContentEncoding = farosv1alpha1.ContentEncodingGzip
+			_, err := ChildData(*spec)
+			Expect(err).To(MatchError(ContainSubstring("unable to decompress data")))
+		})
+
+		It("returns an error for unknown encodings", func() {
+			spec.ContentEncoding = "br"
+			_, err := ChildData(*spec)
+			Expect(err).To(MatchError(`unsupported content encoding "br"`))
+		})
+	})
+})
@@ -0,0 +1,59 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DataConfigMapKey is the key of the binaryData of the ConfigMaps holding the
+// data of (Cluster)GitTrackObjects that is too large to be held by the
+// (Cluster)GitTrackObject itself
+const DataConfigMapKey = "data"
+
+// ReadData returns the decoded data of the (Cluster)GitTrackObject spec,
+// reading it from the ConfigMaps it references if it is stored outside of the
+// (Cluster)GitTrackObject
+func ReadData(c client.Reader, spec farosv1alpha1.GitTrackObjectSpec) ([]byte, error) {
+	if spec.DataFrom == nil {
+		return ChildData(spec)
+	}
+
+	var buf bytes.Buffer
+	for _, name := range spec.DataFrom.ConfigMaps {
+		cm := &corev1.ConfigMap{}
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: spec.DataFrom.Namespace, Name: name}, cm)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get ConfigMap %s/%s: %v", spec.DataFrom.Namespace, name, err)
+		}
+		buf.Write(cm.BinaryData[DataConfigMapKey])
+	}
+	// The ConfigMaps are written before the (Cluster)GitTrackObject is updated
+	// to reference them, so they may briefly hold newer data
+	if hash := DataHash(buf.Bytes()); hash != spec.DataFrom.Hash {
+		return nil, fmt.Errorf("data in ConfigMaps has hash %s, expected %s", hash, spec.DataFrom.Hash)
+	}
+	spec.Data = buf.Bytes()
+	return ChildData(spec)
+}
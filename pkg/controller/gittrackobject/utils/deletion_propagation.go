@@ -0,0 +1,64 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const deletionPropagationAnnotation = "faros.pusher.com/deletion-propagation"
+
+// GetDeletionPropagationPolicy returns the value of the
+// `faros.pusher.com/deletion-propagation` annotation, or nil if one doesn't
+// exist
+func GetDeletionPropagationPolicy(obj *unstructured.Unstructured) (*metav1.DeletionPropagation, error) {
+	annotations := obj.GetAnnotations()
+	if data, ok := annotations[deletionPropagationAnnotation]; ok {
+		return validDeletionPropagationPolicy(metav1.DeletionPropagation(data))
+	}
+	return nil, nil
+}
+
+// DeletionPropagationPolicy returns the propagation policy to delete the child
+// of the (Cluster)GitTrackObject with: the value of the child's
+// `faros.pusher.com/deletion-propagation` annotation, or else the policy set
+// on the (Cluster)GitTrackObject by its GitTrack, or nil if neither is set
+func DeletionPropagationPolicy(gto farosv1alpha1.GitTrackObjectInterface, child *unstructured.Unstructured) (*metav1.DeletionPropagation, error) {
+	policy, err := GetDeletionPropagationPolicy(child)
+	if err != nil || policy != nil {
+		return policy, err
+	}
+	if p := gto.GetSpec().DeletionPropagation; p != "" {
+		return validDeletionPropagationPolicy(p)
+	}
+	return nil, nil
+}
+
+// validDeletionPropagationPolicy returns whether a given deletion propagation
+// policy is valid or not
+func validDeletionPropagationPolicy(p metav1.DeletionPropagation) (*metav1.DeletionPropagation, error) {
+	switch p {
+	case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+		return &p, nil
+	default:
+		return nil, fmt.Errorf("invalid deletion propagation policy: %s", p)
+	}
+}
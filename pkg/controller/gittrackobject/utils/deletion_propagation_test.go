@@ -0,0 +1,96 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("DeletionPropagation Suite", func() {
+	var child *unstructured.Unstructured
+
+	BeforeEach(func() {
+		child = &unstructured.Unstructured{}
+	})
+
+	Context("when no annotation is set", func() {
+		It("returns no policy", func() {
+			policy, err := GetDeletionPropagationPolicy(child)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(BeNil())
+		})
+	})
+
+	Context("when the annotation is set to Background", func() {
+		BeforeEach(func() {
+			child.SetAnnotations(map[string]string{deletionPropagationAnnotation: "Background"})
+		})
+
+		It("returns the Background policy", func() {
+			policy, err := GetDeletionPropagationPolicy(child)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).NotTo(BeNil())
+			Expect(*policy).To(Equal(metav1.DeletePropagationBackground))
+		})
+	})
+
+	Context("when the annotation is invalid", func() {
+		BeforeEach(func() {
+			child.SetAnnotations(map[string]string{deletionPropagationAnnotation: "background"})
+		})
+
+		It("returns an error", func() {
+			_, err := GetDeletionPropagationPolicy(child)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("DeletionPropagationPolicy", func() {
+		var gto *farosv1alpha1.GitTrackObject
+
+		BeforeEach(func() {
+			gto = &farosv1alpha1.GitTrackObject{}
+		})
+
+		It("returns no policy when neither is set", func() {
+			policy, err := DeletionPropagationPolicy(gto, child)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(BeNil())
+		})
+
+		It("returns the policy of the GitTrackObject", func() {
+			gto.Spec.DeletionPropagation = metav1.DeletePropagationBackground
+			policy, err := DeletionPropagationPolicy(gto, child)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).NotTo(BeNil())
+			Expect(*policy).To(Equal(metav1.DeletePropagationBackground))
+		})
+
+		It("prefers the annotation of the child", func() {
+			gto.Spec.DeletionPropagation = metav1.DeletePropagationBackground
+			child.SetAnnotations(map[string]string{deletionPropagationAnnotation: "Orphan"})
+			policy, err := DeletionPropagationPolicy(gto, child)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).NotTo(BeNil())
+			Expect(*policy).To(Equal(metav1.DeletePropagationOrphan))
+		})
+	})
+})
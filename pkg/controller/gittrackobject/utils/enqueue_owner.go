@@ -32,7 +32,9 @@ var _ handler.EventHandler = &EnqueueRequestForOwner{}
 // EnqueueRequestForOwner enqueues Requests for the Owners of an object.  E.g. the object that created
 // the object that was the source of the Event.
 //
-// This implementation handles both namespaced and non-namespaced resources
+// This implementation handles both namespaced and non-namespaced resources.
+// Namespaced resources may be owned by either kind of owner, such as the
+// children a ClusterGitTrackObject applies to the namespaces it selects.
 type EnqueueRequestForOwner struct {
 	NamespacedEnqueueRequestForOwner    *handler.EnqueueRequestForOwner
 	NonNamespacedEnqueueRequestForOwner *handler.EnqueueRequestForOwner
@@ -49,9 +51,8 @@ func (e *EnqueueRequestForOwner) Create(evt event.CreateEvent, q workqueue.RateL
 	}
 	if namespaced {
 		e.NamespacedEnqueueRequestForOwner.Create(evt, q)
-	} else {
-		e.NonNamespacedEnqueueRequestForOwner.Create(evt, q)
 	}
+	e.NonNamespacedEnqueueRequestForOwner.Create(evt, q)
 }
 
 // Update implements EventHandler
@@ -63,9 +64,8 @@ func (e *EnqueueRequestForOwner) Update(evt event.UpdateEvent, q workqueue.RateL
 	}
 	if namespaced {
 		e.NamespacedEnqueueRequestForOwner.Update(evt, q)
-	} else {
-		e.NonNamespacedEnqueueRequestForOwner.Update(evt, q)
 	}
+	e.NonNamespacedEnqueueRequestForOwner.Update(evt, q)
 }
 
 // Delete implements EventHandler
@@ -77,9 +77,8 @@ func (e *EnqueueRequestForOwner) Delete(evt event.DeleteEvent, q workqueue.RateL
 	}
 	if namespaced {
 		e.NamespacedEnqueueRequestForOwner.Delete(evt, q)
-	} else {
-		e.NonNamespacedEnqueueRequestForOwner.Delete(evt, q)
 	}
+	e.NonNamespacedEnqueueRequestForOwner.Delete(evt, q)
 }
 
 // Generic implements EventHandler
@@ -91,9 +90,8 @@ func (e *EnqueueRequestForOwner) Generic(evt event.GenericEvent, q workqueue.Rat
 	}
 	if namespaced {
 		e.NamespacedEnqueueRequestForOwner.Generic(evt, q)
-	} else {
-		e.NonNamespacedEnqueueRequestForOwner.Generic(evt, q)
 	}
+	e.NonNamespacedEnqueueRequestForOwner.Generic(evt, q)
 }
 
 var _ inject.Scheme = &EnqueueRequestForOwner{}
@@ -299,5 +299,19 @@ var _ = Describe("EnqueueRequestForOwner Suite", func() {
 				shouldNotEnqueue,
 			)
 		})
+
+		Context("owned by a non-namespaced owner", func() {
+			t := true
+			clusterOwnedPod := pod.DeepCopy()
+			clusterOwnedPod.OwnerReferences[0].Kind = "ClusterGitTrackObject"
+			clusterOwnedPod.OwnerReferences[0].Controller = &t
+
+			namespacedEvents(
+				clusterOwnedPod,
+				shouldEnqueue,
+				enqueuedItemShouldBeReconcileRequest,
+				nonNamespacedEnqueuedRequestShouldBeFor,
+			)
+		})
 	})
 })
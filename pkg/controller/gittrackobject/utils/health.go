@@ -0,0 +1,152 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Health represents the health of a child resource
+type Health string
+
+const (
+	// HealthReady means the child has reached its desired state
+	HealthReady Health = "Ready"
+
+	// HealthProgressing means the child is working towards its desired state
+	HealthProgressing Health = "Progressing"
+
+	// HealthFailed means the child will not reach its desired state without
+	// intervention
+	HealthFailed Health = "Failed"
+)
+
+// GetHealth computes the health of the child from its status, following the
+// same rules as kstatus. Resources without any status information are
+// considered Ready.
+func GetHealth(obj *unstructured.Unstructured) (Health, string) {
+	generation := obj.GetGeneration()
+	observedGeneration, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if found && observedGeneration < generation {
+		return HealthProgressing, fmt.Sprintf("observed generation %d is behind generation %d", observedGeneration, generation)
+	}
+
+	switch obj.GroupVersionKind().Kind {
+	case "Deployment":
+		return deploymentHealth(obj)
+	case "StatefulSet":
+		return statefulSetHealth(obj)
+	case "Job":
+		return jobHealth(obj)
+	default:
+		return conditionsHealth(obj)
+	}
+}
+
+// deploymentHealth computes the health of a Deployment from its replicas and
+// Progressing condition
+func deploymentHealth(obj *unstructured.Unstructured) (Health, string) {
+	if status, reason := conditionStatus(obj, "Progressing"); status == "False" && reason == "ProgressDeadlineExceeded" {
+		return HealthFailed, "progress deadline exceeded"
+	}
+
+	replicas := specReplicas(obj)
+	updated, _, _ := unstructured.NestedInt64(obj.Object, "status", "updatedReplicas")
+	available, _, _ := unstructured.NestedInt64(obj.Object, "status", "availableReplicas")
+	if updated < replicas {
+		return HealthProgressing, fmt.Sprintf("%d of %d replicas updated", updated, replicas)
+	}
+	if available < replicas {
+		return HealthProgressing, fmt.Sprintf("%d of %d replicas available", available, replicas)
+	}
+	return HealthReady, ""
+}
+
+// statefulSetHealth computes the health of a StatefulSet from its replicas
+// and revisions
+func statefulSetHealth(obj *unstructured.Unstructured) (Health, string) {
+	replicas := specReplicas(obj)
+	ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+	if ready < replicas {
+		return HealthProgressing, fmt.Sprintf("%d of %d replicas ready", ready, replicas)
+	}
+
+	strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type")
+	if strategy == "OnDelete" {
+		return HealthReady, ""
+	}
+	current, _, _ := unstructured.NestedString(obj.Object, "status", "currentRevision")
+	update, _, _ := unstructured.NestedString(obj.Object, "status", "updateRevision")
+	if current != update {
+		return HealthProgressing, fmt.Sprintf("waiting for revision %s to be rolled out", update)
+	}
+	return HealthReady, ""
+}
+
+// jobHealth computes the health of a Job from its Complete and Failed
+// conditions
+func jobHealth(obj *unstructured.Unstructured) (Health, string) {
+	if status, _ := conditionStatus(obj, "Failed"); status == "True" {
+		return HealthFailed, "job failed"
+	}
+	if status, _ := conditionStatus(obj, "Complete"); status == "True" {
+		return HealthReady, ""
+	}
+	return HealthProgressing, "job in progress"
+}
+
+// conditionsHealth computes the health of any other resource from the
+// standard Stalled, Reconciling and Ready conditions
+func conditionsHealth(obj *unstructured.Unstructured) (Health, string) {
+	if status, reason := conditionStatus(obj, "Stalled"); status == "True" {
+		return HealthFailed, fmt.Sprintf("stalled: %s", reason)
+	}
+	if status, reason := conditionStatus(obj, "Reconciling"); status == "True" {
+		return HealthProgressing, fmt.Sprintf("reconciling: %s", reason)
+	}
+	if status, reason := conditionStatus(obj, "Ready"); status == "False" {
+		return HealthProgressing, fmt.Sprintf("not ready: %s", reason)
+	}
+	return HealthReady, ""
+}
+
+// specReplicas returns the desired number of replicas, which defaults to 1
+func specReplicas(obj *unstructured.Unstructured) int64 {
+	replicas, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+	if !found {
+		return 1
+	}
+	return replicas
+}
+
+// conditionStatus returns the status and reason of the condition with the
+// given type, or empty strings if the condition doesn't exist
+func conditionStatus(obj *unstructured.Unstructured, condType string) (string, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != condType {
+			continue
+		}
+		status, _ := condition["status"].(string)
+		reason, _ := condition["reason"].(string)
+		return status, reason
+	}
+	return "", ""
+}
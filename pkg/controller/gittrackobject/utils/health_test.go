@@ -0,0 +1,160 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Health Suite", func() {
+	var obj *unstructured.Unstructured
+
+	var condition = func(condType, status, reason string) interface{} {
+		return map[string]interface{}{"type": condType, "status": status, "reason": reason}
+	}
+
+	Context("with a Deployment", func() {
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"generation": int64(2)},
+				"spec":       map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"updatedReplicas":    int64(2),
+					"availableReplicas":  int64(2),
+				},
+			}}
+		})
+
+		It("is Ready when all replicas are updated and available", func() {
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthReady))
+		})
+
+		It("is Progressing when the generation has not been observed", func() {
+			obj.SetGeneration(3)
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthProgressing))
+		})
+
+		It("is Progressing when replicas are unavailable", func() {
+			Expect(unstructured.SetNestedField(obj.Object, int64(1), "status", "availableReplicas")).To(Succeed())
+			health, detail := GetHealth(obj)
+			Expect(health).To(Equal(HealthProgressing))
+			Expect(detail).To(Equal("1 of 2 replicas available"))
+		})
+
+		It("is Failed when the progress deadline is exceeded", func() {
+			Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+				condition("Progressing", "False", "ProgressDeadlineExceeded"),
+			}, "status", "conditions")).To(Succeed())
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthFailed))
+		})
+	})
+
+	Context("with a StatefulSet", func() {
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "StatefulSet",
+				"spec":       map[string]interface{}{"replicas": int64(1)},
+				"status": map[string]interface{}{
+					"readyReplicas":   int64(1),
+					"currentRevision": "a",
+					"updateRevision":  "a",
+				},
+			}}
+		})
+
+		It("is Ready when all replicas are ready and updated", func() {
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthReady))
+		})
+
+		It("is Progressing when a new revision is rolling out", func() {
+			Expect(unstructured.SetNestedField(obj.Object, "b", "status", "updateRevision")).To(Succeed())
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthProgressing))
+		})
+	})
+
+	Context("with a Job", func() {
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "batch/v1",
+				"kind":       "Job",
+			}}
+		})
+
+		It("is Progressing while running", func() {
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthProgressing))
+		})
+
+		It("is Ready when complete", func() {
+			Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+				condition("Complete", "True", ""),
+			}, "status", "conditions")).To(Succeed())
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthReady))
+		})
+
+		It("is Failed when failed", func() {
+			Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+				condition("Failed", "True", "BackoffLimitExceeded"),
+			}, "status", "conditions")).To(Succeed())
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthFailed))
+		})
+	})
+
+	Context("with a custom resource", func() {
+		BeforeEach(func() {
+			obj = &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Example",
+			}}
+		})
+
+		It("is Ready without any conditions", func() {
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthReady))
+		})
+
+		It("is Progressing when not Ready", func() {
+			Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+				condition("Ready", "False", "Waiting"),
+			}, "status", "conditions")).To(Succeed())
+			health, detail := GetHealth(obj)
+			Expect(health).To(Equal(HealthProgressing))
+			Expect(detail).To(Equal("not ready: Waiting"))
+		})
+
+		It("is Failed when Stalled", func() {
+			Expect(unstructured.SetNestedSlice(obj.Object, []interface{}{
+				condition("Stalled", "True", "InvalidSpec"),
+			}, "status", "conditions")).To(Succeed())
+			health, _ := GetHealth(obj)
+			Expect(health).To(Equal(HealthFailed))
+		})
+	})
+})
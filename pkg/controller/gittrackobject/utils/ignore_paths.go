@@ -0,0 +1,54 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const ignorePathsAnnotation = "faros.pusher.com/ignore-paths"
+
+// GetIgnorePaths returns the JSON paths listed in the
+// `faros.pusher.com/ignore-paths` annotation of each of the given objects
+func GetIgnorePaths(objs ...metav1.Object) ([]string, error) {
+	paths := []string{}
+	seen := make(map[string]struct{})
+	for _, obj := range objs {
+		data, ok := obj.GetAnnotations()[ignorePathsAnnotation]
+		if !ok {
+			continue
+		}
+		for _, path := range strings.Split(data, ",") {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			if !strings.HasPrefix(path, "/") || path == "/" {
+				return nil, fmt.Errorf("invalid ignore path %q: must be a JSON pointer of the form /path/to/field", path)
+			}
+			if _, ok := seen[path]; ok {
+				continue
+			}
+			seen[path] = struct{}{}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("IgnorePaths Suite", func() {
+	var gto, child *metav1.ObjectMeta
+
+	BeforeEach(func() {
+		gto = &metav1.ObjectMeta{}
+		child = &metav1.ObjectMeta{}
+	})
+
+	Context("when no annotation is set", func() {
+		It("returns no paths", func() {
+			paths, err := GetIgnorePaths(gto, child)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paths).To(BeEmpty())
+		})
+	})
+
+	Context("when the annotation is set on both objects", func() {
+		BeforeEach(func() {
+			gto.SetAnnotations(map[string]string{ignorePathsAnnotation: "/spec/replicas, /metadata/annotations/foo"})
+			child.SetAnnotations(map[string]string{ignorePathsAnnotation: "/spec/replicas"})
+		})
+
+		It("returns the union of the paths", func() {
+			paths, err := GetIgnorePaths(gto, child)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(paths).To(Equal([]string{"/spec/replicas", "/metadata/annotations/foo"}))
+		})
+	})
+
+	Context("when a path is not a JSON pointer", func() {
+		BeforeEach(func() {
+			child.SetAnnotations(map[string]string{ignorePathsAnnotation: "spec.replicas"})
+		})
+
+		It("returns an error", func() {
+			_, err := GetIgnorePaths(gto, child)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
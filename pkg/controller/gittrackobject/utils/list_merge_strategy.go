@@ -0,0 +1,59 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const listMergeStrategyAnnotation = "faros.pusher.com/list-merge-strategy"
+
+const (
+	// ReplaceListMergeStrategy represents the default list merge strategy where
+	// lists within custom resources are replaced by the desired list
+	ReplaceListMergeStrategy ListMergeStrategy = "replace"
+	// MergeListMergeStrategy represents the list merge strategy where entries
+	// added to lists within custom resources outside of faros are preserved
+	MergeListMergeStrategy ListMergeStrategy = "merge"
+)
+
+// ListMergeStrategy represents a valid list merge strategy
+type ListMergeStrategy string
+
+// GetListMergeStrategy returns the value of the first
+// `faros.pusher.com/list-merge-strategy` annotation found on the given
+// objects, or the default value if none of them have one
+func GetListMergeStrategy(objs ...metav1.Object) (ListMergeStrategy, error) {
+	for _, obj := range objs {
+		if data, ok := obj.GetAnnotations()[listMergeStrategyAnnotation]; ok {
+			return validListMergeStrategy(ListMergeStrategy(data))
+		}
+	}
+	return ReplaceListMergeStrategy, nil
+}
+
+// validListMergeStrategy returns whether a given list merge strategy is valid or not
+func validListMergeStrategy(s ListMergeStrategy) (ListMergeStrategy, error) {
+	switch s {
+	case ReplaceListMergeStrategy, MergeListMergeStrategy:
+		return s, nil
+	default:
+		return s, fmt.Errorf("invalid list merge strategy: %s", s)
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecreateAnnotation may be set to any value, typically a timestamp, on a
+// (Cluster)GitTrackObject or its child to delete and recreate the child once.
+// Setting a new value requests another recreation.
+const RecreateAnnotation = "faros.pusher.com/recreate"
+
+// PendingRecreate returns the value of the first `faros.pusher.com/recreate`
+// annotation found on the given objects that differs from the value the child
+// was last recreated for, or an empty string if no recreation is pending
+func PendingRecreate(last string, objs ...metav1.Object) string {
+	for _, obj := range objs {
+		if value := obj.GetAnnotations()[RecreateAnnotation]; value != "" && value != last {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Recreate Suite", func() {
+	var gto, child *metav1.ObjectMeta
+
+	BeforeEach(func() {
+		gto = &metav1.ObjectMeta{}
+		child = &metav1.ObjectMeta{}
+	})
+
+	Context("when no annotation is set", func() {
+		It("does not recreate", func() {
+			Expect(PendingRecreate("", gto, child)).To(BeEmpty())
+		})
+	})
+
+	Context("when the annotation is set on the GitTrackObject", func() {
+		BeforeEach(func() {
+			gto.SetAnnotations(map[string]string{RecreateAnnotation: "1546300800"})
+		})
+
+		It("recreates", func() {
+			Expect(PendingRecreate("", gto, child)).To(Equal("1546300800"))
+		})
+
+		It("does not recreate again for the same value", func() {
+			Expect(PendingRecreate("1546300800", gto, child)).To(BeEmpty())
+		})
+	})
+
+	Context("when the annotation is set on the child", func() {
+		BeforeEach(func() {
+			gto.SetAnnotations(map[string]string{RecreateAnnotation: "1546300800"})
+			child.SetAnnotations(map[string]string{RecreateAnnotation: "1546387200"})
+		})
+
+		It("recreates for a new value on either object", func() {
+			Expect(PendingRecreate("1546300800", gto, child)).To(Equal("1546387200"))
+		})
+	})
+})
@@ -34,6 +34,10 @@ const (
 	// RecreateUpdateStrategy represents the update strategy where a resource should
 	// first be deleted and then created again, rather than updated in-place
 	RecreateUpdateStrategy UpdateStrategy = "recreate"
+	// DryRunUpdateStrategy represents the update strategy where the changes
+	// required to update a resource should be computed and reported but the
+	// resource should never be modified
+	DryRunUpdateStrategy UpdateStrategy = "dry-run"
 )
 
 // UpdateStrategy represents a valid update strategy
@@ -49,10 +53,26 @@ func GetUpdateStrategy(obj *unstructured.Unstructured) (UpdateStrategy, error) {
 	return DefaultUpdateStrategy, nil
 }
 
+// SetDefaultUpdateStrategy sets the `faros.pusher.com/update-strategy`
+// annotation to the default value if it is not set, returning whether the
+// object was changed
+func SetDefaultUpdateStrategy(obj *unstructured.Unstructured) bool {
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[updateStrategyAnnotation]; ok {
+		return false
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[updateStrategyAnnotation] = string(DefaultUpdateStrategy)
+	obj.SetAnnotations(annotations)
+	return true
+}
+
 // validUpdateStrategy returns whether a given update strategy is valid or not
 func validUpdateStrategy(s UpdateStrategy) (UpdateStrategy, error) {
 	switch s {
-	case DefaultUpdateStrategy, NeverUpdateStrategy, RecreateUpdateStrategy:
+	case DefaultUpdateStrategy, NeverUpdateStrategy, RecreateUpdateStrategy, DryRunUpdateStrategy:
 		return s, nil
 	default:
 		return s, fmt.Errorf("invalid update strategy: %s", s)
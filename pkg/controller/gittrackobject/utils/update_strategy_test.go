@@ -0,0 +1,53 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("UpdateStrategy Suite", func() {
+	var obj *unstructured.Unstructured
+
+	BeforeEach(func() {
+		obj = &unstructured.Unstructured{}
+		obj.SetName("example")
+	})
+
+	Context("SetDefaultUpdateStrategy", func() {
+		It("sets the default update strategy when none is set", func() {
+			Expect(SetDefaultUpdateStrategy(obj)).To(BeTrue())
+			Expect(obj.GetAnnotations()).To(HaveKeyWithValue(updateStrategyAnnotation, string(DefaultUpdateStrategy)))
+		})
+
+		It("keeps other annotations", func() {
+			obj.SetAnnotations(map[string]string{"example": "true"})
+			Expect(SetDefaultUpdateStrategy(obj)).To(BeTrue())
+			Expect(obj.GetAnnotations()).To(HaveKeyWithValue("example", "true"))
+		})
+
+		It("does not change an update strategy that is already set", func() {
+			obj.SetAnnotations(map[string]string{updateStrategyAnnotation: string(NeverUpdateStrategy)})
+			Expect(SetDefaultUpdateStrategy(obj)).To(BeFalse())
+			strategy, err := GetUpdateStrategy(obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strategy).To(Equal(NeverUpdateStrategy))
+		})
+	})
+})
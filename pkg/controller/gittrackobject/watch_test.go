@@ -24,10 +24,14 @@ import (
 	. "github.com/onsi/gomega"
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/utils"
+	"github.com/pusher/faros/pkg/utils/client/test"
+	"github.com/pusher/faros/test/testenv"
 	testutils "github.com/pusher/faros/test/utils"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -53,10 +57,9 @@ var _ = Describe("Watch Suite", func() {
 		// channel when it is finished.
 		var err error
 		cfg.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
-		mgr, err = manager.New(cfg, manager.Options{
-			Namespace:          farosflags.Namespace,
-			MetricsBindAddress: "0", // Disable serving metrics while testing
-		})
+		opts := testenv.ManagerOptions(farosflags.Namespaces[0])
+		opts.MapperProvider = utils.NewRestMapper
+		mgr, err = manager.New(cfg, opts)
 		Expect(err).NotTo(HaveOccurred())
 		c = mgr.GetClient()
 		m = testutils.Matcher{Client: c}
@@ -65,7 +68,7 @@ var _ = Describe("Watch Suite", func() {
 		r = recFn.(*ReconcileGitTrackObject)
 
 		stopInformers = r.StopChan()
-		stop = StartTestManager(mgr)
+		stop = testenv.StartManager(mgr)
 	})
 
 	AfterEach(func() {
@@ -127,5 +130,31 @@ var _ = Describe("Watch Suite", func() {
 				Expect(r.informers).To(Equal(originalInformers))
 			})
 		})
+
+		Context("when the kind's CRD is installed after the watch is requested", func() {
+			var foo *unstructured.Unstructured
+			var crd *apiextensionsv1beta1.CustomResourceDefinition
+
+			BeforeEach(func() {
+				foo = test.ExampleFoo.DeepCopy()
+				crd = test.ExampleCRD.DeepCopy()
+
+				// Watching the kind fails while its CRD is missing
+				Expect(r.watch(*foo)).To(HaveOccurred())
+
+				m.Create(crd).Should(Succeed())
+				m.Get(crd, timeout).Should(Succeed())
+			})
+
+			AfterEach(func() {
+				m.Delete(crd).Should(Succeed())
+			})
+
+			It("should eventually create an informer", func() {
+				// The API resources are rediscovered at most every few seconds
+				Eventually(func() error { return r.watch(*foo) }, 3*timeout).Should(Succeed())
+				Expect(r.informers).To(HaveKey(informerKey(*foo)))
+			})
+		})
 	})
 })
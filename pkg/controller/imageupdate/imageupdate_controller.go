@@ -0,0 +1,314 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageupdate
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/gitwrite"
+	"github.com/pusher/faros/pkg/health"
+	"github.com/pusher/faros/pkg/registry"
+	"github.com/pusher/faros/pkg/shutdown"
+	"github.com/pusher/faros/pkg/utils"
+	billy "gopkg.in/src-d/go-billy.v4"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Add creates a new ImageUpdate Controller and adds it to the Manager if an
+// image update interval has been configured. The Manager will set fields on
+// the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	if farosflags.ImageUpdateInterval <= 0 {
+		return nil
+	}
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	selector, err := farosflags.ParseGitTrackSelector()
+	if err != nil {
+		panic(fmt.Errorf("unable to parse gittrack selector: %v", err))
+	}
+
+	return &ReconcileImageUpdate{
+		Client:   mgr.GetClient(),
+		recorder: mgr.GetEventRecorderFor("imageupdate-controller"),
+		registry: registry.New(),
+		commit:   gitwrite.Commit,
+		author:   gitwrite.Author{Name: farosflags.GitAuthorName, Email: farosflags.GitAuthorEmail},
+		interval: farosflags.ImageUpdateInterval,
+		selector: selector,
+		log:      rlogr.Log.WithName("imageupdate-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("imageupdate-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	selector, err := farosflags.ParseGitTrackSelector()
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to GitTracks matching the selector
+	err = c.Watch(
+		&source.Kind{Type: &farosv1alpha1.GitTrack{}},
+		&handler.EnqueueRequestForObject{},
+		utils.NewLabelSelectorPredicate(selector),
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// tagLister lists the tags of an image
+type tagLister interface {
+	ListTags(ctx context.Context, image registry.Image, creds *registry.Credentials) ([]string, error)
+}
+
+var _ reconcile.Reconciler = &ReconcileImageUpdate{}
+
+// ReconcileImageUpdate commits the latest tags of the images configured by
+// GitTracks to their repositories
+type ReconcileImageUpdate struct {
+	client.Client
+	recorder record.EventRecorder
+	registry tagLister
+	commit   func(gitwrite.Options, gitwrite.EditFunc) (string, error)
+	author   gitwrite.Author
+	interval time.Duration
+	selector labels.Selector
+	log      logr.Logger
+}
+
+// Reconcile checks the registries of the images configured by the GitTrack
+// for newer tags and commits them to the repository, requeuing the GitTrack
+// to check again after the image update interval
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=gittracks,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=gittracks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=,resources=events,verbs=create;patch
+func (r *ReconcileImageUpdate) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	defer health.RecoverPanic("imageupdate-controller")
+	// Leave the request for the next instance once shutdown has begun
+	done, ok := shutdown.Begin()
+	if !ok {
+		r.log.V(1).Info("Shutting down, skipping reconcile", "request", request.String())
+		return reconcile.Result{}, nil
+	}
+	defer done()
+
+	gt := &farosv1alpha1.GitTrack{}
+	err := r.Get(context.TODO(), request.NamespacedName, gt)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	// GitTracks not matching the selector are managed by another controller
+	if r.selector != nil && !r.selector.Matches(labels.Set(gt.GetLabels())) {
+		return reconcile.Result{}, nil
+	}
+	if gt.Spec.ImageUpdates == nil || gt.Spec.Suspend || gt.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	log := r.log.WithValues("namespace", gt.GetNamespace(), "name", gt.GetName())
+	err = r.updateImages(gt, log)
+	if err != nil {
+		r.recorder.Eventf(gt, apiv1.EventTypeWarning, "ImageUpdateFailed", "Failed to update images: %v", err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: r.interval}, nil
+}
+
+// updateImages commits the latest tags of the GitTrack's images to its
+// repository and syncs the GitTrack if any were changed
+func (r *ReconcileImageUpdate) updateImages(gt *farosv1alpha1.GitTrack, log logr.Logger) error {
+	tags, err := r.latestTags(gt)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return r.updateStatus(gt, tags, "")
+	}
+
+	branch := gt.Spec.ImageUpdates.Branch
+	if branch == "" {
+		branch = gt.Spec.Reference
+	}
+	creds, credType, err := r.deployKey(gt, branch)
+	if err != nil {
+		return err
+	}
+
+	var changes []string
+	hash, err := r.commit(gitwrite.Options{
+		URL:            gt.Spec.Repository,
+		Branch:         branch,
+		Credentials:    creds,
+		CredentialType: credType,
+		Message:        "Update images",
+		Author:         r.author,
+	}, func(fs billy.Filesystem) ([]string, error) {
+		var files []string
+		files, changes, err = updateFiles(fs, gt.Spec.SubPath, tags)
+		return files, err
+	})
+	if err != nil {
+		return err
+	}
+	if hash == "" {
+		log.V(1).Info("Images up to date")
+		return r.updateStatus(gt, tags, "")
+	}
+
+	log.V(0).Info("Images updated", "commit", hash, "branch", branch)
+	r.recorder.Eventf(gt, apiv1.EventTypeNormal, "ImagesUpdated", "Committed %s to '%s': %s", hash, branch, strings.Join(changes, ", "))
+
+	// Sync the new commit straight away rather than waiting for the next sync
+	annotations := gt.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[gittrackutils.SyncNowAnnotation] = hash
+	gt.SetAnnotations(annotations)
+	status := gt.Status
+	if err = r.Update(context.TODO(), gt); err != nil {
+		return fmt.Errorf("unable to request sync: %v", err)
+	}
+	gt.Status = status
+	return r.updateStatus(gt, tags, hash)
+}
+
+// latestTags returns the latest tag of each image allowed by its policy.
+// Images without any matching tags are left out.
+func (r *ReconcileImageUpdate) latestTags(gt *farosv1alpha1.GitTrack) (map[string]string, error) {
+	var dockerConfig []byte
+	if name := gt.Spec.ImageUpdates.PullSecretName; name != "" {
+		secret := &apiv1.Secret{}
+		err := r.Get(context.TODO(), types.NamespacedName{Namespace: gt.Namespace, Name: name}, secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up secret %s: %v", name, err)
+		}
+		dockerConfig = secret.Data[apiv1.DockerConfigJsonKey]
+	}
+
+	tags := make(map[string]string)
+	for _, policy := range gt.Spec.ImageUpdates.Images {
+		image, err := registry.ParseImage(policy.Image)
+		if err != nil {
+			return nil, err
+		}
+		var creds *registry.Credentials
+		if dockerConfig != nil {
+			creds, err = registry.CredentialsFromDockerConfig(dockerConfig, image.Host)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		available, err := r.registry.ListTags(context.TODO(), image, creds)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := selectTag(available, policy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy for image %s: %v", policy.Image, err)
+		}
+		if tag == "" {
+			r.recorder.Eventf(gt, apiv1.EventTypeWarning, "NoMatchingTags", "No tags of image %s match its policy", policy.Image)
+			continue
+		}
+		tags[policy.Image] = tag
+	}
+	return tags, nil
+}
+
+// deployKey returns the deploy key of the GitTrack used to write to the
+// branch, if it has one
+func (r *ReconcileImageUpdate) deployKey(gt *farosv1alpha1.GitTrack, branch string) ([]byte, farosv1alpha1.GitCredentialType, error) {
+	deployKey := gittrackutils.DeployKey(gt, gt.Spec.SubPath, branch)
+	if deployKey.SecretName == "" || deployKey.Key == "" {
+		return nil, "", nil
+	}
+	secret := &apiv1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: gt.Namespace, Name: deployKey.SecretName}, secret)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to look up secret %s: %v", deployKey.SecretName, err)
+	}
+	data, ok := secret.Data[deployKey.Key]
+	if !ok {
+		return nil, "", fmt.Errorf("invalid deploy key reference. Secret %s does not have key %s", deployKey.SecretName, deployKey.Key)
+	}
+	return data, deployKey.Type, nil
+}
+
+// updateStatus records the latest tags and, if one was made, the commit
+// updating them in the status of the GitTrack
+func (r *ReconcileImageUpdate) updateStatus(original *farosv1alpha1.GitTrack, tags map[string]string, hash string) error {
+	gt := original.DeepCopy()
+	status := &farosv1alpha1.GitTrackImageUpdateStatus{}
+	if gt.Status.ImageUpdates != nil {
+		status = gt.Status.ImageUpdates.DeepCopy()
+	}
+	status.LatestTags = tags
+	if hash != "" {
+		now := metav1.Now()
+		status.LastCommit = hash
+		status.LastUpdateTime = &now
+	}
+	if reflect.DeepEqual(original.Status.ImageUpdates, status) {
+		return nil
+	}
+
+	gt.Status.ImageUpdates = status
+	err := r.Status().Update(context.TODO(), gt)
+	if err != nil {
+		return fmt.Errorf("unable to update GitTrack status: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageupdate
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	"github.com/pusher/faros/pkg/gitwrite"
+	"github.com/pusher/faros/pkg/registry"
+	testutils "github.com/pusher/faros/test/utils"
+	billy "gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// fakeRegistry returns the same tags for every image
+type fakeRegistry []string
+
+func (f fakeRegistry) ListTags(ctx context.Context, image registry.Image, creds *registry.Credentials) ([]string, error) {
+	return f, nil
+}
+
+var _ = Describe("ImageUpdate Suite", func() {
+	var c client.Client
+	var r *ReconcileImageUpdate
+	var gt *farosv1alpha1.GitTrack
+	var repo billy.Filesystem
+	var committed *gitwrite.Options
+	var result reconcile.Result
+
+	const timeout = time.Second * 5
+
+	BeforeEach(func() {
+		var err error
+		c, err = client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		repo = memfs.New()
+		Expect(gitwrite.WriteFile(repo, "deploy/faros.yaml", []byte("image: quay.io/pusher/faros:v1.0.0\n"))).To(Succeed())
+		committed = nil
+
+		r = &ReconcileImageUpdate{
+			Client:   c,
+			recorder: record.NewFakeRecorder(10),
+			registry: fakeRegistry{"v1.0.0", "v1.1.0", "v2.0.0"},
+			commit: func(opts gitwrite.Options, edit gitwrite.EditFunc) (string, error) {
+				files, err := edit(repo)
+				if err != nil || len(files) == 0 {
+					return "", err
+				}
+				committed = &opts
+				return "abc123", nil
+			},
+			interval: time.Minute,
+			log:      rlogr.Log.WithName("imageupdate-controller"),
+		}
+
+		gt = &farosv1alpha1.GitTrack{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default"},
+			Spec: farosv1alpha1.GitTrackSpec{
+				Repository: "https://github.com/pusher/faros",
+				Reference:  "master",
+				SubPath:    "deploy",
+				ImageUpdates: &farosv1alpha1.GitTrackImageUpdates{
+					Images: []farosv1alpha1.ImagePolicy{{Image: "quay.io/pusher/faros", Semver: "^1.0"}},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		testutils.DeleteAll(cfg, timeout,
+			&farosv1alpha1.GitTrackList{},
+		)
+	})
+
+	var reconcileGT = func() {
+		Expect(c.Create(context.TODO(), gt)).To(Succeed())
+		var err error
+		result, err = r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: gt.GetNamespace(), Name: gt.GetName()}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: gt.GetNamespace(), Name: gt.GetName()}, gt)).To(Succeed())
+	}
+
+	Context("with a newer tag allowed by the policy", func() {
+		BeforeEach(func() {
+			reconcileGT()
+		})
+
+		It("commits the tag to the tracked branch", func() {
+			Expect(committed).NotTo(BeNil())
+			Expect(committed.Branch).To(Equal("master"))
+			data, err := gitwrite.ReadFile(repo, "deploy/faros.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal("image: quay.io/pusher/faros:v1.1.0\n"))
+		})
+
+		It("requests a sync of the commit", func() {
+			Expect(gt.GetAnnotations()).To(HaveKeyWithValue(gittrackutils.SyncNowAnnotation, "abc123"))
+		})
+
+		It("records the update in the status", func() {
+			Expect(gt.Status.ImageUpdates).NotTo(BeNil())
+			Expect(gt.Status.ImageUpdates.LatestTags).To(HaveKeyWithValue("quay.io/pusher/faros", "v1.1.0"))
+			Expect(gt.Status.ImageUpdates.LastCommit).To(Equal("abc123"))
+			Expect(gt.Status.ImageUpdates.LastUpdateTime).NotTo(BeNil())
+		})
+
+		It("requeues after the interval", func() {
+			Expect(result.RequeueAfter).To(Equal(time.Minute))
+		})
+	})
+
+	Context("with a branch configured", func() {
+		BeforeEach(func() {
+			gt.Spec.ImageUpdates.Branch = "image-updates"
+			reconcileGT()
+		})
+
+		It("commits to the branch", func() {
+			Expect(committed).NotTo(BeNil())
+			Expect(committed.Branch).To(Equal("image-updates"))
+		})
+	})
+
+	Context("with images already at the latest tag", func() {
+		BeforeEach(func() {
+			Expect(gitwrite.WriteFile(repo, "deploy/faros.yaml", []byte("image: quay.io/pusher/faros:v1.1.0\n"))).To(Succeed())
+			reconcileGT()
+		})
+
+		It("does not commit", func() {
+			Expect(committed).To(BeNil())
+		})
+
+		It("does not request a sync", func() {
+			Expect(gt.GetAnnotations()).NotTo(HaveKey(gittrackutils.SyncNowAnnotation))
+		})
+
+		It("records the latest tags in the status", func() {
+			Expect(gt.Status.ImageUpdates).NotTo(BeNil())
+			Expect(gt.Status.ImageUpdates.LatestTags).To(HaveKeyWithValue("quay.io/pusher/faros", "v1.1.0"))
+			Expect(gt.Status.ImageUpdates.LastCommit).To(BeEmpty())
+		})
+	})
+
+	Context("with a suspended GitTrack", func() {
+		BeforeEach(func() {
+			gt.Spec.Suspend = true
+			reconcileGT()
+		})
+
+		It("does not commit", func() {
+			Expect(committed).To(BeNil())
+		})
+
+		It("does not requeue", func() {
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageupdate
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pusher/faros/pkg/gitwrite"
+	billy "gopkg.in/src-d/go-billy.v4"
+)
+
+// manifestExtensions are the extensions of the files the tags are updated in
+var manifestExtensions = map[string]bool{".yaml": true, ".yml": true, ".json": true}
+
+// imageRegexp matches the `image` fields referencing the image in YAML or
+// JSON manifests. The image, tag and the rest of the field are captured.
+func imageRegexp(image string) *regexp.Regexp {
+	return regexp.MustCompile(`(["']?image["']?\s*:\s*["']?)(` + regexp.QuoteMeta(image) + `):([\w][\w.-]{0,127})(["'\s,}]|$)`)
+}
+
+// updateTags sets the tags of the images in the manifest, returning the
+// updated manifest and a description of each change
+func updateTags(manifest []byte, tags map[string]string) ([]byte, []string) {
+	images := []string{}
+	for image := range tags {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+
+	changes := []string{}
+	for _, image := range images {
+		tag := tags[image]
+		manifest = imageRegexp(image).ReplaceAllFunc(manifest, func(field []byte) []byte {
+			match := imageRegexp(image).FindSubmatch(field)
+			if string(match[3]) == tag {
+				return field
+			}
+			changes = append(changes, fmt.Sprintf("%s:%s -> %s", image, match[3], tag))
+			return []byte(string(match[1]) + image + ":" + tag + string(match[4]))
+		})
+	}
+	return manifest, changes
+}
+
+// updateFiles sets the tags of the images in every manifest beneath the
+// subPath, returning the files that were changed and a description of each
+// change
+func updateFiles(fs billy.Filesystem, subPath string, tags map[string]string) ([]string, []string, error) {
+	dir := strings.Trim(subPath, "/")
+	if dir == "" {
+		dir = "."
+	}
+
+	files, changes := []string{}, []string{}
+	err := gitwrite.Walk(fs, dir, func(filename string) error {
+		if !manifestExtensions[path.Ext(filename)] {
+			return nil
+		}
+		data, err := gitwrite.ReadFile(fs, filename)
+		if err != nil {
+			return fmt.Errorf("failed to read '%s': %v", filename, err)
+		}
+		updated, fileChanges := updateTags(data, tags)
+		if len(fileChanges) == 0 {
+			return nil
+		}
+		if err = gitwrite.WriteFile(fs, filename, updated); err != nil {
+			return fmt.Errorf("failed to write '%s': %v", filename, err)
+		}
+		files = append(files, filename)
+		for _, change := range fileChanges {
+			changes = append(changes, fmt.Sprintf("%s: %s", filename, change))
+		}
+		return nil
+	})
+	return files, changes, err
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageupdate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/pkg/gitwrite"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+)
+
+var _ = Describe("Manifests Suite", func() {
+	tags := map[string]string{"quay.io/pusher/faros": "v1.2.0"}
+
+	Context("updateTags", func() {
+		It("updates images in YAML manifests", func() {
+			manifest := "containers:\n- name: faros\n  image: quay.io/pusher/faros:v1.0.0\n"
+			updated, changes := updateTags([]byte(manifest), tags)
+			Expect(string(updated)).To(Equal("containers:\n- name: faros\n  image: quay.io/pusher/faros:v1.2.0\n"))
+			Expect(changes).To(Equal([]string{"quay.io/pusher/faros:v1.0.0 -> v1.2.0"}))
+		})
+
+		It("updates quoted images", func() {
+			manifest := "image: 'quay.io/pusher/faros:v1.0.0'\n"
+			updated, _ := updateTags([]byte(manifest), tags)
+			Expect(string(updated)).To(Equal("image: 'quay.io/pusher/faros:v1.2.0'\n"))
+		})
+
+		It("updates images in JSON manifests", func() {
+			manifest := `{"name": "faros", "image": "quay.io/pusher/faros:v1.0.0"}`
+			updated, _ := updateTags([]byte(manifest), tags)
+			Expect(string(updated)).To(Equal(`{"name": "faros", "image": "quay.io/pusher/faros:v1.2.0"}`))
+		})
+
+		It("does not update other images sharing a prefix", func() {
+			manifest := "image: quay.io/pusher/faros-test:v1.0.0\n"
+			updated, changes := updateTags([]byte(manifest), tags)
+			Expect(string(updated)).To(Equal(manifest))
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("does not report images already at the tag", func() {
+			manifest := "image: quay.io/pusher/faros:v1.2.0\n"
+			_, changes := updateTags([]byte(manifest), tags)
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	Context("updateFiles", func() {
+		It("only updates manifests beneath the subPath", func() {
+			fs := memfs.New()
+			Expect(gitwrite.WriteFile(fs, "deploy/faros.yaml", []byte("image: quay.io/pusher/faros:v1.0.0\n"))).To(Succeed())
+			Expect(gitwrite.WriteFile(fs, "deploy/README.md", []byte("image: quay.io/pusher/faros:v1.0.0\n"))).To(Succeed())
+			Expect(gitwrite.WriteFile(fs, "other/faros.yaml", []byte("image: quay.io/pusher/faros:v1.0.0\n"))).To(Succeed())
+
+			files, changes, err := updateFiles(fs, "/deploy", tags)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal([]string{"deploy/faros.yaml"}))
+			Expect(changes).To(Equal([]string{"deploy/faros.yaml: quay.io/pusher/faros:v1.0.0 -> v1.2.0"}))
+
+			data, err := gitwrite.ReadFile(fs, "other/faros.yaml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("v1.0.0"))
+		})
+	})
+})
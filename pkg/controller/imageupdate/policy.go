@@ -0,0 +1,226 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageupdate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+// version is a semantic version parsed from a tag
+type version struct {
+	major, minor, patch int64
+	prerelease          string
+}
+
+// versionRegexp matches semantic versions with an optional `v` prefix. The
+// minor and patch versions may be omitted in ranges.
+var versionRegexp = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// parseVersion parses a full semantic version from a tag
+func parseVersion(tag string) (version, bool) {
+	v, parts, ok := parsePartialVersion(tag)
+	return v, ok && parts == 3
+}
+
+// parsePartialVersion parses a version that may omit the minor and patch
+// versions, returning how many of them were given
+func parsePartialVersion(s string) (version, int, bool) {
+	match := versionRegexp.FindStringSubmatch(s)
+	if match == nil {
+		return version{}, 0, false
+	}
+	v := version{prerelease: match[4]}
+	parts := 0
+	for i, field := range []*int64{&v.major, &v.minor, &v.patch} {
+		if match[i+1] == "" {
+			break
+		}
+		n, err := strconv.ParseInt(match[i+1], 10, 64)
+		if err != nil {
+			return version{}, 0, false
+		}
+		*field = n
+		parts++
+	}
+	return v, parts, true
+}
+
+// compare returns -1, 0 or 1 if v is lower than, equal to or higher than o.
+// Pre-releases are ordered lexically.
+func (v version) compare(o version) int {
+	for _, d := range []int64{v.major - o.major, v.minor - o.minor, v.patch - o.patch} {
+		if d < 0 {
+			return -1
+		} else if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.prerelease == o.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case o.prerelease == "":
+		return -1
+	case v.prerelease < o.prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// comparator is a single condition of a range, eg `>=1.2.0`
+type comparator struct {
+	op string
+	v  version
+}
+
+func (c comparator) matches(v version) bool {
+	cmp := v.compare(c.v)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// versionRange is a set of alternatives, separated by `||`, each of which is
+// a set of comparators that must all match
+type versionRange [][]comparator
+
+// operatorRegexp splits a comparator into its operator and version
+var operatorRegexp = regexp.MustCompile(`^(>=|<=|>|<|=|\^|~)?\s*(.+)$`)
+
+// parseRange parses a range of versions such as ">=1.2.0 <2.0.0" or "^1.2"
+func parseRange(s string) (versionRange, error) {
+	r := versionRange{}
+	for _, alternative := range strings.Split(s, "||") {
+		comparators := []comparator{}
+		for _, field := range strings.Fields(alternative) {
+			match := operatorRegexp.FindStringSubmatch(field)
+			v, parts, ok := parsePartialVersion(match[2])
+			if !ok {
+				return nil, fmt.Errorf("invalid version %q in range %q", match[2], s)
+			}
+			comparators = append(comparators, expand(match[1], v, parts)...)
+		}
+		if len(comparators) == 0 {
+			return nil, fmt.Errorf("empty alternative in range %q", s)
+		}
+		r = append(r, comparators)
+	}
+	return r, nil
+}
+
+// expand turns an operator and a possibly partial version into comparators
+func expand(op string, v version, parts int) []comparator {
+	switch op {
+	case "^":
+		// Allow changes that do not modify the left-most non-zero part
+		upper := version{major: v.major + 1}
+		if v.major == 0 && parts > 1 {
+			upper = version{minor: v.minor + 1}
+			if v.minor == 0 && parts > 2 {
+				upper = version{patch: v.patch + 1}
+			}
+		}
+		return []comparator{{op: ">=", v: v}, {op: "<", v: upper}}
+	case "~":
+		// Allow patch changes, or minor changes if no minor version is given
+		upper := version{major: v.major, minor: v.minor + 1}
+		if parts == 1 {
+			upper = version{major: v.major + 1}
+		}
+		return []comparator{{op: ">=", v: v}, {op: "<", v: upper}}
+	case "", "=":
+		// A partial version matches every version it is a prefix of
+		if parts < 3 {
+			return expand("~", v, parts)
+		}
+		return []comparator{{op: "=", v: v}}
+	default:
+		return []comparator{{op: op, v: v}}
+	}
+}
+
+func (r versionRange) matches(v version) bool {
+	for _, comparators := range r {
+		matched := true
+		for _, c := range comparators {
+			if !c.matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// selectTag returns the tag with the highest semantic version that matches
+// the policy, or an empty string if none do. Pre-release versions are only
+// selected if they are allowed by the pattern.
+func selectTag(tags []string, policy farosv1alpha1.ImagePolicy) (string, error) {
+	var pattern *regexp.Regexp
+	if policy.Pattern != "" {
+		var err error
+		pattern, err = regexp.Compile(policy.Pattern)
+		if err != nil {
+			return "", fmt.Errorf("invalid pattern %q: %v", policy.Pattern, err)
+		}
+	}
+	var r versionRange
+	if policy.Semver != "" {
+		var err error
+		r, err = parseRange(policy.Semver)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	selected, selectedVersion := "", version{}
+	for _, tag := range tags {
+		if pattern != nil && !pattern.MatchString(tag) {
+			continue
+		}
+		v, ok := parseVersion(tag)
+		if !ok || (v.prerelease != "" && pattern == nil) {
+			continue
+		}
+		if r != nil && !r.matches(v) {
+			continue
+		}
+		if selected == "" || v.compare(selectedVersion) > 0 {
+			selected, selectedVersion = tag, v
+		}
+	}
+	return selected, nil
+}
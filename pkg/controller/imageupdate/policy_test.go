@@ -0,0 +1,76 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageupdate
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+var _ = Describe("Policy Suite", func() {
+	tags := []string{"latest", "v0.9.0", "v1.0.0", "v1.2.0", "v1.2.3", "v1.3.0-rc.1", "v1.10.0", "v2.0.0", "v2.1.0-rc.1"}
+
+	var selected = func(policy farosv1alpha1.ImagePolicy) string {
+		tag, err := selectTag(tags, policy)
+		Expect(err).NotTo(HaveOccurred())
+		return tag
+	}
+
+	It("selects the highest version without a range", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{})).To(Equal("v2.0.0"))
+	})
+
+	It("compares versions numerically", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{Semver: "<2.0.0"})).To(Equal("v1.10.0"))
+	})
+
+	It("selects the highest version within a caret range", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{Semver: "^1.2"})).To(Equal("v1.10.0"))
+	})
+
+	It("selects the highest version within a tilde range", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{Semver: "~1.2.0"})).To(Equal("v1.2.3"))
+	})
+
+	It("selects the highest version within a bounded range", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{Semver: ">=1.0.0 <1.2.3"})).To(Equal("v1.2.0"))
+	})
+
+	It("selects the highest version matching any alternative", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{Semver: "0.9 || 1.0"})).To(Equal("v1.0.0"))
+	})
+
+	It("skips pre-releases unless a pattern allows them", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{Semver: "^1.2"})).NotTo(ContainSubstring("rc"))
+		Expect(selected(farosv1alpha1.ImagePolicy{Pattern: `-rc\.`})).To(Equal("v2.1.0-rc.1"))
+	})
+
+	It("returns an empty tag when no versions match", func() {
+		Expect(selected(farosv1alpha1.ImagePolicy{Semver: ">=3.0.0"})).To(BeEmpty())
+	})
+
+	It("rejects invalid ranges", func() {
+		_, err := selectTag(tags, farosv1alpha1.ImagePolicy{Semver: ">=one"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects invalid patterns", func() {
+		_, err := selectTag(tags, farosv1alpha1.ImagePolicy{Pattern: "("})
+		Expect(err).To(HaveOccurred())
+	})
+})
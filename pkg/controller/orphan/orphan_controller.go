@@ -0,0 +1,143 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	farosflags "github.com/pusher/faros/pkg/flags"
+	"github.com/pusher/faros/pkg/health"
+	"github.com/pusher/faros/pkg/shutdown"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Add creates a new Orphan Controller and adds it to the Manager if an orphan
+// TTL has been configured. The Manager will set fields on the Controller and
+// Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	if farosflags.OrphanTTL <= 0 {
+		return nil
+	}
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileOrphan{
+		Client: mgr.GetClient(),
+		ttl:    farosflags.OrphanTTL,
+		log:    rlogr.Log.WithName("orphan-controller"),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("orphan-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to GitTrackObject
+	err = c.Watch(&source.Kind{Type: &farosv1alpha1.GitTrackObject{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterGitTrackObject
+	err = c.Watch(&source.Kind{Type: &farosv1alpha1.ClusterGitTrackObject{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileOrphan{}
+
+// ReconcileOrphan deletes (Cluster)GitTrackObjects that have been orphaned
+// for longer than the configured TTL
+type ReconcileOrphan struct {
+	client.Client
+	ttl time.Duration
+	log logr.Logger
+}
+
+// Reconcile checks whether an orphaned (Cluster)GitTrackObject has exceeded
+// its TTL and deletes it if so, otherwise requeuing it for when it will expire
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=gittrackobjects,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=faros.pusher.com,resources=clustergittrackobjects,verbs=get;list;watch;delete
+func (r *ReconcileOrphan) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	defer health.RecoverPanic("orphan-controller")
+	// Leave the request for the next instance once shutdown has begun
+	done, ok := shutdown.Begin()
+	if !ok {
+		r.log.V(1).Info("Shutting down, skipping reconcile", "request", request.String())
+		return reconcile.Result{}, nil
+	}
+	defer done()
+	var instance farosv1alpha1.GitTrackObjectInterface
+	if request.Namespace != "" {
+		instance = &farosv1alpha1.GitTrackObject{}
+	} else {
+		instance = &farosv1alpha1.ClusterGitTrackObject{}
+	}
+
+	err := r.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if _, ok := instance.GetLabels()[gittrackutils.OrphanedLabel]; !ok || instance.GetDeletionTimestamp() != nil {
+		return reconcile.Result{}, nil
+	}
+
+	log := r.log.WithValues("namespace", instance.GetNamespace(), "name", instance.GetName())
+	orphanedAt, err := time.Parse(time.RFC3339, instance.GetAnnotations()[gittrackutils.OrphanedAtAnnotation])
+	if err != nil {
+		// Without a valid timestamp the TTL can't be enforced safely
+		log.Error(err, "unable to parse orphaned-at annotation")
+		return reconcile.Result{}, nil
+	}
+
+	remaining := orphanedAt.Add(r.ttl).Sub(time.Now())
+	if remaining > 0 {
+		log.V(1).Info("Orphan has not expired", "remaining", remaining.String())
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	err = r.Delete(context.TODO(), instance)
+	if err != nil && !errors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	log.V(0).Info("Orphan deleted after TTL expired")
+	return reconcile.Result{}, nil
+}
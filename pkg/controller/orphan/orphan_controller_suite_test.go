@@ -0,0 +1,49 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"log"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+	"github.com/pusher/faros/test/testenv"
+	"k8s.io/client-go/rest"
+)
+
+var cfg *rest.Config
+
+func TestOrphanController(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Orphan Suite", reporters.Reporters())
+}
+
+var t *testenv.Environment
+
+var _ = BeforeSuite(func() {
+	var err error
+	if t, err = testenv.Start(); err != nil {
+		log.Fatal(err)
+	}
+	cfg = t.Config
+})
+
+var _ = AfterSuite(func() {
+	t.Stop()
+})
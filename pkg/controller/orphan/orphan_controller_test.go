@@ -0,0 +1,114 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphan
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	testutils "github.com/pusher/faros/test/utils"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Orphan Suite", func() {
+	var c client.Client
+	var r *ReconcileOrphan
+	var gto *farosv1alpha1.GitTrackObject
+	var result reconcile.Result
+
+	const timeout = time.Second * 5
+
+	BeforeEach(func() {
+		var err error
+		c, err = client.New(cfg, client.Options{})
+		Expect(err).NotTo(HaveOccurred())
+
+		r = &ReconcileOrphan{
+			Client: c,
+			ttl:    time.Hour,
+			log:    rlogr.Log.WithName("orphan-controller"),
+		}
+
+		gto = testutils.ExampleGitTrackObject.DeepCopy()
+	})
+
+	AfterEach(func() {
+		testutils.DeleteAll(cfg, timeout,
+			&farosv1alpha1.GitTrackObjectList{},
+		)
+	})
+
+	var reconcileGTO = func() {
+		Expect(c.Create(context.TODO(), gto)).To(Succeed())
+		var err error
+		result, err = r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: gto.GetNamespace(), Name: gto.GetName()}})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	var gtoExists = func() error {
+		return c.Get(context.TODO(), types.NamespacedName{Namespace: gto.GetNamespace(), Name: gto.GetName()}, &farosv1alpha1.GitTrackObject{})
+	}
+
+	Context("with a GitTrackObject that is not orphaned", func() {
+		BeforeEach(func() {
+			reconcileGTO()
+		})
+
+		It("does not delete the GitTrackObject", func() {
+			Expect(gtoExists()).To(Succeed())
+		})
+
+		It("does not requeue", func() {
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+	})
+
+	Context("with a GitTrackObject orphaned within the TTL", func() {
+		BeforeEach(func() {
+			gto.SetLabels(map[string]string{gittrackutils.OrphanedLabel: "true"})
+			gto.SetAnnotations(map[string]string{gittrackutils.OrphanedAtAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)})
+			reconcileGTO()
+		})
+
+		It("does not delete the GitTrackObject", func() {
+			Expect(gtoExists()).To(Succeed())
+		})
+
+		It("requeues for when the TTL expires", func() {
+			Expect(result.RequeueAfter).To(BeNumerically("~", 59*time.Minute, time.Minute))
+		})
+	})
+
+	Context("with a GitTrackObject orphaned beyond the TTL", func() {
+		BeforeEach(func() {
+			gto.SetLabels(map[string]string{gittrackutils.OrphanedLabel: "true"})
+			gto.SetAnnotations(map[string]string{gittrackutils.OrphanedAtAnnotation: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)})
+			reconcileGTO()
+		})
+
+		It("deletes the GitTrackObject", func() {
+			Eventually(gtoExists, timeout).ShouldNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,286 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cosign verifies the signatures created by `cosign sign-blob`
+// against trusted public keys, or against trusted keyless signing identities
+// given the certificate the signature was made with.
+//
+// Keyless signatures are not looked up in a transparency log, so their
+// certificates are checked as of the time they were issued.
+package cosign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// SignatureSuffix is appended to the path of a file to get the path of
+	// its signature, as written by `cosign sign-blob --output-signature`
+	SignatureSuffix = ".sig"
+
+	// CertificateSuffix is appended to the path of a file to get the path of
+	// the certificate of its keyless signature, as written by
+	// `cosign sign-blob --output-certificate`
+	CertificateSuffix = ".pem"
+)
+
+var (
+	// oidIssuer is the extension of Fulcio certificates recording the OIDC
+	// issuer of the signing identity as a DER encoded string
+	oidIssuer = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+
+	// oidIssuerV1 is the deprecated extension recording the OIDC issuer of
+	// the signing identity as a raw string
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+)
+
+// Identity is a keyless signing identity
+type Identity struct {
+	// Issuer is the URL of the OIDC issuer that authenticated the identity
+	Issuer string
+
+	// Subject is the email address or URI of the identity
+	Subject string
+}
+
+// Options configure a Verifier
+type Options struct {
+	// PublicKeys are the PEM encoded public keys trusted to sign blobs
+	PublicKeys []byte
+
+	// Roots are the PEM encoded certificates of the certificate authorities,
+	// eg. Fulcio, trusted to issue the certificates of keyless signatures
+	Roots []byte
+
+	// Identities are the keyless signing identities trusted to sign blobs
+	Identities []Identity
+}
+
+// Verifier verifies the cosign signatures of blobs
+type Verifier struct {
+	keys       []crypto.PublicKey
+	roots      *x509.CertPool
+	identities []Identity
+}
+
+// NewVerifier creates a Verifier trusting the keys and identities of the
+// options, at least one of which must be given
+func NewVerifier(opts Options) (*Verifier, error) {
+	keys, err := parsePublicKeys(opts.PublicKeys)
+	if err != nil {
+		return nil, err
+	}
+	v := &Verifier{keys: keys, identities: opts.Identities}
+
+	if len(opts.Identities) > 0 {
+		v.roots = x509.NewCertPool()
+		if !v.roots.AppendCertsFromPEM(opts.Roots) {
+			return nil, fmt.Errorf("no root certificates to verify keyless signatures with")
+		}
+	}
+	if len(v.keys) == 0 && len(v.identities) == 0 {
+		return nil, fmt.Errorf("no public keys or identities to verify signatures with")
+	}
+	return v, nil
+}
+
+// Verify checks that the signature of the blob was made with one of the
+// trusted keys or, if the certificate of a keyless signature is given, by one
+// of the trusted identities
+func (v *Verifier) Verify(blob, signature, certificate []byte) error {
+	sig := decodeSignature(signature)
+	if len(certificate) > 0 && len(v.identities) > 0 {
+		return v.verifyKeyless(blob, sig, certificate)
+	}
+	if len(v.keys) == 0 {
+		return fmt.Errorf("no certificate to verify keyless signature with")
+	}
+	for _, key := range v.keys {
+		if verifySignature(key, blob, sig) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("signature does not match any trusted key")
+}
+
+// verifyKeyless checks that the certificate was issued to one of the
+// trusted identities by one of the trusted roots, and that the signature of
+// the blob was made with it
+func (v *Verifier) verifyKeyless(blob, sig, certificate []byte) error {
+	certs, err := parseCertificates(certificate)
+	if err != nil {
+		return err
+	}
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	// Keyless certificates are short lived, without a transparency log to
+	// prove when the blob was signed they are checked as of their issue
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   leaf.NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return fmt.Errorf("certificate is not trusted: %v", err)
+	}
+
+	issuer, subjects := certificateIdentity(leaf)
+	if !v.trustsIdentity(issuer, subjects) {
+		return fmt.Errorf("identity %s from %s is not trusted", strings.Join(subjects, ", "), issuer)
+	}
+	if err := verifySignature(leaf.PublicKey, blob, sig); err != nil {
+		return fmt.Errorf("signature does not match certificate: %v", err)
+	}
+	return nil
+}
+
+// trustsIdentity returns whether any of the subjects authenticated by the
+// issuer is a trusted identity
+func (v *Verifier) trustsIdentity(issuer string, subjects []string) bool {
+	for _, identity := range v.identities {
+		if identity.Issuer != issuer {
+			continue
+		}
+		for _, subject := range subjects {
+			if identity.Subject == subject {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// certificateIdentity returns the OIDC issuer and the subjects of the
+// identity the certificate was issued to
+func certificateIdentity(cert *x509.Certificate) (string, []string) {
+	var issuer string
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuer):
+			var value string
+			if _, err := asn1.Unmarshal(ext.Value, &value); err == nil {
+				issuer = value
+			}
+		case ext.Id.Equal(oidIssuerV1) && issuer == "":
+			issuer = string(ext.Value)
+		}
+	}
+	subjects := append([]string{}, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		subjects = append(subjects, uri.String())
+	}
+	return issuer, subjects
+}
+
+// verifySignature checks the signature of the SHA256 digest of the blob, as
+// made by cosign with ECDSA and RSA keys
+func verifySignature(key crypto.PublicKey, blob, sig []byte) error {
+	digest := sha256.Sum256(blob)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var s struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(sig, &s); err != nil || len(rest) > 0 {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+		if !ecdsa.Verify(k, digest[:], s.R, s.S) {
+			return fmt.Errorf("invalid ECDSA signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig)
+	default:
+		return fmt.Errorf("unsupported key type %T", key)
+	}
+}
+
+// decodeSignature decodes the base64 signature written by cosign, returning
+// signatures that are not base64 encoded as they are
+func decodeSignature(signature []byte) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return signature
+	}
+	return decoded
+}
+
+// parsePublicKeys parses the PEM encoded public keys
+func parsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	keys := []crypto.PublicKey{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return keys, nil
+		}
+		if block.Type != "PUBLIC KEY" {
+			return nil, fmt.Errorf("unexpected %s in public keys", block.Type)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %v", err)
+		}
+		keys = append(keys, key)
+	}
+}
+
+// parseCertificates parses the PEM encoded certificate chain, which older
+// versions of cosign base64 encode, leaf first
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %v", err)
+		}
+		data = decoded
+	}
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate: %v", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificate found")
+	}
+	return certs, nil
+}
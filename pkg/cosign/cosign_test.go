@@ -0,0 +1,210 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cosign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const issuer = "https://accounts.example.com"
+
+// newKey creates an ECDSA key as created by `cosign generate-key-pair` and
+// returns it with its PEM encoded public key
+func newKey() (*ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	Expect(err).NotTo(HaveOccurred())
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// sign signs the blob as `cosign sign-blob` does
+func sign(key *ecdsa.PrivateKey, blob []byte) []byte {
+	digest := sha256.Sum256(blob)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	Expect(err).NotTo(HaveOccurred())
+	sig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	Expect(err).NotTo(HaveOccurred())
+	return []byte(base64.StdEncoding.EncodeToString(sig))
+}
+
+// newCertificate creates a certificate for the key signed by the parent, or
+// self-signed if parent is nil
+func newCertificate(template *x509.Certificate, key *ecdsa.PrivateKey, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return cert
+}
+
+func encodeCertificate(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+var _ = Describe("Cosign Suite", func() {
+	blob := []byte("apiVersion: v1\nkind: ConfigMap\n")
+
+	Context("NewVerifier", func() {
+		It("requires public keys or identities", func() {
+			_, err := NewVerifier(Options{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("requires roots to verify identities", func() {
+			_, err := NewVerifier(Options{Identities: []Identity{{Issuer: issuer, Subject: "ci@example.com"}}})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects invalid public keys", func() {
+			_, err := NewVerifier(Options{PublicKeys: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("invalid")})})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("with public keys", func() {
+		var key *ecdsa.PrivateKey
+		var verifier *Verifier
+
+		BeforeEach(func() {
+			var publicKey []byte
+			key, publicKey = newKey()
+			_, otherKey := newKey()
+
+			var err error
+			verifier, err = NewVerifier(Options{PublicKeys: append(otherKey, publicKey...)})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("verifies signatures made with a trusted key", func() {
+			Expect(verifier.Verify(blob, sign(key, blob), nil)).To(Succeed())
+		})
+
+		It("rejects signatures of other blobs", func() {
+			Expect(verifier.Verify([]byte("kind: Secret"), sign(key, blob), nil)).NotTo(Succeed())
+		})
+
+		It("rejects signatures made with other keys", func() {
+			other, _ := newKey()
+			Expect(verifier.Verify(blob, sign(other, blob), nil)).NotTo(Succeed())
+		})
+
+		It("rejects invalid signatures", func() {
+			Expect(verifier.Verify(blob, []byte("invalid"), nil)).NotTo(Succeed())
+		})
+	})
+
+	Context("with identities", func() {
+		var rootKey, key *ecdsa.PrivateKey
+		var root *x509.Certificate
+		var verifier *Verifier
+
+		newLeaf := func(email, oidcIssuer string) []byte {
+			template := &x509.Certificate{
+				SerialNumber:   big.NewInt(2),
+				Subject:        pkix.Name{},
+				EmailAddresses: []string{email},
+				NotBefore:      time.Now().Add(-time.Hour),
+				// Keyless certificates expire shortly after being issued
+				NotAfter:    time.Now().Add(-50 * time.Minute),
+				KeyUsage:    x509.KeyUsageDigitalSignature,
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+			}
+			value, err := asn1.MarshalWithParams(oidcIssuer, "utf8")
+			Expect(err).NotTo(HaveOccurred())
+			template.ExtraExtensions = []pkix.Extension{{Id: oidIssuer, Value: value}}
+			return encodeCertificate(newCertificate(template, key, root, rootKey))
+		}
+
+		BeforeEach(func() {
+			rootKey, _ = newKey()
+			key, _ = newKey()
+			root = newCertificate(&x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "example-root"},
+				NotBefore:             time.Now().Add(-24 * time.Hour),
+				NotAfter:              time.Now().Add(24 * time.Hour),
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true,
+				IsCA:                  true,
+			}, rootKey, nil, nil)
+
+			var err error
+			verifier, err = NewVerifier(Options{
+				Roots:      encodeCertificate(root),
+				Identities: []Identity{{Issuer: issuer, Subject: "ci@example.com"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("verifies signatures made by a trusted identity", func() {
+			Expect(verifier.Verify(blob, sign(key, blob), newLeaf("ci@example.com", issuer))).To(Succeed())
+		})
+
+		It("accepts base64 encoded certificates", func() {
+			certificate := []byte(base64.StdEncoding.EncodeToString(newLeaf("ci@example.com", issuer)))
+			Expect(verifier.Verify(blob, sign(key, blob), certificate)).To(Succeed())
+		})
+
+		It("rejects other identities", func() {
+			Expect(verifier.Verify(blob, sign(key, blob), newLeaf("dev@example.com", issuer))).NotTo(Succeed())
+		})
+
+		It("rejects identities authenticated by other issuers", func() {
+			Expect(verifier.Verify(blob, sign(key, blob), newLeaf("ci@example.com", "https://issuer.example.org"))).NotTo(Succeed())
+		})
+
+		It("rejects certificates issued by other roots", func() {
+			rootKey, _ = newKey()
+			root = newCertificate(&x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "other-root"},
+				NotBefore:             time.Now().Add(-24 * time.Hour),
+				NotAfter:              time.Now().Add(24 * time.Hour),
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true,
+				IsCA:                  true,
+			}, rootKey, nil, nil)
+			Expect(verifier.Verify(blob, sign(key, blob), newLeaf("ci@example.com", issuer))).NotTo(Succeed())
+		})
+
+		It("rejects signatures made with another key", func() {
+			other, _ := newKey()
+			Expect(verifier.Verify(blob, sign(other, blob), newLeaf("ci@example.com", issuer))).NotTo(Succeed())
+		})
+
+		It("requires a certificate", func() {
+			Expect(verifier.Verify(blob, sign(key, blob), nil)).NotTo(Succeed())
+		})
+	})
+})
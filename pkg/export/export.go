@@ -0,0 +1,157 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	farosclient "github.com/pusher/faros/pkg/utils/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// serverMetadata are the metadata fields set by the API server, which must
+// not be written back to a repository
+var serverMetadata = []string{
+	"creationTimestamp",
+	"deletionGracePeriodSeconds",
+	"deletionTimestamp",
+	"generation",
+	"initializers",
+	"managedFields",
+	"resourceVersion",
+	"selfLink",
+	"uid",
+}
+
+// serverAnnotations are the annotations set by clients and controllers while
+// managing a resource
+var serverAnnotations = []string{
+	farosclient.LastAppliedAnnotation,
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+}
+
+// skippedResources are the resources that are managed by the cluster itself
+// and are never exported
+var skippedResources = map[schema.GroupResource]struct{}{
+	{Group: "", Resource: "endpoints"}:                 {},
+	{Group: "", Resource: "events"}:                    {},
+	{Group: "events.k8s.io", Resource: "events"}:       {},
+	{Group: "", Resource: "secrets"}:                   {},
+	{Group: "coordination.k8s.io", Resource: "leases"}: {},
+}
+
+// SkipResource returns whether the resource is never exported, either because
+// it is managed by the cluster or by Faros or, for Secrets, because it must
+// not be committed to a repository
+func SkipResource(gr schema.GroupResource) bool {
+	if gr.Group == farosv1alpha1.SchemeGroupVersion.Group {
+		return true
+	}
+	_, ok := skippedResources[gr]
+	return ok
+}
+
+// Skip returns whether the object should be left out of the export and why.
+// Objects created by controllers, such as the ReplicaSets of a Deployment,
+// are recreated from their owner and are skipped, as are the resources
+// Kubernetes creates in every namespace.
+func Skip(u *unstructured.Unstructured) (string, bool) {
+	if ref := metav1.GetControllerOf(u); ref != nil {
+		return fmt.Sprintf("controlled by %s %s", ref.Kind, ref.Name), true
+	}
+	if u.GetKind() == "ServiceAccount" && u.GetName() == "default" {
+		return "created by Kubernetes in every namespace", true
+	}
+	return "", false
+}
+
+// Strip removes the fields set by the API server and by controllers from the
+// object so that it can be committed to a repository and applied again
+func Strip(u *unstructured.Unstructured) {
+	for _, field := range serverMetadata {
+		unstructured.RemoveNestedField(u.Object, "metadata", field)
+	}
+	annotations := u.GetAnnotations()
+	for _, annotation := range serverAnnotations {
+		delete(annotations, annotation)
+	}
+	if len(annotations) == 0 {
+		unstructured.RemoveNestedField(u.Object, "metadata", "annotations")
+	} else {
+		u.SetAnnotations(annotations)
+	}
+	unstructured.RemoveNestedField(u.Object, "status")
+
+	switch u.GetKind() {
+	case "Service":
+		// Cluster IPs are allocated by the API server unless set explicitly
+		if ip, _, _ := unstructured.NestedString(u.Object, "spec", "clusterIP"); ip != "None" {
+			unstructured.RemoveNestedField(u.Object, "spec", "clusterIP")
+		}
+	case "ServiceAccount":
+		// Token Secrets are created by the token controller
+		unstructured.RemoveNestedField(u.Object, "secrets")
+	}
+}
+
+// Adopt annotates the object so that Faros takes ownership of the existing
+// resource, rather than reporting that it already exists, once the object is
+// synced from the repository
+func Adopt(u *unstructured.Unstructured) {
+	annotations := u.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[gittrackobjectutils.AdoptAnnotation] = "true"
+	u.SetAnnotations(annotations)
+}
+
+// FilePath returns the path, relative to the export directory, of the file
+// the object is written to: `<namespace>/<kind>-<name>.yaml`, or
+// `<kind>-<name>.yaml` for cluster scoped objects
+func FilePath(u *unstructured.Unstructured) string {
+	name := strings.Replace(u.GetName(), ":", "-", -1)
+	file := fmt.Sprintf("%s-%s.yaml", strings.ToLower(u.GetKind()), name)
+	return path.Join(u.GetNamespace(), file)
+}
+
+// GitTrack returns a GitTrack that syncs the exported objects once they have
+// been committed to the subPath of the repository
+func GitTrack(name, namespace, repository, reference, subPath string) *farosv1alpha1.GitTrack {
+	return &farosv1alpha1.GitTrack{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: farosv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "GitTrack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: farosv1alpha1.GitTrackSpec{
+			Repository: repository,
+			Reference:  reference,
+			SubPath:    subPath,
+		},
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestExport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Export Suite", reporters.Reporters())
+}
+
+var _ = Describe("Export Suite", func() {
+	var newObject = func(kind, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind(kind)
+		u.SetNamespace("default")
+		u.SetName(name)
+		return u
+	}
+
+	Context("SkipResource", func() {
+		It("skips resources managed by the cluster or by Faros", func() {
+			Expect(SkipResource(schema.GroupResource{Resource: "events"})).To(BeTrue())
+			Expect(SkipResource(schema.GroupResource{Resource: "secrets"})).To(BeTrue())
+			Expect(SkipResource(schema.GroupResource{Group: "faros.pusher.com", Resource: "gittracks"})).To(BeTrue())
+		})
+
+		It("exports other resources", func() {
+			Expect(SkipResource(schema.GroupResource{Group: "apps", Resource: "deployments"})).To(BeFalse())
+			Expect(SkipResource(schema.GroupResource{Resource: "configmaps"})).To(BeFalse())
+		})
+	})
+
+	Context("Skip", func() {
+		It("skips objects controlled by another object", func() {
+			u := newObject("ReplicaSet", "example-1234")
+			isController := true
+			u.SetOwnerReferences([]metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "example", Controller: &isController},
+			})
+			reason, skipped := Skip(u)
+			Expect(skipped).To(BeTrue())
+			Expect(reason).To(Equal("controlled by Deployment example"))
+		})
+
+		It("skips the default ServiceAccount", func() {
+			_, skipped := Skip(newObject("ServiceAccount", "default"))
+			Expect(skipped).To(BeTrue())
+		})
+
+		It("exports other objects", func() {
+			_, skipped := Skip(newObject("ServiceAccount", "example"))
+			Expect(skipped).To(BeFalse())
+		})
+	})
+
+	Context("Strip", func() {
+		It("removes the fields set by the server", func() {
+			u := newObject("ConfigMap", "example")
+			u.SetUID("d7bd0e4e-8a6f-11e9-a4f4-0a580a2c0204")
+			u.SetResourceVersion("1234")
+			u.SetSelfLink("/api/v1/namespaces/default/configmaps/example")
+			u.SetCreationTimestamp(metav1.Now())
+			u.SetAnnotations(map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"example.com/owner": "team-a",
+			})
+			Expect(unstructured.SetNestedField(u.Object, "value", "data", "key")).To(Succeed())
+
+			Strip(u)
+			Expect(u.Object).To(Equal(map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata": map[string]interface{}{
+					"name":        "example",
+					"namespace":   "default",
+					"annotations": map[string]interface{}{"example.com/owner": "team-a"},
+				},
+				"data": map[string]interface{}{"key": "value"},
+			}))
+		})
+
+		It("removes the status and allocated cluster IPs", func() {
+			u := newObject("Service", "example")
+			Expect(unstructured.SetNestedField(u.Object, "10.0.0.1", "spec", "clusterIP")).To(Succeed())
+			Expect(unstructured.SetNestedField(u.Object, map[string]interface{}{}, "status", "loadBalancer")).To(Succeed())
+
+			Strip(u)
+			Expect(u.Object).NotTo(HaveKey("status"))
+			Expect(u.Object["spec"]).NotTo(HaveKey("clusterIP"))
+		})
+
+		It("keeps headless services headless", func() {
+			u := newObject("Service", "example")
+			Expect(unstructured.SetNestedField(u.Object, "None", "spec", "clusterIP")).To(Succeed())
+
+			Strip(u)
+			Expect(u.Object["spec"]).To(HaveKeyWithValue("clusterIP", "None"))
+		})
+	})
+
+	Context("Adopt", func() {
+		It("annotates the object to be adopted", func() {
+			u := newObject("ConfigMap", "example")
+			u.SetAnnotations(map[string]string{"example.com/owner": "team-a"})
+
+			Adopt(u)
+			Expect(u.GetAnnotations()).To(Equal(map[string]string{
+				"example.com/owner":      "team-a",
+				"faros.pusher.com/adopt": "true",
+			}))
+		})
+	})
+
+	Context("FilePath", func() {
+		It("writes namespaced objects to the directory of their namespace", func() {
+			Expect(FilePath(newObject("ConfigMap", "example"))).To(Equal("default/configmap-example.yaml"))
+		})
+
+		It("writes cluster scoped objects to the top level", func() {
+			u := newObject("ClusterRole", "system:example")
+			u.SetNamespace("")
+			Expect(FilePath(u)).To(Equal("clusterrole-system-example.yaml"))
+		})
+	})
+})
@@ -0,0 +1,506 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ControllerConfiguration is the format of the configuration file given by
+// the config flag. Each field sets the flag it is named after, unless that
+// flag is also given on the command line.
+type ControllerConfiguration struct {
+	// Namespaces sets the namespace flag
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// GitTrackSelector sets the gittrack-selector flag
+	GitTrackSelector string `json:"gitTrackSelector,omitempty"`
+
+	// IgnoreResources sets the ignore-resource flag
+	IgnoreResources []string `json:"ignoreResources,omitempty"`
+
+	// AllowResources sets the allow-resource flag
+	AllowResources []string `json:"allowResources,omitempty"`
+
+	// IgnoreResourcesConfigMap sets the ignore-resources-configmap flag
+	IgnoreResourcesConfigMap string `json:"ignoreResourcesConfigMap,omitempty"`
+
+	// ServerDryRun sets the server-dry-run flag
+	ServerDryRun *bool `json:"serverDryRun,omitempty"`
+
+	// DryRun sets the dry-run flag
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// SyncPeriod sets the sync-period flag
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+
+	// ShutdownTimeout sets the shutdown-timeout flag
+	ShutdownTimeout *metav1.Duration `json:"shutdownTimeout,omitempty"`
+
+	// OrphanTTL sets the orphan-ttl flag
+	OrphanTTL *metav1.Duration `json:"orphanTTL,omitempty"`
+
+	// ClientQPS sets the client-qps flag
+	ClientQPS *float32 `json:"clientQPS,omitempty"`
+
+	// ClientBurst sets the client-burst flag
+	ClientBurst *int `json:"clientBurst,omitempty"`
+
+	// ApplyRate sets the apply-rate flag
+	ApplyRate *float32 `json:"applyRate,omitempty"`
+
+	// ApplyBurst sets the apply-burst flag
+	ApplyBurst *int `json:"applyBurst,omitempty"`
+
+	// UpsertWorkers sets the upsert-workers flag
+	UpsertWorkers *int `json:"upsertWorkers,omitempty"`
+
+	// DestructiveChangeThreshold sets the destructive-change-threshold flag
+	DestructiveChangeThreshold *int `json:"destructiveChangeThreshold,omitempty"`
+
+	// RevisionHistoryLimit sets the revision-history-limit flag
+	RevisionHistoryLimit *int `json:"revisionHistoryLimit,omitempty"`
+
+	// EventAggregationWindow sets the event-aggregation-window flag
+	EventAggregationWindow *metav1.Duration `json:"eventAggregationWindow,omitempty"`
+
+	// AggregatedEventReasons sets the aggregated-event-reason flag
+	AggregatedEventReasons []string `json:"aggregatedEventReasons,omitempty"`
+
+	// ConflictThreshold sets the conflict-threshold flag
+	ConflictThreshold *int `json:"conflictThreshold,omitempty"`
+
+	// ConflictWindow sets the conflict-window flag
+	ConflictWindow *metav1.Duration `json:"conflictWindow,omitempty"`
+
+	// SlackWebhookURLs sets the slack-webhook-url flag
+	SlackWebhookURLs []string `json:"slackWebhookURLs,omitempty"`
+
+	// NotificationWebhookURLs sets the notification-webhook-url flag
+	NotificationWebhookURLs []string `json:"notificationWebhookURLs,omitempty"`
+
+	// LeaderElection sets the leader-election flag
+	LeaderElection *bool `json:"leaderElection,omitempty"`
+
+	// LeaderElectionID sets the leader-election-id flag
+	LeaderElectionID string `json:"leaderElectionID,omitempty"`
+
+	// LeaderElectionNamespace sets the leader-election-namespace flag
+	LeaderElectionNamespace string `json:"leaderElectionNamespace,omitempty"`
+
+	// LogLevel sets the log-level flag
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// ControllerLogLevels sets the controller-log-level flag
+	ControllerLogLevels []string `json:"controllerLogLevels,omitempty"`
+
+	// LogLevelTokenFile sets the log-level-token-file flag
+	LogLevelTokenFile string `json:"logLevelTokenFile,omitempty"`
+
+	// BackupURL sets the backup-url flag
+	BackupURL string `json:"backupURL,omitempty"`
+
+	// BackupEndpoint sets the backup-endpoint flag
+	BackupEndpoint string `json:"backupEndpoint,omitempty"`
+
+	// BackupRegion sets the backup-region flag
+	BackupRegion string `json:"backupRegion,omitempty"`
+
+	// HighPriorityWorkers sets the high-priority-workers flag
+	HighPriorityWorkers *int `json:"highPriorityWorkers,omitempty"`
+
+	// GitHostRate sets the git-host-rate flag
+	GitHostRate *float32 `json:"gitHostRate,omitempty"`
+
+	// GitHostBurst sets the git-host-burst flag
+	GitHostBurst *int `json:"gitHostBurst,omitempty"`
+}
+
+// LoadConfigFile reads a ControllerConfiguration from the YAML file at path,
+// unknown fields are rejected
+func LoadConfigFile(path string) (*ControllerConfiguration, error) {
+	_, config, err := (&ConfigLoader{path: path}).read()
+	return config, err
+}
+
+// Apply sets the flags in the flag set from the configuration. Flags that
+// were given on the command line are left unchanged so that they override
+// the configuration.
+func (c *ControllerConfiguration) Apply(fs *flag.FlagSet) error {
+	values, err := c.flagValues()
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		f := fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("unknown flag %s in configuration", name)
+		}
+		if f.Changed {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("invalid value for %s in configuration: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// flagValues returns the string values of the fields set in the
+// configuration keyed by the name of the flag they set
+func (c *ControllerConfiguration) flagValues() (map[string]string, error) {
+	values := make(map[string]string)
+	setString := func(name, value string) {
+		if value != "" {
+			values[name] = value
+		}
+	}
+	setBool := func(name string, value *bool) {
+		if value != nil {
+			values[name] = strconv.FormatBool(*value)
+		}
+	}
+	setInt := func(name string, value *int) {
+		if value != nil {
+			values[name] = strconv.Itoa(*value)
+		}
+	}
+	setFloat := func(name string, value *float32) {
+		if value != nil {
+			values[name] = strconv.FormatFloat(float64(*value), 'f', -1, 32)
+		}
+	}
+	setDuration := func(name string, value *metav1.Duration) {
+		if value != nil {
+			values[name] = value.Duration.String()
+		}
+	}
+	var sliceErr error
+	setStrings := func(name string, value []string) {
+		if value == nil {
+			return
+		}
+		joined, err := joinSlice(value)
+		if err != nil {
+			sliceErr = fmt.Errorf("invalid value for %s in configuration: %v", name, err)
+			return
+		}
+		values[name] = joined
+	}
+
+	setStrings("namespace", c.Namespaces)
+	setString("gittrack-selector", c.GitTrackSelector)
+	setStrings("ignore-resource", c.IgnoreResources)
+	setStrings("allow-resource", c.AllowResources)
+	setString("ignore-resources-configmap", c.IgnoreResourcesConfigMap)
+	setBool("server-dry-run", c.ServerDryRun)
+	setBool("dry-run", c.DryRun)
+	setDuration("sync-period", c.SyncPeriod)
+	setDuration("shutdown-timeout", c.ShutdownTimeout)
+	setDuration("orphan-ttl", c.OrphanTTL)
+	setFloat("client-qps", c.ClientQPS)
+	setInt("client-burst", c.ClientBurst)
+	setFloat("apply-rate", c.ApplyRate)
+	setInt("apply-burst", c.ApplyBurst)
+	setInt("upsert-workers", c.UpsertWorkers)
+	setInt("destructive-change-threshold", c.DestructiveChangeThreshold)
+	setInt("revision-history-limit", c.RevisionHistoryLimit)
+	setDuration("event-aggregation-window", c.EventAggregationWindow)
+	setStrings("aggregated-event-reason", c.AggregatedEventReasons)
+	setInt("conflict-threshold", c.ConflictThreshold)
+	setDuration("conflict-window", c.ConflictWindow)
+	setStrings("slack-webhook-url", c.SlackWebhookURLs)
+	setStrings("notification-webhook-url", c.NotificationWebhookURLs)
+	setBool("leader-election", c.LeaderElection)
+	setString("leader-election-id", c.LeaderElectionID)
+	setString("leader-election-namespace", c.LeaderElectionNamespace)
+	setString("log-level", c.LogLevel)
+	setStrings("controller-log-level", c.ControllerLogLevels)
+	setString("log-level-token-file", c.LogLevelTokenFile)
+	setString("backup-url", c.BackupURL)
+	setString("backup-endpoint", c.BackupEndpoint)
+	setString("backup-region", c.BackupRegion)
+	setInt("high-priority-workers", c.HighPriorityWorkers)
+	setFloat("git-host-rate", c.GitHostRate)
+	setInt("git-host-burst", c.GitHostBurst)
+	return values, sliceErr
+}
+
+// joinSlice joins the values in the comma separated format parsed by string
+// slice flags, quoting values that contain commas
+func joinSlice(values []string) (string, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	if err := w.Write(values); err != nil {
+		return "", err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+var (
+	// reloadMutex guards the reloadable flags while they are being changed
+	reloadMutex sync.RWMutex
+
+	// reloadableFlags are the variables of the flags that are set again when
+	// the configuration file changes, keyed by flag name. Changes to other
+	// flags require a restart.
+	reloadableFlags = make(map[string]interface{})
+
+	// reloadHandlers are called after the reloadable flags have changed
+	reloadHandlers []func()
+)
+
+// RegisterReloadable marks the flag as one that may be changed at runtime by
+// reloading the configuration file. The value must be the *string,
+// *[]string or *time.Duration the flag was defined with, and must only be read
+// while holding the lock given by RLockReloadable.
+func RegisterReloadable(name string, value interface{}) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	reloadableFlags[name] = value
+}
+
+// RLockReloadable locks the reloadable flags for reading, returning the
+// function that unlocks them
+func RLockReloadable() func() {
+	reloadMutex.RLock()
+	return reloadMutex.RUnlock
+}
+
+// setReloadable sets the variable of a reloadable flag from its string value
+func setReloadable(value interface{}, s string) error {
+	switch v := value.(type) {
+	case *string:
+		*v = s
+	case *[]string:
+		values := []string{}
+		if s != "" {
+			r := csv.NewReader(strings.NewReader(s))
+			record, err := r.Read()
+			if err != nil {
+				return err
+			}
+			values = record
+		}
+		*v = values
+	case *time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		if d < 0 {
+			return fmt.Errorf("must not be negative")
+		}
+		*v = d
+	default:
+		return fmt.Errorf("unsupported reloadable flag type %T", value)
+	}
+	return nil
+}
+
+// OnReload registers a function that is called after the configuration file
+// has been reloaded and the reloadable flags have changed
+func OnReload(fn func()) {
+	reloadMutex.Lock()
+	defer reloadMutex.Unlock()
+	reloadHandlers = append(reloadHandlers, fn)
+}
+
+// Change describes a value that changed in the configuration file
+type Change struct {
+	// Flag is the name of the flag the changed field sets
+	Flag string
+
+	// Old is the previous value
+	Old string
+
+	// New is the value in the configuration file
+	New string
+
+	// Applied is false when the flag was not changed, because it requires a
+	// restart or was given on the command line
+	Applied bool
+}
+
+// String describes the change
+func (c Change) String() string {
+	description := fmt.Sprintf("%s changed from %q to %q", c.Flag, c.Old, c.New)
+	if !c.Applied {
+		description += " (not applied)"
+	}
+	return description
+}
+
+// ConfigLoader applies a configuration file to a flag set and applies the
+// reloadable flags again whenever the file changes
+type ConfigLoader struct {
+	path        string
+	fs          *flag.FlagSet
+	commandLine map[string]bool
+	data        []byte
+	values      map[string]string
+}
+
+// NewConfigLoader creates a ConfigLoader for the configuration file at path.
+// It must be created after the flags are parsed so that the flags given on
+// the command line are never overridden.
+func NewConfigLoader(fs *flag.FlagSet, path string) *ConfigLoader {
+	commandLine := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		commandLine[f.Name] = true
+	})
+	return &ConfigLoader{path: path, fs: fs, commandLine: commandLine}
+}
+
+// Load reads the configuration file and sets the flags from it
+func (l *ConfigLoader) Load() error {
+	data, config, err := l.read()
+	if err != nil {
+		return err
+	}
+	values, err := config.flagValues()
+	if err != nil {
+		return err
+	}
+	if err := config.Apply(l.fs); err != nil {
+		return err
+	}
+	l.data, l.values = data, values
+	return nil
+}
+
+// Reload reads the configuration file again and, if it has changed, applies
+// the reloadable flags and calls the reload handlers. It returns the changes
+// found in the file, fields removed from the file reset their flag to its
+// default value.
+func (l *ConfigLoader) Reload() ([]Change, error) {
+	data, config, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, l.data) {
+		return nil, nil
+	}
+	values, err := config.flagValues()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range l.values {
+		names[name] = true
+	}
+	for name := range values {
+		names[name] = true
+	}
+
+	reloadMutex.Lock()
+	changes := []Change{}
+	for name := range names {
+		old, ok := l.values[name]
+		value, set := values[name]
+		if ok == set && old == value {
+			continue
+		}
+		f := l.fs.Lookup(name)
+		if f == nil {
+			reloadMutex.Unlock()
+			return nil, fmt.Errorf("unknown flag %s in configuration", name)
+		}
+		if !set {
+			// Slice flags format their default as [a,b]
+			value = f.DefValue
+			if f.Value.Type() == "stringSlice" {
+				value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+			}
+		}
+		change := Change{Flag: name, Old: f.Value.String(), New: value}
+		if variable, ok := reloadableFlags[name]; ok && !l.commandLine[name] {
+			if err := setReloadable(variable, value); err != nil {
+				reloadMutex.Unlock()
+				return nil, fmt.Errorf("invalid value for %s in configuration: %v", name, err)
+			}
+			change.New = f.Value.String()
+			change.Applied = true
+		}
+		changes = append(changes, change)
+	}
+	handlers := reloadHandlers
+	reloadMutex.Unlock()
+
+	l.data, l.values = data, values
+	for _, change := range changes {
+		if change.Applied {
+			for _, handler := range handlers {
+				handler()
+			}
+			break
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Flag < changes[j].Flag
+	})
+	return changes, nil
+}
+
+// Watch reloads the configuration file every interval until the stop channel
+// is closed, calling fn with the result of every reload that found changes
+// or failed
+func (l *ConfigLoader) Watch(interval time.Duration, stop <-chan struct{}, fn func([]Change, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			changes, err := l.Reload()
+			if err != nil || len(changes) > 0 {
+				fn(changes, err)
+			}
+		}
+	}
+}
+
+// read reads and parses the configuration file
+func (l *ConfigLoader) read() ([]byte, *ControllerConfiguration, error) {
+	data, err := ioutil.ReadFile(l.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read config file: %v", err)
+	}
+	config := &ControllerConfiguration{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse config file %s: %v", l.path, err)
+	}
+	return data, config, nil
+}
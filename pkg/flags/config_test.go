@@ -0,0 +1,202 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	flag "github.com/spf13/pflag"
+)
+
+var _ = Describe("ControllerConfiguration", func() {
+	var dir string
+	var fs *flag.FlagSet
+	var namespaces, reasons []string
+	var workers int
+	var syncPeriod time.Duration
+	var dryRun bool
+
+	var writeConfig = func(content string) string {
+		path := filepath.Join(dir, "config.yaml")
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "faros-config")
+		Expect(err).NotTo(HaveOccurred())
+
+		fs = flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.StringSliceVar(&namespaces, "namespace", []string{}, "")
+		fs.StringSliceVar(&reasons, "aggregated-event-reason", []string{"UpdateSuccessful"}, "")
+		fs.IntVar(&workers, "upsert-workers", 10, "")
+		fs.DurationVar(&syncPeriod, "sync-period", 5*time.Minute, "")
+		fs.BoolVar(&dryRun, "dry-run", false, "")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("sets the flags from the configuration file", func() {
+		config, err := LoadConfigFile(writeConfig(`
+namespaces: [default, "kube,system"]
+upsertWorkers: 4
+syncPeriod: 1m
+dryRun: true
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fs.Parse([]string{})).To(Succeed())
+		Expect(config.Apply(fs)).To(Succeed())
+
+		Expect(namespaces).To(Equal([]string{"default", "kube,system"}))
+		Expect(workers).To(Equal(4))
+		Expect(syncPeriod).To(Equal(time.Minute))
+		Expect(dryRun).To(BeTrue())
+		Expect(reasons).To(Equal([]string{"UpdateSuccessful"}))
+	})
+
+	It("lets flags given on the command line override the configuration file", func() {
+		config, err := LoadConfigFile(writeConfig("upsertWorkers: 4\nnamespaces: [default]\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fs.Parse([]string{"--upsert-workers=2"})).To(Succeed())
+		Expect(config.Apply(fs)).To(Succeed())
+
+		Expect(workers).To(Equal(2))
+		Expect(namespaces).To(Equal([]string{"default"}))
+	})
+
+	It("rejects unknown fields", func() {
+		_, err := LoadConfigFile(writeConfig("upsertWorker: 4\n"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	Context("ConfigLoader", func() {
+		var path string
+		var loader *ConfigLoader
+
+		BeforeEach(func() {
+			fs.StringSliceVar(&ignoredResources, "ignore-resource", []string{}, "")
+			path = writeConfig("ignoreResources: [jobs.batch/v1]\nupsertWorkers: 4\n")
+		})
+
+		JustBeforeEach(func() {
+			loader = NewConfigLoader(fs, path)
+			Expect(loader.Load()).To(Succeed())
+		})
+
+		It("loads the configuration file", func() {
+			Expect(fs.Parse([]string{})).To(Succeed())
+			Expect(ignoredResources).To(Equal([]string{"jobs.batch/v1"}))
+			Expect(workers).To(Equal(4))
+		})
+
+		It("reports no changes when the file has not changed", func() {
+			changes, err := loader.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+
+		It("applies changes to reloadable flags and calls the reload handlers", func() {
+			reloaded := make(chan struct{}, 1)
+			OnReload(func() {
+				select {
+				case reloaded <- struct{}{}:
+				default:
+				}
+			})
+
+			writeConfig("ignoreResources: [jobs.batch/v1, cronjobs.batch/v1beta1]\nupsertWorkers: 4\n")
+			changes, err := loader.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ConsistOf(Change{
+				Flag:    "ignore-resource",
+				Old:     "[jobs.batch/v1]",
+				New:     "[jobs.batch/v1,cronjobs.batch/v1beta1]",
+				Applied: true,
+			}))
+			Expect(ignoredResources).To(Equal([]string{"jobs.batch/v1", "cronjobs.batch/v1beta1"}))
+			Expect(reloaded).To(Receive())
+		})
+
+		It("resets reloadable flags removed from the file to their default", func() {
+			writeConfig("upsertWorkers: 4\n")
+			_, err := loader.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ignoredResources).To(BeEmpty())
+		})
+
+		It("reports changes to other flags without applying them", func() {
+			writeConfig("ignoreResources: [jobs.batch/v1]\nupsertWorkers: 8\n")
+			changes, err := loader.Reload()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(HaveLen(1))
+			Expect(changes[0].Flag).To(Equal("upsert-workers"))
+			Expect(changes[0].Applied).To(BeFalse())
+			Expect(changes[0].String()).To(ContainSubstring("not applied"))
+			Expect(workers).To(Equal(4))
+		})
+
+		It("returns an error when the file is invalid", func() {
+			writeConfig("ignoreResources: jobs.batch/v1\n")
+			_, err := loader.Reload()
+			Expect(err).To(HaveOccurred())
+			Expect(ignoredResources).To(Equal([]string{"jobs.batch/v1"}))
+		})
+
+		Context("when a reloadable flag was given on the command line", func() {
+			BeforeEach(func() {
+				Expect(fs.Parse([]string{"--ignore-resource=pods/v1"})).To(Succeed())
+			})
+
+			It("is not overridden", func() {
+				writeConfig("ignoreResources: [cronjobs.batch/v1beta1]\nupsertWorkers: 4\n")
+				changes, err := loader.Reload()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(changes).To(HaveLen(1))
+				Expect(changes[0].Applied).To(BeFalse())
+				Expect(ignoredResources).To(Equal([]string{"pods/v1"}))
+			})
+		})
+	})
+
+	Context("setReloadable", func() {
+		It("sets durations", func() {
+			var period time.Duration
+			Expect(setReloadable(&period, "2m")).To(Succeed())
+			Expect(period).To(Equal(2 * time.Minute))
+		})
+
+		It("rejects negative durations", func() {
+			period := time.Minute
+			Expect(setReloadable(&period, "-1m")).NotTo(Succeed())
+			Expect(period).To(Equal(time.Minute))
+		})
+	})
+
+	It("rejects fields for flags that are not defined", func() {
+		config, err := LoadConfigFile(writeConfig("applyRate: 5\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Apply(fs)).To(MatchError(ContainSubstring("apply-rate")))
+	})
+})
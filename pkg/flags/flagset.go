@@ -19,51 +19,309 @@ package flags
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	flag "github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 var (
 	// FlagSet contains faros flags that are needed in multiple packages
 	FlagSet *flag.FlagSet
 
-	// Namespace is the namespace for the controller to be restricted to
-	Namespace string
+	// Namespaces are the namespaces the controller is restricted to, all
+	// namespaces are managed if empty
+	Namespaces []string
+
+	// gitTrackSelector is a label selector restricting the GitTracks the
+	// controller manages
+	gitTrackSelector string
 
 	// ignoredResources is a list of Kubernets kinds to ignore when reconciling
 	ignoredResources []string
 
+	// allowedResources is a list of Kubernetes kinds that may be managed, all
+	// kinds may be managed if empty
+	allowedResources []string
+
+	// syncPeriod is the period after which the informer caches are resynced
+	// and GitTracks are reconciled again, 0 disables resyncs
+	syncPeriod time.Duration
+
+	// IgnoredResourcesConfigMap is the ConfigMap, in <namespace>/<name> format,
+	// listing further resources to ignore which may be changed at runtime
+	IgnoredResourcesConfigMap string
+
 	// ServerDryRun whether to enable Server side dry run or not
 	ServerDryRun bool
+
+	// DryRun makes the controllers report the changes they would make
+	// without creating, updating or deleting any managed resources
+	DryRun bool
+
+	// ClientQPS is the maximum queries per second the GitTrackObject
+	// controller's API client may make
+	ClientQPS float32
+
+	// ClientBurst is the maximum burst of queries the GitTrackObject
+	// controller's API client may make
+	ClientBurst int
+
+	// ApplyRate is the maximum number of child applies per second, 0 disables
+	// apply rate limiting
+	ApplyRate float32
+
+	// ApplyBurst is the maximum burst of child applies
+	ApplyBurst int
+
+	// UpsertWorkers is the number of GitTrackObjects a GitTrack reconcile
+	// creates or updates concurrently
+	UpsertWorkers int
+
+	// OrphanTTL is how long GitTrackObjects retained by a prune policy are
+	// kept before being deleted, 0 keeps them indefinitely
+	OrphanTTL time.Duration
+
+	// DestructiveChangeThreshold is the number of children a single reconcile
+	// may delete or recreate before approval is required, 0 disables approval
+	DestructiveChangeThreshold int
+
+	// RevisionHistoryLimit is the default number of GitTrackRevisions kept for
+	// each GitTrack, 0 disables recording revisions
+	RevisionHistoryLimit int
+
+	// EventAggregationWindow is the window within which repeated events for a
+	// GitTrackObject are collapsed, 0 disables aggregation
+	EventAggregationWindow time.Duration
+
+	// AggregatedEventReasons is the list of event reasons that are aggregated
+	AggregatedEventReasons []string
+
+	// ConflictThreshold is the number of times a child may be reset within the
+	// conflict window before faros backs off from it, 0 disables the check
+	ConflictThreshold int
+
+	// ConflictWindow is the window within which the resets of a child are
+	// counted, and how long faros backs off from the child for
+	ConflictWindow time.Duration
+
+	// SlackWebhookURLs are the Slack incoming webhooks notified when a
+	// GitTrack fails to sync or recovers
+	SlackWebhookURLs []string
+
+	// NotificationWebhookURLs are the generic webhooks notified when a
+	// GitTrack fails to sync or recovers
+	NotificationWebhookURLs []string
+
+	// ImageUpdateInterval is how often the image registries of GitTracks with
+	// image updates are checked for newer tags, 0 disables the image update
+	// controller
+	ImageUpdateInterval time.Duration
+
+	// GitAuthorName is the name of the author of commits Faros makes
+	GitAuthorName string
+
+	// GitAuthorEmail is the email of the author of commits Faros makes
+	GitAuthorEmail string
+
+	// ClusterName identifies the cluster Faros is running in within the sync
+	// reports it writes to repositories
+	ClusterName string
+
+	// BackupURL is the s3:// or gs:// URL of the bucket, and optional prefix,
+	// the manifests of each successful sync are backed up to
+	BackupURL string
+
+	// BackupEndpoint overrides the endpoint of the object storage API, for
+	// S3 compatible stores
+	BackupEndpoint string
+
+	// BackupRegion is the region of the bucket backups are written to
+	BackupRegion string
+
+	// HighPriorityWorkers is the number of GitTracks with High priority that
+	// are reconciled concurrently
+	HighPriorityWorkers int
+
+	// GitHostRate is the maximum number of git operations per second against
+	// each git host, 0 disables the limit
+	GitHostRate float32
+
+	// GitHostBurst is the maximum burst of git operations against each host
+	GitHostBurst int
+
+	// CompressDataThreshold is the size in bytes above which the data of
+	// GitTrackObjects is compressed, 0 disables compression
+	CompressDataThreshold int
+
+	// ExternalDataThreshold is the size in bytes, once compressed, above which
+	// the data of GitTrackObjects is stored in ConfigMaps, 0 disables
+	// external storage
+	ExternalDataThreshold int
 )
 
 func init() {
 	FlagSet = flag.NewFlagSet("faros", flag.PanicOnError)
-	FlagSet.StringVar(&Namespace, "namespace", "", "Only manage GitTrack resources in given namespace")
-	FlagSet.StringSliceVar(&ignoredResources, "ignore-resource", []string{}, "Ignore resources of these kinds found in repositories, specified in <resource>.<group>/<version> format eg jobs.batch/v1")
+	FlagSet.StringSliceVar(&Namespaces, "namespace", []string{}, "Only manage GitTrack resources in the given namespaces, may be repeated or comma separated")
+	FlagSet.StringVar(&gitTrackSelector, "gittrack-selector", "", "Only manage GitTracks matching this label selector eg shard=a or team in (a,b)")
+	FlagSet.StringSliceVar(&ignoredResources, "ignore-resource", []string{}, "Ignore resources of these kinds found in repositories, specified in <resource>.<group>/<version> format eg jobs.batch/v1, any part may be a * wildcard and the version may be omitted eg *.monitoring.coreos.com")
+	FlagSet.StringSliceVar(&allowedResources, "allow-resource", []string{}, "Only manage resources of these kinds, specified in <resource>.<group>/<version> format eg deployments.apps/v1, all kinds are managed if not set")
+	FlagSet.DurationVar(&syncPeriod, "sync-period", 5*time.Minute, "Period after which the informer caches are resynced and every cached resource is reconciled, 0 disables resyncs. Changes from the config file requeue GitTracks with the new period, the informer caches keep the period Faros started with")
+	FlagSet.StringVar(&IgnoredResourcesConfigMap, "ignore-resources-configmap", "", "ConfigMap, in <namespace>/<name> format, listing further resources to ignore, changes are picked up without restarting")
 	FlagSet.BoolVar(&ServerDryRun, "server-dry-run", true, "Enable/Disable server side dry run before updating resources")
+	FlagSet.BoolVar(&DryRun, "dry-run", false, "Report the changes Faros would make without creating, updating or deleting any managed resources")
+	FlagSet.Float32Var(&ClientQPS, "client-qps", 0, "Maximum queries per second to the API server when managing child resources, 0 uses the client default")
+	FlagSet.IntVar(&ClientBurst, "client-burst", 0, "Maximum burst of queries to the API server when managing child resources, 0 uses the client default")
+	FlagSet.Float32Var(&ApplyRate, "apply-rate", 0, "Maximum number of child resources to create or update per second, 0 disables the limit")
+	FlagSet.IntVar(&ApplyBurst, "apply-burst", 1, "Maximum burst of child resources to create or update when apply-rate is set")
+	FlagSet.IntVar(&UpsertWorkers, "upsert-workers", 10, "Number of GitTrackObjects each GitTrack reconcile creates or updates concurrently")
+	FlagSet.IntVar(&DestructiveChangeThreshold, "destructive-change-threshold", 0, "Require approval before a GitTrack deletes or recreates more than this many children in one reconcile, 0 disables approval")
+	FlagSet.IntVar(&RevisionHistoryLimit, "revision-history-limit", 10, "Default number of GitTrackRevisions to keep for each GitTrack, 0 disables recording revisions")
+	FlagSet.DurationVar(&EventAggregationWindow, "event-aggregation-window", 5*time.Minute, "Collapse repeated GitTrackObject events with the same reason within this window, 0 disables aggregation")
+	FlagSet.StringSliceVar(&AggregatedEventReasons, "aggregated-event-reason", []string{"UpdateSuccessful", "DriftCorrected"}, "Reasons of the GitTrackObject events to aggregate")
+	FlagSet.IntVar(&ConflictThreshold, "conflict-threshold", 5, "Back off from a child once its out-of-band changes have been reset this many times within the conflict window, 0 disables the check")
+	FlagSet.DurationVar(&ConflictWindow, "conflict-window", 5*time.Minute, "Window within which resets of a child are counted, and how long Faros backs off from a child in conflict")
+	FlagSet.StringSliceVar(&SlackWebhookURLs, "slack-webhook-url", []string{}, "Slack incoming webhook URLs to notify when a GitTrack fails to sync or recovers")
+	FlagSet.StringSliceVar(&NotificationWebhookURLs, "notification-webhook-url", []string{}, "Webhook URLs to post JSON notifications to when a GitTrack fails to sync or recovers")
+	FlagSet.DurationVar(&OrphanTTL, "orphan-ttl", 0, "Delete GitTrackObjects retained by a prune policy after this duration, 0 disables the orphan controller")
+	FlagSet.DurationVar(&ImageUpdateInterval, "image-update-interval", 0, "Check the image registries of GitTracks with image updates for newer tags this often, 0 disables the image update controller")
+	FlagSet.StringVar(&GitAuthorName, "git-author-name", "Faros", "Name of the author of commits Faros makes to repositories")
+	FlagSet.StringVar(&GitAuthorEmail, "git-author-email", "faros@pusher.com", "Email of the author of commits Faros makes to repositories")
+	FlagSet.StringVar(&ClusterName, "cluster-name", "", "Name of the cluster Faros is running in, recorded in sync reports and backups")
+	FlagSet.StringVar(&BackupURL, "backup-url", "", "Back up the manifests of each successful sync to this bucket, in s3://<bucket>/<prefix> or gs://<bucket>/<prefix> format, backups are disabled if empty")
+	FlagSet.StringVar(&BackupEndpoint, "backup-endpoint", "", "Endpoint of the object storage API backups are written to, defaults to the endpoint of the bucket's provider")
+	FlagSet.StringVar(&BackupRegion, "backup-region", "us-east-1", "Region of the bucket backups are written to")
+	FlagSet.Float32Var(&GitHostRate, "git-host-rate", 0, "Maximum number of git clones, fetches and pushes per second against each git host, 0 disables the limit")
+	FlagSet.IntVar(&GitHostBurst, "git-host-burst", 1, "Maximum burst of git operations against each git host when git-host-rate is set")
+	FlagSet.IntVar(&HighPriorityWorkers, "high-priority-workers", 1, "Number of GitTracks with High priority reconciled concurrently, separately from other GitTracks")
+	FlagSet.IntVar(&CompressDataThreshold, "compress-data-threshold", 256*1024, "Gzip the data of GitTrackObjects larger than this many bytes to keep them below the etcd request size limit, 0 disables compression")
+	FlagSet.IntVar(&ExternalDataThreshold, "external-data-threshold", 512*1024, "Store the data of GitTrackObjects larger than this many bytes once compressed in ConfigMaps in the namespace of their GitTrack, 0 disables external storage")
+
+	// The resource lists and sync period may be changed by reloading the
+	// configuration file
+	RegisterReloadable("ignore-resource", &ignoredResources)
+	RegisterReloadable("allow-resource", &allowedResources)
+	RegisterReloadable("sync-period", &syncPeriod)
+}
+
+// SyncPeriod returns the value of the sync-period flag
+func SyncPeriod() time.Duration {
+	defer RLockReloadable()()
+	return syncPeriod
+}
+
+// ManagesNamespace returns whether resources in the namespace are managed by
+// the controller
+func ManagesNamespace(namespace string) bool {
+	if len(Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseGitTrackSelector parses the gittrack-selector flag value, all GitTracks
+// are selected if it is empty
+func ParseGitTrackSelector() (labels.Selector, error) {
+	selector, err := labels.Parse(gitTrackSelector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse gittrack selector %q: %v", gitTrackSelector, err)
+	}
+	return selector, nil
 }
 
 // ParseIgnoredResources attempts to parse the ignore-resource flag value and
 // create a set of GroupVersionResources from the slice
 func ParseIgnoredResources() (map[schema.GroupVersionResource]interface{}, error) {
+	defer RLockReloadable()()
+	return ParseResources(ignoredResources)
+}
+
+// ParseAllowedResources attempts to parse the allow-resource flag value and
+// create a set of GroupVersionResources from the slice
+func ParseAllowedResources() (map[schema.GroupVersionResource]interface{}, error) {
+	defer RLockReloadable()()
+	return ParseResources(allowedResources)
+}
+
+// ParseIgnoredResourcesConfigMap parses the ignore-resources-configmap flag
+// value, returning nil if it is not set
+func ParseIgnoredResourcesConfigMap() (*types.NamespacedName, error) {
+	if IgnoredResourcesConfigMap == "" {
+		return nil, nil
+	}
+	split := strings.Split(IgnoredResourcesConfigMap, "/")
+	if len(split) != 2 || split[0] == "" || split[1] == "" {
+		return nil, fmt.Errorf("%s is invalid, should be of format <namespace>/<name>", IgnoredResourcesConfigMap)
+	}
+	return &types.NamespacedName{Namespace: split[0], Name: split[1]}, nil
+}
+
+// ResourceWildcard matches any resource, group or version when given as one
+// of them
+const ResourceWildcard = "*"
+
+// ParseResources attempts to parse a list of resources in
+// <resource>.<group>/<version> format and create a set of
+// GroupVersionResources from the slice.
+//
+// Any of resource, group and version may be the ResourceWildcard. A lone
+// wildcard in place of <resource>.<group> matches every resource in every
+// group, and omitting the version matches every version, eg.
+// *.monitoring.coreos.com or */v1beta1
+func ParseResources(resources []string) (map[schema.GroupVersionResource]interface{}, error) {
 	gvrs := make(map[schema.GroupVersionResource]interface{})
-	for _, ignored := range ignoredResources {
-		if !strings.Contains(ignored, ".") || !strings.Contains(ignored, "/") {
-			return nil, fmt.Errorf("%s is invalid, should be of format <resource>.<group>/<version>", ignored)
-		}
-		split := strings.SplitN(ignored, ".", 2)
-		gv, err := schema.ParseGroupVersion(split[1])
+	for _, resource := range resources {
+		gvr, err := parseResource(resource)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse group version %s: %v", split[1], err)
-		}
-		gvr := schema.GroupVersionResource{
-			Group:    gv.Group,
-			Version:  gv.Version,
-			Resource: split[0],
+			return nil, err
 		}
 		gvrs[gvr] = nil
 	}
 	return gvrs, nil
 }
+
+func parseResource(resource string) (schema.GroupVersionResource, error) {
+	resourceGroup, version := resource, ResourceWildcard
+	if i := strings.Index(resource, "/"); i >= 0 {
+		resourceGroup, version = resource[:i], resource[i+1:]
+	}
+	if resourceGroup == ResourceWildcard {
+		resourceGroup = ResourceWildcard + "." + ResourceWildcard
+	}
+	split := strings.SplitN(resourceGroup, ".", 2)
+	if len(split) != 2 || split[0] == "" || version == "" {
+		return schema.GroupVersionResource{}, fmt.Errorf("%s is invalid, should be of format <resource>.<group>/<version>", resource)
+	}
+	gv, err := schema.ParseGroupVersion(split[1] + "/" + version)
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("unable to parse group version %s/%s: %v", split[1], version, err)
+	}
+	return schema.GroupVersionResource{
+		Group:    gv.Group,
+		Version:  gv.Version,
+		Resource: split[0],
+	}, nil
+}
+
+// MatchResource returns the value stored in the set for the first entry that
+// matches the GroupVersionResource, preferring exact matches over wildcards
+func MatchResource(gvrs map[schema.GroupVersionResource]interface{}, gvr schema.GroupVersionResource) (interface{}, bool) {
+	for _, resource := range []string{gvr.Resource, ResourceWildcard} {
+		for _, group := range []string{gvr.Group, ResourceWildcard} {
+			for _, version := range []string{gvr.Version, ResourceWildcard} {
+				key := schema.GroupVersionResource{Group: group, Version: version, Resource: resource}
+				if value, ok := gvrs[key]; ok {
+					return value, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pusher/faros/test/reporters"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -63,4 +64,151 @@ var _ = Describe("FlagSet Suite", func() {
 			Expect(ok).To(BeTrue())
 		})
 	})
+
+	Context("ParseAllowedResources", func() {
+		AfterEach(func() {
+			allowedResources = []string{}
+		})
+
+		It("allows all resources when none are given", func() {
+			gvrs, err := ParseAllowedResources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gvrs).To(BeEmpty())
+		})
+
+		It("parses the allowed resources", func() {
+			Expect(FlagSet.Parse([]string{"--allow-resource=deployments.apps/v1,services./v1"})).To(Succeed())
+			gvrs, err := ParseAllowedResources()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gvrs).To(HaveKey(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}))
+			Expect(gvrs).To(HaveKey(schema.GroupVersionResource{Version: "v1", Resource: "services"}))
+		})
+	})
+
+	Context("ParseResources", func() {
+		It("parses wildcards", func() {
+			gvrs, err := ParseResources([]string{"*.monitoring.coreos.com", "*/v1beta1", "jobs.*/v1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gvrs).To(HaveKey(schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "*", Resource: "*"}))
+			Expect(gvrs).To(HaveKey(schema.GroupVersionResource{Group: "*", Version: "v1beta1", Resource: "*"}))
+			Expect(gvrs).To(HaveKey(schema.GroupVersionResource{Group: "*", Version: "v1", Resource: "jobs"}))
+		})
+
+		It("matches every version when the version is omitted", func() {
+			gvrs, err := ParseResources([]string{"jobs.batch"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gvrs).To(HaveKey(schema.GroupVersionResource{Group: "batch", Version: "*", Resource: "jobs"}))
+		})
+
+		It("errors for invalid resources", func() {
+			for _, resource := range []string{"jobs", "pods/v1", ".batch/v1", "jobs.batch/", "jobs.batch/v1/extra"} {
+				_, err := ParseResources([]string{resource})
+				Expect(err).To(HaveOccurred(), resource)
+			}
+		})
+	})
+
+	Context("MatchResource", func() {
+		var gvrs map[schema.GroupVersionResource]interface{}
+
+		BeforeEach(func() {
+			var err error
+			gvrs, err = ParseResources([]string{"*.monitoring.coreos.com", "*/v1beta1", "jobs.batch/v1"})
+			Expect(err).NotTo(HaveOccurred())
+			gvrs[schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}] = "exact"
+		})
+
+		It("matches resources exactly", func() {
+			value, ok := MatchResource(gvrs, schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"})
+			Expect(ok).To(BeTrue())
+			Expect(value).To(Equal("exact"))
+		})
+
+		It("matches every resource in a group", func() {
+			_, ok := MatchResource(gvrs, schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"})
+			Expect(ok).To(BeTrue())
+		})
+
+		It("matches every resource of a version", func() {
+			_, ok := MatchResource(gvrs, schema.GroupVersionResource{Group: "batch", Version: "v1beta1", Resource: "cronjobs"})
+			Expect(ok).To(BeTrue())
+		})
+
+		It("does not match other resources", func() {
+			_, ok := MatchResource(gvrs, schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"})
+			Expect(ok).To(BeFalse())
+			_, ok = MatchResource(gvrs, schema.GroupVersionResource{Group: "batch", Version: "v2alpha1", Resource: "jobs"})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("ParseIgnoredResourcesConfigMap", func() {
+		AfterEach(func() {
+			IgnoredResourcesConfigMap = ""
+		})
+
+		It("returns nil when not set", func() {
+			name, err := ParseIgnoredResourcesConfigMap()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(BeNil())
+		})
+
+		It("parses the namespace and name", func() {
+			IgnoredResourcesConfigMap = "faros/ignored-resources"
+			name, err := ParseIgnoredResourcesConfigMap()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name.Namespace).To(Equal("faros"))
+			Expect(name.Name).To(Equal("ignored-resources"))
+		})
+
+		It("errors without a namespace", func() {
+			IgnoredResourcesConfigMap = "ignored-resources"
+			_, err := ParseIgnoredResourcesConfigMap()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ManagesNamespace", func() {
+		AfterEach(func() {
+			Namespaces = []string{}
+		})
+
+		It("manages all namespaces when none are given", func() {
+			Expect(ManagesNamespace("default")).To(BeTrue())
+		})
+
+		It("only manages the given namespaces", func() {
+			Expect(FlagSet.Parse([]string{"--namespace=default,team-a", "--namespace=team-b"})).To(Succeed())
+			Expect(Namespaces).To(Equal([]string{"default", "team-a", "team-b"}))
+			Expect(ManagesNamespace("team-b")).To(BeTrue())
+			Expect(ManagesNamespace("kube-system")).To(BeFalse())
+		})
+	})
+
+	Context("ParseGitTrackSelector", func() {
+		AfterEach(func() {
+			gitTrackSelector = ""
+		})
+
+		It("selects all GitTracks when empty", func() {
+			selector, err := ParseGitTrackSelector()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"shard": "b"})).To(BeTrue())
+		})
+
+		It("only selects GitTracks matching the selector", func() {
+			gitTrackSelector = "shard in (a,b),team!=platform"
+			selector, err := ParseGitTrackSelector()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(labels.Set{"shard": "a", "team": "apps"})).To(BeTrue())
+			Expect(selector.Matches(labels.Set{"shard": "a", "team": "platform"})).To(BeFalse())
+			Expect(selector.Matches(labels.Set{"shard": "c"})).To(BeFalse())
+		})
+
+		It("errors for an invalid selector", func() {
+			gitTrackSelector = "shard in (a"
+			_, err := ParseGitTrackSelector()
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,237 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitwrite
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	cryptossh "golang.org/x/crypto/ssh"
+	billy "gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	gitssh "gopkg.in/src-d/go-git.v4/plumbing/transport/ssh"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// Author is the author of the commits made by Faros
+type Author struct {
+	Name  string
+	Email string
+}
+
+// Options configure a commit made to a repository
+type Options struct {
+	// URL of the repository
+	URL string
+
+	// Branch the commit is made to
+	Branch string
+
+	// Credentials is the deploy key used to access the repository, if any
+	Credentials []byte
+
+	// CredentialType is the type of the deploy key. Defaults to SSH.
+	CredentialType farosv1alpha1.GitCredentialType
+
+	// Message is the commit message
+	Message string
+
+	// Author of the commit
+	Author Author
+}
+
+// EditFunc changes the files in the worktree, returning the paths of the
+// files it changed
+type EditFunc func(fs billy.Filesystem) ([]string, error)
+
+// Commit clones the branch of the repository, lets edit change its files and,
+// if any were changed, commits and pushes them. The branch is created from the
+// default branch if it does not exist. The hash of the commit is returned, or
+// an empty string if nothing was changed.
+func Commit(opts Options, edit EditFunc) (string, error) {
+	auth, err := authMethod(opts.Credentials, opts.CredentialType)
+	if err != nil {
+		return "", err
+	}
+
+	branch := plumbing.ReferenceName("refs/heads/" + opts.Branch)
+	exists, err := branchExists(opts.URL, branch, auth)
+	if err != nil {
+		return "", err
+	}
+
+	// Branches that do not exist yet are created from the default branch
+	cloneOpts := &git.CloneOptions{URL: opts.URL, Auth: auth, SingleBranch: true}
+	if exists {
+		cloneOpts.ReferenceName = branch
+	}
+	repo, err := git.Clone(memory.NewStorage(), memfs.New(), cloneOpts)
+	if err != nil {
+		return "", fmt.Errorf("failed to clone '%s' at '%s': %v", opts.URL, opts.Branch, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree: %v", err)
+	}
+	if !exists {
+		err = worktree.Checkout(&git.CheckoutOptions{Branch: branch, Create: true})
+		if err != nil {
+			return "", fmt.Errorf("failed to create branch '%s': %v", opts.Branch, err)
+		}
+	}
+
+	changed, err := edit(worktree.Filesystem)
+	if err != nil {
+		return "", err
+	}
+	if len(changed) == 0 {
+		return "", nil
+	}
+	for _, file := range changed {
+		if _, err = worktree.Add(file); err != nil {
+			return "", fmt.Errorf("failed to add '%s': %v", file, err)
+		}
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return "", fmt.Errorf("failed to get worktree status: %v", err)
+	}
+	if status.IsClean() {
+		return "", nil
+	}
+
+	hash, err := worktree.Commit(opts.Message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  opts.Author.Name,
+			Email: opts.Author.Email,
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit: %v", err)
+	}
+	err = repo.Push(&git.PushOptions{
+		Auth:     auth,
+		RefSpecs: []config.RefSpec{config.RefSpec(branch + ":" + branch)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to push to '%s': %v", opts.URL, err)
+	}
+	return hash.String(), nil
+}
+
+// branchExists returns whether the branch exists in the remote repository
+func branchExists(url string, branch plumbing.ReferenceName, auth transport.AuthMethod) (bool, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{url}})
+	refs, err := remote.List(&git.ListOptions{Auth: auth})
+	if err != nil {
+		return false, fmt.Errorf("failed to list references of '%s': %v", url, err)
+	}
+	for _, ref := range refs {
+		if ref.Name() == branch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// authMethod creates the auth method for the deploy key
+func authMethod(creds []byte, credentialType farosv1alpha1.GitCredentialType) (transport.AuthMethod, error) {
+	if len(creds) == 0 {
+		return nil, nil
+	}
+	switch credentialType {
+	// default to SSH
+	case "", farosv1alpha1.GitCredentialTypeSSH:
+		auth, err := gitssh.NewPublicKeys("git", creds, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SSH key: %v", err)
+		}
+		auth.HostKeyCallback = cryptossh.InsecureIgnoreHostKey()
+		return auth, nil
+	case farosv1alpha1.GitCredentialTypeHTTPBasicAuth:
+		split := strings.SplitN(string(creds), ":", 2)
+		if len(split) != 2 {
+			return nil, fmt.Errorf("You must specify the secret as <username>:<password> for credential type %s", credentialType)
+		}
+		return &githttp.BasicAuth{Username: split[0], Password: split[1]}, nil
+	default:
+		return nil, fmt.Errorf("invalid credential type \"%s\"", credentialType)
+	}
+}
+
+// ReadFile returns the contents of the file
+func ReadFile(fs billy.Filesystem, filename string) ([]byte, error) {
+	f, err := fs.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+// WriteFile replaces the contents of the file, creating it and its
+// directories if needed
+func WriteFile(fs billy.Filesystem, filename string, data []byte) error {
+	if dir := path.Dir(filename); dir != "." {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := fs.Create(filename)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	return err
+}
+
+// Walk calls fn with the path of every file beneath the directory
+func Walk(fs billy.Filesystem, dir string, fn func(filename string) error) error {
+	infos, err := fs.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		filename := path.Join(dir, info.Name())
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				continue
+			}
+			err = Walk(fs, filename, fn)
+		} else {
+			err = fn(filename)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// Check returns an error when the component it checks is unhealthy
+type Check func() error
+
+// checkTimeout is how long a check may take before it is reported as failed
+const checkTimeout = 5 * time.Second
+
+var (
+	mutex           sync.RWMutex
+	livenessChecks  = make(map[string]Check)
+	readinessChecks = make(map[string]Check)
+	panics          = make(map[string]string)
+
+	log = rlogr.Log.WithName("health")
+)
+
+// AddLivenessCheck registers a check that must pass for /healthz to report
+// healthy
+func AddLivenessCheck(name string, check Check) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	livenessChecks[name] = check
+}
+
+// AddReadinessCheck registers a check that must pass for /readyz to report
+// ready
+func AddReadinessCheck(name string, check Check) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	readinessChecks[name] = check
+}
+
+// RecoverPanic recovers a panic in the named component and records it so that
+// /healthz reports unhealthy until the process is restarted.
+// It must be called directly by defer.
+func RecoverPanic(component string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	err := fmt.Errorf("panic: %v", r)
+	log.Error(err, "recovered panic", "component", component)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	panics[component] = err.Error()
+}
+
+// Reset removes all registered checks and recorded panics
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	livenessChecks = make(map[string]Check)
+	readinessChecks = make(map[string]Check)
+	panics = make(map[string]string)
+}
+
+// panicked is a Check that fails if any component has panicked
+func panicked() error {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if len(panics) == 0 {
+		return nil
+	}
+	msgs := []string{}
+	for component, msg := range panics {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", component, msg))
+	}
+	sort.Strings(msgs)
+	return fmt.Errorf(strings.Join(msgs, ", "))
+}
+
+// InformersSynced returns a Check that fails until the informers for each of
+// the given objects have synced
+func InformersSynced(informers cache.Informers, objs ...runtime.Object) Check {
+	return func() error {
+		for _, obj := range objs {
+			informer, err := informers.GetInformer(obj)
+			if err != nil {
+				return fmt.Errorf("unable to get informer for %T: %v", obj, err)
+			}
+			if !informer.HasSynced() {
+				return fmt.Errorf("informer for %T has not synced", obj)
+			}
+		}
+		return nil
+	}
+}
+
+// Handler returns an http.Handler serving /healthz and /readyz.
+// /healthz fails if a component has panicked or a liveness check fails,
+// /readyz additionally fails if a readiness check fails.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, req *http.Request) {
+		serveChecks(w, livenessCheckSet())
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, req *http.Request) {
+		checks := livenessCheckSet()
+		mutex.RLock()
+		for name, check := range readinessChecks {
+			checks[name] = check
+		}
+		mutex.RUnlock()
+		serveChecks(w, checks)
+	})
+	return mux
+}
+
+// livenessCheckSet returns a copy of the liveness checks including the panic
+// check
+func livenessCheckSet() map[string]Check {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	checks := map[string]Check{"panics": panicked}
+	for name, check := range livenessChecks {
+		checks[name] = check
+	}
+	return checks
+}
+
+// serveChecks runs the checks and writes a line per check, responding with
+// a 500 if any check failed
+func serveChecks(w http.ResponseWriter, checks map[string]Check) {
+	names := []string{}
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failed := false
+	lines := []string{}
+	for _, name := range names {
+		if err := runCheck(checks[name]); err != nil {
+			failed = true
+			lines = append(lines, fmt.Sprintf("[-]%s failed: %v", name, err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("[+]%s ok", name))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if failed {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	fmt.Fprintln(w, strings.Join(lines, "\n"))
+}
+
+// runCheck runs the check, failing it if it does not return within the
+// checkTimeout
+func runCheck(check Check) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- check()
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(checkTimeout):
+		return fmt.Errorf("timed out after %s", checkTimeout)
+	}
+}
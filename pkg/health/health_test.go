@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestHealth(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Health Suite", reporters.Reporters())
+}
+
+var _ = Describe("Health Suite", func() {
+	var get = func(path string) (int, string) {
+		recorder := httptest.NewRecorder()
+		Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		body, err := ioutil.ReadAll(recorder.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return recorder.Code, string(body)
+	}
+
+	var panicIn = func(component string) {
+		defer RecoverPanic(component)
+		panic("test panic")
+	}
+
+	BeforeEach(func() {
+		Reset()
+	})
+
+	It("is healthy and ready when all checks pass", func() {
+		AddLivenessCheck("live", func() error { return nil })
+		AddReadinessCheck("ready", func() error { return nil })
+
+		code, body := get("/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring("[+]live ok"))
+
+		code, body = get("/readyz")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(body).To(ContainSubstring("[+]ready ok"))
+	})
+
+	It("is healthy but not ready when a readiness check fails", func() {
+		AddReadinessCheck("informers", func() error { return fmt.Errorf("not synced") })
+
+		code, _ := get("/healthz")
+		Expect(code).To(Equal(http.StatusOK))
+
+		code, body := get("/readyz")
+		Expect(code).To(Equal(http.StatusInternalServerError))
+		Expect(body).To(ContainSubstring("[-]informers failed: not synced"))
+	})
+
+	It("is neither healthy nor ready when a liveness check fails", func() {
+		AddLivenessCheck("live", func() error { return fmt.Errorf("broken") })
+
+		code, _ := get("/healthz")
+		Expect(code).To(Equal(http.StatusInternalServerError))
+		code, _ = get("/readyz")
+		Expect(code).To(Equal(http.StatusInternalServerError))
+	})
+
+	It("recovers panics and reports unhealthy", func() {
+		Expect(func() { panicIn("gittrack-controller") }).NotTo(Panic())
+
+		code, body := get("/healthz")
+		Expect(code).To(Equal(http.StatusInternalServerError))
+		Expect(body).To(ContainSubstring("gittrack-controller: panic: test panic"))
+	})
+})
@@ -0,0 +1,203 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Stage is the stage of a sync in which a GitTrack failed
+type Stage string
+
+const (
+	// FetchStage is the stage in which files are fetched from the repository
+	FetchStage Stage = "fetch"
+
+	// ParseStage is the stage in which files are parsed into objects
+	ParseStage Stage = "parse"
+
+	// ApplyStage is the stage in which children are created or updated
+	ApplyStage Stage = "apply"
+)
+
+// Severity is the severity of a notification
+type Severity string
+
+const (
+	// SeverityInfo is the severity of recovery notifications
+	SeverityInfo Severity = "Info"
+
+	// SeverityError is the severity of failure notifications
+	SeverityError Severity = "Error"
+)
+
+// defaultClient is used by Notifiers that are not given a client
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Notification describes a GitTrack entering or recovering from a failed
+// state
+type Notification struct {
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	Repository string `json:"repository"`
+	Reference  string `json:"reference"`
+
+	// Stage is the stage that failed, or that had failed before recovering
+	Stage Stage `json:"stage"`
+
+	// Recovered is true when the GitTrack synced successfully after failing
+	Recovered bool `json:"recovered"`
+
+	// Message is the error that caused the failure
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Severity returns the severity of the notification
+func (n Notification) Severity() Severity {
+	if n.Recovered {
+		return SeverityInfo
+	}
+	return SeverityError
+}
+
+// Summary returns a human readable description of the notification
+func (n Notification) Summary() string {
+	if n.Recovered {
+		return fmt.Sprintf("GitTrack %s/%s recovered from %s failure: synced '%s' at '%s'", n.Namespace, n.Name, n.Stage, n.Repository, n.Reference)
+	}
+	return fmt.Sprintf("GitTrack %s/%s failed to %s '%s' at '%s': %s", n.Namespace, n.Name, n.Stage, n.Repository, n.Reference, n.Message)
+}
+
+// Notifier sends notifications to an endpoint
+type Notifier interface {
+	Notify(context.Context, Notification) error
+}
+
+// Options configures the endpoints notifications are sent to
+type Options struct {
+	// SlackURLs are Slack incoming webhook URLs
+	SlackURLs []string
+
+	// WebhookURLs are generic webhook URLs, which receive the notification as
+	// JSON
+	WebhookURLs []string
+
+	// Timeout is the timeout for each request. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// New creates a Notifier sending to all endpoints configured in opts, or nil
+// if none are configured
+func New(opts Options) Notifier {
+	client := defaultClient
+	if opts.Timeout != 0 {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+
+	var notifiers multiNotifier
+	for _, url := range opts.SlackURLs {
+		notifiers = append(notifiers, &Slack{URL: url, Client: client})
+	}
+	for _, url := range opts.WebhookURLs {
+		notifiers = append(notifiers, &Webhook{URL: url, Client: client})
+	}
+	if len(notifiers) == 0 {
+		return nil
+	}
+	return notifiers
+}
+
+// Multi returns a Notifier sending notifications to each of notifiers
+func Multi(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+// multiNotifier sends notifications to each of its Notifiers
+type multiNotifier []Notifier
+
+// Notify sends the notification to every Notifier, even if some fail
+func (m multiNotifier) Notify(ctx context.Context, n Notification) error {
+	var errs []string
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to send notification: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Webhook posts notifications as JSON to a URL
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify posts the notification to the webhook
+func (w *Webhook) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, w.Client, w.URL, nil, n)
+}
+
+// Slack posts notifications to a Slack incoming webhook
+type Slack struct {
+	URL    string
+	Client *http.Client
+}
+
+// Notify posts the notification summary to Slack
+func (s *Slack) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, s.Client, s.URL, nil, map[string]string{"text": n.Summary()})
+}
+
+// postJSON posts body encoded as JSON to url with any extra headers given,
+// returning an error unless the response status is 2xx
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("unable to marshal notification: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("unable to create request: %v", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("unable to post to %s: %v", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response from %s: %s", req.URL.Host, resp.Status)
+	}
+	return nil
+}
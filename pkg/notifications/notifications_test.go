@@ -0,0 +1,221 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestNotifications(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Notifications Suite", reporters.Reporters())
+}
+
+var _ = Describe("Notifications Suite", func() {
+	var server *httptest.Server
+	var requests chan map[string]interface{}
+	var status int
+
+	var notification = Notification{
+		Namespace:  "default",
+		Name:       "example",
+		Repository: "https://github.com/pusher/faros",
+		Reference:  "master",
+		Stage:      FetchStage,
+		Message:    "unable to fetch",
+		Time:       time.Now(),
+	}
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		requests = make(chan map[string]interface{}, 10)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			defer GinkgoRecover()
+			Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+			body := map[string]interface{}{}
+			Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+			requests <- body
+			w.WriteHeader(status)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("New", func() {
+		It("returns nil when no endpoints are configured", func() {
+			Expect(New(Options{})).To(BeNil())
+		})
+
+		It("sends to every endpoint", func() {
+			notifier := New(Options{SlackURLs: []string{server.URL}, WebhookURLs: []string{server.URL}})
+			Expect(notifier.Notify(context.TODO(), notification)).To(Succeed())
+			Expect(requests).To(HaveLen(2))
+		})
+	})
+
+	Context("Webhook", func() {
+		It("posts the notification as JSON", func() {
+			webhook := &Webhook{URL: server.URL}
+			Expect(webhook.Notify(context.TODO(), notification)).To(Succeed())
+
+			var body map[string]interface{}
+			Eventually(requests).Should(Receive(&body))
+			Expect(body).To(HaveKeyWithValue("name", "example"))
+			Expect(body).To(HaveKeyWithValue("stage", "fetch"))
+			Expect(body).To(HaveKeyWithValue("recovered", false))
+		})
+
+		It("returns an error for a non 2xx response", func() {
+			status = http.StatusInternalServerError
+			webhook := &Webhook{URL: server.URL}
+			Expect(webhook.Notify(context.TODO(), notification)).To(MatchError(ContainSubstring("500")))
+		})
+	})
+
+	Context("Slack", func() {
+		It("posts the summary as text", func() {
+			slack := &Slack{URL: server.URL}
+			Expect(slack.Notify(context.TODO(), notification)).To(Succeed())
+
+			var body map[string]interface{}
+			Eventually(requests).Should(Receive(&body))
+			Expect(body).To(HaveKeyWithValue("text", notification.Summary()))
+		})
+	})
+
+	Context("Teams", func() {
+		It("posts a message card", func() {
+			teams := &Teams{URL: server.URL}
+			Expect(teams.Notify(context.TODO(), notification)).To(Succeed())
+
+			var body map[string]interface{}
+			Eventually(requests).Should(Receive(&body))
+			Expect(body).To(HaveKeyWithValue("@type", "MessageCard"))
+			Expect(body).To(HaveKeyWithValue("summary", notification.Summary()))
+			Expect(body).To(HaveKeyWithValue("title", "GitTrack default/example failed to fetch"))
+			Expect(body).To(HaveKeyWithValue("themeColor", teamsFailureColor))
+		})
+
+		It("colors recovery cards differently", func() {
+			recovered := notification
+			recovered.Recovered = true
+			card := newTeamsCard(recovered)
+			Expect(card.ThemeColor).To(Equal(teamsRecoveryColor))
+			Expect(card.Title).To(Equal("GitTrack default/example recovered"))
+		})
+	})
+
+	Context("Opsgenie", func() {
+		var opsgenie *Opsgenie
+		var paths chan string
+
+		BeforeEach(func() {
+			paths = make(chan string, 10)
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(req.Header.Get("Authorization")).To(Equal("GenieKey key"))
+				body := map[string]interface{}{}
+				Expect(json.NewDecoder(req.Body).Decode(&body)).To(Succeed())
+				paths <- req.URL.RequestURI()
+				requests <- body
+				w.WriteHeader(http.StatusAccepted)
+			})
+			opsgenie = &Opsgenie{URL: server.URL, APIKey: "key"}
+		})
+
+		It("creates an alert for a failure", func() {
+			Expect(opsgenie.Notify(context.TODO(), notification)).To(Succeed())
+			Eventually(paths).Should(Receive(Equal("/v2/alerts")))
+
+			var body map[string]interface{}
+			Eventually(requests).Should(Receive(&body))
+			Expect(body).To(HaveKeyWithValue("alias", "faros/default/example/fetch"))
+			Expect(body).To(HaveKeyWithValue("message", "GitTrack default/example failed to fetch"))
+			Expect(body).To(HaveKeyWithValue("description", notification.Summary()))
+		})
+
+		It("closes the alert on recovery", func() {
+			recovered := notification
+			recovered.Recovered = true
+			Expect(opsgenie.Notify(context.TODO(), recovered)).To(Succeed())
+			Eventually(paths).Should(Receive(Equal("/v2/alerts/faros%2Fdefault%2Fexample%2Ffetch/close?identifierType=alias")))
+		})
+	})
+
+	Context("PagerDuty", func() {
+		var pagerDuty *PagerDuty
+
+		BeforeEach(func() {
+			pagerDuty = &PagerDuty{URL: server.URL, RoutingKey: "key"}
+		})
+
+		It("triggers an incident for a failure", func() {
+			Expect(pagerDuty.Notify(context.TODO(), notification)).To(Succeed())
+
+			var body map[string]interface{}
+			Eventually(requests).Should(Receive(&body))
+			Expect(body).To(HaveKeyWithValue("routing_key", "key"))
+			Expect(body).To(HaveKeyWithValue("event_action", "trigger"))
+			Expect(body).To(HaveKeyWithValue("dedup_key", "faros/default/example/fetch"))
+			Expect(body).To(HaveKeyWithValue("payload", HaveKeyWithValue("summary", notification.Summary())))
+		})
+
+		It("resolves the incident on recovery", func() {
+			recovered := notification
+			recovered.Recovered = true
+			Expect(pagerDuty.Notify(context.TODO(), recovered)).To(Succeed())
+
+			var body map[string]interface{}
+			Eventually(requests).Should(Receive(&body))
+			Expect(body).To(HaveKeyWithValue("event_action", "resolve"))
+			Expect(body).To(HaveKeyWithValue("dedup_key", "faros/default/example/fetch"))
+			Expect(body).NotTo(HaveKey("payload"))
+		})
+	})
+
+	Context("Severity", func() {
+		It("is Error for failures and Info for recoveries", func() {
+			Expect(notification.Severity()).To(Equal(SeverityError))
+			recovered := notification
+			recovered.Recovered = true
+			Expect(recovered.Severity()).To(Equal(SeverityInfo))
+		})
+	})
+
+	Context("Summary", func() {
+		It("describes a failure", func() {
+			Expect(notification.Summary()).To(Equal("GitTrack default/example failed to fetch 'https://github.com/pusher/faros' at 'master': unable to fetch"))
+		})
+
+		It("describes a recovery", func() {
+			recovered := notification
+			recovered.Recovered = true
+			Expect(recovered.Summary()).To(Equal("GitTrack default/example recovered from fetch failure: synced 'https://github.com/pusher/faros' at 'master'"))
+		})
+	})
+})
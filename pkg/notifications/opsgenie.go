@@ -0,0 +1,102 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// OpsgenieAPIURL is the URL of the Opsgenie API
+	OpsgenieAPIURL = "https://api.opsgenie.com"
+
+	// maxOpsgenieMessage is the maximum length of an Opsgenie alert message
+	maxOpsgenieMessage = 130
+)
+
+// Opsgenie creates an Opsgenie alert when a GitTrack fails and closes it when
+// the GitTrack recovers
+type Opsgenie struct {
+	// URL is the API URL. Defaults to OpsgenieAPIURL.
+	URL string
+
+	// APIKey is the key of an Opsgenie API integration
+	APIKey string
+
+	Client *http.Client
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+type opsgenieClose struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Notify creates an alert for a failure, or closes the alert created for the
+// stage that recovered
+func (o *Opsgenie) Notify(ctx context.Context, n Notification) error {
+	apiURL := strings.TrimSuffix(o.URL, "/")
+	if apiURL == "" {
+		apiURL = OpsgenieAPIURL
+	}
+	header := http.Header{}
+	header.Set("Authorization", "GenieKey "+o.APIKey)
+	alias := fmt.Sprintf("faros/%s/%s/%s", n.Namespace, n.Name, n.Stage)
+
+	if n.Recovered {
+		closeURL := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", apiURL, url.PathEscape(alias))
+		return postJSON(ctx, o.Client, closeURL, header, opsgenieClose{
+			Source: "faros",
+			Note:   n.Summary(),
+		})
+	}
+
+	message := fmt.Sprintf("GitTrack %s/%s failed to %s", n.Namespace, n.Name, n.Stage)
+	return postJSON(ctx, o.Client, apiURL+"/v2/alerts", header, opsgenieAlert{
+		Message:     truncate(message, maxOpsgenieMessage),
+		Alias:       alias,
+		Description: n.Summary(),
+		Source:      "faros",
+		Tags:        []string{"faros", string(n.Stage)},
+		Details: map[string]string{
+			"namespace":  n.Namespace,
+			"name":       n.Name,
+			"repository": n.Repository,
+			"reference":  n.Reference,
+		},
+	})
+}
+
+// truncate shortens s to at most max characters
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
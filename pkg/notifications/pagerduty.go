@@ -0,0 +1,77 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// PagerDutyEventsURL is the URL of the PagerDuty Events API v2
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty triggers a PagerDuty incident when a GitTrack fails and resolves
+// it when the GitTrack recovers
+type PagerDuty struct {
+	// URL is the Events API URL. Defaults to PagerDutyEventsURL.
+	URL string
+
+	// RoutingKey is the integration key of the PagerDuty service
+	RoutingKey string
+
+	Client *http.Client
+}
+
+// pagerDutyEvent is an event of the PagerDuty Events API v2
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary  string `json:"summary"`
+	Source   string `json:"source"`
+	Severity string `json:"severity"`
+}
+
+// Notify triggers an incident for a failure, or resolves the incident
+// triggered for the stage that recovered
+func (p *PagerDuty) Notify(ctx context.Context, n Notification) error {
+	url := p.URL
+	if url == "" {
+		url = PagerDutyEventsURL
+	}
+
+	event := pagerDutyEvent{
+		RoutingKey: p.RoutingKey,
+		DedupKey:   fmt.Sprintf("faros/%s/%s/%s", n.Namespace, n.Name, n.Stage),
+	}
+	if n.Recovered {
+		event.EventAction = "resolve"
+	} else {
+		event.EventAction = "trigger"
+		event.Payload = &pagerDutyPayload{
+			Summary:  n.Summary(),
+			Source:   n.Repository,
+			Severity: "error",
+		}
+	}
+	return postJSON(ctx, p.Client, url, nil, event)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// teamsFailureColor is the theme color of failure cards
+	teamsFailureColor = "D70000"
+
+	// teamsRecoveryColor is the theme color of recovery cards
+	teamsRecoveryColor = "2EB886"
+)
+
+// Teams posts notifications as message cards to a Microsoft Teams incoming
+// webhook
+type Teams struct {
+	URL    string
+	Client *http.Client
+}
+
+type teamsCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Title      string         `json:"title"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+type teamsSection struct {
+	Facts []teamsFact `json:"facts"`
+	Text  string      `json:"text,omitempty"`
+}
+
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// newTeamsCard formats the notification as a message card, colored by
+// whether the GitTrack failed or recovered
+func newTeamsCard(n Notification) teamsCard {
+	card := teamsCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: teamsFailureColor,
+		Summary:    n.Summary(),
+		Title:      fmt.Sprintf("GitTrack %s/%s failed to %s", n.Namespace, n.Name, n.Stage),
+	}
+	if n.Recovered {
+		card.ThemeColor = teamsRecoveryColor
+		card.Title = fmt.Sprintf("GitTrack %s/%s recovered", n.Namespace, n.Name)
+	}
+	card.Sections = []teamsSection{{
+		Facts: []teamsFact{
+			{Name: "Repository", Value: n.Repository},
+			{Name: "Reference", Value: n.Reference},
+			{Name: "Stage", Value: string(n.Stage)},
+		},
+		Text: n.Message,
+	}}
+	return card
+}
+
+// Notify posts the notification to Teams as a message card
+func (t *Teams) Notify(ctx context.Context, n Notification) error {
+	return postJSON(ctx, t.Client, t.URL, nil, newTeamsCard(n))
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DockerHub is the host images without a registry are pulled from
+	DockerHub = "docker.io"
+
+	// dockerHubAPI is the host of the Docker Hub registry API
+	dockerHubAPI = "registry-1.docker.io"
+)
+
+// Credentials authenticate requests to a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Image is a repository of images within a registry
+type Image struct {
+	// Host of the registry
+	Host string
+
+	// Repository is the path of the repository within the registry
+	Repository string
+}
+
+// ParseImage splits an image name without a tag into its registry and
+// repository, defaulting to the Docker Hub
+func ParseImage(image string) (Image, error) {
+	if image == "" || strings.ContainsAny(image, "@ ") {
+		return Image{}, fmt.Errorf("invalid image %q", image)
+	}
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return Image{Host: parts[0], Repository: parts[1]}, nil
+	}
+	if !strings.Contains(image, "/") {
+		image = "library/" + image
+	}
+	return Image{Host: DockerHub, Repository: image}, nil
+}
+
+// baseURL returns the URL of the registry API for the image
+func (i Image) baseURL() string {
+	host := i.Host
+	if host == DockerHub {
+		host = dockerHubAPI
+	}
+	// Registries on the local machine are usually served without TLS
+	if strings.HasPrefix(host, "localhost") || strings.HasPrefix(host, "127.0.0.1") {
+		return "http://" + host
+	}
+	return "https://" + host
+}
+
+// Client lists the tags of images using the Docker Registry HTTP API V2
+type Client struct {
+	client *http.Client
+}
+
+// New creates a Client
+func New() *Client {
+	return &Client{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// ListTags returns every tag of the image, authenticating with creds if
+// given
+func (c *Client) ListTags(ctx context.Context, image Image, creds *Credentials) ([]string, error) {
+	tags := []string{}
+	next := fmt.Sprintf("%s/v2/%s/tags/list", image.baseURL(), image.Repository)
+	authorization := ""
+	for next != "" {
+		resp, err := c.get(ctx, next, authorization)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && authorization == "" {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			authorization, err = c.authorize(ctx, challenge, creds)
+			if err != nil {
+				return nil, fmt.Errorf("unable to authenticate with %s: %v", image.Host, err)
+			}
+			continue
+		}
+
+		var list struct {
+			Tags []string `json:"tags"`
+		}
+		err = decode(resp, &list)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list tags of %s/%s: %v", image.Host, image.Repository, err)
+		}
+		tags = append(tags, list.Tags...)
+
+		next, err = nextPage(next, resp.Header.Get("Link"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// get performs a GET request with the Authorization header, if given
+func (c *Client) get(ctx context.Context, u, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return c.client.Do(req.WithContext(ctx))
+}
+
+// decode reads the JSON body of a successful response
+func decode(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// challengeParam matches the parameters of a WWW-Authenticate challenge
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authorize answers the WWW-Authenticate challenge of the registry, returning
+// the value of the Authorization header to retry with
+func (c *Client) authorize(ctx context.Context, challenge string, creds *Credentials) (string, error) {
+	basic := ""
+	if creds != nil {
+		basic = "Basic " + base64.StdEncoding.EncodeToString([]byte(creds.Username+":"+creds.Password))
+	}
+
+	scheme := strings.ToLower(strings.SplitN(challenge, " ", 2)[0])
+	switch scheme {
+	case "basic":
+		if basic == "" {
+			return "", fmt.Errorf("registry requires credentials")
+		}
+		return basic, nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	params := make(map[string]string)
+	for _, match := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[match[1]] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("no realm in authentication challenge %q", challenge)
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid realm: %v", err)
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	resp, err := c.get(ctx, realm.String(), basic)
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = decode(resp, &token)
+	if err != nil {
+		return "", fmt.Errorf("unable to get token: %v", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// nextPage returns the URL of the next page of tags given in the Link
+// header, or an empty string if there are no more pages
+func nextPage(current, link string) (string, error) {
+	if link == "" || !strings.Contains(link, `rel="next"`) {
+		return "", nil
+	}
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("invalid Link header %q", link)
+	}
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return "", fmt.Errorf("invalid Link header %q: %v", link, err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// CredentialsFromDockerConfig returns the credentials for the registry host
+// from the contents of a `.dockerconfigjson` Secret key, or nil if there are
+// none
+func CredentialsFromDockerConfig(data []byte, host string) (*Credentials, error) {
+	var config struct {
+		Auths map[string]struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			Auth     string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("unable to parse docker config: %v", err)
+	}
+
+	for key, auth := range config.Auths {
+		if registryHost(key) != host && !(host == DockerHub && registryHost(key) == "index.docker.io") {
+			continue
+		}
+		if auth.Username != "" {
+			return &Credentials{Username: auth.Username, Password: auth.Password}, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode auth for %s: %v", key, err)
+		}
+		split := strings.SplitN(string(decoded), ":", 2)
+		if len(split) != 2 {
+			return nil, fmt.Errorf("invalid auth for %s", key)
+		}
+		return &Credentials{Username: split[0], Password: split[1]}, nil
+	}
+	return nil, nil
+}
+
+// registryHost returns the host of a docker config auths key, which may be a
+// URL
+func registryHost(key string) string {
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+	return strings.SplitN(key, "/", 2)[0]
+}
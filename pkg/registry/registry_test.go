@@ -0,0 +1,124 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestRegistry(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Registry Suite", reporters.Reporters())
+}
+
+var _ = Describe("Registry Suite", func() {
+	Context("ParseImage", func() {
+		for image, expected := range map[string]Image{
+			"nginx":                          {Host: "docker.io", Repository: "library/nginx"},
+			"pusher/faros":                   {Host: "docker.io", Repository: "pusher/faros"},
+			"quay.io/pusher/faros":           {Host: "quay.io", Repository: "pusher/faros"},
+			"localhost:5000/faros":           {Host: "localhost:5000", Repository: "faros"},
+			"gcr.io/project/team/faros-test": {Host: "gcr.io", Repository: "project/team/faros-test"},
+		} {
+			image, expected := image, expected
+			It("parses "+image, func() {
+				Expect(ParseImage(image)).To(Equal(expected))
+			})
+		}
+
+		It("rejects images with a digest", func() {
+			_, err := ParseImage("nginx@sha256:abc")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ListTags", func() {
+		var server *httptest.Server
+		var image Image
+
+		BeforeEach(func() {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/token", func(w http.ResponseWriter, req *http.Request) {
+				defer GinkgoRecover()
+				Expect(req.URL.Query().Get("scope")).To(Equal("repository:pusher/faros:pull"))
+				if req.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("user:pass")) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				fmt.Fprint(w, `{"token": "secret"}`)
+			})
+			mux.HandleFunc("/v2/pusher/faros/tags/list", func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Authorization") != "Bearer secret" {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="registry",scope="repository:pusher/faros:pull"`, req.Host))
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if req.URL.Query().Get("last") == "" {
+					w.Header().Set("Link", `</v2/pusher/faros/tags/list?last=v1.1.0&n=2>; rel="next"`)
+					fmt.Fprint(w, `{"name": "pusher/faros", "tags": ["v1.0.0", "v1.1.0"]}`)
+					return
+				}
+				fmt.Fprint(w, `{"name": "pusher/faros", "tags": ["v2.0.0"]}`)
+			})
+			server = httptest.NewServer(mux)
+			image = Image{Host: strings.TrimPrefix(server.URL, "http://"), Repository: "pusher/faros"}
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("lists every page of tags", func() {
+			tags, err := New().ListTags(context.TODO(), image, &Credentials{Username: "user", Password: "pass"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags).To(Equal([]string{"v1.0.0", "v1.1.0", "v2.0.0"}))
+		})
+
+		It("fails without valid credentials", func() {
+			_, err := New().ListTags(context.TODO(), image, &Credentials{Username: "user", Password: "wrong"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("CredentialsFromDockerConfig", func() {
+		It("reads the username and password for the host", func() {
+			config := `{"auths": {"quay.io": {"username": "user", "password": "pass"}}}`
+			Expect(CredentialsFromDockerConfig([]byte(config), "quay.io")).To(Equal(&Credentials{Username: "user", Password: "pass"}))
+		})
+
+		It("decodes the auth for the host", func() {
+			auth := base64.StdEncoding.EncodeToString([]byte("user:pass"))
+			config := fmt.Sprintf(`{"auths": {"https://index.docker.io/v1/": {"auth": "%s"}}}`, auth)
+			Expect(CredentialsFromDockerConfig([]byte(config), DockerHub)).To(Equal(&Credentials{Username: "user", Password: "pass"}))
+		})
+
+		It("returns nil for other hosts", func() {
+			config := `{"auths": {"quay.io": {"username": "user", "password": "pass"}}}`
+			Expect(CredentialsFromDockerConfig([]byte(config), "gcr.io")).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,66 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// LayoutNamespace returns the namespace the file maps to in the Namespaced
+// layout, the first directory of its path beneath the subPath, or an empty
+// string if the file is directly beneath the subPath
+func LayoutNamespace(subPath, file string) string {
+	rel := strings.TrimPrefix(file, "/")
+	if dir := strings.Trim(subPath, "/"); dir != "" {
+		rel = strings.TrimPrefix(rel, dir+"/")
+	}
+	parts := strings.SplitN(rel, "/", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0]
+}
+
+// NamespacedLayout sets the namespace of each object to the directory beneath
+// the subPath its file was read from, overriding any namespace set in the
+// manifest. Objects for which namespaced returns false are left alone, as are
+// objects in files directly beneath the subPath. Objects in directories that
+// are not valid namespace names are dropped and their files returned with the
+// reason.
+func NamespacedLayout(objects []*unstructured.Unstructured, objectFiles map[*unstructured.Unstructured]string, subPath string, namespaced func(*unstructured.Unstructured) bool) ([]*unstructured.Unstructured, map[string]string) {
+	laidOut := []*unstructured.Unstructured{}
+	fileErrors := make(map[string]string)
+	for _, u := range objects {
+		file := objectFiles[u]
+		namespace := LayoutNamespace(subPath, file)
+		if namespace == "" || !namespaced(u) {
+			laidOut = append(laidOut, u)
+			continue
+		}
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			fileErrors[file] = fmt.Sprintf("directory '%s' is not a valid namespace: %s", namespace, strings.Join(errs, ", "))
+			continue
+		}
+		u.SetNamespace(namespace)
+		laidOut = append(laidOut, u)
+	}
+	return laidOut, fileErrors
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var _ = Describe("Layout Suite", func() {
+	Context("LayoutNamespace", func() {
+		It("returns the first directory beneath the subPath", func() {
+			Expect(LayoutNamespace("deploy", "deploy/team-a/deployment.yaml")).To(Equal("team-a"))
+			Expect(LayoutNamespace("/deploy/", "deploy/team-a/app/deployment.yaml")).To(Equal("team-a"))
+		})
+
+		It("returns the first directory without a subPath", func() {
+			Expect(LayoutNamespace("", "team-a/deployment.yaml")).To(Equal("team-a"))
+		})
+
+		It("returns nothing for files directly beneath the subPath", func() {
+			Expect(LayoutNamespace("deploy", "deploy/namespace.yaml")).To(BeEmpty())
+			Expect(LayoutNamespace("", "namespace.yaml")).To(BeEmpty())
+		})
+	})
+
+	Context("NamespacedLayout", func() {
+		var newObject = func(kind, namespace string) *unstructured.Unstructured {
+			u := &unstructured.Unstructured{}
+			u.SetAPIVersion("v1")
+			u.SetKind(kind)
+			u.SetName("example")
+			u.SetNamespace(namespace)
+			return u
+		}
+		var namespaced = func(u *unstructured.Unstructured) bool {
+			return u.GetKind() != "Namespace"
+		}
+
+		It("sets the namespace of namespaced objects from their directory", func() {
+			defaulted := newObject("ConfigMap", "")
+			overridden := newObject("ConfigMap", "other")
+			clusterScoped := newObject("Namespace", "")
+			topLevel := newObject("ConfigMap", "default")
+			objectFiles := map[*unstructured.Unstructured]string{
+				defaulted:     "deploy/team-a/configmap.yaml",
+				overridden:    "deploy/team-b/configmap.yaml",
+				clusterScoped: "deploy/team-a/namespace.yaml",
+				topLevel:      "deploy/configmap.yaml",
+			}
+
+			objects, fileErrors := NamespacedLayout([]*unstructured.Unstructured{defaulted, overridden, clusterScoped, topLevel}, objectFiles, "deploy", namespaced)
+			Expect(fileErrors).To(BeEmpty())
+			Expect(objects).To(HaveLen(4))
+			Expect(defaulted.GetNamespace()).To(Equal("team-a"))
+			Expect(overridden.GetNamespace()).To(Equal("team-b"))
+			Expect(clusterScoped.GetNamespace()).To(BeEmpty())
+			Expect(topLevel.GetNamespace()).To(Equal("default"))
+		})
+
+		It("drops objects in directories that are not valid namespaces", func() {
+			valid := newObject("ConfigMap", "")
+			invalid := newObject("ConfigMap", "")
+			objectFiles := map[*unstructured.Unstructured]string{
+				valid:   "team-a/configmap.yaml",
+				invalid: "Team_B/configmap.yaml",
+			}
+
+			objects, fileErrors := NamespacedLayout([]*unstructured.Unstructured{valid, invalid}, objectFiles, "", namespaced)
+			Expect(objects).To(ConsistOf(valid))
+			Expect(fileErrors).To(HaveKey("Team_B/configmap.yaml"))
+		})
+	})
+})
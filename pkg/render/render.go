@@ -0,0 +1,160 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"github.com/pusher/faros/pkg/utils"
+	gitstore "github.com/pusher/git-store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ClusterAnnotation is set on copies of an object made for each cluster a
+// GitTrack applies its children to and holds the name of the FarosCluster
+const ClusterAnnotation = "faros.pusher.com/cluster"
+
+// NamespaceSelectorAnnotation is set on namespaced manifests to apply a copy
+// of the object to every namespace matching the label selector it holds,
+// instead of the namespace of the manifest
+const NamespaceSelectorAnnotation = "faros.pusher.com/namespace-selector"
+
+// ManifestGlob returns the glob matching every manifest within the subPath
+// of a repository
+func ManifestGlob(subPath string) string {
+	if !strings.HasSuffix(subPath, "/") {
+		subPath += "/"
+	}
+	return strings.TrimPrefix(subPath, "/") + "{**/*,*}.{yaml,yml,json}"
+}
+
+// Objects iterates through all the files given and attempts to create
+// Unstructured objects, recording the path of the file each object was read
+// from and the reason each invalid file could not be parsed
+func Objects(files map[string]*gitstore.File) ([]*unstructured.Unstructured, map[*unstructured.Unstructured]string, map[string]string) {
+	objects := []*unstructured.Unstructured{}
+	objectFiles := make(map[*unstructured.Unstructured]string)
+	fileErrors := make(map[string]string)
+	for path, file := range files {
+		// TODO (@JoelSpeed): What happens if there are multiple resources in one file,
+		// but one of them is invalid? Can we still get the rest?
+		us, err := utils.YAMLToUnstructuredSlice([]byte(file.Contents()))
+		if err != nil {
+			fileErrors[path] = fmt.Sprintf("unable to parse '%s': %v\n", path, err)
+			continue
+		}
+		for _, u := range us {
+			objectFiles[u] = path
+		}
+		objects = append(objects, us...)
+	}
+	return objects, objectFiles, fileErrors
+}
+
+// ObjectName constructs the name of the (Cluster)GitTrackObject for an
+// Unstructured object, suffixed with the cluster it is applied to if any
+func ObjectName(u *unstructured.Unstructured) string {
+	name := strings.ToLower(fmt.Sprintf("%s-%s", u.GetKind(), strings.Replace(u.GetName(), ":", "-", -1)))
+	if cluster := u.GetAnnotations()[ClusterAnnotation]; cluster != "" {
+		name = fmt.Sprintf("%s.%s", name, cluster)
+	}
+	return name
+}
+
+// ForCluster returns a copy of the Unstructured object to be applied to the
+// named cluster
+func ForCluster(u *unstructured.Unstructured, cluster string) *unstructured.Unstructured {
+	c := u.DeepCopy()
+	annotations := c.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[ClusterAnnotation] = cluster
+	c.SetAnnotations(annotations)
+	return c
+}
+
+// HasNamespaceSelector returns whether the object is applied to the
+// namespaces matching the label selector in its namespace selector annotation
+func HasNamespaceSelector(u *unstructured.Unstructured) bool {
+	_, ok := u.GetAnnotations()[NamespaceSelectorAnnotation]
+	return ok
+}
+
+// GitTrackObject builds the GitTrackObject, or ClusterGitTrackObject when the
+// object is not namespaced or is applied to the namespaces matching its
+// namespace selector, that tracks the Unstructured object
+func GitTrackObject(u *unstructured.Unstructured, namespaced bool) (farosv1alpha1.GitTrackObjectInterface, error) {
+	var selector *metav1.LabelSelector
+	if namespaced && HasNamespaceSelector(u) {
+		var err error
+		selector, err = metav1.ParseToLabelSelector(u.GetAnnotations()[NamespaceSelectorAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %v", NamespaceSelectorAnnotation, err)
+		}
+		u = u.DeepCopy()
+		u.SetNamespace("")
+		namespaced = false
+	}
+
+	var instance farosv1alpha1.GitTrackObjectInterface
+	if namespaced {
+		instance = &farosv1alpha1.GitTrackObject{
+			TypeMeta: farosv1alpha1.GitTrackObjectTypeMeta,
+		}
+	} else {
+		instance = &farosv1alpha1.ClusterGitTrackObject{
+			TypeMeta: farosv1alpha1.ClusterGitTrackObjectTypeMeta,
+		}
+	}
+	instance.SetName(ObjectName(u))
+	instance.SetNamespace(u.GetNamespace())
+
+	// Make the default update strategy explicit in the stored child
+	child := u.DeepCopy()
+	removeAnnotation(child, ClusterAnnotation)
+	removeAnnotation(child, NamespaceSelectorAnnotation)
+	gittrackobjectutils.SetDefaultUpdateStrategy(child)
+	data, err := child.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON: %v", err)
+	}
+
+	instance.SetSpec(farosv1alpha1.GitTrackObjectSpec{
+		Name: u.GetName(),
+		Kind: u.GetKind(),
+		Data: data,
+
+		NamespaceSelector: selector,
+	})
+	return instance, nil
+}
+
+// removeAnnotation removes an annotation used by Faros to render the object,
+// such as the one added by ForCluster, so that the child matches its manifest
+func removeAnnotation(u *unstructured.Unstructured, annotation string) {
+	annotations := u.GetAnnotations()
+	if _, ok := annotations[annotation]; !ok {
+		return
+	}
+	delete(annotations, annotation)
+	u.SetAnnotations(annotations)
+}
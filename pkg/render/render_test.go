@@ -0,0 +1,149 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"github.com/pusher/faros/test/reporters"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRender(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Render Suite", reporters.Reporters())
+}
+
+var _ = Describe("Render Suite", func() {
+	var u *unstructured.Unstructured
+
+	BeforeEach(func() {
+		u = &unstructured.Unstructured{}
+		u.SetAPIVersion("apps/v1")
+		u.SetKind("Deployment")
+		u.SetName("nginx")
+		u.SetNamespace("default")
+	})
+
+	Context("ManifestGlob", func() {
+		It("matches every manifest in the repository without a subPath", func() {
+			Expect(ManifestGlob("")).To(Equal("{**/*,*}.{yaml,yml,json}"))
+		})
+
+		It("matches every manifest within the subPath", func() {
+			Expect(ManifestGlob("/foo")).To(Equal("foo/{**/*,*}.{yaml,yml,json}"))
+			Expect(ManifestGlob("foo/")).To(Equal("foo/{**/*,*}.{yaml,yml,json}"))
+		})
+	})
+
+	Context("ObjectName", func() {
+		It("combines the kind and name of the object", func() {
+			Expect(ObjectName(u)).To(Equal("deployment-nginx"))
+		})
+
+		It("replaces colons in the name", func() {
+			u.SetName("system:nginx")
+			Expect(ObjectName(u)).To(Equal("deployment-system-nginx"))
+		})
+
+		It("suffixes the name with the cluster of the object", func() {
+			Expect(ObjectName(ForCluster(u, "staging"))).To(Equal("deployment-nginx.staging"))
+			Expect(ObjectName(u)).To(Equal("deployment-nginx"))
+		})
+	})
+
+	Context("GitTrackObject", func() {
+		It("builds a GitTrackObject for a namespaced object", func() {
+			gto, err := GitTrackObject(u, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gto).To(BeAssignableToTypeOf(&farosv1alpha1.GitTrackObject{}))
+			Expect(gto.GetNamespacedName()).To(Equal("default/deployment-nginx"))
+			Expect(gto.GetSpec().Name).To(Equal("nginx"))
+			Expect(gto.GetSpec().Kind).To(Equal("Deployment"))
+		})
+
+		It("builds a ClusterGitTrackObject for a cluster scoped object", func() {
+			u.SetKind("ClusterRole")
+			u.SetNamespace("")
+			gto, err := GitTrackObject(u, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gto).To(BeAssignableToTypeOf(&farosv1alpha1.ClusterGitTrackObject{}))
+			Expect(gto.GetName()).To(Equal("clusterrole-nginx"))
+		})
+
+		It("sets the default update strategy on the child", func() {
+			gto, err := GitTrackObject(u, true)
+			Expect(err).NotTo(HaveOccurred())
+			child := &unstructured.Unstructured{}
+			Expect(child.UnmarshalJSON(gto.GetSpec().Data)).To(Succeed())
+			Expect(gittrackobjectutils.GetUpdateStrategy(child)).To(Equal(gittrackobjectutils.DefaultUpdateStrategy))
+			Expect(child.GetAnnotations()).To(HaveKey("faros.pusher.com/update-strategy"))
+			Expect(u.GetAnnotations()).To(BeEmpty())
+		})
+
+		It("leaves the cluster annotation off the child", func() {
+			gto, err := GitTrackObject(ForCluster(u, "staging"), true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gto.GetName()).To(Equal("deployment-nginx.staging"))
+			child := &unstructured.Unstructured{}
+			Expect(child.UnmarshalJSON(gto.GetSpec().Data)).To(Succeed())
+			Expect(child.GetAnnotations()).NotTo(HaveKey(ClusterAnnotation))
+		})
+
+		Context("with a namespace selector", func() {
+			BeforeEach(func() {
+				u.SetKind("NetworkPolicy")
+				u.SetAnnotations(map[string]string{NamespaceSelectorAnnotation: "team=platform"})
+			})
+
+			It("builds a ClusterGitTrackObject selecting the namespaces", func() {
+				gto, err := GitTrackObject(u, true)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gto).To(BeAssignableToTypeOf(&farosv1alpha1.ClusterGitTrackObject{}))
+				Expect(gto.GetNamespacedName()).To(Equal("networkpolicy-nginx"))
+				Expect(gto.GetSpec().NamespaceSelector.MatchLabels).To(Equal(map[string]string{"team": "platform"}))
+			})
+
+			It("leaves the namespace and annotation off the child", func() {
+				gto, err := GitTrackObject(u, true)
+				Expect(err).NotTo(HaveOccurred())
+				child := &unstructured.Unstructured{}
+				Expect(child.UnmarshalJSON(gto.GetSpec().Data)).To(Succeed())
+				Expect(child.GetNamespace()).To(BeEmpty())
+				Expect(child.GetAnnotations()).NotTo(HaveKey(NamespaceSelectorAnnotation))
+				Expect(u.GetNamespace()).To(Equal("default"))
+			})
+
+			It("returns an error for an invalid selector", func() {
+				u.SetAnnotations(map[string]string{NamespaceSelectorAnnotation: "team in (platform"})
+				_, err := GitTrackObject(u, true)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("ignores the selector on cluster scoped objects", func() {
+				u.SetKind("ClusterRole")
+				gto, err := GitTrackObject(u, false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gto.GetSpec().NamespaceSelector).To(BeNil())
+			})
+		})
+	})
+})
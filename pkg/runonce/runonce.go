@@ -0,0 +1,239 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runonce
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// State describes how far a GitTrack has got with syncing
+type State string
+
+const (
+	// StatePending means the GitTrack has not finished syncing its current
+	// reference yet
+	StatePending State = "Pending"
+
+	// StateSynced means the GitTrack and all of its children are in sync
+	StateSynced State = "Synced"
+
+	// StateFailed means the GitTrack or one of its children failed to sync
+	StateFailed State = "Failed"
+
+	// StateSuspended means syncing of the GitTrack is suspended by spec.suspend
+	StateSuspended State = "Suspended"
+)
+
+// Result is the sync state of a single GitTrack
+type Result struct {
+	// Name is the namespaced name of the GitTrack
+	Name string
+
+	// State of the GitTrack
+	State State
+
+	// Message explains why the GitTrack is pending or failed
+	Message string
+}
+
+// Done returns whether every GitTrack has finished syncing, successfully or
+// not
+func Done(results []Result) bool {
+	for _, res := range results {
+		if res.State == StatePending {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the results of the GitTracks that are not in sync
+func Failed(results []Result) []Result {
+	failed := []Result{}
+	for _, res := range results {
+		if res.State == StateFailed || res.State == StatePending {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Wait checks the GitTracks in the given namespaces every interval until all
+// of them have finished syncing. It gives up once the timeout expires or the
+// stop channel is closed, returning the latest results with an error.
+func Wait(c client.Reader, namespaces []string, interval, timeout time.Duration, stop <-chan struct{}) ([]Result, error) {
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		results, err := Check(c, namespaces)
+		if err != nil {
+			return nil, err
+		}
+		if Done(results) {
+			return results, nil
+		}
+
+		select {
+		case <-stop:
+			return results, fmt.Errorf("stopped before all GitTracks were synced")
+		case <-deadline:
+			return results, fmt.Errorf("timed out after %s waiting for GitTracks to sync", timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check returns the sync state of every GitTrack in the given namespaces, an
+// empty list of namespaces checks all namespaces
+func Check(c client.Reader, namespaces []string) ([]Result, error) {
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	results := []Result{}
+	for _, namespace := range namespaces {
+		gts := &farosv1alpha1.GitTrackList{}
+		err := c.List(context.TODO(), gts, client.InNamespace(namespace))
+		if err != nil {
+			return nil, fmt.Errorf("unable to list GitTracks: %v", err)
+		}
+
+		for i := range gts.Items {
+			gt := &gts.Items[i]
+			children, err := listChildren(c, namespaces, gt)
+			if err != nil {
+				return nil, err
+			}
+			res := checkGitTrack(gt, children)
+			res.Name = fmt.Sprintf("%s/%s", gt.Namespace, gt.Name)
+			results = append(results, res)
+		}
+	}
+	return results, nil
+}
+
+// listChildren lists the (Cluster)GitTrackObjects controlled by the GitTrack
+func listChildren(c client.Reader, namespaces []string, gt *farosv1alpha1.GitTrack) ([]farosv1alpha1.GitTrackObjectInterface, error) {
+	selector := client.MatchingLabels(map[string]string{gittrackutils.GitTrackLabel: gt.Name})
+	children := []farosv1alpha1.GitTrackObjectInterface{}
+
+	for _, namespace := range namespaces {
+		gtos := &farosv1alpha1.GitTrackObjectList{}
+		err := c.List(context.TODO(), gtos, selector, client.InNamespace(namespace))
+		if err != nil {
+			return nil, fmt.Errorf("unable to list GitTrackObjects: %v", err)
+		}
+		for i := range gtos.Items {
+			if metav1.IsControlledBy(&gtos.Items[i], gt) {
+				children = append(children, &gtos.Items[i])
+			}
+		}
+	}
+
+	cgtos := &farosv1alpha1.ClusterGitTrackObjectList{}
+	err := c.List(context.TODO(), cgtos, selector)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list ClusterGitTrackObjects: %v", err)
+	}
+	for i := range cgtos.Items {
+		if metav1.IsControlledBy(&cgtos.Items[i], gt) {
+			children = append(children, &cgtos.Items[i])
+		}
+	}
+	return children, nil
+}
+
+// syncConditions are the GitTrack conditions that must all be true for the
+// GitTrack to be synced
+var syncConditions = []farosv1alpha1.GitTrackConditionType{
+	farosv1alpha1.FilesFetchedType,
+	farosv1alpha1.FilesParsedType,
+	farosv1alpha1.ChildrenUpToDateType,
+	farosv1alpha1.ChildrenGarbageCollectedType,
+}
+
+// checkGitTrack works out the sync state of the GitTrack from its status and
+// the status of its children
+func checkGitTrack(gt *farosv1alpha1.GitTrack, children []farosv1alpha1.GitTrackObjectInterface) Result {
+	if gt.Spec.Suspend {
+		return Result{State: StateSuspended}
+	}
+	if gt.Status.ObservedGeneration != gt.GetGeneration() {
+		return Result{State: StatePending, Message: "waiting for the controller to observe the latest spec"}
+	}
+
+	for _, condType := range syncConditions {
+		cond := gittrackutils.GetGitTrackCondition(gt.Status, condType)
+		if cond == nil || cond.ObservedGeneration != gt.GetGeneration() {
+			return Result{State: StatePending, Message: fmt.Sprintf("waiting for condition %s", condType)}
+		}
+		held := cond.Reason == string(gittrackutils.ChangesPending) || cond.Reason == string(gittrackutils.ApprovalPending) || cond.Reason == string(gittrackutils.CanaryInProgress) || cond.Reason == string(gittrackutils.HookInProgress)
+		if cond.Status == v1.ConditionFalse && held {
+			return Result{State: StatePending, Message: fmt.Sprintf("%s: %s", condType, cond.Message)}
+		}
+		if cond.Status == v1.ConditionFalse {
+			return Result{State: StateFailed, Message: fmt.Sprintf("%s: %s: %s", condType, cond.Reason, cond.Message)}
+		}
+		if cond.Status == v1.ConditionUnknown {
+			return Result{State: StatePending, Message: fmt.Sprintf("waiting for condition %s", condType)}
+		}
+	}
+
+	reference := gt.Spec.Reference
+	if gt.Spec.RollbackTo != "" {
+		if gt.Status.Rollback == nil || gt.Status.Rollback.Revision != gt.Spec.RollbackTo {
+			return Result{State: StatePending, Message: fmt.Sprintf("waiting for rollback to revision %s", gt.Spec.RollbackTo)}
+		}
+		reference = gt.Status.Rollback.Commit
+	}
+	if gt.Status.LastSyncedReference != reference {
+		return Result{State: StatePending, Message: fmt.Sprintf("waiting for reference %s to be synced", reference)}
+	}
+
+	for _, child := range children {
+		res := checkChild(child)
+		if res.State != StateSynced {
+			return res
+		}
+	}
+	return Result{State: StateSynced}
+}
+
+// checkChild works out the sync state of a (Cluster)GitTrackObject from its
+// ObjectInSync condition
+func checkChild(child farosv1alpha1.GitTrackObjectInterface) Result {
+	status := child.GetStatus()
+	cond := gittrackobjectutils.GetGitTrackObjectCondition(status, farosv1alpha1.ObjectInSyncType)
+	if status.ObservedGeneration != child.GetGeneration() || cond == nil || cond.ObservedGeneration != child.GetGeneration() {
+		return Result{State: StatePending, Message: fmt.Sprintf("waiting for child %s to be synced", child.GetNamespacedName())}
+	}
+	if cond.Status != v1.ConditionTrue {
+		return Result{State: StateFailed, Message: fmt.Sprintf("child %s: %s: %s", child.GetNamespacedName(), cond.Reason, cond.Message)}
+	}
+	return Result{State: StateSynced}
+}
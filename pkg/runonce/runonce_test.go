@@ -0,0 +1,161 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runonce
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackutils "github.com/pusher/faros/pkg/controller/gittrack/utils"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"github.com/pusher/faros/test/reporters"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunOnce(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "RunOnce Suite", reporters.Reporters())
+}
+
+var _ = Describe("RunOnce Suite", func() {
+	var gt *farosv1alpha1.GitTrack
+	var gto *farosv1alpha1.GitTrackObject
+
+	var setCondition = func(condType farosv1alpha1.GitTrackConditionType, status v1.ConditionStatus) {
+		cond := gittrackutils.NewGitTrackCondition(condType, status, gittrackutils.StatusUnknown, "message")
+		cond.ObservedGeneration = gt.Generation
+		gittrackutils.SetGitTrackCondition(&gt.Status, *cond)
+	}
+
+	var setInSync = func(status v1.ConditionStatus) {
+		cond := gittrackobjectutils.NewGitTrackObjectCondition(farosv1alpha1.ObjectInSyncType, status, gittrackobjectutils.ErrorCreatingChild, "message")
+		cond.ObservedGeneration = gto.Generation
+		gittrackobjectutils.SetGitTrackObjectCondition(&gto.Status, *cond)
+	}
+
+	BeforeEach(func() {
+		gt = &farosv1alpha1.GitTrack{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default", Generation: 2},
+			Spec:       farosv1alpha1.GitTrackSpec{Reference: "master"},
+			Status:     farosv1alpha1.GitTrackStatus{ObservedGeneration: 2, LastSyncedReference: "master"},
+		}
+		for _, condType := range syncConditions {
+			setCondition(condType, v1.ConditionTrue)
+		}
+
+		gto = &farosv1alpha1.GitTrackObject{
+			ObjectMeta: metav1.ObjectMeta{Name: "deployment-nginx", Namespace: "default", Generation: 1},
+			Status:     farosv1alpha1.GitTrackObjectStatus{ObservedGeneration: 1},
+		}
+		setInSync(v1.ConditionTrue)
+	})
+
+	Context("checkGitTrack", func() {
+		var check = func() Result {
+			return checkGitTrack(gt, []farosv1alpha1.GitTrackObjectInterface{gto})
+		}
+
+		It("is synced when the GitTrack and its children are in sync", func() {
+			Expect(check().State).To(Equal(StateSynced))
+		})
+
+		It("is suspended when the GitTrack is suspended", func() {
+			gt.Spec.Suspend = true
+			Expect(check().State).To(Equal(StateSuspended))
+		})
+
+		It("is pending when the latest spec has not been observed", func() {
+			gt.Generation = 3
+			Expect(check().State).To(Equal(StatePending))
+		})
+
+		It("is pending when the reference has not been synced yet", func() {
+			gt.Spec.Reference = "develop"
+			Expect(check().State).To(Equal(StatePending))
+		})
+
+		It("is pending when the rollback has not been resolved yet", func() {
+			gt.Spec.RollbackTo = "example-abcde"
+			Expect(check().State).To(Equal(StatePending))
+		})
+
+		It("is synced when the rollback commit has been synced", func() {
+			gt.Spec.RollbackTo = "example-abcde"
+			gt.Status.Rollback = &farosv1alpha1.GitTrackRollback{Revision: "example-abcde", Commit: "a14443638218c782b84cae56a14f1090ee9e5c9c"}
+			gt.Status.LastSyncedReference = "a14443638218c782b84cae56a14f1090ee9e5c9c"
+			Expect(check().State).To(Equal(StateSynced))
+		})
+
+		It("has failed when a condition is false", func() {
+			setCondition(farosv1alpha1.FilesFetchedType, v1.ConditionFalse)
+			res := check()
+			Expect(res.State).To(Equal(StateFailed))
+			Expect(res.Message).To(ContainSubstring("FilesFetched"))
+		})
+
+		It("is pending when a condition is unknown", func() {
+			setCondition(farosv1alpha1.ChildrenUpToDateType, v1.ConditionUnknown)
+			res := check()
+			Expect(res.State).To(Equal(StatePending))
+			Expect(res.Message).To(ContainSubstring("ChildrenUpToDate"))
+		})
+
+		It("is pending when a child has not observed its latest spec", func() {
+			gto.Generation = 2
+			Expect(check().State).To(Equal(StatePending))
+		})
+
+		It("has failed when a child is not in sync", func() {
+			setInSync(v1.ConditionFalse)
+			res := check()
+			Expect(res.State).To(Equal(StateFailed))
+			Expect(res.Message).To(ContainSubstring("default/deployment-nginx"))
+			Expect(res.Message).To(ContainSubstring(string(gittrackobjectutils.ErrorCreatingChild)))
+		})
+	})
+
+	Context("Done and Failed", func() {
+		var results []Result
+
+		BeforeEach(func() {
+			results = []Result{
+				{Name: "default/synced", State: StateSynced},
+				{Name: "default/suspended", State: StateSuspended},
+			}
+		})
+
+		It("is done and has not failed when all GitTracks are synced or suspended", func() {
+			Expect(Done(results)).To(BeTrue())
+			Expect(Failed(results)).To(BeEmpty())
+		})
+
+		It("is not done while a GitTrack is pending", func() {
+			results = append(results, Result{Name: "default/pending", State: StatePending})
+			Expect(Done(results)).To(BeFalse())
+			Expect(Failed(results)).To(HaveLen(1))
+		})
+
+		It("is done and has failed when a GitTrack failed", func() {
+			results = append(results, Result{Name: "default/failed", State: StateFailed})
+			Expect(Done(results)).To(BeTrue())
+			Expect(Failed(results)).To(ConsistOf(results[2]))
+		})
+	})
+})
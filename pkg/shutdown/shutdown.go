@@ -0,0 +1,98 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"sync"
+	"time"
+
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var (
+	mutex    sync.Mutex
+	draining bool
+	inFlight = &sync.WaitGroup{}
+	flushers = make(map[string]func())
+
+	log = rlogr.Log.WithName("shutdown")
+)
+
+// Begin registers the start of a unit of work, such as a reconcile, that
+// should be allowed to finish before the process exits. It returns a function
+// to call once the work is done, or false if the process is shutting down and
+// no new work should be started.
+func Begin() (func(), bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if draining {
+		return nil, false
+	}
+	wg := inFlight
+	wg.Add(1)
+	return wg.Done, true
+}
+
+// AddFlusher registers a function that is called once all in-flight work has
+// finished, to flush anything buffered before the process exits
+func AddFlusher(name string, flush func()) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	flushers[name] = flush
+}
+
+// Drain stops new work from being started and waits for up to timeout for
+// the work in flight to finish before calling the registered flushers. It
+// returns false if the timeout expired before all work had finished.
+func Drain(timeout time.Duration) bool {
+	mutex.Lock()
+	draining = true
+	wg := inFlight
+	mutex.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	finished := true
+	select {
+	case <-drained:
+	case <-time.After(timeout):
+		log.Error(nil, "timed out waiting for in-flight work to finish", "timeout", timeout.String())
+		finished = false
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for name, flush := range flushers {
+		log.V(1).Info("Flushing", "flusher", name)
+		flush()
+	}
+	return finished
+}
+
+// Reset allows work to be started again and removes all registered flushers.
+// Work still in flight is not waited for.
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	draining = false
+	inFlight = &sync.WaitGroup{}
+	flushers = make(map[string]func())
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shutdown
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestShutdown(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Shutdown Suite", reporters.Reporters())
+}
+
+var _ = Describe("Shutdown Suite", func() {
+	BeforeEach(func() {
+		Reset()
+	})
+
+	It("drains immediately when no work is in flight", func() {
+		Expect(Drain(time.Second)).To(BeTrue())
+	})
+
+	It("waits for in-flight work to finish", func() {
+		done, ok := Begin()
+		Expect(ok).To(BeTrue())
+
+		finished := make(chan bool)
+		go func() { finished <- Drain(5 * time.Second) }()
+		Consistently(finished, 100*time.Millisecond).ShouldNot(Receive())
+
+		done()
+		Eventually(finished).Should(Receive(BeTrue()))
+	})
+
+	It("gives up waiting once the timeout expires", func() {
+		_, ok := Begin()
+		Expect(ok).To(BeTrue())
+		Expect(Drain(100 * time.Millisecond)).To(BeFalse())
+	})
+
+	It("does not start new work while draining", func() {
+		Expect(Drain(time.Second)).To(BeTrue())
+		done, ok := Begin()
+		Expect(ok).To(BeFalse())
+		Expect(done).To(BeNil())
+	})
+
+	It("calls the flushers after the work in flight has finished", func() {
+		flushed := []string{}
+		done, _ := Begin()
+		AddFlusher("events", func() { flushed = append(flushed, "events") })
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			flushed = append(flushed, "work")
+			done()
+		}()
+		Expect(Drain(5 * time.Second)).To(BeTrue())
+		Expect(flushed).To(Equal([]string{"work", "events"}))
+	})
+})
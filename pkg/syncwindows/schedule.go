@@ -0,0 +1,152 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncwindows
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is the range of values a cron field accepts
+type field struct {
+	name     string
+	min, max uint
+}
+
+var (
+	minutes     = field{"minute", 0, 59}
+	hours       = field{"hour", 0, 23}
+	daysOfMonth = field{"day-of-month", 1, 31}
+	months      = field{"month", 1, 12}
+	// Both 0 and 7 are Sunday
+	daysOfWeek = field{"day-of-week", 0, 7}
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+
+	// restrictedDays is true when both the day-of-month and day-of-week fields
+	// are restricted, in which case a time matches if either of them match
+	restrictedDays bool
+}
+
+// ParseSchedule parses a standard five field cron expression
+// (minute hour day-of-month month day-of-week). Each field accepts `*`,
+// single values, ranges (`a-b`), steps (`*/n`, `a-b/n`) and comma separated
+// lists of these.
+func ParseSchedule(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields in schedule %q, found %d", expr, len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	for i, f := range []struct {
+		bits  *uint64
+		field field
+	}{
+		{&s.minute, minutes},
+		{&s.hour, hours},
+		{&s.dayOfMonth, daysOfMonth},
+		{&s.month, months},
+		{&s.dayOfWeek, daysOfWeek},
+	} {
+		if *f.bits, err = parseField(fields[i], f.field); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", expr, err)
+		}
+	}
+	// Sunday may be given as 7
+	if s.dayOfWeek&(1<<7) != 0 {
+		s.dayOfWeek |= 1
+	}
+	s.restrictedDays = fields[2] != "*" && fields[4] != "*"
+	return s, nil
+}
+
+// parseField parses a single cron field into a bit set of the values it
+// matches
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			s, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || s == 0 {
+				return 0, fmt.Errorf("invalid step in %s %q", f.name, part)
+			}
+			rangeExpr, step = part[:i], uint(s)
+		}
+
+		start, end := f.min, f.max
+		if rangeExpr != "*" {
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = parseValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				if end, err = parseValue(bounds[1], f); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// `a/n` runs from a to the end of the range
+				end = f.max
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range in %s %q", f.name, part)
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a single value within the range of the field
+func parseValue(value string, f field) (uint, error) {
+	v, err := strconv.ParseUint(value, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", f.name, value)
+	}
+	if uint(v) < f.min || uint(v) > f.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", f.name, v, f.min, f.max)
+	}
+	return uint(v), nil
+}
+
+// Matches returns whether the schedule fires at the minute of t
+func (s *Schedule) Matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 ||
+		s.hour&(1<<uint(t.Hour())) == 0 ||
+		s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	dom := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dow := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.restrictedDays {
+		return dom || dow
+	}
+	return dom && dow
+}
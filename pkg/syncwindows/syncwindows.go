@@ -0,0 +1,104 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncwindows
+
+import (
+	"fmt"
+	"time"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+)
+
+// maxDuration bounds the length of a window so that checking whether it is
+// active stays cheap
+const maxDuration = 7 * 24 * time.Hour
+
+// window is a parsed SyncWindow
+type window struct {
+	farosv1alpha1.SyncWindow
+	schedule *Schedule
+	location *time.Location
+}
+
+// parse validates the SyncWindow and parses its schedule and time zone
+func parse(w farosv1alpha1.SyncWindow) (*window, error) {
+	if w.Kind != farosv1alpha1.SyncWindowAllow && w.Kind != farosv1alpha1.SyncWindowDeny {
+		return nil, fmt.Errorf("invalid kind %q, must be %s or %s", w.Kind, farosv1alpha1.SyncWindowAllow, farosv1alpha1.SyncWindowDeny)
+	}
+	if w.Duration.Duration <= 0 || w.Duration.Duration > maxDuration {
+		return nil, fmt.Errorf("invalid duration %s, must be positive and at most %s", w.Duration.Duration, maxDuration)
+	}
+	schedule, err := ParseSchedule(w.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	location := time.UTC
+	if w.TimeZone != "" {
+		if location, err = time.LoadLocation(w.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", w.TimeZone, err)
+		}
+	}
+	return &window{SyncWindow: w, schedule: schedule, location: location}, nil
+}
+
+// active returns whether a window started within its duration before now
+func (w *window) active(now time.Time) bool {
+	now = now.In(w.location).Truncate(time.Minute)
+	for start := now; now.Sub(start) < w.Duration.Duration; start = start.Add(-time.Minute) {
+		if w.schedule.Matches(start) {
+			return true
+		}
+	}
+	return false
+}
+
+// String describes the window
+func (w *window) String() string {
+	timeZone := w.TimeZone
+	if timeZone == "" {
+		timeZone = "UTC"
+	}
+	return fmt.Sprintf("%s window %q for %s (%s)", w.Kind, w.Schedule, w.Duration.Duration, timeZone)
+}
+
+// Open returns whether changes may be applied at now according to the sync
+// windows, along with a message explaining why when they may not.
+// Changes are never applied while a Deny window is active. If there are any
+// Allow windows, changes are only applied while at least one is active.
+// An error is returned if any of the windows are invalid.
+func Open(windows []farosv1alpha1.SyncWindow, now time.Time) (bool, string, error) {
+	allowed, hasAllow := false, false
+	for _, w := range windows {
+		parsed, err := parse(w)
+		if err != nil {
+			return false, "", err
+		}
+		switch parsed.Kind {
+		case farosv1alpha1.SyncWindowDeny:
+			if parsed.active(now) {
+				return false, fmt.Sprintf("inside %s", parsed), nil
+			}
+		case farosv1alpha1.SyncWindowAllow:
+			hasAllow = true
+			allowed = allowed || parsed.active(now)
+		}
+	}
+	if hasAllow && !allowed {
+		return false, "outside of all Allow windows", nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncwindows
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"github.com/pusher/faros/test/reporters"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSyncWindows(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "SyncWindows Suite", reporters.Reporters())
+}
+
+var _ = Describe("SyncWindows Suite", func() {
+	// Monday 2019-06-03 22:30 UTC
+	var now = time.Date(2019, time.June, 3, 22, 30, 0, 0, time.UTC)
+
+	var newWindow = func(kind farosv1alpha1.SyncWindowKind, schedule string, duration time.Duration) farosv1alpha1.SyncWindow {
+		return farosv1alpha1.SyncWindow{
+			Kind:     kind,
+			Schedule: schedule,
+			Duration: metav1.Duration{Duration: duration},
+		}
+	}
+
+	Context("ParseSchedule", func() {
+		It("matches every minute with wildcards", func() {
+			s, err := ParseSchedule("* * * * *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Matches(now)).To(BeTrue())
+		})
+
+		It("matches values, ranges, steps and lists", func() {
+			s, err := ParseSchedule("*/15 20-23 1,3 6 *")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Matches(now)).To(BeTrue())
+			Expect(s.Matches(now.Add(time.Minute))).To(BeFalse())
+			Expect(s.Matches(now.Add(24 * time.Hour))).To(BeFalse())
+		})
+
+		It("accepts 7 as Sunday", func() {
+			s, err := ParseSchedule("0 0 * * 7")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Matches(time.Date(2019, time.June, 2, 0, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("matches either day field when both are restricted", func() {
+			s, err := ParseSchedule("30 22 15 * 1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Matches(now)).To(BeTrue())
+			Expect(s.Matches(time.Date(2019, time.June, 15, 22, 30, 0, 0, time.UTC))).To(BeTrue())
+			Expect(s.Matches(time.Date(2019, time.June, 4, 22, 30, 0, 0, time.UTC))).To(BeFalse())
+		})
+
+		It("rejects invalid expressions", func() {
+			for _, expr := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+				_, err := ParseSchedule(expr)
+				Expect(err).To(HaveOccurred(), expr)
+			}
+		})
+	})
+
+	Context("Open", func() {
+		It("is open without any windows", func() {
+			open, _, err := Open(nil, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeTrue())
+		})
+
+		It("is open inside an Allow window", func() {
+			open, _, err := Open([]farosv1alpha1.SyncWindow{
+				newWindow(farosv1alpha1.SyncWindowAllow, "0 22 * * *", time.Hour),
+			}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeTrue())
+		})
+
+		It("is closed outside all Allow windows", func() {
+			open, message, err := Open([]farosv1alpha1.SyncWindow{
+				newWindow(farosv1alpha1.SyncWindowAllow, "0 22 * * *", 30*time.Minute),
+			}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeFalse())
+			Expect(message).To(Equal("outside of all Allow windows"))
+		})
+
+		It("is closed inside a Deny window, even inside an Allow window", func() {
+			open, message, err := Open([]farosv1alpha1.SyncWindow{
+				newWindow(farosv1alpha1.SyncWindowAllow, "* * * * *", time.Minute),
+				newWindow(farosv1alpha1.SyncWindowDeny, "0 22 * * 1", time.Hour),
+			}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeFalse())
+			Expect(message).To(ContainSubstring(`Deny window "0 22 * * 1"`))
+		})
+
+		It("evaluates the schedule in the window's time zone", func() {
+			window := newWindow(farosv1alpha1.SyncWindowAllow, "0 0 * * *", time.Hour)
+			window.TimeZone = "Asia/Tokyo"
+			// 22:30 UTC is 07:30 in Tokyo
+			open, _, err := Open([]farosv1alpha1.SyncWindow{window}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeFalse())
+
+			window.TimeZone = "America/Chicago"
+			// 22:30 UTC is 17:30 in Chicago
+			window.Schedule = "0 17 * * *"
+			open, _, err = Open([]farosv1alpha1.SyncWindow{window}, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(open).To(BeTrue())
+		})
+
+		It("returns an error for invalid windows", func() {
+			window := newWindow(farosv1alpha1.SyncWindowAllow, "0 22 * * *", time.Hour)
+			window.TimeZone = "Nowhere/Special"
+			_, _, err := Open([]farosv1alpha1.SyncWindow{window}, now)
+			Expect(err).To(HaveOccurred())
+
+			_, _, err = Open([]farosv1alpha1.SyncWindow{newWindow("Sometimes", "0 22 * * *", time.Hour)}, now)
+			Expect(err).To(HaveOccurred())
+
+			_, _, err = Open([]farosv1alpha1.SyncWindow{newWindow(farosv1alpha1.SyncWindowDeny, "0 22 * * *", 0)}, now)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
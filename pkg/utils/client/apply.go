@@ -14,16 +14,18 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// Package client has been copied from kubectl code almost verbatim
+// This file has been copied from kubectl code almost verbatim
 // Some methods have been moved from k/k into the utils.go file to remove the
 // dependency on K/K
 // https://github.com/kubernetes/kubernetes/blob/v1.13.1/pkg/kubectl/cmd/apply/apply.go
+
 package client
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -51,11 +53,42 @@ type Options struct {
 
 	// Mapper, if provided, will be used to map GroupVersionKinds to Resources
 	Mapper meta.RESTMapper
+
+	// FieldManager, if provided, is sent as the field manager of every create
+	// and patch so that the API server can attribute the fields the Applier
+	// sets to it. API servers that do not track field managers ignore it.
+	FieldManager string
+
+	// Annotation, if provided, is the annotation the last applied
+	// configuration is stored in. Defaults to LastAppliedAnnotation.
+	// Changing the annotation of an existing Applier means the configuration
+	// recorded under the previous annotation is no longer merged.
+	Annotation string
+
+	// DryRun, if true, makes every request a server side dry run, whatever
+	// the ServerDryRun field of the ApplyOptions passed to each call
+	DryRun bool
 }
 
 // Client defines the interface for the Applier
 type Client interface {
+	// Apply creates the object if it does not exist or updates it with a three
+	// way merge patch if it does. The object is updated with the response from
+	// the API server.
 	Apply(context.Context, *ApplyOptions, runtime.Object) error
+
+	// Diff returns the patch Apply would send to update current, the second
+	// object, to desired, the first, and the fields it changes
+	Diff(context.Context, *ApplyOptions, runtime.Object, runtime.Object) (*DiffResult, error)
+
+	// MatchesLastApplied returns whether the configuration of modified, the
+	// second object, is the configuration last applied to current, the first
+	MatchesLastApplied(runtime.Object, runtime.Object) (bool, error)
+
+	// ExceedsAnnotationLimit returns whether the configuration of the object
+	// is too large to be recorded in the annotation the last applied
+	// configuration is stored in
+	ExceedsAnnotationLimit(runtime.Object) (bool, error)
 }
 
 // Make sure Applier implements Client
@@ -72,6 +105,10 @@ type Applier struct {
 	config        *rest.Config
 	codecs        serializer.CodecFactory
 	log           logr.Logger
+
+	fieldManager string
+	annotation   string
+	dryRun       bool
 }
 
 // NewApplier constucts a new Applier client
@@ -85,6 +122,12 @@ func NewApplier(config *rest.Config, options Options) (*Applier, error) {
 		options.Scheme = scheme.Scheme
 	}
 
+	// Store the last applied configuration in the faros annotation unless
+	// told otherwise
+	if options.Annotation == "" {
+		options.Annotation = LastAppliedAnnotation
+	}
+
 	// Init a Mapper if none provided
 	if options.Mapper == nil {
 		var err error
@@ -118,6 +161,9 @@ func NewApplier(config *rest.Config, options Options) (*Applier, error) {
 		dynamicClient: dynamicClient,
 		config:        config,
 		log:           rlogr.Log.WithName("applier"),
+		fieldManager:  options.FieldManager,
+		annotation:    options.Annotation,
+		dryRun:        options.DryRun,
 	}
 
 	return a, nil
@@ -131,6 +177,24 @@ type ApplyOptions struct {
 	DeletionTimeout     *time.Duration
 	DeletionGracePeriod *int
 	ServerDryRun        *bool
+	// IgnorePaths is a list of JSON pointers (eg. /spec/replicas) to exclude
+	// from the three way merge when updating an existing resource
+	IgnorePaths []string
+	// MergeLists preserves list entries added outside of the applier when
+	// updating resources that fall back to a JSON merge patch (eg. custom
+	// resources), rather than replacing lists wholesale
+	MergeLists *bool
+	// ConflictRetries is the number of times to re-fetch the resource and
+	// recompute the patch if an update fails with a conflict
+	ConflictRetries *int
+	// PropagationPolicy overrides the deletion propagation policy implied by
+	// CascadeDeletion when a resource is deleted and recreated
+	PropagationPolicy *metav1.DeletionPropagation
+	// LastApplied is the object as it was last applied. When the last applied
+	// configuration was too large for the annotation only its hash is
+	// recorded, and the configuration of LastApplied is merged against in its
+	// place if it has that hash.
+	LastApplied runtime.Object
 }
 
 // Complete defaults valus within the ApplyOptions struct
@@ -142,6 +206,8 @@ func (a *ApplyOptions) Complete() {
 	deletionTimeout := time.Duration(30 * time.Second)
 	deletionGracePeriod := -1
 	serverDryRun := false
+	mergeLists := false
+	conflictRetries := maxPatchRetry
 
 	if a.Overwrite == nil {
 		a.Overwrite = &overwrite
@@ -161,13 +227,29 @@ func (a *ApplyOptions) Complete() {
 	if a.ServerDryRun == nil {
 		a.ServerDryRun = &serverDryRun
 	}
+	if a.MergeLists == nil {
+		a.MergeLists = &mergeLists
+	}
+	if a.ConflictRetries == nil {
+		a.ConflictRetries = &conflictRetries
+	}
+}
+
+// complete defaults the values within the ApplyOptions and forces server side
+// dry run if the Applier was created with DryRun
+func (a *Applier) complete(opts *ApplyOptions) {
+	opts.Complete()
+	if a.dryRun {
+		serverDryRun := true
+		opts.ServerDryRun = &serverDryRun
+	}
 }
 
 // Apply performs a strategic three way merge update to the resource if it exists,
 // else it creates the resource
 func (a *Applier) Apply(ctx context.Context, opts *ApplyOptions, modified runtime.Object) error {
 	// Default option values
-	opts.Complete()
+	a.complete(opts)
 
 	current := newUnstructuredFor(modified)
 
@@ -205,9 +287,9 @@ func (a *Applier) create(ctx context.Context, opts *ApplyOptions, obj runtime.Ob
 	)
 	log.V(2).Info("creating resource", "dry-run", *opts.ServerDryRun)
 
-	err = createApplyAnnotation(obj, unstructured.UnstructuredJSONScheme)
+	err = createApplyAnnotation(obj, a.annotation, unstructured.UnstructuredJSONScheme)
 	if err != nil {
-		return fmt.Errorf("unable to apply LastAppliedAnnotation to object: %v", err)
+		return fmt.Errorf("unable to apply %s annotation to object: %v", a.annotation, err)
 	}
 
 	gvk := obj.GetObjectKind().GroupVersionKind()
@@ -226,14 +308,18 @@ func (a *Applier) create(ctx context.Context, opts *ApplyOptions, obj runtime.Ob
 		createOptions.DryRun = []string{metav1.DryRunAll}
 	}
 
-	err = restClient.Post().
+	req := restClient.Post().
 		NamespaceIfScoped(metadata.GetNamespace(), isNamespaced(mapping)).
 		Resource(mapping.Resource.Resource).
 		Body(obj).
-		VersionedParams(createOptions, metav1.ParameterCodec).
-		Context(ctx).
+		VersionedParams(createOptions, metav1.ParameterCodec)
+	if a.fieldManager != "" {
+		req = req.Param(fieldManagerParam, a.fieldManager)
+	}
+	err = req.Context(ctx).
 		Do().
 		Into(obj)
+	a.updateOperationMetrics(gvk.Kind, createOperation, nil, err)
 	if err != nil {
 		return fmt.Errorf("error creating object: %v", err)
 	}
@@ -253,7 +339,7 @@ func (a *Applier) update(ctx context.Context, opts *ApplyOptions, current, modif
 	)
 	log.V(2).Info("updating resource", "dry-run", *opts.ServerDryRun)
 
-	modifiedJSON, err := getModifiedConfiguration(modified, true, unstructured.UnstructuredJSONScheme)
+	modifiedJSON, err := getModifiedConfiguration(modified, a.annotation, true, unstructured.UnstructuredJSONScheme)
 	if err != nil {
 		return fmt.Errorf("unable to get modified configuration: %v", err)
 	}
@@ -263,7 +349,8 @@ func (a *Applier) update(ctx context.Context, opts *ApplyOptions, current, modif
 		return fmt.Errorf("unable to construct patcher: %v", err)
 	}
 	source := metadata.GetSelfLink() // This is optional and would normally be the file path
-	_, patchedObj, err := patcher.Patch(current, modifiedJSON, source, metadata.GetNamespace(), metadata.GetName(), nil)
+	patch, patchedObj, err := patcher.Patch(current, modifiedJSON, source, metadata.GetNamespace(), metadata.GetName(), nil)
+	a.updateOperationMetrics(modified.GetObjectKind().GroupVersionKind().Kind, updateOperation, patch, err)
 	if err != nil {
 		return fmt.Errorf("unable to patch object: %v", err)
 	}
@@ -276,6 +363,68 @@ func (a *Applier) update(ctx context.Context, opts *ApplyOptions, current, modif
 	return nil
 }
 
+// Diff computes the three way merge patch that Apply would send to the API
+// server to update current to the desired state, without sending it, and
+// the fields it changes.
+//
+// An empty patch (`{}`) is returned if no update is required
+func (a *Applier) Diff(ctx context.Context, opts *ApplyOptions, desired, current runtime.Object) (*DiffResult, error) {
+	// Default option values
+	a.complete(opts)
+
+	return diff(opts, a.annotation, desired, current)
+}
+
+// MatchesLastApplied returns whether the configuration of modified is the
+// same as the configuration last applied to current. When it is, any
+// difference between current and modified was made outside of the applier.
+//
+// The last applied configuration is read from LastAppliedAnnotation, use the
+// MatchesLastApplied method of an Applier created with another Annotation.
+func MatchesLastApplied(current, modified runtime.Object) (bool, error) {
+	return matchesLastApplied(current, modified, LastAppliedAnnotation)
+}
+
+// MatchesLastApplied returns whether the configuration of modified is the
+// same as the configuration last applied to current by the Applier
+func (a *Applier) MatchesLastApplied(current, modified runtime.Object) (bool, error) {
+	return matchesLastApplied(current, modified, a.annotation)
+}
+
+// ExceedsAnnotationLimit returns whether the configuration of the object is
+// too large to be recorded in the annotation the Applier stores the last
+// applied configuration in, in which case only its hash is recorded
+func (a *Applier) ExceedsAnnotationLimit(obj runtime.Object) (bool, error) {
+	return exceedsAnnotationLimit(obj, a.annotation)
+}
+
+func matchesLastApplied(current, modified runtime.Object, annotation string) (bool, error) {
+	originalJSON, err := getOriginalConfiguration(current, annotation)
+	if err != nil {
+		return false, fmt.Errorf("unable to get original configuration: %v", err)
+	}
+	if originalJSON == nil {
+		return false, nil
+	}
+
+	modifiedJSON, err := getModifiedConfiguration(modified.DeepCopyObject(), annotation, false, unstructured.UnstructuredJSONScheme)
+	if err != nil {
+		return false, fmt.Errorf("unable to get modified configuration: %v", err)
+	}
+	if isConfigurationHash(originalJSON) {
+		return string(originalJSON) == configurationHash(modifiedJSON), nil
+	}
+
+	var original, desired interface{}
+	if err := json.Unmarshal(originalJSON, &original); err != nil {
+		return false, fmt.Errorf("unable to unmarshal original configuration: %v", err)
+	}
+	if err := json.Unmarshal(modifiedJSON, &desired); err != nil {
+		return false, fmt.Errorf("unable to unmarshal modified configuration: %v", err)
+	}
+	return reflect.DeepEqual(original, desired), nil
+}
+
 func (a *Applier) newPatcher(opts *ApplyOptions, obj runtime.Object) (*Patcher, error) {
 	gvk := obj.GetObjectKind().GroupVersionKind()
 	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
@@ -288,24 +437,48 @@ func (a *Applier) newPatcher(opts *ApplyOptions, obj runtime.Object) (*Patcher,
 		return nil, fmt.Errorf("unable to get REST Client: %v", err)
 	}
 
+	lastApplied, err := lastAppliedConfiguration(opts, a.annotation)
+	if err != nil {
+		return nil, err
+	}
+
 	helper := resource.NewHelper(restClient, mapping)
 	p := &Patcher{
-		Mapping:       mapping,
-		Helper:        helper,
-		DynamicClient: a.dynamicClient,
-		Overwrite:     *opts.Overwrite,
-		BackOff:       clockwork.NewRealClock(),
-		Force:         *opts.ForceDeletion,
-		Cascade:       *opts.CascadeDeletion,
-		Timeout:       *opts.DeletionTimeout,
-		GracePeriod:   *opts.DeletionGracePeriod,
-		ServerDryRun:  *opts.ServerDryRun,
-		OpenapiSchema: nil, // Not supporting OpenapiSchema patching
-		Retries:       maxPatchRetry,
+		Mapping:           mapping,
+		Helper:            helper,
+		DynamicClient:     a.dynamicClient,
+		Overwrite:         *opts.Overwrite,
+		BackOff:           clockwork.NewRealClock(),
+		Force:             *opts.ForceDeletion,
+		Cascade:           *opts.CascadeDeletion,
+		PropagationPolicy: opts.PropagationPolicy,
+		Timeout:           *opts.DeletionTimeout,
+		GracePeriod:       *opts.DeletionGracePeriod,
+		ServerDryRun:      *opts.ServerDryRun,
+		OpenapiSchema:     nil, // Not supporting OpenapiSchema patching
+		Retries:           opts.ConflictRetries,
+		IgnorePaths:       opts.IgnorePaths,
+		MergeLists:        *opts.MergeLists,
+		FieldManager:      a.fieldManager,
+		Annotation:        a.annotation,
+		LastApplied:       lastApplied,
 	}
 	return p, nil
 }
 
+// lastAppliedConfiguration returns the configuration of the LastApplied
+// object of the options, or nil if not set
+func lastAppliedConfiguration(opts *ApplyOptions, annotation string) ([]byte, error) {
+	if opts.LastApplied == nil {
+		return nil, nil
+	}
+	config, err := getModifiedConfiguration(opts.LastApplied.DeepCopyObject(), annotation, false, unstructured.UnstructuredJSONScheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get last applied configuration: %v", err)
+	}
+	return config, nil
+}
+
 func (a *Applier) configFor(gv schema.GroupVersion) (*rest.Config, error) {
 	config := rest.CopyConfig(a.config)
 	err := rest.SetKubernetesDefaults(config)
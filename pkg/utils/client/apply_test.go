@@ -204,6 +204,54 @@ var _ = Describe("Applier Suite", func() {
 		})
 	})
 
+	Describe("with a custom annotation", func() {
+		const annotation = "example.com/last-applied-configuration"
+
+		BeforeEach(func() {
+			var err error
+			a, err = NewApplier(cfg, Options{Annotation: annotation, FieldManager: "example"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.Apply(context.TODO(), o, deployment)).NotTo(HaveOccurred())
+		})
+
+		It("records the last applied configuration in the annotation", func() {
+			Expect(deployment.GetAnnotations()).To(HaveKey(annotation))
+			Expect(deployment.GetAnnotations()).NotTo(HaveKey(LastAppliedAnnotation))
+		})
+
+		It("merges updates with the configuration in the annotation", func() {
+			deployment.Spec.Template.Spec.Containers[0].Image = "nginx:latest"
+			Expect(a.Apply(context.TODO(), &ApplyOptions{}, deployment)).NotTo(HaveOccurred())
+
+			serverDeployment := test.ExampleDeployment.DeepCopy()
+			m.Get(serverDeployment, timeout).Should(Succeed())
+			Expect(serverDeployment).Should(test.WithContainers(ContainElement(test.WithImage(Equal("nginx:latest")))))
+		})
+
+		It("matches the last applied configuration from the annotation", func() {
+			applier, ok := a.(*Applier)
+			Expect(ok).To(BeTrue())
+			Expect(applier.MatchesLastApplied(deployment, test.ExampleDeployment.DeepCopy())).To(BeTrue())
+			Expect(MatchesLastApplied(deployment, test.ExampleDeployment.DeepCopy())).To(BeFalse())
+		})
+	})
+
+	Describe("with DryRun", func() {
+		BeforeEach(func() {
+			if skipDryRun {
+				Skip("dry run tests are skipped")
+			}
+			var err error
+			a, err = NewApplier(cfg, Options{DryRun: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.Apply(context.TODO(), o, deployment)).NotTo(HaveOccurred())
+		})
+
+		It("does not create the deployment", func() {
+			m.Get(deployment, timeout).ShouldNot(Succeed())
+		})
+	})
+
 	Describe("with CRDs", func() {
 		var foo *unstructured.Unstructured
 		var crd *apiextensionsv1beta1.CustomResourceDefinition
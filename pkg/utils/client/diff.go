@@ -0,0 +1,198 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DiffResult is the difference between the desired and current state of a
+// resource
+type DiffResult struct {
+	// Patch is the three way merge patch that updates the current state to
+	// the desired state. It is `{}` if no update is required.
+	Patch []byte
+
+	// PatchType is the type of Patch, a strategic merge patch for types known
+	// to the scheme and a JSON merge patch otherwise
+	PatchType types.PatchType
+
+	// Paths are the sorted, dot separated paths of the fields Patch changes,
+	// eg. spec.replicas. Lists are reported as a whole and patch directives
+	// and the last applied annotation are left out.
+	Paths []string
+
+	// Changes are the fields Patch changes, in the same order as Paths, with
+	// their current and desired values
+	Changes []FieldChange
+}
+
+// FieldChange is a field changed by a patch
+type FieldChange struct {
+	// Path is the dot separated path of the field
+	Path string
+
+	// Current is the value of the field in the current state, nil if unset
+	Current interface{}
+
+	// Desired is the value of the field in the desired state, nil if it is
+	// removed
+	Desired interface{}
+}
+
+// Diff computes the patch Apply would send to update current to desired,
+// without contacting an API server, and the fields it changes.
+//
+// The last applied configuration is read from LastAppliedAnnotation.
+func Diff(desired, current runtime.Object) (*DiffResult, error) {
+	return DiffWithOptions(&ApplyOptions{}, desired, current)
+}
+
+// DiffWithOptions computes the patch Apply would send with the given options
+// to update current to desired. Only the options that affect the patch, such
+// as IgnorePaths, MergeLists and Overwrite, are used.
+func DiffWithOptions(opts *ApplyOptions, desired, current runtime.Object) (*DiffResult, error) {
+	return diff(opts, LastAppliedAnnotation, desired, current)
+}
+
+func diff(opts *ApplyOptions, annotation string, desired, current runtime.Object) (*DiffResult, error) {
+	// Default option values
+	opts.Complete()
+
+	metadata, err := meta.Accessor(desired)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get object metadata: %v", err)
+	}
+
+	modifiedJSON, err := getModifiedConfiguration(desired.DeepCopyObject(), annotation, true, unstructured.UnstructuredJSONScheme)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get modified configuration: %v", err)
+	}
+
+	lastApplied, err := lastAppliedConfiguration(opts, annotation)
+	if err != nil {
+		return nil, err
+	}
+
+	// Computing the patch only needs the GroupVersionKind of the mapping
+	patcher := &Patcher{
+		Mapping:     &meta.RESTMapping{GroupVersionKind: desired.GetObjectKind().GroupVersionKind()},
+		Overwrite:   *opts.Overwrite,
+		IgnorePaths: opts.IgnorePaths,
+		MergeLists:  *opts.MergeLists,
+		Annotation:  annotation,
+		LastApplied: lastApplied,
+	}
+	patchType, patch, err := patcher.createPatch(current, modifiedJSON, metadata.GetSelfLink(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compute patch: %v", err)
+	}
+
+	fields, err := patchFields(patch, annotation)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := fieldChanges(fields, desired, current)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return &DiffResult{Patch: patch, PatchType: patchType, Paths: paths, Changes: changes}, nil
+}
+
+// patchFields returns the fields modified by the patch as lists of keys,
+// sorted by their paths, excluding patch directives and the last applied
+// annotation
+func patchFields(patch []byte, annotation string) ([][]string, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(patch, &doc); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal patch: %v", err)
+	}
+	fields := fieldKeys(nil, doc, annotation)
+	sort.Slice(fields, func(i, j int) bool {
+		return strings.Join(fields[i], ".") < strings.Join(fields[j], ".")
+	})
+	return fields, nil
+}
+
+func fieldKeys(prefix []string, doc map[string]interface{}, annotation string) [][]string {
+	fields := [][]string{}
+	for key, value := range doc {
+		if strings.HasPrefix(key, "$") || key == annotation {
+			continue
+		}
+		field := append(append([]string{}, prefix...), key)
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			fields = append(fields, fieldKeys(field, m, annotation)...)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// fieldChanges returns the current and desired values of the given fields
+func fieldChanges(fields [][]string, desired, current runtime.Object) ([]FieldChange, error) {
+	desiredContent, err := unstructuredContent(desired)
+	if err != nil {
+		return nil, err
+	}
+	currentContent, err := unstructuredContent(current)
+	if err != nil {
+		return nil, err
+	}
+	changes := []FieldChange{}
+	for _, field := range fields {
+		changes = append(changes, FieldChange{
+			Path:    strings.Join(field, "."),
+			Current: fieldValue(currentContent, field),
+			Desired: fieldValue(desiredContent, field),
+		})
+	}
+	return changes, nil
+}
+
+func unstructuredContent(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert object to unstructured: %v", err)
+	}
+	return content, nil
+}
+
+// fieldValue returns the value of the field, or nil if it is not set
+func fieldValue(content map[string]interface{}, field []string) interface{} {
+	value, found, err := unstructured.NestedFieldNoCopy(content, field...)
+	if err != nil || !found {
+		return nil
+	}
+	return value
+}
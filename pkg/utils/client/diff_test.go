@@ -0,0 +1,120 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/pkg/utils/client/test"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Diff Suite", func() {
+	Context("Diff", func() {
+		var current, desired *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(test.ExampleDeployment.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			desired = &unstructured.Unstructured{Object: obj}
+			desired.SetAPIVersion("apps/v1")
+			desired.SetKind("Deployment")
+
+			current = desired.DeepCopy()
+			_, err = getModifiedConfiguration(current, LastAppliedAnnotation, true, unstructured.UnstructuredJSONScheme)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an empty patch when no update is required", func() {
+			result, err := Diff(desired, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(result.Patch)).To(Equal("{}"))
+			Expect(result.Paths).To(BeEmpty())
+		})
+
+		It("returns the paths changed in the desired state", func() {
+			Expect(unstructured.SetNestedField(desired.Object, int64(3), "spec", "replicas")).To(Succeed())
+			desired.SetLabels(map[string]string{"app": "nginx", "changed": "true"})
+
+			result, err := Diff(desired, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.PatchType).To(Equal(types.StrategicMergePatchType))
+			Expect(result.Paths).To(Equal([]string{"metadata.labels.changed", "spec.replicas"}))
+		})
+
+		It("returns the paths changed outside of the applier", func() {
+			Expect(unstructured.SetNestedField(current.Object, int64(5), "spec", "replicas")).To(Succeed())
+			Expect(unstructured.SetNestedField(desired.Object, int64(1), "spec", "replicas")).To(Succeed())
+
+			result, err := Diff(desired, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Paths).To(Equal([]string{"spec.replicas"}))
+		})
+
+		It("returns the current and desired values of the changed fields", func() {
+			Expect(unstructured.SetNestedField(current.Object, int64(5), "spec", "replicas")).To(Succeed())
+			Expect(unstructured.SetNestedField(desired.Object, int64(1), "spec", "replicas")).To(Succeed())
+			desired.SetLabels(map[string]string{"app": "nginx", "example.com/added": "true"})
+
+			result, err := Diff(desired, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Changes).To(Equal([]FieldChange{
+				{Path: "metadata.labels.example.com/added", Current: nil, Desired: "true"},
+				{Path: "spec.replicas", Current: int64(5), Desired: int64(1)},
+			}))
+		})
+
+		It("does not return ignored paths", func() {
+			Expect(unstructured.SetNestedField(desired.Object, int64(3), "spec", "replicas")).To(Succeed())
+
+			result, err := DiffWithOptions(&ApplyOptions{IgnorePaths: []string{"/spec/replicas"}}, desired, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Paths).To(BeEmpty())
+		})
+
+		It("returns a JSON merge patch for types unknown to the scheme", func() {
+			desired.SetAPIVersion("example.com/v1")
+			desired.SetKind("Example")
+			current.SetAPIVersion("example.com/v1")
+			current.SetKind("Example")
+			Expect(unstructured.SetNestedField(desired.Object, int64(3), "spec", "replicas")).To(Succeed())
+
+			result, err := Diff(desired, current)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.PatchType).To(Equal(types.MergePatchType))
+			Expect(result.Paths).To(Equal([]string{"spec.replicas"}))
+		})
+	})
+
+	Context("patchFields", func() {
+		It("returns the sorted fields modified", func() {
+			patch := []byte(`{"spec":{"template":{"spec":{"containers":[{"image":"nginx","name":"nginx"}]}},"replicas":1}}`)
+			Expect(patchFields(patch, LastAppliedAnnotation)).To(Equal([][]string{{"spec", "replicas"}, {"spec", "template", "spec", "containers"}}))
+		})
+
+		It("ignores patch directives and the last applied annotation", func() {
+			patch := []byte(`{"metadata":{"annotations":{"faros.pusher.com/last-applied-configuration":"{}"},"labels":{"app":"nginx"}},"spec":{"$setElementOrder/containers":[{"name":"nginx"}]}}`)
+			Expect(patchFields(patch, LastAppliedAnnotation)).To(Equal([][]string{{"metadata", "labels", "app"}}))
+		})
+
+		It("returns no fields for an empty patch", func() {
+			Expect(patchFields([]byte(`{}`), LastAppliedAnnotation)).To(BeEmpty())
+		})
+	})
+})
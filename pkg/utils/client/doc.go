@@ -0,0 +1,68 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package client provides the Applier faros uses to create and update
+resources, which may be used by other controllers as a library.
+
+The Applier performs the same three way merge as `kubectl apply`. The
+configuration applied is recorded in an annotation on the resource, and each
+update is a patch computed from the last applied configuration, the desired
+configuration and the live state of the resource. Fields set by other
+controllers are therefore left alone unless the desired configuration sets
+them.
+
+Create an Applier from a rest.Config:
+
+	applier, err := client.NewApplier(config, client.Options{
+		FieldManager: "my-operator",
+		Annotation:   "example.com/last-applied-configuration",
+	})
+	if err != nil {
+		return err
+	}
+
+and apply objects with it, the object is updated with the response of the API
+server:
+
+	err = applier.Apply(context.TODO(), &client.ApplyOptions{}, deployment)
+
+Options configure the Applier as a whole: the field manager sent with every
+request, the annotation the last applied configuration is stored in and
+whether every request is a server side dry run. ApplyOptions configure a
+single call, for example the fields to leave alone or whether to delete and
+recreate resources whose updates are rejected. Diff returns the patch Apply
+would send without sending it, along with the fields it changes.
+
+The package level Diff computes the same patch without an Applier or an API
+server, along with the paths of the fields it changes and their current and
+desired values:
+
+	result, err := client.Diff(desired, current)
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.Join(result.Paths, ", "))
+
+The Applier counts the operations it performs, the size of the patches it
+sends and the requests that fail in the prometheus collectors of the metrics
+subpackage, which are registered with the controller-runtime metrics registry.
+
+Much of the package has been copied from kubectl almost verbatim, with some
+methods moved into utils.go to remove the dependency on k/k.
+https://github.com/kubernetes/kubernetes/blob/v1.13.1/pkg/kubectl/cmd/apply/apply.go
+*/
+package client
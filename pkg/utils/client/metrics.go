@@ -0,0 +1,81 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strconv"
+
+	"github.com/pusher/faros/pkg/utils/client/metrics"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	createOperation = "create"
+	updateOperation = "update"
+	noopOperation   = "noop"
+)
+
+// updateOperationMetrics records the outcome of an operation on a resource of
+// the given kind, and the size of the patch sent for updates
+func (a *Applier) updateOperationMetrics(kind, operation string, patch []byte, opErr error) {
+	if opErr != nil {
+		counter, err := metrics.Errors.GetMetricWith(map[string]string{
+			"kind":      kind,
+			"operation": operation,
+			"code":      errorCode(opErr),
+		})
+		if err != nil {
+			a.log.Error(err, "unable to update errors metric")
+		} else {
+			counter.Inc()
+		}
+		return
+	}
+
+	// An empty patch is not sent to the API server
+	if operation == updateOperation && string(patch) == "{}" {
+		operation = noopOperation
+	}
+	counter, err := metrics.Operations.GetMetricWith(map[string]string{
+		"kind":      kind,
+		"operation": operation,
+	})
+	if err != nil {
+		a.log.Error(err, "unable to update operations metric")
+		return
+	}
+	counter.Inc()
+
+	if operation != updateOperation {
+		return
+	}
+	histogram, err := metrics.PatchSize.GetMetricWith(map[string]string{"kind": kind})
+	if err != nil {
+		a.log.Error(err, "unable to update patch size metric")
+		return
+	}
+	histogram.Observe(float64(len(patch)))
+}
+
+// errorCode returns the HTTP status code of an error returned by the API
+// server, or "unknown" for errors that did not come from the API server
+func errorCode(err error) string {
+	if status, ok := err.(errors.APIStatus); ok && status.Status().Code != 0 {
+		return strconv.Itoa(int(status.Status().Code))
+	}
+	return "unknown"
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// Operations is a prometheus counter for the operations performed by the
+	// Applier, whichever controller it is used by
+	Operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "faros_applier_operations_total",
+		Help: "Counts the create, update and no-op operations performed by the Applier",
+	}, []string{"kind", "operation"})
+
+	// PatchSize is a prometheus histogram that holds the size of the patches
+	// sent by the Applier to update resources
+	PatchSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "faros_applier_patch_size_bytes",
+		Help:    "Measures the size of the patches sent by the Applier",
+		Buckets: prometheus.ExponentialBuckets(64, 2, 14),
+	}, []string{"kind"})
+
+	// Errors is a prometheus counter for the requests made by the Applier
+	// that failed, by the status code returned by the API server
+	Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "faros_applier_errors_total",
+		Help: "Counts the create and update requests made by the Applier that failed",
+	}, []string{"kind", "operation", "code"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(Operations)
+	ctrlmetrics.Registry.MustRegister(PatchSize)
+	ctrlmetrics.Registry.MustRegister(Errors)
+}
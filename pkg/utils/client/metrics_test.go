@@ -0,0 +1,100 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/pusher/faros/pkg/utils/client/metrics"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	rlogr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+var _ = Describe("Metrics Suite", func() {
+	var a *Applier
+
+	var counterValue = func(labels map[string]string) float64 {
+		var metric dto.Metric
+		vec := metrics.Operations
+		if _, ok := labels["code"]; ok {
+			vec = metrics.Errors
+		}
+		counter, err := vec.GetMetricWith(labels)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counter.Write(&metric)).To(Succeed())
+		return metric.GetCounter().GetValue()
+	}
+
+	var patchSizeCount = func(kind string) uint64 {
+		var metric dto.Metric
+		histogram, err := metrics.PatchSize.GetMetricWith(map[string]string{"kind": kind})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(histogram.(prometheus.Metric).Write(&metric)).To(Succeed())
+		return metric.GetHistogram().GetSampleCount()
+	}
+
+	BeforeEach(func() {
+		a = &Applier{log: rlogr.Log.WithName("applier")}
+		metrics.Operations.Reset()
+		metrics.PatchSize.Reset()
+		metrics.Errors.Reset()
+	})
+
+	Context("updateOperationMetrics", func() {
+		It("counts creates", func() {
+			a.updateOperationMetrics("Deployment", createOperation, nil, nil)
+			Expect(counterValue(map[string]string{"kind": "Deployment", "operation": createOperation})).To(Equal(1.0))
+			Expect(patchSizeCount("Deployment")).To(BeZero())
+		})
+
+		It("counts updates and observes the size of their patch", func() {
+			a.updateOperationMetrics("Deployment", updateOperation, []byte(`{"spec":{"replicas":3}}`), nil)
+			Expect(counterValue(map[string]string{"kind": "Deployment", "operation": updateOperation})).To(Equal(1.0))
+			Expect(patchSizeCount("Deployment")).To(Equal(uint64(1)))
+		})
+
+		It("counts updates with an empty patch as no-ops", func() {
+			a.updateOperationMetrics("Deployment", updateOperation, []byte(`{}`), nil)
+			Expect(counterValue(map[string]string{"kind": "Deployment", "operation": noopOperation})).To(Equal(1.0))
+			Expect(counterValue(map[string]string{"kind": "Deployment", "operation": updateOperation})).To(BeZero())
+			Expect(patchSizeCount("Deployment")).To(BeZero())
+		})
+
+		It("counts errors by status code", func() {
+			err := apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "example", fmt.Errorf("conflict"))
+			a.updateOperationMetrics("Deployment", updateOperation, nil, err)
+			Expect(counterValue(map[string]string{"kind": "Deployment", "operation": updateOperation, "code": "409"})).To(Equal(1.0))
+			Expect(counterValue(map[string]string{"kind": "Deployment", "operation": updateOperation})).To(BeZero())
+		})
+	})
+
+	Context("errorCode", func() {
+		It("returns the status code of API errors", func() {
+			err := apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "example")
+			Expect(errorCode(err)).To(Equal("404"))
+		})
+
+		It("returns unknown for other errors", func() {
+			Expect(errorCode(fmt.Errorf("connection refused"))).To(Equal("unknown"))
+		})
+	})
+})
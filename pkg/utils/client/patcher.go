@@ -59,12 +59,14 @@ const (
 	maxPatchRetry = 5
 	// backOffPeriod is the period to back off when apply patch results in error.
 	backOffPeriod = 1 * time.Second
+	// maxBackOffPeriod is the longest period to back off between conflict retries
+	maxBackOffPeriod = 16 * time.Second
 	// how many times we can retry before back off
 	triesBeforeBackOff = 1
 )
 
 func (p *Patcher) delete(namespace, name string) error {
-	return runDelete(namespace, name, p.Mapping, p.DynamicClient, p.Cascade, p.GracePeriod, p.ServerDryRun)
+	return runDelete(namespace, name, p.Mapping, p.DynamicClient, p.Cascade, p.PropagationPolicy, p.GracePeriod, p.ServerDryRun)
 }
 
 // Patcher is used to perform a three-way-merge on runtime.Objects
@@ -76,32 +78,162 @@ type Patcher struct {
 	Overwrite bool
 	BackOff   clockwork.Clock
 
-	Force        bool
-	Cascade      bool
-	Timeout      time.Duration
-	GracePeriod  int
-	ServerDryRun bool
+	Force   bool
+	Cascade bool
+	// If set, overrides the propagation policy implied by Cascade
+	PropagationPolicy *metav1.DeletionPropagation
+	Timeout           time.Duration
+	GracePeriod       int
+	ServerDryRun      bool
 
 	// If set, forces the patch against a specific resourceVersion
 	ResourceVersion *string
 
-	// Number of retries to make if the patch fails with conflict
-	Retries int
+	// Number of retries to make if the patch fails with conflict, defaults to
+	// maxPatchRetry if nil
+	Retries *int
+
+	// JSON pointers to exclude from the original and modified configurations
+	// when computing the patch
+	IgnorePaths []string
+
+	// Whether to preserve list entries added outside of the patcher when
+	// falling back to a JSON merge patch
+	MergeLists bool
+
+	// If set, sent as the field manager of patches and creates
+	FieldManager string
+
+	// Annotation the last applied configuration is stored in, defaults to
+	// LastAppliedAnnotation
+	Annotation string
+
+	// Configuration last applied, merged against in place of the annotation
+	// when the annotation only holds the hash of the configuration
+	LastApplied []byte
 
 	OpenapiSchema openapi.Resources
 }
 
 func (p *Patcher) patchSimple(obj runtime.Object, modified []byte, source, namespace, name string, errOut io.Writer) ([]byte, runtime.Object, error) {
+	patchType, patch, err := p.createPatch(obj, modified, source, errOut)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if string(patch) == "{}" {
+		return patch, obj, nil
+	}
+
+	if p.ResourceVersion != nil {
+		patch, err = addResourceVersion(patch, *p.ResourceVersion)
+		if err != nil {
+			return nil, nil, addSourceToErr("Failed to insert resourceVersion in patch", source, err)
+		}
+	}
+
+	options := metav1.UpdateOptions{}
+	if p.ServerDryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+
+	patchedObj, err := p.patch(namespace, name, patchType, patch, &options)
+	return patch, patchedObj, err
+}
+
+// patch sends the patch to the API server, as resource.Helper does, along with
+// the field manager if set
+func (p *Patcher) patch(namespace, name string, pt types.PatchType, data []byte, options *metav1.UpdateOptions) (runtime.Object, error) {
+	req := p.Helper.RESTClient.Patch(pt).
+		NamespaceIfScoped(namespace, p.Helper.NamespaceScoped).
+		Resource(p.Helper.Resource).
+		Name(name).
+		VersionedParams(options, metav1.ParameterCodec)
+	if p.FieldManager != "" {
+		req = req.Param(fieldManagerParam, p.FieldManager)
+	}
+	return req.Body(data).Do().Get()
+}
+
+// create creates the object, as resource.Helper does, along with the field
+// manager if set
+func (p *Patcher) create(namespace string, obj runtime.Object, options *metav1.CreateOptions) (runtime.Object, error) {
+	// An object that has been read from the API server cannot be created with
+	// its resourceVersion
+	if p.Helper.Versioner != nil {
+		if version, err := p.Helper.Versioner.ResourceVersion(obj); err == nil && version != "" {
+			if err := p.Helper.Versioner.SetResourceVersion(obj, ""); err != nil {
+				return nil, err
+			}
+		}
+	}
+	req := p.Helper.RESTClient.Post().
+		NamespaceIfScoped(namespace, p.Helper.NamespaceScoped).
+		Resource(p.Helper.Resource).
+		VersionedParams(options, metav1.ParameterCodec)
+	if p.FieldManager != "" {
+		req = req.Param(fieldManagerParam, p.FieldManager)
+	}
+	return req.Body(obj).Do().Get()
+}
+
+// annotation returns the annotation the last applied configuration is stored
+// in
+func (p *Patcher) annotation() string {
+	if p.Annotation == "" {
+		return LastAppliedAnnotation
+	}
+	return p.Annotation
+}
+
+// hashedConfiguration returns the original configuration whose hash is held
+// in the annotation: the last applied configuration if it has the hash, or
+// the modified configuration if it has not changed. Otherwise the original
+// configuration is unknown, and fields removed from the configuration since
+// it was last applied are left in place rather than deleted.
+func (p *Patcher) hashedConfiguration(hash string, modified []byte) []byte {
+	if p.LastApplied != nil && configurationHash(p.LastApplied) == hash {
+		return p.LastApplied
+	}
+
+	// The modified configuration holds its own hash in the annotation
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(modified); err == nil && u.GetAnnotations()[p.annotation()] == hash {
+		return modified
+	}
+	return nil
+}
+
+// createPatch computes the three way merge patch between the original
+// configuration stored on obj, the modified configuration and the current
+// state of obj without sending it to the API server
+func (p *Patcher) createPatch(obj runtime.Object, modified []byte, source string, errOut io.Writer) (types.PatchType, []byte, error) {
 	// Serialize the current configuration of the object from the server.
 	current, err := runtime.Encode(unstructured.UnstructuredJSONScheme, obj)
 	if err != nil {
-		return nil, nil, addSourceToErr(fmt.Sprintf("serializing current configuration from:\n%v\nfor:", obj), source, err)
+		return "", nil, addSourceToErr(fmt.Sprintf("serializing current configuration from:\n%v\nfor:", obj), source, err)
 	}
 
 	// Retrieve the original configuration of the object from the annotation.
-	original, err := getOriginalConfiguration(obj)
+	original, err := getOriginalConfiguration(obj, p.annotation())
 	if err != nil {
-		return nil, nil, addSourceToErr(fmt.Sprintf("retrieving original configuration from:\n%v\nfor:", obj), source, err)
+		return "", nil, addSourceToErr(fmt.Sprintf("retrieving original configuration from:\n%v\nfor:", obj), source, err)
+	}
+	if isConfigurationHash(original) {
+		original = p.hashedConfiguration(string(original), modified)
+	}
+
+	// Remove ignored paths from both the original and modified configuration
+	// so that the current value of those fields is always preserved
+	if len(p.IgnorePaths) > 0 {
+		original, err = removePaths(original, p.IgnorePaths)
+		if err != nil {
+			return "", nil, addSourceToErr("removing ignored paths from original configuration for:", source, err)
+		}
+		modified, err = removePaths(modified, p.IgnorePaths)
+		if err != nil {
+			return "", nil, addSourceToErr("removing ignored paths from modified configuration for:", source, err)
+		}
 	}
 
 	var patchType types.PatchType
@@ -117,17 +249,23 @@ func (p *Patcher) patchSimple(obj runtime.Object, modified []byte, source, names
 	case runtime.IsNotRegisteredError(err):
 		// fall back to generic JSON merge patch
 		patchType = types.MergePatchType
+		if p.MergeLists {
+			modified, err = mergeLists(original, modified, current)
+			if err != nil {
+				return "", nil, addSourceToErr("merging lists for:", source, err)
+			}
+		}
 		preconditions := []mergepatch.PreconditionFunc{mergepatch.RequireKeyUnchanged("apiVersion"),
 			mergepatch.RequireKeyUnchanged("kind"), mergepatch.RequireMetadataKeyUnchanged("name")}
 		patch, err = jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current, preconditions...)
 		if err != nil {
 			if mergepatch.IsPreconditionFailed(err) {
-				return nil, nil, fmt.Errorf("%s", "At least one of apiVersion, kind and name was changed")
+				return "", nil, fmt.Errorf("%s", "At least one of apiVersion, kind and name was changed")
 			}
-			return nil, nil, addSourceToErr(fmt.Sprintf(createPatchErrFormat, original, modified, current), source, err)
+			return "", nil, addSourceToErr(fmt.Sprintf(createPatchErrFormat, original, modified, current), source, err)
 		}
 	case err != nil:
-		return nil, nil, addSourceToErr(fmt.Sprintf("getting instance of versioned object for %v:", p.Mapping.GroupVersionKind), source, err)
+		return "", nil, addSourceToErr(fmt.Sprintf("getting instance of versioned object for %v:", p.Mapping.GroupVersionKind), source, err)
 	default:
 		// Compute a three way strategic merge patch to send to server.
 		patchType = types.StrategicMergePatchType
@@ -149,33 +287,16 @@ func (p *Patcher) patchSimple(obj runtime.Object, modified []byte, source, names
 		if patch == nil {
 			lookupPatchMeta, err = strategicpatch.NewPatchMetaFromStruct(versionedObject)
 			if err != nil {
-				return nil, nil, addSourceToErr(fmt.Sprintf(createPatchErrFormat, original, modified, current), source, err)
+				return "", nil, addSourceToErr(fmt.Sprintf(createPatchErrFormat, original, modified, current), source, err)
 			}
 			patch, err = strategicpatch.CreateThreeWayMergePatch(original, modified, current, lookupPatchMeta, p.Overwrite)
 			if err != nil {
-				return nil, nil, addSourceToErr(fmt.Sprintf(createPatchErrFormat, original, modified, current), source, err)
+				return "", nil, addSourceToErr(fmt.Sprintf(createPatchErrFormat, original, modified, current), source, err)
 			}
 		}
 	}
 
-	if string(patch) == "{}" {
-		return patch, obj, nil
-	}
-
-	if p.ResourceVersion != nil {
-		patch, err = addResourceVersion(patch, *p.ResourceVersion)
-		if err != nil {
-			return nil, nil, addSourceToErr("Failed to insert resourceVersion in patch", source, err)
-		}
-	}
-
-	options := metav1.UpdateOptions{}
-	if p.ServerDryRun {
-		options.DryRun = []string{metav1.DryRunAll}
-	}
-
-	patchedObj, err := p.Helper.Patch(namespace, name, patchType, patch, &options)
-	return patch, patchedObj, err
+	return patchType, patch, nil
 }
 
 // Patch performs a strategic three way merge patch on the given object.
@@ -186,12 +307,9 @@ func (p *Patcher) patchSimple(obj runtime.Object, modified []byte, source, names
 func (p *Patcher) Patch(current runtime.Object, modified []byte, source, namespace, name string, errOut io.Writer) ([]byte, runtime.Object, error) {
 	var getErr error
 	patchBytes, patchObject, err := p.patchSimple(current, modified, source, namespace, name, errOut)
-	if p.Retries == 0 {
-		p.Retries = maxPatchRetry
-	}
-	for i := 1; i <= p.Retries && errors.IsConflict(err); i++ {
+	for i := 1; i <= p.retries() && errors.IsConflict(err); i++ {
 		if i > triesBeforeBackOff {
-			p.BackOff.Sleep(backOffPeriod)
+			p.BackOff.Sleep(conflictBackOff(i))
 		}
 		current, getErr = p.Helper.Get(namespace, name, false)
 		if getErr != nil {
@@ -205,6 +323,28 @@ func (p *Patcher) Patch(current runtime.Object, modified []byte, source, namespa
 	return patchBytes, patchObject, err
 }
 
+// retries returns the number of retries to make if the patch fails with
+// conflict
+func (p *Patcher) retries() int {
+	if p.Retries == nil {
+		return maxPatchRetry
+	}
+	return *p.Retries
+}
+
+// conflictBackOff returns the period to back off before the given retry,
+// doubling with each retry after triesBeforeBackOff up to maxBackOffPeriod
+func conflictBackOff(try int) time.Duration {
+	period := backOffPeriod
+	for i := triesBeforeBackOff + 1; i < try; i++ {
+		period *= 2
+		if period >= maxBackOffPeriod {
+			return maxBackOffPeriod
+		}
+	}
+	return period
+}
+
 func (p *Patcher) deleteAndCreate(original runtime.Object, modified []byte, namespace, name string) ([]byte, runtime.Object, error) {
 	if err := p.delete(namespace, name); err != nil {
 		return modified, nil, err
@@ -226,11 +366,11 @@ func (p *Patcher) deleteAndCreate(original runtime.Object, modified []byte, name
 	if p.ServerDryRun {
 		options.DryRun = []string{metav1.DryRunAll}
 	}
-	createdObject, err := p.Helper.Create(namespace, true, versionedObject, &options)
+	createdObject, err := p.create(namespace, versionedObject, &options)
 	if err != nil {
 		// restore the original object if we fail to create the new one
 		// but still propagate and advertise error to user
-		recreated, recreateErr := p.Helper.Create(namespace, true, original, &options)
+		recreated, recreateErr := p.create(namespace, original, &options)
 		if recreateErr != nil {
 			err = fmt.Errorf("An error occurred force-replacing the existing object with the newly provided one:\n\n%v.\n\nAdditionally, an error occurred attempting to restore the original object:\n\n%v", err, recreateErr)
 		} else {
@@ -0,0 +1,50 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Patcher Suite", func() {
+	Context("retries", func() {
+		It("retries maxPatchRetry times by default", func() {
+			Expect((&Patcher{}).retries()).To(Equal(maxPatchRetry))
+		})
+
+		It("does not retry when no retries are configured", func() {
+			retries := 0
+			Expect((&Patcher{Retries: &retries}).retries()).To(Equal(0))
+		})
+	})
+
+	Context("conflictBackOff", func() {
+		It("backs off for the base period on the first back off", func() {
+			Expect(conflictBackOff(triesBeforeBackOff + 1)).To(Equal(backOffPeriod))
+		})
+
+		It("doubles the back off period on each retry", func() {
+			Expect(conflictBackOff(triesBeforeBackOff + 2)).To(Equal(2 * backOffPeriod))
+			Expect(conflictBackOff(triesBeforeBackOff + 3)).To(Equal(4 * backOffPeriod))
+		})
+
+		It("caps the back off period", func() {
+			Expect(conflictBackOff(100)).To(Equal(maxBackOffPeriod))
+		})
+	})
+})
@@ -32,8 +32,12 @@ limitations under the License.
 package client
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -50,6 +54,18 @@ var metadataAccessor = meta.NewAccessor()
 // applied config
 const LastAppliedAnnotation = "faros.pusher.com/last-applied-configuration"
 
+// fieldManagerParam is the query parameter the field manager is sent in
+const fieldManagerParam = "fieldManager"
+
+// maxAnnotationsSize is the largest total size of the keys and values of the
+// annotations of an object accepted by the API server, as
+// TotalAnnotationSizeLimitB in k8s.io/apimachinery/pkg/api/validation
+const maxAnnotationsSize = 256 * (1 << 10)
+
+// configurationHashPrefix prefixes the hash of a configuration too large to
+// be stored in the last applied annotation, which is stored in its place
+const configurationHashPrefix = "sha256:"
+
 func getNamespacedName(obj runtime.Object) (types.NamespacedName, error) {
 	name, err := metadataAccessor.Name(obj)
 	if err != nil {
@@ -64,17 +80,17 @@ func getNamespacedName(obj runtime.Object) (types.NamespacedName, error) {
 
 // createApplyAnnotation gets the modified configuration of the object,
 // without embedding it again, and then sets it on the object as the annotation.
-func createApplyAnnotation(obj runtime.Object, codec runtime.Encoder) error {
-	modified, err := getModifiedConfiguration(obj, false, codec)
+func createApplyAnnotation(obj runtime.Object, annotation string, codec runtime.Encoder) error {
+	modified, err := getModifiedConfiguration(obj, annotation, false, codec)
 	if err != nil {
 		return err
 	}
-	return setOriginalConfiguration(obj, modified)
+	return setOriginalConfiguration(obj, annotation, modified)
 }
 
 // getOriginalConfiguration retrieves the original configuration of the object
 // from the annotation, or nil if no annotation was found.
-func getOriginalConfiguration(obj runtime.Object) ([]byte, error) {
+func getOriginalConfiguration(obj runtime.Object, annotation string) ([]byte, error) {
 	annots, err := metadataAccessor.Annotations(obj)
 	if err != nil {
 		return nil, err
@@ -84,7 +100,7 @@ func getOriginalConfiguration(obj runtime.Object) ([]byte, error) {
 		return nil, nil
 	}
 
-	original, ok := annots[LastAppliedAnnotation]
+	original, ok := annots[annotation]
 	if !ok {
 		return nil, nil
 	}
@@ -94,7 +110,7 @@ func getOriginalConfiguration(obj runtime.Object) ([]byte, error) {
 
 // setOriginalConfiguration sets the original configuration of the object
 // as the annotation on the object for later use in computing a three way patch.
-func setOriginalConfiguration(obj runtime.Object, original []byte) error {
+func setOriginalConfiguration(obj runtime.Object, annotation string, original []byte) error {
 	if len(original) < 1 {
 		return nil
 	}
@@ -108,15 +124,67 @@ func setOriginalConfiguration(obj runtime.Object, original []byte) error {
 		annots = map[string]string{}
 	}
 
-	annots[LastAppliedAnnotation] = string(original)
+	annots[annotation] = lastAppliedValue(annots, annotation, original)
 	return metadataAccessor.SetAnnotations(obj, annots)
 }
 
+// lastAppliedValue returns the value of the annotation recording the
+// configuration. This is the configuration itself unless it would take the
+// annotations of the object over the size limit, in which case only the hash
+// of the configuration is recorded rather than having the API server reject
+// the object.
+func lastAppliedValue(annots map[string]string, annotation string, config []byte) string {
+	size := len(annotation) + len(config)
+	for k, v := range annots {
+		if k != annotation {
+			size += len(k) + len(v)
+		}
+	}
+	if size <= maxAnnotationsSize {
+		return string(config)
+	}
+	return configurationHash(config)
+}
+
+// configurationHash returns the hash recorded in the annotation in place of
+// the configuration
+func configurationHash(config []byte) string {
+	return fmt.Sprintf("%s%x", configurationHashPrefix, sha256.Sum256(config))
+}
+
+// isConfigurationHash returns whether the annotation holds the hash of the
+// configuration rather than the configuration
+func isConfigurationHash(original []byte) bool {
+	return bytes.HasPrefix(original, []byte(configurationHashPrefix))
+}
+
+// ExceedsAnnotationLimit returns whether the configuration of the object is
+// too large to be recorded in the last applied annotation, in which case
+// only its hash is recorded
+//
+// The configuration is sized for LastAppliedAnnotation, use the
+// ExceedsAnnotationLimit method of an Applier created with another Annotation.
+func ExceedsAnnotationLimit(obj runtime.Object) (bool, error) {
+	return exceedsAnnotationLimit(obj, LastAppliedAnnotation)
+}
+
+func exceedsAnnotationLimit(obj runtime.Object, annotation string) (bool, error) {
+	annots, err := metadataAccessor.Annotations(obj)
+	if err != nil {
+		return false, err
+	}
+	config, err := getModifiedConfiguration(obj.DeepCopyObject(), annotation, false, unstructured.UnstructuredJSONScheme)
+	if err != nil {
+		return false, err
+	}
+	return isConfigurationHash([]byte(lastAppliedValue(annots, annotation, config))), nil
+}
+
 // getModifiedConfiguration retrieves the modified configuration of the object.
 // If annotate is true, it embeds the result as an annotation in the modified
 // configuration. If an object was read from the command input, it will use that
 // version of the object. Otherwise, it will use the version from the server.
-func getModifiedConfiguration(obj runtime.Object, annotate bool, codec runtime.Encoder) ([]byte, error) {
+func getModifiedConfiguration(obj runtime.Object, annotation string, annotate bool, codec runtime.Encoder) ([]byte, error) {
 	// First serialize the object without the annotation to prevent recursion,
 	// then add that serialization to it as the annotation and serialize it again.
 	var modified []byte
@@ -135,7 +203,7 @@ func getModifiedConfiguration(obj runtime.Object, annotate bool, codec runtime.E
 		annots = map[string]string{}
 	}
 
-	delete(annots, LastAppliedAnnotation)
+	delete(annots, annotation)
 	err = metadataAccessor.SetAnnotations(obj, annots)
 	if err != nil {
 		return nil, err
@@ -153,7 +221,7 @@ func getModifiedConfiguration(obj runtime.Object, annotate bool, codec runtime.E
 	}
 
 	if annotate {
-		annots[LastAppliedAnnotation] = string(modified)
+		annots[annotation] = lastAppliedValue(annots, annotation, modified)
 		err = metadataAccessor.SetAnnotations(obj, annots)
 		if err != nil {
 			return nil, err
@@ -201,7 +269,7 @@ func addSourceToErr(verb string, source string, err error) error {
 	return err
 }
 
-func runDelete(namespace, name string, mapping *meta.RESTMapping, c dynamic.Interface, cascade bool, gracePeriod int, serverDryRun bool) error {
+func runDelete(namespace, name string, mapping *meta.RESTMapping, c dynamic.Interface, cascade bool, propagationPolicy *metav1.DeletionPropagation, gracePeriod int, serverDryRun bool) error {
 	options := &metav1.DeleteOptions{}
 	if gracePeriod >= 0 {
 		options = metav1.NewDeleteOptions(int64(gracePeriod))
@@ -213,6 +281,9 @@ func runDelete(namespace, name string, mapping *meta.RESTMapping, c dynamic.Inte
 	if !cascade {
 		policy = metav1.DeletePropagationOrphan
 	}
+	if propagationPolicy != nil {
+		policy = *propagationPolicy
+	}
 	options.PropagationPolicy = &policy
 	return c.Resource(mapping.Resource).Namespace(namespace).Delete(name, options)
 }
@@ -232,3 +303,156 @@ func addResourceVersion(patch []byte, rv string) ([]byte, error) {
 
 	return json.Marshal(patchMap)
 }
+
+// removePaths removes the fields referenced by the given JSON pointers from
+// the JSON document. Paths that do not exist within the document are skipped.
+func removePaths(data []byte, paths []string) ([]byte, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+	var doc interface{}
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		doc = removePath(doc, splitJSONPointer(path))
+	}
+	return json.Marshal(doc)
+}
+
+// removePath recursively walks the document and removes the final token of
+// the path from its parent
+func removePath(doc interface{}, tokens []string) interface{} {
+	if len(tokens) == 0 {
+		return doc
+	}
+	switch typed := doc.(type) {
+	case map[string]interface{}:
+		if len(tokens) == 1 {
+			delete(typed, tokens[0])
+			return typed
+		}
+		if child, ok := typed[tokens[0]]; ok {
+			typed[tokens[0]] = removePath(child, tokens[1:])
+		}
+	case []interface{}:
+		index, err := strconv.Atoi(tokens[0])
+		if err != nil || index < 0 || index >= len(typed) {
+			return typed
+		}
+		if len(tokens) == 1 {
+			return append(typed[:index], typed[index+1:]...)
+		}
+		typed[index] = removePath(typed[index], tokens[1:])
+	}
+	return doc
+}
+
+// splitJSONPointer splits a JSON pointer (RFC 6901) into its unescaped tokens
+func splitJSONPointer(path string) []string {
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, token := range tokens {
+		token = strings.Replace(token, "~1", "/", -1)
+		tokens[i] = strings.Replace(token, "~0", "~", -1)
+	}
+	return tokens
+}
+
+// mergeLists adds any list entries that exist in the current configuration,
+// but were neither in the original nor the modified configuration, to the
+// corresponding lists in the modified configuration.
+//
+// This preserves list entries that were added outside of the applier when
+// computing a JSON merge patch, which otherwise replaces lists wholesale.
+func mergeLists(original, modified, current []byte) ([]byte, error) {
+	var originalMap, modifiedMap, currentMap map[string]interface{}
+	for _, doc := range []struct {
+		data []byte
+		into *map[string]interface{}
+	}{{original, &originalMap}, {modified, &modifiedMap}, {current, &currentMap}} {
+		if len(doc.data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(doc.data, doc.into); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(mergeMapLists(originalMap, modifiedMap, currentMap))
+}
+
+// mergeMapLists recursively merges the lists within the modified map
+func mergeMapLists(original, modified, current map[string]interface{}) map[string]interface{} {
+	for key, modifiedValue := range modified {
+		currentValue, ok := current[key]
+		if !ok {
+			continue
+		}
+		originalValue := original[key]
+		switch typed := modifiedValue.(type) {
+		case map[string]interface{}:
+			currentMap, ok := currentValue.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			originalMap, _ := originalValue.(map[string]interface{})
+			modified[key] = mergeMapLists(originalMap, typed, currentMap)
+		case []interface{}:
+			currentList, ok := currentValue.([]interface{})
+			if !ok {
+				continue
+			}
+			originalList, _ := originalValue.([]interface{})
+			modified[key] = mergeList(originalList, typed, currentList)
+		}
+	}
+	return modified
+}
+
+// mergeList appends entries from current that are not in original or
+// modified to modified. Map entries with a `name` field are matched by name,
+// all other entries are matched by value.
+func mergeList(original, modified, current []interface{}) []interface{} {
+	modifiedIndex := make(map[string]int)
+	for i, item := range modified {
+		modifiedIndex[listItemKey(item)] = i
+	}
+	originalKeys := make(map[string]interface{})
+	for _, item := range original {
+		originalKeys[listItemKey(item)] = item
+	}
+
+	for _, item := range current {
+		key := listItemKey(item)
+		if i, ok := modifiedIndex[key]; ok {
+			// Merge nested lists of entries present in both
+			modifiedMap, mOK := modified[i].(map[string]interface{})
+			currentMap, cOK := item.(map[string]interface{})
+			if mOK && cOK {
+				originalMap, _ := originalKeys[key].(map[string]interface{})
+				modified[i] = mergeMapLists(originalMap, modifiedMap, currentMap)
+			}
+			continue
+		}
+		if _, ok := originalKeys[key]; ok {
+			// Entry was removed from the desired state
+			continue
+		}
+		modified = append(modified, item)
+	}
+	return modified
+}
+
+// listItemKey returns a key uniquely identifying a list entry
+func listItemKey(item interface{}) string {
+	if m, ok := item.(map[string]interface{}); ok {
+		if name, ok := m["name"].(string); ok {
+			return "name:" + name
+		}
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Sprintf("%v", item)
+	}
+	return "value:" + string(data)
+}
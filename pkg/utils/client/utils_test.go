@@ -0,0 +1,156 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/pkg/utils/client/test"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var _ = Describe("Utils Suite", func() {
+	Context("MatchesLastApplied", func() {
+		var current, modified *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(test.ExampleDeployment.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			modified = &unstructured.Unstructured{Object: obj}
+			modified.SetAPIVersion("apps/v1")
+			modified.SetKind("Deployment")
+
+			current = modified.DeepCopy()
+			_, err = getModifiedConfiguration(current, LastAppliedAnnotation, true, unstructured.UnstructuredJSONScheme)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("matches when the configuration has not changed", func() {
+			Expect(MatchesLastApplied(current, modified)).To(BeTrue())
+		})
+
+		It("does not match when the configuration has changed", func() {
+			modified.SetLabels(map[string]string{"changed": "true"})
+			Expect(MatchesLastApplied(current, modified)).To(BeFalse())
+		})
+
+		It("does not match when there is no last applied configuration", func() {
+			current.SetAnnotations(nil)
+			Expect(MatchesLastApplied(current, modified)).To(BeFalse())
+		})
+	})
+
+	Context("ExceedsAnnotationLimit", func() {
+		var obj *unstructured.Unstructured
+
+		BeforeEach(func() {
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(test.ExampleDeployment.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			obj = &unstructured.Unstructured{Object: content}
+			obj.SetAPIVersion("apps/v1")
+			obj.SetKind("Deployment")
+			// A configuration previously recorded in the default annotation
+			// that leaves little room for other annotations
+			obj.SetAnnotations(map[string]string{LastAppliedAnnotation: strings.Repeat("x", maxAnnotationsSize-100)})
+		})
+
+		It("replaces the configuration recorded in the default annotation", func() {
+			Expect(ExceedsAnnotationLimit(obj)).To(BeFalse())
+		})
+
+		It("sizes the configuration for the given annotation", func() {
+			Expect(exceedsAnnotationLimit(obj, "example.com/last-applied-configuration")).To(BeTrue())
+		})
+	})
+
+	Context("with a configuration too large for the annotation", func() {
+		var current, modified *unstructured.Unstructured
+
+		BeforeEach(func() {
+			obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(test.ExampleDeployment.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			modified = &unstructured.Unstructured{Object: obj}
+			modified.SetAPIVersion("apps/v1")
+			modified.SetKind("Deployment")
+			modified.SetLabels(map[string]string{"large": "true"})
+			modified.Object["data"] = map[string]interface{}{"large": strings.Repeat("x", maxAnnotationsSize)}
+
+			current = modified.DeepCopy()
+			_, err = getModifiedConfiguration(current, LastAppliedAnnotation, true, unstructured.UnstructuredJSONScheme)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("records the hash of the configuration", func() {
+			Expect(current.GetAnnotations()[LastAppliedAnnotation]).To(HavePrefix(configurationHashPrefix))
+		})
+
+		It("exceeds the annotation limit", func() {
+			Expect(ExceedsAnnotationLimit(modified)).To(BeTrue())
+			Expect(ExceedsAnnotationLimit(test.ExampleDeployment.DeepCopy())).To(BeFalse())
+		})
+
+		It("matches when the configuration has not changed", func() {
+			Expect(MatchesLastApplied(current, modified)).To(BeTrue())
+		})
+
+		It("does not match when the configuration has changed", func() {
+			modified.SetLabels(map[string]string{"large": "false"})
+			Expect(MatchesLastApplied(current, modified)).To(BeFalse())
+		})
+
+		Context("hashedConfiguration", func() {
+			var patcher *Patcher
+			var hash string
+			var lastApplied []byte
+
+			BeforeEach(func() {
+				hash = current.GetAnnotations()[LastAppliedAnnotation]
+				var err error
+				lastApplied, err = getModifiedConfiguration(modified.DeepCopy(), LastAppliedAnnotation, false, unstructured.UnstructuredJSONScheme)
+				Expect(err).NotTo(HaveOccurred())
+				patcher = &Patcher{}
+			})
+
+			It("uses the last applied configuration if it has the hash", func() {
+				patcher.LastApplied = lastApplied
+				changed := modified.DeepCopy()
+				changed.SetLabels(map[string]string{"large": "false"})
+				modifiedJSON, err := getModifiedConfiguration(changed, LastAppliedAnnotation, true, unstructured.UnstructuredJSONScheme)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(patcher.hashedConfiguration(hash, modifiedJSON)).To(Equal(lastApplied))
+			})
+
+			It("uses the modified configuration if it has not changed", func() {
+				modifiedJSON, err := getModifiedConfiguration(modified.DeepCopy(), LastAppliedAnnotation, true, unstructured.UnstructuredJSONScheme)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(patcher.hashedConfiguration(hash, modifiedJSON)).To(Equal(modifiedJSON))
+			})
+
+			It("has no original configuration otherwise", func() {
+				patcher.LastApplied = []byte(`{"kind":"Deployment"}`)
+				changed := modified.DeepCopy()
+				changed.SetLabels(map[string]string{"large": "false"})
+				modifiedJSON, err := getModifiedConfiguration(changed, LastAppliedAnnotation, true, unstructured.UnstructuredJSONScheme)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(patcher.hashedConfiguration(hash, modifiedJSON)).To(BeNil())
+			})
+		})
+	})
+})
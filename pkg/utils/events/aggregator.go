@@ -0,0 +1,154 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Aggregator is an EventRecorder that collapses repeated events with the same
+// type and reason for the same object. Only the first such event within the
+// window is recorded, the next event recorded after the window has passed
+// includes a count of the events suppressed in between.
+type Aggregator struct {
+	record.EventRecorder
+	window  time.Duration
+	reasons map[string]struct{}
+	clock   clockwork.Clock
+	mutex   sync.Mutex
+	entries map[string]*entry
+}
+
+// entry tracks the events recorded for a single object, type and reason
+type entry struct {
+	recorded   time.Time
+	suppressed int
+
+	// last is the most recently suppressed event, recorded when flushing
+	last *suppressedEvent
+}
+
+// suppressedEvent is an event that was not recorded
+type suppressedEvent struct {
+	object    runtime.Object
+	eventtype string
+	reason    string
+	message   string
+}
+
+var _ record.EventRecorder = &Aggregator{}
+
+// NewAggregator wraps the recorder so that events with the given reasons are
+// aggregated within the window. If the window is 0 or no reasons are given,
+// the recorder is returned unchanged.
+func NewAggregator(recorder record.EventRecorder, window time.Duration, reasons []string) record.EventRecorder {
+	if window <= 0 || len(reasons) == 0 {
+		return recorder
+	}
+	return newAggregator(recorder, window, reasons, clockwork.NewRealClock())
+}
+
+func newAggregator(recorder record.EventRecorder, window time.Duration, reasons []string, clock clockwork.Clock) *Aggregator {
+	reasonSet := make(map[string]struct{})
+	for _, reason := range reasons {
+		reasonSet[reason] = struct{}{}
+	}
+	return &Aggregator{
+		EventRecorder: recorder,
+		window:        window,
+		reasons:       reasonSet,
+		clock:         clock,
+		entries:       make(map[string]*entry),
+	}
+}
+
+// Event records the event unless an event with the same type and reason was
+// recorded for the object within the window
+func (a *Aggregator) Event(object runtime.Object, eventtype, reason, message string) {
+	if _, ok := a.reasons[reason]; !ok {
+		a.EventRecorder.Event(object, eventtype, reason, message)
+		return
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		a.EventRecorder.Event(object, eventtype, reason, message)
+		return
+	}
+	key := fmt.Sprintf("%s/%s/%s/%s/%s", accessor.GetUID(), accessor.GetNamespace(), accessor.GetName(), eventtype, reason)
+
+	a.mutex.Lock()
+	now := a.clock.Now()
+	e, ok := a.entries[key]
+	if ok && now.Sub(e.recorded) < a.window {
+		e.suppressed++
+		e.last = &suppressedEvent{object: object, eventtype: eventtype, reason: reason, message: message}
+		a.mutex.Unlock()
+		return
+	}
+	if ok && e.suppressed > 0 {
+		message = fmt.Sprintf("%s (%d similar events since %s)", message, e.suppressed, e.recorded.UTC().Format(time.RFC3339))
+	}
+	a.entries[key] = &entry{recorded: now}
+	a.prune(now)
+	a.mutex.Unlock()
+
+	a.EventRecorder.Event(object, eventtype, reason, message)
+}
+
+// Eventf is like Event but with Sprintf formatting of the message
+func (a *Aggregator) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	a.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// prune forgets entries that can no longer suppress events and have nothing
+// to report, or that have not been seen for a long time. The mutex must be
+// held by the caller.
+func (a *Aggregator) prune(now time.Time) {
+	for key, e := range a.entries {
+		age := now.Sub(e.recorded)
+		if (age >= a.window && e.suppressed == 0) || age >= 10*a.window {
+			delete(a.entries, key)
+		}
+	}
+}
+
+// Flush records the most recent suppressed event for every object, including
+// the number of events suppressed, so that they are not lost when the process
+// exits
+func (a *Aggregator) Flush() {
+	a.mutex.Lock()
+	pending := []*entry{}
+	for key, e := range a.entries {
+		if e.suppressed > 0 {
+			pending = append(pending, e)
+		}
+		delete(a.entries, key)
+	}
+	a.mutex.Unlock()
+
+	for _, e := range pending {
+		message := fmt.Sprintf("%s (%d similar events since %s)", e.last.message, e.suppressed, e.recorded.UTC().Format(time.RFC3339))
+		a.EventRecorder.Event(e.last.object, e.last.eventtype, e.last.reason, message)
+	}
+}
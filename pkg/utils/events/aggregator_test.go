@@ -0,0 +1,119 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEvents(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Events Suite", reporters.Reporters())
+}
+
+var _ = Describe("Aggregator", func() {
+	var recorder *record.FakeRecorder
+	var clock clockwork.FakeClock
+	var aggregator *Aggregator
+	var pod, other *corev1.Pod
+
+	var recorded = func() []string {
+		events := []string{}
+		for {
+			select {
+			case e := <-recorder.Events:
+				events = append(events, e)
+			default:
+				return events
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		clock = clockwork.NewFakeClock()
+		aggregator = newAggregator(recorder, time.Minute, []string{"UpdateSuccessful"}, clock)
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "default", UID: "1"}}
+		other = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", UID: "2"}}
+	})
+
+	It("returns the recorder when disabled", func() {
+		Expect(NewAggregator(recorder, 0, []string{"UpdateSuccessful"})).To(BeIdenticalTo(recorder))
+		Expect(NewAggregator(recorder, time.Minute, []string{})).To(BeIdenticalTo(recorder))
+	})
+
+	It("records events with other reasons", func() {
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "CreateSuccessful", "Created %s", "example")
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "CreateSuccessful", "Created %s", "example")
+		Expect(recorded()).To(HaveLen(2))
+	})
+
+	It("suppresses repeated events within the window", func() {
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated %s", "example")
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated %s", "example")
+		Expect(recorded()).To(Equal([]string{"Normal UpdateSuccessful Updated example"}))
+	})
+
+	It("aggregates events separately for each object", func() {
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated example")
+		aggregator.Eventf(other, corev1.EventTypeNormal, "UpdateSuccessful", "Updated other")
+		Expect(recorded()).To(HaveLen(2))
+	})
+
+	It("includes the number of suppressed events after the window", func() {
+		start := clock.Now().UTC().Format(time.RFC3339)
+		for i := 0; i < 3; i++ {
+			aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated example")
+		}
+		clock.Advance(time.Minute)
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated example")
+		Expect(recorded()).To(Equal([]string{
+			"Normal UpdateSuccessful Updated example",
+			"Normal UpdateSuccessful Updated example (2 similar events since " + start + ")",
+		}))
+	})
+
+	It("records the suppressed events when flushed", func() {
+		start := clock.Now().UTC().Format(time.RFC3339)
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated example")
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated example again")
+		aggregator.Eventf(other, corev1.EventTypeNormal, "UpdateSuccessful", "Updated other")
+		Expect(recorded()).To(HaveLen(2))
+
+		aggregator.Flush()
+		Expect(recorded()).To(Equal([]string{
+			"Normal UpdateSuccessful Updated example again (1 similar events since " + start + ")",
+		}))
+		Expect(aggregator.entries).To(BeEmpty())
+	})
+
+	It("forgets entries once the window has passed", func() {
+		aggregator.Eventf(pod, corev1.EventTypeNormal, "UpdateSuccessful", "Updated example")
+		clock.Advance(time.Minute)
+		aggregator.Eventf(other, corev1.EventTypeNormal, "UpdateSuccessful", "Updated other")
+		Expect(aggregator.entries).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,50 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pusher/faros/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("HasFinalizer", func() {
+	var obj *metav1.ObjectMeta
+
+	BeforeEach(func() {
+		obj = &metav1.ObjectMeta{Finalizers: []string{"foo", "bar"}}
+	})
+
+	It("returns true if the object has the finalizer", func() {
+		Expect(HasFinalizer(obj, "bar")).To(BeTrue())
+	})
+
+	It("returns false if the object does not have the finalizer", func() {
+		Expect(HasFinalizer(obj, "baz")).To(BeFalse())
+	})
+})
+
+var _ = Describe("RemoveFinalizer", func() {
+	It("removes every occurrence of the finalizer", func() {
+		Expect(RemoveFinalizer([]string{"foo", "bar", "foo"}, "foo")).To(Equal([]string{"bar"}))
+	})
+
+	It("returns an empty list if no finalizers remain", func() {
+		Expect(RemoveFinalizer([]string{"foo"}, "foo")).To(Equal([]string{}))
+	})
+})
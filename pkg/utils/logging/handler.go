@@ -0,0 +1,91 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// levelsBody is the JSON representation of the Levels served by Handler
+type levelsBody struct {
+	Level            string            `json:"level"`
+	ControllerLevels map[string]string `json:"controllerLevels"`
+}
+
+// Handler serves the Levels as JSON and changes them when sent a PUT request
+// with the same JSON body. An omitted level or controllerLevels is left as it
+// is, an empty controllerLevels object removes every override.
+//
+// Every request must carry the token as a bearer token in its Authorization
+// header, all requests are refused if the token is empty.
+func Handler(levels *Levels, token string, log logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !authorized(req, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			level, controllerLevels := levels.Get()
+			body := &levelsBody{}
+			if err := json.NewDecoder(req.Body).Decode(body); err != nil {
+				http.Error(w, fmt.Sprintf("invalid body: %v", err), http.StatusBadRequest)
+				return
+			}
+			if body.Level != "" {
+				level = body.Level
+			}
+			if body.ControllerLevels != nil {
+				controllerLevels = body.ControllerLevels
+			}
+			if err := levels.Set(level, controllerLevels); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			log.V(0).Info("Log levels changed", "level", level, "controllerLevels", controllerLevels, "remoteAddr", req.RemoteAddr)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		level, controllerLevels := levels.Get()
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(&levelsBody{Level: level, ControllerLevels: controllerLevels}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// authorized returns whether the request carries the token as a bearer token
+func authorized(req *http.Request, token string) bool {
+	header := req.Header.Get("Authorization")
+	if token == "" || !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+	given := strings.TrimPrefix(header, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// JSONEncoding writes one JSON object per log line
+	JSONEncoding = "json"
+
+	// ConsoleEncoding writes human readable, tab separated log lines
+	ConsoleEncoding = "console"
+)
+
+// Options configures the logger built by New
+type Options struct {
+	// Level is the default log level, either a zap level name (debug, info,
+	// warn, error) or a logr verbosity such as 2
+	Level string
+
+	// Encoding is the log line encoding, either json or console
+	Encoding string
+
+	// ControllerLevels overrides Level for the named controllers, keyed by
+	// controller name (eg gittrack) or full logger name (eg applier)
+	ControllerLevels map[string]string
+}
+
+// New builds a zap backed logr.Logger writing to out according to opts
+func New(out io.Writer, opts Options) (logr.Logger, error) {
+	logger, _, err := NewReloadable(out, opts)
+	return logger, err
+}
+
+// NewReloadable is like New but also returns the Levels of the logger so that
+// they can be changed while it is in use
+func NewReloadable(out io.Writer, opts Options) (logr.Logger, *Levels, error) {
+	levels := &Levels{}
+	if err := levels.Set(opts.Level, opts.ControllerLevels); err != nil {
+		return nil, nil, err
+	}
+
+	encoder, err := newEncoder(opts.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The inner core must let through anything any logger could want, the
+	// per name filtering is then performed by the levelCore
+	core := &levelCore{
+		Core:   zapcore.NewCore(encoder, zapcore.AddSync(out), zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })),
+		levels: levels,
+	}
+	return zapr.NewLogger(zap.New(core, zap.ErrorOutput(zapcore.AddSync(out)))), levels, nil
+}
+
+// Levels are the log levels of a logger built by NewReloadable
+type Levels struct {
+	mutex     sync.RWMutex
+	level     zapcore.Level
+	overrides map[string]zapcore.Level
+	min       zapcore.Level
+
+	// value and controllerLevels are the levels as they were given to Set
+	value            string
+	controllerLevels map[string]string
+}
+
+// Set replaces the default log level and the per controller overrides, in
+// the same format as Options.Level and Options.ControllerLevels
+func (l *Levels) Set(value string, controllerLevels map[string]string) error {
+	level, err := ParseLevel(value)
+	if err != nil {
+		return fmt.Errorf("invalid log level: %v", err)
+	}
+
+	overrides := make(map[string]zapcore.Level)
+	for name, value := range controllerLevels {
+		o, err := ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("invalid log level for %s: %v", name, err)
+		}
+		overrides[name] = o
+	}
+
+	min := level
+	for _, o := range overrides {
+		if o < min {
+			min = o
+		}
+	}
+
+	values := make(map[string]string)
+	for name, value := range controllerLevels {
+		values[name] = value
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.level = level
+	l.overrides = overrides
+	l.min = min
+	l.value = value
+	l.controllerLevels = values
+	return nil
+}
+
+// Get returns the default log level and the per controller overrides as they
+// were last given to Set
+func (l *Levels) Get() (string, map[string]string) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	controllerLevels := make(map[string]string)
+	for name, value := range l.controllerLevels {
+		controllerLevels[name] = value
+	}
+	return l.value, controllerLevels
+}
+
+// ParseControllerLevels parses a list of <controller>=<level> pairs into a
+// map suitable for Options.ControllerLevels
+func ParseControllerLevels(values []string) (map[string]string, error) {
+	levels := make(map[string]string)
+	for _, value := range values {
+		split := strings.SplitN(value, "=", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, fmt.Errorf("%s is invalid, should be of format <controller>=<level>", value)
+		}
+		levels[split[0]] = split[1]
+	}
+	return levels, nil
+}
+
+// ParseLevel parses a zap level name or a logr verbosity into a zap level.
+// logr verbosity n is equivalent to zap level -n, so debug enables V(1).
+func ParseLevel(value string) (zapcore.Level, error) {
+	if value == "" {
+		return zapcore.InfoLevel, nil
+	}
+	if v, err := strconv.Atoi(value); err == nil {
+		if v < 0 {
+			return 0, fmt.Errorf("verbosity %d must not be negative", v)
+		}
+		return zapcore.Level(-v), nil
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(strings.ToLower(value))); err != nil {
+		return 0, err
+	}
+	return level, nil
+}
+
+// newEncoder returns the zap encoder for the given encoding name
+func newEncoder(encoding string) (zapcore.Encoder, error) {
+	config := zap.NewProductionEncoderConfig()
+	config.EncodeTime = zapcore.ISO8601TimeEncoder
+	switch encoding {
+	case "", JSONEncoding:
+		return zapcore.NewJSONEncoder(config), nil
+	case ConsoleEncoding:
+		config.EncodeLevel = zapcore.CapitalLevelEncoder
+		return zapcore.NewConsoleEncoder(config), nil
+	default:
+		return nil, fmt.Errorf("unknown log encoding %q, should be one of %s or %s", encoding, JSONEncoding, ConsoleEncoding)
+	}
+}
+
+// levelCore filters entries using a level chosen by the name of the logger
+// that wrote them
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+// Enabled reports whether any logger could write at the given level, the
+// final decision is deferred to Check where the logger name is known
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	c.levels.mutex.RLock()
+	defer c.levels.mutex.RUnlock()
+	return c.levels.min.Enabled(l)
+}
+
+// With adds structured context to the core
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{
+		Core:   c.Core.With(fields),
+		levels: c.levels,
+	}
+}
+
+// Check adds the inner core to the checked entry if the entry's level is
+// enabled for the logger that wrote it
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levelFor(ent.LoggerName).Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// levelFor returns the level for the named logger. Loggers are matched on the
+// first segment of their name, so gittrack matches gittrack-controller and
+// gittrack-controller/enqueue-request-for-owner.
+func (c *levelCore) levelFor(loggerName string) zapcore.Level {
+	c.levels.mutex.RLock()
+	defer c.levels.mutex.RUnlock()
+	root := strings.FieldsFunc(loggerName, func(r rune) bool {
+		return r == '.' || r == '/'
+	})
+	if len(root) == 0 {
+		return c.levels.level
+	}
+	if l, ok := c.levels.overrides[root[0]]; ok {
+		return l
+	}
+	if l, ok := c.levels.overrides[strings.TrimSuffix(root[0], "-controller")]; ok {
+		return l
+	}
+	return c.levels.level
+}
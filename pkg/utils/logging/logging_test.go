@@ -0,0 +1,252 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pusher/faros/test/reporters"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogging(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Logging Suite", reporters.Reporters())
+}
+
+var _ = Describe("Logging Suite", func() {
+	var out *bytes.Buffer
+	var opts Options
+
+	lines := func() []map[string]interface{} {
+		entries := []map[string]interface{}{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			entry := make(map[string]interface{})
+			Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		opts = Options{}
+	})
+
+	Context("ParseLevel", func() {
+		It("defaults to info", func() {
+			Expect(ParseLevel("")).To(Equal(zapcore.InfoLevel))
+		})
+
+		It("parses zap level names", func() {
+			Expect(ParseLevel("debug")).To(Equal(zapcore.DebugLevel))
+			Expect(ParseLevel("WARN")).To(Equal(zapcore.WarnLevel))
+		})
+
+		It("parses logr verbosities", func() {
+			Expect(ParseLevel("3")).To(Equal(zapcore.Level(-3)))
+		})
+
+		It("rejects unknown levels", func() {
+			_, err := ParseLevel("loud")
+			Expect(err).To(HaveOccurred())
+			_, err = ParseLevel("-1")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ParseControllerLevels", func() {
+		It("parses controller level pairs", func() {
+			levels, err := ParseControllerLevels([]string{"gittrack=debug", "applier=2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(levels).To(Equal(map[string]string{"gittrack": "debug", "applier": "2"}))
+		})
+
+		It("rejects malformed pairs", func() {
+			for _, value := range []string{"gittrack", "=debug", "gittrack="} {
+				_, err := ParseControllerLevels([]string{value})
+				Expect(err).To(HaveOccurred())
+			}
+		})
+	})
+
+	Context("New", func() {
+		It("rejects unknown encodings", func() {
+			opts.Encoding = "xml"
+			_, err := New(out, opts)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects invalid controller levels", func() {
+			opts.ControllerLevels = map[string]string{"gittrack": "loud"}
+			_, err := New(out, opts)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("writes structured key values", func() {
+			log, err := New(out, opts)
+			Expect(err).NotTo(HaveOccurred())
+			log.WithName("gittrack-controller").WithValues("name", "example").Info("Reconcile started")
+
+			entries := lines()
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0]).To(HaveKeyWithValue("msg", "Reconcile started"))
+			Expect(entries[0]).To(HaveKeyWithValue("logger", "gittrack-controller"))
+			Expect(entries[0]).To(HaveKeyWithValue("name", "example"))
+		})
+
+		It("drops messages below the default level", func() {
+			log, err := New(out, opts)
+			Expect(err).NotTo(HaveOccurred())
+			log.WithName("gittrack-controller").V(1).Info("hidden")
+			Expect(lines()).To(BeEmpty())
+		})
+
+		It("applies controller level overrides", func() {
+			opts.ControllerLevels = map[string]string{"gittrack": "debug"}
+			log, err := New(out, opts)
+			Expect(err).NotTo(HaveOccurred())
+			log.WithName("gittrack-controller").V(1).Info("shown")
+			log.WithName("gittrack-controller").WithName("enqueue").V(1).Info("shown")
+			log.WithName("gittrackobject-controller").V(1).Info("hidden")
+			log.WithName("applier").V(1).Info("hidden")
+
+			entries := lines()
+			Expect(entries).To(HaveLen(2))
+			for _, entry := range entries {
+				Expect(entry).To(HaveKeyWithValue("msg", "shown"))
+			}
+		})
+
+		It("lets a controller override raise its level", func() {
+			opts.Level = "debug"
+			opts.ControllerLevels = map[string]string{"applier": "error"}
+			log, err := New(out, opts)
+			Expect(err).NotTo(HaveOccurred())
+			log.WithName("applier").Info("hidden")
+			log.WithName("gittrack-controller").V(1).Info("shown")
+
+			entries := lines()
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0]).To(HaveKeyWithValue("msg", "shown"))
+		})
+	})
+
+	Context("NewReloadable", func() {
+		It("changes the levels of loggers already in use", func() {
+			log, levels, err := NewReloadable(out, opts)
+			Expect(err).NotTo(HaveOccurred())
+			log = log.WithName("gittrack-controller")
+			log.V(1).Info("hidden")
+
+			Expect(levels.Set("info", map[string]string{"gittrack": "debug"})).To(Succeed())
+			log.V(1).Info("shown")
+
+			Expect(levels.Set("info", nil)).To(Succeed())
+			log.V(1).Info("hidden")
+
+			entries := lines()
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0]).To(HaveKeyWithValue("msg", "shown"))
+		})
+
+		It("keeps the previous levels when given invalid levels", func() {
+			log, levels, err := NewReloadable(out, opts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(levels.Set("loud", nil)).NotTo(Succeed())
+			log.Info("shown")
+			Expect(lines()).To(HaveLen(1))
+		})
+	})
+
+	Context("Handler", func() {
+		var log logr.Logger
+		var levels *Levels
+		var handler http.Handler
+
+		request := func(method, token, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, "/log-level", strings.NewReader(body))
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+
+		BeforeEach(func() {
+			var err error
+			log, levels, err = NewReloadable(out, Options{Level: "info", ControllerLevels: map[string]string{"orphan": "warn"}})
+			Expect(err).NotTo(HaveOccurred())
+			handler = Handler(levels, "secret", log)
+		})
+
+		It("refuses requests without the token", func() {
+			Expect(request(http.MethodGet, "", "").Code).To(Equal(http.StatusUnauthorized))
+			Expect(request(http.MethodGet, "wrong", "").Code).To(Equal(http.StatusUnauthorized))
+			Expect(request(http.MethodPut, "wrong", `{"level":"debug"}`).Code).To(Equal(http.StatusUnauthorized))
+			level, _ := levels.Get()
+			Expect(level).To(Equal("info"))
+		})
+
+		It("refuses every request when the token is empty", func() {
+			handler = Handler(levels, "", log)
+			Expect(request(http.MethodGet, "", "").Code).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("serves the current levels", func() {
+			rec := request(http.MethodGet, "secret", "")
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{"level":"info","controllerLevels":{"orphan":"warn"}}`))
+		})
+
+		It("changes the default level and keeps the overrides", func() {
+			rec := request(http.MethodPut, "secret", `{"level":"debug"}`)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{"level":"debug","controllerLevels":{"orphan":"warn"}}`))
+		})
+
+		It("replaces the overrides", func() {
+			rec := request(http.MethodPut, "secret", `{"controllerLevels":{"gittrack":"2"}}`)
+			Expect(rec.Code).To(Equal(http.StatusOK))
+			Expect(rec.Body.String()).To(MatchJSON(`{"level":"info","controllerLevels":{"gittrack":"2"}}`))
+		})
+
+		It("keeps the levels when given invalid levels", func() {
+			Expect(request(http.MethodPut, "secret", `{"level":"loud"}`).Code).To(Equal(http.StatusBadRequest))
+			Expect(request(http.MethodPut, "secret", `not json`).Code).To(Equal(http.StatusBadRequest))
+			level, controllerLevels := levels.Get()
+			Expect(level).To(Equal("info"))
+			Expect(controllerLevels).To(Equal(map[string]string{"orphan": "warn"}))
+		})
+
+		It("refuses other methods", func() {
+			Expect(request(http.MethodDelete, "secret", "").Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
@@ -21,6 +21,7 @@ import (
 
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
@@ -29,10 +30,45 @@ const (
 	farosGroupVersion = "faros.pusher.com/v1alpha1"
 )
 
+// LabelSelectorPredicate filters events to those whose object matches a
+// label selector
+type LabelSelectorPredicate struct {
+	selector labels.Selector
+}
+
+// Create returns true if the event object matches the selector
+func (p LabelSelectorPredicate) Create(e event.CreateEvent) bool {
+	return p.selector.Matches(labels.Set(e.Meta.GetLabels()))
+}
+
+// Update returns true if the new event object matches the selector
+func (p LabelSelectorPredicate) Update(e event.UpdateEvent) bool {
+	return p.selector.Matches(labels.Set(e.MetaNew.GetLabels()))
+}
+
+// Delete returns true if the event object matches the selector
+func (p LabelSelectorPredicate) Delete(e event.DeleteEvent) bool {
+	return p.selector.Matches(labels.Set(e.Meta.GetLabels()))
+}
+
+// Generic returns true if the event object matches the selector
+func (p LabelSelectorPredicate) Generic(e event.GenericEvent) bool {
+	return p.selector.Matches(labels.Set(e.Meta.GetLabels()))
+}
+
+// NewLabelSelectorPredicate constructs a new LabelSelectorPredicate
+func NewLabelSelectorPredicate(selector labels.Selector) LabelSelectorPredicate {
+	return LabelSelectorPredicate{
+		selector: selector,
+	}
+}
+
 // OwnerInNamespacePredicate filters events to check the owner of the event
-// object is in the controller's namespace
+// object is in the controller's namespace and matches the controller's
+// GitTrack selector
 type OwnerInNamespacePredicate struct {
-	client client.Client
+	client   client.Client
+	selector labels.Selector
 }
 
 // Create returns true if the event object's owner is in the same namespace
@@ -56,7 +92,7 @@ func (p OwnerInNamespacePredicate) Generic(e event.GenericEvent) bool {
 }
 
 // ownerInNamespace returns true if the the GitTrack owner is in the namespace
-// managed by the controller
+// managed by the controller and matches its GitTrack selector
 //
 // This works on the premise that listing objects from the client will only
 // return those in its cache.
@@ -73,7 +109,7 @@ func (p OwnerInNamespacePredicate) ownerInNamespace(ownerRefs []metav1.OwnerRefe
 		if ref.Kind == "GitTrack" && ref.APIVersion == farosGroupVersion {
 			for _, gt := range gtList.Items {
 				if ref.UID == gt.UID {
-					return true
+					return p.selector.Matches(labels.Set(gt.GetLabels()))
 				}
 			}
 		}
@@ -82,9 +118,10 @@ func (p OwnerInNamespacePredicate) ownerInNamespace(ownerRefs []metav1.OwnerRefe
 }
 
 // NewOwnerInNamespacePredicate constructs a new OwnerInNamespacePredicate
-func NewOwnerInNamespacePredicate(client client.Client) OwnerInNamespacePredicate {
+func NewOwnerInNamespacePredicate(client client.Client, selector labels.Selector) OwnerInNamespacePredicate {
 	return OwnerInNamespacePredicate{
-		client: client,
+		client:   client,
+		selector: selector,
 	}
 }
 
@@ -156,9 +193,9 @@ func (p OwnersOwnerInNamespacePredicate) ownersOwnerInNamespace(ownerRefs []meta
 }
 
 // NewOwnersOwnerInNamespacePredicate constructs a new OwnersOwnerInNamespacePredicate
-func NewOwnersOwnerInNamespacePredicate(client client.Client) OwnersOwnerInNamespacePredicate {
+func NewOwnersOwnerInNamespacePredicate(client client.Client, selector labels.Selector) OwnersOwnerInNamespacePredicate {
 	return OwnersOwnerInNamespacePredicate{
 		client:                    client,
-		ownerInNamespacePredicate: NewOwnerInNamespacePredicate(client),
+		ownerInNamespacePredicate: NewOwnerInNamespacePredicate(client, selector),
 	}
 }
@@ -18,34 +18,143 @@ package utils
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
-	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 )
 
-// NewRestMapper creates a restMapper from the discovery client
+// minRefreshInterval limits how often the API resources are rediscovered when
+// a kind or resource can't be mapped
+const minRefreshInterval = 5 * time.Second
+
+// NewRestMapper creates a restMapper from the discovery client.
+//
+// The restMapper rediscovers the API resources when asked to map an unknown
+// kind or resource so that APIs installed after it was created (eg. CRDs
+// installed after Faros started) can be mapped without a restart.
 func NewRestMapper(config *rest.Config) (meta.RESTMapper, error) {
 	client, err := discovery.NewDiscoveryClientForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create dynamic client: %v", err)
 	}
 
-	apiGroupResources, err := restmapper.GetAPIGroupResources(client)
+	drm := &dynamicRESTMapper{client: client}
+	err = drm.refresh()
+	if err != nil {
+		return nil, err
+	}
+	return drm, nil
+}
+
+// dynamicRESTMapper is a RESTMapper which reloads the discovery information
+// when it fails to find a match
+type dynamicRESTMapper struct {
+	client      discovery.DiscoveryInterface
+	mutex       sync.RWMutex
+	delegate    meta.RESTMapper
+	lastRefresh time.Time
+}
+
+// refresh rebuilds the delegate RESTMapper from the discovery client
+func (m *dynamicRESTMapper) refresh() error {
+	apiGroupResources, err := restmapper.GetAPIGroupResources(m.client)
 	if err != nil {
-		return nil, fmt.Errorf("unable to fetch API Group Resources: %v", err)
+		return fmt.Errorf("unable to fetch API Group Resources: %v", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.delegate = restmapper.NewDiscoveryRESTMapper(apiGroupResources)
+	m.lastRefresh = time.Now()
+	return nil
+}
+
+// shouldRetry refreshes the delegate if err is a no match error and the
+// delegate hasn't been refreshed recently, it returns true if the lookup
+// should be retried
+func (m *dynamicRESTMapper) shouldRetry(err error) bool {
+	if !meta.IsNoMatchError(err) {
+		return false
+	}
+
+	m.mutex.RLock()
+	recent := time.Since(m.lastRefresh) < minRefreshInterval
+	m.mutex.RUnlock()
+	if recent {
+		return false
+	}
+	return m.refresh() == nil
+}
+
+// getDelegate returns the current delegate RESTMapper
+func (m *dynamicRESTMapper) getDelegate() meta.RESTMapper {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.delegate
+}
+
+// KindFor implements meta.RESTMapper
+func (m *dynamicRESTMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	gvk, err := m.getDelegate().KindFor(resource)
+	if m.shouldRetry(err) {
+		gvk, err = m.getDelegate().KindFor(resource)
+	}
+	return gvk, err
+}
+
+// KindsFor implements meta.RESTMapper
+func (m *dynamicRESTMapper) KindsFor(resource schema.GroupVersionResource) ([]schema.GroupVersionKind, error) {
+	gvks, err := m.getDelegate().KindsFor(resource)
+	if m.shouldRetry(err) {
+		gvks, err = m.getDelegate().KindsFor(resource)
+	}
+	return gvks, err
+}
+
+// ResourceFor implements meta.RESTMapper
+func (m *dynamicRESTMapper) ResourceFor(input schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	gvr, err := m.getDelegate().ResourceFor(input)
+	if m.shouldRetry(err) {
+		gvr, err = m.getDelegate().ResourceFor(input)
 	}
+	return gvr, err
+}
+
+// ResourcesFor implements meta.RESTMapper
+func (m *dynamicRESTMapper) ResourcesFor(input schema.GroupVersionResource) ([]schema.GroupVersionResource, error) {
+	gvrs, err := m.getDelegate().ResourcesFor(input)
+	if m.shouldRetry(err) {
+		gvrs, err = m.getDelegate().ResourcesFor(input)
+	}
+	return gvrs, err
+}
 
-	drm := restmapper.NewDiscoveryRESTMapper(apiGroupResources)
+// RESTMapping implements meta.RESTMapper
+func (m *dynamicRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (*meta.RESTMapping, error) {
+	mapping, err := m.getDelegate().RESTMapping(gk, versions...)
+	if m.shouldRetry(err) {
+		mapping, err = m.getDelegate().RESTMapping(gk, versions...)
+	}
+	return mapping, err
+}
 
-	lrm := meta.NewLazyRESTMapperLoader(func() (meta.RESTMapper, error) {
-		return apiutil.NewDiscoveryRESTMapper(config)
-	})
+// RESTMappings implements meta.RESTMapper
+func (m *dynamicRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) ([]*meta.RESTMapping, error) {
+	mappings, err := m.getDelegate().RESTMappings(gk, versions...)
+	if m.shouldRetry(err) {
+		mappings, err = m.getDelegate().RESTMappings(gk, versions...)
+	}
+	return mappings, err
+}
 
-	return meta.FirstHitRESTMapper{MultiRESTMapper: meta.MultiRESTMapper{drm, lrm}}, nil
+// ResourceSingularizer implements meta.RESTMapper
+func (m *dynamicRESTMapper) ResourceSingularizer(resource string) (string, error) {
+	return m.getDelegate().ResourceSingularizer(resource)
 }
 
 // GetAPIResource uses a rest mapper to get the GroupVersionResource and
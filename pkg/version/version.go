@@ -0,0 +1,85 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// These are set at build time using -ldflags "-X"
+var (
+	// Version is the released version of Faros
+	Version = "undefined"
+
+	// GitCommit is the SHA of the commit Faros was built from
+	GitCommit = "undefined"
+
+	// BuildDate is the time Faros was built, in RFC3339 format
+	BuildDate = "undefined"
+)
+
+// BuildInfo is a prometheus gauge, always set to 1, labelled with the build
+// information of the running binary
+var BuildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "faros_build_info",
+	Help: "Shows the version, git SHA and build date of the running Faros",
+}, []string{"version", "git_sha", "build_date", "go_version"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(BuildInfo)
+	info := Get()
+	BuildInfo.WithLabelValues(info.Version, info.GitCommit, info.BuildDate, info.GoVersion).Set(1)
+}
+
+// Info describes the build of the running binary
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build information of the running binary
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// String formats the build information on a single line
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s with %s)", i.Version, i.GitCommit, i.BuildDate, i.GoVersion)
+}
+
+// Handler serves the build information as JSON
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Get()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
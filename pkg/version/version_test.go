@@ -0,0 +1,59 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestVersion(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Version Suite", reporters.Reporters())
+}
+
+var _ = Describe("Version Suite", func() {
+	It("serves the build information as JSON", func() {
+		recorder := httptest.NewRecorder()
+		Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/version", nil))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		info := Info{}
+		Expect(json.NewDecoder(recorder.Body).Decode(&info)).To(Succeed())
+		Expect(info).To(Equal(Get()))
+		Expect(info.GoVersion).To(Equal(runtime.Version()))
+	})
+
+	It("sets the build info metric", func() {
+		info := Get()
+		gauge, err := BuildInfo.GetMetricWithLabelValues(info.Version, info.GitCommit, info.BuildDate, info.GoVersion)
+		Expect(err).NotTo(HaveOccurred())
+
+		var metric dto.Metric
+		Expect(gauge.Write(&metric)).To(Succeed())
+		Expect(metric.GetGauge().GetValue()).To(Equal(1.0))
+	})
+})
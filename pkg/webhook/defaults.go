@@ -0,0 +1,80 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"github.com/pusher/faros/pkg/utils"
+)
+
+// documentSeparator separates the documents of (Cluster)GitTrackObjects
+// holding more than one child
+const documentSeparator = "\n---\n"
+
+// DefaultGitTrack sets the default values of the GitTrack's spec
+func DefaultGitTrack(gt *farosv1alpha1.GitTrack) {
+	if gt.Spec.PrunePolicy == "" {
+		gt.Spec.PrunePolicy = farosv1alpha1.PrunePolicyDelete
+	}
+}
+
+// DefaultGitTrackObject sets the default update strategy of each child held
+// by the (Cluster)GitTrackObject, keeping the encoding of its data. Children
+// that cannot be read are left unchanged for the controller to report.
+func DefaultGitTrackObject(gto farosv1alpha1.GitTrackObjectInterface) error {
+	spec := gto.GetSpec()
+	// Data stored in ConfigMaps is defaulted when it is rendered
+	if spec.DataFrom != nil {
+		return nil
+	}
+	data, err := gittrackobjectutils.ChildData(spec)
+	if err != nil {
+		return nil
+	}
+	children, err := utils.YAMLToUnstructuredSlice(data)
+	if err != nil {
+		return nil
+	}
+	defaulted := false
+	for _, child := range children {
+		if gittrackobjectutils.SetDefaultUpdateStrategy(child) {
+			defaulted = true
+		}
+	}
+	if !defaulted {
+		return nil
+	}
+
+	documents := [][]byte{}
+	for _, child := range children {
+		data, err := child.MarshalJSON()
+		if err != nil {
+			return fmt.Errorf("unable to marshal child: %v", err)
+		}
+		documents = append(documents, data)
+	}
+	err = gittrackobjectutils.SetChildData(&spec, bytes.Join(documents, []byte(documentSeparator)))
+	if err != nil {
+		return err
+	}
+	gto.SetSpec(spec)
+	return nil
+}
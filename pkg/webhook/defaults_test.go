@@ -0,0 +1,109 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	gittrackobjectutils "github.com/pusher/faros/pkg/controller/gittrackobject/utils"
+	"github.com/pusher/faros/pkg/utils"
+	"github.com/pusher/faros/test/reporters"
+)
+
+func TestWebhook(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecsWithDefaultAndCustomReporters(t, "Webhook Suite", reporters.Reporters())
+}
+
+var _ = Describe("Defaults Suite", func() {
+	Context("DefaultGitTrack", func() {
+		It("defaults the prune policy to Delete", func() {
+			gt := &farosv1alpha1.GitTrack{}
+			DefaultGitTrack(gt)
+			Expect(gt.Spec.PrunePolicy).To(Equal(farosv1alpha1.PrunePolicyDelete))
+		})
+
+		It("keeps a prune policy that is already set", func() {
+			gt := &farosv1alpha1.GitTrack{}
+			gt.Spec.PrunePolicy = farosv1alpha1.PrunePolicyRetain
+			DefaultGitTrack(gt)
+			Expect(gt.Spec.PrunePolicy).To(Equal(farosv1alpha1.PrunePolicyRetain))
+		})
+	})
+
+	Context("DefaultGitTrackObject", func() {
+		var gto *farosv1alpha1.GitTrackObject
+
+		BeforeEach(func() {
+			gto = &farosv1alpha1.GitTrackObject{}
+		})
+
+		It("sets the default update strategy on the child", func() {
+			gto.Spec.Data = []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"example"}}`)
+			Expect(DefaultGitTrackObject(gto)).To(Succeed())
+
+			child, err := utils.YAMLToUnstructured(gto.Spec.Data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(child.GetAnnotations()).To(HaveKeyWithValue("faros.pusher.com/update-strategy", "update"))
+			Expect(child.GetName()).To(Equal("example"))
+		})
+
+		It("keeps an update strategy that is already set", func() {
+			data := []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"example","annotations":{"faros.pusher.com/update-strategy":"never"}}}`)
+			gto.Spec.Data = data
+			Expect(DefaultGitTrackObject(gto)).To(Succeed())
+			Expect(gto.Spec.Data).To(Equal(data))
+		})
+
+		It("sets the default update strategy on every document", func() {
+			gto.Spec.Data = []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: first\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: second\n  annotations:\n    faros.pusher.com/update-strategy: never\n")
+			Expect(DefaultGitTrackObject(gto)).To(Succeed())
+
+			children, err := utils.YAMLToUnstructuredSlice(gto.Spec.Data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(children).To(HaveLen(2))
+			Expect(children[0].GetName()).To(Equal("first"))
+			Expect(children[0].GetAnnotations()).To(HaveKeyWithValue("faros.pusher.com/update-strategy", "update"))
+			Expect(children[1].GetName()).To(Equal("second"))
+			Expect(children[1].GetAnnotations()).To(HaveKeyWithValue("faros.pusher.com/update-strategy", "never"))
+		})
+
+		It("keeps compressed data compressed", func() {
+			spec := farosv1alpha1.GitTrackObjectSpec{ContentEncoding: farosv1alpha1.ContentEncodingGzip}
+			Expect(gittrackobjectutils.SetChildData(&spec, []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"example"}}`))).To(Succeed())
+			gto.Spec = spec
+			Expect(DefaultGitTrackObject(gto)).To(Succeed())
+			Expect(gto.Spec.ContentEncoding).To(Equal(farosv1alpha1.ContentEncodingGzip))
+
+			data, err := gittrackobjectutils.ChildData(gto.Spec)
+			Expect(err).NotTo(HaveOccurred())
+			child, err := utils.YAMLToUnstructured(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(child.GetAnnotations()).To(HaveKeyWithValue("faros.pusher.com/update-strategy", "update"))
+		})
+
+		It("leaves invalid data unchanged", func() {
+			data := []byte("not a manifest")
+			gto.Spec.Data = data
+			Expect(DefaultGitTrackObject(gto)).To(Succeed())
+			Expect(gto.Spec.Data).To(Equal(data))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// gitTrackDefaulter sets the default values of GitTracks
+type gitTrackDefaulter struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &gitTrackDefaulter{}
+var _ admission.DecoderInjector = &gitTrackDefaulter{}
+
+// Handle returns a patch setting the default values of the GitTrack
+func (d *gitTrackDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	gt := &farosv1alpha1.GitTrack{}
+	err := d.decoder.Decode(req, gt)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	DefaultGitTrack(gt)
+	return patchResponse(req, gt)
+}
+
+// InjectDecoder injects the decoder into the gitTrackDefaulter
+func (d *gitTrackDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// gitTrackObjectDefaulter sets the default values of GitTrackObjects and
+// ClusterGitTrackObjects
+type gitTrackObjectDefaulter struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &gitTrackObjectDefaulter{}
+var _ admission.DecoderInjector = &gitTrackObjectDefaulter{}
+
+// Handle returns a patch setting the default values of the
+// (Cluster)GitTrackObject
+func (d *gitTrackObjectDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var gto farosv1alpha1.GitTrackObjectInterface = &farosv1alpha1.GitTrackObject{}
+	if req.Kind.Kind == "ClusterGitTrackObject" {
+		gto = &farosv1alpha1.ClusterGitTrackObject{}
+	}
+	err := d.decoder.Decode(req, gto)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	err = DefaultGitTrackObject(gto)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return patchResponse(req, gto)
+}
+
+// InjectDecoder injects the decoder into the gitTrackObjectDefaulter
+func (d *gitTrackObjectDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+// patchResponse returns a response patching the requested object to match
+// the defaulted object
+func patchResponse(req admission.Request, obj runtime.Object) admission.Response {
+	marshaled, err := json.Marshal(obj)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// GitTrackPath is the path the GitTrack defaulting webhook is served on
+	GitTrackPath = "/mutate-gittrack"
+
+	// GitTrackObjectPath is the path the (Cluster)GitTrackObject defaulting
+	// webhook is served on
+	GitTrackObjectPath = "/mutate-gittrackobject"
+)
+
+// Options configures the webhook server
+type Options struct {
+	// Port is the port the webhook server listens on
+	Port int
+
+	// CertDir is the directory containing the server's tls.crt and tls.key
+	CertDir string
+}
+
+// AddToManager adds a webhook server serving the defaulting webhooks for the
+// faros resources to the Manager
+func AddToManager(mgr manager.Manager, opts Options) error {
+	server := &webhook.Server{
+		Port:    opts.Port,
+		CertDir: opts.CertDir,
+	}
+	server.Register(GitTrackPath, &webhook.Admission{Handler: &gitTrackDefaulter{}})
+	server.Register(GitTrackObjectPath, &webhook.Admission{Handler: &gitTrackObjectDefaulter{}})
+	return mgr.Add(server)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	goruntime "runtime"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"github.com/pusher/faros/pkg/apis"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog"
+	"k8s.io/klog/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logr "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// CRDDirectory is the directory holding the faros CRD manifests
+var CRDDirectory = func() string {
+	_, file, _, _ := goruntime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "config", "crds")
+}()
+
+// Environment is a test control plane with the faros CRDs installed
+type Environment struct {
+	*envtest.Environment
+
+	// Config is the configuration for connecting to the control plane
+	Config *rest.Config
+}
+
+// Start registers the faros APIs with the client-go scheme, alongside the
+// built-in APIs already registered there, and starts a control plane with
+// the faros CRDs installed
+func Start() (*Environment, error) {
+	if err := apis.AddToScheme(scheme.Scheme); err != nil {
+		return nil, fmt.Errorf("unable to add faros APIs to scheme: %v", err)
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths: []string{CRDDirectory},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("unable to start test environment: %v", err)
+	}
+	return &Environment{
+		Environment: env,
+		Config:      cfg,
+	}, nil
+}
+
+// ManagerOptions returns the options for a manager restricted to the given
+// namespace, or all namespaces if empty, which doesn't serve metrics
+func ManagerOptions(namespace string) manager.Options {
+	return manager.Options{
+		Namespace:          namespace,
+		MetricsBindAddress: "0", // Disable serving metrics while testing
+	}
+}
+
+// NewManager creates a manager for the environment using ManagerOptions
+func (e *Environment) NewManager(namespace string) (manager.Manager, error) {
+	return manager.New(e.Config, ManagerOptions(namespace))
+}
+
+// StartManager starts the manager in the background, failing the current
+// test if it errors. Close the returned channel to stop the manager.
+func StartManager(mgr manager.Manager) chan struct{} {
+	stop := make(chan struct{})
+	go func() {
+		defer ginkgo.GinkgoRecover()
+		gomega.Expect(mgr.Start(stop)).NotTo(gomega.HaveOccurred())
+	}()
+	return stop
+}
+
+// SetupLogging logs through klog with a high verbosity for tests
+func SetupLogging() {
+	logr.SetLogger(klogr.New())
+	logFlags := &flag.FlagSet{}
+	klog.InitFlags(logFlags)
+	// Set log level high for tests
+	logFlags.Lookup("v").Value.Set("4")
+}
@@ -88,3 +88,24 @@ func deleteObj(c client.Client, timeout time.Duration, obj runtime.Object) error
 	g.Eventually(checkDeleted, timeout).Should(g.Succeed())
 	return nil
 }
+
+// RemoveFinalizers lists all resources and removes their finalizers, for when
+// the controller is no longer running to handle them
+func RemoveFinalizers(cfg *rest.Config, objLists ...runtime.Object) {
+	c, err := client.New(rest.CopyConfig(cfg), client.Options{})
+	g.Expect(err).ToNot(g.HaveOccurred())
+	for _, objList := range objLists {
+		g.Expect(c.List(context.TODO(), objList)).To(g.Succeed())
+		objs, err := apimeta.ExtractList(objList)
+		g.Expect(err).ToNot(g.HaveOccurred())
+		for _, obj := range objs {
+			metaAccessor, err := apimeta.Accessor(obj)
+			g.Expect(err).ToNot(g.HaveOccurred())
+			if len(metaAccessor.GetFinalizers()) == 0 {
+				continue
+			}
+			metaAccessor.SetFinalizers([]string{})
+			g.Expect(c.Update(context.TODO(), obj)).To(g.Succeed())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2018 Pusher Ltd.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+
+	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// Fixture is a GitTrackObject loaded from a YAML manifest, along with the
+// typed child it is expected to produce
+type Fixture struct {
+	GitTrackObject farosv1alpha1.GitTrackObjectInterface
+	Child          Object
+}
+
+// FixturesDir is the directory holding the YAML fixtures shared by the test
+// suites
+var FixturesDir = func() string {
+	_, file, _, _ := goruntime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "fixtures")
+}()
+
+// LoadFixtures loads every YAML manifest within dir, returning the fixtures
+// keyed by the name of the manifest without its extension
+func LoadFixtures(dir string) (map[string]*Fixture, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list fixtures: %v", err)
+	}
+
+	fixtures := make(map[string]*Fixture)
+	for _, file := range files {
+		fixture, err := LoadFixture(file)
+		if err != nil {
+			return nil, err
+		}
+		fixtures[strings.TrimSuffix(filepath.Base(file), ".yaml")] = fixture
+	}
+	return fixtures, nil
+}
+
+// LoadFixture loads a single YAML manifest as a Fixture.
+// Manifests with a namespace produce a GitTrackObject, all others produce a
+// ClusterGitTrackObject. In both cases it shares its name with the child.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture %s: %v", path, err)
+	}
+	u := &unstructured.Unstructured{}
+	if err = yaml.Unmarshal(data, &u.Object); err != nil {
+		return nil, fmt.Errorf("unable to parse fixture %s: %v", path, err)
+	}
+
+	child, err := scheme.Scheme.New(u.GroupVersionKind())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create child for fixture %s: %v", path, err)
+	}
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, child); err != nil {
+		return nil, fmt.Errorf("unable to convert fixture %s: %v", path, err)
+	}
+	obj, ok := child.(Object)
+	if !ok {
+		return nil, fmt.Errorf("fixture %s is not a Kubernetes object", path)
+	}
+
+	var gto farosv1alpha1.GitTrackObjectInterface
+	if u.GetNamespace() != "" {
+		gto = &farosv1alpha1.GitTrackObject{
+			TypeMeta: farosv1alpha1.GitTrackObjectTypeMeta,
+		}
+	} else {
+		gto = &farosv1alpha1.ClusterGitTrackObject{
+			TypeMeta: farosv1alpha1.ClusterGitTrackObjectTypeMeta,
+		}
+	}
+	gto.SetName(u.GetName())
+	gto.SetNamespace(u.GetNamespace())
+	if err = SetGitTrackObjectInterfaceSpec(gto, obj); err != nil {
+		return nil, fmt.Errorf("unable to set spec for fixture %s: %v", path, err)
+	}
+
+	return &Fixture{
+		GitTrackObject: gto,
+		Child:          obj,
+	}, nil
+}
+
+// MustLoadFixture loads the named fixture from FixturesDir, panicking if it
+// cannot be loaded
+func MustLoadFixture(name string) *Fixture {
+	fixture, err := LoadFixture(filepath.Join(FixturesDir, name+".yaml"))
+	if err != nil {
+		panic(err)
+	}
+	return fixture
+}
@@ -87,8 +87,16 @@ func (m *Matcher) Get(obj Object, intervals ...interface{}) gomega.GomegaAsyncAs
 }
 
 // Consistently continually gets the object from the API for comparison
-func (m *Matcher) Consistently(obj Object, intervals ...interface{}) gomega.GomegaAsyncAssertion {
-	return m.consistentlyObject(obj, intervals...)
+func (m *Matcher) Consistently(obj runtime.Object, intervals ...interface{}) gomega.GomegaAsyncAssertion {
+	// If the object is a list, return a list
+	if meta.IsListType(obj) {
+		return m.consistentlyList(obj, intervals...)
+	}
+	if o, ok := obj.(Object); ok {
+		return m.consistentlyObject(o, intervals...)
+	}
+	//Should not get here
+	panic("Unknown object.")
 }
 
 // consistentlyObject gets an individual object from the API server
@@ -107,6 +115,18 @@ func (m *Matcher) consistentlyObject(obj Object, intervals ...interface{}) gomeg
 	return gomega.Consistently(get, intervals...)
 }
 
+// consistentlyList gets a list type from the API server
+func (m *Matcher) consistentlyList(obj runtime.Object, intervals ...interface{}) gomega.GomegaAsyncAssertion {
+	list := func() runtime.Object {
+		err := m.Client.List(context.TODO(), obj)
+		if err != nil {
+			panic(err)
+		}
+		return obj
+	}
+	return gomega.Consistently(list, intervals...)
+}
+
 // Eventually continually gets the object from the API for comparison
 func (m *Matcher) Eventually(obj runtime.Object, intervals ...interface{}) gomega.GomegaAsyncAssertion {
 	// If the object is a list, return a list
@@ -250,6 +270,69 @@ func WithGitTrackObjectConditionMessage(matcher gtypes.GomegaMatcher) gtypes.Gom
 	}, matcher)
 }
 
+// WithGitTrackStatusObjectsDiscovered returns the GitTrack's discovered object count
+func WithGitTrackStatusObjectsDiscovered(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(gt *farosv1alpha1.GitTrack) int64 {
+		return gt.Status.ObjectsDiscovered
+	}, matcher)
+}
+
+// WithGitTrackStatusObjectsApplied returns the GitTrack's applied object count
+func WithGitTrackStatusObjectsApplied(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(gt *farosv1alpha1.GitTrack) int64 {
+		return gt.Status.ObjectsApplied
+	}, matcher)
+}
+
+// WithGitTrackStatusObjectsIgnored returns the GitTrack's ignored object count
+func WithGitTrackStatusObjectsIgnored(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(gt *farosv1alpha1.GitTrack) int64 {
+		return gt.Status.ObjectsIgnored
+	}, matcher)
+}
+
+// WithGitTrackStatusObjectsInSync returns the GitTrack's in sync object count
+func WithGitTrackStatusObjectsInSync(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(gt *farosv1alpha1.GitTrack) int64 {
+		return gt.Status.ObjectsInSync
+	}, matcher)
+}
+
+// WithGitTrackStatusConditions returns the GitTrack's status conditions
+func WithGitTrackStatusConditions(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(gt *farosv1alpha1.GitTrack) []farosv1alpha1.GitTrackCondition {
+		return gt.Status.Conditions
+	}, matcher)
+}
+
+// WithGitTrackConditionType returns the GitTrackCondition's type
+func WithGitTrackConditionType(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(c farosv1alpha1.GitTrackCondition) farosv1alpha1.GitTrackConditionType {
+		return c.Type
+	}, matcher)
+}
+
+// WithGitTrackConditionStatus returns the GitTrackCondition's status
+func WithGitTrackConditionStatus(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(c farosv1alpha1.GitTrackCondition) corev1.ConditionStatus {
+		return c.Status
+	}, matcher)
+}
+
+// WithGitTrackConditionReason returns the GitTrackCondition's reason
+func WithGitTrackConditionReason(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(c farosv1alpha1.GitTrackCondition) string {
+		return c.Reason
+	}, matcher)
+}
+
+// WithGitTrackConditionMessage returns the GitTrackCondition's message
+func WithGitTrackConditionMessage(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
+	return gomega.WithTransform(func(c farosv1alpha1.GitTrackCondition) string {
+		return c.Message
+	}, matcher)
+}
+
 // WithItems returns the items of the list
 func WithItems(matcher gtypes.GomegaMatcher) gtypes.GomegaMatcher {
 	return gomega.WithTransform(func(obj runtime.Object) []runtime.Object {
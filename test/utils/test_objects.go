@@ -21,10 +21,8 @@ import (
 
 	farosv1alpha1 "github.com/pusher/faros/pkg/apis/faros/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -55,127 +53,19 @@ func SetGitTrackObjectInterfaceSpec(gto farosv1alpha1.GitTrackObjectInterface, o
 	return nil
 }
 
+var (
+	deploymentFixture         = MustLoadFixture("deployment-example")
+	clusterRoleBindingFixture = MustLoadFixture("clusterrolebinding-example")
+)
+
 // ExampleGitTrackObject is an example GitTrackObject object for use within test suites
-var ExampleGitTrackObject = &farosv1alpha1.GitTrackObject{
-	TypeMeta: metav1.TypeMeta{
-		APIVersion: "faros.pusher.com/v1",
-		Kind:       "GitTrackObject",
-	},
-	ObjectMeta: metav1.ObjectMeta{
-		Name:      "example",
-		Namespace: "default",
-	},
-	Spec: farosv1alpha1.GitTrackObjectSpec{
-		Name: "deployment-example",
-		Kind: "Deployment",
-		Data: []byte(`apiVersion: apps/v1
-		kind: Deployment
-		metadata:
-		  name: example
-		  namespace: default
-		  labels:
-		    app: nginx
-		spec:
-		  selector:
-		    matchLabels:
-		      app: nginx
-		  template:
-		    metadata:
-		     labels:
-		       app: nginx
-		    spec:
-		      containers:
-		      - name: nginx
-		        image: nginx
-		`),
-	},
-}
+var ExampleGitTrackObject = deploymentFixture.GitTrackObject.(*farosv1alpha1.GitTrackObject)
 
 // ExampleClusterGitTrackObject is an example ClusterGitTrackObject object for use within test suites
-var ExampleClusterGitTrackObject = &farosv1alpha1.ClusterGitTrackObject{
-	TypeMeta: metav1.TypeMeta{
-		APIVersion: "faros.pusher.com/v1",
-		Kind:       "ClusterGitTrackObject",
-	},
-	ObjectMeta: metav1.ObjectMeta{
-		Name: "example",
-	},
-	Spec: farosv1alpha1.GitTrackObjectSpec{
-		Name: "clusterrolebinding-example",
-		Kind: "ClusterRoleBinding",
-		Data: []byte(`apiVersion: rbac.authorization.k8s.io/v1
-		kind: ClusterRoleBinding
-		metadata:
-		  name: example
-		  labels:
-		    app: nginx
-		roleRef:
-		  apiGroup: rbac.authorization.k8s.io
-		  kind: ClusterRole
-		  name: nginx-ingress-controller
-		subjects:
-		- kind: ServiceAccount
-		  name: nginx-ingress-controller
-		  namespace: example
-		`),
-	},
-}
-
-var appNginx = map[string]string{
-	"app": "nginx",
-}
+var ExampleClusterGitTrackObject = clusterRoleBindingFixture.GitTrackObject.(*farosv1alpha1.ClusterGitTrackObject)
 
 // ExampleDeployment is an example Deployment object for use within test suites
-var ExampleDeployment = &appsv1.Deployment{
-	TypeMeta: metav1.TypeMeta{
-		APIVersion: "apps/v1",
-		Kind:       "Deployment",
-	},
-	ObjectMeta: metav1.ObjectMeta{
-		Name:      "example",
-		Namespace: "default",
-		Labels:    appNginx,
-	},
-	Spec: appsv1.DeploymentSpec{
-		Selector: &metav1.LabelSelector{
-			MatchLabels: appNginx,
-		},
-		Template: corev1.PodTemplateSpec{
-			ObjectMeta: metav1.ObjectMeta{
-				Labels: appNginx,
-			},
-			Spec: corev1.PodSpec{
-				Containers: []corev1.Container{
-					{
-						Name:  "nginx",
-						Image: "nginx",
-					},
-				},
-			},
-		},
-	},
-}
+var ExampleDeployment = deploymentFixture.Child.(*appsv1.Deployment)
 
 // ExampleClusterRoleBinding is an example ClusterRoleBinding object for use within test suites
-var ExampleClusterRoleBinding = &rbacv1.ClusterRoleBinding{
-	TypeMeta: metav1.TypeMeta{
-		APIVersion: "rbac.authorization.k8s.io/v1",
-		Kind:       "ClusterRoleBinding",
-	},
-	ObjectMeta: metav1.ObjectMeta{
-		Name:   "example",
-		Labels: appNginx,
-	},
-	RoleRef: rbacv1.RoleRef{
-		APIGroup: "rbac.authorization.k8s.io",
-		Kind:     "ClusterRole",
-		Name:     "nginx-ingress-controller",
-	},
-	Subjects: []rbacv1.Subject{
-		{
-			Kind:      "ServiceAccount",
-			Name:      "nginx-ingress-controller",
-			Namespace: "example",
-		},
-	},
-}
+var ExampleClusterRoleBinding = clusterRoleBindingFixture.Child.(*rbacv1.ClusterRoleBinding)